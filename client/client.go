@@ -26,6 +26,7 @@ import (
 	"github.com/opentracing/opentracing-go"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/pkg/watchpb"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
@@ -63,6 +64,12 @@ type Client interface {
 	// If the given safePoint is less than the current one, it will not be updated.
 	// Returns the new safePoint after updating.
 	UpdateGCSafePoint(ctx context.Context, safePoint uint64) (uint64, error)
+	// WatchRegions watches the epoch and leader changes of the regions which
+	// overlap with [startKey, endKey). An empty endKey means no upper bound.
+	// The returned channel is closed once the watch is broken, for example
+	// ctx is canceled or PD leader is changed, then caller should reload the
+	// regions and watch again.
+	WatchRegions(ctx context.Context, startKey, endKey []byte) (<-chan []*watchpb.RegionEvent, error)
 	// Close closes the client.
 	Close()
 }
@@ -728,6 +735,46 @@ func (c *client) UpdateGCSafePoint(ctx context.Context, safePoint uint64) (uint6
 	return resp.GetNewSafePoint(), nil
 }
 
+// regionWatchChanSize is the buffer size of the channel returned by WatchRegions.
+const regionWatchChanSize = 16
+
+func (c *client) WatchRegions(ctx context.Context, startKey, endKey []byte) (<-chan []*watchpb.RegionEvent, error) {
+	c.connMu.RLock()
+	cc := c.connMu.clientConns[c.connMu.leader]
+	c.connMu.RUnlock()
+
+	stream, err := watchpb.NewWatchClient(cc).WatchRegions(ctx, &watchpb.WatchRegionsRequest{
+		Header:   c.requestHeader(),
+		StartKey: startKey,
+		EndKey:   endKey,
+	})
+	if err != nil {
+		c.ScheduleCheckLeader()
+		return nil, errors.WithStack(err)
+	}
+
+	ch := make(chan []*watchpb.RegionEvent, regionWatchChanSize)
+	go func() {
+		defer close(ch)
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Warnf("[pd] region watch stream is broken: %v", err)
+					c.ScheduleCheckLeader()
+				}
+				return
+			}
+			select {
+			case ch <- resp.GetEvents():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
 func (c *client) requestHeader() *pdpb.RequestHeader {
 	return &pdpb.RequestHeader{
 		ClusterId: c.clusterID,
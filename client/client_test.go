@@ -21,6 +21,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gogo/protobuf/proto"
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
@@ -313,3 +314,58 @@ func (s *testClientSuite) TestUpdateGCSafePoint(c *C) {
 	c.Assert(err, IsNil)
 	s.checkGCSafePoint(c, math.MaxUint64)
 }
+
+func (s *testClientSuite) TestWatchRegions(c *C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := s.client.WatchRegions(ctx, []byte("w1"), []byte("w3"))
+	c.Assert(err, IsNil)
+
+	newRegion := func(startKey, endKey string, version uint64) *metapb.Region {
+		regionID, _ := regionIDAllocator.Alloc()
+		return &metapb.Region{
+			Id:          regionID,
+			RegionEpoch: &metapb.RegionEpoch{ConfVer: 1, Version: version},
+			StartKey:    []byte(startKey),
+			EndKey:      []byte(endKey),
+			Peers:       []*metapb.Peer{peer},
+		}
+	}
+	mustRecv := func(expect *metapb.Region) {
+		select {
+		case events, ok := <-ch:
+			c.Assert(ok, IsTrue)
+			c.Assert(events, HasLen, 1)
+			c.Assert(events[0].GetRegion(), DeepEquals, expect)
+			c.Assert(events[0].GetLeader(), DeepEquals, peer)
+		case <-time.After(5 * time.Second):
+			c.Fatal("no region event received")
+		}
+	}
+	heartbeat := func(region *metapb.Region) {
+		err := s.regionHeartbeat.Send(&pdpb.RegionHeartbeatRequest{
+			Header: newHeader(s.srv),
+			Region: region,
+			Leader: peer,
+		})
+		c.Assert(err, IsNil)
+	}
+
+	// The region out of the range is not watched.
+	heartbeat(newRegion("x1", "x2", 1))
+	region := newRegion("w1", "w2", 1)
+	heartbeat(region)
+	mustRecv(region)
+
+	// Heartbeat without change is not notified.
+	heartbeat(region)
+	region = proto.Clone(region).(*metapb.Region)
+	region.RegionEpoch.Version = 2
+	heartbeat(region)
+	mustRecv(region)
+
+	cancel()
+	testutil.WaitUntil(c, func(c *C) bool {
+		_, ok := <-ch
+		return !ok
+	})
+}
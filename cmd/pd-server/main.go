@@ -23,7 +23,7 @@ import (
 
 	"github.com/grpc-ecosystem/go-grpc-prometheus"
 	"github.com/pingcap/pd/pkg/logutil"
-	"github.com/pingcap/pd/pkg/metricutil"
+	"github.com/pingcap/pd/pkg/tracing"
 	"github.com/pingcap/pd/server"
 	"github.com/pingcap/pd/server/api"
 	"github.com/pkg/errors"
@@ -68,7 +68,7 @@ func main() {
 	// TODO: Make it configurable if it has big impact on performance.
 	grpc_prometheus.EnableHandlingTimeHistogram()
 
-	metricutil.Push(&cfg.Metric)
+	closeTracer := tracing.Init(&cfg.Tracing)
 
 	err = server.PrepareJoinCluster(cfg)
 	if err != nil {
@@ -105,6 +105,7 @@ func main() {
 	log.Infof("Got signal [%d] to exit.", sig)
 
 	svr.Close()
+	closeTracer()
 	switch sig {
 	case syscall.SIGTERM:
 		os.Exit(0)
@@ -18,8 +18,10 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/http"
 	"strconv"
 
+	"github.com/pingcap/errcode"
 	"github.com/pkg/errors"
 )
 
@@ -31,6 +33,17 @@ func DeferClose(c io.Closer, err *error) {
 	}
 }
 
+// ErrorStatus returns the HTTP status code to respond with for the error. An
+// error with an errcode.ErrorCode in its chain, such as the not bootstrapped
+// and the not leader errors, uses the HTTP code of its code, so that the
+// handlers of all API versions agree on it. Other errors are internal errors.
+func ErrorStatus(err error) int {
+	if errCode := errcode.CodeChain(err); errCode != nil {
+		return errCode.Code().HTTPCode()
+	}
+	return http.StatusInternalServerError
+}
+
 // JSONError lets callers check for just one error type
 type JSONError struct {
 	Err error
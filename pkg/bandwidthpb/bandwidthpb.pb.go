@@ -0,0 +1,799 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: bandwidthpb.proto
+
+/*
+Package bandwidthpb is a generated protocol buffer package.
+
+It is generated from these files:
+
+	bandwidthpb.proto
+
+It has these top-level messages:
+
+	SnapshotBandwidth
+	GetSnapshotBandwidthRequest
+	GetSnapshotBandwidthResponse
+*/
+package bandwidthpb
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	proto "github.com/golang/protobuf/proto"
+
+	pdpb "github.com/pingcap/kvproto/pkg/pdpb"
+
+	_ "github.com/gogo/protobuf/gogoproto"
+
+	context "golang.org/x/net/context"
+
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// SnapshotBandwidth is the bandwidth a store may use to send snapshots until
+// its next heartbeat.
+type SnapshotBandwidth struct {
+	// tokens is the bytes of snapshots the store may send.
+	Tokens uint64 `protobuf:"varint,1,opt,name=tokens,proto3" json:"tokens,omitempty"`
+	// rate_limit is the bytes per second the store should send snapshots at.
+	RateLimit uint64 `protobuf:"varint,2,opt,name=rate_limit,json=rateLimit,proto3" json:"rate_limit,omitempty"`
+}
+
+func (m *SnapshotBandwidth) Reset()                    { *m = SnapshotBandwidth{} }
+func (m *SnapshotBandwidth) String() string            { return proto.CompactTextString(m) }
+func (*SnapshotBandwidth) ProtoMessage()               {}
+func (*SnapshotBandwidth) Descriptor() ([]byte, []int) { return fileDescriptorBandwidthpb, []int{0} }
+
+func (m *SnapshotBandwidth) GetTokens() uint64 {
+	if m != nil {
+		return m.Tokens
+	}
+	return 0
+}
+
+func (m *SnapshotBandwidth) GetRateLimit() uint64 {
+	if m != nil {
+		return m.RateLimit
+	}
+	return 0
+}
+
+type GetSnapshotBandwidthRequest struct {
+	Header  *pdpb.RequestHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+	StoreId uint64              `protobuf:"varint,2,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
+}
+
+func (m *GetSnapshotBandwidthRequest) Reset()         { *m = GetSnapshotBandwidthRequest{} }
+func (m *GetSnapshotBandwidthRequest) String() string { return proto.CompactTextString(m) }
+func (*GetSnapshotBandwidthRequest) ProtoMessage()    {}
+func (*GetSnapshotBandwidthRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptorBandwidthpb, []int{1}
+}
+
+func (m *GetSnapshotBandwidthRequest) GetHeader() *pdpb.RequestHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *GetSnapshotBandwidthRequest) GetStoreId() uint64 {
+	if m != nil {
+		return m.StoreId
+	}
+	return 0
+}
+
+// GetSnapshotBandwidthResponse returns the share of the store, which is not
+// set if the bandwidth is not limited.
+type GetSnapshotBandwidthResponse struct {
+	Header    *pdpb.ResponseHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+	Bandwidth *SnapshotBandwidth   `protobuf:"bytes,2,opt,name=bandwidth" json:"bandwidth,omitempty"`
+}
+
+func (m *GetSnapshotBandwidthResponse) Reset()         { *m = GetSnapshotBandwidthResponse{} }
+func (m *GetSnapshotBandwidthResponse) String() string { return proto.CompactTextString(m) }
+func (*GetSnapshotBandwidthResponse) ProtoMessage()    {}
+func (*GetSnapshotBandwidthResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptorBandwidthpb, []int{2}
+}
+
+func (m *GetSnapshotBandwidthResponse) GetHeader() *pdpb.ResponseHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *GetSnapshotBandwidthResponse) GetBandwidth() *SnapshotBandwidth {
+	if m != nil {
+		return m.Bandwidth
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*SnapshotBandwidth)(nil), "bandwidthpb.SnapshotBandwidth")
+	proto.RegisterType((*GetSnapshotBandwidthRequest)(nil), "bandwidthpb.GetSnapshotBandwidthRequest")
+	proto.RegisterType((*GetSnapshotBandwidthResponse)(nil), "bandwidthpb.GetSnapshotBandwidthResponse")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for Bandwidth service
+
+type BandwidthClient interface {
+	GetSnapshotBandwidth(ctx context.Context, in *GetSnapshotBandwidthRequest, opts ...grpc.CallOption) (*GetSnapshotBandwidthResponse, error)
+}
+
+type bandwidthClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewBandwidthClient(cc *grpc.ClientConn) BandwidthClient {
+	return &bandwidthClient{cc}
+}
+
+func (c *bandwidthClient) GetSnapshotBandwidth(ctx context.Context, in *GetSnapshotBandwidthRequest, opts ...grpc.CallOption) (*GetSnapshotBandwidthResponse, error) {
+	out := new(GetSnapshotBandwidthResponse)
+	err := grpc.Invoke(ctx, "/bandwidthpb.Bandwidth/GetSnapshotBandwidth", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Bandwidth service
+
+type BandwidthServer interface {
+	GetSnapshotBandwidth(context.Context, *GetSnapshotBandwidthRequest) (*GetSnapshotBandwidthResponse, error)
+}
+
+func RegisterBandwidthServer(s *grpc.Server, srv BandwidthServer) {
+	s.RegisterService(&_Bandwidth_serviceDesc, srv)
+}
+
+func _Bandwidth_GetSnapshotBandwidth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSnapshotBandwidthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BandwidthServer).GetSnapshotBandwidth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bandwidthpb.Bandwidth/GetSnapshotBandwidth",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BandwidthServer).GetSnapshotBandwidth(ctx, req.(*GetSnapshotBandwidthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Bandwidth_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "bandwidthpb.Bandwidth",
+	HandlerType: (*BandwidthServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetSnapshotBandwidth",
+			Handler:    _Bandwidth_GetSnapshotBandwidth_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "bandwidthpb.proto",
+}
+
+func (m *SnapshotBandwidth) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SnapshotBandwidth) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Tokens != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintBandwidthpb(dAtA, i, uint64(m.Tokens))
+	}
+	if m.RateLimit != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintBandwidthpb(dAtA, i, uint64(m.RateLimit))
+	}
+	return i, nil
+}
+
+func (m *GetSnapshotBandwidthRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetSnapshotBandwidthRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintBandwidthpb(dAtA, i, uint64(m.Header.Size()))
+		n1, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	if m.StoreId != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintBandwidthpb(dAtA, i, uint64(m.StoreId))
+	}
+	return i, nil
+}
+
+func (m *GetSnapshotBandwidthResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetSnapshotBandwidthResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintBandwidthpb(dAtA, i, uint64(m.Header.Size()))
+		n2, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n2
+	}
+	if m.Bandwidth != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintBandwidthpb(dAtA, i, uint64(m.Bandwidth.Size()))
+		n3, err := m.Bandwidth.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n3
+	}
+	return i, nil
+}
+
+func encodeVarintBandwidthpb(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *SnapshotBandwidth) Size() (n int) {
+	var l int
+	_ = l
+	if m.Tokens != 0 {
+		n += 1 + sovBandwidthpb(uint64(m.Tokens))
+	}
+	if m.RateLimit != 0 {
+		n += 1 + sovBandwidthpb(uint64(m.RateLimit))
+	}
+	return n
+}
+
+func (m *GetSnapshotBandwidthRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovBandwidthpb(uint64(l))
+	}
+	if m.StoreId != 0 {
+		n += 1 + sovBandwidthpb(uint64(m.StoreId))
+	}
+	return n
+}
+
+func (m *GetSnapshotBandwidthResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovBandwidthpb(uint64(l))
+	}
+	if m.Bandwidth != nil {
+		l = m.Bandwidth.Size()
+		n += 1 + l + sovBandwidthpb(uint64(l))
+	}
+	return n
+}
+
+func sovBandwidthpb(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozBandwidthpb(x uint64) (n int) {
+	return sovBandwidthpb(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *SnapshotBandwidth) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowBandwidthpb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SnapshotBandwidth: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SnapshotBandwidth: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tokens", wireType)
+			}
+			m.Tokens = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBandwidthpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Tokens |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RateLimit", wireType)
+			}
+			m.RateLimit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBandwidthpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RateLimit |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipBandwidthpb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthBandwidthpb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetSnapshotBandwidthRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowBandwidthpb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetSnapshotBandwidthRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetSnapshotBandwidthRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBandwidthpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthBandwidthpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.RequestHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StoreId", wireType)
+			}
+			m.StoreId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBandwidthpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StoreId |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipBandwidthpb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthBandwidthpb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetSnapshotBandwidthResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowBandwidthpb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetSnapshotBandwidthResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetSnapshotBandwidthResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBandwidthpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthBandwidthpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.ResponseHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bandwidth", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBandwidthpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthBandwidthpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Bandwidth == nil {
+				m.Bandwidth = &SnapshotBandwidth{}
+			}
+			if err := m.Bandwidth.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipBandwidthpb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthBandwidthpb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipBandwidthpb(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowBandwidthpb
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowBandwidthpb
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+			return iNdEx, nil
+		case 1:
+			iNdEx += 8
+			return iNdEx, nil
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowBandwidthpb
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			iNdEx += length
+			if length < 0 {
+				return 0, ErrInvalidLengthBandwidthpb
+			}
+			return iNdEx, nil
+		case 3:
+			for {
+				var innerWire uint64
+				var start int = iNdEx
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return 0, ErrIntOverflowBandwidthpb
+					}
+					if iNdEx >= l {
+						return 0, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					innerWire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				innerWireType := int(innerWire & 0x7)
+				if innerWireType == 4 {
+					break
+				}
+				next, err := skipBandwidthpb(dAtA[start:])
+				if err != nil {
+					return 0, err
+				}
+				iNdEx = start + next
+			}
+			return iNdEx, nil
+		case 4:
+			return iNdEx, nil
+		case 5:
+			iNdEx += 4
+			return iNdEx, nil
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+	}
+	panic("unreachable")
+}
+
+var (
+	ErrInvalidLengthBandwidthpb = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowBandwidthpb   = fmt.Errorf("proto: integer overflow")
+)
+
+func init() { proto.RegisterFile("bandwidthpb.proto", fileDescriptorBandwidthpb) }
+
+var fileDescriptorBandwidthpb = []byte{
+	// 274 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x4c, 0x4a, 0xcc, 0x4b,
+	0x29, 0xcf, 0x4c, 0x29, 0xc9, 0x28, 0x48, 0xd2, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x46,
+	0x12, 0x92, 0xe2, 0x2a, 0x48, 0x81, 0x49, 0x48, 0x89, 0xa4, 0xe7, 0xa7, 0xe7, 0x83, 0x99, 0xfa,
+	0x20, 0x16, 0x44, 0x54, 0xc9, 0x8b, 0x4b, 0x30, 0x38, 0x2f, 0xb1, 0xa0, 0x38, 0x23, 0xbf, 0xc4,
+	0x09, 0xa6, 0x51, 0x48, 0x8c, 0x8b, 0xad, 0x24, 0x3f, 0x3b, 0x35, 0xaf, 0x58, 0x82, 0x51, 0x81,
+	0x51, 0x83, 0x25, 0x08, 0xca, 0x13, 0x92, 0xe5, 0xe2, 0x2a, 0x4a, 0x2c, 0x49, 0x8d, 0xcf, 0xc9,
+	0xcc, 0xcd, 0x2c, 0x91, 0x60, 0x02, 0xcb, 0x71, 0x82, 0x44, 0x7c, 0x40, 0x02, 0x4a, 0xa9, 0x5c,
+	0xd2, 0xee, 0xa9, 0x25, 0x18, 0xc6, 0x05, 0xa5, 0x16, 0x96, 0xa6, 0x16, 0x97, 0x08, 0x69, 0x73,
+	0xb1, 0x65, 0xa4, 0x26, 0xa6, 0xa4, 0x16, 0x81, 0x4d, 0xe5, 0x36, 0x12, 0xd6, 0x03, 0xbb, 0x0e,
+	0x2a, 0xed, 0x01, 0x96, 0x0a, 0x82, 0x2a, 0x11, 0x92, 0xe4, 0xe2, 0x28, 0x2e, 0xc9, 0x2f, 0x4a,
+	0x8d, 0xcf, 0x4c, 0x81, 0x5a, 0xc4, 0x0e, 0xe6, 0x7b, 0xa6, 0x28, 0x75, 0x31, 0x72, 0xc9, 0x60,
+	0xb7, 0xa7, 0xb8, 0x20, 0x3f, 0xaf, 0x38, 0x55, 0x48, 0x07, 0xcd, 0x22, 0x11, 0x98, 0x45, 0x10,
+	0x79, 0x34, 0x9b, 0x6c, 0xb8, 0x38, 0xe1, 0x41, 0x06, 0xb6, 0x8a, 0xdb, 0x48, 0x4e, 0x0f, 0x39,
+	0x5c, 0x31, 0x2d, 0x42, 0x68, 0x30, 0xaa, 0xe0, 0xe2, 0x44, 0x84, 0x5b, 0x36, 0x97, 0x08, 0x36,
+	0x87, 0x09, 0x69, 0xa0, 0x98, 0x87, 0x27, 0x8c, 0xa4, 0x34, 0x89, 0x50, 0x09, 0xf1, 0x85, 0x93,
+	0xc0, 0x89, 0x47, 0x72, 0x8c, 0x17, 0x1e, 0xc9, 0x31, 0x3e, 0x78, 0x24, 0xc7, 0x38, 0xe3, 0xb1,
+	0x1c, 0x43, 0x12, 0x1b, 0x38, 0x4a, 0x8d, 0x01, 0x03, 0x00, 0x13, 0x48, 0x0f, 0xd5, 0x16, 0x02,
+	0x00, 0x00,
+}
@@ -0,0 +1,887 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: confchangepb.proto
+
+/*
+Package confchangepb is a generated protocol buffer package.
+
+It is generated from these files:
+
+	confchangepb.proto
+
+It has these top-level messages:
+
+	ConfChangeCommand
+	GetConfChangeCommandsRequest
+	GetConfChangeCommandsResponse
+*/
+package confchangepb
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	proto "github.com/golang/protobuf/proto"
+
+	metapb "github.com/pingcap/kvproto/pkg/metapb"
+
+	pdpb "github.com/pingcap/kvproto/pkg/pdpb"
+
+	_ "github.com/gogo/protobuf/gogoproto"
+
+	context "golang.org/x/net/context"
+
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// ConfChangeCommand asks the leader of a region to apply the changes in a
+// single conf change, which enters the joint consensus and then leaves it. A
+// voter is demoted by AddLearnerNode, and a learner is promoted by AddNode. It
+// is stale if the region epoch does not match.
+type ConfChangeCommand struct {
+	RegionId    uint64              `protobuf:"varint,1,opt,name=region_id,json=regionId,proto3" json:"region_id,omitempty"`
+	RegionEpoch *metapb.RegionEpoch `protobuf:"bytes,2,opt,name=region_epoch,json=regionEpoch" json:"region_epoch,omitempty"`
+	Changes     []*pdpb.ChangePeer  `protobuf:"bytes,3,rep,name=changes" json:"changes,omitempty"`
+}
+
+func (m *ConfChangeCommand) Reset()                    { *m = ConfChangeCommand{} }
+func (m *ConfChangeCommand) String() string            { return proto.CompactTextString(m) }
+func (*ConfChangeCommand) ProtoMessage()               {}
+func (*ConfChangeCommand) Descriptor() ([]byte, []int) { return fileDescriptorConfchangepb, []int{0} }
+
+func (m *ConfChangeCommand) GetRegionId() uint64 {
+	if m != nil {
+		return m.RegionId
+	}
+	return 0
+}
+
+func (m *ConfChangeCommand) GetRegionEpoch() *metapb.RegionEpoch {
+	if m != nil {
+		return m.RegionEpoch
+	}
+	return nil
+}
+
+func (m *ConfChangeCommand) GetChanges() []*pdpb.ChangePeer {
+	if m != nil {
+		return m.Changes
+	}
+	return nil
+}
+
+type GetConfChangeCommandsRequest struct {
+	Header  *pdpb.RequestHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+	StoreId uint64              `protobuf:"varint,2,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
+}
+
+func (m *GetConfChangeCommandsRequest) Reset()         { *m = GetConfChangeCommandsRequest{} }
+func (m *GetConfChangeCommandsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetConfChangeCommandsRequest) ProtoMessage()    {}
+func (*GetConfChangeCommandsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptorConfchangepb, []int{1}
+}
+
+func (m *GetConfChangeCommandsRequest) GetHeader() *pdpb.RequestHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *GetConfChangeCommandsRequest) GetStoreId() uint64 {
+	if m != nil {
+		return m.StoreId
+	}
+	return 0
+}
+
+// GetConfChangeCommandsResponse returns the conf changes of the regions led by
+// the store, sorted by region ID.
+type GetConfChangeCommandsResponse struct {
+	Header   *pdpb.ResponseHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+	Commands []*ConfChangeCommand `protobuf:"bytes,2,rep,name=commands" json:"commands,omitempty"`
+}
+
+func (m *GetConfChangeCommandsResponse) Reset()         { *m = GetConfChangeCommandsResponse{} }
+func (m *GetConfChangeCommandsResponse) String() string { return proto.CompactTextString(m) }
+func (*GetConfChangeCommandsResponse) ProtoMessage()    {}
+func (*GetConfChangeCommandsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptorConfchangepb, []int{2}
+}
+
+func (m *GetConfChangeCommandsResponse) GetHeader() *pdpb.ResponseHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *GetConfChangeCommandsResponse) GetCommands() []*ConfChangeCommand {
+	if m != nil {
+		return m.Commands
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*ConfChangeCommand)(nil), "confchangepb.ConfChangeCommand")
+	proto.RegisterType((*GetConfChangeCommandsRequest)(nil), "confchangepb.GetConfChangeCommandsRequest")
+	proto.RegisterType((*GetConfChangeCommandsResponse)(nil), "confchangepb.GetConfChangeCommandsResponse")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for ConfChange service
+
+type ConfChangeClient interface {
+	// GetConfChangeCommands gets the conf changes of the regions led by the
+	// store.
+	GetConfChangeCommands(ctx context.Context, in *GetConfChangeCommandsRequest, opts ...grpc.CallOption) (*GetConfChangeCommandsResponse, error)
+}
+
+type confChangeClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewConfChangeClient(cc *grpc.ClientConn) ConfChangeClient {
+	return &confChangeClient{cc}
+}
+
+func (c *confChangeClient) GetConfChangeCommands(ctx context.Context, in *GetConfChangeCommandsRequest, opts ...grpc.CallOption) (*GetConfChangeCommandsResponse, error) {
+	out := new(GetConfChangeCommandsResponse)
+	err := grpc.Invoke(ctx, "/confchangepb.ConfChange/GetConfChangeCommands", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for ConfChange service
+
+type ConfChangeServer interface {
+	// GetConfChangeCommands gets the conf changes of the regions led by the
+	// store.
+	GetConfChangeCommands(context.Context, *GetConfChangeCommandsRequest) (*GetConfChangeCommandsResponse, error)
+}
+
+func RegisterConfChangeServer(s *grpc.Server, srv ConfChangeServer) {
+	s.RegisterService(&_ConfChange_serviceDesc, srv)
+}
+
+func _ConfChange_GetConfChangeCommands_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConfChangeCommandsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfChangeServer).GetConfChangeCommands(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/confchangepb.ConfChange/GetConfChangeCommands",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfChangeServer).GetConfChangeCommands(ctx, req.(*GetConfChangeCommandsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ConfChange_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "confchangepb.ConfChange",
+	HandlerType: (*ConfChangeServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetConfChangeCommands",
+			Handler:    _ConfChange_GetConfChangeCommands_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "confchangepb.proto",
+}
+
+func (m *ConfChangeCommand) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ConfChangeCommand) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.RegionId != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintConfchangepb(dAtA, i, uint64(m.RegionId))
+	}
+	if m.RegionEpoch != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintConfchangepb(dAtA, i, uint64(m.RegionEpoch.Size()))
+		n1, err := m.RegionEpoch.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	if len(m.Changes) > 0 {
+		for _, msg := range m.Changes {
+			dAtA[i] = 0x1a
+			i++
+			i = encodeVarintConfchangepb(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *GetConfChangeCommandsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetConfChangeCommandsRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintConfchangepb(dAtA, i, uint64(m.Header.Size()))
+		n2, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n2
+	}
+	if m.StoreId != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintConfchangepb(dAtA, i, uint64(m.StoreId))
+	}
+	return i, nil
+}
+
+func (m *GetConfChangeCommandsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetConfChangeCommandsResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintConfchangepb(dAtA, i, uint64(m.Header.Size()))
+		n3, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n3
+	}
+	if len(m.Commands) > 0 {
+		for _, msg := range m.Commands {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintConfchangepb(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func encodeVarintConfchangepb(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *ConfChangeCommand) Size() (n int) {
+	var l int
+	_ = l
+	if m.RegionId != 0 {
+		n += 1 + sovConfchangepb(uint64(m.RegionId))
+	}
+	if m.RegionEpoch != nil {
+		l = m.RegionEpoch.Size()
+		n += 1 + l + sovConfchangepb(uint64(l))
+	}
+	if len(m.Changes) > 0 {
+		for _, e := range m.Changes {
+			l = e.Size()
+			n += 1 + l + sovConfchangepb(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *GetConfChangeCommandsRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovConfchangepb(uint64(l))
+	}
+	if m.StoreId != 0 {
+		n += 1 + sovConfchangepb(uint64(m.StoreId))
+	}
+	return n
+}
+
+func (m *GetConfChangeCommandsResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovConfchangepb(uint64(l))
+	}
+	if len(m.Commands) > 0 {
+		for _, e := range m.Commands {
+			l = e.Size()
+			n += 1 + l + sovConfchangepb(uint64(l))
+		}
+	}
+	return n
+}
+
+func sovConfchangepb(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozConfchangepb(x uint64) (n int) {
+	return sovConfchangepb(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *ConfChangeCommand) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfchangepb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ConfChangeCommand: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ConfChangeCommand: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RegionId", wireType)
+			}
+			m.RegionId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfchangepb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RegionId |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RegionEpoch", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfchangepb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfchangepb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.RegionEpoch == nil {
+				m.RegionEpoch = &metapb.RegionEpoch{}
+			}
+			if err := m.RegionEpoch.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Changes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfchangepb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfchangepb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Changes = append(m.Changes, &pdpb.ChangePeer{})
+			if err := m.Changes[len(m.Changes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfchangepb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfchangepb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetConfChangeCommandsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfchangepb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetConfChangeCommandsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetConfChangeCommandsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfchangepb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfchangepb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.RequestHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StoreId", wireType)
+			}
+			m.StoreId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfchangepb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StoreId |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfchangepb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfchangepb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetConfChangeCommandsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfchangepb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetConfChangeCommandsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetConfChangeCommandsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfchangepb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfchangepb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.ResponseHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Commands", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfchangepb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfchangepb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Commands = append(m.Commands, &ConfChangeCommand{})
+			if err := m.Commands[len(m.Commands)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfchangepb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfchangepb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipConfchangepb(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowConfchangepb
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowConfchangepb
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+			return iNdEx, nil
+		case 1:
+			iNdEx += 8
+			return iNdEx, nil
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowConfchangepb
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			iNdEx += length
+			if length < 0 {
+				return 0, ErrInvalidLengthConfchangepb
+			}
+			return iNdEx, nil
+		case 3:
+			for {
+				var innerWire uint64
+				var start int = iNdEx
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return 0, ErrIntOverflowConfchangepb
+					}
+					if iNdEx >= l {
+						return 0, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					innerWire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				innerWireType := int(innerWire & 0x7)
+				if innerWireType == 4 {
+					break
+				}
+				next, err := skipConfchangepb(dAtA[start:])
+				if err != nil {
+					return 0, err
+				}
+				iNdEx = start + next
+			}
+			return iNdEx, nil
+		case 4:
+			return iNdEx, nil
+		case 5:
+			iNdEx += 4
+			return iNdEx, nil
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+	}
+	panic("unreachable")
+}
+
+var (
+	ErrInvalidLengthConfchangepb = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowConfchangepb   = fmt.Errorf("proto: integer overflow")
+)
+
+func init() { proto.RegisterFile("confchangepb.proto", fileDescriptorConfchangepb) }
+
+var fileDescriptorConfchangepb = []byte{
+	// 326 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x92, 0xc1, 0x4e, 0x02, 0x31,
+	0x10, 0x40, 0x2d, 0x18, 0xc0, 0x81, 0x03, 0x56, 0x4c, 0x56, 0xd4, 0x95, 0x70, 0x22, 0x60, 0xd6,
+	0x04, 0x13, 0x2f, 0xde, 0x24, 0x46, 0xb9, 0x99, 0xfe, 0x80, 0x59, 0xb6, 0xc3, 0xc2, 0x81, 0xb6,
+	0xb6, 0xeb, 0xd5, 0x0f, 0xf0, 0xec, 0xc1, 0x4f, 0xf2, 0xe8, 0x27, 0x18, 0xfc, 0x11, 0x43, 0x5b,
+	0x40, 0x40, 0x8d, 0xb7, 0x99, 0x79, 0xb3, 0xf3, 0x66, 0x76, 0x17, 0x68, 0x22, 0xc5, 0x30, 0x19,
+	0xc5, 0x22, 0x45, 0x35, 0x88, 0x94, 0x96, 0x99, 0xa4, 0x95, 0xef, 0xb5, 0x7a, 0x65, 0x82, 0x59,
+	0x3c, 0x67, 0x75, 0x50, 0x7c, 0x11, 0xd7, 0x52, 0x99, 0x4a, 0x1b, 0x9e, 0xcd, 0x22, 0x57, 0x6d,
+	0xbe, 0x10, 0xd8, 0xed, 0x49, 0x31, 0xec, 0xd9, 0x01, 0x3d, 0x39, 0x99, 0xc4, 0x82, 0xd3, 0x43,
+	0xd8, 0xd1, 0x98, 0x8e, 0xa5, 0xb8, 0x1f, 0xf3, 0x80, 0x34, 0x48, 0x6b, 0x9b, 0x95, 0x5c, 0xa1,
+	0xcf, 0xe9, 0x05, 0x54, 0x3c, 0x44, 0x25, 0x93, 0x51, 0x90, 0x6b, 0x90, 0x56, 0xb9, 0xbb, 0x17,
+	0x79, 0x33, 0xb3, 0xec, 0x7a, 0x86, 0x58, 0x59, 0x2f, 0x13, 0xda, 0x86, 0xa2, 0x5b, 0xd3, 0x04,
+	0xf9, 0x46, 0xbe, 0x55, 0xee, 0x56, 0x23, 0xbb, 0x9e, 0x53, 0xdf, 0x21, 0x6a, 0x36, 0x6f, 0x68,
+	0x0e, 0xe1, 0xe8, 0x06, 0xb3, 0x8d, 0xc5, 0x0c, 0xc3, 0x87, 0x47, 0x34, 0x19, 0xed, 0x40, 0x61,
+	0x84, 0x31, 0x47, 0x1d, 0x10, 0x6f, 0xb7, 0xa3, 0x3c, 0xbe, 0xb5, 0x88, 0xf9, 0x16, 0x7a, 0x00,
+	0x25, 0x93, 0x49, 0x8d, 0xb3, 0x63, 0x72, 0xf6, 0x98, 0xa2, 0xcd, 0xfb, 0xbc, 0xf9, 0x4c, 0xe0,
+	0xf8, 0x17, 0x91, 0x51, 0x52, 0x18, 0xa4, 0xa7, 0x6b, 0xa6, 0xda, 0xdc, 0xe4, 0xf8, 0x9a, 0xea,
+	0x12, 0x4a, 0x89, 0x9f, 0x10, 0xe4, 0xec, 0x91, 0x27, 0xd1, 0xca, 0x37, 0xdb, 0x30, 0xb1, 0xc5,
+	0x03, 0xdd, 0x27, 0x80, 0x25, 0xa6, 0x0a, 0xf6, 0x7f, 0xdc, 0x8c, 0xb6, 0x57, 0x27, 0xfe, 0xf5,
+	0x9e, 0xea, 0x9d, 0x7f, 0xf5, 0xba, 0x53, 0xae, 0xaa, 0x6f, 0xd3, 0x90, 0xbc, 0x4f, 0x43, 0xf2,
+	0x31, 0x0d, 0xc9, 0xeb, 0x67, 0xb8, 0x35, 0x28, 0xd8, 0x9f, 0xe4, 0xfc, 0x6b, 0x00, 0xda, 0xc6,
+	0x81, 0x0d, 0x78, 0x02, 0x00, 0x00,
+}
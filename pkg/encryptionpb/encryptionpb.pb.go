@@ -0,0 +1,1084 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: encryptionpb.proto
+
+/*
+Package encryptionpb is a generated protocol buffer package.
+
+It is generated from these files:
+
+	encryptionpb.proto
+
+It has these top-level messages:
+
+	DataKey
+	GetCurrentKeyRequest
+	GetKeyRequest
+	GetKeyResponse
+*/
+package encryptionpb
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	proto "github.com/golang/protobuf/proto"
+
+	pdpb "github.com/pingcap/kvproto/pkg/pdpb"
+
+	_ "github.com/gogo/protobuf/gogoproto"
+
+	context "golang.org/x/net/context"
+
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// EncryptionMethod is the cipher to encrypt the data with a data key.
+type EncryptionMethod int32
+
+const (
+	EncryptionMethod_UNKNOWN    EncryptionMethod = 0
+	EncryptionMethod_AES256_CTR EncryptionMethod = 1
+)
+
+var EncryptionMethod_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "AES256_CTR",
+}
+var EncryptionMethod_value = map[string]int32{
+	"UNKNOWN":    0,
+	"AES256_CTR": 1,
+}
+
+func (x EncryptionMethod) String() string {
+	return proto.EnumName(EncryptionMethod_name, int32(x))
+}
+func (EncryptionMethod) EnumDescriptor() ([]byte, []int) { return fileDescriptorEncryptionpb, []int{0} }
+
+// DataKey is a key to encrypt the data. The keys are never deleted, so that
+// the data encrypted by the rotated keys can still be decrypted.
+type DataKey struct {
+	KeyId  uint64           `protobuf:"varint,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Key    []byte           `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Method EncryptionMethod `protobuf:"varint,3,opt,name=method,proto3,enum=encryptionpb.EncryptionMethod" json:"method,omitempty"`
+	// creation_time is the unix timestamp in seconds the key is created at.
+	CreationTime int64 `protobuf:"varint,4,opt,name=creation_time,json=creationTime,proto3" json:"creation_time,omitempty"`
+}
+
+func (m *DataKey) Reset()                    { *m = DataKey{} }
+func (m *DataKey) String() string            { return proto.CompactTextString(m) }
+func (*DataKey) ProtoMessage()               {}
+func (*DataKey) Descriptor() ([]byte, []int) { return fileDescriptorEncryptionpb, []int{0} }
+
+func (m *DataKey) GetKeyId() uint64 {
+	if m != nil {
+		return m.KeyId
+	}
+	return 0
+}
+
+func (m *DataKey) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *DataKey) GetMethod() EncryptionMethod {
+	if m != nil {
+		return m.Method
+	}
+	return EncryptionMethod_UNKNOWN
+}
+
+func (m *DataKey) GetCreationTime() int64 {
+	if m != nil {
+		return m.CreationTime
+	}
+	return 0
+}
+
+type GetCurrentKeyRequest struct {
+	Header *pdpb.RequestHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+}
+
+func (m *GetCurrentKeyRequest) Reset()         { *m = GetCurrentKeyRequest{} }
+func (m *GetCurrentKeyRequest) String() string { return proto.CompactTextString(m) }
+func (*GetCurrentKeyRequest) ProtoMessage()    {}
+func (*GetCurrentKeyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptorEncryptionpb, []int{1}
+}
+
+func (m *GetCurrentKeyRequest) GetHeader() *pdpb.RequestHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+type GetKeyRequest struct {
+	Header *pdpb.RequestHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+	KeyId  uint64              `protobuf:"varint,2,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+}
+
+func (m *GetKeyRequest) Reset()                    { *m = GetKeyRequest{} }
+func (m *GetKeyRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetKeyRequest) ProtoMessage()               {}
+func (*GetKeyRequest) Descriptor() ([]byte, []int) { return fileDescriptorEncryptionpb, []int{2} }
+
+func (m *GetKeyRequest) GetHeader() *pdpb.RequestHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *GetKeyRequest) GetKeyId() uint64 {
+	if m != nil {
+		return m.KeyId
+	}
+	return 0
+}
+
+// GetKeyResponse is the response of GetCurrentKey and GetKey.
+type GetKeyResponse struct {
+	Header *pdpb.ResponseHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+	Key    *DataKey             `protobuf:"bytes,2,opt,name=key" json:"key,omitempty"`
+}
+
+func (m *GetKeyResponse) Reset()                    { *m = GetKeyResponse{} }
+func (m *GetKeyResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetKeyResponse) ProtoMessage()               {}
+func (*GetKeyResponse) Descriptor() ([]byte, []int) { return fileDescriptorEncryptionpb, []int{3} }
+
+func (m *GetKeyResponse) GetHeader() *pdpb.ResponseHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *GetKeyResponse) GetKey() *DataKey {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*DataKey)(nil), "encryptionpb.DataKey")
+	proto.RegisterType((*GetCurrentKeyRequest)(nil), "encryptionpb.GetCurrentKeyRequest")
+	proto.RegisterType((*GetKeyRequest)(nil), "encryptionpb.GetKeyRequest")
+	proto.RegisterType((*GetKeyResponse)(nil), "encryptionpb.GetKeyResponse")
+	proto.RegisterEnum("encryptionpb.EncryptionMethod", EncryptionMethod_name, EncryptionMethod_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for KeyManager service
+
+type KeyManagerClient interface {
+	// GetCurrentKey gets the key to encrypt the new data with.
+	GetCurrentKey(ctx context.Context, in *GetCurrentKeyRequest, opts ...grpc.CallOption) (*GetKeyResponse, error)
+	// GetKey gets a key by ID to decrypt the data encrypted with it.
+	GetKey(ctx context.Context, in *GetKeyRequest, opts ...grpc.CallOption) (*GetKeyResponse, error)
+}
+
+type keyManagerClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewKeyManagerClient(cc *grpc.ClientConn) KeyManagerClient {
+	return &keyManagerClient{cc}
+}
+
+func (c *keyManagerClient) GetCurrentKey(ctx context.Context, in *GetCurrentKeyRequest, opts ...grpc.CallOption) (*GetKeyResponse, error) {
+	out := new(GetKeyResponse)
+	err := grpc.Invoke(ctx, "/encryptionpb.KeyManager/GetCurrentKey", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyManagerClient) GetKey(ctx context.Context, in *GetKeyRequest, opts ...grpc.CallOption) (*GetKeyResponse, error) {
+	out := new(GetKeyResponse)
+	err := grpc.Invoke(ctx, "/encryptionpb.KeyManager/GetKey", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for KeyManager service
+
+type KeyManagerServer interface {
+	// GetCurrentKey gets the key to encrypt the new data with.
+	GetCurrentKey(context.Context, *GetCurrentKeyRequest) (*GetKeyResponse, error)
+	// GetKey gets a key by ID to decrypt the data encrypted with it.
+	GetKey(context.Context, *GetKeyRequest) (*GetKeyResponse, error)
+}
+
+func RegisterKeyManagerServer(s *grpc.Server, srv KeyManagerServer) {
+	s.RegisterService(&_KeyManager_serviceDesc, srv)
+}
+
+func _KeyManager_GetCurrentKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCurrentKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyManagerServer).GetCurrentKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/encryptionpb.KeyManager/GetCurrentKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyManagerServer).GetCurrentKey(ctx, req.(*GetCurrentKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyManager_GetKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyManagerServer).GetKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/encryptionpb.KeyManager/GetKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyManagerServer).GetKey(ctx, req.(*GetKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _KeyManager_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "encryptionpb.KeyManager",
+	HandlerType: (*KeyManagerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetCurrentKey",
+			Handler:    _KeyManager_GetCurrentKey_Handler,
+		},
+		{
+			MethodName: "GetKey",
+			Handler:    _KeyManager_GetKey_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "encryptionpb.proto",
+}
+
+func (m *DataKey) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DataKey) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.KeyId != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintEncryptionpb(dAtA, i, uint64(m.KeyId))
+	}
+	if len(m.Key) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintEncryptionpb(dAtA, i, uint64(len(m.Key)))
+		i += copy(dAtA[i:], m.Key)
+	}
+	if m.Method != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintEncryptionpb(dAtA, i, uint64(m.Method))
+	}
+	if m.CreationTime != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintEncryptionpb(dAtA, i, uint64(m.CreationTime))
+	}
+	return i, nil
+}
+
+func (m *GetCurrentKeyRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetCurrentKeyRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintEncryptionpb(dAtA, i, uint64(m.Header.Size()))
+		n1, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	return i, nil
+}
+
+func (m *GetKeyRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetKeyRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintEncryptionpb(dAtA, i, uint64(m.Header.Size()))
+		n2, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n2
+	}
+	if m.KeyId != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintEncryptionpb(dAtA, i, uint64(m.KeyId))
+	}
+	return i, nil
+}
+
+func (m *GetKeyResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetKeyResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintEncryptionpb(dAtA, i, uint64(m.Header.Size()))
+		n3, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n3
+	}
+	if m.Key != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintEncryptionpb(dAtA, i, uint64(m.Key.Size()))
+		n4, err := m.Key.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n4
+	}
+	return i, nil
+}
+
+func encodeVarintEncryptionpb(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *DataKey) Size() (n int) {
+	var l int
+	_ = l
+	if m.KeyId != 0 {
+		n += 1 + sovEncryptionpb(uint64(m.KeyId))
+	}
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovEncryptionpb(uint64(l))
+	}
+	if m.Method != 0 {
+		n += 1 + sovEncryptionpb(uint64(m.Method))
+	}
+	if m.CreationTime != 0 {
+		n += 1 + sovEncryptionpb(uint64(m.CreationTime))
+	}
+	return n
+}
+
+func (m *GetCurrentKeyRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovEncryptionpb(uint64(l))
+	}
+	return n
+}
+
+func (m *GetKeyRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovEncryptionpb(uint64(l))
+	}
+	if m.KeyId != 0 {
+		n += 1 + sovEncryptionpb(uint64(m.KeyId))
+	}
+	return n
+}
+
+func (m *GetKeyResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovEncryptionpb(uint64(l))
+	}
+	if m.Key != nil {
+		l = m.Key.Size()
+		n += 1 + l + sovEncryptionpb(uint64(l))
+	}
+	return n
+}
+
+func sovEncryptionpb(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozEncryptionpb(x uint64) (n int) {
+	return sovEncryptionpb(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *DataKey) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEncryptionpb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DataKey: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DataKey: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field KeyId", wireType)
+			}
+			m.KeyId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEncryptionpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.KeyId |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEncryptionpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthEncryptionpb
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = append(m.Key[:0], dAtA[iNdEx:postIndex]...)
+			if m.Key == nil {
+				m.Key = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Method", wireType)
+			}
+			m.Method = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEncryptionpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Method |= (EncryptionMethod(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreationTime", wireType)
+			}
+			m.CreationTime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEncryptionpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CreationTime |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEncryptionpb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEncryptionpb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetCurrentKeyRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEncryptionpb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetCurrentKeyRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetCurrentKeyRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEncryptionpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEncryptionpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.RequestHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEncryptionpb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEncryptionpb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetKeyRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEncryptionpb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetKeyRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetKeyRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEncryptionpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEncryptionpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.RequestHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field KeyId", wireType)
+			}
+			m.KeyId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEncryptionpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.KeyId |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEncryptionpb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEncryptionpb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetKeyResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEncryptionpb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetKeyResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetKeyResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEncryptionpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEncryptionpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.ResponseHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEncryptionpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEncryptionpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Key == nil {
+				m.Key = &DataKey{}
+			}
+			if err := m.Key.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEncryptionpb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEncryptionpb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipEncryptionpb(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowEncryptionpb
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowEncryptionpb
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+			return iNdEx, nil
+		case 1:
+			iNdEx += 8
+			return iNdEx, nil
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowEncryptionpb
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			iNdEx += length
+			if length < 0 {
+				return 0, ErrInvalidLengthEncryptionpb
+			}
+			return iNdEx, nil
+		case 3:
+			for {
+				var innerWire uint64
+				var start int = iNdEx
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return 0, ErrIntOverflowEncryptionpb
+					}
+					if iNdEx >= l {
+						return 0, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					innerWire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				innerWireType := int(innerWire & 0x7)
+				if innerWireType == 4 {
+					break
+				}
+				next, err := skipEncryptionpb(dAtA[start:])
+				if err != nil {
+					return 0, err
+				}
+				iNdEx = start + next
+			}
+			return iNdEx, nil
+		case 4:
+			return iNdEx, nil
+		case 5:
+			iNdEx += 4
+			return iNdEx, nil
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+	}
+	panic("unreachable")
+}
+
+var (
+	ErrInvalidLengthEncryptionpb = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowEncryptionpb   = fmt.Errorf("proto: integer overflow")
+)
+
+func init() { proto.RegisterFile("encryptionpb.proto", fileDescriptorEncryptionpb) }
+
+var fileDescriptorEncryptionpb = []byte{
+	// 375 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x92, 0xcd, 0x4e, 0xea, 0x50,
+	0x14, 0x85, 0x39, 0xc0, 0x2d, 0xc9, 0xe6, 0x27, 0xcd, 0xb9, 0x90, 0x34, 0xdc, 0x9b, 0xa6, 0xa9,
+	0x03, 0x1b, 0x35, 0x90, 0xd4, 0xc8, 0x5c, 0x2b, 0x41, 0xd3, 0x80, 0xb1, 0x60, 0x1c, 0x92, 0x42,
+	0x77, 0x4a, 0x43, 0x68, 0x6b, 0x39, 0x0c, 0xfa, 0x14, 0x4e, 0x7d, 0x00, 0x1f, 0xc6, 0xa1, 0x8f,
+	0x60, 0xf0, 0x45, 0x0c, 0xa5, 0xd5, 0x16, 0x19, 0x18, 0x67, 0xbb, 0x6b, 0xad, 0xae, 0x9e, 0xfd,
+	0xf5, 0x00, 0x45, 0x77, 0x1a, 0x84, 0x3e, 0x73, 0x3c, 0xd7, 0x9f, 0xb4, 0xfc, 0xc0, 0x63, 0x1e,
+	0xad, 0xa4, 0xb5, 0x26, 0xf8, 0x56, 0xe2, 0x34, 0xeb, 0xb6, 0x67, 0x7b, 0xd1, 0xd8, 0xde, 0x4c,
+	0x5b, 0x55, 0x7e, 0x24, 0x50, 0xba, 0x34, 0x99, 0xa9, 0x63, 0x48, 0x1b, 0xc0, 0xcd, 0x31, 0x1c,
+	0x3b, 0x96, 0x40, 0x24, 0xa2, 0x14, 0x8d, 0x3f, 0x73, 0x0c, 0xaf, 0x2d, 0xca, 0x43, 0x61, 0x8e,
+	0xa1, 0x90, 0x97, 0x88, 0x52, 0x31, 0x36, 0x23, 0xed, 0x00, 0xb7, 0x40, 0x36, 0xf3, 0x2c, 0xa1,
+	0x20, 0x11, 0xa5, 0xa6, 0x8a, 0xad, 0xcc, 0x49, 0xba, 0x9f, 0x0f, 0xfd, 0x28, 0x65, 0xc4, 0x69,
+	0x7a, 0x00, 0xd5, 0x69, 0x80, 0xe6, 0xc6, 0x19, 0x33, 0x67, 0x81, 0x42, 0x51, 0x22, 0x4a, 0xc1,
+	0xa8, 0x24, 0xe2, 0xc8, 0x59, 0xa0, 0xac, 0x41, 0xbd, 0x87, 0x4c, 0x5b, 0x05, 0x01, 0xba, 0x4c,
+	0xc7, 0xd0, 0xc0, 0x87, 0x15, 0x2e, 0x19, 0x3d, 0x06, 0x6e, 0x86, 0xa6, 0x85, 0x41, 0x74, 0xba,
+	0xb2, 0xfa, 0xb7, 0x15, 0x2d, 0x17, 0xdb, 0x57, 0x91, 0x65, 0xc4, 0x11, 0x79, 0x08, 0xd5, 0x1e,
+	0xfe, 0xf6, 0xed, 0x14, 0x88, 0x7c, 0x0a, 0x84, 0x6c, 0x43, 0x2d, 0x29, 0x5d, 0xfa, 0x9e, 0xbb,
+	0x44, 0x7a, 0xb2, 0xd3, 0x5a, 0x4f, 0x5a, 0xb7, 0xfe, 0x4e, 0xed, 0xe1, 0x17, 0xc8, 0xb2, 0xda,
+	0xc8, 0x32, 0x8b, 0xff, 0x41, 0xc4, 0xf7, 0xa8, 0x0d, 0xfc, 0x2e, 0x43, 0x5a, 0x86, 0xd2, 0xdd,
+	0x40, 0x1f, 0xdc, 0xdc, 0x0f, 0xf8, 0x1c, 0xad, 0x01, 0x9c, 0x77, 0x87, 0xea, 0x59, 0x67, 0xac,
+	0x8d, 0x0c, 0x9e, 0xa8, 0xcf, 0x04, 0x40, 0xc7, 0xb0, 0x6f, 0xba, 0xa6, 0x8d, 0x01, 0xbd, 0x85,
+	0x6a, 0x06, 0x21, 0x95, 0xb3, 0x1f, 0xdb, 0xc7, 0xb7, 0xf9, 0xff, 0x5b, 0x26, 0xbd, 0xa9, 0x06,
+	0xdc, 0x56, 0xa1, 0xff, 0xf6, 0xe7, 0x7e, 0x50, 0x72, 0xc1, 0xbf, 0xac, 0x45, 0xf2, 0xba, 0x16,
+	0xc9, 0xdb, 0x5a, 0x24, 0x4f, 0xef, 0x62, 0x6e, 0xc2, 0x45, 0xb7, 0xf0, 0xf4, 0x63, 0x00, 0x34,
+	0x1b, 0xea, 0xe3, 0xcb, 0x02, 0x00, 0x00,
+}
@@ -0,0 +1,314 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extstorage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	s3RequestTimeout = time.Minute
+	s3TimeFormat     = "20060102T150405Z"
+	s3DateFormat     = "20060102"
+	defaultS3Region  = "us-east-1"
+	gcsEndpoint      = "https://storage.googleapis.com"
+)
+
+// s3Storage saves the data as objects of a bucket via the S3 REST API. The
+// requests are signed with AWS Signature Version 4, which is also accepted by
+// the XML API of GCS with HMAC keys, so it serves both of them.
+type s3Storage struct {
+	client *http.Client
+	// endpoint is the URL of the bucket, such as `https://s3.amazonaws.com/bucket`.
+	endpoint  *url.URL
+	prefix    string
+	region    string
+	accessKey string
+	secretKey string
+	// sessionToken is the token of temporary credentials, it can be empty.
+	sessionToken string
+}
+
+// newS3Storage creates the storage of a URL like
+// `s3://bucket/prefix?region=us-west-2&endpoint=http://minio:9000`. The
+// credentials are read from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+// AWS_SESSION_TOKEN, so they don't show up in the config.
+func newS3Storage(u *url.URL) (ExternalStorage, error) {
+	region := u.Query().Get("region")
+	if region == "" {
+		region = os.Getenv("AWS_REGION")
+	}
+	if region == "" {
+		region = defaultS3Region
+	}
+	endpoint := u.Query().Get("endpoint")
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+	return newS3StorageWithEndpoint(u, endpoint, region,
+		os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"), os.Getenv("AWS_SESSION_TOKEN"))
+}
+
+// newGCSStorage creates the storage of a URL like `gcs://bucket/prefix`. The
+// HMAC key is read from GCS_ACCESS_KEY_ID and GCS_SECRET_ACCESS_KEY.
+func newGCSStorage(u *url.URL) (ExternalStorage, error) {
+	endpoint := u.Query().Get("endpoint")
+	if endpoint == "" {
+		endpoint = gcsEndpoint
+	}
+	return newS3StorageWithEndpoint(u, endpoint, "auto",
+		os.Getenv("GCS_ACCESS_KEY_ID"), os.Getenv("GCS_SECRET_ACCESS_KEY"), "")
+}
+
+func newS3StorageWithEndpoint(u *url.URL, endpoint, region, accessKey, secretKey, sessionToken string) (ExternalStorage, error) {
+	if u.Host == "" {
+		return nil, errors.Errorf("%s storage requires a bucket", u.Scheme)
+	}
+	if accessKey == "" || secretKey == "" {
+		return nil, errors.Errorf("%s storage requires the access key and secret key", u.Scheme)
+	}
+	e, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if e.Scheme == "" || e.Host == "" {
+		return nil, errors.Errorf("invalid endpoint %q", endpoint)
+	}
+	// Always use the path-style URL, it works with any bucket name and the
+	// S3 compatible storages.
+	e.Path = strings.TrimSuffix(e.Path, "/") + "/" + u.Host
+	prefix := strings.Trim(u.Path, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	return &s3Storage{
+		client:       &http.Client{Timeout: s3RequestTimeout},
+		endpoint:     e,
+		prefix:       prefix,
+		region:       region,
+		accessKey:    accessKey,
+		secretKey:    secretKey,
+		sessionToken: sessionToken,
+	}, nil
+}
+
+func (s *s3Storage) objectPath(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return "", errors.Errorf("invalid name %q", name)
+	}
+	return s.endpoint.Path + "/" + s.prefix + name, nil
+}
+
+func (s *s3Storage) Write(name string, data []byte) error {
+	path, err := s.objectPath(name)
+	if err != nil {
+		return err
+	}
+	_, err = s.do(http.MethodPut, path, nil, data)
+	return err
+}
+
+func (s *s3Storage) Read(name string) ([]byte, error) {
+	path, err := s.objectPath(name)
+	if err != nil {
+		return nil, err
+	}
+	return s.do(http.MethodGet, path, nil, nil)
+}
+
+// listBucketResult is the response of ListObjectsV2.
+type listBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func (s *s3Storage) List() ([]string, error) {
+	var (
+		names []string
+		token string
+	)
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		query.Set("prefix", s.prefix)
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		data, err := s.do(http.MethodGet, s.endpoint.Path, query, nil)
+		if err != nil {
+			return nil, err
+		}
+		var result listBucketResult
+		if err = xml.Unmarshal(data, &result); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		for _, c := range result.Contents {
+			name := strings.TrimPrefix(c.Key, s.prefix)
+			// Skip the objects in the sub-directories.
+			if name != "" && !strings.Contains(name, "/") {
+				names = append(names, name)
+			}
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			break
+		}
+		token = result.NextContinuationToken
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *s3Storage) Delete(name string) error {
+	path, err := s.objectPath(name)
+	if err != nil {
+		return err
+	}
+	_, err = s.do(http.MethodDelete, path, nil, nil)
+	return err
+}
+
+// do sends a signed request and returns the response body. A non-2xx status
+// is returned as an error.
+func (s *s3Storage) do(method, path string, query url.Values, body []byte) ([]byte, error) {
+	u := *s.endpoint
+	u.Path = path
+	u.RawPath = uriEncode(path, false)
+	u.RawQuery = canonicalQuery(query)
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	s.sign(req, body, time.Now())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, errors.Errorf("%s %s failed: %s %s", method, path, resp.Status, bytes.TrimSpace(data))
+	}
+	return data, nil
+}
+
+// sign adds the headers of AWS Signature Version 4 to the request.
+func (s *s3Storage) sign(req *http.Request, body []byte, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format(s3TimeFormat)
+	date := now.Format(s3DateFormat)
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var canonicalHeaders bytes.Buffer
+	for _, k := range keys {
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", k, headers[k])
+	}
+	signedHeaders := strings.Join(keys, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		uriEncode(req.URL.Path, false),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := strings.Join([]string{date, s.region, "s3", "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := []byte("AWS4" + s.secretKey)
+	for _, v := range []string{date, s.region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, v)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+// canonicalQuery encodes the query sorted by key as required by the signature.
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var pairs []string
+	for _, k := range keys {
+		for _, v := range query[k] {
+			pairs = append(pairs, uriEncode(k, true)+"="+uriEncode(v, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode escapes all bytes except the unreserved characters, and also '/'
+// if encodeSlash is true.
+func uriEncode(s string, encodeSlash bool) string {
+	var buf bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		if (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9') ||
+			b == '-' || b == '_' || b == '.' || b == '~' || (b == '/' && !encodeSlash) {
+			buf.WriteByte(b)
+		} else {
+			fmt.Fprintf(&buf, "%%%02X", b)
+		}
+	}
+	return buf.String()
+}
+
+func sha256Hex(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
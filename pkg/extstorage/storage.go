@@ -0,0 +1,152 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extstorage
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ExternalStorage is a storage outside of the PD cluster, which is used to
+// keep the backups of PD.
+type ExternalStorage interface {
+	// Write saves the data with the name, an existing one is overwritten.
+	Write(name string, data []byte) error
+	// Read returns the data with the name.
+	Read(name string) ([]byte, error)
+	// List returns the names of all data in ascending order.
+	List() ([]string, error)
+	// Delete deletes the data with the name.
+	Delete(name string) error
+}
+
+// CreateFunc creates an external storage by the URL.
+type CreateFunc func(u *url.URL) (ExternalStorage, error)
+
+var (
+	mu       sync.RWMutex
+	backends = map[string]CreateFunc{
+		"":      newLocalStorage,
+		"local": newLocalStorage,
+		"file":  newLocalStorage,
+		"s3":    newS3Storage,
+		"gcs":   newGCSStorage,
+		"gs":    newGCSStorage,
+	}
+)
+
+// Register registers the backend of a URL scheme, which replaces the built-in
+// one of the same scheme.
+func Register(scheme string, f CreateFunc) {
+	mu.Lock()
+	defer mu.Unlock()
+	backends[scheme] = f
+}
+
+// Create creates an external storage by the URL, such as `/path/to/dir`,
+// `local:///path/to/dir`, `s3://bucket/prefix` or `gcs://bucket/prefix`.
+func Create(rawURL string) (ExternalStorage, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	mu.RLock()
+	f, ok := backends[u.Scheme]
+	mu.RUnlock()
+	if !ok {
+		return nil, errors.Errorf("storage %q is not supported", u.Scheme)
+	}
+	return f(u)
+}
+
+// localStorage saves the data as files in a local directory, which is
+// usually a mounted NFS.
+type localStorage struct {
+	dir string
+}
+
+func newLocalStorage(u *url.URL) (ExternalStorage, error) {
+	dir := u.Path
+	if u.Host != "" {
+		// For relative path like `local://backup`.
+		dir = filepath.Join(u.Host, u.Path)
+	}
+	if dir == "" {
+		return nil, errors.New("local storage requires a directory")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &localStorage{dir: dir}, nil
+}
+
+func (s *localStorage) path(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return "", errors.Errorf("invalid name %q", name)
+	}
+	return filepath.Join(s.dir, name), nil
+}
+
+func (s *localStorage) Write(name string, data []byte) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first, so a partial file is never visible.
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(os.Rename(tmp, path))
+}
+
+func (s *localStorage) Read(name string) ([]byte, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(path)
+	return data, errors.WithStack(err)
+}
+
+func (s *localStorage) List() ([]string, error) {
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var names []string
+	for _, f := range files {
+		if f.IsDir() || strings.HasSuffix(f.Name(), ".tmp") {
+			continue
+		}
+		names = append(names, f.Name())
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *localStorage) Delete(name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	return errors.WithStack(os.Remove(path))
+}
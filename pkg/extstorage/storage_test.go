@@ -0,0 +1,65 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extstorage
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	. "github.com/pingcap/check"
+)
+
+func TestExtStorage(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testStorageSuite{})
+
+type testStorageSuite struct{}
+
+func (s *testStorageSuite) TestLocalStorage(c *C) {
+	dir, err := ioutil.TempDir("", "extstorage")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+
+	storage, err := Create("local://" + dir)
+	c.Assert(err, IsNil)
+	c.Assert(storage.Write("b", []byte("2")), IsNil)
+	c.Assert(storage.Write("a", []byte("1")), IsNil)
+	c.Assert(storage.Write("a", []byte("3")), IsNil)
+	names, err := storage.List()
+	c.Assert(err, IsNil)
+	c.Assert(names, DeepEquals, []string{"a", "b"})
+	data, err := storage.Read("a")
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "3")
+
+	c.Assert(storage.Delete("a"), IsNil)
+	_, err = storage.Read("a")
+	c.Assert(err, NotNil)
+	c.Assert(storage.Write("../a", nil), NotNil)
+
+	// The plain path is the same as local storage.
+	storage, err = Create(dir)
+	c.Assert(err, IsNil)
+	names, err = storage.List()
+	c.Assert(err, IsNil)
+	c.Assert(names, DeepEquals, []string{"b"})
+}
+
+func (s *testStorageSuite) TestUnsupportedStorage(c *C) {
+	_, err := Create("hdfs://bucket/prefix")
+	c.Assert(err, NotNil)
+}
@@ -0,0 +1,1445 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: gcpb.proto
+
+/*
+Package gcpb is a generated protocol buffer package.
+
+It is generated from these files:
+
+	gcpb.proto
+
+It has these top-level messages:
+
+	ServiceSafePoint
+	UpdateServiceSafePointRequest
+	UpdateServiceSafePointResponse
+	ListServiceSafePointsRequest
+	ListServiceSafePointsResponse
+*/
+package gcpb
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	proto "github.com/golang/protobuf/proto"
+
+	pdpb "github.com/pingcap/kvproto/pkg/pdpb"
+
+	_ "github.com/gogo/protobuf/gogoproto"
+
+	context "golang.org/x/net/context"
+
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// ServiceSafePoint is the GC safe point of a service. The cluster GC safe
+// point never exceeds it before it expires.
+type ServiceSafePoint struct {
+	ServiceId string `protobuf:"bytes,1,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	SafePoint uint64 `protobuf:"varint,2,opt,name=safe_point,json=safePoint,proto3" json:"safe_point,omitempty"`
+	// expired_at is the unix time in seconds, math.MaxInt64 means never.
+	ExpiredAt int64 `protobuf:"varint,3,opt,name=expired_at,json=expiredAt,proto3" json:"expired_at,omitempty"`
+}
+
+func (m *ServiceSafePoint) Reset()                    { *m = ServiceSafePoint{} }
+func (m *ServiceSafePoint) String() string            { return proto.CompactTextString(m) }
+func (*ServiceSafePoint) ProtoMessage()               {}
+func (*ServiceSafePoint) Descriptor() ([]byte, []int) { return fileDescriptorGcpb, []int{0} }
+
+func (m *ServiceSafePoint) GetServiceId() string {
+	if m != nil {
+		return m.ServiceId
+	}
+	return ""
+}
+
+func (m *ServiceSafePoint) GetSafePoint() uint64 {
+	if m != nil {
+		return m.SafePoint
+	}
+	return 0
+}
+
+func (m *ServiceSafePoint) GetExpiredAt() int64 {
+	if m != nil {
+		return m.ExpiredAt
+	}
+	return 0
+}
+
+// UpdateServiceSafePointRequest registers or updates the safe point of a
+// service, which expires after ttl seconds. The safe point is removed if ttl
+// is not positive, and it never expires if ttl is math.MaxInt64.
+type UpdateServiceSafePointRequest struct {
+	Header    *pdpb.RequestHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+	ServiceId string              `protobuf:"bytes,2,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	Ttl       int64               `protobuf:"varint,3,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	SafePoint uint64              `protobuf:"varint,4,opt,name=safe_point,json=safePoint,proto3" json:"safe_point,omitempty"`
+}
+
+func (m *UpdateServiceSafePointRequest) Reset()         { *m = UpdateServiceSafePointRequest{} }
+func (m *UpdateServiceSafePointRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateServiceSafePointRequest) ProtoMessage()    {}
+func (*UpdateServiceSafePointRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptorGcpb, []int{1}
+}
+
+func (m *UpdateServiceSafePointRequest) GetHeader() *pdpb.RequestHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *UpdateServiceSafePointRequest) GetServiceId() string {
+	if m != nil {
+		return m.ServiceId
+	}
+	return ""
+}
+
+func (m *UpdateServiceSafePointRequest) GetTtl() int64 {
+	if m != nil {
+		return m.Ttl
+	}
+	return 0
+}
+
+func (m *UpdateServiceSafePointRequest) GetSafePoint() uint64 {
+	if m != nil {
+		return m.SafePoint
+	}
+	return 0
+}
+
+// UpdateServiceSafePointResponse returns the min safe point of all services
+// after the update. The safe point is not updated if it is less than the
+// cluster GC safe point, and ttl is 0 then.
+type UpdateServiceSafePointResponse struct {
+	Header       *pdpb.ResponseHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+	ServiceId    string               `protobuf:"bytes,2,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	Ttl          int64                `protobuf:"varint,3,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	MinSafePoint uint64               `protobuf:"varint,4,opt,name=min_safe_point,json=minSafePoint,proto3" json:"min_safe_point,omitempty"`
+}
+
+func (m *UpdateServiceSafePointResponse) Reset()         { *m = UpdateServiceSafePointResponse{} }
+func (m *UpdateServiceSafePointResponse) String() string { return proto.CompactTextString(m) }
+func (*UpdateServiceSafePointResponse) ProtoMessage()    {}
+func (*UpdateServiceSafePointResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptorGcpb, []int{2}
+}
+
+func (m *UpdateServiceSafePointResponse) GetHeader() *pdpb.ResponseHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *UpdateServiceSafePointResponse) GetServiceId() string {
+	if m != nil {
+		return m.ServiceId
+	}
+	return ""
+}
+
+func (m *UpdateServiceSafePointResponse) GetTtl() int64 {
+	if m != nil {
+		return m.Ttl
+	}
+	return 0
+}
+
+func (m *UpdateServiceSafePointResponse) GetMinSafePoint() uint64 {
+	if m != nil {
+		return m.MinSafePoint
+	}
+	return 0
+}
+
+// ListServiceSafePointsRequest lists the safe points of all services.
+type ListServiceSafePointsRequest struct {
+	Header *pdpb.RequestHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+}
+
+func (m *ListServiceSafePointsRequest) Reset()         { *m = ListServiceSafePointsRequest{} }
+func (m *ListServiceSafePointsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListServiceSafePointsRequest) ProtoMessage()    {}
+func (*ListServiceSafePointsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptorGcpb, []int{3}
+}
+
+func (m *ListServiceSafePointsRequest) GetHeader() *pdpb.RequestHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+// ListServiceSafePointsResponse returns the safe points of the unexpired
+// services and the cluster GC safe point.
+type ListServiceSafePointsResponse struct {
+	Header            *pdpb.ResponseHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+	ServiceSafePoints []*ServiceSafePoint  `protobuf:"bytes,2,rep,name=service_safe_points,json=serviceSafePoints" json:"service_safe_points,omitempty"`
+	GcSafePoint       uint64               `protobuf:"varint,3,opt,name=gc_safe_point,json=gcSafePoint,proto3" json:"gc_safe_point,omitempty"`
+	MinSafePoint      uint64               `protobuf:"varint,4,opt,name=min_safe_point,json=minSafePoint,proto3" json:"min_safe_point,omitempty"`
+}
+
+func (m *ListServiceSafePointsResponse) Reset()         { *m = ListServiceSafePointsResponse{} }
+func (m *ListServiceSafePointsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListServiceSafePointsResponse) ProtoMessage()    {}
+func (*ListServiceSafePointsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptorGcpb, []int{4}
+}
+
+func (m *ListServiceSafePointsResponse) GetHeader() *pdpb.ResponseHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *ListServiceSafePointsResponse) GetServiceSafePoints() []*ServiceSafePoint {
+	if m != nil {
+		return m.ServiceSafePoints
+	}
+	return nil
+}
+
+func (m *ListServiceSafePointsResponse) GetGcSafePoint() uint64 {
+	if m != nil {
+		return m.GcSafePoint
+	}
+	return 0
+}
+
+func (m *ListServiceSafePointsResponse) GetMinSafePoint() uint64 {
+	if m != nil {
+		return m.MinSafePoint
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*ServiceSafePoint)(nil), "gcpb.ServiceSafePoint")
+	proto.RegisterType((*UpdateServiceSafePointRequest)(nil), "gcpb.UpdateServiceSafePointRequest")
+	proto.RegisterType((*UpdateServiceSafePointResponse)(nil), "gcpb.UpdateServiceSafePointResponse")
+	proto.RegisterType((*ListServiceSafePointsRequest)(nil), "gcpb.ListServiceSafePointsRequest")
+	proto.RegisterType((*ListServiceSafePointsResponse)(nil), "gcpb.ListServiceSafePointsResponse")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for GC service
+
+type GCClient interface {
+	UpdateServiceSafePoint(ctx context.Context, in *UpdateServiceSafePointRequest, opts ...grpc.CallOption) (*UpdateServiceSafePointResponse, error)
+	ListServiceSafePoints(ctx context.Context, in *ListServiceSafePointsRequest, opts ...grpc.CallOption) (*ListServiceSafePointsResponse, error)
+}
+
+type gCClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewGCClient(cc *grpc.ClientConn) GCClient {
+	return &gCClient{cc}
+}
+
+func (c *gCClient) UpdateServiceSafePoint(ctx context.Context, in *UpdateServiceSafePointRequest, opts ...grpc.CallOption) (*UpdateServiceSafePointResponse, error) {
+	out := new(UpdateServiceSafePointResponse)
+	err := grpc.Invoke(ctx, "/gcpb.GC/UpdateServiceSafePoint", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gCClient) ListServiceSafePoints(ctx context.Context, in *ListServiceSafePointsRequest, opts ...grpc.CallOption) (*ListServiceSafePointsResponse, error) {
+	out := new(ListServiceSafePointsResponse)
+	err := grpc.Invoke(ctx, "/gcpb.GC/ListServiceSafePoints", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for GC service
+
+type GCServer interface {
+	UpdateServiceSafePoint(context.Context, *UpdateServiceSafePointRequest) (*UpdateServiceSafePointResponse, error)
+	ListServiceSafePoints(context.Context, *ListServiceSafePointsRequest) (*ListServiceSafePointsResponse, error)
+}
+
+func RegisterGCServer(s *grpc.Server, srv GCServer) {
+	s.RegisterService(&_GC_serviceDesc, srv)
+}
+
+func _GC_UpdateServiceSafePoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateServiceSafePointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GCServer).UpdateServiceSafePoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gcpb.GC/UpdateServiceSafePoint",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GCServer).UpdateServiceSafePoint(ctx, req.(*UpdateServiceSafePointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GC_ListServiceSafePoints_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListServiceSafePointsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GCServer).ListServiceSafePoints(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gcpb.GC/ListServiceSafePoints",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GCServer).ListServiceSafePoints(ctx, req.(*ListServiceSafePointsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _GC_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "gcpb.GC",
+	HandlerType: (*GCServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "UpdateServiceSafePoint",
+			Handler:    _GC_UpdateServiceSafePoint_Handler,
+		},
+		{
+			MethodName: "ListServiceSafePoints",
+			Handler:    _GC_ListServiceSafePoints_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "gcpb.proto",
+}
+
+func (m *ServiceSafePoint) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ServiceSafePoint) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.ServiceId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintGcpb(dAtA, i, uint64(len(m.ServiceId)))
+		i += copy(dAtA[i:], m.ServiceId)
+	}
+	if m.SafePoint != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintGcpb(dAtA, i, uint64(m.SafePoint))
+	}
+	if m.ExpiredAt != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintGcpb(dAtA, i, uint64(m.ExpiredAt))
+	}
+	return i, nil
+}
+
+func (m *UpdateServiceSafePointRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *UpdateServiceSafePointRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintGcpb(dAtA, i, uint64(m.Header.Size()))
+		n1, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	if len(m.ServiceId) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintGcpb(dAtA, i, uint64(len(m.ServiceId)))
+		i += copy(dAtA[i:], m.ServiceId)
+	}
+	if m.Ttl != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintGcpb(dAtA, i, uint64(m.Ttl))
+	}
+	if m.SafePoint != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintGcpb(dAtA, i, uint64(m.SafePoint))
+	}
+	return i, nil
+}
+
+func (m *UpdateServiceSafePointResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *UpdateServiceSafePointResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintGcpb(dAtA, i, uint64(m.Header.Size()))
+		n2, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n2
+	}
+	if len(m.ServiceId) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintGcpb(dAtA, i, uint64(len(m.ServiceId)))
+		i += copy(dAtA[i:], m.ServiceId)
+	}
+	if m.Ttl != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintGcpb(dAtA, i, uint64(m.Ttl))
+	}
+	if m.MinSafePoint != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintGcpb(dAtA, i, uint64(m.MinSafePoint))
+	}
+	return i, nil
+}
+
+func (m *ListServiceSafePointsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ListServiceSafePointsRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintGcpb(dAtA, i, uint64(m.Header.Size()))
+		n3, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n3
+	}
+	return i, nil
+}
+
+func (m *ListServiceSafePointsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ListServiceSafePointsResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintGcpb(dAtA, i, uint64(m.Header.Size()))
+		n4, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n4
+	}
+	if len(m.ServiceSafePoints) > 0 {
+		for _, msg := range m.ServiceSafePoints {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintGcpb(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.GcSafePoint != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintGcpb(dAtA, i, uint64(m.GcSafePoint))
+	}
+	if m.MinSafePoint != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintGcpb(dAtA, i, uint64(m.MinSafePoint))
+	}
+	return i, nil
+}
+
+func encodeVarintGcpb(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *ServiceSafePoint) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.ServiceId)
+	if l > 0 {
+		n += 1 + l + sovGcpb(uint64(l))
+	}
+	if m.SafePoint != 0 {
+		n += 1 + sovGcpb(uint64(m.SafePoint))
+	}
+	if m.ExpiredAt != 0 {
+		n += 1 + sovGcpb(uint64(m.ExpiredAt))
+	}
+	return n
+}
+
+func (m *UpdateServiceSafePointRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovGcpb(uint64(l))
+	}
+	l = len(m.ServiceId)
+	if l > 0 {
+		n += 1 + l + sovGcpb(uint64(l))
+	}
+	if m.Ttl != 0 {
+		n += 1 + sovGcpb(uint64(m.Ttl))
+	}
+	if m.SafePoint != 0 {
+		n += 1 + sovGcpb(uint64(m.SafePoint))
+	}
+	return n
+}
+
+func (m *UpdateServiceSafePointResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovGcpb(uint64(l))
+	}
+	l = len(m.ServiceId)
+	if l > 0 {
+		n += 1 + l + sovGcpb(uint64(l))
+	}
+	if m.Ttl != 0 {
+		n += 1 + sovGcpb(uint64(m.Ttl))
+	}
+	if m.MinSafePoint != 0 {
+		n += 1 + sovGcpb(uint64(m.MinSafePoint))
+	}
+	return n
+}
+
+func (m *ListServiceSafePointsRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovGcpb(uint64(l))
+	}
+	return n
+}
+
+func (m *ListServiceSafePointsResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovGcpb(uint64(l))
+	}
+	if len(m.ServiceSafePoints) > 0 {
+		for _, e := range m.ServiceSafePoints {
+			l = e.Size()
+			n += 1 + l + sovGcpb(uint64(l))
+		}
+	}
+	if m.GcSafePoint != 0 {
+		n += 1 + sovGcpb(uint64(m.GcSafePoint))
+	}
+	if m.MinSafePoint != 0 {
+		n += 1 + sovGcpb(uint64(m.MinSafePoint))
+	}
+	return n
+}
+
+func sovGcpb(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozGcpb(x uint64) (n int) {
+	return sovGcpb(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *ServiceSafePoint) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGcpb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ServiceSafePoint: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ServiceSafePoint: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ServiceId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGcpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGcpb
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ServiceId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SafePoint", wireType)
+			}
+			m.SafePoint = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGcpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SafePoint |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExpiredAt", wireType)
+			}
+			m.ExpiredAt = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGcpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ExpiredAt |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGcpb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthGcpb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *UpdateServiceSafePointRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGcpb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: UpdateServiceSafePointRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: UpdateServiceSafePointRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGcpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGcpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.RequestHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ServiceId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGcpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGcpb
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ServiceId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Ttl", wireType)
+			}
+			m.Ttl = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGcpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Ttl |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SafePoint", wireType)
+			}
+			m.SafePoint = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGcpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SafePoint |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGcpb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthGcpb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *UpdateServiceSafePointResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGcpb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: UpdateServiceSafePointResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: UpdateServiceSafePointResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGcpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGcpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.ResponseHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ServiceId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGcpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGcpb
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ServiceId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Ttl", wireType)
+			}
+			m.Ttl = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGcpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Ttl |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinSafePoint", wireType)
+			}
+			m.MinSafePoint = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGcpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MinSafePoint |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGcpb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthGcpb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ListServiceSafePointsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGcpb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ListServiceSafePointsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ListServiceSafePointsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGcpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGcpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.RequestHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGcpb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthGcpb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ListServiceSafePointsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGcpb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ListServiceSafePointsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ListServiceSafePointsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGcpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGcpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.ResponseHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ServiceSafePoints", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGcpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGcpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ServiceSafePoints = append(m.ServiceSafePoints, &ServiceSafePoint{})
+			if err := m.ServiceSafePoints[len(m.ServiceSafePoints)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GcSafePoint", wireType)
+			}
+			m.GcSafePoint = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGcpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.GcSafePoint |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinSafePoint", wireType)
+			}
+			m.MinSafePoint = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGcpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MinSafePoint |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGcpb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthGcpb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipGcpb(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowGcpb
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowGcpb
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+			return iNdEx, nil
+		case 1:
+			iNdEx += 8
+			return iNdEx, nil
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowGcpb
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			iNdEx += length
+			if length < 0 {
+				return 0, ErrInvalidLengthGcpb
+			}
+			return iNdEx, nil
+		case 3:
+			for {
+				var innerWire uint64
+				var start int = iNdEx
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return 0, ErrIntOverflowGcpb
+					}
+					if iNdEx >= l {
+						return 0, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					innerWire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				innerWireType := int(innerWire & 0x7)
+				if innerWireType == 4 {
+					break
+				}
+				next, err := skipGcpb(dAtA[start:])
+				if err != nil {
+					return 0, err
+				}
+				iNdEx = start + next
+			}
+			return iNdEx, nil
+		case 4:
+			return iNdEx, nil
+		case 5:
+			iNdEx += 4
+			return iNdEx, nil
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+	}
+	panic("unreachable")
+}
+
+var (
+	ErrInvalidLengthGcpb = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowGcpb   = fmt.Errorf("proto: integer overflow")
+)
+
+func init() { proto.RegisterFile("gcpb.proto", fileDescriptorGcpb) }
+
+var fileDescriptorGcpb = []byte{
+	// 386 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x93, 0xdf, 0x4a, 0xeb, 0x40,
+	0x10, 0xc6, 0xcf, 0x26, 0xa5, 0x90, 0xe9, 0x39, 0x87, 0xba, 0xad, 0x25, 0x04, 0x1b, 0x42, 0xda,
+	0x8b, 0x80, 0x52, 0xa1, 0x3e, 0x81, 0x0a, 0xfe, 0x41, 0x2f, 0x24, 0xc5, 0xeb, 0x98, 0x26, 0xdb,
+	0x18, 0xb0, 0x49, 0xcc, 0xae, 0xe2, 0xa3, 0x78, 0xe1, 0xad, 0xef, 0x22, 0x78, 0xe3, 0x1b, 0x28,
+	0xf5, 0x45, 0x24, 0x9b, 0xd4, 0x96, 0x6d, 0x1b, 0xa4, 0xde, 0x0d, 0xdf, 0xcc, 0x66, 0x7e, 0xf3,
+	0xcd, 0x04, 0x20, 0xf0, 0x92, 0x61, 0x2f, 0x49, 0x63, 0x16, 0xe3, 0x4a, 0x16, 0x6b, 0x90, 0xf8,
+	0x53, 0x45, 0x6b, 0x06, 0x71, 0x10, 0xf3, 0x70, 0x37, 0x8b, 0x72, 0xd5, 0x8c, 0xa1, 0x3e, 0x20,
+	0xe9, 0x7d, 0xe8, 0x91, 0x81, 0x3b, 0x22, 0x17, 0x71, 0x18, 0x31, 0xdc, 0x06, 0xa0, 0xb9, 0xe6,
+	0x84, 0xbe, 0x8a, 0x0c, 0x64, 0x29, 0xb6, 0x52, 0x28, 0xa7, 0x3e, 0x4f, 0xbb, 0x23, 0xe2, 0x24,
+	0x59, 0xb1, 0x2a, 0x19, 0xc8, 0xaa, 0xd8, 0x0a, 0x9d, 0x7f, 0x4d, 0x1e, 0x92, 0x30, 0x25, 0xbe,
+	0xe3, 0x32, 0x55, 0x36, 0x90, 0x25, 0xdb, 0x4a, 0xa1, 0xec, 0x33, 0xf3, 0x09, 0x41, 0xfb, 0x32,
+	0xf1, 0x5d, 0x46, 0xc4, 0xbe, 0x36, 0xb9, 0xbd, 0x23, 0x94, 0xe1, 0x6d, 0xa8, 0x5e, 0x13, 0xd7,
+	0x27, 0x29, 0x6f, 0x5d, 0xeb, 0x37, 0x7a, 0x7c, 0x8a, 0x22, 0x7d, 0xc2, 0x53, 0x76, 0x51, 0x22,
+	0xb0, 0x4a, 0x22, 0x6b, 0x1d, 0x64, 0xc6, 0x6e, 0x0a, 0x8a, 0x2c, 0x14, 0xe8, 0x2b, 0x02, 0xbd,
+	0xf9, 0x8c, 0x40, 0x5f, 0x85, 0x47, 0x93, 0x38, 0xa2, 0x04, 0xef, 0x08, 0x7c, 0xcd, 0x29, 0x5f,
+	0x9e, 0xff, 0x2d, 0x60, 0x17, 0xfe, 0x8f, 0xc3, 0xc8, 0x59, 0x80, 0xfc, 0x3b, 0x0e, 0xa3, 0x6f,
+	0x18, 0xf3, 0x0c, 0xb6, 0xce, 0x43, 0xca, 0x44, 0x48, 0xba, 0x8e, 0x89, 0xe6, 0x3b, 0x82, 0xf6,
+	0x8a, 0xaf, 0xad, 0x35, 0xf3, 0x11, 0x34, 0xa6, 0x33, 0xcf, 0xc6, 0xa0, 0xaa, 0x64, 0xc8, 0x56,
+	0xad, 0xdf, 0xea, 0xf1, 0x33, 0x5d, 0xb0, 0x77, 0x83, 0x8a, 0xdd, 0xb1, 0x09, 0xff, 0x02, 0x6f,
+	0xde, 0x09, 0x99, 0x3b, 0x51, 0x0b, 0xbc, 0xd9, 0xb1, 0xfe, 0xc8, 0xae, 0xfe, 0x2b, 0x02, 0xe9,
+	0xf8, 0x10, 0x7b, 0xd0, 0x5a, 0xbe, 0x5c, 0xdc, 0xc9, 0xa9, 0x4a, 0x2f, 0x53, 0xeb, 0x96, 0x17,
+	0x15, 0x5e, 0x5d, 0xc1, 0xe6, 0x52, 0x33, 0xb1, 0x99, 0x3f, 0x2f, 0xdb, 0x9b, 0xd6, 0x29, 0xad,
+	0xc9, 0x3b, 0x1c, 0xd4, 0x5f, 0x26, 0x3a, 0x7a, 0x9b, 0xe8, 0xe8, 0x63, 0xa2, 0xa3, 0xc7, 0x4f,
+	0xfd, 0xcf, 0xb0, 0xca, 0xff, 0xe6, 0xbd, 0xaf, 0x01, 0x00, 0xf9, 0xc0, 0x30, 0x85, 0x03, 0x04,
+	0x00, 0x00,
+}
@@ -0,0 +1,639 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: idpb.proto
+
+/*
+Package idpb is a generated protocol buffer package.
+
+It is generated from these files:
+
+	idpb.proto
+
+It has these top-level messages:
+
+	AllocIDRequest
+	AllocIDResponse
+*/
+package idpb
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	proto "github.com/golang/protobuf/proto"
+
+	pdpb "github.com/pingcap/kvproto/pkg/pdpb"
+
+	_ "github.com/gogo/protobuf/gogoproto"
+
+	context "golang.org/x/net/context"
+
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type IDKind int32
+
+const (
+	// COUNTER IDs are allocated from a counter. They are small but do not tell
+	// the allocation order across leaders.
+	IDKind_COUNTER IDKind = 0
+	// TIME_ORDERED IDs are prefixed with the allocation time, so that they are
+	// roughly ordered by the allocation time.
+	IDKind_TIME_ORDERED IDKind = 1
+)
+
+var IDKind_name = map[int32]string{
+	0: "COUNTER",
+	1: "TIME_ORDERED",
+}
+var IDKind_value = map[string]int32{
+	"COUNTER":      0,
+	"TIME_ORDERED": 1,
+}
+
+func (x IDKind) String() string {
+	return proto.EnumName(IDKind_name, int32(x))
+}
+func (IDKind) EnumDescriptor() ([]byte, []int) { return fileDescriptorIdpb, []int{0} }
+
+type AllocIDRequest struct {
+	Header *pdpb.RequestHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+	Kind   IDKind              `protobuf:"varint,2,opt,name=kind,proto3,enum=idpb.IDKind" json:"kind,omitempty"`
+}
+
+func (m *AllocIDRequest) Reset()                    { *m = AllocIDRequest{} }
+func (m *AllocIDRequest) String() string            { return proto.CompactTextString(m) }
+func (*AllocIDRequest) ProtoMessage()               {}
+func (*AllocIDRequest) Descriptor() ([]byte, []int) { return fileDescriptorIdpb, []int{0} }
+
+func (m *AllocIDRequest) GetHeader() *pdpb.RequestHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *AllocIDRequest) GetKind() IDKind {
+	if m != nil {
+		return m.Kind
+	}
+	return IDKind_COUNTER
+}
+
+type AllocIDResponse struct {
+	Header *pdpb.ResponseHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+	Id     uint64               `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (m *AllocIDResponse) Reset()                    { *m = AllocIDResponse{} }
+func (m *AllocIDResponse) String() string            { return proto.CompactTextString(m) }
+func (*AllocIDResponse) ProtoMessage()               {}
+func (*AllocIDResponse) Descriptor() ([]byte, []int) { return fileDescriptorIdpb, []int{1} }
+
+func (m *AllocIDResponse) GetHeader() *pdpb.ResponseHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *AllocIDResponse) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*AllocIDRequest)(nil), "idpb.AllocIDRequest")
+	proto.RegisterType((*AllocIDResponse)(nil), "idpb.AllocIDResponse")
+	proto.RegisterEnum("idpb.IDKind", IDKind_name, IDKind_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for ID service
+
+type IDClient interface {
+	AllocID(ctx context.Context, in *AllocIDRequest, opts ...grpc.CallOption) (*AllocIDResponse, error)
+}
+
+type iDClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewIDClient(cc *grpc.ClientConn) IDClient {
+	return &iDClient{cc}
+}
+
+func (c *iDClient) AllocID(ctx context.Context, in *AllocIDRequest, opts ...grpc.CallOption) (*AllocIDResponse, error) {
+	out := new(AllocIDResponse)
+	err := grpc.Invoke(ctx, "/idpb.ID/AllocID", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for ID service
+
+type IDServer interface {
+	AllocID(context.Context, *AllocIDRequest) (*AllocIDResponse, error)
+}
+
+func RegisterIDServer(s *grpc.Server, srv IDServer) {
+	s.RegisterService(&_ID_serviceDesc, srv)
+}
+
+func _ID_AllocID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AllocIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IDServer).AllocID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idpb.ID/AllocID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IDServer).AllocID(ctx, req.(*AllocIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ID_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "idpb.ID",
+	HandlerType: (*IDServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AllocID",
+			Handler:    _ID_AllocID_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "idpb.proto",
+}
+
+func (m *AllocIDRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AllocIDRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintIdpb(dAtA, i, uint64(m.Header.Size()))
+		n1, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	if m.Kind != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintIdpb(dAtA, i, uint64(m.Kind))
+	}
+	return i, nil
+}
+
+func (m *AllocIDResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AllocIDResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintIdpb(dAtA, i, uint64(m.Header.Size()))
+		n2, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n2
+	}
+	if m.Id != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintIdpb(dAtA, i, uint64(m.Id))
+	}
+	return i, nil
+}
+
+func encodeVarintIdpb(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *AllocIDRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovIdpb(uint64(l))
+	}
+	if m.Kind != 0 {
+		n += 1 + sovIdpb(uint64(m.Kind))
+	}
+	return n
+}
+
+func (m *AllocIDResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovIdpb(uint64(l))
+	}
+	if m.Id != 0 {
+		n += 1 + sovIdpb(uint64(m.Id))
+	}
+	return n
+}
+
+func sovIdpb(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozIdpb(x uint64) (n int) {
+	return sovIdpb(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *AllocIDRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowIdpb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AllocIDRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AllocIDRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIdpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthIdpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.RequestHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Kind", wireType)
+			}
+			m.Kind = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIdpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Kind |= (IDKind(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipIdpb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthIdpb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AllocIDResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowIdpb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AllocIDResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AllocIDResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIdpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthIdpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.ResponseHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			m.Id = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIdpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Id |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipIdpb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthIdpb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipIdpb(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowIdpb
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowIdpb
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+			return iNdEx, nil
+		case 1:
+			iNdEx += 8
+			return iNdEx, nil
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowIdpb
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			iNdEx += length
+			if length < 0 {
+				return 0, ErrInvalidLengthIdpb
+			}
+			return iNdEx, nil
+		case 3:
+			for {
+				var innerWire uint64
+				var start int = iNdEx
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return 0, ErrIntOverflowIdpb
+					}
+					if iNdEx >= l {
+						return 0, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					innerWire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				innerWireType := int(innerWire & 0x7)
+				if innerWireType == 4 {
+					break
+				}
+				next, err := skipIdpb(dAtA[start:])
+				if err != nil {
+					return 0, err
+				}
+				iNdEx = start + next
+			}
+			return iNdEx, nil
+		case 4:
+			return iNdEx, nil
+		case 5:
+			iNdEx += 4
+			return iNdEx, nil
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+	}
+	panic("unreachable")
+}
+
+var (
+	ErrInvalidLengthIdpb = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowIdpb   = fmt.Errorf("proto: integer overflow")
+)
+
+func init() { proto.RegisterFile("idpb.proto", fileDescriptorIdpb) }
+
+var fileDescriptorIdpb = []byte{
+	// 250 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0xca, 0x4c, 0x29, 0x48,
+	0xd2, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x01, 0xb1, 0xa5, 0xb8, 0x0a, 0xe0, 0x22, 0x52,
+	0x22, 0xe9, 0xf9, 0xe9, 0xf9, 0x60, 0xa6, 0x3e, 0x88, 0x05, 0x11, 0x55, 0x8a, 0xe7, 0xe2, 0x73,
+	0xcc, 0xc9, 0xc9, 0x4f, 0xf6, 0x74, 0x09, 0x4a, 0x2d, 0x2c, 0x4d, 0x2d, 0x2e, 0x11, 0xd2, 0xe6,
+	0x62, 0xcb, 0x48, 0x4d, 0x4c, 0x49, 0x2d, 0x92, 0x60, 0x54, 0x60, 0xd4, 0xe0, 0x36, 0x12, 0xd6,
+	0x03, 0x1b, 0x02, 0x95, 0xf6, 0x00, 0x4b, 0x05, 0x41, 0x95, 0x08, 0x29, 0x70, 0xb1, 0x64, 0x67,
+	0xe6, 0xa5, 0x48, 0x30, 0x29, 0x30, 0x6a, 0xf0, 0x19, 0xf1, 0xe8, 0x81, 0x5d, 0xe0, 0xe9, 0xe2,
+	0x9d, 0x99, 0x97, 0x12, 0x04, 0x96, 0x51, 0xf2, 0xe7, 0xe2, 0x87, 0x5b, 0x50, 0x5c, 0x90, 0x9f,
+	0x57, 0x9c, 0x2a, 0xa4, 0x83, 0x66, 0x83, 0x08, 0xcc, 0x06, 0x88, 0x3c, 0x9a, 0x15, 0x7c, 0x5c,
+	0x4c, 0x99, 0x10, 0x0b, 0x58, 0x82, 0x98, 0x32, 0x53, 0xb4, 0xd4, 0xb9, 0xd8, 0x20, 0x16, 0x08,
+	0x71, 0x73, 0xb1, 0x3b, 0xfb, 0x87, 0xfa, 0x85, 0xb8, 0x06, 0x09, 0x30, 0x08, 0x09, 0x70, 0xf1,
+	0x84, 0x78, 0xfa, 0xba, 0xc6, 0xfb, 0x07, 0xb9, 0xb8, 0x06, 0xb9, 0xba, 0x08, 0x30, 0x1a, 0xd9,
+	0x70, 0x31, 0x79, 0xba, 0x08, 0x99, 0x71, 0xb1, 0x43, 0xed, 0x17, 0x12, 0x81, 0x38, 0x0f, 0xd5,
+	0xbf, 0x52, 0xa2, 0x68, 0xa2, 0x10, 0x47, 0x38, 0x09, 0x9c, 0x78, 0x24, 0xc7, 0x78, 0xe1, 0x91,
+	0x1c, 0xe3, 0x83, 0x47, 0x72, 0x8c, 0x33, 0x1e, 0xcb, 0x31, 0x24, 0xb1, 0x81, 0x43, 0xcc, 0x18,
+	0x30, 0x00, 0x84, 0xf4, 0xdf, 0xe2, 0x67, 0x01, 0x00, 0x00,
+}
@@ -14,6 +14,7 @@
 package integration
 
 import (
+	"context"
 	"time"
 
 	. "github.com/pingcap/check"
@@ -62,7 +63,7 @@ func (s *integrationTestSuite) TestRegionSyncer(c *C) {
 		regions = append(regions, core.NewRegionInfo(r, r.Peers[0]))
 	}
 	for _, region := range regions {
-		err = rc.HandleRegionHeartbeat(region)
+		err = rc.HandleRegionHeartbeat(context.Background(), region)
 		c.Assert(err, IsNil)
 	}
 	// ensure flush to region kv
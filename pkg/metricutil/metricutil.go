@@ -14,22 +14,54 @@
 package metricutil
 
 import (
-	"time"
 	"unicode"
 
 	"github.com/pingcap/pd/pkg/typeutil"
-	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/push"
-	log "github.com/sirupsen/logrus"
+	"github.com/pkg/errors"
 )
 
-const zeroDuration = time.Duration(0)
+// The types of the endpoint to push the metrics to.
+const (
+	// PushTypePushgateway pushes the metrics to a Prometheus Pushgateway.
+	PushTypePushgateway = "pushgateway"
+	// PushTypeRemoteWrite writes the metrics to a Prometheus remote write
+	// endpoint.
+	PushTypeRemoteWrite = "remote-write"
+)
 
 // MetricConfig is the metric configuration.
 type MetricConfig struct {
-	PushJob      string            `toml:"job" json:"job"`
+	PushJob string `toml:"job" json:"job"`
+	// PushAddress is the address of the Pushgateway, or the URL of the remote
+	// write endpoint.
 	PushAddress  string            `toml:"address" json:"address"`
 	PushInterval typeutil.Duration `toml:"interval" json:"interval"`
+	// PushType is "pushgateway" or "remote-write".
+	PushType string `toml:"type" json:"type"`
+	// CAPath, CertPath and KeyPath are the TLS files to connect to the push
+	// endpoint with https.
+	CAPath   string `toml:"cacert-path" json:"cacert-path"`
+	CertPath string `toml:"cert-path" json:"cert-path"`
+	KeyPath  string `toml:"key-path" json:"key-path"`
+}
+
+// Adjust fills the default values and validates the config.
+func (c *MetricConfig) Adjust() error {
+	if c.PushType == "" {
+		c.PushType = PushTypePushgateway
+	}
+	if c.PushType != PushTypePushgateway && c.PushType != PushTypeRemoteWrite {
+		return errors.Errorf("unknown metric push type %q", c.PushType)
+	}
+	if c.PushInterval.Duration < 0 {
+		return errors.Errorf("metric push interval should not be negative, got %v", c.PushInterval.Duration)
+	}
+	return nil
+}
+
+// IsPushEnabled returns true if the metrics are pushed.
+func (c *MetricConfig) IsPushEnabled() bool {
+	return c.PushInterval.Duration > 0 && c.PushAddress != ""
 }
 
 func runesHasLowerNeighborAt(runes []rune, idx int) bool {
@@ -56,32 +88,3 @@ func camelCaseToSnakeCase(str string) string {
 
 	return string(ret)
 }
-
-// prometheusPushClient pushs metrics to Prometheus Pushgateway.
-func prometheusPushClient(job, addr string, interval time.Duration) {
-	for {
-		err := push.FromGatherer(
-			job, push.HostnameGroupingKey(),
-			addr,
-			prometheus.DefaultGatherer,
-		)
-		if err != nil {
-			log.Errorf("could not push metrics to Prometheus Pushgateway: %v", err)
-		}
-
-		time.Sleep(interval)
-	}
-}
-
-// Push metircs in background.
-func Push(cfg *MetricConfig) {
-	if cfg.PushInterval.Duration == zeroDuration || len(cfg.PushAddress) == 0 {
-		log.Info("disable Prometheus push client")
-		return
-	}
-
-	log.Info("start Prometheus push client")
-
-	interval := cfg.PushInterval.Duration
-	go prometheusPushClient(cfg.PushJob, cfg.PushAddress, interval)
-}
@@ -14,11 +14,19 @@
 package metricutil
 
 import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/golang/snappy"
 	. "github.com/pingcap/check"
 	"github.com/pingcap/pd/pkg/typeutil"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
 )
 
 func Test(t *testing.T) {
@@ -62,25 +70,124 @@ func (s *testMetricsSuite) TestCamelCaseToSnakeCase(c *C) {
 	}
 }
 
-func (s *testMetricsSuite) TestCoverage(c *C) {
-	cfgs := []*MetricConfig{
-		{
-			PushJob:     "j1",
-			PushAddress: "127.0.0.1:9091",
-			PushInterval: typeutil.Duration{
-				Duration: time.Hour,
-			},
-		},
-		{
-			PushJob:     "j2",
-			PushAddress: "127.0.0.1:9091",
-			PushInterval: typeutil.Duration{
-				Duration: zeroDuration,
-			},
-		},
+func (s *testMetricsSuite) TestAdjust(c *C) {
+	cfg := &MetricConfig{}
+	c.Assert(cfg.Adjust(), IsNil)
+	c.Assert(cfg.PushType, Equals, PushTypePushgateway)
+	c.Assert(cfg.IsPushEnabled(), IsFalse)
+
+	cfg.PushAddress = "127.0.0.1:9091"
+	cfg.PushInterval = typeutil.NewDuration(15 * time.Second)
+	c.Assert(cfg.IsPushEnabled(), IsTrue)
+
+	cfg.PushType = "unknown"
+	c.Assert(cfg.Adjust(), NotNil)
+}
+
+func (s *testMetricsSuite) newPusher(c *C, pushType, addr string) *Pusher {
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_counter", Help: "test"}, []string{"type"})
+	counter.WithLabelValues("a").Add(3)
+	histogram := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "test_histogram", Help: "test", Buckets: []float64{1}})
+	histogram.Observe(0.5)
+	registry.MustRegister(counter, histogram)
+
+	cfg := &MetricConfig{PushJob: "pd", PushAddress: addr, PushType: pushType}
+	c.Assert(cfg.Adjust(), IsNil)
+	p, err := NewPusher(cfg)
+	c.Assert(err, IsNil)
+	p.gatherer = registry
+	return p
+}
+
+func (s *testMetricsSuite) TestPushToGateway(c *C) {
+	var path string
+	families := make(map[string]bool)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Assert(r.Method, Equals, http.MethodPut)
+		path = r.URL.Path
+		dec := expfmt.NewDecoder(r.Body, expfmt.FmtProtoDelim)
+		for {
+			var mf dto.MetricFamily
+			if dec.Decode(&mf) != nil {
+				break
+			}
+			families[mf.GetName()] = true
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	p := s.newPusher(c, "", strings.TrimPrefix(server.URL, "http://"))
+	c.Assert(p.Push(), IsNil)
+	c.Assert(path, Equals, "/metrics/job/pd/instance/"+p.instance)
+	c.Assert(families["test_counter"], IsTrue)
+	c.Assert(families["test_histogram"], IsTrue)
+}
+
+func (s *testMetricsSuite) TestRemoteWrite(c *C) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Assert(r.Method, Equals, http.MethodPost)
+		c.Assert(r.Header.Get("Content-Encoding"), Equals, "snappy")
+		c.Assert(r.Header.Get("X-Prometheus-Remote-Write-Version"), Equals, remoteWriteVersion)
+		data, err := ioutil.ReadAll(r.Body)
+		c.Assert(err, IsNil)
+		body, err = snappy.Decode(nil, data)
+		c.Assert(err, IsNil)
+	}))
+	defer server.Close()
+
+	p := s.newPusher(c, PushTypeRemoteWrite, server.URL+"/api/v1/write")
+	c.Assert(p.Push(), IsNil)
+	c.Assert(strings.Contains(string(body), "test_histogram_bucket"), IsTrue)
+
+	// The status of the endpoint is checked.
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	c.Assert(p.Push(), NotNil)
+}
+
+func (s *testMetricsSuite) TestToTimeSeries(c *C) {
+	p := s.newPusher(c, PushTypeRemoteWrite, "")
+	mfs, err := p.gatherer.Gather()
+	c.Assert(err, IsNil)
+	series := toTimeSeries(mfs, "pd", "host", 1000)
+
+	names := make(map[string]float64)
+	for _, ts := range series {
+		var name, le string
+		for _, l := range ts.labels {
+			switch l.name {
+			case "__name__":
+				name = l.value
+			case "le":
+				le = l.value
+			}
+		}
+		c.Assert(ts.samples, HasLen, 1)
+		c.Assert(ts.samples[0].timestamp, Equals, int64(1000))
+		names[name+le] = ts.samples[0].value
 	}
+	c.Assert(names, DeepEquals, map[string]float64{
+		"test_counter":              3,
+		"test_histogram_bucket1":    1,
+		"test_histogram_bucket+Inf": 1,
+		"test_histogram_sum":        0.5,
+		"test_histogram_count":      1,
+	})
+}
 
-	for _, cfg := range cfgs {
-		Push(cfg)
+func (s *testMetricsSuite) TestEncodeWriteRequest(c *C) {
+	series := []timeSeries{{
+		labels:  []label{{"a", "b"}},
+		samples: []sample{{value: 1, timestamp: 2}},
+	}}
+	expected := []byte{
+		0x0a, 0x15, // timeseries
+		0x0a, 0x06, 0x0a, 0x01, 'a', 0x12, 0x01, 'b', // label
+		0x12, 0x0b, 0x09, 0, 0, 0, 0, 0, 0, 0xf0, 0x3f, 0x10, 0x02, // sample
 	}
+	c.Assert(encodeWriteRequest(series), DeepEquals, expected)
 }
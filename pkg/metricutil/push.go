@@ -0,0 +1,270 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricutil
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/coreos/etcd/pkg/transport"
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+const (
+	pushTimeout = 10 * time.Second
+	// remoteWriteVersion is the version of the remote write protocol.
+	remoteWriteVersion = "0.1.0"
+)
+
+// Pusher pushes the metrics to a Prometheus Pushgateway or a remote write
+// endpoint.
+type Pusher struct {
+	cfg      *MetricConfig
+	client   *http.Client
+	instance string
+	gatherer prometheus.Gatherer
+}
+
+// NewPusher creates a Pusher with the config.
+func NewPusher(cfg *MetricConfig) (*Pusher, error) {
+	client := &http.Client{Timeout: pushTimeout}
+	if cfg.CAPath != "" || cfg.CertPath != "" || cfg.KeyPath != "" {
+		tlsInfo := transport.TLSInfo{
+			CertFile:      cfg.CertPath,
+			KeyFile:       cfg.KeyPath,
+			TrustedCAFile: cfg.CAPath,
+		}
+		tlsConfig, err := tlsInfo.ClientConfig()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		client.Transport = &http.Transport{TLSClientConfig: tlsConfig}
+	}
+	return &Pusher{
+		cfg:      cfg,
+		client:   client,
+		instance: instanceName(),
+		gatherer: prometheus.DefaultGatherer,
+	}, nil
+}
+
+// Push pushes the current metrics once.
+func (p *Pusher) Push() error {
+	mfs, err := p.gatherer.Gather()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if p.cfg.PushType == PushTypeRemoteWrite {
+		return p.remoteWrite(mfs)
+	}
+	return p.pushToGateway(mfs)
+}
+
+// pushToGateway replaces the metrics of the job and the instance in the
+// Pushgateway.
+func (p *Pusher) pushToGateway(mfs []*dto.MetricFamily) error {
+	buf := &bytes.Buffer{}
+	enc := expfmt.NewEncoder(buf, expfmt.FmtProtoDelim)
+	for _, mf := range mfs {
+		if err := enc.Encode(mf); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	pushURL := fmt.Sprintf("%s/metrics/job/%s/instance/%s",
+		strings.TrimSuffix(withScheme(p.cfg.PushAddress), "/"),
+		url.PathEscape(p.cfg.PushJob), url.PathEscape(p.instance))
+	req, err := http.NewRequest(http.MethodPut, pushURL, buf)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", string(expfmt.FmtProtoDelim))
+	return p.do(req)
+}
+
+// remoteWrite writes the metrics to the remote write endpoint as samples of
+// the current time.
+func (p *Pusher) remoteWrite(mfs []*dto.MetricFamily) error {
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	series := toTimeSeries(mfs, p.cfg.PushJob, p.instance, now)
+	body := snappy.Encode(nil, encodeWriteRequest(series))
+	req, err := http.NewRequest(http.MethodPost, withScheme(p.cfg.PushAddress), bytes.NewReader(body))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", remoteWriteVersion)
+	return p.do(req)
+}
+
+func (p *Pusher) do(req *http.Request) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return errors.Errorf("push metrics to %s failed, status %d: %s", req.URL, resp.StatusCode, msg)
+	}
+	return nil
+}
+
+func withScheme(addr string) string {
+	if strings.HasPrefix(addr, "http://") || strings.HasPrefix(addr, "https://") {
+		return addr
+	}
+	return "http://" + addr
+}
+
+func instanceName() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "unknown"
+	}
+	return hostname
+}
+
+type label struct {
+	name, value string
+}
+
+type sample struct {
+	value     float64
+	timestamp int64
+}
+
+type timeSeries struct {
+	labels  []label
+	samples []sample
+}
+
+// toTimeSeries flattens the metric families to time series in the way
+// Prometheus scrapes them, summaries and histograms are split into the
+// quantiles or buckets, the sum and the count.
+func toTimeSeries(mfs []*dto.MetricFamily, job, instance string, timestamp int64) []timeSeries {
+	var series []timeSeries
+	for _, mf := range mfs {
+		name := mf.GetName()
+		for _, m := range mf.GetMetric() {
+			add := func(suffix string, value float64, extra ...label) {
+				labels := make([]label, 0, len(m.GetLabel())+len(extra)+3)
+				labels = append(labels, label{"__name__", name + suffix}, label{"job", job}, label{"instance", instance})
+				for _, lp := range m.GetLabel() {
+					labels = append(labels, label{lp.GetName(), lp.GetValue()})
+				}
+				labels = append(labels, extra...)
+				sort.Slice(labels, func(i, j int) bool { return labels[i].name < labels[j].name })
+				series = append(series, timeSeries{
+					labels:  labels,
+					samples: []sample{{value: value, timestamp: timestamp}},
+				})
+			}
+			switch mf.GetType() {
+			case dto.MetricType_COUNTER:
+				add("", m.GetCounter().GetValue())
+			case dto.MetricType_GAUGE:
+				add("", m.GetGauge().GetValue())
+			case dto.MetricType_UNTYPED:
+				add("", m.GetUntyped().GetValue())
+			case dto.MetricType_SUMMARY:
+				s := m.GetSummary()
+				for _, q := range s.GetQuantile() {
+					add("", q.GetValue(), label{"quantile", formatFloat(q.GetQuantile())})
+				}
+				add("_sum", s.GetSampleSum())
+				add("_count", float64(s.GetSampleCount()))
+			case dto.MetricType_HISTOGRAM:
+				h := m.GetHistogram()
+				for _, b := range h.GetBucket() {
+					add("_bucket", float64(b.GetCumulativeCount()), label{"le", formatFloat(b.GetUpperBound())})
+				}
+				add("_bucket", float64(h.GetSampleCount()), label{"le", "+Inf"})
+				add("_sum", h.GetSampleSum())
+				add("_count", float64(h.GetSampleCount()))
+			}
+		}
+	}
+	return series
+}
+
+func formatFloat(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+Inf"
+	}
+	return fmt.Sprint(f)
+}
+
+// encodeWriteRequest encodes the time series as a remote write WriteRequest
+// protobuf message:
+//
+//	message WriteRequest { repeated TimeSeries timeseries = 1; }
+//	message TimeSeries { repeated Label labels = 1; repeated Sample samples = 2; }
+//	message Label { string name = 1; string value = 2; }
+//	message Sample { double value = 1; int64 timestamp = 2; }
+func encodeWriteRequest(series []timeSeries) []byte {
+	var req []byte
+	for _, ts := range series {
+		var tsBuf []byte
+		for _, l := range ts.labels {
+			var lBuf []byte
+			lBuf = appendBytesField(lBuf, 1, []byte(l.name))
+			lBuf = appendBytesField(lBuf, 2, []byte(l.value))
+			tsBuf = appendBytesField(tsBuf, 1, lBuf)
+		}
+		for _, s := range ts.samples {
+			var sBuf []byte
+			sBuf = appendVarint(sBuf, 1<<3|1)
+			sBuf = appendFixed64(sBuf, math.Float64bits(s.value))
+			sBuf = appendVarint(sBuf, 2<<3)
+			sBuf = appendVarint(sBuf, uint64(s.timestamp))
+			tsBuf = appendBytesField(tsBuf, 2, sBuf)
+		}
+		req = appendBytesField(req, 1, tsBuf)
+	}
+	return req
+}
+
+// appendBytesField appends a length-delimited field.
+func appendBytesField(buf []byte, field uint64, data []byte) []byte {
+	buf = appendVarint(buf, field<<3|2)
+	buf = appendVarint(buf, uint64(len(data)))
+	return append(buf, data...)
+}
+
+func appendVarint(buf []byte, v uint64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], v)
+	return append(buf, b[:n]...)
+}
+
+func appendFixed64(buf []byte, v uint64) []byte {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	return append(buf, b[:]...)
+}
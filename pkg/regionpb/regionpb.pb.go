@@ -0,0 +1,1071 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: regionpb.proto
+
+/*
+Package regionpb is a generated protocol buffer package.
+
+It is generated from these files:
+
+	regionpb.proto
+
+It has these top-level messages:
+
+	GetRegionRequest
+	GetRegionByIDRequest
+	GetRegionResponse
+*/
+package regionpb
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	proto "github.com/golang/protobuf/proto"
+
+	metapb "github.com/pingcap/kvproto/pkg/metapb"
+
+	pdpb "github.com/pingcap/kvproto/pkg/pdpb"
+
+	_ "github.com/gogo/protobuf/gogoproto"
+
+	context "golang.org/x/net/context"
+
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type GetRegionRequest struct {
+	Header    *pdpb.RequestHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+	RegionKey []byte              `protobuf:"bytes,2,opt,name=region_key,json=regionKey,proto3" json:"region_key,omitempty"`
+}
+
+func (m *GetRegionRequest) Reset()                    { *m = GetRegionRequest{} }
+func (m *GetRegionRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetRegionRequest) ProtoMessage()               {}
+func (*GetRegionRequest) Descriptor() ([]byte, []int) { return fileDescriptorRegionpb, []int{0} }
+
+func (m *GetRegionRequest) GetHeader() *pdpb.RequestHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *GetRegionRequest) GetRegionKey() []byte {
+	if m != nil {
+		return m.RegionKey
+	}
+	return nil
+}
+
+type GetRegionByIDRequest struct {
+	Header   *pdpb.RequestHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+	RegionId uint64              `protobuf:"varint,2,opt,name=region_id,json=regionId,proto3" json:"region_id,omitempty"`
+}
+
+func (m *GetRegionByIDRequest) Reset()                    { *m = GetRegionByIDRequest{} }
+func (m *GetRegionByIDRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetRegionByIDRequest) ProtoMessage()               {}
+func (*GetRegionByIDRequest) Descriptor() ([]byte, []int) { return fileDescriptorRegionpb, []int{1} }
+
+func (m *GetRegionByIDRequest) GetHeader() *pdpb.RequestHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *GetRegionByIDRequest) GetRegionId() uint64 {
+	if m != nil {
+		return m.RegionId
+	}
+	return 0
+}
+
+// GetRegionResponse returns the region as reported by the last heartbeat of
+// its leader. The region is not set if it is not found.
+type GetRegionResponse struct {
+	Header       *pdpb.ResponseHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+	Region       *metapb.Region       `protobuf:"bytes,2,opt,name=region" json:"region,omitempty"`
+	Leader       *metapb.Peer         `protobuf:"bytes,3,opt,name=leader" json:"leader,omitempty"`
+	DownPeers    []*pdpb.PeerStats    `protobuf:"bytes,4,rep,name=down_peers,json=downPeers" json:"down_peers,omitempty"`
+	PendingPeers []*metapb.Peer       `protobuf:"bytes,5,rep,name=pending_peers,json=pendingPeers" json:"pending_peers,omitempty"`
+}
+
+func (m *GetRegionResponse) Reset()                    { *m = GetRegionResponse{} }
+func (m *GetRegionResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetRegionResponse) ProtoMessage()               {}
+func (*GetRegionResponse) Descriptor() ([]byte, []int) { return fileDescriptorRegionpb, []int{2} }
+
+func (m *GetRegionResponse) GetHeader() *pdpb.ResponseHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *GetRegionResponse) GetRegion() *metapb.Region {
+	if m != nil {
+		return m.Region
+	}
+	return nil
+}
+
+func (m *GetRegionResponse) GetLeader() *metapb.Peer {
+	if m != nil {
+		return m.Leader
+	}
+	return nil
+}
+
+func (m *GetRegionResponse) GetDownPeers() []*pdpb.PeerStats {
+	if m != nil {
+		return m.DownPeers
+	}
+	return nil
+}
+
+func (m *GetRegionResponse) GetPendingPeers() []*metapb.Peer {
+	if m != nil {
+		return m.PendingPeers
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*GetRegionRequest)(nil), "regionpb.GetRegionRequest")
+	proto.RegisterType((*GetRegionByIDRequest)(nil), "regionpb.GetRegionByIDRequest")
+	proto.RegisterType((*GetRegionResponse)(nil), "regionpb.GetRegionResponse")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for Region service
+
+type RegionClient interface {
+	GetRegion(ctx context.Context, in *GetRegionRequest, opts ...grpc.CallOption) (*GetRegionResponse, error)
+	// GetPrevRegion gets the region adjacent to the left of the region
+	// containing the key.
+	GetPrevRegion(ctx context.Context, in *GetRegionRequest, opts ...grpc.CallOption) (*GetRegionResponse, error)
+	GetRegionByID(ctx context.Context, in *GetRegionByIDRequest, opts ...grpc.CallOption) (*GetRegionResponse, error)
+}
+
+type regionClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewRegionClient(cc *grpc.ClientConn) RegionClient {
+	return &regionClient{cc}
+}
+
+func (c *regionClient) GetRegion(ctx context.Context, in *GetRegionRequest, opts ...grpc.CallOption) (*GetRegionResponse, error) {
+	out := new(GetRegionResponse)
+	err := grpc.Invoke(ctx, "/regionpb.Region/GetRegion", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *regionClient) GetPrevRegion(ctx context.Context, in *GetRegionRequest, opts ...grpc.CallOption) (*GetRegionResponse, error) {
+	out := new(GetRegionResponse)
+	err := grpc.Invoke(ctx, "/regionpb.Region/GetPrevRegion", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *regionClient) GetRegionByID(ctx context.Context, in *GetRegionByIDRequest, opts ...grpc.CallOption) (*GetRegionResponse, error) {
+	out := new(GetRegionResponse)
+	err := grpc.Invoke(ctx, "/regionpb.Region/GetRegionByID", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Region service
+
+type RegionServer interface {
+	GetRegion(context.Context, *GetRegionRequest) (*GetRegionResponse, error)
+	// GetPrevRegion gets the region adjacent to the left of the region
+	// containing the key.
+	GetPrevRegion(context.Context, *GetRegionRequest) (*GetRegionResponse, error)
+	GetRegionByID(context.Context, *GetRegionByIDRequest) (*GetRegionResponse, error)
+}
+
+func RegisterRegionServer(s *grpc.Server, srv RegionServer) {
+	s.RegisterService(&_Region_serviceDesc, srv)
+}
+
+func _Region_GetRegion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRegionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegionServer).GetRegion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/regionpb.Region/GetRegion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegionServer).GetRegion(ctx, req.(*GetRegionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Region_GetPrevRegion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRegionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegionServer).GetPrevRegion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/regionpb.Region/GetPrevRegion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegionServer).GetPrevRegion(ctx, req.(*GetRegionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Region_GetRegionByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRegionByIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegionServer).GetRegionByID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/regionpb.Region/GetRegionByID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegionServer).GetRegionByID(ctx, req.(*GetRegionByIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Region_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "regionpb.Region",
+	HandlerType: (*RegionServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetRegion",
+			Handler:    _Region_GetRegion_Handler,
+		},
+		{
+			MethodName: "GetPrevRegion",
+			Handler:    _Region_GetPrevRegion_Handler,
+		},
+		{
+			MethodName: "GetRegionByID",
+			Handler:    _Region_GetRegionByID_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "regionpb.proto",
+}
+
+func (m *GetRegionRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetRegionRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintRegionpb(dAtA, i, uint64(m.Header.Size()))
+		n1, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	if len(m.RegionKey) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintRegionpb(dAtA, i, uint64(len(m.RegionKey)))
+		i += copy(dAtA[i:], m.RegionKey)
+	}
+	return i, nil
+}
+
+func (m *GetRegionByIDRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetRegionByIDRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintRegionpb(dAtA, i, uint64(m.Header.Size()))
+		n2, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n2
+	}
+	if m.RegionId != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintRegionpb(dAtA, i, uint64(m.RegionId))
+	}
+	return i, nil
+}
+
+func (m *GetRegionResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetRegionResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintRegionpb(dAtA, i, uint64(m.Header.Size()))
+		n3, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n3
+	}
+	if m.Region != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintRegionpb(dAtA, i, uint64(m.Region.Size()))
+		n4, err := m.Region.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n4
+	}
+	if m.Leader != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintRegionpb(dAtA, i, uint64(m.Leader.Size()))
+		n5, err := m.Leader.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n5
+	}
+	if len(m.DownPeers) > 0 {
+		for _, msg := range m.DownPeers {
+			dAtA[i] = 0x22
+			i++
+			i = encodeVarintRegionpb(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.PendingPeers) > 0 {
+		for _, msg := range m.PendingPeers {
+			dAtA[i] = 0x2a
+			i++
+			i = encodeVarintRegionpb(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func encodeVarintRegionpb(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *GetRegionRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovRegionpb(uint64(l))
+	}
+	l = len(m.RegionKey)
+	if l > 0 {
+		n += 1 + l + sovRegionpb(uint64(l))
+	}
+	return n
+}
+
+func (m *GetRegionByIDRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovRegionpb(uint64(l))
+	}
+	if m.RegionId != 0 {
+		n += 1 + sovRegionpb(uint64(m.RegionId))
+	}
+	return n
+}
+
+func (m *GetRegionResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovRegionpb(uint64(l))
+	}
+	if m.Region != nil {
+		l = m.Region.Size()
+		n += 1 + l + sovRegionpb(uint64(l))
+	}
+	if m.Leader != nil {
+		l = m.Leader.Size()
+		n += 1 + l + sovRegionpb(uint64(l))
+	}
+	if len(m.DownPeers) > 0 {
+		for _, e := range m.DownPeers {
+			l = e.Size()
+			n += 1 + l + sovRegionpb(uint64(l))
+		}
+	}
+	if len(m.PendingPeers) > 0 {
+		for _, e := range m.PendingPeers {
+			l = e.Size()
+			n += 1 + l + sovRegionpb(uint64(l))
+		}
+	}
+	return n
+}
+
+func sovRegionpb(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozRegionpb(x uint64) (n int) {
+	return sovRegionpb(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *GetRegionRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRegionpb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetRegionRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetRegionRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRegionpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRegionpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.RequestHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RegionKey", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRegionpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRegionpb
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RegionKey = append(m.RegionKey[:0], dAtA[iNdEx:postIndex]...)
+			if m.RegionKey == nil {
+				m.RegionKey = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRegionpb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRegionpb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetRegionByIDRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRegionpb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetRegionByIDRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetRegionByIDRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRegionpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRegionpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.RequestHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RegionId", wireType)
+			}
+			m.RegionId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRegionpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RegionId |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRegionpb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRegionpb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetRegionResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRegionpb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetRegionResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetRegionResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRegionpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRegionpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.ResponseHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Region", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRegionpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRegionpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Region == nil {
+				m.Region = &metapb.Region{}
+			}
+			if err := m.Region.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Leader", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRegionpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRegionpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Leader == nil {
+				m.Leader = &metapb.Peer{}
+			}
+			if err := m.Leader.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DownPeers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRegionpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRegionpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DownPeers = append(m.DownPeers, &pdpb.PeerStats{})
+			if err := m.DownPeers[len(m.DownPeers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PendingPeers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRegionpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRegionpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PendingPeers = append(m.PendingPeers, &metapb.Peer{})
+			if err := m.PendingPeers[len(m.PendingPeers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRegionpb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRegionpb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipRegionpb(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowRegionpb
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowRegionpb
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+			return iNdEx, nil
+		case 1:
+			iNdEx += 8
+			return iNdEx, nil
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowRegionpb
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			iNdEx += length
+			if length < 0 {
+				return 0, ErrInvalidLengthRegionpb
+			}
+			return iNdEx, nil
+		case 3:
+			for {
+				var innerWire uint64
+				var start int = iNdEx
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return 0, ErrIntOverflowRegionpb
+					}
+					if iNdEx >= l {
+						return 0, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					innerWire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				innerWireType := int(innerWire & 0x7)
+				if innerWireType == 4 {
+					break
+				}
+				next, err := skipRegionpb(dAtA[start:])
+				if err != nil {
+					return 0, err
+				}
+				iNdEx = start + next
+			}
+			return iNdEx, nil
+		case 4:
+			return iNdEx, nil
+		case 5:
+			iNdEx += 4
+			return iNdEx, nil
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+	}
+	panic("unreachable")
+}
+
+var (
+	ErrInvalidLengthRegionpb = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowRegionpb   = fmt.Errorf("proto: integer overflow")
+)
+
+func init() { proto.RegisterFile("regionpb.proto", fileDescriptorRegionpb) }
+
+var fileDescriptorRegionpb = []byte{
+	// 359 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x92, 0x5f, 0x4a, 0xc3, 0x40,
+	0x10, 0xc6, 0x5d, 0x5b, 0x83, 0x9d, 0xa6, 0xb5, 0xae, 0x7d, 0x28, 0x29, 0x86, 0x50, 0x44, 0x0a,
+	0x4a, 0xc4, 0x78, 0x83, 0x52, 0x68, 0x8b, 0x3e, 0x94, 0xf8, 0x6e, 0x6d, 0xc9, 0x10, 0x8b, 0x9a,
+	0x8d, 0xc9, 0xaa, 0xf4, 0x26, 0x1e, 0xc9, 0x47, 0x8f, 0x20, 0x15, 0x3c, 0x80, 0x27, 0x90, 0xfd,
+	0x93, 0x50, 0x62, 0xf1, 0x41, 0xdf, 0x66, 0xe7, 0xfb, 0xe6, 0x37, 0x3b, 0xbb, 0x03, 0xf5, 0x04,
+	0xc3, 0x39, 0x8b, 0xe2, 0x99, 0x1b, 0x27, 0x8c, 0x33, 0xba, 0x9d, 0x9d, 0x2d, 0xf3, 0x1e, 0xf9,
+	0x34, 0xcb, 0x5b, 0x10, 0x07, 0x79, 0xdc, 0x0c, 0x59, 0xc8, 0x64, 0x78, 0x22, 0x22, 0x95, 0xed,
+	0x5c, 0x41, 0x63, 0x80, 0xdc, 0x97, 0xe5, 0x3e, 0x3e, 0x3c, 0x62, 0xca, 0xe9, 0x11, 0x18, 0x37,
+	0x38, 0x0d, 0x30, 0x69, 0x11, 0x87, 0x74, 0xab, 0xde, 0x9e, 0x2b, 0x31, 0x5a, 0x1e, 0x4a, 0xc9,
+	0xd7, 0x16, 0xba, 0x0f, 0xa0, 0x9a, 0x4f, 0x6e, 0x71, 0xd1, 0xda, 0x74, 0x48, 0xd7, 0xf4, 0x2b,
+	0x2a, 0x73, 0x8e, 0x8b, 0xce, 0x35, 0x34, 0x73, 0x7e, 0x6f, 0x31, 0xea, 0xff, 0xa9, 0x47, 0x1b,
+	0x34, 0x71, 0x32, 0x0f, 0x64, 0x8b, 0xb2, 0xaf, 0x27, 0x1e, 0x05, 0x9d, 0x2f, 0x02, 0xbb, 0x2b,
+	0x23, 0xa4, 0x31, 0x8b, 0x52, 0xa4, 0xc7, 0x05, 0x7e, 0x33, 0xe3, 0x2b, 0xbd, 0xd0, 0xe0, 0x10,
+	0x0c, 0xc5, 0x93, 0xf4, 0xaa, 0x57, 0x77, 0xf5, 0x33, 0x6a, 0xaa, 0x56, 0xe9, 0x01, 0x18, 0x77,
+	0x8a, 0x5a, 0x92, 0x3e, 0x33, 0xf3, 0x8d, 0x51, 0xd0, 0x94, 0x46, 0x5d, 0x80, 0x80, 0x3d, 0x47,
+	0x93, 0x18, 0x31, 0x49, 0x5b, 0x65, 0xa7, 0xd4, 0xad, 0x7a, 0x3b, 0xaa, 0xbf, 0xf0, 0x5d, 0xf2,
+	0x29, 0x4f, 0xfd, 0x8a, 0xb0, 0x88, 0x63, 0x4a, 0x4f, 0xa1, 0x16, 0x63, 0x14, 0xcc, 0xa3, 0x50,
+	0x97, 0x6c, 0x39, 0xa5, 0x1f, 0x70, 0x53, 0x5b, 0x64, 0x89, 0xf7, 0x49, 0xc0, 0x50, 0x77, 0xa3,
+	0x7d, 0xa8, 0xe4, 0xe3, 0x53, 0xcb, 0xcd, 0x37, 0xa3, 0xf8, 0xad, 0x56, 0x7b, 0xad, 0xa6, 0xdf,
+	0x6b, 0x08, 0xb5, 0x01, 0xf2, 0x71, 0x82, 0x4f, 0xff, 0x25, 0x5d, 0x40, 0x2d, 0x4f, 0x8a, 0x1f,
+	0xa7, 0xf6, 0x1a, 0xf7, 0xca, 0x2a, 0xfc, 0x4a, 0xeb, 0x35, 0x5e, 0x97, 0x36, 0x79, 0x5b, 0xda,
+	0xe4, 0x7d, 0x69, 0x93, 0x97, 0x0f, 0x7b, 0x63, 0x66, 0xc8, 0xc5, 0x3d, 0xfb, 0x1e, 0x00, 0x58,
+	0xd6, 0x1f, 0xe9, 0x04, 0x03, 0x00, 0x00,
+}
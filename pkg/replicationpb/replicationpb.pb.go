@@ -0,0 +1,1090 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: replicationpb.proto
+
+/*
+Package replicationpb is a generated protocol buffer package.
+
+It is generated from these files:
+
+	replicationpb.proto
+
+It has these top-level messages:
+
+	ReplicationStatus
+	DRAutoSync
+	GetReplicationStatusRequest
+	GetReplicationStatusResponse
+*/
+package replicationpb
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	proto "github.com/golang/protobuf/proto"
+
+	pdpb "github.com/pingcap/kvproto/pkg/pdpb"
+
+	_ "github.com/gogo/protobuf/gogoproto"
+
+	context "golang.org/x/net/context"
+
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type ReplicationMode int32
+
+const (
+	// MAJORITY requires a majority of replicas to commit.
+	ReplicationMode_MAJORITY ReplicationMode = 0
+	// DR_AUTO_SYNC requires the DR zone to commit when it is healthy, and
+	// falls back to the majority otherwise.
+	ReplicationMode_DR_AUTO_SYNC ReplicationMode = 1
+)
+
+var ReplicationMode_name = map[int32]string{
+	0: "MAJORITY",
+	1: "DR_AUTO_SYNC",
+}
+var ReplicationMode_value = map[string]int32{
+	"MAJORITY":     0,
+	"DR_AUTO_SYNC": 1,
+}
+
+func (x ReplicationMode) String() string {
+	return proto.EnumName(ReplicationMode_name, int32(x))
+}
+func (ReplicationMode) EnumDescriptor() ([]byte, []int) { return fileDescriptorReplicationpb, []int{0} }
+
+type DRAutoSyncState int32
+
+const (
+	// SYNC requires logs to be committed in both zones.
+	DRAutoSyncState_SYNC DRAutoSyncState = 0
+	// ASYNC only requires logs to be committed in the primary zone.
+	DRAutoSyncState_ASYNC DRAutoSyncState = 1
+	// SYNC_RECOVER is the state when the DR zone recovers, logs are
+	// replicated to it but not required to be committed in it.
+	DRAutoSyncState_SYNC_RECOVER DRAutoSyncState = 2
+)
+
+var DRAutoSyncState_name = map[int32]string{
+	0: "SYNC",
+	1: "ASYNC",
+	2: "SYNC_RECOVER",
+}
+var DRAutoSyncState_value = map[string]int32{
+	"SYNC":         0,
+	"ASYNC":        1,
+	"SYNC_RECOVER": 2,
+}
+
+func (x DRAutoSyncState) String() string {
+	return proto.EnumName(DRAutoSyncState_name, int32(x))
+}
+func (DRAutoSyncState) EnumDescriptor() ([]byte, []int) { return fileDescriptorReplicationpb, []int{1} }
+
+// ReplicationStatus is the replication mode of the cluster and its state.
+type ReplicationStatus struct {
+	Mode       ReplicationMode `protobuf:"varint,1,opt,name=mode,proto3,enum=replicationpb.ReplicationMode" json:"mode,omitempty"`
+	DrAutoSync *DRAutoSync     `protobuf:"bytes,2,opt,name=dr_auto_sync,json=drAutoSync" json:"dr_auto_sync,omitempty"`
+}
+
+func (m *ReplicationStatus) Reset()                    { *m = ReplicationStatus{} }
+func (m *ReplicationStatus) String() string            { return proto.CompactTextString(m) }
+func (*ReplicationStatus) ProtoMessage()               {}
+func (*ReplicationStatus) Descriptor() ([]byte, []int) { return fileDescriptorReplicationpb, []int{0} }
+
+func (m *ReplicationStatus) GetMode() ReplicationMode {
+	if m != nil {
+		return m.Mode
+	}
+	return ReplicationMode_MAJORITY
+}
+
+func (m *ReplicationStatus) GetDrAutoSync() *DRAutoSync {
+	if m != nil {
+		return m.DrAutoSync
+	}
+	return nil
+}
+
+// DRAutoSync is the state of the DR_AUTO_SYNC mode.
+type DRAutoSync struct {
+	// label_key is the store label to distinguish the zones.
+	LabelKey string          `protobuf:"bytes,1,opt,name=label_key,json=labelKey,proto3" json:"label_key,omitempty"`
+	State    DRAutoSyncState `protobuf:"varint,2,opt,name=state,proto3,enum=replicationpb.DRAutoSyncState" json:"state,omitempty"`
+	// state_id is allocated every time the state is switched, so that TiKV
+	// can tell which state a region is replicated in.
+	StateId uint64 `protobuf:"varint,3,opt,name=state_id,json=stateId,proto3" json:"state_id,omitempty"`
+	// wait_sync_timeout_hint is the seconds for TiKV to wait for the DR zone
+	// to commit logs in the SYNC state.
+	WaitSyncTimeoutHint int32 `protobuf:"varint,4,opt,name=wait_sync_timeout_hint,json=waitSyncTimeoutHint,proto3" json:"wait_sync_timeout_hint,omitempty"`
+}
+
+func (m *DRAutoSync) Reset()                    { *m = DRAutoSync{} }
+func (m *DRAutoSync) String() string            { return proto.CompactTextString(m) }
+func (*DRAutoSync) ProtoMessage()               {}
+func (*DRAutoSync) Descriptor() ([]byte, []int) { return fileDescriptorReplicationpb, []int{1} }
+
+func (m *DRAutoSync) GetLabelKey() string {
+	if m != nil {
+		return m.LabelKey
+	}
+	return ""
+}
+
+func (m *DRAutoSync) GetState() DRAutoSyncState {
+	if m != nil {
+		return m.State
+	}
+	return DRAutoSyncState_SYNC
+}
+
+func (m *DRAutoSync) GetStateId() uint64 {
+	if m != nil {
+		return m.StateId
+	}
+	return 0
+}
+
+func (m *DRAutoSync) GetWaitSyncTimeoutHint() int32 {
+	if m != nil {
+		return m.WaitSyncTimeoutHint
+	}
+	return 0
+}
+
+type GetReplicationStatusRequest struct {
+	Header *pdpb.RequestHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+}
+
+func (m *GetReplicationStatusRequest) Reset()         { *m = GetReplicationStatusRequest{} }
+func (m *GetReplicationStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*GetReplicationStatusRequest) ProtoMessage()    {}
+func (*GetReplicationStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptorReplicationpb, []int{2}
+}
+
+func (m *GetReplicationStatusRequest) GetHeader() *pdpb.RequestHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+type GetReplicationStatusResponse struct {
+	Header *pdpb.ResponseHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+	Status *ReplicationStatus   `protobuf:"bytes,2,opt,name=status" json:"status,omitempty"`
+}
+
+func (m *GetReplicationStatusResponse) Reset()         { *m = GetReplicationStatusResponse{} }
+func (m *GetReplicationStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*GetReplicationStatusResponse) ProtoMessage()    {}
+func (*GetReplicationStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptorReplicationpb, []int{3}
+}
+
+func (m *GetReplicationStatusResponse) GetHeader() *pdpb.ResponseHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *GetReplicationStatusResponse) GetStatus() *ReplicationStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*ReplicationStatus)(nil), "replicationpb.ReplicationStatus")
+	proto.RegisterType((*DRAutoSync)(nil), "replicationpb.DRAutoSync")
+	proto.RegisterType((*GetReplicationStatusRequest)(nil), "replicationpb.GetReplicationStatusRequest")
+	proto.RegisterType((*GetReplicationStatusResponse)(nil), "replicationpb.GetReplicationStatusResponse")
+	proto.RegisterEnum("replicationpb.ReplicationMode", ReplicationMode_name, ReplicationMode_value)
+	proto.RegisterEnum("replicationpb.DRAutoSyncState", DRAutoSyncState_name, DRAutoSyncState_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for Replication service
+
+type ReplicationClient interface {
+	// GetReplicationStatus returns the replication status which the stores
+	// should follow. TiKV polls it along with the store heartbeats.
+	GetReplicationStatus(ctx context.Context, in *GetReplicationStatusRequest, opts ...grpc.CallOption) (*GetReplicationStatusResponse, error)
+}
+
+type replicationClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewReplicationClient(cc *grpc.ClientConn) ReplicationClient {
+	return &replicationClient{cc}
+}
+
+func (c *replicationClient) GetReplicationStatus(ctx context.Context, in *GetReplicationStatusRequest, opts ...grpc.CallOption) (*GetReplicationStatusResponse, error) {
+	out := new(GetReplicationStatusResponse)
+	err := grpc.Invoke(ctx, "/replicationpb.Replication/GetReplicationStatus", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Replication service
+
+type ReplicationServer interface {
+	// GetReplicationStatus returns the replication status which the stores
+	// should follow. TiKV polls it along with the store heartbeats.
+	GetReplicationStatus(context.Context, *GetReplicationStatusRequest) (*GetReplicationStatusResponse, error)
+}
+
+func RegisterReplicationServer(s *grpc.Server, srv ReplicationServer) {
+	s.RegisterService(&_Replication_serviceDesc, srv)
+}
+
+func _Replication_GetReplicationStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReplicationStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReplicationServer).GetReplicationStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/replicationpb.Replication/GetReplicationStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReplicationServer).GetReplicationStatus(ctx, req.(*GetReplicationStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Replication_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "replicationpb.Replication",
+	HandlerType: (*ReplicationServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetReplicationStatus",
+			Handler:    _Replication_GetReplicationStatus_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "replicationpb.proto",
+}
+
+func (m *ReplicationStatus) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReplicationStatus) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Mode != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintReplicationpb(dAtA, i, uint64(m.Mode))
+	}
+	if m.DrAutoSync != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintReplicationpb(dAtA, i, uint64(m.DrAutoSync.Size()))
+		n1, err := m.DrAutoSync.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	return i, nil
+}
+
+func (m *DRAutoSync) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DRAutoSync) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.LabelKey) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintReplicationpb(dAtA, i, uint64(len(m.LabelKey)))
+		i += copy(dAtA[i:], m.LabelKey)
+	}
+	if m.State != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintReplicationpb(dAtA, i, uint64(m.State))
+	}
+	if m.StateId != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintReplicationpb(dAtA, i, uint64(m.StateId))
+	}
+	if m.WaitSyncTimeoutHint != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintReplicationpb(dAtA, i, uint64(m.WaitSyncTimeoutHint))
+	}
+	return i, nil
+}
+
+func (m *GetReplicationStatusRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetReplicationStatusRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintReplicationpb(dAtA, i, uint64(m.Header.Size()))
+		n2, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n2
+	}
+	return i, nil
+}
+
+func (m *GetReplicationStatusResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetReplicationStatusResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintReplicationpb(dAtA, i, uint64(m.Header.Size()))
+		n3, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n3
+	}
+	if m.Status != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintReplicationpb(dAtA, i, uint64(m.Status.Size()))
+		n4, err := m.Status.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n4
+	}
+	return i, nil
+}
+
+func encodeVarintReplicationpb(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *ReplicationStatus) Size() (n int) {
+	var l int
+	_ = l
+	if m.Mode != 0 {
+		n += 1 + sovReplicationpb(uint64(m.Mode))
+	}
+	if m.DrAutoSync != nil {
+		l = m.DrAutoSync.Size()
+		n += 1 + l + sovReplicationpb(uint64(l))
+	}
+	return n
+}
+
+func (m *DRAutoSync) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.LabelKey)
+	if l > 0 {
+		n += 1 + l + sovReplicationpb(uint64(l))
+	}
+	if m.State != 0 {
+		n += 1 + sovReplicationpb(uint64(m.State))
+	}
+	if m.StateId != 0 {
+		n += 1 + sovReplicationpb(uint64(m.StateId))
+	}
+	if m.WaitSyncTimeoutHint != 0 {
+		n += 1 + sovReplicationpb(uint64(m.WaitSyncTimeoutHint))
+	}
+	return n
+}
+
+func (m *GetReplicationStatusRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovReplicationpb(uint64(l))
+	}
+	return n
+}
+
+func (m *GetReplicationStatusResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovReplicationpb(uint64(l))
+	}
+	if m.Status != nil {
+		l = m.Status.Size()
+		n += 1 + l + sovReplicationpb(uint64(l))
+	}
+	return n
+}
+
+func sovReplicationpb(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozReplicationpb(x uint64) (n int) {
+	return sovReplicationpb(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *ReplicationStatus) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowReplicationpb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ReplicationStatus: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ReplicationStatus: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Mode", wireType)
+			}
+			m.Mode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowReplicationpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Mode |= (ReplicationMode(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DrAutoSync", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowReplicationpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthReplicationpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.DrAutoSync == nil {
+				m.DrAutoSync = &DRAutoSync{}
+			}
+			if err := m.DrAutoSync.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipReplicationpb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthReplicationpb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DRAutoSync) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowReplicationpb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DRAutoSync: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DRAutoSync: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LabelKey", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowReplicationpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthReplicationpb
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LabelKey = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
+			}
+			m.State = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowReplicationpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.State |= (DRAutoSyncState(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StateId", wireType)
+			}
+			m.StateId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowReplicationpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StateId |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WaitSyncTimeoutHint", wireType)
+			}
+			m.WaitSyncTimeoutHint = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowReplicationpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.WaitSyncTimeoutHint |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipReplicationpb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthReplicationpb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetReplicationStatusRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowReplicationpb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetReplicationStatusRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetReplicationStatusRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowReplicationpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthReplicationpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.RequestHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipReplicationpb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthReplicationpb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetReplicationStatusResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowReplicationpb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetReplicationStatusResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetReplicationStatusResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowReplicationpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthReplicationpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.ResponseHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowReplicationpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthReplicationpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Status == nil {
+				m.Status = &ReplicationStatus{}
+			}
+			if err := m.Status.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipReplicationpb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthReplicationpb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipReplicationpb(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowReplicationpb
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowReplicationpb
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+			return iNdEx, nil
+		case 1:
+			iNdEx += 8
+			return iNdEx, nil
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowReplicationpb
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			iNdEx += length
+			if length < 0 {
+				return 0, ErrInvalidLengthReplicationpb
+			}
+			return iNdEx, nil
+		case 3:
+			for {
+				var innerWire uint64
+				var start int = iNdEx
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return 0, ErrIntOverflowReplicationpb
+					}
+					if iNdEx >= l {
+						return 0, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					innerWire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				innerWireType := int(innerWire & 0x7)
+				if innerWireType == 4 {
+					break
+				}
+				next, err := skipReplicationpb(dAtA[start:])
+				if err != nil {
+					return 0, err
+				}
+				iNdEx = start + next
+			}
+			return iNdEx, nil
+		case 4:
+			return iNdEx, nil
+		case 5:
+			iNdEx += 4
+			return iNdEx, nil
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+	}
+	panic("unreachable")
+}
+
+var (
+	ErrInvalidLengthReplicationpb = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowReplicationpb   = fmt.Errorf("proto: integer overflow")
+)
+
+func init() { proto.RegisterFile("replicationpb.proto", fileDescriptorReplicationpb) }
+
+var fileDescriptorReplicationpb = []byte{
+	// 457 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x53, 0xc1, 0x6e, 0xd3, 0x40,
+	0x10, 0xcd, 0x86, 0x24, 0x24, 0x93, 0x40, 0xcd, 0x24, 0x42, 0x6e, 0x8a, 0x2c, 0x2b, 0xa7, 0x28,
+	0x45, 0x41, 0xb8, 0x1c, 0x2a, 0x71, 0x0a, 0x6d, 0x45, 0x5b, 0x54, 0x22, 0x6d, 0x02, 0x52, 0x4f,
+	0x2b, 0x27, 0xbb, 0x6a, 0x2d, 0x52, 0xaf, 0xb1, 0xd7, 0x42, 0xbe, 0x70, 0x42, 0x7c, 0x03, 0x7f,
+	0xc1, 0x6f, 0x70, 0xe4, 0x13, 0x50, 0xf8, 0x11, 0xe4, 0xb5, 0xa3, 0x34, 0x26, 0xa0, 0xde, 0xc6,
+	0xf3, 0xde, 0xbc, 0x79, 0x7e, 0xa3, 0x85, 0x76, 0x28, 0x82, 0x85, 0x37, 0x77, 0x95, 0x27, 0xfd,
+	0x60, 0x36, 0x0c, 0x42, 0xa9, 0x24, 0x3e, 0xd8, 0x68, 0x76, 0x21, 0xe0, 0x2b, 0xa8, 0xdb, 0xb9,
+	0x92, 0x57, 0x52, 0x97, 0xcf, 0xd2, 0x2a, 0xeb, 0xf6, 0xbe, 0x10, 0x78, 0x44, 0xd7, 0x33, 0x13,
+	0xe5, 0xaa, 0x38, 0x42, 0x07, 0x2a, 0x37, 0x92, 0x0b, 0x93, 0xd8, 0xa4, 0xff, 0xd0, 0xb1, 0x86,
+	0x9b, 0xab, 0x6e, 0xf1, 0x2f, 0x24, 0x17, 0x54, 0x73, 0xf1, 0x25, 0xb4, 0x78, 0xc8, 0xdc, 0x58,
+	0x49, 0x16, 0x25, 0xfe, 0xdc, 0x2c, 0xdb, 0xa4, 0xdf, 0x74, 0x76, 0x0b, 0xb3, 0xc7, 0x74, 0x14,
+	0x2b, 0x39, 0x49, 0xfc, 0x39, 0x05, 0x1e, 0xae, 0xea, 0xde, 0x77, 0x02, 0xb0, 0x86, 0x70, 0x0f,
+	0x1a, 0x0b, 0x77, 0x26, 0x16, 0xec, 0x83, 0x48, 0xb4, 0x89, 0x06, 0xad, 0xeb, 0xc6, 0x1b, 0x91,
+	0xe0, 0x0b, 0xa8, 0x46, 0xca, 0x55, 0xc2, 0x2c, 0x6f, 0x75, 0xb7, 0x96, 0x49, 0x7f, 0x46, 0xd0,
+	0x8c, 0x8c, 0xbb, 0x50, 0xd7, 0x05, 0xf3, 0xb8, 0x79, 0xcf, 0x26, 0xfd, 0x0a, 0xbd, 0xaf, 0xbf,
+	0xcf, 0x38, 0x1e, 0xc0, 0xe3, 0x4f, 0xae, 0xa7, 0xb4, 0x6d, 0xa6, 0xbc, 0x1b, 0x21, 0x63, 0xc5,
+	0xae, 0x3d, 0x5f, 0x99, 0x15, 0x9b, 0xf4, 0xab, 0xb4, 0x9d, 0xa2, 0xa9, 0xe0, 0x34, 0xc3, 0x4e,
+	0x3d, 0x5f, 0xf5, 0xce, 0x61, 0xef, 0xb5, 0x50, 0x7f, 0x45, 0x47, 0xc5, 0xc7, 0x58, 0x44, 0x0a,
+	0xf7, 0xa1, 0x76, 0x2d, 0x5c, 0x2e, 0x42, 0x6d, 0xbf, 0xe9, 0xb4, 0x87, 0xfa, 0x14, 0x39, 0x7c,
+	0xaa, 0x21, 0x9a, 0x53, 0x7a, 0x5f, 0x09, 0x3c, 0xd9, 0x2e, 0x16, 0x05, 0xd2, 0x8f, 0x04, 0x3e,
+	0x2d, 0xa8, 0x75, 0x56, 0x6a, 0x19, 0xbe, 0x29, 0x87, 0x87, 0x50, 0x8b, 0xf4, 0x7c, 0x7e, 0x03,
+	0xfb, 0xdf, 0xf7, 0xcb, 0xf7, 0xe4, 0xfc, 0xc1, 0x73, 0xd8, 0x29, 0x1c, 0x17, 0x5b, 0x50, 0xbf,
+	0x18, 0x9d, 0x8f, 0xe9, 0xd9, 0xf4, 0xd2, 0x28, 0xa1, 0x01, 0xad, 0x63, 0xca, 0x46, 0xef, 0xa6,
+	0x63, 0x36, 0xb9, 0x7c, 0x7b, 0x64, 0x90, 0xc1, 0x21, 0xec, 0x14, 0x12, 0xc7, 0x3a, 0x54, 0x34,
+	0x58, 0xc2, 0x06, 0x54, 0x47, 0x19, 0x2f, 0x9d, 0x4c, 0x2b, 0x46, 0x4f, 0x8e, 0xc6, 0xef, 0x4f,
+	0xa8, 0x51, 0x76, 0x3e, 0x43, 0xf3, 0xd6, 0x32, 0x94, 0xd0, 0xd9, 0x96, 0x01, 0x0e, 0x0a, 0xee,
+	0xff, 0x93, 0x7a, 0x77, 0xff, 0x4e, 0xdc, 0x2c, 0xb4, 0x57, 0xc6, 0x8f, 0xa5, 0x45, 0x7e, 0x2e,
+	0x2d, 0xf2, 0x6b, 0x69, 0x91, 0x6f, 0xbf, 0xad, 0xd2, 0xac, 0xa6, 0xdf, 0xc4, 0xc1, 0x9f, 0x01,
+	0x00, 0x46, 0x1e, 0x28, 0x4f, 0x5b, 0x03, 0x00, 0x00,
+}
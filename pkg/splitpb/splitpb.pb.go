@@ -0,0 +1,1520 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: splitpb.proto
+
+/*
+Package splitpb is a generated protocol buffer package.
+
+It is generated from these files:
+
+	splitpb.proto
+
+It has these top-level messages:
+
+	KeySample
+	ReportKeySamplesRequest
+	ReportKeySamplesResponse
+	SplitCommand
+	GetSplitCommandsRequest
+	GetSplitCommandsResponse
+*/
+package splitpb
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	proto "github.com/golang/protobuf/proto"
+
+	metapb "github.com/pingcap/kvproto/pkg/metapb"
+
+	pdpb "github.com/pingcap/kvproto/pkg/pdpb"
+
+	_ "github.com/gogo/protobuf/gogoproto"
+
+	context "golang.org/x/net/context"
+
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// KeySample is a sampled key accessed by the requests to a region, count is
+// the number of the requests accessing it.
+type KeySample struct {
+	Key   []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Count uint64 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (m *KeySample) Reset()                    { *m = KeySample{} }
+func (m *KeySample) String() string            { return proto.CompactTextString(m) }
+func (*KeySample) ProtoMessage()               {}
+func (*KeySample) Descriptor() ([]byte, []int) { return fileDescriptorSplitpb, []int{0} }
+
+func (m *KeySample) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *KeySample) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+// ReportKeySamplesRequest reports the key samples of a region. The samples
+// replace the ones reported before.
+type ReportKeySamplesRequest struct {
+	Header  *pdpb.RequestHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+	Region  *metapb.Region      `protobuf:"bytes,2,opt,name=region" json:"region,omitempty"`
+	Samples []*KeySample        `protobuf:"bytes,3,rep,name=samples" json:"samples,omitempty"`
+}
+
+func (m *ReportKeySamplesRequest) Reset()                    { *m = ReportKeySamplesRequest{} }
+func (m *ReportKeySamplesRequest) String() string            { return proto.CompactTextString(m) }
+func (*ReportKeySamplesRequest) ProtoMessage()               {}
+func (*ReportKeySamplesRequest) Descriptor() ([]byte, []int) { return fileDescriptorSplitpb, []int{1} }
+
+func (m *ReportKeySamplesRequest) GetHeader() *pdpb.RequestHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *ReportKeySamplesRequest) GetRegion() *metapb.Region {
+	if m != nil {
+		return m.Region
+	}
+	return nil
+}
+
+func (m *ReportKeySamplesRequest) GetSamples() []*KeySample {
+	if m != nil {
+		return m.Samples
+	}
+	return nil
+}
+
+// ReportKeySamplesResponse returns whether the samples are accepted. They are
+// only accepted when the region is regarded as hot by PD.
+type ReportKeySamplesResponse struct {
+	Header   *pdpb.ResponseHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+	Accepted bool                 `protobuf:"varint,2,opt,name=accepted,proto3" json:"accepted,omitempty"`
+}
+
+func (m *ReportKeySamplesResponse) Reset()                    { *m = ReportKeySamplesResponse{} }
+func (m *ReportKeySamplesResponse) String() string            { return proto.CompactTextString(m) }
+func (*ReportKeySamplesResponse) ProtoMessage()               {}
+func (*ReportKeySamplesResponse) Descriptor() ([]byte, []int) { return fileDescriptorSplitpb, []int{2} }
+
+func (m *ReportKeySamplesResponse) GetHeader() *pdpb.ResponseHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *ReportKeySamplesResponse) GetAccepted() bool {
+	if m != nil {
+		return m.Accepted
+	}
+	return false
+}
+
+// SplitCommand asks the leader of a region to split it at the keys. It is
+// stale if the region epoch does not match.
+type SplitCommand struct {
+	RegionId    uint64              `protobuf:"varint,1,opt,name=region_id,json=regionId,proto3" json:"region_id,omitempty"`
+	RegionEpoch *metapb.RegionEpoch `protobuf:"bytes,2,opt,name=region_epoch,json=regionEpoch" json:"region_epoch,omitempty"`
+	SplitKeys   [][]byte            `protobuf:"bytes,3,rep,name=split_keys,json=splitKeys" json:"split_keys,omitempty"`
+}
+
+func (m *SplitCommand) Reset()                    { *m = SplitCommand{} }
+func (m *SplitCommand) String() string            { return proto.CompactTextString(m) }
+func (*SplitCommand) ProtoMessage()               {}
+func (*SplitCommand) Descriptor() ([]byte, []int) { return fileDescriptorSplitpb, []int{3} }
+
+func (m *SplitCommand) GetRegionId() uint64 {
+	if m != nil {
+		return m.RegionId
+	}
+	return 0
+}
+
+func (m *SplitCommand) GetRegionEpoch() *metapb.RegionEpoch {
+	if m != nil {
+		return m.RegionEpoch
+	}
+	return nil
+}
+
+func (m *SplitCommand) GetSplitKeys() [][]byte {
+	if m != nil {
+		return m.SplitKeys
+	}
+	return nil
+}
+
+type GetSplitCommandsRequest struct {
+	Header  *pdpb.RequestHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+	StoreId uint64              `protobuf:"varint,2,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
+}
+
+func (m *GetSplitCommandsRequest) Reset()                    { *m = GetSplitCommandsRequest{} }
+func (m *GetSplitCommandsRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetSplitCommandsRequest) ProtoMessage()               {}
+func (*GetSplitCommandsRequest) Descriptor() ([]byte, []int) { return fileDescriptorSplitpb, []int{4} }
+
+func (m *GetSplitCommandsRequest) GetHeader() *pdpb.RequestHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *GetSplitCommandsRequest) GetStoreId() uint64 {
+	if m != nil {
+		return m.StoreId
+	}
+	return 0
+}
+
+type GetSplitCommandsResponse struct {
+	Header   *pdpb.ResponseHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+	Commands []*SplitCommand      `protobuf:"bytes,2,rep,name=commands" json:"commands,omitempty"`
+}
+
+func (m *GetSplitCommandsResponse) Reset()                    { *m = GetSplitCommandsResponse{} }
+func (m *GetSplitCommandsResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetSplitCommandsResponse) ProtoMessage()               {}
+func (*GetSplitCommandsResponse) Descriptor() ([]byte, []int) { return fileDescriptorSplitpb, []int{5} }
+
+func (m *GetSplitCommandsResponse) GetHeader() *pdpb.ResponseHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *GetSplitCommandsResponse) GetCommands() []*SplitCommand {
+	if m != nil {
+		return m.Commands
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*KeySample)(nil), "splitpb.KeySample")
+	proto.RegisterType((*ReportKeySamplesRequest)(nil), "splitpb.ReportKeySamplesRequest")
+	proto.RegisterType((*ReportKeySamplesResponse)(nil), "splitpb.ReportKeySamplesResponse")
+	proto.RegisterType((*SplitCommand)(nil), "splitpb.SplitCommand")
+	proto.RegisterType((*GetSplitCommandsRequest)(nil), "splitpb.GetSplitCommandsRequest")
+	proto.RegisterType((*GetSplitCommandsResponse)(nil), "splitpb.GetSplitCommandsResponse")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for Split service
+
+type SplitClient interface {
+	ReportKeySamples(ctx context.Context, in *ReportKeySamplesRequest, opts ...grpc.CallOption) (*ReportKeySamplesResponse, error)
+	// GetSplitCommands gets the splits at the given keys of the regions led by
+	// the store. They are not sent by the region heartbeat responses, since
+	// pdpb.SplitRegion can not carry the keys.
+	GetSplitCommands(ctx context.Context, in *GetSplitCommandsRequest, opts ...grpc.CallOption) (*GetSplitCommandsResponse, error)
+}
+
+type splitClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewSplitClient(cc *grpc.ClientConn) SplitClient {
+	return &splitClient{cc}
+}
+
+func (c *splitClient) ReportKeySamples(ctx context.Context, in *ReportKeySamplesRequest, opts ...grpc.CallOption) (*ReportKeySamplesResponse, error) {
+	out := new(ReportKeySamplesResponse)
+	err := grpc.Invoke(ctx, "/splitpb.Split/ReportKeySamples", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *splitClient) GetSplitCommands(ctx context.Context, in *GetSplitCommandsRequest, opts ...grpc.CallOption) (*GetSplitCommandsResponse, error) {
+	out := new(GetSplitCommandsResponse)
+	err := grpc.Invoke(ctx, "/splitpb.Split/GetSplitCommands", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Split service
+
+type SplitServer interface {
+	ReportKeySamples(context.Context, *ReportKeySamplesRequest) (*ReportKeySamplesResponse, error)
+	// GetSplitCommands gets the splits at the given keys of the regions led by
+	// the store. They are not sent by the region heartbeat responses, since
+	// pdpb.SplitRegion can not carry the keys.
+	GetSplitCommands(context.Context, *GetSplitCommandsRequest) (*GetSplitCommandsResponse, error)
+}
+
+func RegisterSplitServer(s *grpc.Server, srv SplitServer) {
+	s.RegisterService(&_Split_serviceDesc, srv)
+}
+
+func _Split_ReportKeySamples_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportKeySamplesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SplitServer).ReportKeySamples(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/splitpb.Split/ReportKeySamples",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SplitServer).ReportKeySamples(ctx, req.(*ReportKeySamplesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Split_GetSplitCommands_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSplitCommandsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SplitServer).GetSplitCommands(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/splitpb.Split/GetSplitCommands",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SplitServer).GetSplitCommands(ctx, req.(*GetSplitCommandsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Split_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "splitpb.Split",
+	HandlerType: (*SplitServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ReportKeySamples",
+			Handler:    _Split_ReportKeySamples_Handler,
+		},
+		{
+			MethodName: "GetSplitCommands",
+			Handler:    _Split_GetSplitCommands_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "splitpb.proto",
+}
+
+func (m *KeySample) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *KeySample) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Key) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintSplitpb(dAtA, i, uint64(len(m.Key)))
+		i += copy(dAtA[i:], m.Key)
+	}
+	if m.Count != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintSplitpb(dAtA, i, uint64(m.Count))
+	}
+	return i, nil
+}
+
+func (m *ReportKeySamplesRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReportKeySamplesRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintSplitpb(dAtA, i, uint64(m.Header.Size()))
+		n1, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	if m.Region != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintSplitpb(dAtA, i, uint64(m.Region.Size()))
+		n2, err := m.Region.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n2
+	}
+	if len(m.Samples) > 0 {
+		for _, msg := range m.Samples {
+			dAtA[i] = 0x1a
+			i++
+			i = encodeVarintSplitpb(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *ReportKeySamplesResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReportKeySamplesResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintSplitpb(dAtA, i, uint64(m.Header.Size()))
+		n3, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n3
+	}
+	if m.Accepted {
+		dAtA[i] = 0x10
+		i++
+		if m.Accepted {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *SplitCommand) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SplitCommand) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.RegionId != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintSplitpb(dAtA, i, uint64(m.RegionId))
+	}
+	if m.RegionEpoch != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintSplitpb(dAtA, i, uint64(m.RegionEpoch.Size()))
+		n4, err := m.RegionEpoch.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n4
+	}
+	if len(m.SplitKeys) > 0 {
+		for _, b := range m.SplitKeys {
+			dAtA[i] = 0x1a
+			i++
+			i = encodeVarintSplitpb(dAtA, i, uint64(len(b)))
+			i += copy(dAtA[i:], b)
+		}
+	}
+	return i, nil
+}
+
+func (m *GetSplitCommandsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetSplitCommandsRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintSplitpb(dAtA, i, uint64(m.Header.Size()))
+		n5, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n5
+	}
+	if m.StoreId != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintSplitpb(dAtA, i, uint64(m.StoreId))
+	}
+	return i, nil
+}
+
+func (m *GetSplitCommandsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetSplitCommandsResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintSplitpb(dAtA, i, uint64(m.Header.Size()))
+		n6, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n6
+	}
+	if len(m.Commands) > 0 {
+		for _, msg := range m.Commands {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintSplitpb(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func encodeVarintSplitpb(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *KeySample) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovSplitpb(uint64(l))
+	}
+	if m.Count != 0 {
+		n += 1 + sovSplitpb(uint64(m.Count))
+	}
+	return n
+}
+
+func (m *ReportKeySamplesRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovSplitpb(uint64(l))
+	}
+	if m.Region != nil {
+		l = m.Region.Size()
+		n += 1 + l + sovSplitpb(uint64(l))
+	}
+	if len(m.Samples) > 0 {
+		for _, e := range m.Samples {
+			l = e.Size()
+			n += 1 + l + sovSplitpb(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ReportKeySamplesResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovSplitpb(uint64(l))
+	}
+	if m.Accepted {
+		n += 2
+	}
+	return n
+}
+
+func (m *SplitCommand) Size() (n int) {
+	var l int
+	_ = l
+	if m.RegionId != 0 {
+		n += 1 + sovSplitpb(uint64(m.RegionId))
+	}
+	if m.RegionEpoch != nil {
+		l = m.RegionEpoch.Size()
+		n += 1 + l + sovSplitpb(uint64(l))
+	}
+	if len(m.SplitKeys) > 0 {
+		for _, b := range m.SplitKeys {
+			l = len(b)
+			n += 1 + l + sovSplitpb(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *GetSplitCommandsRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovSplitpb(uint64(l))
+	}
+	if m.StoreId != 0 {
+		n += 1 + sovSplitpb(uint64(m.StoreId))
+	}
+	return n
+}
+
+func (m *GetSplitCommandsResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovSplitpb(uint64(l))
+	}
+	if len(m.Commands) > 0 {
+		for _, e := range m.Commands {
+			l = e.Size()
+			n += 1 + l + sovSplitpb(uint64(l))
+		}
+	}
+	return n
+}
+
+func sovSplitpb(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozSplitpb(x uint64) (n int) {
+	return sovSplitpb(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *KeySample) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSplitpb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: KeySample: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: KeySample: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSplitpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthSplitpb
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = append(m.Key[:0], dAtA[iNdEx:postIndex]...)
+			if m.Key == nil {
+				m.Key = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			}
+			m.Count = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSplitpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Count |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSplitpb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSplitpb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ReportKeySamplesRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSplitpb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ReportKeySamplesRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ReportKeySamplesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSplitpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSplitpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.RequestHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Region", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSplitpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSplitpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Region == nil {
+				m.Region = &metapb.Region{}
+			}
+			if err := m.Region.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Samples", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSplitpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSplitpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Samples = append(m.Samples, &KeySample{})
+			if err := m.Samples[len(m.Samples)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSplitpb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSplitpb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ReportKeySamplesResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSplitpb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ReportKeySamplesResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ReportKeySamplesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSplitpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSplitpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.ResponseHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Accepted", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSplitpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Accepted = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSplitpb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSplitpb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SplitCommand) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSplitpb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SplitCommand: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SplitCommand: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RegionId", wireType)
+			}
+			m.RegionId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSplitpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RegionId |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RegionEpoch", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSplitpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSplitpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.RegionEpoch == nil {
+				m.RegionEpoch = &metapb.RegionEpoch{}
+			}
+			if err := m.RegionEpoch.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SplitKeys", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSplitpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthSplitpb
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SplitKeys = append(m.SplitKeys, make([]byte, postIndex-iNdEx))
+			copy(m.SplitKeys[len(m.SplitKeys)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSplitpb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSplitpb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetSplitCommandsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSplitpb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetSplitCommandsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetSplitCommandsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSplitpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSplitpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.RequestHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StoreId", wireType)
+			}
+			m.StoreId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSplitpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StoreId |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSplitpb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSplitpb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetSplitCommandsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSplitpb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetSplitCommandsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetSplitCommandsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSplitpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSplitpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.ResponseHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Commands", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSplitpb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSplitpb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Commands = append(m.Commands, &SplitCommand{})
+			if err := m.Commands[len(m.Commands)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSplitpb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSplitpb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipSplitpb(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowSplitpb
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowSplitpb
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+			return iNdEx, nil
+		case 1:
+			iNdEx += 8
+			return iNdEx, nil
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowSplitpb
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			iNdEx += length
+			if length < 0 {
+				return 0, ErrInvalidLengthSplitpb
+			}
+			return iNdEx, nil
+		case 3:
+			for {
+				var innerWire uint64
+				var start int = iNdEx
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return 0, ErrIntOverflowSplitpb
+					}
+					if iNdEx >= l {
+						return 0, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					innerWire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				innerWireType := int(innerWire & 0x7)
+				if innerWireType == 4 {
+					break
+				}
+				next, err := skipSplitpb(dAtA[start:])
+				if err != nil {
+					return 0, err
+				}
+				iNdEx = start + next
+			}
+			return iNdEx, nil
+		case 4:
+			return iNdEx, nil
+		case 5:
+			iNdEx += 4
+			return iNdEx, nil
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+	}
+	panic("unreachable")
+}
+
+var (
+	ErrInvalidLengthSplitpb = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowSplitpb   = fmt.Errorf("proto: integer overflow")
+)
+
+func init() { proto.RegisterFile("splitpb.proto", fileDescriptorSplitpb) }
+
+var fileDescriptorSplitpb = []byte{
+	// 435 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x93, 0x4f, 0x6e, 0xd3, 0x40,
+	0x14, 0xc6, 0x99, 0xa6, 0x4d, 0x9c, 0x17, 0x83, 0xa2, 0x69, 0x50, 0x8d, 0x11, 0x91, 0xeb, 0x05,
+	0x8a, 0x44, 0x65, 0x84, 0x2b, 0x71, 0x00, 0x10, 0x82, 0xaa, 0xbb, 0xe9, 0xa2, 0xcb, 0xca, 0xf5,
+	0x3c, 0xa5, 0x51, 0x6b, 0xcf, 0xe0, 0x99, 0x2e, 0x2c, 0x76, 0x9c, 0x82, 0x05, 0x17, 0xe1, 0x06,
+	0x2c, 0x39, 0x02, 0x0a, 0x17, 0x41, 0x9e, 0x19, 0x9b, 0xe0, 0xaa, 0x5d, 0x64, 0xf7, 0xfe, 0xcd,
+	0x37, 0xbf, 0x6f, 0x9e, 0x0d, 0x8f, 0x95, 0xbc, 0x59, 0x69, 0x79, 0x99, 0xc8, 0x4a, 0x68, 0x41,
+	0x47, 0x2e, 0x0d, 0xfd, 0x02, 0x75, 0xd6, 0x96, 0x43, 0x90, 0xbc, 0x8b, 0x67, 0x4b, 0xb1, 0x14,
+	0x26, 0x7c, 0xdd, 0x44, 0xb6, 0x1a, 0x1f, 0xc3, 0xf8, 0x14, 0xeb, 0xb3, 0xac, 0x90, 0x37, 0x48,
+	0xa7, 0x30, 0xb8, 0xc6, 0x3a, 0x20, 0x11, 0x59, 0xf8, 0xac, 0x09, 0xe9, 0x0c, 0xf6, 0x72, 0x71,
+	0x5b, 0xea, 0x60, 0x27, 0x22, 0x8b, 0x5d, 0x66, 0x93, 0xf8, 0x3b, 0x81, 0x03, 0x86, 0x52, 0x54,
+	0xba, 0x3b, 0xab, 0x18, 0x7e, 0xbe, 0x45, 0xa5, 0xe9, 0x2b, 0x18, 0x5e, 0x61, 0xc6, 0xb1, 0x32,
+	0x32, 0x93, 0x74, 0x3f, 0x31, 0x0c, 0xae, 0xfd, 0xc9, 0xb4, 0x98, 0x1b, 0xa1, 0x2f, 0x61, 0x58,
+	0xe1, 0x72, 0x25, 0x4a, 0xa3, 0x3f, 0x49, 0x9f, 0x24, 0x0e, 0x9f, 0x99, 0x2a, 0x73, 0x5d, 0x7a,
+	0x04, 0x23, 0x65, 0xaf, 0x09, 0x06, 0xd1, 0x60, 0x31, 0x49, 0x69, 0xd2, 0xfa, 0xef, 0x08, 0x58,
+	0x3b, 0x12, 0x73, 0x08, 0xee, 0xd2, 0x29, 0x29, 0x4a, 0x85, 0xf4, 0xa8, 0x87, 0x37, 0x6b, 0xf1,
+	0x6c, 0xbf, 0xc7, 0x17, 0x82, 0x97, 0xe5, 0x39, 0x4a, 0x8d, 0xdc, 0x10, 0x7a, 0xac, 0xcb, 0xe3,
+	0xaf, 0x04, 0xfc, 0xb3, 0x06, 0xe2, 0xbd, 0x28, 0x8a, 0xac, 0xe4, 0xf4, 0x39, 0x8c, 0x2d, 0xee,
+	0xc5, 0x8a, 0x1b, 0xf5, 0x5d, 0xe6, 0xd9, 0xc2, 0x09, 0xa7, 0x6f, 0xc1, 0x77, 0x4d, 0x94, 0x22,
+	0xbf, 0x72, 0x7e, 0xf7, 0xff, 0xf7, 0xfb, 0xa1, 0x69, 0xb1, 0x49, 0xf5, 0x2f, 0xa1, 0x2f, 0x00,
+	0x8c, 0xd3, 0x8b, 0x6b, 0xac, 0xad, 0x79, 0x9f, 0x8d, 0x4d, 0xe5, 0x14, 0x6b, 0x15, 0x67, 0x70,
+	0xf0, 0x11, 0xf5, 0x26, 0xc6, 0x76, 0x8b, 0x78, 0x06, 0x9e, 0xd2, 0xa2, 0xc2, 0x06, 0xdd, 0xae,
+	0x7a, 0x64, 0xf2, 0x13, 0x1e, 0x7f, 0x81, 0xe0, 0xee, 0x15, 0x5b, 0xbd, 0xe6, 0x1b, 0xf0, 0x72,
+	0xa7, 0x10, 0xec, 0x98, 0x35, 0x3e, 0xed, 0xd6, 0xb8, 0xa9, 0xcf, 0xba, 0xb1, 0xf4, 0x07, 0x81,
+	0x3d, 0xd3, 0xa2, 0xe7, 0x30, 0xed, 0x2f, 0x95, 0x46, 0xdd, 0xf1, 0x7b, 0xbe, 0xc6, 0xf0, 0xf0,
+	0x81, 0x09, 0xe7, 0xe1, 0x1c, 0xa6, 0x7d, 0x7f, 0x1b, 0xc2, 0xf7, 0xbc, 0x6e, 0x78, 0xf8, 0xc0,
+	0x84, 0x15, 0x7e, 0x37, 0xfd, 0xb9, 0x9e, 0x93, 0x5f, 0xeb, 0x39, 0xf9, 0xbd, 0x9e, 0x93, 0x6f,
+	0x7f, 0xe6, 0x8f, 0x2e, 0x87, 0xe6, 0x9f, 0x3b, 0xfe, 0x3b, 0x00, 0x6e, 0xac, 0x4c, 0xf4, 0xbd,
+	0x03, 0x00, 0x00,
+}
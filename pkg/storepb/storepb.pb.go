@@ -0,0 +1,1645 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: storepb.proto
+
+/*
+Package storepb is a generated protocol buffer package.
+
+It is generated from these files:
+
+	storepb.proto
+
+It has these top-level messages:
+
+	StoreInfo
+	GetAllStoresRequest
+	GetAllStoresResponse
+	DiskStats
+	ReportDiskStatsRequest
+	ReportDiskStatsResponse
+*/
+package storepb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	proto "github.com/golang/protobuf/proto"
+
+	metapb "github.com/pingcap/kvproto/pkg/metapb"
+
+	pdpb "github.com/pingcap/kvproto/pkg/pdpb"
+
+	_ "github.com/gogo/protobuf/gogoproto"
+
+	context "golang.org/x/net/context"
+
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// StoreInfo is a store with its last heartbeat stats and scores, which are
+// taken from the same snapshot of the cluster.
+type StoreInfo struct {
+	Store *metapb.Store    `protobuf:"bytes,1,opt,name=store" json:"store,omitempty"`
+	Stats *pdpb.StoreStats `protobuf:"bytes,2,opt,name=stats" json:"stats,omitempty"`
+	// last_heartbeat is the unix time in nanoseconds.
+	LastHeartbeat int64   `protobuf:"varint,3,opt,name=last_heartbeat,json=lastHeartbeat,proto3" json:"last_heartbeat,omitempty"`
+	LeaderScore   float64 `protobuf:"fixed64,4,opt,name=leader_score,json=leaderScore,proto3" json:"leader_score,omitempty"`
+	RegionScore   float64 `protobuf:"fixed64,5,opt,name=region_score,json=regionScore,proto3" json:"region_score,omitempty"`
+}
+
+func (m *StoreInfo) Reset()                    { *m = StoreInfo{} }
+func (m *StoreInfo) String() string            { return proto.CompactTextString(m) }
+func (*StoreInfo) ProtoMessage()               {}
+func (*StoreInfo) Descriptor() ([]byte, []int) { return fileDescriptorStorepb, []int{0} }
+
+func (m *StoreInfo) GetStore() *metapb.Store {
+	if m != nil {
+		return m.Store
+	}
+	return nil
+}
+
+func (m *StoreInfo) GetStats() *pdpb.StoreStats {
+	if m != nil {
+		return m.Stats
+	}
+	return nil
+}
+
+func (m *StoreInfo) GetLastHeartbeat() int64 {
+	if m != nil {
+		return m.LastHeartbeat
+	}
+	return 0
+}
+
+func (m *StoreInfo) GetLeaderScore() float64 {
+	if m != nil {
+		return m.LeaderScore
+	}
+	return 0
+}
+
+func (m *StoreInfo) GetRegionScore() float64 {
+	if m != nil {
+		return m.RegionScore
+	}
+	return 0
+}
+
+// GetAllStoresRequest gets all the stores. The stats and scores are returned
+// only if with_stats is set.
+type GetAllStoresRequest struct {
+	Header                 *pdpb.RequestHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+	ExcludeTombstoneStores bool                `protobuf:"varint,2,opt,name=exclude_tombstone_stores,json=excludeTombstoneStores,proto3" json:"exclude_tombstone_stores,omitempty"`
+	WithStats              bool                `protobuf:"varint,3,opt,name=with_stats,json=withStats,proto3" json:"with_stats,omitempty"`
+}
+
+func (m *GetAllStoresRequest) Reset()                    { *m = GetAllStoresRequest{} }
+func (m *GetAllStoresRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetAllStoresRequest) ProtoMessage()               {}
+func (*GetAllStoresRequest) Descriptor() ([]byte, []int) { return fileDescriptorStorepb, []int{1} }
+
+func (m *GetAllStoresRequest) GetHeader() *pdpb.RequestHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *GetAllStoresRequest) GetExcludeTombstoneStores() bool {
+	if m != nil {
+		return m.ExcludeTombstoneStores
+	}
+	return false
+}
+
+func (m *GetAllStoresRequest) GetWithStats() bool {
+	if m != nil {
+		return m.WithStats
+	}
+	return false
+}
+
+// GetAllStoresResponse returns the stores sorted by ID.
+type GetAllStoresResponse struct {
+	Header *pdpb.ResponseHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+	Stores []*StoreInfo         `protobuf:"bytes,2,rep,name=stores" json:"stores,omitempty"`
+}
+
+func (m *GetAllStoresResponse) Reset()                    { *m = GetAllStoresResponse{} }
+func (m *GetAllStoresResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetAllStoresResponse) ProtoMessage()               {}
+func (*GetAllStoresResponse) Descriptor() ([]byte, []int) { return fileDescriptorStorepb, []int{2} }
+
+func (m *GetAllStoresResponse) GetHeader() *pdpb.ResponseHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *GetAllStoresResponse) GetStores() []*StoreInfo {
+	if m != nil {
+		return m.Stores
+	}
+	return nil
+}
+
+// DiskStats is the disk statistics of a store, which is reported along with
+// the store heartbeats.
+type DiskStats struct {
+	StoreId  uint64             `protobuf:"varint,1,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
+	Interval *pdpb.TimeInterval `protobuf:"bytes,2,opt,name=interval" json:"interval,omitempty"`
+	// disk_read_bytes and disk_write_bytes are the bytes read from and written
+	// to the disk during the interval.
+	DiskReadBytes  uint64 `protobuf:"varint,3,opt,name=disk_read_bytes,json=diskReadBytes,proto3" json:"disk_read_bytes,omitempty"`
+	DiskWriteBytes uint64 `protobuf:"varint,4,opt,name=disk_write_bytes,json=diskWriteBytes,proto3" json:"disk_write_bytes,omitempty"`
+	// pending_compaction_bytes is the estimated bytes to be compacted by the
+	// storage engine.
+	PendingCompactionBytes uint64 `protobuf:"varint,5,opt,name=pending_compaction_bytes,json=pendingCompactionBytes,proto3" json:"pending_compaction_bytes,omitempty"`
+}
+
+func (m *DiskStats) Reset()                    { *m = DiskStats{} }
+func (m *DiskStats) String() string            { return proto.CompactTextString(m) }
+func (*DiskStats) ProtoMessage()               {}
+func (*DiskStats) Descriptor() ([]byte, []int) { return fileDescriptorStorepb, []int{3} }
+
+func (m *DiskStats) GetStoreId() uint64 {
+	if m != nil {
+		return m.StoreId
+	}
+	return 0
+}
+
+func (m *DiskStats) GetInterval() *pdpb.TimeInterval {
+	if m != nil {
+		return m.Interval
+	}
+	return nil
+}
+
+func (m *DiskStats) GetDiskReadBytes() uint64 {
+	if m != nil {
+		return m.DiskReadBytes
+	}
+	return 0
+}
+
+func (m *DiskStats) GetDiskWriteBytes() uint64 {
+	if m != nil {
+		return m.DiskWriteBytes
+	}
+	return 0
+}
+
+func (m *DiskStats) GetPendingCompactionBytes() uint64 {
+	if m != nil {
+		return m.PendingCompactionBytes
+	}
+	return 0
+}
+
+type ReportDiskStatsRequest struct {
+	Header *pdpb.RequestHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+	Stats  *DiskStats          `protobuf:"bytes,2,opt,name=stats" json:"stats,omitempty"`
+}
+
+func (m *ReportDiskStatsRequest) Reset()                    { *m = ReportDiskStatsRequest{} }
+func (m *ReportDiskStatsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ReportDiskStatsRequest) ProtoMessage()               {}
+func (*ReportDiskStatsRequest) Descriptor() ([]byte, []int) { return fileDescriptorStorepb, []int{4} }
+
+func (m *ReportDiskStatsRequest) GetHeader() *pdpb.RequestHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *ReportDiskStatsRequest) GetStats() *DiskStats {
+	if m != nil {
+		return m.Stats
+	}
+	return nil
+}
+
+type ReportDiskStatsResponse struct {
+	Header *pdpb.ResponseHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+}
+
+func (m *ReportDiskStatsResponse) Reset()                    { *m = ReportDiskStatsResponse{} }
+func (m *ReportDiskStatsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ReportDiskStatsResponse) ProtoMessage()               {}
+func (*ReportDiskStatsResponse) Descriptor() ([]byte, []int) { return fileDescriptorStorepb, []int{5} }
+
+func (m *ReportDiskStatsResponse) GetHeader() *pdpb.ResponseHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*StoreInfo)(nil), "storepb.StoreInfo")
+	proto.RegisterType((*GetAllStoresRequest)(nil), "storepb.GetAllStoresRequest")
+	proto.RegisterType((*GetAllStoresResponse)(nil), "storepb.GetAllStoresResponse")
+	proto.RegisterType((*DiskStats)(nil), "storepb.DiskStats")
+	proto.RegisterType((*ReportDiskStatsRequest)(nil), "storepb.ReportDiskStatsRequest")
+	proto.RegisterType((*ReportDiskStatsResponse)(nil), "storepb.ReportDiskStatsResponse")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for Store service
+
+type StoreClient interface {
+	GetAllStores(ctx context.Context, in *GetAllStoresRequest, opts ...grpc.CallOption) (*GetAllStoresResponse, error)
+	ReportDiskStats(ctx context.Context, in *ReportDiskStatsRequest, opts ...grpc.CallOption) (*ReportDiskStatsResponse, error)
+}
+
+type storeClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewStoreClient(cc *grpc.ClientConn) StoreClient {
+	return &storeClient{cc}
+}
+
+func (c *storeClient) GetAllStores(ctx context.Context, in *GetAllStoresRequest, opts ...grpc.CallOption) (*GetAllStoresResponse, error) {
+	out := new(GetAllStoresResponse)
+	err := grpc.Invoke(ctx, "/storepb.Store/GetAllStores", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storeClient) ReportDiskStats(ctx context.Context, in *ReportDiskStatsRequest, opts ...grpc.CallOption) (*ReportDiskStatsResponse, error) {
+	out := new(ReportDiskStatsResponse)
+	err := grpc.Invoke(ctx, "/storepb.Store/ReportDiskStats", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Store service
+
+type StoreServer interface {
+	GetAllStores(context.Context, *GetAllStoresRequest) (*GetAllStoresResponse, error)
+	ReportDiskStats(context.Context, *ReportDiskStatsRequest) (*ReportDiskStatsResponse, error)
+}
+
+func RegisterStoreServer(s *grpc.Server, srv StoreServer) {
+	s.RegisterService(&_Store_serviceDesc, srv)
+}
+
+func _Store_GetAllStores_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAllStoresRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoreServer).GetAllStores(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/storepb.Store/GetAllStores",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoreServer).GetAllStores(ctx, req.(*GetAllStoresRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Store_ReportDiskStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportDiskStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoreServer).ReportDiskStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/storepb.Store/ReportDiskStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoreServer).ReportDiskStats(ctx, req.(*ReportDiskStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Store_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "storepb.Store",
+	HandlerType: (*StoreServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetAllStores",
+			Handler:    _Store_GetAllStores_Handler,
+		},
+		{
+			MethodName: "ReportDiskStats",
+			Handler:    _Store_ReportDiskStats_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "storepb.proto",
+}
+
+func (m *StoreInfo) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StoreInfo) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Store != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStorepb(dAtA, i, uint64(m.Store.Size()))
+		n1, err := m.Store.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	if m.Stats != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintStorepb(dAtA, i, uint64(m.Stats.Size()))
+		n2, err := m.Stats.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n2
+	}
+	if m.LastHeartbeat != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintStorepb(dAtA, i, uint64(m.LastHeartbeat))
+	}
+	if m.LeaderScore != 0 {
+		dAtA[i] = 0x21
+		i++
+		binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.LeaderScore))))
+		i += 8
+	}
+	if m.RegionScore != 0 {
+		dAtA[i] = 0x29
+		i++
+		binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.RegionScore))))
+		i += 8
+	}
+	return i, nil
+}
+
+func (m *GetAllStoresRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetAllStoresRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStorepb(dAtA, i, uint64(m.Header.Size()))
+		n3, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n3
+	}
+	if m.ExcludeTombstoneStores {
+		dAtA[i] = 0x10
+		i++
+		if m.ExcludeTombstoneStores {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.WithStats {
+		dAtA[i] = 0x18
+		i++
+		if m.WithStats {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *GetAllStoresResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetAllStoresResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStorepb(dAtA, i, uint64(m.Header.Size()))
+		n4, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n4
+	}
+	if len(m.Stores) > 0 {
+		for _, msg := range m.Stores {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintStorepb(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *DiskStats) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DiskStats) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.StoreId != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintStorepb(dAtA, i, uint64(m.StoreId))
+	}
+	if m.Interval != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintStorepb(dAtA, i, uint64(m.Interval.Size()))
+		n5, err := m.Interval.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n5
+	}
+	if m.DiskReadBytes != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintStorepb(dAtA, i, uint64(m.DiskReadBytes))
+	}
+	if m.DiskWriteBytes != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintStorepb(dAtA, i, uint64(m.DiskWriteBytes))
+	}
+	if m.PendingCompactionBytes != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintStorepb(dAtA, i, uint64(m.PendingCompactionBytes))
+	}
+	return i, nil
+}
+
+func (m *ReportDiskStatsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReportDiskStatsRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStorepb(dAtA, i, uint64(m.Header.Size()))
+		n6, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n6
+	}
+	if m.Stats != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintStorepb(dAtA, i, uint64(m.Stats.Size()))
+		n7, err := m.Stats.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n7
+	}
+	return i, nil
+}
+
+func (m *ReportDiskStatsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReportDiskStatsResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStorepb(dAtA, i, uint64(m.Header.Size()))
+		n8, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n8
+	}
+	return i, nil
+}
+
+func encodeVarintStorepb(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *StoreInfo) Size() (n int) {
+	var l int
+	_ = l
+	if m.Store != nil {
+		l = m.Store.Size()
+		n += 1 + l + sovStorepb(uint64(l))
+	}
+	if m.Stats != nil {
+		l = m.Stats.Size()
+		n += 1 + l + sovStorepb(uint64(l))
+	}
+	if m.LastHeartbeat != 0 {
+		n += 1 + sovStorepb(uint64(m.LastHeartbeat))
+	}
+	if m.LeaderScore != 0 {
+		n += 9
+	}
+	if m.RegionScore != 0 {
+		n += 9
+	}
+	return n
+}
+
+func (m *GetAllStoresRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovStorepb(uint64(l))
+	}
+	if m.ExcludeTombstoneStores {
+		n += 2
+	}
+	if m.WithStats {
+		n += 2
+	}
+	return n
+}
+
+func (m *GetAllStoresResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovStorepb(uint64(l))
+	}
+	if len(m.Stores) > 0 {
+		for _, e := range m.Stores {
+			l = e.Size()
+			n += 1 + l + sovStorepb(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *DiskStats) Size() (n int) {
+	var l int
+	_ = l
+	if m.StoreId != 0 {
+		n += 1 + sovStorepb(uint64(m.StoreId))
+	}
+	if m.Interval != nil {
+		l = m.Interval.Size()
+		n += 1 + l + sovStorepb(uint64(l))
+	}
+	if m.DiskReadBytes != 0 {
+		n += 1 + sovStorepb(uint64(m.DiskReadBytes))
+	}
+	if m.DiskWriteBytes != 0 {
+		n += 1 + sovStorepb(uint64(m.DiskWriteBytes))
+	}
+	if m.PendingCompactionBytes != 0 {
+		n += 1 + sovStorepb(uint64(m.PendingCompactionBytes))
+	}
+	return n
+}
+
+func (m *ReportDiskStatsRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovStorepb(uint64(l))
+	}
+	if m.Stats != nil {
+		l = m.Stats.Size()
+		n += 1 + l + sovStorepb(uint64(l))
+	}
+	return n
+}
+
+func (m *ReportDiskStatsResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovStorepb(uint64(l))
+	}
+	return n
+}
+
+func sovStorepb(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozStorepb(x uint64) (n int) {
+	return sovStorepb(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *StoreInfo) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStorepb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StoreInfo: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StoreInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Store", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorepb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorepb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Store == nil {
+				m.Store = &metapb.Store{}
+			}
+			if err := m.Store.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stats", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorepb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorepb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Stats == nil {
+				m.Stats = &pdpb.StoreStats{}
+			}
+			if err := m.Stats.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastHeartbeat", wireType)
+			}
+			m.LastHeartbeat = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorepb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LastHeartbeat |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LeaderScore", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.LeaderScore = float64(math.Float64frombits(v))
+		case 5:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RegionScore", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.RegionScore = float64(math.Float64frombits(v))
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStorepb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStorepb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetAllStoresRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStorepb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetAllStoresRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetAllStoresRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorepb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorepb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.RequestHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExcludeTombstoneStores", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorepb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ExcludeTombstoneStores = bool(v != 0)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WithStats", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorepb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.WithStats = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStorepb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStorepb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetAllStoresResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStorepb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetAllStoresResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetAllStoresResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorepb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorepb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.ResponseHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stores", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorepb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorepb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Stores = append(m.Stores, &StoreInfo{})
+			if err := m.Stores[len(m.Stores)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStorepb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStorepb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DiskStats) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStorepb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DiskStats: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DiskStats: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StoreId", wireType)
+			}
+			m.StoreId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorepb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StoreId |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Interval", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorepb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorepb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Interval == nil {
+				m.Interval = &pdpb.TimeInterval{}
+			}
+			if err := m.Interval.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DiskReadBytes", wireType)
+			}
+			m.DiskReadBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorepb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DiskReadBytes |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DiskWriteBytes", wireType)
+			}
+			m.DiskWriteBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorepb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DiskWriteBytes |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PendingCompactionBytes", wireType)
+			}
+			m.PendingCompactionBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorepb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PendingCompactionBytes |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStorepb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStorepb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ReportDiskStatsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStorepb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ReportDiskStatsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ReportDiskStatsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorepb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorepb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.RequestHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stats", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorepb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorepb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Stats == nil {
+				m.Stats = &DiskStats{}
+			}
+			if err := m.Stats.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStorepb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStorepb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ReportDiskStatsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStorepb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ReportDiskStatsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ReportDiskStatsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorepb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorepb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.ResponseHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStorepb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStorepb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipStorepb(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowStorepb
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowStorepb
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+			return iNdEx, nil
+		case 1:
+			iNdEx += 8
+			return iNdEx, nil
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowStorepb
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			iNdEx += length
+			if length < 0 {
+				return 0, ErrInvalidLengthStorepb
+			}
+			return iNdEx, nil
+		case 3:
+			for {
+				var innerWire uint64
+				var start int = iNdEx
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return 0, ErrIntOverflowStorepb
+					}
+					if iNdEx >= l {
+						return 0, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					innerWire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				innerWireType := int(innerWire & 0x7)
+				if innerWireType == 4 {
+					break
+				}
+				next, err := skipStorepb(dAtA[start:])
+				if err != nil {
+					return 0, err
+				}
+				iNdEx = start + next
+			}
+			return iNdEx, nil
+		case 4:
+			return iNdEx, nil
+		case 5:
+			iNdEx += 4
+			return iNdEx, nil
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+	}
+	panic("unreachable")
+}
+
+var (
+	ErrInvalidLengthStorepb = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowStorepb   = fmt.Errorf("proto: integer overflow")
+)
+
+func init() { proto.RegisterFile("storepb.proto", fileDescriptorStorepb) }
+
+var fileDescriptorStorepb = []byte{
+	// 540 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x54, 0xcd, 0x6e, 0xd3, 0x40,
+	0x10, 0x66, 0x49, 0x52, 0x92, 0x49, 0xd2, 0x46, 0xdb, 0x28, 0x84, 0x88, 0x86, 0x60, 0x44, 0x65,
+	0x01, 0x0a, 0x52, 0xb8, 0xf4, 0x4a, 0x41, 0x6a, 0x23, 0x6e, 0x9b, 0x48, 0x1c, 0x2d, 0x3b, 0x3b,
+	0x24, 0x56, 0x1d, 0xaf, 0xf1, 0x6e, 0x29, 0xbc, 0x09, 0x12, 0x6f, 0xc0, 0x7b, 0x20, 0x71, 0xe4,
+	0x11, 0x50, 0x78, 0x11, 0xb4, 0x3f, 0x76, 0x69, 0x68, 0x2f, 0xbd, 0xad, 0xbf, 0xef, 0x9b, 0x99,
+	0x6f, 0x7e, 0x64, 0x68, 0x4b, 0x25, 0x72, 0xcc, 0xa2, 0x71, 0x96, 0x0b, 0x25, 0xe8, 0x3d, 0xf7,
+	0x39, 0x68, 0xad, 0x51, 0x85, 0x05, 0x3c, 0x80, 0x8c, 0x97, 0xef, 0xee, 0x52, 0x2c, 0x85, 0x79,
+	0xbe, 0xd4, 0x2f, 0x8b, 0x7a, 0x3f, 0x08, 0x34, 0x66, 0x3a, 0x76, 0x9a, 0x7e, 0x10, 0xf4, 0x09,
+	0xd4, 0x4c, 0xa2, 0x3e, 0x19, 0x11, 0xbf, 0x39, 0x69, 0x8f, 0x5d, 0x36, 0xa3, 0x60, 0x96, 0xa3,
+	0x87, 0x5a, 0x14, 0x2a, 0xd9, 0xbf, 0x6b, 0x44, 0x9d, 0x71, 0xc6, 0x0b, 0xc9, 0x4c, 0xe3, 0xcc,
+	0xd2, 0xf4, 0x29, 0xec, 0x26, 0xa1, 0x54, 0xc1, 0x0a, 0xc3, 0x5c, 0x45, 0x18, 0xaa, 0x7e, 0x65,
+	0x44, 0xfc, 0x0a, 0x6b, 0x6b, 0xf4, 0xb4, 0x00, 0xe9, 0x63, 0x68, 0x25, 0x18, 0x72, 0xcc, 0x03,
+	0xb9, 0xd0, 0xa5, 0xab, 0x23, 0xe2, 0x13, 0xd6, 0xb4, 0xd8, 0x4c, 0x43, 0x5a, 0x92, 0xe3, 0x32,
+	0x16, 0xa9, 0x93, 0xd4, 0xac, 0xc4, 0x62, 0x46, 0xe2, 0x7d, 0x23, 0xb0, 0x7f, 0x82, 0xea, 0x75,
+	0x92, 0x18, 0x23, 0x92, 0xe1, 0xc7, 0x73, 0x94, 0x8a, 0x3e, 0x87, 0x9d, 0x95, 0xc9, 0xe4, 0x5a,
+	0xda, 0xb7, 0x6e, 0x1d, 0x7d, 0x6a, 0x28, 0xe6, 0x24, 0xf4, 0x08, 0xfa, 0xf8, 0x79, 0x91, 0x9c,
+	0x73, 0x0c, 0x94, 0x58, 0x47, 0x52, 0x89, 0x14, 0x03, 0xd3, 0xb4, 0x6d, 0xb6, 0xce, 0x7a, 0x8e,
+	0x9f, 0x17, 0xb4, 0xad, 0x46, 0x0f, 0x00, 0x2e, 0x62, 0xb5, 0x0a, 0xec, 0x60, 0x2a, 0x46, 0xdb,
+	0xd0, 0x88, 0x99, 0x88, 0x97, 0x41, 0xf7, 0xaa, 0x39, 0x99, 0x89, 0x54, 0x22, 0x7d, 0xb1, 0xe5,
+	0xae, 0x5b, 0xb8, 0xb3, 0xfc, 0x96, 0xbd, 0x67, 0xb0, 0x53, 0x9a, 0xa9, 0xf8, 0xcd, 0x09, 0x1d,
+	0x17, 0x47, 0x50, 0x6e, 0x90, 0x39, 0x85, 0xb7, 0x21, 0xd0, 0x78, 0x1b, 0xcb, 0x33, 0x53, 0x9f,
+	0x3e, 0x80, 0xba, 0xc1, 0x83, 0x98, 0x9b, 0x4a, 0x55, 0x66, 0x0f, 0x66, 0xca, 0xe9, 0x18, 0xea,
+	0x71, 0xaa, 0x30, 0xff, 0x14, 0x26, 0x6e, 0xa1, 0xd4, 0x9a, 0x98, 0xc7, 0x6b, 0x9c, 0x3a, 0x86,
+	0x95, 0x1a, 0x7a, 0x08, 0x7b, 0x3c, 0x96, 0x67, 0x41, 0x8e, 0x21, 0x0f, 0xa2, 0x2f, 0x0a, 0x6d,
+	0xbb, 0x55, 0xd6, 0xd6, 0x30, 0xc3, 0x90, 0x1f, 0x6b, 0x90, 0xfa, 0xd0, 0x31, 0xba, 0x8b, 0x3c,
+	0x56, 0xe8, 0x84, 0x55, 0x23, 0xdc, 0xd5, 0xf8, 0x7b, 0x0d, 0x5b, 0xe5, 0x11, 0xf4, 0x33, 0x4c,
+	0x79, 0x9c, 0x2e, 0x83, 0x85, 0x58, 0x67, 0xe1, 0x42, 0xe9, 0x4d, 0xdb, 0x88, 0x9a, 0x89, 0xe8,
+	0x39, 0xfe, 0x4d, 0x49, 0x9b, 0x48, 0x4f, 0x40, 0x8f, 0x61, 0x26, 0x72, 0x55, 0x76, 0x7a, 0xab,
+	0xb5, 0xfb, 0x57, 0x0f, 0xfa, 0x72, 0xac, 0x97, 0x69, 0xad, 0xc0, 0x3b, 0x81, 0xfb, 0xff, 0x15,
+	0xbc, 0xcd, 0x2a, 0x27, 0xdf, 0x09, 0xd4, 0xcc, 0xd2, 0xe8, 0x3b, 0x68, 0xfd, 0x7b, 0x1a, 0xf4,
+	0x61, 0x59, 0xfd, 0x9a, 0x73, 0x1e, 0x1c, 0xdc, 0xc0, 0x3a, 0x13, 0x73, 0xd8, 0xdb, 0xf2, 0x47,
+	0x1f, 0x95, 0x11, 0xd7, 0x8f, 0x6a, 0x30, 0xba, 0x59, 0x60, 0xb3, 0x1e, 0x77, 0x7e, 0x6e, 0x86,
+	0xe4, 0xd7, 0x66, 0x48, 0x7e, 0x6f, 0x86, 0xe4, 0xeb, 0x9f, 0xe1, 0x9d, 0x68, 0xc7, 0xfc, 0x3c,
+	0x5e, 0xfd, 0x1d, 0x00, 0xb4, 0x1d, 0xfd, 0xce, 0x86, 0x04, 0x00, 0x00,
+}
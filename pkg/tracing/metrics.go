@@ -0,0 +1,39 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	reportedSpanCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "tracing",
+			Name:      "reported_spans_total",
+			Help:      "Counter of the reported spans.",
+		})
+
+	droppedSpanCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "tracing",
+			Name:      "dropped_spans_total",
+			Help:      "Counter of the spans dropped because the queue is full or the collector fails.",
+		})
+)
+
+func init() {
+	prometheus.MustRegister(reportedSpanCounter)
+	prometheus.MustRegister(droppedSpanCounter)
+}
@@ -0,0 +1,219 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// reportQueueSize is the number of finished spans waiting to be reported.
+	// The spans are dropped if the queue is full.
+	reportQueueSize = 4096
+	// reportBatchSize is the max number of spans reported in a request.
+	reportBatchSize = 128
+	reportTimeout   = 10 * time.Second
+)
+
+// Reporter reports the finished spans.
+type Reporter interface {
+	Report(span *Span)
+	// Close reports the pending spans and stops the reporter.
+	Close() error
+}
+
+// zipkinSpan is a span of the Zipkin v2 JSON format, which is also accepted
+// by the Jaeger collector.
+type zipkinSpan struct {
+	TraceID       string             `json:"traceId"`
+	ID            string             `json:"id"`
+	ParentID      string             `json:"parentId,omitempty"`
+	Name          string             `json:"name"`
+	Timestamp     int64              `json:"timestamp"`
+	Duration      int64              `json:"duration"`
+	LocalEndpoint zipkinEndpoint     `json:"localEndpoint"`
+	Tags          map[string]string  `json:"tags,omitempty"`
+	Annotations   []zipkinAnnotation `json:"annotations,omitempty"`
+}
+
+type zipkinEndpoint struct {
+	ServiceName string `json:"serviceName"`
+}
+
+type zipkinAnnotation struct {
+	Timestamp int64  `json:"timestamp"`
+	Value     string `json:"value"`
+}
+
+func toZipkinSpan(s *Span) *zipkinSpan {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	zs := &zipkinSpan{
+		TraceID:       formatID(s.context.TraceID),
+		ID:            formatID(s.context.SpanID),
+		Name:          s.operationName,
+		Timestamp:     s.start.UnixNano() / int64(time.Microsecond),
+		Duration:      int64(s.duration / time.Microsecond),
+		LocalEndpoint: zipkinEndpoint{ServiceName: s.tracer.serviceName},
+	}
+	if s.parentID != 0 {
+		zs.ParentID = formatID(s.parentID)
+	}
+	if len(s.tags) > 0 {
+		zs.Tags = make(map[string]string, len(s.tags))
+		for k, v := range s.tags {
+			zs.Tags[k] = fmt.Sprint(v)
+		}
+	}
+	for _, l := range s.logs {
+		fields := make([]string, 0, len(l.Fields))
+		for _, f := range l.Fields {
+			fields = append(fields, f.String())
+		}
+		zs.Annotations = append(zs.Annotations, zipkinAnnotation{
+			Timestamp: l.Timestamp.UnixNano() / int64(time.Microsecond),
+			Value:     strings.Join(fields, " "),
+		})
+	}
+	return zs
+}
+
+// zipkinReporter posts the spans to a Zipkin compatible collector in batches.
+type zipkinReporter struct {
+	endpoint      string
+	flushInterval time.Duration
+	client        *http.Client
+
+	spans chan *zipkinSpan
+	quit  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// NewZipkinReporter creates a Reporter posting the spans to the endpoint, for
+// example http://jaeger-collector:9411/api/v2/spans.
+func NewZipkinReporter(endpoint string, flushInterval time.Duration) Reporter {
+	r := &zipkinReporter{
+		endpoint:      endpoint,
+		flushInterval: flushInterval,
+		client:        &http.Client{Timeout: reportTimeout},
+		spans:         make(chan *zipkinSpan, reportQueueSize),
+		quit:          make(chan struct{}),
+	}
+	r.wg.Add(1)
+	go r.run()
+	return r
+}
+
+func (r *zipkinReporter) Report(span *Span) {
+	select {
+	case r.spans <- toZipkinSpan(span):
+	default:
+		droppedSpanCounter.Inc()
+	}
+}
+
+func (r *zipkinReporter) Close() error {
+	close(r.quit)
+	r.wg.Wait()
+	return nil
+}
+
+func (r *zipkinReporter) run() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.flushInterval)
+	defer ticker.Stop()
+	batch := make([]*zipkinSpan, 0, reportBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := r.post(batch); err != nil {
+			log.Warnf("failed to report %d spans: %v", len(batch), err)
+			droppedSpanCounter.Add(float64(len(batch)))
+		}
+		batch = batch[:0]
+	}
+	for {
+		select {
+		case span := <-r.spans:
+			batch = append(batch, span)
+			if len(batch) >= reportBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-r.quit:
+			for {
+				select {
+				case span := <-r.spans:
+					batch = append(batch, span)
+					if len(batch) >= reportBatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (r *zipkinReporter) post(spans []*zipkinSpan) error {
+	body, err := json.Marshal(spans)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	resp, err := r.client.Post(r.endpoint, "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return errors.Errorf("collector responded %s", resp.Status)
+	}
+	reportedSpanCounter.Add(float64(len(spans)))
+	return nil
+}
+
+// logReporter writes the spans to the log, which is useful when there is no
+// collector.
+type logReporter struct{}
+
+// NewLogReporter creates a Reporter writing the spans to the log.
+func NewLogReporter() Reporter {
+	return logReporter{}
+}
+
+func (logReporter) Report(span *Span) {
+	data, err := json.Marshal(toZipkinSpan(span))
+	if err != nil {
+		log.Warnf("failed to marshal span: %v", err)
+		return
+	}
+	log.Infof("[span] %s", data)
+	reportedSpanCounter.Inc()
+}
+
+func (logReporter) Close() error { return nil }
@@ -0,0 +1,331 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+)
+
+// B3 headers to propagate the span context, which are understood by both
+// Zipkin and Jaeger.
+const (
+	traceIDHeader = "x-b3-traceid"
+	spanIDHeader  = "x-b3-spanid"
+	sampledHeader = "x-b3-sampled"
+)
+
+// Tracer is an opentracing.Tracer which reports the sampled spans to a
+// Reporter.
+type Tracer struct {
+	serviceName string
+	sampleRate  float64
+	reporter    Reporter
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// NewTracer creates a Tracer. A trace is sampled with the probability of
+// sampleRate when its root span starts.
+func NewTracer(serviceName string, sampleRate float64, reporter Reporter) *Tracer {
+	return &Tracer{
+		serviceName: serviceName,
+		sampleRate:  sampleRate,
+		reporter:    reporter,
+		rnd:         rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (t *Tracer) randomID() uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for {
+		if id := t.rnd.Uint64(); id != 0 {
+			return id
+		}
+	}
+}
+
+func (t *Tracer) sample() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.rnd.Float64() < t.sampleRate
+}
+
+// StartSpan implements opentracing.Tracer.
+func (t *Tracer) StartSpan(operationName string, opts ...opentracing.StartSpanOption) opentracing.Span {
+	var options opentracing.StartSpanOptions
+	for _, opt := range opts {
+		opt.Apply(&options)
+	}
+	span := &Span{
+		tracer:        t,
+		operationName: operationName,
+		start:         options.StartTime,
+		tags:          make(map[string]interface{}, len(options.Tags)),
+	}
+	if span.start.IsZero() {
+		span.start = time.Now()
+	}
+	for k, v := range options.Tags {
+		span.tags[k] = v
+	}
+
+	// The first ChildOf reference is the parent, or the first FollowsFrom
+	// reference if there is no ChildOf reference.
+	var parent *SpanContext
+	for _, ref := range options.References {
+		ctx, ok := ref.ReferencedContext.(SpanContext)
+		if !ok {
+			continue
+		}
+		if ref.Type == opentracing.ChildOfRef {
+			parent = &ctx
+			break
+		}
+		if parent == nil {
+			parent = &ctx
+		}
+	}
+	span.context.SpanID = t.randomID()
+	if parent != nil {
+		span.context.TraceID = parent.TraceID
+		span.context.Sampled = parent.Sampled
+		span.parentID = parent.SpanID
+		span.context.baggage = parent.baggage
+	} else {
+		span.context.TraceID = t.randomID()
+		span.context.Sampled = t.sample()
+	}
+	return span
+}
+
+// Inject implements opentracing.Tracer. The span context is injected as the
+// B3 headers.
+func (t *Tracer) Inject(sm opentracing.SpanContext, format interface{}, carrier interface{}) error {
+	ctx, ok := sm.(SpanContext)
+	if !ok {
+		return opentracing.ErrInvalidSpanContext
+	}
+	writer, ok := carrier.(opentracing.TextMapWriter)
+	if !ok || (format != opentracing.TextMap && format != opentracing.HTTPHeaders) {
+		return opentracing.ErrUnsupportedFormat
+	}
+	writer.Set(traceIDHeader, formatID(ctx.TraceID))
+	writer.Set(spanIDHeader, formatID(ctx.SpanID))
+	if ctx.Sampled {
+		writer.Set(sampledHeader, "1")
+	} else {
+		writer.Set(sampledHeader, "0")
+	}
+	return nil
+}
+
+// Extract implements opentracing.Tracer.
+func (t *Tracer) Extract(format interface{}, carrier interface{}) (opentracing.SpanContext, error) {
+	reader, ok := carrier.(opentracing.TextMapReader)
+	if !ok || (format != opentracing.TextMap && format != opentracing.HTTPHeaders) {
+		return nil, opentracing.ErrUnsupportedFormat
+	}
+	var ctx SpanContext
+	var found int
+	err := reader.ForeachKey(func(key, val string) error {
+		var err error
+		switch strings.ToLower(key) {
+		case traceIDHeader:
+			ctx.TraceID, err = parseID(val)
+			found++
+		case spanIDHeader:
+			ctx.SpanID, err = parseID(val)
+			found++
+		case sampledHeader:
+			ctx.Sampled = val == "1" || val == "true"
+		}
+		if err != nil {
+			return opentracing.ErrSpanContextCorrupted
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if found < 2 {
+		return nil, opentracing.ErrSpanContextNotFound
+	}
+	return ctx, nil
+}
+
+func formatID(id uint64) string {
+	s := strconv.FormatUint(id, 16)
+	return strings.Repeat("0", 16-len(s)) + s
+}
+
+func parseID(s string) (uint64, error) {
+	// Only the lower 64 bits of a 128 bits trace ID are kept.
+	if len(s) > 16 {
+		s = s[len(s)-16:]
+	}
+	return strconv.ParseUint(s, 16, 64)
+}
+
+// SpanContext is the context propagated from a span to its children.
+type SpanContext struct {
+	TraceID uint64
+	SpanID  uint64
+	Sampled bool
+
+	baggage map[string]string
+}
+
+// ForeachBaggageItem implements opentracing.SpanContext.
+func (c SpanContext) ForeachBaggageItem(handler func(k, v string) bool) {
+	for k, v := range c.baggage {
+		if !handler(k, v) {
+			return
+		}
+	}
+}
+
+// Span is an opentracing.Span which is reported when it finishes if the
+// trace is sampled.
+type Span struct {
+	tracer   *Tracer
+	parentID uint64
+
+	mu            sync.Mutex
+	context       SpanContext
+	operationName string
+	start         time.Time
+	duration      time.Duration
+	tags          map[string]interface{}
+	logs          []opentracing.LogRecord
+}
+
+// Finish implements opentracing.Span.
+func (s *Span) Finish() {
+	s.FinishWithOptions(opentracing.FinishOptions{})
+}
+
+// FinishWithOptions implements opentracing.Span.
+func (s *Span) FinishWithOptions(opts opentracing.FinishOptions) {
+	finish := opts.FinishTime
+	if finish.IsZero() {
+		finish = time.Now()
+	}
+	s.mu.Lock()
+	s.duration = finish.Sub(s.start)
+	s.logs = append(s.logs, opts.LogRecords...)
+	for _, ld := range opts.BulkLogData {
+		s.logs = append(s.logs, ld.ToLogRecord())
+	}
+	sampled := s.context.Sampled
+	s.mu.Unlock()
+	if sampled {
+		s.tracer.reporter.Report(s)
+	}
+}
+
+// Context implements opentracing.Span.
+func (s *Span) Context() opentracing.SpanContext {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.context
+}
+
+// SetOperationName implements opentracing.Span.
+func (s *Span) SetOperationName(operationName string) opentracing.Span {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.operationName = operationName
+	return s
+}
+
+// SetTag implements opentracing.Span.
+func (s *Span) SetTag(key string, value interface{}) opentracing.Span {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tags[key] = value
+	return s
+}
+
+// LogFields implements opentracing.Span.
+func (s *Span) LogFields(fields ...log.Field) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.context.Sampled {
+		return
+	}
+	s.logs = append(s.logs, opentracing.LogRecord{Timestamp: time.Now(), Fields: fields})
+}
+
+// LogKV implements opentracing.Span.
+func (s *Span) LogKV(alternatingKeyValues ...interface{}) {
+	fields, err := log.InterleavedKVToFields(alternatingKeyValues...)
+	if err != nil {
+		fields = []log.Field{log.Error(err)}
+	}
+	s.LogFields(fields...)
+}
+
+// SetBaggageItem implements opentracing.Span.
+func (s *Span) SetBaggageItem(restrictedKey, value string) opentracing.Span {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	baggage := make(map[string]string, len(s.context.baggage)+1)
+	for k, v := range s.context.baggage {
+		baggage[k] = v
+	}
+	baggage[restrictedKey] = value
+	s.context.baggage = baggage
+	return s
+}
+
+// BaggageItem implements opentracing.Span.
+func (s *Span) BaggageItem(restrictedKey string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.context.baggage[restrictedKey]
+}
+
+// Tracer implements opentracing.Span.
+func (s *Span) Tracer() opentracing.Tracer {
+	return s.tracer
+}
+
+// LogEvent implements opentracing.Span.
+func (s *Span) LogEvent(event string) {
+	s.Log(opentracing.LogData{Event: event})
+}
+
+// LogEventWithPayload implements opentracing.Span.
+func (s *Span) LogEventWithPayload(event string, payload interface{}) {
+	s.Log(opentracing.LogData{Event: event, Payload: payload})
+}
+
+// Log implements opentracing.Span.
+func (s *Span) Log(data opentracing.LogData) {
+	record := data.ToLogRecord()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.context.Sampled {
+		s.logs = append(s.logs, record)
+	}
+}
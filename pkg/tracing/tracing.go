@@ -0,0 +1,104 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracing implements an OpenTracing tracer which exports the spans in
+// the Zipkin v2 JSON format, so that they can be collected by Jaeger or
+// Zipkin. It is a minimal replacement of jaeger-client-go, which is not
+// vendored, and should be replaced by it once it is.
+package tracing
+
+import (
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pingcap/pd/pkg/typeutil"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// Exporters of the spans.
+const (
+	ExporterZipkin = "zipkin"
+	ExporterLog    = "log"
+)
+
+// DefaultSampleRate is the sample rate if it is not configured. The rate of
+// 0 is a valid config, so it is filled by the caller, which knows whether the
+// rate is configured.
+const DefaultSampleRate = 0.01
+
+const (
+	defaultServiceName   = "pd"
+	defaultExporter      = ExporterZipkin
+	defaultFlushInterval = time.Second
+)
+
+// Config is the tracing configuration.
+type Config struct {
+	Enable      bool   `toml:"enable" json:"enable"`
+	ServiceName string `toml:"service-name" json:"service-name"`
+	// SampleRate is the probability for a trace to be sampled, none of the
+	// traces are sampled if it is 0.
+	SampleRate float64 `toml:"sample-rate" json:"sample-rate"`
+	// Exporter is "zipkin" to post the spans to Endpoint, or "log" to write
+	// them to the log.
+	Exporter      string            `toml:"exporter" json:"exporter"`
+	Endpoint      string            `toml:"endpoint" json:"endpoint"`
+	FlushInterval typeutil.Duration `toml:"flush-interval" json:"flush-interval"`
+}
+
+// Adjust fills the default values except SampleRate and validates the config.
+func (c *Config) Adjust() error {
+	if c.ServiceName == "" {
+		c.ServiceName = defaultServiceName
+	}
+	if c.Exporter == "" {
+		c.Exporter = defaultExporter
+	}
+	if c.FlushInterval.Duration == 0 {
+		c.FlushInterval.Duration = defaultFlushInterval
+	}
+	if c.SampleRate < 0 || c.SampleRate > 1 {
+		return errors.Errorf("tracing sample-rate should be between 0 and 1, got %v", c.SampleRate)
+	}
+	switch c.Exporter {
+	case ExporterZipkin:
+		if c.Enable && c.Endpoint == "" {
+			return errors.New("tracing endpoint is required by the zipkin exporter")
+		}
+	case ExporterLog:
+	default:
+		return errors.Errorf("unknown tracing exporter %q", c.Exporter)
+	}
+	return nil
+}
+
+// Init sets the global tracer by the config. The returned function reports
+// the pending spans and restores the noop tracer.
+func Init(cfg *Config) func() {
+	if !cfg.Enable {
+		return func() {}
+	}
+	var reporter Reporter
+	if cfg.Exporter == ExporterLog {
+		reporter = NewLogReporter()
+	} else {
+		reporter = NewZipkinReporter(cfg.Endpoint, cfg.FlushInterval.Duration)
+	}
+	opentracing.SetGlobalTracer(NewTracer(cfg.ServiceName, cfg.SampleRate, reporter))
+	log.Infof("tracing is enabled, %v of the traces are exported to %s %s", cfg.SampleRate, cfg.Exporter, cfg.Endpoint)
+	return func() {
+		opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+		reporter.Close()
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	. "github.com/pingcap/check"
+)
+
+func TestTracing(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testTracingSuite{})
+
+type testTracingSuite struct{}
+
+type memReporter struct {
+	sync.Mutex
+	spans []*zipkinSpan
+}
+
+func (r *memReporter) Report(span *Span) {
+	r.Lock()
+	defer r.Unlock()
+	r.spans = append(r.spans, toZipkinSpan(span))
+}
+
+func (r *memReporter) Close() error { return nil }
+
+func (s *testTracingSuite) TestSpan(c *C) {
+	reporter := &memReporter{}
+	tracer := NewTracer("pd", 1, reporter)
+
+	root := tracer.StartSpan("root", opentracing.Tag{Key: "region_id", Value: 1})
+	child := tracer.StartSpan("child", opentracing.ChildOf(root.Context()))
+	child.LogKV("operator", "balance-leader")
+	child.Finish()
+	follower := tracer.StartSpan("follower", opentracing.FollowsFrom(child.Context()), opentracing.ChildOf(root.Context()))
+	follower.Finish()
+	root.Finish()
+
+	c.Assert(reporter.spans, HasLen, 3)
+	child2, follower2, root2 := reporter.spans[0], reporter.spans[1], reporter.spans[2]
+	c.Assert(root2.Name, Equals, "root")
+	c.Assert(root2.ParentID, Equals, "")
+	c.Assert(root2.Tags["region_id"], Equals, "1")
+	c.Assert(root2.LocalEndpoint.ServiceName, Equals, "pd")
+	c.Assert(child2.TraceID, Equals, root2.TraceID)
+	c.Assert(child2.ParentID, Equals, root2.ID)
+	c.Assert(child2.Annotations, HasLen, 1)
+	c.Assert(child2.Annotations[0].Value, Equals, "operator:balance-leader")
+	// ChildOf takes precedence over FollowsFrom.
+	c.Assert(follower2.ParentID, Equals, root2.ID)
+
+	// The traces not sampled are not reported.
+	tracer = NewTracer("pd", 0, reporter)
+	root = tracer.StartSpan("root")
+	tracer.StartSpan("child", opentracing.ChildOf(root.Context())).Finish()
+	root.Finish()
+	c.Assert(reporter.spans, HasLen, 3)
+}
+
+func (s *testTracingSuite) TestPropagation(c *C) {
+	tracer := NewTracer("pd", 1, &memReporter{})
+	span := tracer.StartSpan("root")
+	span.SetBaggageItem("k", "v")
+	carrier := opentracing.HTTPHeadersCarrier(http.Header{})
+	c.Assert(tracer.Inject(span.Context(), opentracing.HTTPHeaders, carrier), IsNil)
+	ctx, err := tracer.Extract(opentracing.HTTPHeaders, carrier)
+	c.Assert(err, IsNil)
+	injected := span.Context().(SpanContext)
+	extracted := ctx.(SpanContext)
+	c.Assert(extracted.TraceID, Equals, injected.TraceID)
+	c.Assert(extracted.SpanID, Equals, injected.SpanID)
+	c.Assert(extracted.Sampled, IsTrue)
+
+	_, err = tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(http.Header{}))
+	c.Assert(err, Equals, opentracing.ErrSpanContextNotFound)
+	_, err = tracer.Extract(opentracing.Binary, carrier)
+	c.Assert(err, Equals, opentracing.ErrUnsupportedFormat)
+}
+
+func (s *testTracingSuite) TestZipkinReporter(c *C) {
+	var mu sync.Mutex
+	var received []zipkinSpan
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var spans []zipkinSpan
+		if err := json.NewDecoder(r.Body).Decode(&spans); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		received = append(received, spans...)
+		mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer collector.Close()
+
+	reporter := NewZipkinReporter(collector.URL+"/api/v2/spans", time.Hour)
+	tracer := NewTracer("pd", 1, reporter)
+	for i := 0; i < reportBatchSize+1; i++ {
+		tracer.StartSpan("span").Finish()
+	}
+	// The pending spans are reported when the reporter is closed.
+	c.Assert(reporter.Close(), IsNil)
+	mu.Lock()
+	defer mu.Unlock()
+	c.Assert(received, HasLen, reportBatchSize+1)
+	c.Assert(received[0].Name, Equals, "span")
+}
+
+func (s *testTracingSuite) TestConfig(c *C) {
+	cfg := &Config{}
+	c.Assert(cfg.Adjust(), IsNil)
+	c.Assert(cfg.ServiceName, Equals, defaultServiceName)
+	c.Assert(cfg.SampleRate, Equals, 0.0)
+	c.Assert(cfg.Exporter, Equals, ExporterZipkin)
+	cfg.Enable = true
+	c.Assert(cfg.Adjust(), NotNil)
+	cfg.Endpoint = "http://127.0.0.1:9411/api/v2/spans"
+	c.Assert(cfg.Adjust(), IsNil)
+	cfg.SampleRate = 2
+	c.Assert(cfg.Adjust(), NotNil)
+	cfg.SampleRate = 1
+	cfg.Exporter = "unknown"
+	c.Assert(cfg.Adjust(), NotNil)
+	cfg.Exporter = ExporterLog
+	c.Assert(cfg.Adjust(), IsNil)
+}
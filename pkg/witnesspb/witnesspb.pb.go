@@ -0,0 +1,1325 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: witnesspb.proto
+
+/*
+Package witnesspb is a generated protocol buffer package.
+
+It is generated from these files:
+
+	witnesspb.proto
+
+It has these top-level messages:
+
+	WitnessCommand
+	GetWitnessCommandsRequest
+	GetWitnessCommandsResponse
+	ReportWitnessRequest
+	ReportWitnessResponse
+*/
+package witnesspb
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	proto "github.com/golang/protobuf/proto"
+
+	metapb "github.com/pingcap/kvproto/pkg/metapb"
+
+	pdpb "github.com/pingcap/kvproto/pkg/pdpb"
+
+	_ "github.com/gogo/protobuf/gogoproto"
+
+	context "golang.org/x/net/context"
+
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// WitnessCommand switches the peer to a witness if witness is set, or to a
+// full voter otherwise.
+type WitnessCommand struct {
+	RegionId    uint64              `protobuf:"varint,1,opt,name=region_id,json=regionId,proto3" json:"region_id,omitempty"`
+	RegionEpoch *metapb.RegionEpoch `protobuf:"bytes,2,opt,name=region_epoch,json=regionEpoch" json:"region_epoch,omitempty"`
+	PeerId      uint64              `protobuf:"varint,3,opt,name=peer_id,json=peerId,proto3" json:"peer_id,omitempty"`
+	Witness     bool                `protobuf:"varint,4,opt,name=witness,proto3" json:"witness,omitempty"`
+}
+
+func (m *WitnessCommand) Reset()                    { *m = WitnessCommand{} }
+func (m *WitnessCommand) String() string            { return proto.CompactTextString(m) }
+func (*WitnessCommand) ProtoMessage()               {}
+func (*WitnessCommand) Descriptor() ([]byte, []int) { return fileDescriptorWitnesspb, []int{0} }
+
+func (m *WitnessCommand) GetRegionId() uint64 {
+	if m != nil {
+		return m.RegionId
+	}
+	return 0
+}
+
+func (m *WitnessCommand) GetRegionEpoch() *metapb.RegionEpoch {
+	if m != nil {
+		return m.RegionEpoch
+	}
+	return nil
+}
+
+func (m *WitnessCommand) GetPeerId() uint64 {
+	if m != nil {
+		return m.PeerId
+	}
+	return 0
+}
+
+func (m *WitnessCommand) GetWitness() bool {
+	if m != nil {
+		return m.Witness
+	}
+	return false
+}
+
+type GetWitnessCommandsRequest struct {
+	Header  *pdpb.RequestHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+	StoreId uint64              `protobuf:"varint,2,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
+}
+
+func (m *GetWitnessCommandsRequest) Reset()         { *m = GetWitnessCommandsRequest{} }
+func (m *GetWitnessCommandsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetWitnessCommandsRequest) ProtoMessage()    {}
+func (*GetWitnessCommandsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptorWitnesspb, []int{1}
+}
+
+func (m *GetWitnessCommandsRequest) GetHeader() *pdpb.RequestHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *GetWitnessCommandsRequest) GetStoreId() uint64 {
+	if m != nil {
+		return m.StoreId
+	}
+	return 0
+}
+
+// GetWitnessCommandsResponse returns the switches of the peers on the store,
+// sorted by region ID.
+type GetWitnessCommandsResponse struct {
+	Header   *pdpb.ResponseHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+	Commands []*WitnessCommand    `protobuf:"bytes,2,rep,name=commands" json:"commands,omitempty"`
+}
+
+func (m *GetWitnessCommandsResponse) Reset()         { *m = GetWitnessCommandsResponse{} }
+func (m *GetWitnessCommandsResponse) String() string { return proto.CompactTextString(m) }
+func (*GetWitnessCommandsResponse) ProtoMessage()    {}
+func (*GetWitnessCommandsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptorWitnesspb, []int{2}
+}
+
+func (m *GetWitnessCommandsResponse) GetHeader() *pdpb.ResponseHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *GetWitnessCommandsResponse) GetCommands() []*WitnessCommand {
+	if m != nil {
+		return m.Commands
+	}
+	return nil
+}
+
+type ReportWitnessRequest struct {
+	Header   *pdpb.RequestHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+	RegionId uint64              `protobuf:"varint,2,opt,name=region_id,json=regionId,proto3" json:"region_id,omitempty"`
+	PeerId   uint64              `protobuf:"varint,3,opt,name=peer_id,json=peerId,proto3" json:"peer_id,omitempty"`
+	Witness  bool                `protobuf:"varint,4,opt,name=witness,proto3" json:"witness,omitempty"`
+}
+
+func (m *ReportWitnessRequest) Reset()                    { *m = ReportWitnessRequest{} }
+func (m *ReportWitnessRequest) String() string            { return proto.CompactTextString(m) }
+func (*ReportWitnessRequest) ProtoMessage()               {}
+func (*ReportWitnessRequest) Descriptor() ([]byte, []int) { return fileDescriptorWitnesspb, []int{3} }
+
+func (m *ReportWitnessRequest) GetHeader() *pdpb.RequestHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *ReportWitnessRequest) GetRegionId() uint64 {
+	if m != nil {
+		return m.RegionId
+	}
+	return 0
+}
+
+func (m *ReportWitnessRequest) GetPeerId() uint64 {
+	if m != nil {
+		return m.PeerId
+	}
+	return 0
+}
+
+func (m *ReportWitnessRequest) GetWitness() bool {
+	if m != nil {
+		return m.Witness
+	}
+	return false
+}
+
+type ReportWitnessResponse struct {
+	Header *pdpb.ResponseHeader `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
+}
+
+func (m *ReportWitnessResponse) Reset()                    { *m = ReportWitnessResponse{} }
+func (m *ReportWitnessResponse) String() string            { return proto.CompactTextString(m) }
+func (*ReportWitnessResponse) ProtoMessage()               {}
+func (*ReportWitnessResponse) Descriptor() ([]byte, []int) { return fileDescriptorWitnesspb, []int{4} }
+
+func (m *ReportWitnessResponse) GetHeader() *pdpb.ResponseHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*WitnessCommand)(nil), "witnesspb.WitnessCommand")
+	proto.RegisterType((*GetWitnessCommandsRequest)(nil), "witnesspb.GetWitnessCommandsRequest")
+	proto.RegisterType((*GetWitnessCommandsResponse)(nil), "witnesspb.GetWitnessCommandsResponse")
+	proto.RegisterType((*ReportWitnessRequest)(nil), "witnesspb.ReportWitnessRequest")
+	proto.RegisterType((*ReportWitnessResponse)(nil), "witnesspb.ReportWitnessResponse")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for Witness service
+
+type WitnessClient interface {
+	GetWitnessCommands(ctx context.Context, in *GetWitnessCommandsRequest, opts ...grpc.CallOption) (*GetWitnessCommandsResponse, error)
+	ReportWitness(ctx context.Context, in *ReportWitnessRequest, opts ...grpc.CallOption) (*ReportWitnessResponse, error)
+}
+
+type witnessClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewWitnessClient(cc *grpc.ClientConn) WitnessClient {
+	return &witnessClient{cc}
+}
+
+func (c *witnessClient) GetWitnessCommands(ctx context.Context, in *GetWitnessCommandsRequest, opts ...grpc.CallOption) (*GetWitnessCommandsResponse, error) {
+	out := new(GetWitnessCommandsResponse)
+	err := grpc.Invoke(ctx, "/witnesspb.Witness/GetWitnessCommands", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *witnessClient) ReportWitness(ctx context.Context, in *ReportWitnessRequest, opts ...grpc.CallOption) (*ReportWitnessResponse, error) {
+	out := new(ReportWitnessResponse)
+	err := grpc.Invoke(ctx, "/witnesspb.Witness/ReportWitness", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Witness service
+
+type WitnessServer interface {
+	GetWitnessCommands(context.Context, *GetWitnessCommandsRequest) (*GetWitnessCommandsResponse, error)
+	ReportWitness(context.Context, *ReportWitnessRequest) (*ReportWitnessResponse, error)
+}
+
+func RegisterWitnessServer(s *grpc.Server, srv WitnessServer) {
+	s.RegisterService(&_Witness_serviceDesc, srv)
+}
+
+func _Witness_GetWitnessCommands_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWitnessCommandsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WitnessServer).GetWitnessCommands(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/witnesspb.Witness/GetWitnessCommands",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WitnessServer).GetWitnessCommands(ctx, req.(*GetWitnessCommandsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Witness_ReportWitness_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportWitnessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WitnessServer).ReportWitness(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/witnesspb.Witness/ReportWitness",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WitnessServer).ReportWitness(ctx, req.(*ReportWitnessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Witness_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "witnesspb.Witness",
+	HandlerType: (*WitnessServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetWitnessCommands",
+			Handler:    _Witness_GetWitnessCommands_Handler,
+		},
+		{
+			MethodName: "ReportWitness",
+			Handler:    _Witness_ReportWitness_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "witnesspb.proto",
+}
+
+func (m *WitnessCommand) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WitnessCommand) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.RegionId != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintWitnesspb(dAtA, i, uint64(m.RegionId))
+	}
+	if m.RegionEpoch != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintWitnesspb(dAtA, i, uint64(m.RegionEpoch.Size()))
+		n1, err := m.RegionEpoch.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	if m.PeerId != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintWitnesspb(dAtA, i, uint64(m.PeerId))
+	}
+	if m.Witness {
+		dAtA[i] = 0x20
+		i++
+		if m.Witness {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *GetWitnessCommandsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetWitnessCommandsRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintWitnesspb(dAtA, i, uint64(m.Header.Size()))
+		n2, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n2
+	}
+	if m.StoreId != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintWitnesspb(dAtA, i, uint64(m.StoreId))
+	}
+	return i, nil
+}
+
+func (m *GetWitnessCommandsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetWitnessCommandsResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintWitnesspb(dAtA, i, uint64(m.Header.Size()))
+		n3, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n3
+	}
+	if len(m.Commands) > 0 {
+		for _, msg := range m.Commands {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintWitnesspb(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *ReportWitnessRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReportWitnessRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintWitnesspb(dAtA, i, uint64(m.Header.Size()))
+		n4, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n4
+	}
+	if m.RegionId != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintWitnesspb(dAtA, i, uint64(m.RegionId))
+	}
+	if m.PeerId != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintWitnesspb(dAtA, i, uint64(m.PeerId))
+	}
+	if m.Witness {
+		dAtA[i] = 0x20
+		i++
+		if m.Witness {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *ReportWitnessResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReportWitnessResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Header != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintWitnesspb(dAtA, i, uint64(m.Header.Size()))
+		n5, err := m.Header.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n5
+	}
+	return i, nil
+}
+
+func encodeVarintWitnesspb(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *WitnessCommand) Size() (n int) {
+	var l int
+	_ = l
+	if m.RegionId != 0 {
+		n += 1 + sovWitnesspb(uint64(m.RegionId))
+	}
+	if m.RegionEpoch != nil {
+		l = m.RegionEpoch.Size()
+		n += 1 + l + sovWitnesspb(uint64(l))
+	}
+	if m.PeerId != 0 {
+		n += 1 + sovWitnesspb(uint64(m.PeerId))
+	}
+	if m.Witness {
+		n += 2
+	}
+	return n
+}
+
+func (m *GetWitnessCommandsRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovWitnesspb(uint64(l))
+	}
+	if m.StoreId != 0 {
+		n += 1 + sovWitnesspb(uint64(m.StoreId))
+	}
+	return n
+}
+
+func (m *GetWitnessCommandsResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovWitnesspb(uint64(l))
+	}
+	if len(m.Commands) > 0 {
+		for _, e := range m.Commands {
+			l = e.Size()
+			n += 1 + l + sovWitnesspb(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ReportWitnessRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovWitnesspb(uint64(l))
+	}
+	if m.RegionId != 0 {
+		n += 1 + sovWitnesspb(uint64(m.RegionId))
+	}
+	if m.PeerId != 0 {
+		n += 1 + sovWitnesspb(uint64(m.PeerId))
+	}
+	if m.Witness {
+		n += 2
+	}
+	return n
+}
+
+func (m *ReportWitnessResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovWitnesspb(uint64(l))
+	}
+	return n
+}
+
+func sovWitnesspb(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozWitnesspb(x uint64) (n int) {
+	return sovWitnesspb(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *WitnessCommand) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowWitnesspb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WitnessCommand: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WitnessCommand: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RegionId", wireType)
+			}
+			m.RegionId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWitnesspb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RegionId |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RegionEpoch", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWitnesspb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthWitnesspb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.RegionEpoch == nil {
+				m.RegionEpoch = &metapb.RegionEpoch{}
+			}
+			if err := m.RegionEpoch.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PeerId", wireType)
+			}
+			m.PeerId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWitnesspb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PeerId |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Witness", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWitnesspb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Witness = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipWitnesspb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthWitnesspb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetWitnessCommandsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowWitnesspb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetWitnessCommandsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetWitnessCommandsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWitnesspb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthWitnesspb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.RequestHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StoreId", wireType)
+			}
+			m.StoreId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWitnesspb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StoreId |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipWitnesspb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthWitnesspb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetWitnessCommandsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowWitnesspb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetWitnessCommandsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetWitnessCommandsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWitnesspb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthWitnesspb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.ResponseHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Commands", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWitnesspb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthWitnesspb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Commands = append(m.Commands, &WitnessCommand{})
+			if err := m.Commands[len(m.Commands)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipWitnesspb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthWitnesspb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ReportWitnessRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowWitnesspb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ReportWitnessRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ReportWitnessRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWitnesspb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthWitnesspb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.RequestHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RegionId", wireType)
+			}
+			m.RegionId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWitnesspb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RegionId |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PeerId", wireType)
+			}
+			m.PeerId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWitnesspb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PeerId |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Witness", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWitnesspb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Witness = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipWitnesspb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthWitnesspb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ReportWitnessResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowWitnesspb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ReportWitnessResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ReportWitnessResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowWitnesspb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthWitnesspb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &pdpb.ResponseHeader{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipWitnesspb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthWitnesspb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipWitnesspb(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowWitnesspb
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowWitnesspb
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+			return iNdEx, nil
+		case 1:
+			iNdEx += 8
+			return iNdEx, nil
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowWitnesspb
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			iNdEx += length
+			if length < 0 {
+				return 0, ErrInvalidLengthWitnesspb
+			}
+			return iNdEx, nil
+		case 3:
+			for {
+				var innerWire uint64
+				var start int = iNdEx
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return 0, ErrIntOverflowWitnesspb
+					}
+					if iNdEx >= l {
+						return 0, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					innerWire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				innerWireType := int(innerWire & 0x7)
+				if innerWireType == 4 {
+					break
+				}
+				next, err := skipWitnesspb(dAtA[start:])
+				if err != nil {
+					return 0, err
+				}
+				iNdEx = start + next
+			}
+			return iNdEx, nil
+		case 4:
+			return iNdEx, nil
+		case 5:
+			iNdEx += 4
+			return iNdEx, nil
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+	}
+	panic("unreachable")
+}
+
+var (
+	ErrInvalidLengthWitnesspb = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowWitnesspb   = fmt.Errorf("proto: integer overflow")
+)
+
+func init() { proto.RegisterFile("witnesspb.proto", fileDescriptorWitnesspb) }
+
+var fileDescriptorWitnesspb = []byte{
+	// 374 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x53, 0xc1, 0x4e, 0xf2, 0x40,
+	0x10, 0xfe, 0xb7, 0x10, 0x5a, 0x06, 0x7e, 0x35, 0x2b, 0xc6, 0x52, 0x93, 0xda, 0x34, 0x9a, 0x34,
+	0xd1, 0x60, 0x82, 0xd1, 0x07, 0xd0, 0x10, 0xe5, 0xba, 0x17, 0x8f, 0xa6, 0xd0, 0x09, 0x70, 0xa0,
+	0x5b, 0xbb, 0x35, 0x9e, 0x7d, 0x06, 0x2f, 0x3c, 0x8e, 0x47, 0x8f, 0x3e, 0x82, 0xc1, 0x17, 0x31,
+	0xdd, 0x6e, 0x81, 0x56, 0x88, 0x09, 0xb7, 0x99, 0xf9, 0xa6, 0xdf, 0xf7, 0xed, 0x37, 0x29, 0xec,
+	0xbe, 0x4c, 0x92, 0x10, 0x85, 0x88, 0x06, 0x9d, 0x28, 0xe6, 0x09, 0xa7, 0xf5, 0xc5, 0xc0, 0x6a,
+	0x4e, 0x31, 0xf1, 0x73, 0xc0, 0x82, 0x28, 0x58, 0xd4, 0xad, 0x11, 0x1f, 0x71, 0x59, 0x5e, 0xa4,
+	0x55, 0x36, 0x75, 0x67, 0x04, 0x76, 0x1e, 0xb2, 0xaf, 0x6f, 0xf9, 0x74, 0xea, 0x87, 0x01, 0x3d,
+	0x82, 0x7a, 0x8c, 0xa3, 0x09, 0x0f, 0x1f, 0x27, 0x81, 0x49, 0x1c, 0xe2, 0x55, 0x99, 0x91, 0x0d,
+	0xfa, 0x01, 0xbd, 0x86, 0xa6, 0x02, 0x31, 0xe2, 0xc3, 0xb1, 0xa9, 0x39, 0xc4, 0x6b, 0x74, 0xf7,
+	0x3b, 0x4a, 0x96, 0x49, 0xac, 0x97, 0x42, 0xac, 0x11, 0x2f, 0x1b, 0x7a, 0x08, 0x7a, 0x84, 0x18,
+	0xa7, 0x94, 0x15, 0x49, 0x59, 0x4b, 0xdb, 0x7e, 0x40, 0x4d, 0xd0, 0x95, 0x7b, 0xb3, 0xea, 0x10,
+	0xcf, 0x60, 0x79, 0xeb, 0x0e, 0xa1, 0x7d, 0x87, 0x49, 0xd1, 0x9c, 0x60, 0xf8, 0xf4, 0x8c, 0x22,
+	0xa1, 0x67, 0x50, 0x1b, 0xa3, 0x1f, 0x60, 0x6c, 0x12, 0xe5, 0x40, 0x3e, 0x55, 0xc1, 0xf7, 0x12,
+	0x62, 0x6a, 0x85, 0xb6, 0xc1, 0x10, 0x09, 0x8f, 0x31, 0x55, 0xd7, 0xa4, 0xba, 0x2e, 0xfb, 0x7e,
+	0xe0, 0xbe, 0x12, 0xb0, 0xd6, 0xa9, 0x88, 0x88, 0x87, 0x02, 0xe9, 0x79, 0x49, 0xa6, 0x95, 0xcb,
+	0x64, 0x78, 0x49, 0xe7, 0x0a, 0x8c, 0xa1, 0x62, 0x30, 0x35, 0xa7, 0xe2, 0x35, 0xba, 0xed, 0xce,
+	0xf2, 0x56, 0x45, 0x0d, 0xb6, 0x58, 0x75, 0xdf, 0x08, 0xb4, 0x18, 0x46, 0x3c, 0xce, 0x6d, 0x6c,
+	0xf5, 0xc8, 0xc2, 0xd9, 0xb4, 0xd2, 0xd9, 0xb6, 0x88, 0xbf, 0x07, 0x07, 0x25, 0x53, 0xdb, 0x64,
+	0xd2, 0x7d, 0x27, 0xa0, 0x2b, 0x06, 0xea, 0x03, 0xfd, 0x9d, 0x35, 0x3d, 0x59, 0xc9, 0x68, 0xe3,
+	0xc1, 0xad, 0xd3, 0x3f, 0xb6, 0x94, 0x39, 0x06, 0xff, 0x0b, 0xae, 0xe9, 0xf1, 0xca, 0x77, 0xeb,
+	0x42, 0xb6, 0x9c, 0xcd, 0x0b, 0x19, 0xe7, 0xcd, 0xde, 0xc7, 0xdc, 0x26, 0x9f, 0x73, 0x9b, 0x7c,
+	0xcd, 0x6d, 0x32, 0xfb, 0xb6, 0xff, 0x0d, 0x6a, 0xf2, 0xe7, 0xb9, 0xfc, 0x19, 0x00, 0x05, 0xd3,
+	0x3a, 0x45, 0x8a, 0x03, 0x00, 0x00,
+}
@@ -0,0 +1,77 @@
+#!/usr/bin/env bash
+# Generates pkg/<name>/<name>.pb.go for every proto/<name>.proto, the same way
+# as kvproto's generate_go.sh. The kvproto protos are imported from the
+# kvproto checkout under GOPATH, which must match the version in go.mod.
+
+cmd_exists () {
+    which "$1" 1>/dev/null 2>&1
+}
+
+PROGRAM=$(basename "$0")
+
+if [ -z $GOPATH ]; then
+    printf "Error: the environment variable GOPATH is not set, please set it before running %s\n" $PROGRAM > /dev/stderr
+    exit 1
+fi
+
+if ! cmd_exists protoc; then
+    printf "Error: protoc 3.1.x or newer is needed to run %s\n" $PROGRAM > /dev/stderr
+    exit 1
+fi
+
+GO_PREFIX_PATH=github.com/pingcap/pd/pkg
+KVPROTO_PREFIX_PATH=github.com/pingcap/kvproto/pkg
+
+gogo_protobuf_url=github.com/gogo/protobuf
+GOGO_ROOT=${GOPATH}/src/${gogo_protobuf_url}
+KVPROTO_ROOT=${GOPATH}/src/github.com/pingcap/kvproto
+GO_OUT_M="Meraftpb.proto=$KVPROTO_PREFIX_PATH/eraftpb,Mmetapb.proto=$KVPROTO_PREFIX_PATH/metapb,Mpdpb.proto=$KVPROTO_PREFIX_PATH/pdpb"
+GO_INSTALL='go install'
+
+echo "install gogoproto code/generator ..."
+${GO_INSTALL} ${gogo_protobuf_url}/proto
+${GO_INSTALL} ${gogo_protobuf_url}/protoc-gen-gofast
+${GO_INSTALL} ${gogo_protobuf_url}/gogoproto
+
+echo "install goimports ..."
+${GO_INSTALL} golang.org/x/tools/cmd/goimports
+
+# add the bin path of gogoproto generator into PATH if it's missing
+if ! cmd_exists protoc-gen-gofast; then
+    for path in $(echo "${GOPATH}" | sed -e 's/:/ /g'); do
+        gogo_proto_bin="${path}/bin/protoc-gen-gofast"
+        if [ -e "${gogo_proto_bin}" ]; then
+            export PATH=$(dirname "${gogo_proto_bin}"):$PATH
+            break
+        fi
+    done
+fi
+
+cd $(dirname "$0")
+for file in `ls *.proto`
+    do
+    base_name=$(basename $file ".proto")
+    GO_OUT_M="$GO_OUT_M,M$file=$GO_PREFIX_PATH/$base_name"
+done
+
+echo "generate go code..."
+ret=0
+
+function gen() {
+    base_name=$(basename $1 ".proto")
+    mkdir -p ../pkg/$base_name
+    protoc -I.:${GOGO_ROOT}:${GOGO_ROOT}/protobuf:${KVPROTO_ROOT}/proto:${KVPROTO_ROOT}/include --gofast_out=plugins=grpc,$GO_OUT_M:../pkg/$base_name $1 || ret=$?
+    cd ../pkg/$base_name
+    sed -i.bak -E 's/import fmt \"fmt\"//g' *.pb.go
+    sed -i.bak -E 's/import io \"io\"//g' *.pb.go
+    sed -i.bak -E 's/import math \"math\"//g' *.pb.go
+    rm -f *.bak
+    goimports -w *.pb.go
+    cd ../../proto
+}
+
+for file in `ls *.proto`
+    do
+    gen $file
+done
+exit $ret
@@ -18,7 +18,9 @@ import (
 	"strconv"
 
 	"github.com/gorilla/mux"
+	"github.com/pingcap/pd/pkg/apiutil"
 	"github.com/pingcap/pd/server"
+	"github.com/pkg/errors"
 	"github.com/unrolled/render"
 )
 
@@ -37,7 +39,7 @@ func newAdminHandler(svr *server.Server, rd *render.Render) *adminHandler {
 func (h *adminHandler) HandleDropCacheRegion(w http.ResponseWriter, r *http.Request) {
 	cluster := h.svr.GetRaftCluster()
 	if cluster == nil {
-		h.rd.JSON(w, http.StatusInternalServerError, server.ErrNotBootstrapped.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
 		return
 	}
 
@@ -51,3 +53,70 @@ func (h *adminHandler) HandleDropCacheRegion(w http.ResponseWriter, r *http.Requ
 	cluster.DropCacheRegion(regionID)
 	h.rd.JSON(w, http.StatusOK, nil)
 }
+
+func (h *adminHandler) GetJanitorReports(w http.ResponseWriter, r *http.Request) {
+	cluster := h.svr.GetRaftCluster()
+	if cluster == nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, cluster.GetJanitorReports())
+}
+
+func (h *adminHandler) CreateMetaSnapshot(w http.ResponseWriter, r *http.Request) {
+	info, err := h.svr.CreateMetaSnapshot()
+	if err != nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, info)
+}
+
+func (h *adminHandler) ListMetaSnapshots(w http.ResponseWriter, r *http.Request) {
+	infos, err := h.svr.GetMetaSnapshots()
+	if err != nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, infos)
+}
+
+func (h *adminHandler) RestoreMetaSnapshot(w http.ResponseWriter, r *http.Request) {
+	if err := h.svr.RestoreMetaSnapshot(mux.Vars(r)["name"]); err != nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, nil)
+}
+
+func (h *adminHandler) GetEtcdStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := h.svr.GetEtcdMaintenanceStatus()
+	if err != nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, status)
+}
+
+func (h *adminHandler) CompactEtcd(w http.ResponseWriter, r *http.Request) {
+	rev, err := h.svr.CompactEtcd()
+	if err != nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, rev)
+}
+
+func (h *adminHandler) DefragEtcdMember(w http.ResponseWriter, r *http.Request) {
+	err := h.svr.DefragEtcdMember(mux.Vars(r)["name"])
+	switch errors.Cause(err) {
+	case nil:
+		h.rd.JSON(w, http.StatusOK, nil)
+	case server.ErrEtcdMemberNotFound:
+		h.rd.JSON(w, http.StatusNotFound, err.Error())
+	case server.ErrDefragLeader, server.ErrDefragUnhealthy:
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+	default:
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
+	}
+}
@@ -14,6 +14,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
@@ -52,7 +53,7 @@ func (s *testAdminSuite) TestDropRegion(c *C) {
 		core.SetRegionConfVer(100),
 		core.SetRegionVersion(100),
 	)
-	err := cluster.HandleRegionHeartbeat(region)
+	err := cluster.HandleRegionHeartbeat(context.Background(), region)
 	c.Assert(err, IsNil)
 
 	// Region epoch cannot decrease.
@@ -60,7 +61,7 @@ func (s *testAdminSuite) TestDropRegion(c *C) {
 		core.SetRegionConfVer(50),
 		core.SetRegionVersion(50),
 	)
-	err = cluster.HandleRegionHeartbeat(region)
+	err = cluster.HandleRegionHeartbeat(context.Background(), region)
 	c.Assert(err, NotNil)
 
 	// After drop region from cache, lower version is accepted.
@@ -71,10 +72,37 @@ func (s *testAdminSuite) TestDropRegion(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(res.StatusCode, Equals, http.StatusOK)
 	res.Body.Close()
-	err = cluster.HandleRegionHeartbeat(region)
+	err = cluster.HandleRegionHeartbeat(context.Background(), region)
 	c.Assert(err, IsNil)
 
 	region = cluster.GetRegionInfoByKey([]byte("foo"))
 	c.Assert(region.GetRegionEpoch().ConfVer, Equals, uint64(50))
 	c.Assert(region.GetRegionEpoch().Version, Equals, uint64(50))
 }
+
+func (s *testAdminSuite) TestEtcdMaintenance(c *C) {
+	var status server.EtcdMaintenanceStatus
+	err := readJSONWithURL(s.urlPrefix+"/admin/etcd", &status)
+	c.Assert(err, IsNil)
+	c.Assert(status.Members, HasLen, 1)
+	c.Assert(status.Members[0].Name, Equals, s.svr.Name())
+	c.Assert(status.Members[0].IsLeader, IsTrue)
+
+	res, err := http.Post(s.urlPrefix+"/admin/etcd/compact", "", nil)
+	c.Assert(err, IsNil)
+	c.Assert(res.StatusCode, Equals, http.StatusOK)
+	res.Body.Close()
+	err = readJSONWithURL(s.urlPrefix+"/admin/etcd", &status)
+	c.Assert(err, IsNil)
+	c.Assert(status.CompactRevision, Greater, int64(0))
+
+	// The leader is never defragmented.
+	res, err = http.Post(fmt.Sprintf("%s/admin/etcd/defrag/%s", s.urlPrefix, s.svr.Name()), "", nil)
+	c.Assert(err, IsNil)
+	c.Assert(res.StatusCode, Equals, http.StatusBadRequest)
+	res.Body.Close()
+	res, err = http.Post(s.urlPrefix+"/admin/etcd/defrag/unknown", "", nil)
+	c.Assert(err, IsNil)
+	c.Assert(res.StatusCode, Equals, http.StatusNotFound)
+	res.Body.Close()
+}
@@ -16,6 +16,7 @@ package api
 import (
 	"net/http"
 
+	"github.com/pingcap/pd/pkg/apiutil"
 	"github.com/pingcap/pd/server"
 	"github.com/pkg/errors"
 	"github.com/unrolled/render"
@@ -44,7 +45,7 @@ func newClassifierHandler(svr *server.Server, rd *render.Render, prefix string)
 func (h *classifierHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	cluster := h.svr.GetRaftCluster()
 	if cluster == nil {
-		h.rd.JSON(w, http.StatusInternalServerError, server.ErrNotBootstrapped.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
 		return
 	}
 	classifier := cluster.GetNamespaceClassifier()
@@ -16,6 +16,8 @@ package api
 import (
 	"net/http"
 
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/pkg/apiutil"
 	"github.com/pingcap/pd/server"
 	"github.com/unrolled/render"
 )
@@ -39,8 +41,22 @@ func (h *clusterHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 func (h *clusterHandler) GetClusterStatus(w http.ResponseWriter, r *http.Request) {
 	status, err := h.svr.GetClusterStatus()
 	if err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 		return
 	}
 	h.rd.JSON(w, http.StatusOK, status)
 }
+
+// PrepareBootstrap checks whether the cluster can be bootstrapped with the
+// store and region in the body, without bootstrapping it.
+func (h *clusterHandler) PrepareBootstrap(w http.ResponseWriter, r *http.Request) {
+	req := &pdpb.BootstrapRequest{}
+	if err := readJSONRespondError(h.rd, w, r.Body, req); err != nil {
+		return
+	}
+	if err := h.svr.PrepareBootstrap(req); err != nil {
+		errorResp(h.rd, w, err)
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, nil)
+}
@@ -14,11 +14,13 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/pd/server"
 )
 
@@ -69,4 +71,34 @@ func (s *testClusterInfo) TestGetClusterStatus(c *C) {
 	err = readJSONWithURL(url, &status)
 	c.Assert(err, IsNil)
 	c.Assert(status.RaftBootstrapTime.After(now), IsTrue)
+	c.Assert(status.Leader.GetName(), Equals, s.svr.Name())
+	// The store has not sent heartbeats.
+	c.Assert(status.StoreStates, DeepEquals, map[string]int{"Down": 1})
+	c.Assert(status.ReplicationMode.Mode, Equals, "majority")
+	// The bootstrapped region has not been reported by heartbeat.
+	c.Assert(status.IsPrepared, IsFalse)
+	c.Assert(status.RegionLoadProgress, Equals, float64(0))
+}
+
+func (s *testClusterInfo) TestPrepareBootstrap(c *C) {
+	svr, cleanup := mustNewServer(c)
+	defer cleanup()
+	mustWaitLeader(c, []*server.Server{svr})
+	url := fmt.Sprintf("%s%s/api/v1/cluster/bootstrap/prepare", svr.GetAddr(), apiPrefix)
+
+	req := &pdpb.BootstrapRequest{Header: newRequestHeader(svr.ClusterID()), Store: store, Region: region}
+	data, err := json.Marshal(req)
+	c.Assert(err, IsNil)
+	c.Assert(postJSON(url, data), IsNil)
+
+	req.Store = &metapb.Store{Id: store.GetId()}
+	data, err = json.Marshal(req)
+	c.Assert(err, IsNil)
+	c.Assert(postJSON(url, data), ErrorMatches, "(?s).*missing store address.*")
+
+	mustBootstrapCluster(c, svr)
+	req.Store = store
+	data, err = json.Marshal(req)
+	c.Assert(err, IsNil)
+	c.Assert(postJSON(url, data), ErrorMatches, "(?s).*state.already_bootstrapped.*")
 }
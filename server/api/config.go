@@ -21,6 +21,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/pingcap/errcode"
+	"github.com/pingcap/pd/pkg/apiutil"
 	"github.com/pingcap/pd/server"
 	"github.com/pkg/errors"
 	"github.com/unrolled/render"
@@ -47,23 +48,31 @@ func (h *confHandler) Post(w http.ResponseWriter, r *http.Request) {
 	data, err := ioutil.ReadAll(r.Body)
 	r.Body.Close()
 	if err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 		return
 	}
 	if err := json.Unmarshal(data, &config.Schedule); err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 		return
 	}
 	if err := json.Unmarshal(data, &config.Replication); err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
+		return
+	}
+	if err := json.Unmarshal(data, &config.PDServerCfg); err != nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 		return
 	}
 	if err := h.svr.SetScheduleConfig(config.Schedule); err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 		return
 	}
 	if err := h.svr.SetReplicationConfig(config.Replication); err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
+		return
+	}
+	if err := h.svr.SetPDServerConfig(config.PDServerCfg); err != nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 		return
 	}
 	h.rd.JSON(w, http.StatusOK, nil)
@@ -80,7 +89,7 @@ func (h *confHandler) SetSchedule(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.svr.SetScheduleConfig(*config); err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 		return
 	}
 	h.rd.JSON(w, http.StatusOK, nil)
@@ -96,7 +105,10 @@ func (h *confHandler) SetReplication(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.svr.SetReplicationConfig(*config)
+	if err := h.svr.SetReplicationConfig(*config); err != nil {
+		errorResp(h.rd, w, err)
+		return
+	}
 	h.rd.JSON(w, http.StatusOK, nil)
 }
 
@@ -161,10 +173,49 @@ func (h *confHandler) SetLabelProperty(w http.ResponseWriter, r *http.Request) {
 	case "delete":
 		err = h.svr.DeleteLabelProperty(input["type"], input["label-key"], input["label-value"])
 	default:
-		err = errors.Errorf("unknown action %v", input["action"])
+		h.rd.JSON(w, http.StatusBadRequest, fmt.Sprintf("unknown action %v", input["action"]))
+		return
+	}
+	if errors.Cause(err) == server.ErrStoreLabelNotFound {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
 	}
 	if err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, nil)
+}
+
+func (h *confHandler) GetMergeProtection(w http.ResponseWriter, r *http.Request) {
+	h.rd.JSON(w, http.StatusOK, h.svr.GetMergeProtection())
+}
+
+func (h *confHandler) SetMergeProtectionRule(w http.ResponseWriter, r *http.Request) {
+	var rule server.MergeProtectionRule
+	if err := readJSONRespondError(h.rd, w, r.Body, &rule); err != nil {
+		return
+	}
+	err := h.svr.SetMergeProtectionRule(mux.Vars(r)["name"], &rule)
+	if errors.Cause(err) == server.ErrInvalidMergeProtectionRule {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, nil)
+}
+
+func (h *confHandler) DeleteMergeProtectionRule(w http.ResponseWriter, r *http.Request) {
+	err := h.svr.DeleteMergeProtectionRule(mux.Vars(r)["name"])
+	if errors.Cause(err) == server.ErrMergeProtectionRuleNotFound {
+		h.rd.JSON(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 		return
 	}
 	h.rd.JSON(w, http.StatusOK, nil)
@@ -174,6 +225,10 @@ func (h *confHandler) GetClusterVersion(w http.ResponseWriter, r *http.Request)
 	h.rd.JSON(w, http.StatusOK, h.svr.GetClusterVersion())
 }
 
+func (h *confHandler) GetFeatures(w http.ResponseWriter, r *http.Request) {
+	h.rd.JSON(w, http.StatusOK, h.svr.GetFeatureStatuses())
+}
+
 func (h *confHandler) SetClusterVersion(w http.ResponseWriter, r *http.Request) {
 	input := make(map[string]string)
 	if err := readJSONRespondError(h.rd, w, r.Body, &input); err != nil {
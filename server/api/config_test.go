@@ -14,11 +14,14 @@
 package api
 
 import (
+	"bytes"
 	"encoding/json"
 	"math/rand"
+	"net/http"
 	"time"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/pd/server"
 )
 
@@ -126,6 +129,12 @@ func (s *testConfigSuite) TestConfigReplication(c *C) {
 	c.Assert(err, IsNil)
 
 	c.Assert(*rc, DeepEquals, *rc3)
+
+	// The invalid config is rejected as a bad request.
+	resp, err = server.DialClient.Post(postAddr, "application/json", bytes.NewBufferString(`{"isolation-level": "host"}`))
+	c.Assert(err, IsNil)
+	resp.Body.Close()
+	c.Assert(resp.StatusCode, Equals, http.StatusBadRequest)
 }
 
 func (s *testConfigSuite) TestConfigLabelProperty(c *C) {
@@ -143,6 +152,18 @@ func (s *testConfigSuite) TestConfigLabelProperty(c *C) {
 	cfg := loadProperties()
 	c.Assert(cfg, HasLen, 0)
 
+	// The labels are not checked before the cluster is bootstrapped.
+	err := postJSON(addr, []byte(`{"type": "foo", "action": "set", "label-key": "zone", "label-value": "cn1"}`))
+	c.Assert(err, IsNil)
+	c.Assert(loadProperties(), HasLen, 1)
+	// The labels of label properties should be on stores.
+	leader := mustWaitLeader(c, s.servers)
+	mustBootstrapCluster(c, leader)
+	mustPutStore(c, leader, 2, metapb.StoreState_Up, []*metapb.StoreLabel{{Key: "zone", Value: "cn1"}, {Key: "host", Value: "h1"}})
+	mustPutStore(c, leader, 3, metapb.StoreState_Up, []*metapb.StoreLabel{{Key: "zone", Value: "cn2"}})
+	err = postJSON(addr, []byte(`{"type": "foo", "action": "set", "label-key": "zone", "label-value": "cn3"}`))
+	c.Assert(err, NotNil)
+
 	cmds := []string{
 		`{"type": "foo", "action": "set", "label-key": "zone", "label-value": "cn1"}`,
 		`{"type": "foo", "action": "set", "label-key": "zone", "label-value": "cn2"}`,
@@ -172,3 +193,64 @@ func (s *testConfigSuite) TestConfigLabelProperty(c *C) {
 	c.Assert(cfg, HasLen, 1)
 	c.Assert(cfg["foo"], DeepEquals, []server.StoreLabel{{Key: "zone", Value: "cn2"}})
 }
+
+func (s *testConfigSuite) TestConfigMergeProtection(c *C) {
+	addr := s.servers[0].GetAddr() + apiPrefix + "/api/v1/config/merge-protection"
+
+	loadRules := func() server.MergeProtectionConfig {
+		res, err := doGet(addr)
+		c.Assert(err, IsNil)
+		var cfg server.MergeProtectionConfig
+		err = readJSON(res.Body, &cfg)
+		c.Assert(err, IsNil)
+		return cfg
+	}
+
+	c.Assert(loadRules(), HasLen, 0)
+
+	invalid := []string{
+		`{"interval": "1h", "start-key": "zz"}`,
+		`{"interval": "1h", "start-key": "62", "end-key": "61"}`,
+		`{"interval": "1h", "start-key": "61", "table-id": 10}`,
+		`{"interval": "0s", "table-id": 10}`,
+	}
+	for _, rule := range invalid {
+		c.Assert(postJSON(addr+"/foo", []byte(rule)), NotNil)
+	}
+	c.Assert(postJSON(addr+"/foo", []byte(`{"interval": "1h", "start-key": "61", "end-key": "62"}`)), IsNil)
+	c.Assert(postJSON(addr+"/bar", []byte(`{"interval": "10m", "table-id": 10}`)), IsNil)
+	cfg := loadRules()
+	c.Assert(cfg, HasLen, 2)
+	c.Assert(cfg["foo"].Interval.Duration, Equals, time.Hour)
+	c.Assert(cfg["bar"].TableID, Equals, int64(10))
+
+	c.Assert(doDelete(addr+"/foo"), IsNil)
+	cfg = loadRules()
+	c.Assert(cfg, HasLen, 1)
+	_, ok := cfg["foo"]
+	c.Assert(ok, IsFalse)
+}
+
+func (s *testConfigSuite) TestFeatures(c *C) {
+	addr := s.cfgs[rand.Intn(len(s.cfgs))].ClientUrls + apiPrefix + "/api/v1/config/cluster-version/features"
+	resp, err := doGet(addr)
+	c.Assert(err, IsNil)
+	var statuses []*server.FeatureStatus
+	err = readJSON(resp.Body, &statuses)
+	c.Assert(err, IsNil)
+	c.Assert(statuses, HasLen, len(s.servers[0].GetFeatureStatuses()))
+	for _, status := range statuses {
+		c.Assert(status.Name, Not(Equals), "")
+		c.Assert(status.Enabled, Equals, s.servers[0].IsFeatureSupported(featureByName(c, status.Name)))
+	}
+}
+
+func featureByName(c *C, name string) server.Feature {
+	for f := server.Base; f <= server.BatchSplit; f++ {
+		if f.String() == name {
+			return f
+		}
+	}
+	c.Fatalf("unknown feature %s", name)
+	return server.Base
+}
@@ -0,0 +1,256 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/pkg/apiutil"
+	"github.com/pingcap/pd/pkg/typeutil"
+	"github.com/pingcap/pd/server"
+	"github.com/pingcap/pd/server/core"
+	"github.com/unrolled/render"
+)
+
+const (
+	defaultHotRegionLimit = 10
+	maxHotRegionLimit     = 100
+)
+
+// dashboardHandler serves the data aggregated for dashboards, so that a UI
+// does not need to scrape metrics and join them.
+type dashboardHandler struct {
+	*server.Handler
+	svr *server.Server
+	rd  *render.Render
+}
+
+func newDashboardHandler(svr *server.Server, rd *render.Render) *dashboardHandler {
+	return &dashboardHandler{
+		Handler: svr.GetHandler(),
+		svr:     svr,
+		rd:      rd,
+	}
+}
+
+type topologyStore struct {
+	ID          uint64            `json:"id"`
+	Address     string            `json:"address"`
+	StateName   string            `json:"state_name"`
+	Capacity    typeutil.ByteSize `json:"capacity"`
+	Available   typeutil.ByteSize `json:"available"`
+	RegionCount int               `json:"region_count"`
+	LeaderCount int               `json:"leader_count"`
+}
+
+// topologyNode is the stores with the same value of a location label. The
+// nodes of the last location label have stores, the others have children.
+type topologyNode struct {
+	Label       string            `json:"label"`
+	Value       string            `json:"value"`
+	StoreCount  int               `json:"store_count"`
+	Capacity    typeutil.ByteSize `json:"capacity"`
+	Available   typeutil.ByteSize `json:"available"`
+	RegionCount int               `json:"region_count"`
+	LeaderCount int               `json:"leader_count"`
+	Children    []*topologyNode   `json:"children,omitempty"`
+	Stores      []*topologyStore  `json:"stores,omitempty"`
+}
+
+type topology struct {
+	LocationLabels []string         `json:"location_labels"`
+	Nodes          []*topologyNode  `json:"nodes,omitempty"`
+	Stores         []*topologyStore `json:"stores,omitempty"`
+}
+
+// buildTopology groups the stores by the values of the first label, and the
+// stores of each group by the rest labels.
+func buildTopology(labels []string, stores []*core.StoreInfo, infos map[uint64]*topologyStore) []*topologyNode {
+	groups := make(map[string][]*core.StoreInfo)
+	for _, store := range stores {
+		value := store.GetLabelValue(labels[0])
+		groups[value] = append(groups[value], store)
+	}
+	nodes := make([]*topologyNode, 0, len(groups))
+	for value, group := range groups {
+		node := &topologyNode{Label: labels[0], Value: value}
+		for _, store := range group {
+			info := infos[store.GetId()]
+			node.StoreCount++
+			node.Capacity += info.Capacity
+			node.Available += info.Available
+			node.RegionCount += info.RegionCount
+			node.LeaderCount += info.LeaderCount
+			if len(labels) == 1 {
+				node.Stores = append(node.Stores, info)
+			}
+		}
+		if len(labels) > 1 {
+			node.Children = buildTopology(labels[1:], group, infos)
+		}
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Value < nodes[j].Value })
+	return nodes
+}
+
+func (h *dashboardHandler) GetTopology(w http.ResponseWriter, r *http.Request) {
+	stores, err := h.GetStores()
+	if err != nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
+		return
+	}
+	cfg := h.svr.GetScheduleConfig()
+	var upStores []*core.StoreInfo
+	infos := make(map[uint64]*topologyStore, len(stores))
+	for _, store := range stores {
+		if store.IsTombstone() {
+			continue
+		}
+		info := newStoreInfo(cfg, store)
+		infos[store.GetId()] = &topologyStore{
+			ID:          store.GetId(),
+			Address:     store.GetAddress(),
+			StateName:   info.Store.StateName,
+			Capacity:    info.Status.Capacity,
+			Available:   info.Status.Available,
+			RegionCount: info.Status.RegionCount,
+			LeaderCount: info.Status.LeaderCount,
+		}
+		upStores = append(upStores, store)
+	}
+	sort.Slice(upStores, func(i, j int) bool { return upStores[i].GetId() < upStores[j].GetId() })
+
+	result := &topology{LocationLabels: h.svr.GetReplicationConfig().LocationLabels}
+	if len(result.LocationLabels) == 0 {
+		for _, store := range upStores {
+			result.Stores = append(result.Stores, infos[store.GetId()])
+		}
+	} else {
+		result.Nodes = buildTopology(result.LocationLabels, upStores, infos)
+	}
+	h.rd.JSON(w, http.StatusOK, result)
+}
+
+type storeFlowTrend struct {
+	StoreID uint64                   `json:"store_id"`
+	Address string                   `json:"address"`
+	Samples []server.StoreFlowSample `json:"samples"`
+}
+
+func (h *dashboardHandler) GetStoreFlowTrends(w http.ResponseWriter, r *http.Request) {
+	cluster := h.svr.GetRaftCluster()
+	if cluster == nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
+		return
+	}
+	var storeID uint64
+	if s := r.URL.Query().Get("store_id"); s != "" {
+		var err error
+		if storeID, err = strconv.ParseUint(s, 10, 64); err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	trends := []*storeFlowTrend{}
+	for _, store := range cluster.GetStores() {
+		if (storeID != 0 && store.GetId() != storeID) || store.GetState() == metapb.StoreState_Tombstone {
+			continue
+		}
+		samples := cluster.GetStoreFlowTrend(store.GetId())
+		if samples == nil {
+			samples = []server.StoreFlowSample{}
+		}
+		trends = append(trends, &storeFlowTrend{
+			StoreID: store.GetId(),
+			Address: store.GetAddress(),
+			Samples: samples,
+		})
+	}
+	sort.Slice(trends, func(i, j int) bool { return trends[i].StoreID < trends[j].StoreID })
+	h.rd.JSON(w, http.StatusOK, trends)
+}
+
+type hotRegion struct {
+	RegionID  uint64 `json:"region_id"`
+	StoreID   uint64 `json:"store_id"`
+	FlowBytes uint64 `json:"flow_bytes"`
+	HotDegree int    `json:"hot_degree"`
+	StartKey  string `json:"start_key"`
+	EndKey    string `json:"end_key"`
+}
+
+func (h *dashboardHandler) GetTopHotRegions(w http.ResponseWriter, r *http.Request) {
+	cluster := h.svr.GetRaftCluster()
+	if cluster == nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
+		return
+	}
+	query := r.URL.Query()
+	var infos *core.StoreHotRegionInfos
+	switch typ := query.Get("type"); typ {
+	case "", "write":
+		infos = h.GetHotWriteRegions()
+	case "read":
+		infos = h.GetHotReadRegions()
+	default:
+		h.rd.JSON(w, http.StatusBadRequest, fmt.Sprintf("invalid hot region type %q", typ))
+		return
+	}
+	limit := defaultHotRegionLimit
+	if s := query.Get("limit"); s != "" {
+		var err error
+		if limit, err = strconv.Atoi(s); err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if limit <= 0 {
+			h.rd.JSON(w, http.StatusBadRequest, "limit should be positive")
+			return
+		}
+	}
+	if limit > maxHotRegionLimit {
+		limit = maxHotRegionLimit
+	}
+
+	hotRegions := []*hotRegion{}
+	if infos != nil {
+		// The stats as leader have one entry for each region.
+		for storeID, stat := range infos.AsLeader {
+			for _, s := range stat.RegionsStat {
+				hotRegions = append(hotRegions, &hotRegion{
+					RegionID:  s.RegionID,
+					StoreID:   storeID,
+					FlowBytes: s.FlowBytes,
+					HotDegree: s.HotDegree,
+				})
+			}
+		}
+	}
+	sort.Slice(hotRegions, func(i, j int) bool { return hotRegions[i].FlowBytes > hotRegions[j].FlowBytes })
+	if len(hotRegions) > limit {
+		hotRegions = hotRegions[:limit]
+	}
+	for _, hr := range hotRegions {
+		if region := cluster.GetRegionInfoByID(hr.RegionID); region != nil {
+			hr.StartKey = string(core.HexRegionKey(region.GetStartKey()))
+			hr.EndKey = string(core.HexRegionKey(region.GetEndKey()))
+		}
+	}
+	h.rd.JSON(w, http.StatusOK, hotRegions)
+}
@@ -0,0 +1,127 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/server"
+)
+
+var _ = Suite(&testDashboardSuite{})
+
+type testDashboardSuite struct {
+	svr       *server.Server
+	cleanup   cleanUpFunc
+	urlPrefix string
+}
+
+func (s *testDashboardSuite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c)
+	mustWaitLeader(c, []*server.Server{s.svr})
+	s.urlPrefix = fmt.Sprintf("%s%s/api/v1/dashboard", s.svr.GetAddr(), apiPrefix)
+	mustBootstrapCluster(c, s.svr)
+}
+
+func (s *testDashboardSuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+func (s *testDashboardSuite) TestTopology(c *C) {
+	cfg := s.svr.GetReplicationConfig()
+	cfg.LocationLabels = []string{"zone", "host"}
+	c.Assert(s.svr.SetReplicationConfig(*cfg), IsNil)
+	labels := func(zone, host string) []*metapb.StoreLabel {
+		return []*metapb.StoreLabel{{Key: "zone", Value: zone}, {Key: "host", Value: host}}
+	}
+	mustPutStore(c, s.svr, 2, metapb.StoreState_Up, labels("z1", "h1"))
+	mustPutStore(c, s.svr, 3, metapb.StoreState_Up, labels("z1", "h2"))
+	mustPutStore(c, s.svr, 4, metapb.StoreState_Up, labels("z2", "h1"))
+	mustPutStore(c, s.svr, 5, metapb.StoreState_Tombstone, labels("z3", "h1"))
+
+	result := &topology{}
+	err := readJSONWithURL(s.urlPrefix+"/topology", result)
+	c.Assert(err, IsNil)
+	c.Assert(result.LocationLabels, DeepEquals, []string{"zone", "host"})
+	// Store 1 has no label.
+	c.Assert(result.Nodes, HasLen, 3)
+	c.Assert(result.Nodes[0].Value, Equals, "")
+	c.Assert(result.Nodes[0].Children[0].Stores[0].ID, Equals, uint64(1))
+
+	z1 := result.Nodes[1]
+	c.Assert(z1.Label, Equals, "zone")
+	c.Assert(z1.Value, Equals, "z1")
+	c.Assert(z1.StoreCount, Equals, 2)
+	c.Assert(z1.Stores, HasLen, 0)
+	c.Assert(z1.Children, HasLen, 2)
+	c.Assert(z1.Children[0].Label, Equals, "host")
+	c.Assert(z1.Children[0].Value, Equals, "h1")
+	c.Assert(z1.Children[0].Stores, HasLen, 1)
+	c.Assert(z1.Children[0].Stores[0].ID, Equals, uint64(2))
+	c.Assert(z1.Children[1].Stores[0].ID, Equals, uint64(3))
+	c.Assert(result.Nodes[2].Value, Equals, "z2")
+	c.Assert(result.Nodes[2].StoreCount, Equals, 1)
+
+	cfg.LocationLabels = nil
+	c.Assert(s.svr.SetReplicationConfig(*cfg), IsNil)
+	result = &topology{}
+	err = readJSONWithURL(s.urlPrefix+"/topology", result)
+	c.Assert(err, IsNil)
+	c.Assert(result.Nodes, HasLen, 0)
+	c.Assert(result.Stores, HasLen, 4)
+}
+
+func (s *testDashboardSuite) TestStoreFlowTrends(c *C) {
+	now := uint64(time.Now().Unix())
+	_, err := s.svr.StoreHeartbeat(context.Background(), &pdpb.StoreHeartbeatRequest{
+		Header: &pdpb.RequestHeader{ClusterId: s.svr.ClusterID()},
+		Stats: &pdpb.StoreStats{
+			StoreId:      1,
+			BytesWritten: 1000,
+			KeysWritten:  100,
+			Interval:     &pdpb.TimeInterval{StartTimestamp: now - 10, EndTimestamp: now},
+		},
+	})
+	c.Assert(err, IsNil)
+
+	var trends []*storeFlowTrend
+	err = readJSONWithURL(s.urlPrefix+"/stores/flow?store_id=1", &trends)
+	c.Assert(err, IsNil)
+	c.Assert(trends, HasLen, 1)
+	c.Assert(trends[0].StoreID, Equals, uint64(1))
+	c.Assert(trends[0].Samples, HasLen, 1)
+	c.Assert(trends[0].Samples[0].BytesWritten, Equals, float64(100))
+	c.Assert(trends[0].Samples[0].KeysWritten, Equals, float64(10))
+
+	code, _ := requestStatusBody(c, server.DialClient, http.MethodGet, s.urlPrefix+"/stores/flow?store_id=x")
+	c.Assert(code, Equals, http.StatusBadRequest)
+}
+
+func (s *testDashboardSuite) TestTopHotRegions(c *C) {
+	var hotRegions []*hotRegion
+	err := readJSONWithURL(s.urlPrefix+"/hot-regions?type=read&limit=5", &hotRegions)
+	c.Assert(err, IsNil)
+	c.Assert(hotRegions, HasLen, 0)
+
+	for _, query := range []string{"type=x", "limit=x", "limit=0"} {
+		code, _ := requestStatusBody(c, server.DialClient, http.MethodGet, s.urlPrefix+"/hot-regions?"+query)
+		c.Assert(code, Equals, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"runtime/debug"
+	rpprof "runtime/pprof"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pingcap/pd/server"
+	log "github.com/sirupsen/logrus"
+	"github.com/unrolled/render"
+)
+
+const debugPrefix = "/api/v1/debug"
+
+// adminAuth rejects the requests without the admin token. The admin-only APIs
+// are disabled if the admin token is not configured.
+type adminAuth struct {
+	s *server.Server
+}
+
+func newAdminAuth(s *server.Server) *adminAuth {
+	return &adminAuth{s: s}
+}
+
+func (h *adminAuth) ServeHTTP(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	token := h.s.GetSecurityConfig().AdminToken
+	if token == "" {
+		http.Error(w, "admin API is disabled, set security.admin-token to enable it", http.StatusForbidden)
+		return
+	}
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") ||
+		subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(auth, "Bearer ")), []byte(token)) != 1 {
+		log.Warnf("unauthorized admin request %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+		http.Error(w, "invalid admin token", http.StatusUnauthorized)
+		return
+	}
+	next(w, r)
+}
+
+// createDebugRouter creates the router of the debug APIs. The requests are
+// served by the member itself instead of being redirected to the leader.
+func createDebugRouter(prefix string, svr *server.Server) *mux.Router {
+	rd := render.New(render.Options{
+		IndentJSON: true,
+	})
+
+	router := mux.NewRouter().PathPrefix(prefix + debugPrefix).Subrouter()
+	debugHandler := newDebugHandler(svr, rd)
+	// The CPU profile lasts 30 seconds unless `seconds` is specified.
+	router.HandleFunc("/pprof/profile", pprof.Profile).Methods("GET")
+	router.HandleFunc("/pprof/trace", pprof.Trace).Methods("GET")
+	router.HandleFunc("/pprof/cmdline", pprof.Cmdline).Methods("GET")
+	router.HandleFunc("/pprof/symbol", pprof.Symbol).Methods("GET", "POST")
+	router.HandleFunc("/pprof/{name}", debugHandler.Profile).Methods("GET")
+	router.HandleFunc("/goroutines", debugHandler.Goroutines).Methods("GET")
+	router.HandleFunc("/runtime", debugHandler.Runtime).Methods("GET")
+	return router
+}
+
+type debugHandler struct {
+	svr *server.Server
+	rd  *render.Render
+}
+
+func newDebugHandler(svr *server.Server, rd *render.Render) *debugHandler {
+	return &debugHandler{
+		svr: svr,
+		rd:  rd,
+	}
+}
+
+// Profile serves the named profiles such as heap, goroutine and block.
+func (h *debugHandler) Profile(w http.ResponseWriter, r *http.Request) {
+	pprof.Handler(mux.Vars(r)["name"]).ServeHTTP(w, r)
+}
+
+// Goroutines dumps the stacks of all goroutines.
+func (h *debugHandler) Goroutines(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if err := rpprof.Lookup("goroutine").WriteTo(w, 2); err != nil {
+		log.Errorf("failed to dump goroutines: %v", err)
+	}
+}
+
+// RuntimeStats is the GC and heap statistics of the PD server.
+type RuntimeStats struct {
+	Goroutines   int           `json:"goroutines"`
+	HeapAlloc    uint64        `json:"heap_alloc"`
+	HeapInuse    uint64        `json:"heap_inuse"`
+	HeapIdle     uint64        `json:"heap_idle"`
+	HeapReleased uint64        `json:"heap_released"`
+	HeapObjects  uint64        `json:"heap_objects"`
+	Sys          uint64        `json:"sys"`
+	NextGC       uint64        `json:"next_gc"`
+	NumGC        int64         `json:"num_gc"`
+	LastGC       time.Time     `json:"last_gc"`
+	PauseTotal   time.Duration `json:"pause_total"`
+	// RecentPauses are the pause durations of the recent GCs, the most recent
+	// one first.
+	RecentPauses []time.Duration `json:"recent_pauses"`
+}
+
+// Runtime returns the GC and heap statistics.
+func (h *debugHandler) Runtime(w http.ResponseWriter, r *http.Request) {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	var gc debug.GCStats
+	debug.ReadGCStats(&gc)
+	h.rd.JSON(w, http.StatusOK, &RuntimeStats{
+		Goroutines:   runtime.NumGoroutine(),
+		HeapAlloc:    ms.HeapAlloc,
+		HeapInuse:    ms.HeapInuse,
+		HeapIdle:     ms.HeapIdle,
+		HeapReleased: ms.HeapReleased,
+		HeapObjects:  ms.HeapObjects,
+		Sys:          ms.Sys,
+		NextGC:       ms.NextGC,
+		NumGC:        gc.NumGC,
+		LastGC:       gc.LastGC,
+		PauseTotal:   gc.PauseTotal,
+		RecentPauses: gc.Pause,
+	})
+}
@@ -0,0 +1,96 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/pd/server"
+)
+
+var _ = Suite(&testDebugSuite{})
+
+type testDebugSuite struct {
+	svr       *server.Server
+	cleanup   cleanUpFunc
+	urlPrefix string
+}
+
+func (s *testDebugSuite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c)
+	mustWaitLeader(c, []*server.Server{s.svr})
+
+	addr := s.svr.GetAddr()
+	s.urlPrefix = fmt.Sprintf("%s%s%s", addr, apiPrefix, debugPrefix)
+}
+
+func (s *testDebugSuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+func (s *testDebugSuite) request(c *C, path, token string) (int, []byte) {
+	req, err := http.NewRequest("GET", s.urlPrefix+path, nil)
+	c.Assert(err, IsNil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := server.DialClient.Do(req)
+	c.Assert(err, IsNil)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, IsNil)
+	return resp.StatusCode, body
+}
+
+func (s *testDebugSuite) TestAdminAuth(c *C) {
+	status, _ := s.request(c, "/runtime", "")
+	c.Assert(status, Equals, http.StatusForbidden)
+
+	s.svr.GetSecurityConfig().AdminToken = "secret"
+	defer func() { s.svr.GetSecurityConfig().AdminToken = "" }()
+	status, _ = s.request(c, "/runtime", "")
+	c.Assert(status, Equals, http.StatusUnauthorized)
+	status, _ = s.request(c, "/runtime", "wrong")
+	c.Assert(status, Equals, http.StatusUnauthorized)
+	status, _ = s.request(c, "/runtime", "secret")
+	c.Assert(status, Equals, http.StatusOK)
+}
+
+func (s *testDebugSuite) TestDebug(c *C) {
+	s.svr.GetSecurityConfig().AdminToken = "secret"
+	defer func() { s.svr.GetSecurityConfig().AdminToken = "" }()
+
+	status, body := s.request(c, "/runtime", "secret")
+	c.Assert(status, Equals, http.StatusOK)
+	stats := &RuntimeStats{}
+	c.Assert(json.Unmarshal(body, stats), IsNil)
+	c.Assert(stats.Goroutines, Greater, 0)
+	c.Assert(stats.HeapAlloc, Greater, uint64(0))
+
+	status, body = s.request(c, "/goroutines", "secret")
+	c.Assert(status, Equals, http.StatusOK)
+	c.Assert(strings.Contains(string(body), "goroutine"), IsTrue)
+
+	status, _ = s.request(c, "/pprof/heap", "secret")
+	c.Assert(status, Equals, http.StatusOK)
+	status, _ = s.request(c, "/pprof/profile?seconds=1", "secret")
+	c.Assert(status, Equals, http.StatusOK)
+	status, _ = s.request(c, "/pprof/unknown", "secret")
+	c.Assert(status, Equals, http.StatusNotFound)
+}
@@ -0,0 +1,65 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/pingcap/pd/server"
+	"github.com/unrolled/render"
+)
+
+type diagnosisHandler struct {
+	*server.Handler
+	r *render.Render
+}
+
+func newDiagnosisHandler(handler *server.Handler, r *render.Render) *diagnosisHandler {
+	return &diagnosisHandler{
+		Handler: handler,
+		r:       r,
+	}
+}
+
+func (h *diagnosisHandler) DiagnoseRegion(w http.ResponseWriter, r *http.Request) {
+	regionID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		h.r.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	d, err := h.Handler.DiagnoseRegion(regionID)
+	if err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.r.JSON(w, http.StatusOK, d)
+}
+
+func (h *diagnosisHandler) DiagnoseStore(w http.ResponseWriter, r *http.Request) {
+	storeID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		h.r.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	d, err := h.Handler.DiagnoseStore(storeID)
+	if err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.r.JSON(w, http.StatusOK, d)
+}
@@ -0,0 +1,55 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/server"
+)
+
+var _ = Suite(&testDiagnosisSuite{})
+
+type testDiagnosisSuite struct{}
+
+func (s *testDiagnosisSuite) TestDiagnose(c *C) {
+	svr, cleanup := mustNewServer(c)
+	defer cleanup()
+	mustWaitLeader(c, []*server.Server{svr})
+
+	mustBootstrapCluster(c, svr)
+	mustPutStore(c, svr, 1, metapb.StoreState_Up, nil)
+	mustPutStore(c, svr, 2, metapb.StoreState_Offline, nil)
+	mustRegionHeartbeat(c, svr, newTestRegionInfo(10, 1, []byte("a"), []byte("b")))
+	urlPrefix := fmt.Sprintf("%s%s/api/v1/diagnosis", svr.GetAddr(), apiPrefix)
+
+	var region server.RegionDiagnosis
+	err := readJSONWithURL(urlPrefix+"/region/10", &region)
+	c.Assert(err, IsNil)
+	c.Assert(region.RegionID, Equals, uint64(10))
+	c.Assert(region.Checkers, HasLen, 3)
+	c.Assert(region.Limits, HasLen, 4)
+	c.Assert(readJSONWithURL(urlPrefix+"/region/100", &region), NotNil)
+
+	var store server.StoreDiagnosis
+	err = readJSONWithURL(urlPrefix+"/store/2", &store)
+	c.Assert(err, IsNil)
+	c.Assert(store.StoreID, Equals, uint64(2))
+	c.Assert(store.State, Equals, metapb.StoreState_Offline.String())
+	// The store has no heartbeat yet.
+	c.Assert(store.TargetRejectedBy, DeepEquals, []string{"state-filter", "disconnect-filter", "health-filter", "storage-threshold-filter"})
+	c.Assert(readJSONWithURL(urlPrefix+"/store/100", &store), NotNil)
+}
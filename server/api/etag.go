@@ -0,0 +1,59 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strings"
+)
+
+// newETag generates a weak ETag from the version of the data and the URL of
+// the request. It is weak because the response may be compressed.
+func newETag(r *http.Request, version string) string {
+	h := fnv.New64a()
+	h.Write([]byte(version))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.Path))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.RawQuery))
+	return fmt.Sprintf(`W/"%016x"`, h.Sum64())
+}
+
+// checkNotModified sets the ETag of the response, and responds 304 if the
+// ETag matches the If-None-Match header of the request. It returns true if the
+// response is written.
+func checkNotModified(w http.ResponseWriter, r *http.Request, version string) bool {
+	etag := newETag(r, version)
+	w.Header().Set("ETag", etag)
+	if !matchETag(r.Header.Get("If-None-Match"), etag) {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// matchETag checks if the If-None-Match header matches the ETag by the weak
+// comparison.
+func matchETag(ifNoneMatch string, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/server"
+)
+
+var _ = Suite(&testETagSuite{})
+
+type testETagSuite struct {
+	svr       *server.Server
+	cleanup   cleanUpFunc
+	urlPrefix string
+}
+
+func (s *testETagSuite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c)
+	mustWaitLeader(c, []*server.Server{s.svr})
+
+	addr := s.svr.GetAddr()
+	s.urlPrefix = fmt.Sprintf("%s%s/api/v1", addr, apiPrefix)
+
+	mustBootstrapCluster(c, s.svr)
+}
+
+func (s *testETagSuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+// getWithETag gets the URL with the If-None-Match header, and returns the
+// status code and the ETag of the response.
+func getWithETag(c *C, url string, etag string) (int, string) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	c.Assert(err, IsNil)
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	resp, err := server.DialClient.Do(req)
+	c.Assert(err, IsNil)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, IsNil)
+	if resp.StatusCode == http.StatusNotModified {
+		c.Assert(body, HasLen, 0)
+	}
+	return resp.StatusCode, resp.Header.Get("ETag")
+}
+
+func (s *testETagSuite) TestRegions(c *C) {
+	mustRegionHeartbeat(c, s.svr, newTestRegionInfo(2, 1, []byte("a"), []byte("b")))
+	url := s.urlPrefix + "/regions"
+	code, etag := getWithETag(c, url, "")
+	c.Assert(code, Equals, http.StatusOK)
+	c.Assert(etag, Not(Equals), "")
+	code, etag2 := getWithETag(c, url, etag)
+	c.Assert(code, Equals, http.StatusNotModified)
+	c.Assert(etag2, Equals, etag)
+	code, _ = getWithETag(c, url, `"other", `+etag)
+	c.Assert(code, Equals, http.StatusNotModified)
+
+	// The ETag depends on the URL.
+	code, etag2 = getWithETag(c, s.urlPrefix+"/regions/store/1", etag)
+	c.Assert(code, Equals, http.StatusOK)
+	c.Assert(etag2, Not(Equals), etag)
+	code, _ = getWithETag(c, s.urlPrefix+"/regions/store/1", etag2)
+	c.Assert(code, Equals, http.StatusNotModified)
+
+	// The ETag changes after the regions change.
+	mustRegionHeartbeat(c, s.svr, newTestRegionInfo(3, 1, []byte("b"), []byte("c")))
+	code, etag2 = getWithETag(c, url, etag)
+	c.Assert(code, Equals, http.StatusOK)
+	c.Assert(etag2, Not(Equals), etag)
+	regions := &regionsInfo{}
+	c.Assert(readJSONWithURL(url, regions), IsNil)
+	c.Assert(regions.Count, Equals, 2)
+}
+
+func (s *testETagSuite) TestStores(c *C) {
+	url := s.urlPrefix + "/stores"
+	code, etag := getWithETag(c, url, "")
+	c.Assert(code, Equals, http.StatusOK)
+	code, _ = getWithETag(c, url, etag)
+	c.Assert(code, Equals, http.StatusNotModified)
+	code, _ = getWithETag(c, url+"?state=2", etag)
+	c.Assert(code, Equals, http.StatusOK)
+
+	mustPutStore(c, s.svr, 10, metapb.StoreState_Up, nil)
+	code, _ = getWithETag(c, url, etag)
+	c.Assert(code, Equals, http.StatusOK)
+}
+
+func (s *testETagSuite) TestMatchETag(c *C) {
+	c.Assert(matchETag(`W/"1"`, `W/"1"`), IsTrue)
+	c.Assert(matchETag(`"1"`, `W/"1"`), IsTrue)
+	c.Assert(matchETag(`"2", W/"1"`, `W/"1"`), IsTrue)
+	c.Assert(matchETag(`*`, `W/"1"`), IsTrue)
+	c.Assert(matchETag(`"2"`, `W/"1"`), IsFalse)
+	c.Assert(matchETag(``, `W/"1"`), IsFalse)
+}
@@ -0,0 +1,60 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/pingcap/pd/server"
+	"github.com/unrolled/render"
+)
+
+type eventHandler struct {
+	svr *server.Server
+	rd  *render.Render
+}
+
+func newEventHandler(svr *server.Server, rd *render.Render) *eventHandler {
+	return &eventHandler{
+		svr: svr,
+		rd:  rd,
+	}
+}
+
+func (h *eventHandler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var (
+		sinceID uint64
+		limit   int
+		err     error
+	)
+	if s := query.Get("since_id"); s != "" {
+		if sinceID, err = strconv.ParseUint(s, 10, 64); err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if s := query.Get("limit"); s != "" {
+		if limit, err = strconv.Atoi(s); err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	events := h.svr.GetClusterEvents(query.Get("type"), sinceID, limit)
+	if events == nil {
+		events = []*server.ClusterEvent{}
+	}
+	h.rd.JSON(w, http.StatusOK, events)
+}
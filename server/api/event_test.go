@@ -0,0 +1,57 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/server"
+)
+
+var _ = Suite(&testEventSuite{})
+
+type testEventSuite struct{}
+
+func (s *testEventSuite) TestEvents(c *C) {
+	svr, cleanup := mustNewServer(c)
+	defer cleanup()
+	mustWaitLeader(c, []*server.Server{svr})
+	mustBootstrapCluster(c, svr)
+	mustPutStore(c, svr, 2, metapb.StoreState_Up, nil)
+	urlPrefix := fmt.Sprintf("%s%s/api/v1/events", svr.GetAddr(), apiPrefix)
+
+	var events []*server.ClusterEvent
+	err := readJSONWithURL(urlPrefix, &events)
+	c.Assert(err, IsNil)
+	c.Assert(events[0].Type, Equals, server.EventLeaderChanged)
+
+	err = readJSONWithURL(urlPrefix+"?type=store-up", &events)
+	c.Assert(err, IsNil)
+	c.Assert(events, HasLen, 1)
+	c.Assert(events[0].StoreID, Equals, uint64(2))
+
+	err = readJSONWithURL(fmt.Sprintf("%s?since_id=%d", urlPrefix, events[0].ID), &events)
+	c.Assert(err, IsNil)
+	c.Assert(events, HasLen, 0)
+	err = readJSONWithURL(urlPrefix+"?limit=1", &events)
+	c.Assert(err, IsNil)
+	c.Assert(events, HasLen, 1)
+	c.Assert(events[0].Type, Equals, server.EventStoreUp)
+
+	code, _ := requestStatusBody(c, server.DialClient, http.MethodGet, urlPrefix+"?limit=x")
+	c.Assert(code, Equals, http.StatusBadRequest)
+}
@@ -0,0 +1,105 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/pingcap/pd/pkg/apiutil"
+	"github.com/pingcap/pd/pkg/gcpb"
+	"github.com/pingcap/pd/server"
+	"github.com/pkg/errors"
+	"github.com/unrolled/render"
+)
+
+type gcHandler struct {
+	svr *server.Server
+	rd  *render.Render
+}
+
+func newGCHandler(svr *server.Server, rd *render.Render) *gcHandler {
+	return &gcHandler{
+		svr: svr,
+		rd:  rd,
+	}
+}
+
+func (h *gcHandler) respond(w http.ResponseWriter, result interface{}, err error) {
+	if err == nil {
+		h.rd.JSON(w, http.StatusOK, result)
+		return
+	}
+	switch errors.Cause(err) {
+	case server.ErrInvalidServiceSafePoint:
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+	default:
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
+	}
+}
+
+type gcSafePoints struct {
+	GCSafePoint       uint64                   `json:"gc_safe_point"`
+	MinSafePoint      uint64                   `json:"min_service_safe_point,omitempty"`
+	ServiceSafePoints []*gcpb.ServiceSafePoint `json:"service_safe_points"`
+}
+
+func (h *gcHandler) GetSafePoints(w http.ResponseWriter, r *http.Request) {
+	ssps, gcSafePoint, err := h.svr.GetServiceGCSafePoints()
+	if err != nil {
+		h.respond(w, nil, err)
+		return
+	}
+	if ssps == nil {
+		ssps = []*gcpb.ServiceSafePoint{}
+	}
+	result := &gcSafePoints{
+		GCSafePoint:       gcSafePoint,
+		ServiceSafePoints: ssps,
+	}
+	for i, ssp := range ssps {
+		if i == 0 || ssp.GetSafePoint() < result.MinSafePoint {
+			result.MinSafePoint = ssp.GetSafePoint()
+		}
+	}
+	h.respond(w, result, nil)
+}
+
+type updateServiceSafePointInput struct {
+	SafePoint uint64 `json:"safe_point"`
+	TTL       int64  `json:"ttl"`
+}
+
+type updateServiceSafePointOutput struct {
+	Updated      bool   `json:"updated"`
+	MinSafePoint uint64 `json:"min_service_safe_point"`
+}
+
+func (h *gcHandler) UpdateServiceSafePoint(w http.ResponseWriter, r *http.Request) {
+	var input updateServiceSafePointInput
+	if err := readJSONRespondError(h.rd, w, r.Body, &input); err != nil {
+		return
+	}
+	if input.TTL <= 0 {
+		h.rd.JSON(w, http.StatusBadRequest, "ttl should be positive")
+		return
+	}
+	min, updated, err := h.svr.UpdateServiceGCSafePoint(mux.Vars(r)["service_id"], input.TTL, input.SafePoint)
+	h.respond(w, &updateServiceSafePointOutput{Updated: updated, MinSafePoint: min}, err)
+}
+
+func (h *gcHandler) DeleteServiceSafePoint(w http.ResponseWriter, r *http.Request) {
+	_, _, err := h.svr.UpdateServiceGCSafePoint(mux.Vars(r)["service_id"], 0, 0)
+	h.respond(w, nil, err)
+}
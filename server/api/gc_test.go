@@ -0,0 +1,55 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/pd/server"
+)
+
+var _ = Suite(&testGCSuite{})
+
+type testGCSuite struct{}
+
+func (s *testGCSuite) TestServiceSafePoint(c *C) {
+	svr, cleanup := mustNewServer(c)
+	defer cleanup()
+	mustWaitLeader(c, []*server.Server{svr})
+	urlPrefix := fmt.Sprintf("%s%s/api/v1/gc/safepoint", svr.GetAddr(), apiPrefix)
+
+	err := postJSON(urlPrefix+"/service/cdc", []byte(`{"safe_point":10,"ttl":100}`))
+	c.Assert(err, IsNil)
+	err = postJSON(urlPrefix+"/service/br", []byte(`{"safe_point":20,"ttl":100}`))
+	c.Assert(err, IsNil)
+	c.Assert(postJSON(urlPrefix+"/service/br", []byte(`{"safe_point":20}`)), NotNil)
+
+	var safePoints gcSafePoints
+	err = readJSONWithURL(urlPrefix, &safePoints)
+	c.Assert(err, IsNil)
+	c.Assert(safePoints.GCSafePoint, Equals, uint64(0))
+	c.Assert(safePoints.MinSafePoint, Equals, uint64(10))
+	c.Assert(safePoints.ServiceSafePoints, HasLen, 2)
+	c.Assert(safePoints.ServiceSafePoints[0].GetServiceId(), Equals, "br")
+	c.Assert(safePoints.ServiceSafePoints[1].GetServiceId(), Equals, "cdc")
+
+	code, _ := requestStatusBody(c, server.DialClient, http.MethodDelete, urlPrefix+"/service/cdc")
+	c.Assert(code, Equals, http.StatusOK)
+	err = readJSONWithURL(urlPrefix, &safePoints)
+	c.Assert(err, IsNil)
+	c.Assert(safePoints.MinSafePoint, Equals, uint64(20))
+	c.Assert(safePoints.ServiceSafePoints, HasLen, 1)
+}
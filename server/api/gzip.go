@@ -0,0 +1,114 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var gzipWriterPool = sync.Pool{
+	New: func() interface{} { return gzip.NewWriter(nil) },
+}
+
+// gzipCompressor compresses the responses if the clients accept gzip.
+type gzipCompressor struct{}
+
+func newGzipCompressor() *gzipCompressor {
+	return &gzipCompressor{}
+}
+
+func (h *gzipCompressor) ServeHTTP(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	w.Header().Add("Vary", "Accept-Encoding")
+	if !acceptGzip(r) {
+		next(w, r)
+		return
+	}
+	gw := &gzipResponseWriter{ResponseWriter: w}
+	defer gw.close()
+	next(gw, r)
+}
+
+// acceptGzip checks if gzip is in the Accept-Encoding header and its quality
+// is not 0.
+func acceptGzip(r *http.Request) bool {
+	for _, encoding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		parts := strings.Split(encoding, ";")
+		if strings.TrimSpace(parts[0]) != "gzip" {
+			continue
+		}
+		for _, param := range parts[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+			if q, err := strconv.ParseFloat(param[2:], 64); err == nil && q == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// gzipResponseWriter compresses the body unless the response has no body or
+// it is already encoded.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	gw          *gzip.Writer
+	wroteHeader bool
+}
+
+func (w *gzipResponseWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	header := w.Header()
+	if code != http.StatusNoContent && code != http.StatusNotModified && header.Get("Content-Encoding") == "" {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		w.gw = gzipWriterPool.Get().(*gzip.Writer)
+		w.gw.Reset(w.ResponseWriter)
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.gw == nil {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.gw.Write(b)
+}
+
+func (w *gzipResponseWriter) close() {
+	if w.gw == nil {
+		return
+	}
+	if err := w.gw.Close(); err != nil {
+		log.Warnf("failed to compress the response: %v", err)
+	}
+	gzipWriterPool.Put(w.gw)
+}
@@ -0,0 +1,87 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/pingcap/check"
+	"github.com/unrolled/render"
+	"github.com/urfave/negroni"
+)
+
+var _ = Suite(&testGzipSuite{})
+
+type testGzipSuite struct{}
+
+func (s *testGzipSuite) TestAcceptGzip(c *C) {
+	for encoding, accept := range map[string]bool{
+		"":                   false,
+		"gzip":               true,
+		"deflate, gzip":      true,
+		"gzip;q=0.5, br":     true,
+		"gzip; q=0":          false,
+		"identity":           false,
+		"x-gzip, deflate":    false,
+		"br;q=1.0, gzip;q=0": false,
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept-Encoding", encoding)
+		c.Assert(acceptGzip(r), Equals, accept, Commentf("encoding %q", encoding))
+	}
+}
+
+func (s *testGzipSuite) TestCompress(c *C) {
+	rd := render.New(render.Options{IndentJSON: true})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/json", func(w http.ResponseWriter, r *http.Request) {
+		rd.JSON(w, http.StatusOK, map[string]string{"key": "value"})
+	})
+	mux.HandleFunc("/not-modified", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	})
+	handler := negroni.New(newGzipCompressor(), negroni.Wrap(mux))
+
+	r := httptest.NewRequest(http.MethodGet, "/json", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	c.Assert(w.Code, Equals, http.StatusOK)
+	c.Assert(w.Header().Get("Content-Encoding"), Equals, "gzip")
+	c.Assert(w.Header().Get("Vary"), Equals, "Accept-Encoding")
+	gr, err := gzip.NewReader(w.Body)
+	c.Assert(err, IsNil)
+	var data map[string]string
+	c.Assert(readJSON(ioutil.NopCloser(gr), &data), IsNil)
+	c.Assert(data["key"], Equals, "value")
+
+	// Not compressed if gzip is not accepted.
+	r = httptest.NewRequest(http.MethodGet, "/json", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	c.Assert(w.Header().Get("Content-Encoding"), Equals, "")
+	c.Assert(readJSON(ioutil.NopCloser(w.Body), &data), IsNil)
+
+	// Not compressed if there is no body.
+	r = httptest.NewRequest(http.MethodGet, "/not-modified", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	c.Assert(w.Code, Equals, http.StatusNotModified)
+	c.Assert(w.Header().Get("Content-Encoding"), Equals, "")
+	c.Assert(w.Body.Len(), Equals, 0)
+}
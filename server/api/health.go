@@ -16,6 +16,7 @@ package api
 import (
 	"net/http"
 
+	"github.com/pingcap/pd/pkg/apiutil"
 	"github.com/pingcap/pd/server"
 	"github.com/unrolled/render"
 )
@@ -41,10 +42,9 @@ func newHealthHandler(svr *server.Server, rd *render.Render) *healthHandler {
 }
 
 func (h *healthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	client := h.svr.GetClient()
-	members, err := server.GetMembers(client)
+	members, err := h.svr.ListMembers()
 	if err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 		return
 	}
 	unhealthMembers := h.svr.CheckHealth(members)
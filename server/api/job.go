@@ -0,0 +1,96 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/pingcap/pd/pkg/apiutil"
+	"github.com/pingcap/pd/server"
+	"github.com/pkg/errors"
+	"github.com/unrolled/render"
+)
+
+type jobHandler struct {
+	svr *server.Server
+	rd  *render.Render
+}
+
+func newJobHandler(svr *server.Server, rd *render.Render) *jobHandler {
+	return &jobHandler{
+		svr: svr,
+		rd:  rd,
+	}
+}
+
+func (h *jobHandler) respond(w http.ResponseWriter, result interface{}, err error) {
+	if err == nil {
+		h.rd.JSON(w, http.StatusOK, result)
+		return
+	}
+	switch errors.Cause(err) {
+	case server.ErrJobNotFound:
+		h.rd.JSON(w, http.StatusNotFound, err.Error())
+	case server.ErrJobNotRunning, server.ErrJobNotPaused, server.ErrInvalidJob:
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+	default:
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
+	}
+}
+
+func (h *jobHandler) List(w http.ResponseWriter, r *http.Request) {
+	h.rd.JSON(w, http.StatusOK, h.svr.GetJobs(r.URL.Query().Get("type")))
+}
+
+func (h *jobHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	job, err := h.svr.GetJob(id)
+	h.respond(w, job, err)
+}
+
+func (h *jobHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	job, err := h.svr.CancelJob(id)
+	h.respond(w, job, err)
+}
+
+func (h *jobHandler) Pause(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	job, err := h.svr.PauseJob(id)
+	h.respond(w, job, err)
+}
+
+func (h *jobHandler) Resume(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	job, err := h.svr.ResumeJob(id)
+	h.respond(w, job, err)
+}
@@ -0,0 +1,86 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/pd/server"
+)
+
+var _ = Suite(&testJobSuite{})
+
+type testJobSuite struct {
+	svr       *server.Server
+	cleanup   cleanUpFunc
+	urlPrefix string
+}
+
+func (s *testJobSuite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c)
+	mustWaitLeader(c, []*server.Server{s.svr})
+
+	addr := s.svr.GetAddr()
+	s.urlPrefix = fmt.Sprintf("%s%s/api/v1", addr, apiPrefix)
+
+	mustBootstrapCluster(c, s.svr)
+}
+
+func (s *testJobSuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+func (s *testJobSuite) TestJob(c *C) {
+	var jobs []*server.Job
+	c.Assert(readJSONWithURL(s.urlPrefix+"/jobs", &jobs), IsNil)
+	c.Assert(jobs, HasLen, 0)
+
+	// The pre-split job keeps running until the region is split.
+	preSplitJob, err := s.svr.GetHandler().PreSplitRegions([]byte("t\x80\x00\x00\x00\x00\x00\x00\x02_r"), 100, 2)
+	c.Assert(err, IsNil)
+
+	c.Assert(readJSONWithURL(s.urlPrefix+"/jobs?type=pre-split", &jobs), IsNil)
+	c.Assert(jobs, HasLen, 1)
+	c.Assert(jobs[0].ID, Equals, preSplitJob.ID)
+	c.Assert(jobs[0].Type, Equals, "pre-split")
+	c.Assert(jobs[0].Status, Equals, server.JobRunning)
+	c.Assert(readJSONWithURL(s.urlPrefix+"/jobs?type=unknown", &jobs), IsNil)
+	c.Assert(jobs, HasLen, 0)
+
+	jobURL := fmt.Sprintf("%s/jobs/%d", s.urlPrefix, preSplitJob.ID)
+	job := &server.Job{}
+	c.Assert(readJSONWithURL(jobURL, job), IsNil)
+	c.Assert(job.Status, Equals, server.JobRunning)
+
+	code, body := requestStatusBody(c, server.DialClient, http.MethodPost, jobURL+"/cancel")
+	c.Assert(code, Equals, http.StatusOK)
+	c.Assert(json.Unmarshal(body, job), IsNil)
+	c.Assert(job.Status, Equals, server.JobCanceled)
+	c.Assert(job.FinishTime.IsZero(), IsFalse)
+
+	// The pre-split API shows the canceled job too.
+	c.Assert(s.svr.GetHandler().GetPreSplitJob(job.ID).Status, Equals, server.PreSplitJobCanceled)
+
+	code, _ = requestStatusBody(c, server.DialClient, http.MethodPost, jobURL+"/cancel")
+	c.Assert(code, Equals, http.StatusBadRequest)
+	code, _ = requestStatusBody(c, server.DialClient, http.MethodGet, s.urlPrefix+"/jobs/100000")
+	c.Assert(code, Equals, http.StatusNotFound)
+	code, _ = requestStatusBody(c, server.DialClient, http.MethodPost, s.urlPrefix+"/jobs/100000/cancel")
+	c.Assert(code, Equals, http.StatusNotFound)
+	code, _ = requestStatusBody(c, server.DialClient, http.MethodGet, s.urlPrefix+"/jobs/abc")
+	c.Assert(code, Equals, http.StatusBadRequest)
+}
@@ -0,0 +1,94 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pingcap/pd/pkg/apiutil"
+	"github.com/pingcap/pd/server"
+	"github.com/pingcap/pd/server/keyvisual"
+	"github.com/pkg/errors"
+	"github.com/unrolled/render"
+)
+
+const (
+	defaultHeatmapBuckets = 256
+	maxHeatmapBuckets     = 1024
+)
+
+type keyVisualHandler struct {
+	svr *server.Server
+	rd  *render.Render
+}
+
+func newKeyVisualHandler(svr *server.Server, rd *render.Render) *keyVisualHandler {
+	return &keyVisualHandler{
+		svr: svr,
+		rd:  rd,
+	}
+}
+
+func parseHeatmapQuery(r *http.Request) (*keyvisual.Query, error) {
+	query := r.URL.Query()
+	q := &keyvisual.Query{
+		StartKey: []byte(query.Get("start_key")),
+		EndKey:   []byte(query.Get("end_key")),
+		Buckets:  defaultHeatmapBuckets,
+		Tag:      keyvisual.WrittenBytes,
+	}
+	for name, t := range map[string]*time.Time{"start_time": &q.StartTime, "end_time": &q.EndTime} {
+		if s := query.Get(name); s != "" {
+			ts, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return nil, errors.Errorf("invalid %s: %s", name, s)
+			}
+			*t = time.Unix(ts, 0)
+		}
+	}
+	if s := query.Get("buckets"); s != "" {
+		buckets, err := strconv.Atoi(s)
+		if err != nil || buckets <= 0 {
+			return nil, errors.Errorf("invalid buckets: %s", s)
+		}
+		if buckets > maxHeatmapBuckets {
+			buckets = maxHeatmapBuckets
+		}
+		q.Buckets = buckets
+	}
+	switch tag := query.Get("type"); tag {
+	case "", "write":
+	case "read":
+		q.Tag = keyvisual.ReadBytes
+	default:
+		return nil, errors.Errorf("invalid type: %s", tag)
+	}
+	return q, nil
+}
+
+func (h *keyVisualHandler) Heatmap(w http.ResponseWriter, r *http.Request) {
+	cluster := h.svr.GetRaftCluster()
+	if cluster == nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
+		return
+	}
+	q, err := parseHeatmapQuery(r)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, cluster.GetKeyVisualHeatmap(q))
+}
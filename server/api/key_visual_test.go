@@ -0,0 +1,69 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/pd/server"
+	"github.com/pingcap/pd/server/keyvisual"
+)
+
+var _ = Suite(&testKeyVisualSuite{})
+
+type testKeyVisualSuite struct {
+	svr       *server.Server
+	cleanup   cleanUpFunc
+	urlPrefix string
+}
+
+func (s *testKeyVisualSuite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c)
+	mustWaitLeader(c, []*server.Server{s.svr})
+
+	addr := s.svr.GetAddr()
+	s.urlPrefix = fmt.Sprintf("%s%s/api/v1", addr, apiPrefix)
+
+	mustBootstrapCluster(c, s.svr)
+}
+
+func (s *testKeyVisualSuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+func (s *testKeyVisualSuite) TestHeatmap(c *C) {
+	heatmapURL := s.urlPrefix + "/keyvisual/heatmap"
+
+	m := &keyvisual.Matrix{}
+	c.Assert(readJSONWithURL(heatmapURL+"?start_key=a&end_key=b", m), IsNil)
+	c.Assert(m.Keys, DeepEquals, []string{"61", "62"})
+	c.Assert(m.Times, HasLen, 0)
+
+	for _, query := range []string{"start_time=abc", "buckets=0", "type=size"} {
+		status, _ := requestStatusBody(c, newHTTPClient(), "GET", heatmapURL+"?"+query)
+		c.Assert(status, Equals, http.StatusBadRequest)
+	}
+}
+
+func (s *testKeyVisualSuite) TestParseHeatmapQuery(c *C) {
+	q, err := parseHeatmapQuery(httptest.NewRequest("GET", "/heatmap?start_time=60&buckets=2000&type=read", nil))
+	c.Assert(err, IsNil)
+	c.Assert(q.StartTime.Unix(), Equals, int64(60))
+	c.Assert(q.EndTime.IsZero(), IsTrue)
+	c.Assert(q.Buckets, Equals, maxHeatmapBuckets)
+	c.Assert(q.Tag, Equals, keyvisual.ReadBytes)
+}
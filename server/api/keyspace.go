@@ -0,0 +1,122 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/pingcap/pd/pkg/apiutil"
+	"github.com/pingcap/pd/pkg/keyspacepb"
+	"github.com/pingcap/pd/server"
+	"github.com/pkg/errors"
+	"github.com/unrolled/render"
+)
+
+type keyspaceHandler struct {
+	svr *server.Server
+	rd  *render.Render
+}
+
+func newKeyspaceHandler(svr *server.Server, rd *render.Render) *keyspaceHandler {
+	return &keyspaceHandler{
+		svr: svr,
+		rd:  rd,
+	}
+}
+
+func (h *keyspaceHandler) respond(w http.ResponseWriter, result interface{}, err error) {
+	if err == nil {
+		h.rd.JSON(w, http.StatusOK, result)
+		return
+	}
+	switch errors.Cause(err) {
+	case server.ErrKeyspaceNotFound:
+		h.rd.JSON(w, http.StatusNotFound, err.Error())
+	case server.ErrKeyspaceExists, server.ErrInvalidKeyspace:
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+	default:
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
+	}
+}
+
+// keyspaceInfo is the metadata of a keyspace with the name of its state.
+type keyspaceInfo struct {
+	*keyspacepb.KeyspaceMeta
+	StateName string `json:"state_name"`
+}
+
+func newKeyspaceInfo(meta *keyspacepb.KeyspaceMeta) *keyspaceInfo {
+	if meta == nil {
+		return nil
+	}
+	return &keyspaceInfo{KeyspaceMeta: meta, StateName: meta.GetState().String()}
+}
+
+type createKeyspaceInput struct {
+	Name   string            `json:"name"`
+	Config map[string]string `json:"config"`
+}
+
+func (h *keyspaceHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input createKeyspaceInput
+	if err := readJSONRespondError(h.rd, w, r.Body, &input); err != nil {
+		return
+	}
+	meta, err := h.svr.CreateKeyspace(input.Name, input.Config)
+	h.respond(w, newKeyspaceInfo(meta), err)
+}
+
+func (h *keyspaceHandler) List(w http.ResponseWriter, r *http.Request) {
+	keyspaces, err := h.svr.GetKeyspaces()
+	infos := make([]*keyspaceInfo, 0, len(keyspaces))
+	for _, meta := range keyspaces {
+		infos = append(infos, newKeyspaceInfo(meta))
+	}
+	h.respond(w, infos, err)
+}
+
+func (h *keyspaceHandler) Get(w http.ResponseWriter, r *http.Request) {
+	meta, err := h.svr.GetKeyspace(mux.Vars(r)["name"])
+	h.respond(w, newKeyspaceInfo(meta), err)
+}
+
+func (h *keyspaceHandler) UpdateState(w http.ResponseWriter, r *http.Request) {
+	var input map[string]string
+	if err := readJSONRespondError(h.rd, w, r.Body, &input); err != nil {
+		return
+	}
+	state, ok := keyspacepb.KeyspaceState_value[input["state"]]
+	if !ok {
+		h.rd.JSON(w, http.StatusBadRequest, fmt.Sprintf("invalid keyspace state %q", input["state"]))
+		return
+	}
+	meta, err := h.svr.UpdateKeyspaceState(mux.Vars(r)["name"], keyspacepb.KeyspaceState(state))
+	h.respond(w, newKeyspaceInfo(meta), err)
+}
+
+type updateKeyspaceConfigInput struct {
+	Put    map[string]string `json:"put"`
+	Remove []string          `json:"remove"`
+}
+
+func (h *keyspaceHandler) UpdateConfig(w http.ResponseWriter, r *http.Request) {
+	var input updateKeyspaceConfigInput
+	if err := readJSONRespondError(h.rd, w, r.Body, &input); err != nil {
+		return
+	}
+	meta, err := h.svr.UpdateKeyspaceConfig(mux.Vars(r)["name"], input.Put, input.Remove)
+	h.respond(w, newKeyspaceInfo(meta), err)
+}
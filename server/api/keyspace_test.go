@@ -0,0 +1,64 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/pd/pkg/keyspacepb"
+	"github.com/pingcap/pd/server"
+)
+
+var _ = Suite(&testKeyspaceSuite{})
+
+type testKeyspaceSuite struct{}
+
+func (s *testKeyspaceSuite) TestKeyspace(c *C) {
+	svr, cleanup := mustNewServer(c)
+	defer cleanup()
+	mustWaitLeader(c, []*server.Server{svr})
+	urlPrefix := fmt.Sprintf("%s%s/api/v1/keyspaces", svr.GetAddr(), apiPrefix)
+
+	err := postJSON(urlPrefix, []byte(`{"name":"ks1","config":{"a":"1"}}`))
+	c.Assert(err, IsNil)
+	c.Assert(postJSON(urlPrefix, []byte(`{"name":"ks1"}`)), NotNil)
+
+	var meta keyspaceInfo
+	err = readJSONWithURL(urlPrefix+"/ks1", &meta)
+	c.Assert(err, IsNil)
+	c.Assert(meta.GetName(), Equals, "ks1")
+	c.Assert(meta.GetState(), Equals, keyspacepb.KeyspaceState_ENABLED)
+	c.Assert(meta.StateName, Equals, "ENABLED")
+	c.Assert(meta.GetConfig(), DeepEquals, map[string]string{"a": "1"})
+	resp, err := http.Get(urlPrefix + "/ks2")
+	c.Assert(err, IsNil)
+	resp.Body.Close()
+	c.Assert(resp.StatusCode, Equals, http.StatusNotFound)
+
+	err = postJSON(urlPrefix+"/ks1/config", []byte(`{"put":{"b":"2"},"remove":["a"]}`))
+	c.Assert(err, IsNil)
+	err = postJSON(urlPrefix+"/ks1/state", []byte(`{"state":"DISABLED"}`))
+	c.Assert(err, IsNil)
+	c.Assert(postJSON(urlPrefix+"/ks1/state", []byte(`{"state":"UNKNOWN"}`)), NotNil)
+
+	var keyspaces []*keyspaceInfo
+	err = readJSONWithURL(urlPrefix, &keyspaces)
+	c.Assert(err, IsNil)
+	c.Assert(keyspaces, HasLen, 1)
+	c.Assert(keyspaces[0].GetState(), Equals, keyspacepb.KeyspaceState_DISABLED)
+	c.Assert(keyspaces[0].StateName, Equals, "DISABLED")
+	c.Assert(keyspaces[0].GetConfig(), DeepEquals, map[string]string{"b": "2"})
+}
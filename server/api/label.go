@@ -19,6 +19,7 @@ import (
 	"strings"
 
 	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/pkg/apiutil"
 	"github.com/pingcap/pd/server"
 	"github.com/pkg/errors"
 	"github.com/unrolled/render"
@@ -39,7 +40,7 @@ func newLabelsHandler(svr *server.Server, rd *render.Render) *labelsHandler {
 func (h *labelsHandler) Get(w http.ResponseWriter, r *http.Request) {
 	cluster := h.svr.GetRaftCluster()
 	if cluster == nil {
-		h.rd.JSON(w, http.StatusInternalServerError, server.ErrNotBootstrapped.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
 		return
 	}
 	var labels []*metapb.StoreLabel
@@ -60,7 +61,7 @@ func (h *labelsHandler) Get(w http.ResponseWriter, r *http.Request) {
 func (h *labelsHandler) GetStores(w http.ResponseWriter, r *http.Request) {
 	cluster := h.svr.GetRaftCluster()
 	if cluster == nil {
-		h.rd.JSON(w, http.StatusInternalServerError, server.ErrNotBootstrapped.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
 		return
 	}
 
@@ -68,7 +69,7 @@ func (h *labelsHandler) GetStores(w http.ResponseWriter, r *http.Request) {
 	value := r.URL.Query().Get("value")
 	filter, err := newStoresLabelFilter(name, value)
 	if err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 		return
 	}
 
@@ -81,7 +82,7 @@ func (h *labelsHandler) GetStores(w http.ResponseWriter, r *http.Request) {
 	for _, s := range stores {
 		store, err := cluster.GetStore(s.GetId())
 		if err != nil {
-			h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+			h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 			return
 		}
 
@@ -18,6 +18,7 @@ import (
 	"io/ioutil"
 	"net/http"
 
+	"github.com/pingcap/pd/pkg/apiutil"
 	"github.com/pingcap/pd/pkg/logutil"
 	"github.com/pingcap/pd/server"
 	log "github.com/sirupsen/logrus"
@@ -41,7 +42,7 @@ func (h *logHandler) Handle(w http.ResponseWriter, r *http.Request) {
 	data, err := ioutil.ReadAll(r.Body)
 	r.Body.Close()
 	if err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 		return
 	}
 	err = json.Unmarshal(data, &level)
@@ -0,0 +1,112 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/pingcap/pd/pkg/apiutil"
+	"github.com/pingcap/pd/server"
+	"github.com/unrolled/render"
+)
+
+const maintenanceAPI = "/api/v1/admin/maintenance"
+
+// maintenanceChecker rejects the mutating requests if the cluster is in the
+// maintenance mode, except the requests to exit the maintenance mode.
+type maintenanceChecker struct {
+	s *server.Server
+}
+
+func newMaintenanceChecker(s *server.Server) *maintenanceChecker {
+	return &maintenanceChecker{s: s}
+}
+
+func (h *maintenanceChecker) ServeHTTP(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		next(w, r)
+		return
+	}
+	if r.URL.Path == apiPrefix+maintenanceAPI {
+		next(w, r)
+		return
+	}
+	if err := h.s.CheckMaintenance(); err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	next(w, r)
+}
+
+type maintenanceHandler struct {
+	svr *server.Server
+	rd  *render.Render
+}
+
+func newMaintenanceHandler(svr *server.Server, rd *render.Render) *maintenanceHandler {
+	return &maintenanceHandler{
+		svr: svr,
+		rd:  rd,
+	}
+}
+
+func (h *maintenanceHandler) Get(w http.ResponseWriter, r *http.Request) {
+	h.rd.JSON(w, http.StatusOK, h.svr.GetMaintenanceStatus())
+}
+
+type enterMaintenanceInput struct {
+	Reason    string `json:"reason"`
+	Requester string `json:"requester"`
+	// Duration is how long the cluster stays in the maintenance mode, such
+	// as "2h". Empty means until exiting manually.
+	Duration string `json:"duration"`
+}
+
+func (h *maintenanceHandler) Enter(w http.ResponseWriter, r *http.Request) {
+	var input enterMaintenanceInput
+	if err := readJSONRespondError(h.rd, w, r.Body, &input); err != nil {
+		return
+	}
+	if input.Reason == "" {
+		h.rd.JSON(w, http.StatusBadRequest, "reason is required")
+		return
+	}
+	if input.Requester == "" {
+		input.Requester = r.RemoteAddr
+	}
+	var deadline time.Time
+	if input.Duration != "" {
+		duration, err := time.ParseDuration(input.Duration)
+		if err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		deadline = time.Now().Add(duration)
+	}
+	if err := h.svr.EnterMaintenance(input.Reason, input.Requester, deadline); err != nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, h.svr.GetMaintenanceStatus())
+}
+
+func (h *maintenanceHandler) Exit(w http.ResponseWriter, r *http.Request) {
+	if err := h.svr.ExitMaintenance(); err != nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, nil)
+}
@@ -0,0 +1,76 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/pd/pkg/testutil"
+	"github.com/pingcap/pd/server"
+)
+
+var _ = Suite(&testMaintenanceSuite{})
+
+type testMaintenanceSuite struct{}
+
+func (s *testMaintenanceSuite) TestMaintenance(c *C) {
+	svr, cleanup := mustNewServer(c)
+	defer cleanup()
+	mustWaitLeader(c, []*server.Server{svr})
+	urlPrefix := fmt.Sprintf("%s%s/api/v1", svr.GetAddr(), apiPrefix)
+	url := urlPrefix + "/admin/maintenance"
+
+	c.Assert(postJSON(url, []byte(`{"requester":"alice"}`)), NotNil)
+	c.Assert(postJSON(url, []byte(`{"reason":"upgrade","duration":"1d"}`)), NotNil)
+	err := postJSON(url, []byte(`{"reason":"upgrade","requester":"alice"}`))
+	c.Assert(err, IsNil)
+	var status server.MaintenanceStatus
+	err = readJSONWithURL(url, &status)
+	c.Assert(err, IsNil)
+	c.Assert(status.Enabled, IsTrue)
+	c.Assert(status.Reason, Equals, "upgrade")
+	c.Assert(status.Requester, Equals, "alice")
+	c.Assert(status.Deadline.IsZero(), IsTrue)
+
+	// Mutating APIs are rejected with the reason and the requester.
+	resp, err := server.DialClient.Post(urlPrefix+"/keyspaces", "application/json", nil)
+	c.Assert(err, IsNil)
+	resp.Body.Close()
+	c.Assert(resp.StatusCode, Equals, http.StatusServiceUnavailable)
+	err = postJSON(urlPrefix+"/keyspaces", []byte(`{"name":"ks1"}`))
+	c.Assert(err, ErrorMatches, "reason: upgrade, requester: alice: cluster is in maintenance mode\n")
+	code, _ := requestStatusBody(c, server.DialClient, http.MethodDelete, urlPrefix+"/schedulers/balance-leader-scheduler")
+	c.Assert(code, Equals, http.StatusServiceUnavailable)
+	// Reads continue.
+	var keyspaces []interface{}
+	c.Assert(readJSONWithURL(urlPrefix+"/keyspaces", &keyspaces), IsNil)
+
+	err = doDelete(url)
+	c.Assert(err, IsNil)
+	c.Assert(svr.GetMaintenanceStatus().Enabled, IsFalse)
+	err = postJSON(urlPrefix+"/keyspaces", []byte(`{"name":"ks1"}`))
+	c.Assert(err, IsNil)
+
+	// Exit at the deadline automatically.
+	err = postJSON(url, []byte(`{"reason":"upgrade","duration":"1s"}`))
+	c.Assert(err, IsNil)
+	c.Assert(postJSON(urlPrefix+"/keyspaces", []byte(`{"name":"ks2"}`)), NotNil)
+	time.Sleep(time.Second)
+	testutil.WaitUntil(c, func(c *C) bool {
+		return postJSON(urlPrefix+"/keyspaces", []byte(`{"name":"ks2"}`)) == nil
+	})
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/pkg/apiutil"
 	"github.com/pingcap/pd/pkg/etcdutil"
 	"github.com/pingcap/pd/server"
 	"github.com/pkg/errors"
@@ -40,13 +41,30 @@ func newMemberHandler(svr *server.Server, rd *render.Render) *memberHandler {
 	}
 }
 
+// MemberInfo is a member with its deployment and status information.
+type MemberInfo struct {
+	*pdpb.Member
+	*server.MemberDeployInfo
+	Health       bool `json:"health"`
+	IsLeader     bool `json:"is_leader"`
+	IsEtcdLeader bool `json:"is_etcd_leader"`
+}
+
+// MembersInfo contains all members of the cluster.
+type MembersInfo struct {
+	Header     *pdpb.ResponseHeader `json:"header,omitempty"`
+	Members    []*MemberInfo        `json:"members,omitempty"`
+	Leader     *pdpb.Member         `json:"leader,omitempty"`
+	EtcdLeader *pdpb.Member         `json:"etcd_leader,omitempty"`
+}
+
 func (h *memberHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
 	members, err := h.getMembers()
 	if err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 		return
 	}
-	h.rd.JSON(w, http.StatusOK, members)
+	h.rd.JSON(w, http.StatusOK, h.getMembersInfo(members))
 }
 
 func (h *memberHandler) getMembers() (*pdpb.GetMembersResponse, error) {
@@ -55,20 +73,35 @@ func (h *memberHandler) getMembers() (*pdpb.GetMembersResponse, error) {
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
-	// Fill leader priorities.
+	return members, nil
+}
+
+func (h *memberHandler) getMembersInfo(members *pdpb.GetMembersResponse) *MembersInfo {
+	unhealthMembers := h.svr.CheckHealth(members.GetMembers())
+	infos := make([]*MemberInfo, 0, len(members.GetMembers()))
 	for _, m := range members.GetMembers() {
-		if h.svr.GetEtcdLeader() == 0 {
-			log.Warnf("no etcd leader, skip get leader priority, member: %v", m.GetMemberId())
-			continue
+		info := &MemberInfo{
+			Member:       m,
+			IsLeader:     m.GetMemberId() == members.GetLeader().GetMemberId(),
+			IsEtcdLeader: m.GetMemberId() == members.GetEtcdLeader().GetMemberId(),
 		}
-		leaderPriority, e := h.svr.GetMemberLeaderPriority(m.GetMemberId())
-		if e != nil {
-			log.Errorf("failed to load leader priority, member: %v, err: %v", m.GetMemberId(), e)
-			continue
+		if _, ok := unhealthMembers[m.GetMemberId()]; !ok {
+			info.Health = true
 		}
-		m.LeaderPriority = int32(leaderPriority)
+		deployInfo, err := h.svr.GetMemberDeployInfo(m.GetMemberId())
+		if err != nil {
+			log.Errorf("failed to load deploy info, member: %v, err: %v", m.GetMemberId(), err)
+			deployInfo = &server.MemberDeployInfo{}
+		}
+		info.MemberDeployInfo = deployInfo
+		infos = append(infos, info)
+	}
+	return &MembersInfo{
+		Header:     members.GetHeader(),
+		Members:    infos,
+		Leader:     members.GetLeader(),
+		EtcdLeader: members.GetEtcdLeader(),
 	}
-	return members, nil
 }
 
 func (h *memberHandler) DeleteByName(w http.ResponseWriter, r *http.Request) {
@@ -79,7 +112,7 @@ func (h *memberHandler) DeleteByName(w http.ResponseWriter, r *http.Request) {
 	name := mux.Vars(r)["name"]
 	listResp, err := etcdutil.ListEtcdMembers(client)
 	if err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 		return
 	}
 	for _, m := range listResp.Members {
@@ -96,14 +129,19 @@ func (h *memberHandler) DeleteByName(w http.ResponseWriter, r *http.Request) {
 	// Delete config.
 	err = h.svr.DeleteMemberLeaderPriority(id)
 	if err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
+		return
+	}
+	err = h.svr.DeleteMemberDeployInfo(id)
+	if err != nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 		return
 	}
 
 	// Remove member by id
 	_, err = etcdutil.RemoveEtcdMember(client, id)
 	if err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 		return
 	}
 	h.rd.JSON(w, http.StatusOK, fmt.Sprintf("removed, pd: %s", name))
@@ -120,14 +158,19 @@ func (h *memberHandler) DeleteByID(w http.ResponseWriter, r *http.Request) {
 	// Delete config.
 	err = h.svr.DeleteMemberLeaderPriority(id)
 	if err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
+		return
+	}
+	err = h.svr.DeleteMemberDeployInfo(id)
+	if err != nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 		return
 	}
 
 	client := h.svr.GetClient()
 	_, err = etcdutil.RemoveEtcdMember(client, id)
 	if err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 		return
 	}
 	h.rd.JSON(w, http.StatusOK, fmt.Sprintf("removed, pd: %v", id))
@@ -167,7 +210,7 @@ func (h *memberHandler) SetMemberPropertyByName(w http.ResponseWriter, r *http.R
 			}
 			err := h.svr.SetMemberLeaderPriority(memberID, int(priority))
 			if err != nil {
-				h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+				h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 				return
 			}
 		}
@@ -194,7 +237,7 @@ func (h *leaderHandler) Get(w http.ResponseWriter, r *http.Request) {
 func (h *leaderHandler) Resign(w http.ResponseWriter, r *http.Request) {
 	err := h.svr.ResignLeader("")
 	if err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 		return
 	}
 
@@ -204,9 +247,35 @@ func (h *leaderHandler) Resign(w http.ResponseWriter, r *http.Request) {
 func (h *leaderHandler) Transfer(w http.ResponseWriter, r *http.Request) {
 	err := h.svr.ResignLeader(mux.Vars(r)["next_leader"])
 	if err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 		return
 	}
 
 	h.rd.JSON(w, http.StatusOK, nil)
 }
+
+func (h *leaderHandler) GetLease(w http.ResponseWriter, r *http.Request) {
+	lease := h.svr.GetLeaderLease()
+	if lease == nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotLeader), server.ErrNotLeader.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, lease)
+}
+
+func (h *leaderHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	var limit int
+	if s := r.URL.Query().Get("limit"); s != "" {
+		var err error
+		if limit, err = strconv.Atoi(s); err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	terms, err := h.svr.GetLeaderHistory(limit)
+	if err != nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, terms)
+}
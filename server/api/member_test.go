@@ -20,6 +20,7 @@ import (
 	"net/http"
 	"sort"
 	"strings"
+	"time"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/pdpb"
@@ -83,6 +84,36 @@ func (s *testMemberAPISuite) TestMemberList(c *C) {
 	}
 }
 
+func (s *testMemberAPISuite) TestMemberListInfo(c *C) {
+	addr := s.cfgs[rand.Intn(len(s.cfgs))].ClientUrls + apiPrefix + "/api/v1/members"
+	resp, err := s.hc.Get(addr)
+	c.Assert(err, IsNil)
+	defer resp.Body.Close()
+	buf, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, IsNil)
+
+	got := &MembersInfo{}
+	c.Assert(json.Unmarshal(buf, got), IsNil)
+	c.Assert(got.Members, HasLen, len(s.cfgs))
+	var leaders, etcdLeaders int
+	for _, m := range got.Members {
+		c.Assert(m.Health, IsTrue)
+		c.Assert(m.BinaryVersion, Equals, server.PDReleaseVersion)
+		c.Assert(m.GitHash, Equals, server.PDGitHash)
+		c.Assert(m.DeployPath, Not(Equals), "")
+		if m.IsLeader {
+			leaders++
+			c.Assert(m.GetMemberId(), Equals, got.Leader.GetMemberId())
+		}
+		if m.IsEtcdLeader {
+			etcdLeaders++
+			c.Assert(m.GetMemberId(), Equals, got.EtcdLeader.GetMemberId())
+		}
+	}
+	c.Assert(leaders, Equals, 1)
+	c.Assert(etcdLeaders, Equals, 1)
+}
+
 func (s *testMemberAPISuite) TestMemberLeader(c *C) {
 	leader := s.servers[0].GetLeader()
 	addr := s.cfgs[rand.Intn(len(s.cfgs))].ClientUrls + apiPrefix + "/api/v1/leader"
@@ -97,3 +128,19 @@ func (s *testMemberAPISuite) TestMemberLeader(c *C) {
 	c.Assert(got.GetClientUrls(), DeepEquals, leader.GetClientUrls())
 	c.Assert(got.GetMemberId(), Equals, leader.GetMemberId())
 }
+
+func (s *testMemberAPISuite) TestLeaderLeaseAndHistory(c *C) {
+	leader := mustWaitLeader(c, s.servers)
+	urlPrefix := leader.GetAddr() + apiPrefix + "/api/v1/leader"
+
+	var lease server.LeaderLease
+	c.Assert(readJSONWithURL(urlPrefix+"/lease", &lease), IsNil)
+	c.Assert(lease.Name, Equals, leader.Name())
+	c.Assert(lease.Remaining.Duration, Greater, time.Duration(0))
+
+	var terms []*server.LeaderTerm
+	c.Assert(readJSONWithURL(urlPrefix+"/history?limit=1", &terms), IsNil)
+	c.Assert(terms, HasLen, 1)
+	c.Assert(terms[0].Name, Equals, leader.Name())
+	c.Assert(terms[0].Reason, Equals, "")
+}
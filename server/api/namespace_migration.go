@@ -0,0 +1,83 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/pingcap/pd/server"
+	"github.com/unrolled/render"
+)
+
+type namespaceMigrationHandler struct {
+	*server.Handler
+	rd *render.Render
+}
+
+func newNamespaceMigrationHandler(handler *server.Handler, rd *render.Render) *namespaceMigrationHandler {
+	return &namespaceMigrationHandler{
+		Handler: handler,
+		rd:      rd,
+	}
+}
+
+type namespaceMigrationInput struct {
+	Namespace        string   `json:"namespace"`
+	StoreIDs         []uint64 `json:"store_ids"`
+	Concurrency      int      `json:"concurrency"`
+	RegionsPerMinute int      `json:"regions_per_minute"`
+}
+
+func (h *namespaceMigrationHandler) Post(w http.ResponseWriter, r *http.Request) {
+	var input namespaceMigrationInput
+	if err := readJSONRespondError(h.rd, w, r.Body, &input); err != nil {
+		return
+	}
+	if input.Namespace == "" {
+		h.rd.JSON(w, http.StatusBadRequest, "namespace is required")
+		return
+	}
+	if input.Concurrency < 0 || input.RegionsPerMinute < 0 {
+		h.rd.JSON(w, http.StatusBadRequest, "invalid concurrency or regions per minute")
+		return
+	}
+
+	job, err := h.MigrateNamespace(input.Namespace, input.StoreIDs, input.Concurrency, input.RegionsPerMinute)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, job)
+}
+
+func (h *namespaceMigrationHandler) List(w http.ResponseWriter, r *http.Request) {
+	h.rd.JSON(w, http.StatusOK, h.GetNamespaceMigrationJobs())
+}
+
+func (h *namespaceMigrationHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	job := h.GetNamespaceMigrationJob(id)
+	if job == nil {
+		h.rd.JSON(w, http.StatusNotFound, fmt.Sprintf("namespace migration job %d not found", id))
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, job)
+}
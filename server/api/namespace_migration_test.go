@@ -0,0 +1,123 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/pkg/testutil"
+	"github.com/pingcap/pd/server"
+	"github.com/pingcap/pd/server/core"
+)
+
+var _ = Suite(&testNamespaceMigrationSuite{})
+
+type testNamespaceMigrationSuite struct {
+	svr       *server.Server
+	cleanup   cleanUpFunc
+	urlPrefix string
+}
+
+func (s *testNamespaceMigrationSuite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c)
+	mustWaitLeader(c, []*server.Server{s.svr})
+
+	addr := s.svr.GetAddr()
+	s.urlPrefix = fmt.Sprintf("%s%s/api/v1", addr, apiPrefix)
+
+	mustBootstrapCluster(c, s.svr)
+}
+
+func (s *testNamespaceMigrationSuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+func (s *testNamespaceMigrationSuite) TestNamespaceMigration(c *C) {
+	for id := uint64(1); id <= 4; id++ {
+		mustPutStore(c, s.svr, id, metapb.StoreState_Up, nil)
+		_, err := s.svr.StoreHeartbeat(context.Background(), &pdpb.StoreHeartbeatRequest{
+			Header: &pdpb.RequestHeader{ClusterId: s.svr.ClusterID()},
+			Stats:  &pdpb.StoreStats{StoreId: id, Capacity: 100 << 30, Available: 100 << 30},
+		})
+		c.Assert(err, IsNil)
+	}
+	for _, id := range []uint64{10, 20} {
+		peers := []*metapb.Peer{{Id: id + 1, StoreId: 1}, {Id: id + 2, StoreId: 2}, {Id: id + 3, StoreId: 3}}
+		mustRegionHeartbeat(c, s.svr, core.NewRegionInfo(&metapb.Region{
+			Id:          id,
+			StartKey:    []byte(fmt.Sprintf("a%d", id)),
+			EndKey:      []byte(fmt.Sprintf("a%d", id+10)),
+			Peers:       peers,
+			RegionEpoch: &metapb.RegionEpoch{ConfVer: 1, Version: 2},
+		}, peers[0]))
+	}
+
+	migrationURL := s.urlPrefix + "/namespace-migrations"
+	c.Assert(postJSON(migrationURL, []byte(`{"namespace":"unknown"}`)), NotNil)
+	c.Assert(postJSON(migrationURL, []byte(`{"namespace":"global","store_ids":[2,3,5]}`)), NotNil)
+	c.Assert(postJSON(migrationURL, []byte(`{"namespace":"global","store_ids":[2,3]}`)), NotNil)
+	c.Assert(postJSON(migrationURL, []byte(`{"namespace":"global","concurrency":-1}`)), NotNil)
+
+	c.Assert(postJSON(migrationURL, []byte(`{"namespace":"global","store_ids":[4,3,2],"concurrency":2}`)), IsNil)
+	var jobs []*server.NamespaceMigrationJob
+	c.Assert(readJSONWithURL(migrationURL, &jobs), IsNil)
+	c.Assert(jobs, HasLen, 1)
+	job := jobs[0]
+	c.Assert(job.StoreIDs, DeepEquals, []uint64{2, 3, 4})
+	c.Assert(job.Concurrency, Equals, 2)
+	c.Assert(job.Status, Equals, server.JobRunning)
+
+	// The peers on store 1 are moved.
+	handler := s.svr.GetHandler()
+	testutil.WaitUntil(c, func(c *C) bool {
+		for _, id := range []uint64{10, 20} {
+			op, err := handler.GetOperator(id)
+			if err != nil || op.Desc() != "migrate-namespace-region" {
+				return false
+			}
+		}
+		return true
+	})
+	jobURL := fmt.Sprintf("%s/%d", migrationURL, job.ID)
+	testutil.WaitUntil(c, func(c *C) bool {
+		c.Assert(readJSONWithURL(jobURL, job), IsNil)
+		return job.TotalRegions == 2
+	})
+	c.Assert(job.MigratedRegions, Equals, 0)
+
+	// The job is paused and resumed by the job APIs.
+	jobsURL := fmt.Sprintf("%s/jobs/%d", s.urlPrefix, job.ID)
+	code, _ := requestStatusBody(c, server.DialClient, http.MethodPost, jobsURL+"/resume")
+	c.Assert(code, Equals, http.StatusBadRequest)
+	code, _ = requestStatusBody(c, server.DialClient, http.MethodPost, jobsURL+"/pause")
+	c.Assert(code, Equals, http.StatusOK)
+	c.Assert(readJSONWithURL(jobURL, job), IsNil)
+	c.Assert(job.Status, Equals, server.JobPaused)
+	code, _ = requestStatusBody(c, server.DialClient, http.MethodPost, jobsURL+"/pause")
+	c.Assert(code, Equals, http.StatusBadRequest)
+	code, _ = requestStatusBody(c, server.DialClient, http.MethodPost, jobsURL+"/resume")
+	c.Assert(code, Equals, http.StatusOK)
+	c.Assert(readJSONWithURL(jobURL, job), IsNil)
+	c.Assert(job.Status, Equals, server.JobRunning)
+	code, _ = requestStatusBody(c, server.DialClient, http.MethodPost, jobsURL+"/cancel")
+	c.Assert(code, Equals, http.StatusOK)
+
+	code, _ = requestStatusBody(c, server.DialClient, http.MethodGet, migrationURL+"/100000")
+	c.Assert(code, Equals, http.StatusNotFound)
+}
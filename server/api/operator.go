@@ -100,6 +100,12 @@ func (h *operatorHandler) Post(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+	if dryRun && !dryRunOperators[name] {
+		h.r.JSON(w, http.StatusBadRequest, "dry run is not supported by the operator")
+		return
+	}
+
 	switch name {
 	case "transfer-leader":
 		regionID, ok := input["region_id"].(float64)
@@ -107,11 +113,24 @@ func (h *operatorHandler) Post(w http.ResponseWriter, r *http.Request) {
 			h.r.JSON(w, http.StatusBadRequest, "missing region id")
 			return
 		}
+		if auto, _ := input["auto"].(bool); auto {
+			h.transferLeaderAuto(w, uint64(regionID), dryRun)
+			return
+		}
 		storeID, ok := input["to_store_id"].(float64)
 		if !ok {
 			h.r.JSON(w, http.StatusBadRequest, "missing store id to transfer leader to")
 			return
 		}
+		if dryRun {
+			result, err := h.DryRunTransferLeaderOperator(uint64(regionID), uint64(storeID))
+			if err != nil {
+				h.r.JSON(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			h.r.JSON(w, http.StatusOK, result)
+			return
+		}
 		if err := h.AddTransferLeaderOperator(uint64(regionID), uint64(storeID)); err != nil {
 			h.r.JSON(w, http.StatusInternalServerError, err.Error())
 			return
@@ -131,6 +150,15 @@ func (h *operatorHandler) Post(w http.ResponseWriter, r *http.Request) {
 			h.r.JSON(w, http.StatusBadRequest, "missing store ids to transfer region to")
 			return
 		}
+		if dryRun {
+			result, err := h.DryRunTransferRegionOperator(uint64(regionID), storeIDs)
+			if err != nil {
+				h.r.JSON(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			h.r.JSON(w, http.StatusOK, result)
+			return
+		}
 		if err := h.AddTransferRegionOperator(uint64(regionID), storeIDs); err != nil {
 			h.r.JSON(w, http.StatusInternalServerError, err.Error())
 			return
@@ -166,6 +194,15 @@ func (h *operatorHandler) Post(w http.ResponseWriter, r *http.Request) {
 			h.r.JSON(w, http.StatusBadRequest, "invalid store id to transfer peer to")
 			return
 		}
+		if dryRun {
+			result, err := h.DryRunAddPeerOperator(uint64(regionID), uint64(storeID))
+			if err != nil {
+				h.r.JSON(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			h.r.JSON(w, http.StatusOK, result)
+			return
+		}
 		if err := h.AddAddPeerOperator(uint64(regionID), uint64(storeID)); err != nil {
 			h.r.JSON(w, http.StatusInternalServerError, err.Error())
 			return
@@ -212,7 +249,7 @@ func (h *operatorHandler) Post(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		if err := h.AddSplitRegionOperator(uint64(regionID), policy); err != nil {
-			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			errorResp(h.r, w, err)
 			return
 		}
 	case "scatter-region":
@@ -222,7 +259,7 @@ func (h *operatorHandler) Post(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		if err := h.AddScatterRegionOperator(uint64(regionID)); err != nil {
-			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			errorResp(h.r, w, err)
 			return
 		}
 	default:
@@ -233,6 +270,51 @@ func (h *operatorHandler) Post(w http.ResponseWriter, r *http.Request) {
 	h.r.JSON(w, http.StatusOK, nil)
 }
 
+// transferLeaderAuto transfers leader to the follower picked by PD.
+func (h *operatorHandler) transferLeaderAuto(w http.ResponseWriter, regionID uint64, dryRun bool) {
+	if dryRun {
+		result, err := h.DryRunAutoTransferLeaderOperator(regionID)
+		if err != nil {
+			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		h.r.JSON(w, http.StatusOK, result)
+		return
+	}
+	if err := h.AddAutoTransferLeaderOperator(regionID); err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.r.JSON(w, http.StatusOK, nil)
+}
+
+// batchOperators is the input of adding operators in a batch.
+type batchOperators struct {
+	Operators []*server.BatchOperator `json:"operators"`
+}
+
+// PostBatch adds the admin operators in a batch, all of them are added or
+// none is added.
+func (h *operatorHandler) PostBatch(w http.ResponseWriter, r *http.Request) {
+	var input batchOperators
+	if err := readJSONRespondError(h.r, w, r.Body, &input); err != nil {
+		return
+	}
+	results, err := h.AddBatchOperators(input.Operators)
+	if err != nil {
+		errorResp(h.r, w, err)
+		return
+	}
+	h.r.JSON(w, http.StatusOK, results)
+}
+
+// dryRunOperators are the operators which support dry run.
+var dryRunOperators = map[string]bool{
+	"transfer-leader": true,
+	"transfer-region": true,
+	"add-peer":        true,
+}
+
 func (h *operatorHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	id := mux.Vars(r)["region_id"]
 
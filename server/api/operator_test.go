@@ -93,6 +93,170 @@ func (s *testOperatorSuite) TestAddRemovePeer(c *C) {
 
 }
 
+func (s *testOperatorSuite) TestBatch(c *C) {
+	for _, id := range []uint64{31, 32, 33} {
+		mustPutStore(c, s.svr, id, metapb.StoreState_Up, nil)
+	}
+	for i := uint64(0); i < 4; i++ {
+		peer1 := &metapb.Peer{Id: 300 + i*2, StoreId: 31}
+		peer2 := &metapb.Peer{Id: 301 + i*2, StoreId: 32}
+		region := &metapb.Region{Id: 30 + i, StartKey: []byte{byte(i)}, EndKey: []byte{byte(i + 1)}, Peers: []*metapb.Peer{peer1, peer2}}
+		mustRegionHeartbeat(c, s.svr, core.NewRegionInfo(region, peer1))
+	}
+	batchURL := fmt.Sprintf("%s/operators/batch", s.urlPrefix)
+	mustNoOperator := func() {
+		for i := 30; i < 34; i++ {
+			operator := mustReadURL(c, fmt.Sprintf("%s/operators/%d", s.urlPrefix, i))
+			c.Assert(strings.Contains(operator, "operator not found"), IsTrue)
+		}
+	}
+
+	invalid := []string{
+		// Conflict on a region.
+		`{"operators": [{"name": "transfer-leader", "region_id": 30, "to_store_id": 32}, {"name": "remove-peer", "region_id": 30, "store_id": 32}]}`,
+		// Store 33 would receive too many snapshots.
+		`{"operators": [{"name": "add-peer", "region_id": 30, "store_id": 33}, {"name": "add-peer", "region_id": 31, "store_id": 33},
+			{"name": "add-peer", "region_id": 32, "store_id": 33}, {"name": "add-peer", "region_id": 33, "store_id": 33}]}`,
+		// The region does not exist.
+		`{"operators": [{"name": "transfer-leader", "region_id": 30, "to_store_id": 32}, {"name": "remove-peer", "region_id": 300, "store_id": 32}]}`,
+		`{"operators": [{"name": "merge-region", "region_id": 30}]}`,
+		`{"operators": []}`,
+	}
+	for _, batch := range invalid {
+		c.Assert(postJSON(batchURL, []byte(batch)), NotNil)
+		mustNoOperator()
+	}
+
+	res, err := server.DialClient.Post(batchURL, "application/json", strings.NewReader(`{"operators": [
+		{"name": "transfer-leader", "region_id": 30, "to_store_id": 32},
+		{"name": "add-peer", "region_id": 31, "store_id": 33},
+		{"name": "remove-peer", "region_id": 32, "store_id": 32}]}`))
+	c.Assert(err, IsNil)
+	c.Assert(res.StatusCode, Equals, http.StatusOK)
+	var results []*server.BatchOperatorResult
+	c.Assert(readJSON(res.Body, &results), IsNil)
+	c.Assert(results, HasLen, 3)
+	for i, result := range results {
+		c.Assert(result.RegionID, Equals, uint64(30+i))
+		regionURL := fmt.Sprintf("%s/operators/%d", s.urlPrefix, result.RegionID)
+		c.Assert(strings.Contains(mustReadURL(c, regionURL), "admin"), IsTrue)
+		c.Assert(doDelete(regionURL), IsNil)
+	}
+}
+
+func (s *testOperatorSuite) TestTransferLeaderToRejectLeaderStore(c *C) {
+	mustPutStore(c, s.svr, 1, metapb.StoreState_Up, nil)
+	mustPutStore(c, s.svr, 5, metapb.StoreState_Up, []*metapb.StoreLabel{{Key: "noleader", Value: "true"}})
+	c.Assert(s.svr.SetLabelProperty("reject-leader", "noleader", "true"), IsNil)
+	defer s.svr.DeleteLabelProperty("reject-leader", "noleader", "true")
+
+	peer1 := &metapb.Peer{Id: 11, StoreId: 1}
+	peer2 := &metapb.Peer{Id: 12, StoreId: 5}
+	region := &metapb.Region{Id: 10, Peers: []*metapb.Peer{peer1, peer2}}
+	mustRegionHeartbeat(c, s.svr, core.NewRegionInfo(region, peer1))
+
+	err := postJSON(fmt.Sprintf("%s/operators", s.urlPrefix), []byte(`{"name":"transfer-leader", "region_id": 10, "to_store_id": 5}`))
+	c.Assert(err, NotNil)
+	c.Assert(strings.Contains(err.Error(), "rejects leaders"), IsTrue)
+}
+
+func (s *testOperatorSuite) TestTransferLeaderAuto(c *C) {
+	// Store 42 is busy, store 45 has no heartbeat.
+	for id := uint64(41); id <= 45; id++ {
+		mustPutStore(c, s.svr, id, metapb.StoreState_Up, nil)
+		if id == 45 {
+			continue
+		}
+		_, err := s.svr.StoreHeartbeat(context.Background(), &pdpb.StoreHeartbeatRequest{
+			Header: &pdpb.RequestHeader{ClusterId: s.svr.ClusterID()},
+			Stats:  &pdpb.StoreStats{StoreId: id, Capacity: 100 << 30, Available: 100 << 30, IsBusy: id == 42},
+		})
+		c.Assert(err, IsNil)
+	}
+
+	// The peer on store 43 is pending.
+	peers := []*metapb.Peer{{Id: 41, StoreId: 41}, {Id: 42, StoreId: 42}, {Id: 43, StoreId: 43}, {Id: 44, StoreId: 44}, {Id: 45, StoreId: 45}}
+	region := &metapb.Region{Id: 40, Peers: peers, RegionEpoch: &metapb.RegionEpoch{ConfVer: 1, Version: 1}}
+	mustRegionHeartbeat(c, s.svr, core.NewRegionInfo(region, peers[0], core.WithPendingPeers(peers[2:3])))
+
+	operatorsURL := fmt.Sprintf("%s/operators", s.urlPrefix)
+	result := mustDryRunOperator(c, operatorsURL+"?dry_run=true", `{"name":"transfer-leader", "region_id": 40, "auto": true}`)
+	c.Assert(result.Steps, DeepEquals, []string{"transfer leader from store 41 to store 44"})
+
+	err := postJSON(operatorsURL, []byte(`{"name":"transfer-leader", "region_id": 40, "auto": true}`))
+	c.Assert(err, IsNil)
+	regionURL := fmt.Sprintf("%s/operators/%d", s.urlPrefix, region.GetId())
+	c.Assert(strings.Contains(mustReadURL(c, regionURL), "to store 44"), IsTrue)
+	c.Assert(doDelete(regionURL), IsNil)
+
+	// No follower can be the leader.
+	region = &metapb.Region{Id: 46, Peers: peers[:3], RegionEpoch: &metapb.RegionEpoch{ConfVer: 1, Version: 1}}
+	mustRegionHeartbeat(c, s.svr, core.NewRegionInfo(region, peers[0], core.WithPendingPeers(peers[2:3])))
+	err = postJSON(operatorsURL, []byte(`{"name":"transfer-leader", "region_id": 46, "auto": true}`))
+	c.Assert(err, NotNil)
+	c.Assert(strings.Contains(err.Error(), "no follower"), IsTrue)
+}
+
+func (s *testOperatorSuite) TestDryRun(c *C) {
+	mustPutStore(c, s.svr, 1, metapb.StoreState_Up, nil)
+	mustPutStore(c, s.svr, 2, metapb.StoreState_Up, nil)
+	mustPutStore(c, s.svr, 4, metapb.StoreState_Up, nil)
+
+	peer1 := &metapb.Peer{Id: 1, StoreId: 1}
+	peer2 := &metapb.Peer{Id: 2, StoreId: 2}
+	region := &metapb.Region{Id: 1, Peers: []*metapb.Peer{peer1, peer2}}
+	mustRegionHeartbeat(c, s.svr, core.NewRegionInfo(region, peer1, core.SetApproximateSize(10)))
+
+	operatorsURL := fmt.Sprintf("%s/operators?dry_run=true", s.urlPrefix)
+	regionURL := fmt.Sprintf("%s/operators/%d", s.urlPrefix, region.GetId())
+
+	result := mustDryRunOperator(c, operatorsURL, `{"name":"transfer-leader", "region_id": 1, "to_store_id": 2}`)
+	c.Assert(result.Steps, DeepEquals, []string{"transfer leader from store 1 to store 2"})
+	c.Assert(result.Addable, IsTrue)
+	c.Assert(result.SnapshotSize, Equals, int64(0))
+	c.Assert(result.Limits, HasLen, 1)
+	c.Assert(result.Limits[0].Kind, Equals, "leader")
+	c.Assert(result.Stores, HasLen, 2)
+	c.Assert(result.Stores[0].LeaderCount, Equals, int64(-1))
+	c.Assert(result.Stores[1].LeaderCount, Equals, int64(1))
+
+	result = mustDryRunOperator(c, operatorsURL, `{"name":"add-peer", "region_id": 1, "store_id": 4}`)
+	c.Assert(result.Steps, DeepEquals, []string{"add learner peer 0 on store 4", "promote learner peer 0 on store 4 to voter"})
+	c.Assert(result.SnapshotSize, Equals, int64(10))
+	c.Assert(result.Stores, HasLen, 1)
+	c.Assert(result.Stores[0].StoreID, Equals, uint64(4))
+	c.Assert(result.Stores[0].ReceiveSnapshot, IsTrue)
+	c.Assert(result.Stores[0].RegionSize, Equals, int64(10))
+	// The store has no heartbeat yet.
+	c.Assert(result.Stores[0].RejectedBy, DeepEquals, []string{"store-state-filter", "storage-threshold-filter"})
+
+	result = mustDryRunOperator(c, operatorsURL, `{"name":"transfer-region", "region_id": 1, "to_store_ids": [1, 4]}`)
+	c.Assert(result.SnapshotSize, Equals, int64(10))
+	c.Assert(result.Stores, HasLen, 2)
+	c.Assert(result.Stores[0].StoreID, Equals, uint64(2))
+	c.Assert(result.Stores[0].RegionSize, Equals, int64(-10))
+	c.Assert(result.Stores[1].StoreID, Equals, uint64(4))
+	c.Assert(result.Stores[1].ReceiveSnapshot, IsTrue)
+
+	// Nothing is dispatched.
+	c.Assert(strings.Contains(mustReadURL(c, regionURL), "operator not found"), IsTrue)
+
+	err := postJSON(operatorsURL, []byte(`{"name":"remove-peer", "region_id": 1, "store_id": 2}`))
+	c.Assert(err, NotNil)
+	err = postJSON(operatorsURL, []byte(`{"name":"add-peer", "region_id": 1, "store_id": 2}`))
+	c.Assert(err, NotNil)
+}
+
+func mustDryRunOperator(c *C, url string, body string) *server.OperatorDryRun {
+	res, err := http.Post(url, "application/json", strings.NewReader(body))
+	c.Assert(err, IsNil)
+	c.Assert(res.StatusCode, Equals, http.StatusOK)
+	result := &server.OperatorDryRun{}
+	err = readJSON(res.Body, result)
+	c.Assert(err, IsNil)
+	return result
+}
+
 func mustPutStore(c *C, svr *server.Server, id uint64, state metapb.StoreState, labels []*metapb.StoreLabel) {
 	_, err := svr.PutStore(context.Background(), &pdpb.PutStoreRequest{
 		Header: &pdpb.RequestHeader{ClusterId: svr.ClusterID()},
@@ -109,7 +273,7 @@ func mustPutStore(c *C, svr *server.Server, id uint64, state metapb.StoreState,
 
 func mustRegionHeartbeat(c *C, svr *server.Server, region *core.RegionInfo) {
 	cluster := svr.GetRaftCluster()
-	err := cluster.HandleRegionHeartbeat(region)
+	err := cluster.HandleRegionHeartbeat(context.Background(), region)
 	c.Assert(err, IsNil)
 }
 
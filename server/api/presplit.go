@@ -0,0 +1,86 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/pingcap/pd/pkg/apiutil"
+	"github.com/pingcap/pd/server"
+	"github.com/unrolled/render"
+)
+
+type preSplitHandler struct {
+	*server.Handler
+	rd *render.Render
+}
+
+func newPreSplitHandler(handler *server.Handler, rd *render.Render) *preSplitHandler {
+	return &preSplitHandler{
+		Handler: handler,
+		rd:      rd,
+	}
+}
+
+type preSplitInput struct {
+	// Prefix is the hex encoded table or index key prefix, such as `t{tableID}_r`.
+	Prefix      string `json:"prefix"`
+	RowCount    int64  `json:"row_count"`
+	RegionCount int    `json:"region_count"`
+}
+
+func (h *preSplitHandler) Post(w http.ResponseWriter, r *http.Request) {
+	var input preSplitInput
+	if err := readJSONRespondError(h.rd, w, r.Body, &input); err != nil {
+		return
+	}
+	prefix, err := hex.DecodeString(input.Prefix)
+	if err != nil || len(prefix) == 0 {
+		h.rd.JSON(w, http.StatusBadRequest, fmt.Sprintf("invalid prefix %q", input.Prefix))
+		return
+	}
+	if input.RowCount <= 0 || input.RegionCount < 0 {
+		h.rd.JSON(w, http.StatusBadRequest, "invalid row count or region count")
+		return
+	}
+
+	job, err := h.PreSplitRegions(prefix, input.RowCount, input.RegionCount)
+	if err != nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, job)
+}
+
+func (h *preSplitHandler) List(w http.ResponseWriter, r *http.Request) {
+	h.rd.JSON(w, http.StatusOK, h.GetPreSplitJobs())
+}
+
+func (h *preSplitHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	job := h.GetPreSplitJob(id)
+	if job == nil {
+		h.rd.JSON(w, http.StatusNotFound, fmt.Sprintf("pre-split job %d not found", id))
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, job)
+}
@@ -0,0 +1,120 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/pkg/splitpb"
+	"github.com/pingcap/pd/pkg/testutil"
+	"github.com/pingcap/pd/server"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/schedule"
+	"github.com/pingcap/pd/table"
+	"google.golang.org/grpc"
+)
+
+var _ = Suite(&testPreSplitSuite{})
+
+type testPreSplitSuite struct {
+	svr       *server.Server
+	cleanup   cleanUpFunc
+	urlPrefix string
+}
+
+func (s *testPreSplitSuite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c)
+	mustWaitLeader(c, []*server.Server{s.svr})
+
+	addr := s.svr.GetAddr()
+	s.urlPrefix = fmt.Sprintf("%s%s/api/v1/regions/presplit", addr, apiPrefix)
+
+	mustBootstrapCluster(c, s.svr)
+}
+
+func (s *testPreSplitSuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+func (s *testPreSplitSuite) TestPreSplit(c *C) {
+	prefix := []byte("t\x80\x00\x00\x00\x00\x00\x00\x01_r")
+
+	// Invalid input.
+	input := map[string]interface{}{"prefix": "zz", "row_count": 100}
+	data, err := json.Marshal(input)
+	c.Assert(err, IsNil)
+	c.Assert(postJSON(s.urlPrefix, data), NotNil)
+	input = map[string]interface{}{"prefix": hex.EncodeToString(prefix), "row_count": 0}
+	data, err = json.Marshal(input)
+	c.Assert(err, IsNil)
+	c.Assert(postJSON(s.urlPrefix, data), NotNil)
+
+	input = map[string]interface{}{"prefix": hex.EncodeToString(prefix), "row_count": 100, "region_count": 2}
+	data, err = json.Marshal(input)
+	c.Assert(err, IsNil)
+	c.Assert(postJSON(s.urlPrefix, data), IsNil)
+
+	var jobs []*server.PreSplitJob
+	c.Assert(readJSONWithURL(s.urlPrefix, &jobs), IsNil)
+	c.Assert(jobs, HasLen, 1)
+	job := jobs[0]
+	c.Assert(job.TargetCount, Equals, 2)
+	c.Assert(job.Status, Equals, server.PreSplitJobSplitting)
+
+	// The region containing the split key should be split at the key.
+	handler := s.svr.GetHandler()
+	splitKeys := table.GenerateSplitKeys(prefix, 100, 2)
+	testutil.WaitUntil(c, func(c *C) bool {
+		op, err := handler.GetOperator(region.GetId())
+		return err == nil && op.Desc() == "pre-split-region"
+	})
+	op, err := handler.GetOperator(region.GetId())
+	c.Assert(err, IsNil)
+	c.Assert(op.Step(0).(schedule.SplitRegion).SplitKeys, DeepEquals, [][]byte{splitKeys[0]})
+
+	// The leader store gets the split key.
+	mustRegionHeartbeat(c, s.svr, core.NewRegionInfo(region, region.Peers[0]))
+	conn, err := grpc.Dial(strings.TrimPrefix(s.svr.GetAddr(), "http://"), grpc.WithInsecure())
+	c.Assert(err, IsNil)
+	defer conn.Close()
+	resp, err := splitpb.NewSplitClient(conn).GetSplitCommands(context.Background(), &splitpb.GetSplitCommandsRequest{
+		Header:  newRequestHeader(s.svr.ClusterID()),
+		StoreId: region.Peers[0].GetStoreId(),
+	})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetCommands(), HasLen, 1)
+	c.Assert(resp.GetCommands()[0].GetSplitKeys(), DeepEquals, [][]byte{splitKeys[0]})
+
+	// Simulate the split.
+	splitKey := []byte(splitKeys[0])
+	left := &metapb.Region{Id: region.GetId(), EndKey: splitKey, Peers: peers, RegionEpoch: &metapb.RegionEpoch{ConfVer: 1, Version: 2}}
+	right := &metapb.Region{Id: 100, StartKey: splitKey, Peers: []*metapb.Peer{{Id: 101, StoreId: store.GetId()}}, RegionEpoch: &metapb.RegionEpoch{ConfVer: 1, Version: 2}}
+	mustRegionHeartbeat(c, s.svr, core.NewRegionInfo(left, left.Peers[0]))
+	mustRegionHeartbeat(c, s.svr, core.NewRegionInfo(right, right.Peers[0]))
+
+	testutil.WaitUntil(c, func(c *C) bool {
+		job = &server.PreSplitJob{}
+		c.Assert(readJSONWithURL(fmt.Sprintf("%s/%d", s.urlPrefix, jobs[0].ID), job), IsNil)
+		return job.Status == server.PreSplitJobFinished
+	})
+	c.Assert(job.RegionCount, Equals, 2)
+
+	c.Assert(readJSONWithURL(fmt.Sprintf("%s/%d", s.urlPrefix, 100), job), NotNil)
+}
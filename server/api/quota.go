@@ -0,0 +1,52 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/pingcap/pd/server"
+	"github.com/unrolled/render"
+)
+
+type quotaHandler struct {
+	*server.Handler
+	rd *render.Render
+}
+
+func newQuotaHandler(handler *server.Handler, rd *render.Render) *quotaHandler {
+	return &quotaHandler{
+		Handler: handler,
+		rd:      rd,
+	}
+}
+
+func (h *quotaHandler) List(w http.ResponseWriter, r *http.Request) {
+	usages, err := h.GetQuotaUsage()
+	if err != nil {
+		errorResp(h.rd, w, err)
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, usages)
+}
+
+func (h *quotaHandler) Get(w http.ResponseWriter, r *http.Request) {
+	usage, err := h.GetNamespaceQuotaUsage(mux.Vars(r)["name"])
+	if err != nil {
+		errorResp(h.rd, w, err)
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, usage)
+}
@@ -0,0 +1,76 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/server"
+	"github.com/pingcap/pd/server/namespace"
+)
+
+var _ = Suite(&testQuotaSuite{})
+
+type testQuotaSuite struct {
+	svr       *server.Server
+	cleanup   cleanUpFunc
+	urlPrefix string
+}
+
+func (s *testQuotaSuite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c)
+	mustWaitLeader(c, []*server.Server{s.svr})
+
+	addr := s.svr.GetAddr()
+	s.urlPrefix = fmt.Sprintf("%s%s/api/v1", addr, apiPrefix)
+
+	mustBootstrapCluster(c, s.svr)
+	mustPutStore(c, s.svr, 1, metapb.StoreState_Up, nil)
+	mustRegionHeartbeat(c, s.svr, newTestRegionInfo(2, 1, []byte("a"), []byte("b")))
+}
+
+func (s *testQuotaSuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+func (s *testQuotaSuite) TestQuota(c *C) {
+	var usages []*server.NamespaceQuotaUsage
+	c.Assert(readJSONWithURL(s.urlPrefix+"/quotas", &usages), IsNil)
+	c.Assert(usages, Not(HasLen), 0)
+
+	s.svr.SetNamespaceConfig(namespace.DefaultNamespace, server.NamespaceConfig{
+		NamespaceQuota: server.NamespaceQuota{MaxRegionCount: 1},
+	})
+	defer s.svr.DeleteNamespaceConfig(namespace.DefaultNamespace)
+	c.Assert(s.svr.GetNamespaceConfig(namespace.DefaultNamespace).MaxRegionCount, Equals, uint64(1))
+	usage := &server.NamespaceQuotaUsage{}
+	c.Assert(readJSONWithURL(s.urlPrefix+"/quotas/"+namespace.DefaultNamespace, usage), IsNil)
+	c.Assert(usage.Quota.MaxRegionCount, Equals, uint64(1))
+	c.Assert(usage.RemainingRegionCount, NotNil)
+	c.Assert(*usage.RemainingRegionCount, Equals, uint64(0))
+
+	// The split exceeds the region count quota.
+	body := []byte(`{"name": "split-region", "region_id": 2, "policy": "approximate"}`)
+	resp, err := server.DialClient.Post(s.urlPrefix+"/operators", "application/json", bytes.NewBuffer(body))
+	c.Assert(err, IsNil)
+	resp.Body.Close()
+	c.Assert(resp.StatusCode, Equals, http.StatusTooManyRequests)
+
+	code, _ := requestStatusBody(c, server.DialClient, "GET", s.urlPrefix+"/quotas/unknown-namespace")
+	c.Assert(code, Equals, http.StatusNotFound)
+}
@@ -19,6 +19,7 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/pingcap/pd/pkg/apiutil"
 	"github.com/pingcap/pd/server"
 	log "github.com/sirupsen/logrus"
 )
@@ -49,7 +50,7 @@ func (h *redirector) ServeHTTP(w http.ResponseWriter, r *http.Request, next http
 	// Prevent more than one redirection.
 	if name := r.Header.Get(redirectorHeader); len(name) != 0 {
 		log.Errorf("redirect from %v, but %v is not leader", name, h.s.Name())
-		http.Error(w, errRedirectToNotLeader, http.StatusInternalServerError)
+		http.Error(w, errRedirectToNotLeader, apiutil.ErrorStatus(server.ErrNotLeader))
 		return
 	}
 
@@ -21,6 +21,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/pkg/apiutil"
 	"github.com/pingcap/pd/server"
 	"github.com/pingcap/pd/server/core"
 	"github.com/unrolled/render"
@@ -36,6 +37,7 @@ type regionInfo struct {
 	Leader          *metapb.Peer      `json:"leader,omitempty"`
 	DownPeers       []*pdpb.PeerStats `json:"down_peers,omitempty"`
 	PendingPeers    []*metapb.Peer    `json:"pending_peers,omitempty"`
+	Witnesses       []*metapb.Peer    `json:"witnesses,omitempty"`
 	WrittenBytes    uint64            `json:"written_bytes,omitempty"`
 	ReadBytes       uint64            `json:"read_bytes,omitempty"`
 	ApproximateSize int64             `json:"approximate_size,omitempty"`
@@ -55,6 +57,7 @@ func newRegionInfo(r *core.RegionInfo) *regionInfo {
 		Leader:          r.GetLeader(),
 		DownPeers:       r.GetDownPeers(),
 		PendingPeers:    r.GetPendingPeers(),
+		Witnesses:       r.GetWitnesses(),
 		WrittenBytes:    r.GetBytesWritten(),
 		ReadBytes:       r.GetBytesRead(),
 		ApproximateSize: r.GetApproximateSize(),
@@ -82,7 +85,7 @@ func newRegionHandler(svr *server.Server, rd *render.Render) *regionHandler {
 func (h *regionHandler) GetRegionByID(w http.ResponseWriter, r *http.Request) {
 	cluster := h.svr.GetRaftCluster()
 	if cluster == nil {
-		h.rd.JSON(w, http.StatusInternalServerError, server.ErrNotBootstrapped.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
 		return
 	}
 
@@ -101,7 +104,7 @@ func (h *regionHandler) GetRegionByID(w http.ResponseWriter, r *http.Request) {
 func (h *regionHandler) GetRegionByKey(w http.ResponseWriter, r *http.Request) {
 	cluster := h.svr.GetRaftCluster()
 	if cluster == nil {
-		h.rd.JSON(w, http.StatusInternalServerError, server.ErrNotBootstrapped.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
 		return
 	}
 	vars := mux.Vars(r)
@@ -136,7 +139,10 @@ func convertToAPIRegions(regions []*core.RegionInfo) *regionsInfo {
 func (h *regionsHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	cluster := h.svr.GetRaftCluster()
 	if cluster == nil {
-		h.rd.JSON(w, http.StatusInternalServerError, server.ErrNotBootstrapped.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
+		return
+	}
+	if checkNotModified(w, r, cluster.GetRegionsVersion()) {
 		return
 	}
 	regions := cluster.GetRegions()
@@ -147,7 +153,7 @@ func (h *regionsHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 func (h *regionsHandler) ScanRegionsByKey(w http.ResponseWriter, r *http.Request) {
 	cluster := h.svr.GetRaftCluster()
 	if cluster == nil {
-		h.rd.JSON(w, http.StatusInternalServerError, server.ErrNotBootstrapped.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
 		return
 	}
 
@@ -165,15 +171,66 @@ func (h *regionsHandler) ScanRegionsByKey(w http.ResponseWriter, r *http.Request
 	if limit > maxRegionLimit {
 		limit = maxRegionLimit
 	}
+	if checkNotModified(w, r, cluster.GetRegionsVersion()) {
+		return
+	}
 	regions := cluster.ScanRegionsByKey([]byte(startKey), limit)
 	regionsInfo := convertToAPIRegions(regions)
 	h.rd.JSON(w, http.StatusOK, regionsInfo)
 }
 
+func (h *regionsHandler) GetRegionsInRange(w http.ResponseWriter, r *http.Request) {
+	cluster := h.svr.GetRaftCluster()
+	if cluster == nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
+		return
+	}
+
+	query := r.URL.Query()
+	limit := defaultRegionLimit
+	if limitStr := query.Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if limit <= 0 || limit > maxRegionLimit {
+		limit = maxRegionLimit
+	}
+	if checkNotModified(w, r, cluster.GetRegionsVersion()) {
+		return
+	}
+	regions := cluster.GetRegionsInRange([]byte(query.Get("start_key")), []byte(query.Get("end_key")), limit)
+	h.rd.JSON(w, http.StatusOK, convertToAPIRegions(regions))
+}
+
+func (h *regionsHandler) GetRangeSummary(w http.ResponseWriter, r *http.Request) {
+	cluster := h.svr.GetRaftCluster()
+	if cluster == nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
+		return
+	}
+
+	query := r.URL.Query()
+	sample := defaultRangeSampleLimit
+	if sampleStr := query.Get("sample"); sampleStr != "" {
+		var err error
+		sample, err = strconv.Atoi(sampleStr)
+		if err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	summary := cluster.GetRangeSummary([]byte(query.Get("start_key")), []byte(query.Get("end_key")), sample)
+	h.rd.JSON(w, http.StatusOK, summary)
+}
+
 func (h *regionsHandler) GetStoreRegions(w http.ResponseWriter, r *http.Request) {
 	cluster := h.svr.GetRaftCluster()
 	if cluster == nil {
-		h.rd.JSON(w, http.StatusInternalServerError, server.ErrNotBootstrapped.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
 		return
 	}
 
@@ -183,6 +240,9 @@ func (h *regionsHandler) GetStoreRegions(w http.ResponseWriter, r *http.Request)
 		h.rd.JSON(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	if checkNotModified(w, r, cluster.GetRegionsVersion()) {
+		return
+	}
 	regions := cluster.GetStoreRegions(uint64(id))
 	regionsInfo := convertToAPIRegions(regions)
 	h.rd.JSON(w, http.StatusOK, regionsInfo)
@@ -192,7 +252,7 @@ func (h *regionsHandler) GetMissPeerRegions(w http.ResponseWriter, r *http.Reque
 	handler := h.svr.GetHandler()
 	regions, err := handler.GetMissPeerRegions()
 	if err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 		return
 	}
 	regionsInfo := convertToAPIRegions(regions)
@@ -203,7 +263,7 @@ func (h *regionsHandler) GetExtraPeerRegions(w http.ResponseWriter, r *http.Requ
 	handler := h.svr.GetHandler()
 	regions, err := handler.GetExtraPeerRegions()
 	if err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 		return
 	}
 	regionsInfo := convertToAPIRegions(regions)
@@ -214,7 +274,7 @@ func (h *regionsHandler) GetPendingPeerRegions(w http.ResponseWriter, r *http.Re
 	handler := h.svr.GetHandler()
 	regions, err := handler.GetPendingPeerRegions()
 	if err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 		return
 	}
 	regionsInfo := convertToAPIRegions(regions)
@@ -225,7 +285,7 @@ func (h *regionsHandler) GetDownPeerRegions(w http.ResponseWriter, r *http.Reque
 	handler := h.svr.GetHandler()
 	regions, err := handler.GetDownPeerRegions()
 	if err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 		return
 	}
 	regionsInfo := convertToAPIRegions(regions)
@@ -236,7 +296,7 @@ func (h *regionsHandler) GetIncorrectNamespaceRegions(w http.ResponseWriter, r *
 	handler := h.svr.GetHandler()
 	regions, err := handler.GetIncorrectNamespaceRegions()
 	if err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 		return
 	}
 	regionsInfo := convertToAPIRegions(regions)
@@ -246,7 +306,7 @@ func (h *regionsHandler) GetIncorrectNamespaceRegions(w http.ResponseWriter, r *
 func (h *regionsHandler) GetRegionSiblings(w http.ResponseWriter, r *http.Request) {
 	cluster := h.svr.GetRaftCluster()
 	if cluster == nil {
-		h.rd.JSON(w, http.StatusInternalServerError, server.ErrNotBootstrapped.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
 		return
 	}
 
@@ -270,6 +330,9 @@ func (h *regionsHandler) GetRegionSiblings(w http.ResponseWriter, r *http.Reques
 const (
 	defaultRegionLimit = 16
 	maxRegionLimit     = 10240
+	// defaultRangeSampleLimit is the max number of regions to estimate the
+	// size and keys of a range.
+	defaultRangeSampleLimit = 10000
 )
 
 func (h *regionsHandler) GetTopWriteFlow(w http.ResponseWriter, r *http.Request) {
@@ -301,7 +364,7 @@ func (h *regionsHandler) GetTopSize(w http.ResponseWriter, r *http.Request) {
 func (h *regionsHandler) GetTopNRegions(w http.ResponseWriter, r *http.Request, less func(a, b *core.RegionInfo) bool) {
 	cluster := h.svr.GetRaftCluster()
 	if cluster == nil {
-		h.rd.JSON(w, http.StatusInternalServerError, server.ErrNotBootstrapped.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
 		return
 	}
 	limit := defaultRegionLimit
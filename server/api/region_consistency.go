@@ -0,0 +1,72 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/pingcap/pd/server"
+	"github.com/unrolled/render"
+)
+
+type regionConsistencyHandler struct {
+	*server.Handler
+	rd *render.Render
+}
+
+func newRegionConsistencyHandler(handler *server.Handler, rd *render.Render) *regionConsistencyHandler {
+	return &regionConsistencyHandler{
+		Handler: handler,
+		rd:      rd,
+	}
+}
+
+type regionConsistencyInput struct {
+	Repair           bool `json:"repair"`
+	RegionsPerSecond int  `json:"regions_per_second"`
+}
+
+func (h *regionConsistencyHandler) Post(w http.ResponseWriter, r *http.Request) {
+	var input regionConsistencyInput
+	if err := readJSONRespondError(h.rd, w, r.Body, &input); err != nil {
+		return
+	}
+	job, err := h.CheckRegionConsistency(input.Repair, input.RegionsPerSecond)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, job)
+}
+
+func (h *regionConsistencyHandler) List(w http.ResponseWriter, r *http.Request) {
+	h.rd.JSON(w, http.StatusOK, h.GetRegionConsistencyJobs())
+}
+
+func (h *regionConsistencyHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	job := h.GetRegionConsistencyJob(id)
+	if job == nil {
+		h.rd.JSON(w, http.StatusNotFound, fmt.Sprintf("region consistency check job %d not found", id))
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, job)
+}
@@ -0,0 +1,69 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/pd/pkg/testutil"
+	"github.com/pingcap/pd/server"
+)
+
+var _ = Suite(&testRegionConsistencySuite{})
+
+type testRegionConsistencySuite struct {
+	svr       *server.Server
+	cleanup   cleanUpFunc
+	urlPrefix string
+}
+
+func (s *testRegionConsistencySuite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c)
+	mustWaitLeader(c, []*server.Server{s.svr})
+
+	addr := s.svr.GetAddr()
+	s.urlPrefix = fmt.Sprintf("%s%s/api/v1", addr, apiPrefix)
+
+	mustBootstrapCluster(c, s.svr)
+}
+
+func (s *testRegionConsistencySuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+func (s *testRegionConsistencySuite) TestRegionConsistency(c *C) {
+	checkURL := s.urlPrefix + "/admin/consistency-checks"
+	c.Assert(postJSON(checkURL, []byte(`{"regions_per_second":-1}`)), NotNil)
+	c.Assert(postJSON(checkURL, []byte(`{"repair":true}`)), IsNil)
+
+	var jobs []*server.RegionConsistencyJob
+	c.Assert(readJSONWithURL(checkURL, &jobs), IsNil)
+	c.Assert(jobs, HasLen, 1)
+	job := jobs[0]
+	c.Assert(job.Repair, IsTrue)
+	jobURL := fmt.Sprintf("%s/%d", checkURL, job.ID)
+	testutil.WaitUntil(c, func(c *C) bool {
+		c.Assert(readJSONWithURL(jobURL, job), IsNil)
+		return job.Status == server.JobFinished
+	})
+	c.Assert(job.Checked, Equals, 1)
+	c.Assert(job.Repaired, Equals, 0)
+
+	code, _ := requestStatusBody(c, server.DialClient, http.MethodGet, checkURL+"/100000")
+	c.Assert(code, Equals, http.StatusNotFound)
+	code, _ = requestStatusBody(c, server.DialClient, http.MethodGet, checkURL+"/abc")
+	c.Assert(code, Equals, http.StatusBadRequest)
+}
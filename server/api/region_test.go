@@ -135,6 +135,30 @@ func (s *testRegionSuite) TestRegions(c *C) {
 	}
 }
 
+func (s *testRegionSuite) TestRegionsInRange(c *C) {
+	rs := []*core.RegionInfo{
+		newTestRegionInfo(2, 1, []byte("a"), []byte("b")),
+		newTestRegionInfo(3, 1, []byte("b"), []byte("c")),
+		newTestRegionInfo(4, 2, []byte("c"), []byte("d")),
+	}
+	for _, r := range rs {
+		mustRegionHeartbeat(c, s.svr, r)
+	}
+	url := fmt.Sprintf("%s/regions/range?start_key=%s&end_key=%s", s.urlPrefix, "b1", "d")
+	regionsInfo := &regionsInfo{}
+	err := readJSONWithURL(url, regionsInfo)
+	c.Assert(err, IsNil)
+	c.Assert(regionsInfo.Count, Equals, 2)
+	c.Assert(regionsInfo.Regions[0].ID, Equals, uint64(3))
+	c.Assert(regionsInfo.Regions[1].ID, Equals, uint64(4))
+
+	url = fmt.Sprintf("%s/regions/range/summary?start_key=%s&end_key=%s", s.urlPrefix, "a", "c")
+	summary := &core.RangeSummary{}
+	err = readJSONWithURL(url, summary)
+	c.Assert(err, IsNil)
+	c.Assert(summary, DeepEquals, &core.RangeSummary{Count: 2, ApproximateSize: 20, ApproximateKeys: 20})
+}
+
 func (s *testRegionSuite) TestStoreRegions(c *C) {
 	r1 := newTestRegionInfo(2, 1, []byte("a"), []byte("b"))
 	r2 := newTestRegionInfo(3, 1, []byte("b"), []byte("c"))
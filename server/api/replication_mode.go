@@ -0,0 +1,43 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/pingcap/pd/pkg/apiutil"
+	"github.com/pingcap/pd/server"
+	"github.com/unrolled/render"
+)
+
+type replicationModeHandler struct {
+	svr *server.Server
+	rd  *render.Render
+}
+
+func newReplicationModeHandler(svr *server.Server, rd *render.Render) *replicationModeHandler {
+	return &replicationModeHandler{
+		svr: svr,
+		rd:  rd,
+	}
+}
+
+func (h *replicationModeHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	cluster := h.svr.GetRaftCluster()
+	if cluster == nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, cluster.GetReplicationModeStatus())
+}
@@ -35,16 +35,31 @@ func createRouter(prefix string, svr *server.Server) *mux.Router {
 	operatorHandler := newOperatorHandler(handler, rd)
 	router.HandleFunc("/api/v1/operators", operatorHandler.List).Methods("GET")
 	router.HandleFunc("/api/v1/operators", operatorHandler.Post).Methods("POST")
+	router.HandleFunc("/api/v1/operators/batch", operatorHandler.PostBatch).Methods("POST")
 	router.HandleFunc("/api/v1/operators/{region_id}", operatorHandler.Get).Methods("GET")
 	router.HandleFunc("/api/v1/operators/{region_id}", operatorHandler.Delete).Methods("DELETE")
 
+	diagnosisHandler := newDiagnosisHandler(handler, rd)
+	router.HandleFunc("/api/v1/diagnosis/region/{id}", diagnosisHandler.DiagnoseRegion).Methods("GET")
+	router.HandleFunc("/api/v1/diagnosis/store/{id}", diagnosisHandler.DiagnoseStore).Methods("GET")
+
 	schedulerHandler := newSchedulerHandler(handler, rd)
 	router.HandleFunc("/api/v1/schedulers", schedulerHandler.List).Methods("GET")
 	router.HandleFunc("/api/v1/schedulers", schedulerHandler.Post).Methods("POST")
 	router.HandleFunc("/api/v1/schedulers/{name}", schedulerHandler.Delete).Methods("DELETE")
+	router.HandleFunc("/api/v1/schedulers/{name}/config", schedulerHandler.GetConfig).Methods("GET")
+	router.HandleFunc("/api/v1/schedulers/{name}/config", schedulerHandler.SetConfig).Methods("POST")
+	router.HandleFunc("/api/v1/schedulers/types", schedulerHandler.ListTypes).Methods("GET")
+	router.HandleFunc("/api/v1/schedulers/halt", schedulerHandler.GetHaltStatus).Methods("GET")
+	router.HandleFunc("/api/v1/schedulers/prepare", schedulerHandler.GetPrepareStatus).Methods("GET")
+	router.HandleFunc("/api/v1/schedulers/prepare/force", schedulerHandler.ForcePrepare).Methods("POST")
+	router.HandleFunc("/api/v1/plugins/schedulers", schedulerHandler.ListPlugins).Methods("GET")
+	router.HandleFunc("/api/v1/plugins/schedulers", schedulerHandler.LoadPlugin).Methods("POST")
+	router.HandleFunc("/api/v1/plugins/schedulers", schedulerHandler.UnloadPlugin).Methods("DELETE")
 
 	router.Handle("/api/v1/cluster", newClusterHandler(svr, rd)).Methods("GET")
 	router.HandleFunc("/api/v1/cluster/status", newClusterHandler(svr, rd).GetClusterStatus).Methods("GET")
+	router.HandleFunc("/api/v1/cluster/bootstrap/prepare", newClusterHandler(svr, rd).PrepareBootstrap).Methods("POST")
 
 	confHandler := newConfHandler(svr, rd)
 	router.HandleFunc("/api/v1/config", confHandler.Get).Methods("GET")
@@ -58,16 +73,31 @@ func createRouter(prefix string, svr *server.Server) *mux.Router {
 	router.HandleFunc("/api/v1/config/namespace/{name}", confHandler.DeleteNamespace).Methods("DELETE")
 	router.HandleFunc("/api/v1/config/label-property", confHandler.GetLabelProperty).Methods("GET")
 	router.HandleFunc("/api/v1/config/label-property", confHandler.SetLabelProperty).Methods("POST")
+	router.HandleFunc("/api/v1/config/merge-protection", confHandler.GetMergeProtection).Methods("GET")
+	router.HandleFunc("/api/v1/config/merge-protection/{name}", confHandler.SetMergeProtectionRule).Methods("POST")
+	router.HandleFunc("/api/v1/config/merge-protection/{name}", confHandler.DeleteMergeProtectionRule).Methods("DELETE")
 	router.HandleFunc("/api/v1/config/cluster-version", confHandler.GetClusterVersion).Methods("GET")
 	router.HandleFunc("/api/v1/config/cluster-version", confHandler.SetClusterVersion).Methods("POST")
+	router.HandleFunc("/api/v1/config/cluster-version/features", confHandler.GetFeatures).Methods("GET")
+
+	quotaHandler := newQuotaHandler(handler, rd)
+	router.HandleFunc("/api/v1/quotas", quotaHandler.List).Methods("GET")
+	router.HandleFunc("/api/v1/quotas/{name}", quotaHandler.Get).Methods("GET")
 
 	storeHandler := newStoreHandler(svr, rd)
 	router.HandleFunc("/api/v1/store/{id}", storeHandler.Get).Methods("GET")
 	router.HandleFunc("/api/v1/store/{id}", storeHandler.Delete).Methods("DELETE")
 	router.HandleFunc("/api/v1/store/{id}/state", storeHandler.SetState).Methods("POST")
 	router.HandleFunc("/api/v1/store/{id}/label", storeHandler.SetLabels).Methods("POST")
+	router.HandleFunc("/api/v1/store/{id}/label/{key}", storeHandler.DeleteLabel).Methods("DELETE")
 	router.HandleFunc("/api/v1/store/{id}/weight", storeHandler.SetWeight).Methods("POST")
+	router.HandleFunc("/api/v1/store/{id}/cordon", storeHandler.Cordon).Methods("POST")
+	router.HandleFunc("/api/v1/store/{id}/cordon", storeHandler.Uncordon).Methods("DELETE")
+	router.HandleFunc("/api/v1/store/{id}/replacement", storeHandler.GetReplacement).Methods("GET")
+	router.HandleFunc("/api/v1/store/{id}/replacement", storeHandler.SetReplacement).Methods("POST")
+	router.HandleFunc("/api/v1/store/{id}/replacement", storeHandler.DeleteReplacement).Methods("DELETE")
 	router.Handle("/api/v1/stores", newStoresHandler(svr, rd)).Methods("GET")
+	router.HandleFunc("/api/v1/stores/simulation", newSimulationHandler(handler, rd).SimulateCapacity).Methods("POST")
 
 	labelsHandler := newLabelsHandler(svr, rd)
 	router.HandleFunc("/api/v1/labels", labelsHandler.Get).Methods("GET")
@@ -85,6 +115,8 @@ func createRouter(prefix string, svr *server.Server) *mux.Router {
 	regionsHandler := newRegionsHandler(svr, rd)
 	router.HandleFunc("/api/v1/regions", regionsHandler.GetAll).Methods("GET")
 	router.HandleFunc("/api/v1/regions/key", regionsHandler.ScanRegionsByKey).Methods("GET")
+	router.HandleFunc("/api/v1/regions/range", regionsHandler.GetRegionsInRange).Methods("GET")
+	router.HandleFunc("/api/v1/regions/range/summary", regionsHandler.GetRangeSummary).Methods("GET")
 	router.HandleFunc("/api/v1/regions/store/{id}", regionsHandler.GetStoreRegions).Methods("GET")
 	router.HandleFunc("/api/v1/regions/writeflow", regionsHandler.GetTopWriteFlow).Methods("GET")
 	router.HandleFunc("/api/v1/regions/readflow", regionsHandler.GetTopReadFlow).Methods("GET")
@@ -98,6 +130,31 @@ func createRouter(prefix string, svr *server.Server) *mux.Router {
 	router.HandleFunc("/api/v1/regions/sibling/{id}", regionsHandler.GetRegionSiblings).Methods("GET")
 	router.HandleFunc("/api/v1/regions/check/incorrect-ns", regionsHandler.GetIncorrectNamespaceRegions).Methods("GET")
 
+	preSplitHandler := newPreSplitHandler(handler, rd)
+	router.HandleFunc("/api/v1/regions/presplit", preSplitHandler.Post).Methods("POST")
+	router.HandleFunc("/api/v1/regions/presplit", preSplitHandler.List).Methods("GET")
+	router.HandleFunc("/api/v1/regions/presplit/{id}", preSplitHandler.Get).Methods("GET")
+
+	namespaceMigrationHandler := newNamespaceMigrationHandler(handler, rd)
+	router.HandleFunc("/api/v1/namespace-migrations", namespaceMigrationHandler.Post).Methods("POST")
+	router.HandleFunc("/api/v1/namespace-migrations", namespaceMigrationHandler.List).Methods("GET")
+	router.HandleFunc("/api/v1/namespace-migrations/{id}", namespaceMigrationHandler.Get).Methods("GET")
+
+	jobHandler := newJobHandler(svr, rd)
+	router.HandleFunc("/api/v1/jobs", jobHandler.List).Methods("GET")
+	router.HandleFunc("/api/v1/jobs/{id}", jobHandler.Get).Methods("GET")
+	router.HandleFunc("/api/v1/jobs/{id}/cancel", jobHandler.Cancel).Methods("POST")
+	router.HandleFunc("/api/v1/jobs/{id}/pause", jobHandler.Pause).Methods("POST")
+	router.HandleFunc("/api/v1/jobs/{id}/resume", jobHandler.Resume).Methods("POST")
+
+	eventHandler := newEventHandler(svr, rd)
+	router.HandleFunc("/api/v1/events", eventHandler.List).Methods("GET")
+
+	dashboardHandler := newDashboardHandler(svr, rd)
+	router.HandleFunc("/api/v1/dashboard/topology", dashboardHandler.GetTopology).Methods("GET")
+	router.HandleFunc("/api/v1/dashboard/stores/flow", dashboardHandler.GetStoreFlowTrends).Methods("GET")
+	router.HandleFunc("/api/v1/dashboard/hot-regions", dashboardHandler.GetTopHotRegions).Methods("GET")
+
 	router.Handle("/api/v1/version", newVersionHandler(rd)).Methods("GET")
 	router.Handle("/api/v1/status", newStatusHandler(rd)).Methods("GET")
 
@@ -109,6 +166,8 @@ func createRouter(prefix string, svr *server.Server) *mux.Router {
 
 	leaderHandler := newLeaderHandler(svr, rd)
 	router.HandleFunc("/api/v1/leader", leaderHandler.Get).Methods("GET")
+	router.HandleFunc("/api/v1/leader/lease", leaderHandler.GetLease).Methods("GET")
+	router.HandleFunc("/api/v1/leader/history", leaderHandler.GetHistory).Methods("GET")
 	router.HandleFunc("/api/v1/leader/resign", leaderHandler.Resign).Methods("POST")
 	router.HandleFunc("/api/v1/leader/transfer/{next_leader}", leaderHandler.Transfer).Methods("POST")
 
@@ -119,11 +178,46 @@ func createRouter(prefix string, svr *server.Server) *mux.Router {
 	statsHandler := newStatsHandler(svr, rd)
 	router.HandleFunc("/api/v1/stats/region", statsHandler.Region).Methods("GET")
 
+	keyVisualHandler := newKeyVisualHandler(svr, rd)
+	router.HandleFunc("/api/v1/keyvisual/heatmap", keyVisualHandler.Heatmap).Methods("GET")
+
 	trendHandler := newTrendHandler(svr, rd)
 	router.HandleFunc("/api/v1/trend", trendHandler.Handle).Methods("GET")
 
 	adminHandler := newAdminHandler(svr, rd)
 	router.HandleFunc("/api/v1/admin/cache/region/{id}", adminHandler.HandleDropCacheRegion).Methods("DELETE")
+	router.HandleFunc("/api/v1/admin/janitor", adminHandler.GetJanitorReports).Methods("GET")
+	router.HandleFunc("/api/v1/admin/snapshots", adminHandler.CreateMetaSnapshot).Methods("POST")
+	router.HandleFunc("/api/v1/admin/snapshots", adminHandler.ListMetaSnapshots).Methods("GET")
+	router.HandleFunc("/api/v1/admin/snapshots/{name}/restore", adminHandler.RestoreMetaSnapshot).Methods("POST")
+	router.HandleFunc("/api/v1/admin/etcd", adminHandler.GetEtcdStatus).Methods("GET")
+	router.HandleFunc("/api/v1/admin/etcd/compact", adminHandler.CompactEtcd).Methods("POST")
+	router.HandleFunc("/api/v1/admin/etcd/defrag/{name}", adminHandler.DefragEtcdMember).Methods("POST")
+
+	regionConsistencyHandler := newRegionConsistencyHandler(handler, rd)
+	router.HandleFunc("/api/v1/admin/consistency-checks", regionConsistencyHandler.Post).Methods("POST")
+	router.HandleFunc("/api/v1/admin/consistency-checks", regionConsistencyHandler.List).Methods("GET")
+	router.HandleFunc("/api/v1/admin/consistency-checks/{id}", regionConsistencyHandler.Get).Methods("GET")
+
+	maintenanceHandler := newMaintenanceHandler(svr, rd)
+	router.HandleFunc(maintenanceAPI, maintenanceHandler.Get).Methods("GET")
+	router.HandleFunc(maintenanceAPI, maintenanceHandler.Enter).Methods("POST")
+	router.HandleFunc(maintenanceAPI, maintenanceHandler.Exit).Methods("DELETE")
+
+	replicationModeHandler := newReplicationModeHandler(svr, rd)
+	router.HandleFunc("/api/v1/replication_mode/status", replicationModeHandler.GetStatus).Methods("GET")
+
+	keyspaceHandler := newKeyspaceHandler(svr, rd)
+	router.HandleFunc("/api/v1/keyspaces", keyspaceHandler.List).Methods("GET")
+	router.HandleFunc("/api/v1/keyspaces", keyspaceHandler.Create).Methods("POST")
+	router.HandleFunc("/api/v1/keyspaces/{name}", keyspaceHandler.Get).Methods("GET")
+	router.HandleFunc("/api/v1/keyspaces/{name}/state", keyspaceHandler.UpdateState).Methods("POST")
+	router.HandleFunc("/api/v1/keyspaces/{name}/config", keyspaceHandler.UpdateConfig).Methods("POST")
+
+	gcHandler := newGCHandler(svr, rd)
+	router.HandleFunc("/api/v1/gc/safepoint", gcHandler.GetSafePoints).Methods("GET")
+	router.HandleFunc("/api/v1/gc/safepoint/service/{service_id}", gcHandler.UpdateServiceSafePoint).Methods("POST")
+	router.HandleFunc("/api/v1/gc/safepoint/service/{service_id}", gcHandler.DeleteServiceSafePoint).Methods("DELETE")
 
 	logHanler := newlogHandler(svr, rd)
 	router.HandleFunc("/api/v1/admin/log", logHanler.Handle).Methods("POST")
@@ -14,6 +14,8 @@
 package api
 
 import (
+	"fmt"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/gorilla/mux"
@@ -77,23 +79,18 @@ func (h *schedulerHandler) Post(w http.ResponseWriter, r *http.Request) {
 		}
 	case "scatter-range":
 		var args []string
-		startKey, ok := input["start_key"].(string)
-		if ok {
-			args = append(args, startKey)
-		}
-		endKey, ok := input["end_key"].(string)
-		if ok {
-			args = append(args, endKey)
-		}
-		name, ok := input["range_name"].(string)
-		if ok {
-			args = append(args, name)
+		for _, key := range []string{"start_key", "end_key", "range_name"} {
+			arg, ok := input[key].(string)
+			if !ok {
+				h.r.JSON(w, http.StatusBadRequest, fmt.Sprintf("missing %s", key))
+				return
+			}
+			args = append(args, arg)
 		}
 		if err := h.AddScatterRangeScheduler(args...); err != nil {
 			h.r.JSON(w, http.StatusInternalServerError, err.Error())
 			return
 		}
-
 	case "balance-adjacent-region-scheduler":
 		var args []string
 		leaderLimit, ok := input["leader_limit"].(string)
@@ -130,25 +127,124 @@ func (h *schedulerHandler) Post(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	case "shuffle-leader-scheduler":
-		if err := h.AddShuffleLeaderScheduler(); err != nil {
+		var args []string
+		if interval, ok := input["interval"].(string); ok {
+			args = append(args, interval)
+		}
+		if err := h.AddShuffleLeaderScheduler(args...); err != nil {
 			h.r.JSON(w, http.StatusInternalServerError, err.Error())
 			return
 		}
 	case "shuffle-region-scheduler":
-		if err := h.AddShuffleRegionScheduler(); err != nil {
+		var args []string
+		if interval, ok := input["interval"].(string); ok {
+			args = append(args, interval)
+		}
+		if err := h.AddShuffleRegionScheduler(args...); err != nil {
 			h.r.JSON(w, http.StatusInternalServerError, err.Error())
 			return
 		}
 	case "random-merge-scheduler":
-		if err := h.AddRandomMergeScheduler(); err != nil {
+		var args []string
+		if interval, ok := input["interval"].(string); ok {
+			args = append(args, interval)
+		}
+		if err := h.AddRandomMergeScheduler(args...); err != nil {
 			h.r.JSON(w, http.StatusInternalServerError, err.Error())
 			return
 		}
 	default:
-		h.r.JSON(w, http.StatusBadRequest, "unknown scheduler")
+		// Other registered schedulers such as the plugin schedulers, whose
+		// arguments are passed by `args` in order.
+		var args []string
+		if rawArgs, ok := input["args"].([]interface{}); ok {
+			for _, arg := range rawArgs {
+				s, ok := arg.(string)
+				if !ok {
+					h.r.JSON(w, http.StatusBadRequest, "args should be strings")
+					return
+				}
+				args = append(args, s)
+			}
+		}
+		if !h.isSchedulerTypeRegistered(name) {
+			h.r.JSON(w, http.StatusBadRequest, "unknown scheduler")
+			return
+		}
+		if err := h.AddScheduler(name, args...); err != nil {
+			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	h.r.JSON(w, http.StatusOK, nil)
+}
+
+func (h *schedulerHandler) isSchedulerTypeRegistered(name string) bool {
+	for _, t := range h.GetSchedulerTypes() {
+		if t.Type == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *schedulerHandler) ListTypes(w http.ResponseWriter, r *http.Request) {
+	h.r.JSON(w, http.StatusOK, h.GetSchedulerTypes())
+}
+
+func (h *schedulerHandler) GetHaltStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := h.GetScheduleHaltStatus()
+	if err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+	h.r.JSON(w, http.StatusOK, status)
+}
 
+func (h *schedulerHandler) GetPrepareStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := h.Handler.GetPrepareStatus()
+	if err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.r.JSON(w, http.StatusOK, status)
+}
+
+func (h *schedulerHandler) ForcePrepare(w http.ResponseWriter, r *http.Request) {
+	if err := h.Handler.ForcePrepare(); err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.r.JSON(w, http.StatusOK, nil)
+}
+
+type schedulerPluginInput struct {
+	Name string `json:"name"`
+}
+
+func (h *schedulerHandler) ListPlugins(w http.ResponseWriter, r *http.Request) {
+	h.r.JSON(w, http.StatusOK, h.GetSchedulerPlugins())
+}
+
+func (h *schedulerHandler) LoadPlugin(w http.ResponseWriter, r *http.Request) {
+	var input schedulerPluginInput
+	if err := readJSONRespondError(h.r, w, r.Body, &input); err != nil {
+		return
+	}
+	types, err := h.LoadSchedulerPlugin(input.Name)
+	if err != nil {
+		errorResp(h.r, w, err)
+		return
+	}
+	h.r.JSON(w, http.StatusOK, types)
+}
+
+func (h *schedulerHandler) UnloadPlugin(w http.ResponseWriter, r *http.Request) {
+	if err := h.UnloadSchedulerPlugin(r.URL.Query().Get("name")); err != nil {
+		errorResp(h.r, w, err)
+		return
+	}
 	h.r.JSON(w, http.StatusOK, nil)
 }
 
@@ -162,3 +258,27 @@ func (h *schedulerHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	h.r.JSON(w, http.StatusOK, nil)
 }
+
+func (h *schedulerHandler) GetConfig(w http.ResponseWriter, r *http.Request) {
+	cfg, err := h.GetSchedulerConfig(mux.Vars(r)["name"])
+	if err != nil {
+		errorResp(h.r, w, err)
+		return
+	}
+	h.r.JSON(w, http.StatusOK, cfg)
+}
+
+func (h *schedulerHandler) SetConfig(w http.ResponseWriter, r *http.Request) {
+	data, err := ioutil.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	cfg, err := h.SetSchedulerConfig(mux.Vars(r)["name"], data)
+	if err != nil {
+		errorResp(h.r, w, err)
+		return
+	}
+	h.r.JSON(w, http.StatusOK, cfg)
+}
@@ -16,10 +16,15 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/pd/server"
+	"github.com/pingcap/pd/server/schedule"
 	_ "github.com/pingcap/pd/server/schedulers"
 )
 
@@ -47,6 +52,14 @@ func (s *testScheduleSuite) TearDownSuite(c *C) {
 }
 
 func (s *testScheduleSuite) TestAPI(c *C) {
+	cfg := s.svr.GetScheduleConfig()
+	cfg.EnableDebugSchedulers = true
+	c.Assert(s.svr.SetScheduleConfig(*cfg), IsNil)
+	defer func() {
+		cfg.EnableDebugSchedulers = false
+		c.Assert(s.svr.SetScheduleConfig(*cfg), IsNil)
+	}()
+
 	type arg struct {
 		opt   string
 		value interface{}
@@ -61,6 +74,12 @@ func (s *testScheduleSuite) TestAPI(c *C) {
 		{name: "balance-region-scheduler"},
 		{name: "shuffle-leader-scheduler"},
 		{name: "shuffle-region-scheduler"},
+		{name: "random-merge-scheduler", args: []arg{{"interval", "10s"}}},
+		{
+			name:        "scatter-range",
+			createdName: "scatter-range-t1",
+			args:        []arg{{"start_key", url.QueryEscape("t1_\x00")}, {"end_key", url.QueryEscape("t1_\xff")}, {"range_name", "t1"}},
+		},
 		{
 			name:        "grant-leader-scheduler",
 			createdName: "grant-leader-scheduler-1",
@@ -85,6 +104,105 @@ func (s *testScheduleSuite) TestAPI(c *C) {
 
 }
 
+func (s *testScheduleSuite) TestPrepare(c *C) {
+	var status server.PrepareStatus
+	c.Assert(readJSONWithURL(s.urlPrefix+"/prepare", &status), IsNil)
+	c.Assert(status.CollectFactor, Equals, 0.8)
+	c.Assert(status.Timeout.Duration, Greater, time.Duration(0))
+
+	c.Assert(postJSON(s.urlPrefix+"/prepare/force", nil), IsNil)
+	c.Assert(readJSONWithURL(s.urlPrefix+"/prepare", &status), IsNil)
+	c.Assert(status.IsPrepared, IsTrue)
+	c.Assert(status.Progress, Equals, float64(100))
+}
+
+func (s *testScheduleSuite) TestScatterRangeInvalid(c *C) {
+	inputs := []map[string]interface{}{
+		{"name": "scatter-range", "start_key": "a", "end_key": "b"},
+		{"name": "scatter-range", "start_key": "a", "end_key": "b", "range_name": ""},
+		{"name": "scatter-range", "start_key": "b", "end_key": "a", "range_name": "r"},
+	}
+	for _, input := range inputs {
+		body, err := json.Marshal(input)
+		c.Assert(err, IsNil)
+		c.Assert(postJSON(s.urlPrefix, body), NotNil)
+	}
+	sches, err := s.svr.GetHandler().GetSchedulers()
+	c.Assert(err, IsNil)
+	for _, name := range sches {
+		c.Assert(strings.HasPrefix(name, "scatter-range"), IsFalse)
+	}
+}
+
+func (s *testScheduleSuite) TestDebugSchedulersDisabled(c *C) {
+	body, err := json.Marshal(map[string]interface{}{"name": "shuffle-leader-scheduler"})
+	c.Assert(err, IsNil)
+	err = postJSON(s.urlPrefix, body)
+	c.Assert(err, ErrorMatches, "(?s).*debug schedulers are disabled.*")
+}
+
+func (s *testScheduleSuite) TestListTypes(c *C) {
+	var types []schedule.SchedulerType
+	err := readJSONWithURL(s.urlPrefix+"/types", &types)
+	c.Assert(err, IsNil)
+	found := false
+	for _, t := range types {
+		if t.Type == "grant-leader" {
+			found = true
+			c.Assert(t.Args, HasLen, 1)
+			c.Assert(t.Args[0].Name, Equals, "store-id")
+		}
+	}
+	c.Assert(found, IsTrue)
+
+	// No plugin is configured.
+	pluginURL := fmt.Sprintf("%s%s/api/v1/plugins/schedulers", s.svr.GetAddr(), apiPrefix)
+	var plugins []string
+	err = readJSONWithURL(pluginURL, &plugins)
+	c.Assert(err, IsNil)
+	c.Assert(plugins, HasLen, 0)
+
+	// Only the plugins in the allow list can be loaded.
+	err = postJSON(pluginURL, []byte(`{"name": "../exist.so"}`))
+	c.Assert(err, ErrorMatches, "(?s).*not in the allow list.*")
+	req, err := http.NewRequest("DELETE", pluginURL+"?name=exist.so", nil)
+	c.Assert(err, IsNil)
+	resp, err := server.DialClient.Do(req)
+	c.Assert(err, IsNil)
+	resp.Body.Close()
+	c.Assert(resp.StatusCode, Equals, http.StatusBadRequest)
+}
+
+func (s *testScheduleSuite) TestHaltStatus(c *C) {
+	status := &server.ScheduleHaltStatus{}
+	err := readJSONWithURL(s.urlPrefix+"/halt", status)
+	c.Assert(err, IsNil)
+	c.Assert(status.Halted, IsFalse)
+	c.Assert(status.Reasons, HasLen, 0)
+}
+
+func (s *testScheduleSuite) TestConfig(c *C) {
+	handler := s.svr.GetHandler()
+	if err := handler.AddBalanceHotRegionScheduler(); err != nil {
+		c.Assert(err, ErrorMatches, ".*scheduler existed.*")
+	}
+	configURL := s.urlPrefix + "/balance-hot-region-scheduler/config"
+	cfg := make(map[string]interface{})
+	c.Assert(readJSONWithURL(configURL, &cfg), IsNil)
+	c.Assert(cfg["limit-factor"], Equals, 0.75)
+
+	c.Assert(postJSON(configURL, []byte(`{"limit-factor": 0.5}`)), IsNil)
+	c.Assert(readJSONWithURL(configURL, &cfg), IsNil)
+	c.Assert(cfg["limit-factor"], Equals, 0.5)
+	c.Assert(cfg["schedule-factor"], Equals, 0.9)
+	err := postJSON(configURL, []byte(`{"limit-factor": 2}`))
+	c.Assert(err, ErrorMatches, "(?s).*limit-factor should be in.*")
+	c.Assert(postJSON(configURL, []byte(`{"limit-factor": 0.75}`)), IsNil)
+
+	code, _ := requestStatusBody(c, server.DialClient, "GET", s.urlPrefix+"/unknown-scheduler/config")
+	c.Assert(code, Equals, http.StatusNotFound)
+}
+
 func (s *testScheduleSuite) testAddAndRemoveScheduler(name, createdName string, body []byte, c *C) {
 	if createdName == "" {
 		createdName = name
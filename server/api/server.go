@@ -29,10 +29,24 @@ func NewHandler(svr *server.Server) http.Handler {
 
 	recovery := negroni.NewRecovery()
 	engine.Use(recovery)
+	engine.Use(newSlowLogger(svr))
 
 	router := mux.NewRouter()
+	router.PathPrefix(apiPrefix + apiV2Prefix).Handler(negroni.New(
+		newRedirector(svr),
+		newMaintenanceChecker(svr),
+		newGzipCompressor(),
+		negroni.Wrap(createRouterV2(apiPrefix, svr)),
+	))
+	router.PathPrefix(apiPrefix + debugPrefix).Handler(negroni.New(
+		newAdminAuth(svr),
+		negroni.Wrap(createDebugRouter(apiPrefix, svr)),
+	))
 	router.PathPrefix(apiPrefix).Handler(negroni.New(
 		newRedirector(svr),
+		newMaintenanceChecker(svr),
+		newDeprecationHeader(),
+		newGzipCompressor(),
 		negroni.Wrap(createRouter(apiPrefix, svr)),
 	))
 
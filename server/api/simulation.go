@@ -0,0 +1,46 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/pingcap/pd/server"
+	"github.com/unrolled/render"
+)
+
+type simulationHandler struct {
+	*server.Handler
+	rd *render.Render
+}
+
+func newSimulationHandler(handler *server.Handler, rd *render.Render) *simulationHandler {
+	return &simulationHandler{
+		Handler: handler,
+		rd:      rd,
+	}
+}
+
+func (h *simulationHandler) SimulateCapacity(w http.ResponseWriter, r *http.Request) {
+	var input server.CapacitySimulationInput
+	if err := readJSONRespondError(h.rd, w, r.Body, &input); err != nil {
+		return
+	}
+	result, err := h.Handler.SimulateCapacity(&input)
+	if err != nil {
+		errorResp(h.rd, w, err)
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, result)
+}
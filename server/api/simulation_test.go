@@ -0,0 +1,67 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/server"
+)
+
+var _ = Suite(&testSimulationSuite{})
+
+type testSimulationSuite struct{}
+
+func (s *testSimulationSuite) TestSimulateCapacity(c *C) {
+	svr, cleanup := mustNewServer(c)
+	defer cleanup()
+	mustWaitLeader(c, []*server.Server{svr})
+
+	mustBootstrapCluster(c, svr)
+	mustPutStore(c, svr, 1, metapb.StoreState_Up, nil)
+	mustRegionHeartbeat(c, svr, newTestRegionInfo(10, 1, []byte("a"), []byte("b")))
+	url := fmt.Sprintf("%s%s/api/v1/stores/simulation", svr.GetAddr(), apiPrefix)
+
+	// The missing replicas of the region are added to the new stores.
+	res, err := server.DialClient.Post(url, "application/json", strings.NewReader(`{"add_stores": 2, "capacity": "1GiB"}`))
+	c.Assert(err, IsNil)
+	c.Assert(res.StatusCode, Equals, http.StatusOK)
+	var result server.CapacitySimulation
+	c.Assert(readJSON(res.Body, &result), IsNil)
+	c.Assert(result.Converged, IsTrue)
+	c.Assert(result.MovedPeers, Equals, 2)
+	c.Assert(result.MovedSize, Equals, int64(20))
+	c.Assert(result.Stores, HasLen, 3)
+	c.Assert(result.Stores[0].StoreID, Equals, uint64(1))
+	for _, store := range result.Stores[1:] {
+		c.Assert(store.Added, IsTrue)
+		c.Assert(store.After.RegionCount, Equals, 1)
+	}
+	c.Assert(svr.GetRaftCluster().GetStores(), HasLen, 1)
+
+	for input, status := range map[string]int{
+		`{"add_stores": -1}`:       http.StatusBadRequest,
+		`{"remove_stores": [100]}`: http.StatusNotFound,
+		`{"add_stores": "2"}`:      http.StatusBadRequest,
+	} {
+		res, err = server.DialClient.Post(url, "application/json", strings.NewReader(input))
+		c.Assert(err, IsNil)
+		res.Body.Close()
+		c.Assert(res.StatusCode, Equals, status, Commentf("input %s", input))
+	}
+}
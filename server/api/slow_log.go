@@ -0,0 +1,59 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/pingcap/pd/server"
+	"github.com/pkg/errors"
+	"github.com/urfave/negroni"
+)
+
+var (
+	regionPathRegexp = regexp.MustCompile(`/(?:region|operators)/(?:id/)?(\d+)`)
+	storePathRegexp  = regexp.MustCompile(`/stores?/(\d+)`)
+)
+
+// slowLogger logs the requests running longer than the slow log threshold.
+type slowLogger struct {
+	s *server.Server
+}
+
+func newSlowLogger(s *server.Server) *slowLogger {
+	return &slowLogger{s: s}
+}
+
+func (h *slowLogger) ServeHTTP(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	ctx, l := h.s.StartRequestSlowLog(r.Context(), "http", r.Method+" "+r.URL.Path, r.RemoteAddr)
+	if m := regionPathRegexp.FindStringSubmatch(r.URL.Path); m != nil {
+		if id, err := strconv.ParseUint(m[1], 10, 64); err == nil {
+			l.TouchRegion(id)
+		}
+	}
+	if m := storePathRegexp.FindStringSubmatch(r.URL.Path); m != nil {
+		if id, err := strconv.ParseUint(m[1], 10, 64); err == nil {
+			l.TouchStore(id)
+		}
+	}
+	next(w, r.WithContext(ctx))
+
+	var err error
+	if rw, ok := w.(negroni.ResponseWriter); ok && rw.Status() >= http.StatusInternalServerError {
+		err = errors.Errorf("status %d", rw.Status())
+	}
+	l.Finish(err)
+}
@@ -0,0 +1,57 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	. "github.com/pingcap/check"
+)
+
+var _ = Suite(&testSlowLogSuite{})
+
+type testSlowLogSuite struct{}
+
+func (s *testSlowLogSuite) TestPathRegexp(c *C) {
+	for path, id := range map[string]string{
+		"/pd/api/v1/region/id/10":            "10",
+		"/pd/api/v1/operators/11":            "11",
+		"/pd/api/v1/diagnosis/region/12":     "12",
+		"/pd/api/v1/region/key/a":            "",
+		"/pd/api/v1/regions/store/1":         "",
+		"/pd/api/v1/store/1/replacement":     "",
+		"/pd/api/v1/operators/batch":         "",
+		"/pd/api/v1/regions/check/miss-peer": "",
+	} {
+		m := regionPathRegexp.FindStringSubmatch(path)
+		if id == "" {
+			c.Assert(m, IsNil, Commentf("path %s", path))
+		} else {
+			c.Assert(m[1], Equals, id, Commentf("path %s", path))
+		}
+	}
+
+	for path, id := range map[string]string{
+		"/pd/api/v1/store/1":             "1",
+		"/pd/api/v1/store/2/replacement": "2",
+		"/pd/api/v1/regions/store/3":     "3",
+		"/pd/api/v1/stores":              "",
+		"/pd/api/v1/region/id/10":        "",
+	} {
+		m := storePathRegexp.FindStringSubmatch(path)
+		if id == "" {
+			c.Assert(m, IsNil, Commentf("path %s", path))
+		} else {
+			c.Assert(m[1], Equals, id, Commentf("path %s", path))
+		}
+	}
+}
@@ -16,6 +16,7 @@ package api
 import (
 	"net/http"
 
+	"github.com/pingcap/pd/pkg/apiutil"
 	"github.com/pingcap/pd/server"
 	"github.com/unrolled/render"
 )
@@ -35,7 +36,7 @@ func newStatsHandler(svr *server.Server, rd *render.Render) *statsHandler {
 func (h *statsHandler) Region(w http.ResponseWriter, r *http.Request) {
 	cluster := h.svr.GetRaftCluster()
 	if cluster == nil {
-		h.rd.JSON(w, http.StatusInternalServerError, server.ErrNotBootstrapped.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
 		return
 	}
 	startKey, endKey := r.URL.Query().Get("start_key"), r.URL.Query().Get("end_key")
@@ -14,8 +14,10 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"time"
 
@@ -55,12 +57,25 @@ type StoreStatus struct {
 	StartTS            *time.Time         `json:"start_ts,omitempty"`
 	LastHeartbeatTS    *time.Time         `json:"last_heartbeat_ts,omitempty"`
 	Uptime             *typeutil.Duration `json:"uptime,omitempty"`
+	UsedSizeGrowthRate float64            `json:"used_size_growth_rate,omitempty"`
+	TimeToFull         *typeutil.Duration `json:"time_to_full,omitempty"`
+	// DiskReadRate and DiskWriteRate are the disk bandwidth in bytes per second.
+	DiskReadRate           float64           `json:"disk_read_rate,omitempty"`
+	DiskWriteRate          float64           `json:"disk_write_rate,omitempty"`
+	PendingCompactionBytes typeutil.ByteSize `json:"pending_compaction_bytes,omitempty"`
+	// Cordoned means no new peers or leaders are scheduled to the store,
+	// until CordonDeadline if it is set.
+	Cordoned       bool       `json:"cordoned,omitempty"`
+	CordonDeadline *time.Time `json:"cordon_deadline,omitempty"`
+	// ReplacementStoreID is the store which the peers of the offline store
+	// are moved to first.
+	ReplacementStoreID uint64 `json:"replacement_store_id,omitempty"`
 }
 
 // StoreInfo contains information about a store.
 type StoreInfo struct {
 	Store  *MetaStore   `json:"store"`
-	Status *StoreStatus `json:"status"`
+	Status *StoreStatus `json:"status,omitempty"`
 }
 
 const (
@@ -75,20 +90,24 @@ func newStoreInfo(opt *server.ScheduleConfig, store *core.StoreInfo) *StoreInfo
 			StateName: store.State.String(),
 		},
 		Status: &StoreStatus{
-			Capacity:           typeutil.ByteSize(store.Stats.GetCapacity()),
-			Available:          typeutil.ByteSize(store.Stats.GetAvailable()),
-			LeaderCount:        store.LeaderCount,
-			LeaderWeight:       store.LeaderWeight,
-			LeaderScore:        store.LeaderScore(0),
-			LeaderSize:         store.LeaderSize,
-			RegionCount:        store.RegionCount,
-			RegionWeight:       store.RegionWeight,
-			RegionScore:        store.RegionScore(opt.HighSpaceRatio, opt.LowSpaceRatio, 0),
-			RegionSize:         store.RegionSize,
-			SendingSnapCount:   store.Stats.GetSendingSnapCount(),
-			ReceivingSnapCount: store.Stats.GetReceivingSnapCount(),
-			ApplyingSnapCount:  store.Stats.GetApplyingSnapCount(),
-			IsBusy:             store.Stats.GetIsBusy(),
+			Capacity:               typeutil.ByteSize(store.Stats.GetCapacity()),
+			Available:              typeutil.ByteSize(store.Stats.GetAvailable()),
+			LeaderCount:            store.LeaderCount,
+			LeaderWeight:           store.LeaderWeight,
+			LeaderScore:            store.LeaderScore(0),
+			LeaderSize:             store.LeaderSize,
+			RegionCount:            store.RegionCount,
+			RegionWeight:           store.RegionWeight,
+			RegionScore:            store.RegionScore(opt.HighSpaceRatio, opt.LowSpaceRatio, 0),
+			RegionSize:             store.RegionSize,
+			SendingSnapCount:       store.Stats.GetSendingSnapCount(),
+			ReceivingSnapCount:     store.Stats.GetReceivingSnapCount(),
+			ApplyingSnapCount:      store.Stats.GetApplyingSnapCount(),
+			IsBusy:                 store.Stats.GetIsBusy(),
+			UsedSizeGrowthRate:     store.UsedSizeGrowthRate(),
+			DiskReadRate:           store.DiskReadRate(),
+			DiskWriteRate:          store.DiskWriteRate(),
+			PendingCompactionBytes: typeutil.ByteSize(store.DiskStats.PendingCompactionBytes),
 		},
 	}
 
@@ -103,6 +122,21 @@ func newStoreInfo(opt *server.ScheduleConfig, store *core.StoreInfo) *StoreInfo
 		duration := typeutil.NewDuration(upTime)
 		s.Status.Uptime = &duration
 	}
+	if timeToFull, ok := store.TimeToFull(); ok {
+		duration := typeutil.NewDuration(timeToFull)
+		s.Status.TimeToFull = &duration
+	}
+
+	if store.IsCordoned() {
+		s.Status.Cordoned = true
+		if deadline := store.GetCordonDeadline(); !deadline.IsZero() {
+			s.Status.CordonDeadline = &deadline
+		}
+	}
+
+	if replacement := store.GetReplacement(); replacement != nil {
+		s.Status.ReplacementStoreID = replacement.StoreID
+	}
 
 	if store.State == metapb.StoreState_Up {
 		if store.DownTime() > opt.MaxStoreDownTime.Duration {
@@ -135,7 +169,7 @@ func newStoreHandler(svr *server.Server, rd *render.Render) *storeHandler {
 func (h *storeHandler) Get(w http.ResponseWriter, r *http.Request) {
 	cluster := h.svr.GetRaftCluster()
 	if cluster == nil {
-		h.rd.JSON(w, http.StatusInternalServerError, server.ErrNotBootstrapped.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
 		return
 	}
 
@@ -148,7 +182,7 @@ func (h *storeHandler) Get(w http.ResponseWriter, r *http.Request) {
 
 	store, err := cluster.GetStore(storeID)
 	if err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 		return
 	}
 
@@ -159,7 +193,7 @@ func (h *storeHandler) Get(w http.ResponseWriter, r *http.Request) {
 func (h *storeHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	cluster := h.svr.GetRaftCluster()
 	if cluster == nil {
-		errorResp(h.rd, w, errcode.NewInternalErr(server.ErrNotBootstrapped))
+		errorResp(h.rd, w, server.ErrNotBootstrapped)
 		return
 	}
 
@@ -189,7 +223,7 @@ func (h *storeHandler) Delete(w http.ResponseWriter, r *http.Request) {
 func (h *storeHandler) SetState(w http.ResponseWriter, r *http.Request) {
 	cluster := h.svr.GetRaftCluster()
 	if cluster == nil {
-		h.rd.JSON(w, http.StatusInternalServerError, server.ErrNotBootstrapped.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
 		return
 	}
 
@@ -209,7 +243,7 @@ func (h *storeHandler) SetState(w http.ResponseWriter, r *http.Request) {
 
 	err := cluster.SetStoreState(storeID, metapb.StoreState(state))
 	if err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 		return
 	}
 
@@ -219,7 +253,7 @@ func (h *storeHandler) SetState(w http.ResponseWriter, r *http.Request) {
 func (h *storeHandler) SetLabels(w http.ResponseWriter, r *http.Request) {
 	cluster := h.svr.GetRaftCluster()
 	if cluster == nil {
-		h.rd.JSON(w, http.StatusInternalServerError, server.ErrNotBootstrapped.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
 		return
 	}
 
@@ -249,7 +283,29 @@ func (h *storeHandler) SetLabels(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := cluster.UpdateStoreLabels(storeID, labels); err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		errorResp(h.rd, w, err)
+		return
+	}
+
+	h.rd.JSON(w, http.StatusOK, nil)
+}
+
+func (h *storeHandler) DeleteLabel(w http.ResponseWriter, r *http.Request) {
+	cluster := h.svr.GetRaftCluster()
+	if cluster == nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
+		return
+	}
+
+	vars := mux.Vars(r)
+	storeID, errParse := apiutil.ParseUint64VarsField(vars, "id")
+	if errParse != nil {
+		errorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+
+	if err := cluster.DeleteStoreLabel(storeID, vars["key"]); err != nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 		return
 	}
 
@@ -259,7 +315,7 @@ func (h *storeHandler) SetLabels(w http.ResponseWriter, r *http.Request) {
 func (h *storeHandler) SetWeight(w http.ResponseWriter, r *http.Request) {
 	cluster := h.svr.GetRaftCluster()
 	if cluster == nil {
-		h.rd.JSON(w, http.StatusInternalServerError, server.ErrNotBootstrapped.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
 		return
 	}
 
@@ -297,7 +353,146 @@ func (h *storeHandler) SetWeight(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := cluster.SetStoreWeight(storeID, leader, region); err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
+		return
+	}
+
+	h.rd.JSON(w, http.StatusOK, nil)
+}
+
+// Cordon stops scheduling new peers and leaders to the store. The store is
+// uncordoned automatically after the optional duration.
+func (h *storeHandler) Cordon(w http.ResponseWriter, r *http.Request) {
+	cluster := h.svr.GetRaftCluster()
+	if cluster == nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
+		return
+	}
+
+	vars := mux.Vars(r)
+	storeID, errParse := apiutil.ParseUint64VarsField(vars, "id")
+	if errParse != nil {
+		errorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+
+	var deadline time.Time
+	if durationStr := r.URL.Query().Get("duration"); durationStr != "" {
+		duration, err := time.ParseDuration(durationStr)
+		if err != nil || duration <= 0 {
+			h.rd.JSON(w, http.StatusBadRequest, "invalid duration")
+			return
+		}
+		deadline = time.Now().Add(duration)
+	}
+
+	if err := cluster.CordonStore(storeID, deadline); err != nil {
+		errorResp(h.rd, w, err)
+		return
+	}
+
+	h.rd.JSON(w, http.StatusOK, nil)
+}
+
+// Uncordon allows scheduling new peers and leaders to the store.
+func (h *storeHandler) Uncordon(w http.ResponseWriter, r *http.Request) {
+	cluster := h.svr.GetRaftCluster()
+	if cluster == nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
+		return
+	}
+
+	vars := mux.Vars(r)
+	storeID, errParse := apiutil.ParseUint64VarsField(vars, "id")
+	if errParse != nil {
+		errorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+
+	if err := cluster.UncordonStore(storeID); err != nil {
+		errorResp(h.rd, w, err)
+		return
+	}
+
+	h.rd.JSON(w, http.StatusOK, nil)
+}
+
+// SetReplacement pairs the offline store with a replacement store, the peers
+// of the offline store are moved to the replacement store first.
+func (h *storeHandler) SetReplacement(w http.ResponseWriter, r *http.Request) {
+	cluster := h.svr.GetRaftCluster()
+	if cluster == nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
+		return
+	}
+
+	vars := mux.Vars(r)
+	storeID, errParse := apiutil.ParseUint64VarsField(vars, "id")
+	if errParse != nil {
+		errorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+
+	var input map[string]interface{}
+	if err := readJSONRespondError(h.rd, w, r.Body, &input); err != nil {
+		return
+	}
+	replacementID, ok := input["replacement_store_id"].(float64)
+	if !ok || replacementID <= 0 {
+		h.rd.JSON(w, http.StatusBadRequest, "invalid replacement store id")
+		return
+	}
+
+	if err := cluster.SetStoreReplacement(storeID, uint64(replacementID)); err != nil {
+		errorResp(h.rd, w, err)
+		return
+	}
+
+	h.rd.JSON(w, http.StatusOK, nil)
+}
+
+// GetReplacement returns the progress of moving the peers of the offline
+// store to its replacement store.
+func (h *storeHandler) GetReplacement(w http.ResponseWriter, r *http.Request) {
+	cluster := h.svr.GetRaftCluster()
+	if cluster == nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
+		return
+	}
+
+	vars := mux.Vars(r)
+	storeID, errParse := apiutil.ParseUint64VarsField(vars, "id")
+	if errParse != nil {
+		errorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+
+	progress, err := cluster.GetStoreReplacementProgress(storeID)
+	if err != nil {
+		errorResp(h.rd, w, err)
+		return
+	}
+
+	h.rd.JSON(w, http.StatusOK, progress)
+}
+
+// DeleteReplacement unpairs the store with its replacement store.
+func (h *storeHandler) DeleteReplacement(w http.ResponseWriter, r *http.Request) {
+	cluster := h.svr.GetRaftCluster()
+	if cluster == nil {
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
+		return
+	}
+
+	vars := mux.Vars(r)
+	storeID, errParse := apiutil.ParseUint64VarsField(vars, "id")
+	if errParse != nil {
+		errorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+
+	if err := cluster.DeleteStoreReplacement(storeID); err != nil {
+		errorResp(h.rd, w, err)
 		return
 	}
 
@@ -319,34 +514,55 @@ func newStoresHandler(svr *server.Server, rd *render.Render) *storesHandler {
 func (h *storesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	cluster := h.svr.GetRaftCluster()
 	if cluster == nil {
-		h.rd.JSON(w, http.StatusInternalServerError, server.ErrNotBootstrapped.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(server.ErrNotBootstrapped), server.ErrNotBootstrapped.Error())
 		return
 	}
 
-	stores := cluster.GetStores()
-	StoresInfo := &StoresInfo{
-		Stores: make([]*StoreInfo, 0, len(stores)),
-	}
-
 	urlFilter, err := newStoreStateFilter(r.URL)
 	if err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		h.rd.JSON(w, apiutil.ErrorStatus(err), err.Error())
 		return
 	}
-
-	stores = urlFilter.filter(cluster.GetStores())
-	for _, s := range stores {
-		store, err := cluster.GetStore(s.GetId())
-		if err != nil {
-			h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+	withStats := true
+	if v := r.URL.Query().Get("with_stats"); v != "" {
+		if withStats, err = strconv.ParseBool(v); err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
 			return
 		}
+	}
 
-		storeInfo := newStoreInfo(h.svr.GetScheduleConfig(), store)
+	// The metas and stats are taken from the same snapshot, so that they are
+	// consistent with each other.
+	version := cluster.GetStoresVersion()
+	stores := cluster.GetStoreInfos()
+	sort.Slice(stores, func(i, j int) bool { return stores[i].GetId() < stores[j].GetId() })
+	StoresInfo := &StoresInfo{
+		Stores: make([]*StoreInfo, 0, len(stores)),
+	}
+	cfg := h.svr.GetScheduleConfig()
+	for _, store := range stores {
+		if !urlFilter.accept(store.GetState()) {
+			continue
+		}
+		storeInfo := newStoreInfo(cfg, store)
+		if !withStats {
+			storeInfo.Status = nil
+		}
 		StoresInfo.Stores = append(StoresInfo.Stores, storeInfo)
 	}
 	StoresInfo.Count = len(StoresInfo.Stores)
 
+	// The states and the scores also depend on the time and the config.
+	for _, store := range StoresInfo.Stores {
+		version += fmt.Sprintf("-%s", store.Store.StateName)
+		if store.Status != nil && store.Status.Cordoned {
+			version += "-cordoned"
+		}
+	}
+	version += fmt.Sprintf("-%v-%v", cfg.HighSpaceRatio, cfg.LowSpaceRatio)
+	if checkNotModified(w, r, version) {
+		return
+	}
 	h.rd.JSON(w, http.StatusOK, StoresInfo)
 }
 
@@ -384,13 +600,18 @@ func newStoreStateFilter(u *url.URL) (*storeStateFilter, error) {
 func (filter *storeStateFilter) filter(stores []*metapb.Store) []*metapb.Store {
 	ret := make([]*metapb.Store, 0, len(stores))
 	for _, s := range stores {
-		state := s.GetState()
-		for _, accept := range filter.accepts {
-			if state == accept {
-				ret = append(ret, s)
-				break
-			}
+		if filter.accept(s.GetState()) {
+			ret = append(ret, s)
 		}
 	}
 	return ret
 }
+
+func (filter *storeStateFilter) accept(state metapb.StoreState) bool {
+	for _, accept := range filter.accepts {
+		if state == accept {
+			return true
+		}
+	}
+	return false
+}
@@ -19,6 +19,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	. "github.com/pingcap/check"
@@ -129,6 +130,15 @@ func (s *testStoreSuite) TestStoresList(c *C) {
 	c.Assert(err, IsNil)
 	checkStoresInfo(c, info.Stores, s.stores[2:3])
 
+	url = fmt.Sprintf("%s/stores?with_stats=false", s.urlPrefix)
+	info = new(StoresInfo)
+	err = readJSONWithURL(url, info)
+	c.Assert(err, IsNil)
+	c.Assert(info.Stores, HasLen, 3)
+	for i, store := range info.Stores {
+		c.Assert(store.Store.GetId(), Equals, s.stores[i].GetId())
+		c.Assert(store.Status, IsNil)
+	}
 }
 
 func (s *testStoreSuite) TestStoreGet(c *C) {
@@ -175,9 +185,46 @@ func (s *testStoreSuite) TestStoreLabel(c *C) {
 		c.Assert(expectLabel[l.Key], Equals, l.Value)
 	}
 
+	// Test strictly match location labels.
+	cfg := s.svr.GetReplicationConfig()
+	defer s.svr.SetReplicationConfig(*cfg)
+	strictCfg := *cfg
+	strictCfg.LocationLabels = []string{"zone", "host"}
+	strictCfg.StrictlyMatchLabel = true
+	c.Assert(s.svr.SetReplicationConfig(strictCfg), IsNil)
+	b, err = json.Marshal(map[string]string{"host": "host2"})
+	c.Assert(err, IsNil)
+	c.Assert(postJSON(url+"/label", b), NotNil)
+	c.Assert(s.deleteLabel(url, "zone"), Equals, http.StatusInternalServerError)
+
+	// Test delete.
+	c.Assert(s.deleteLabel(url, "zack"), Equals, http.StatusOK)
+	c.Assert(s.deleteLabel(url, "zack"), Equals, http.StatusInternalServerError)
+	c.Assert(postJSON(url+"/label", b), IsNil)
+	expectLabel = map[string]string{"zone": "cn", "host": "host2"}
+	err = readJSONWithURL(url, &info)
+	c.Assert(err, IsNil)
+	c.Assert(info.Store.Labels, HasLen, len(expectLabel))
+	for _, l := range info.Store.Labels {
+		c.Assert(expectLabel[l.Key], Equals, l.Value)
+	}
+
 	s.stores[0].Labels = info.Store.Labels
 }
 
+func (s *testStoreSuite) deleteLabel(url, key string) int {
+	req, err := http.NewRequest("DELETE", url+"/label/"+key, nil)
+	if err != nil {
+		return 0
+	}
+	resp, err := server.DialClient.Do(req)
+	if err != nil {
+		return 0
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
 func (s *testStoreSuite) TestStoreDelete(c *C) {
 	table := []struct {
 		id     int
@@ -232,6 +279,90 @@ func (s *testStoreSuite) TestStoreSetState(c *C) {
 	c.Assert(info.Store.State, Equals, metapb.StoreState_Up)
 }
 
+func (s *testStoreSuite) TestStoreCordon(c *C) {
+	url := fmt.Sprintf("%s/store/4", s.urlPrefix)
+	info := StoreInfo{}
+	err := readJSONWithURL(url, &info)
+	c.Assert(err, IsNil)
+	c.Assert(info.Status.Cordoned, IsFalse)
+
+	// Cordon for a duration.
+	err = postJSON(url+"/cordon?duration=1h", nil)
+	c.Assert(err, IsNil)
+	info = StoreInfo{}
+	err = readJSONWithURL(url, &info)
+	c.Assert(err, IsNil)
+	c.Assert(info.Status.Cordoned, IsTrue)
+	c.Assert(info.Status.CordonDeadline, NotNil)
+	c.Assert(info.Status.CordonDeadline.After(time.Now().Add(59*time.Minute)), IsTrue)
+	c.Assert(info.Store.State, Equals, metapb.StoreState_Up)
+
+	// Invalid duration.
+	err = postJSON(url+"/cordon?duration=foo", nil)
+	c.Assert(err, NotNil)
+
+	// Cordon without a deadline.
+	err = postJSON(url+"/cordon", nil)
+	c.Assert(err, IsNil)
+	info = StoreInfo{}
+	err = readJSONWithURL(url, &info)
+	c.Assert(err, IsNil)
+	c.Assert(info.Status.Cordoned, IsTrue)
+	c.Assert(info.Status.CordonDeadline, IsNil)
+
+	// Uncordon.
+	status, _ := requestStatusBody(c, server.DialClient, http.MethodDelete, url+"/cordon")
+	c.Assert(status, Equals, http.StatusOK)
+	info = StoreInfo{}
+	err = readJSONWithURL(url, &info)
+	c.Assert(err, IsNil)
+	c.Assert(info.Status.Cordoned, IsFalse)
+
+	// Tombstone and nonexistent stores.
+	status, _ = requestStatusBody(c, server.DialClient, http.MethodPost, fmt.Sprintf("%s/store/7/cordon", s.urlPrefix))
+	c.Assert(status, Equals, http.StatusGone)
+	status, _ = requestStatusBody(c, server.DialClient, http.MethodPost, fmt.Sprintf("%s/store/100/cordon", s.urlPrefix))
+	c.Assert(status, Equals, http.StatusNotFound)
+}
+
+func (s *testStoreSuite) TestStoreReplacement(c *C) {
+	url := fmt.Sprintf("%s/store/6", s.urlPrefix)
+	status, _ := requestStatusBody(c, server.DialClient, http.MethodGet, url+"/replacement")
+	c.Assert(status, Equals, http.StatusNotFound)
+
+	pair := func(storeID, replacementID uint64) int {
+		data := fmt.Sprintf(`{"replacement_store_id": %d}`, replacementID)
+		resp, err := server.DialClient.Post(fmt.Sprintf("%s/store/%d/replacement", s.urlPrefix, storeID), "application/json", strings.NewReader(data))
+		c.Assert(err, IsNil)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	c.Assert(pair(6, 4), Equals, http.StatusOK)
+	info := StoreInfo{}
+	err := readJSONWithURL(url, &info)
+	c.Assert(err, IsNil)
+	c.Assert(info.Status.ReplacementStoreID, Equals, uint64(4))
+	progress := server.StoreReplacementProgress{}
+	err = readJSONWithURL(url+"/replacement", &progress)
+	c.Assert(err, IsNil)
+	c.Assert(progress.StoreID, Equals, uint64(6))
+	c.Assert(progress.ReplacementStoreID, Equals, uint64(4))
+	c.Assert(progress.StateName, Equals, metapb.StoreState_Offline.String())
+	c.Assert(progress.Progress, Equals, 1.0)
+
+	// The store is not offline, or the replacement store is not up.
+	c.Assert(pair(1, 4), Equals, http.StatusBadRequest)
+	c.Assert(pair(6, 6), Equals, http.StatusBadRequest)
+	c.Assert(pair(6, 7), Equals, http.StatusBadRequest)
+	c.Assert(pair(6, 100), Equals, http.StatusNotFound)
+	c.Assert(pair(7, 4), Equals, http.StatusGone)
+
+	status, _ = requestStatusBody(c, server.DialClient, http.MethodDelete, url+"/replacement")
+	c.Assert(status, Equals, http.StatusOK)
+	status, _ = requestStatusBody(c, server.DialClient, http.MethodGet, url+"/replacement")
+	c.Assert(status, Equals, http.StatusNotFound)
+}
+
 func (s *testStoreSuite) TestUrlStoreFilter(c *C) {
 	table := []struct {
 		u    string
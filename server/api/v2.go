@@ -0,0 +1,377 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pingcap/errcode"
+	"github.com/pingcap/pd/pkg/apiutil"
+	"github.com/pingcap/pd/pkg/keyspacepb"
+	"github.com/pingcap/pd/server"
+	"github.com/pkg/errors"
+	"github.com/unrolled/render"
+)
+
+const (
+	apiV1Prefix = "/api/v1"
+	apiV2Prefix = "/api/v2"
+)
+
+func createRouterV2(prefix string, svr *server.Server) *mux.Router {
+	rd := render.New(render.Options{
+		IndentJSON: true,
+	})
+
+	router := mux.NewRouter().PathPrefix(prefix + apiV2Prefix).Subrouter()
+	h := newV2Handler(svr, rd)
+	router.HandleFunc("/cluster/status", h.GetClusterStatus).Methods("GET")
+	router.HandleFunc("/stores", h.GetStores).Methods("GET")
+	router.HandleFunc("/stores/{id}", h.GetStore).Methods("GET")
+	router.HandleFunc("/regions", h.GetRegions).Methods("GET")
+	router.HandleFunc("/regions/{id}", h.GetRegion).Methods("GET")
+	router.HandleFunc("/keyspaces", h.GetKeyspaces).Methods("GET")
+	router.HandleFunc("/keyspaces/{name}", h.GetKeyspace).Methods("GET")
+	router.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.error(w, errcode.NewNotFoundErr(errors.Errorf("no API for %s %s", r.Method, r.URL.Path)))
+	})
+	return router
+}
+
+// deprecationHeader marks the responses of API v1 as deprecated in favor of
+// API v2.
+type deprecationHeader struct {
+	successor string
+}
+
+func newDeprecationHeader() *deprecationHeader {
+	return &deprecationHeader{successor: apiPrefix + apiV2Prefix}
+}
+
+func (d *deprecationHeader) ServeHTTP(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	if strings.HasPrefix(r.URL.Path, apiPrefix+apiV1Prefix) {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", "<"+d.successor+`>; rel="successor-version"`)
+	}
+	next(w, r)
+}
+
+// ErrorV2 is the error body of API v2.
+type ErrorV2 struct {
+	// Code is the errcode string of the error, such as "missing".
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Cause is the message of the root cause if it is different from Message.
+	Cause string `json:"cause,omitempty"`
+}
+
+// toErrorCode attaches a code to the errors of the server package.
+func toErrorCode(err error) errcode.ErrorCode {
+	if errCode := errcode.CodeChain(err); errCode != nil {
+		return errCode
+	}
+	switch errors.Cause(err) {
+	case server.ErrKeyspaceNotFound, server.ErrOperatorNotFound:
+		return errcode.NewNotFoundErr(err)
+	case server.ErrKeyspaceExists, server.ErrInvalidKeyspace:
+		return errcode.NewInvalidInputErr(err)
+	}
+	return errcode.NewInternalErr(err)
+}
+
+func newErrorV2(err error) (int, *ErrorV2) {
+	errCode := toErrorCode(err)
+	body := &ErrorV2{
+		Code:    errCode.Code().CodeStr().String(),
+		Message: err.Error(),
+	}
+	if cause := errors.Cause(err).Error(); cause != body.Message {
+		body.Cause = cause
+	}
+	return errCode.Code().HTTPCode(), body
+}
+
+// fieldMask selects fields of JSON objects by dotted paths, such as "id" and
+// "leader.store_id". Masks apply to each element of arrays.
+type fieldMask map[string]fieldMask
+
+// parseFieldMask parses a comma separated list of paths. It returns nil if
+// there is no path, which selects all fields.
+func parseFieldMask(s string) fieldMask {
+	var mask fieldMask
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		}
+		if mask == nil {
+			mask = make(fieldMask)
+		}
+		m := mask
+		for _, name := range strings.Split(p, ".") {
+			if m[name] == nil {
+				m[name] = make(fieldMask)
+			}
+			m = m[name]
+		}
+	}
+	return mask
+}
+
+func (m fieldMask) filter(v interface{}) interface{} {
+	if len(m) == 0 {
+		return v
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(m))
+		for name, sub := range m {
+			if field, ok := v[name]; ok {
+				res[name] = sub.filter(field)
+			}
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, 0, len(v))
+		for _, e := range v {
+			res = append(res, m.filter(e))
+		}
+		return res
+	}
+	return v
+}
+
+// apply returns the masked JSON representation of v.
+func (m fieldMask) apply(v interface{}) (interface{}, error) {
+	if len(m) == 0 {
+		return v, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	// Keep numbers as they are, IDs do not fit in float64.
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var obj interface{}
+	if err := dec.Decode(&obj); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return m.filter(obj), nil
+}
+
+// ListV2 is the body of list responses of API v2.
+type ListV2 struct {
+	Count int         `json:"count"`
+	Items interface{} `json:"items"`
+}
+
+// KeyspaceV2 is the keyspace of API v2.
+type KeyspaceV2 struct {
+	ID             uint32            `json:"id"`
+	Name           string            `json:"name"`
+	State          string            `json:"state"`
+	CreatedAt      time.Time         `json:"created_at"`
+	StateChangedAt time.Time         `json:"state_changed_at"`
+	Config         map[string]string `json:"config,omitempty"`
+}
+
+func newKeyspaceV2(meta *keyspacepb.KeyspaceMeta) *KeyspaceV2 {
+	return &KeyspaceV2{
+		ID:             meta.GetId(),
+		Name:           meta.GetName(),
+		State:          meta.GetState().String(),
+		CreatedAt:      time.Unix(meta.GetCreatedAt(), 0).UTC(),
+		StateChangedAt: time.Unix(meta.GetStateChangedAt(), 0).UTC(),
+		Config:         meta.GetConfig(),
+	}
+}
+
+// ClusterStatusV2 is the cluster status of API v2.
+type ClusterStatusV2 struct {
+	RaftBootstrapTime  *time.Time     `json:"raft_bootstrap_time"`
+	IsPrepared         bool           `json:"is_prepared"`
+	RegionLoadProgress float64        `json:"region_load_progress"`
+	StoreStates        map[string]int `json:"store_states"`
+	// Leader is the name of the leader member.
+	Leader          string                        `json:"leader"`
+	ReplicationMode *server.ReplicationModeStatus `json:"replication_mode"`
+}
+
+type v2Handler struct {
+	svr     *server.Server
+	handler *server.Handler
+	rd      *render.Render
+}
+
+func newV2Handler(svr *server.Server, rd *render.Render) *v2Handler {
+	return &v2Handler{
+		svr:     svr,
+		handler: svr.GetHandler(),
+		rd:      rd,
+	}
+}
+
+func (h *v2Handler) error(w http.ResponseWriter, err error) {
+	status, body := newErrorV2(err)
+	h.rd.JSON(w, status, body)
+}
+
+// respond writes the result with the field mask in the `fields` parameter.
+// The mask of a list applies to its items.
+func (h *v2Handler) respond(w http.ResponseWriter, r *http.Request, result interface{}) {
+	mask := parseFieldMask(r.URL.Query().Get("fields"))
+	var err error
+	if list, ok := result.(*ListV2); ok {
+		list.Items, err = mask.apply(list.Items)
+	} else {
+		result, err = mask.apply(result)
+	}
+	if err != nil {
+		h.error(w, err)
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, result)
+}
+
+func (h *v2Handler) GetClusterStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := h.svr.GetClusterStatus()
+	if err != nil {
+		h.error(w, err)
+		return
+	}
+	res := &ClusterStatusV2{
+		IsPrepared:         status.IsPrepared,
+		RegionLoadProgress: status.RegionLoadProgress,
+		StoreStates:        status.StoreStates,
+		Leader:             status.Leader.GetName(),
+		ReplicationMode:    status.ReplicationMode,
+	}
+	if !status.RaftBootstrapTime.IsZero() {
+		t := status.RaftBootstrapTime.UTC()
+		res.RaftBootstrapTime = &t
+	}
+	h.respond(w, r, res)
+}
+
+func (h *v2Handler) GetStores(w http.ResponseWriter, r *http.Request) {
+	stores, err := h.handler.GetStores()
+	if err != nil {
+		h.error(w, err)
+		return
+	}
+	stateFilter, err := newStoreStateFilter(r.URL)
+	if err != nil {
+		h.error(w, errcode.NewInvalidInputErr(err))
+		return
+	}
+	opt := h.svr.GetScheduleConfig()
+	items := make([]*StoreInfo, 0, len(stores))
+	for _, store := range stores {
+		if stateFilter.accept(store.GetState()) {
+			items = append(items, newStoreInfo(opt, store))
+		}
+	}
+	h.respond(w, r, &ListV2{Count: len(items), Items: items})
+}
+
+func (h *v2Handler) GetStore(w http.ResponseWriter, r *http.Request) {
+	storeID, errParse := apiutil.ParseUint64VarsField(mux.Vars(r), "id")
+	if errParse != nil {
+		h.error(w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+	cluster := h.svr.GetRaftCluster()
+	if cluster == nil {
+		h.error(w, server.ErrNotBootstrapped)
+		return
+	}
+	store, err := cluster.GetStore(storeID)
+	if err != nil {
+		h.error(w, err)
+		return
+	}
+	h.respond(w, r, newStoreInfo(h.svr.GetScheduleConfig(), store))
+}
+
+// GetRegions scans regions from the `key` parameter, at most `limit` regions
+// are returned. The limit must be positive.
+func (h *v2Handler) GetRegions(w http.ResponseWriter, r *http.Request) {
+	limit := defaultRegionLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		if limit, err = strconv.Atoi(limitStr); err != nil {
+			h.error(w, errcode.NewInvalidInputErr(errors.WithStack(err)))
+			return
+		}
+		if limit <= 0 {
+			h.error(w, errcode.NewInvalidInputErr(errors.Errorf("invalid limit %d", limit)))
+			return
+		}
+	}
+	if limit > maxRegionLimit {
+		limit = maxRegionLimit
+	}
+	regions, err := h.handler.ScanRegions([]byte(r.URL.Query().Get("key")), limit)
+	if err != nil {
+		h.error(w, err)
+		return
+	}
+	items := make([]*regionInfo, 0, len(regions))
+	for _, region := range regions {
+		items = append(items, newRegionInfo(region))
+	}
+	h.respond(w, r, &ListV2{Count: len(items), Items: items})
+}
+
+func (h *v2Handler) GetRegion(w http.ResponseWriter, r *http.Request) {
+	regionID, errParse := apiutil.ParseUint64VarsField(mux.Vars(r), "id")
+	if errParse != nil {
+		h.error(w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+	region, err := h.handler.GetRegion(regionID)
+	if err != nil {
+		h.error(w, err)
+		return
+	}
+	h.respond(w, r, newRegionInfo(region))
+}
+
+func (h *v2Handler) GetKeyspaces(w http.ResponseWriter, r *http.Request) {
+	keyspaces, err := h.svr.GetKeyspaces()
+	if err != nil {
+		h.error(w, err)
+		return
+	}
+	items := make([]*KeyspaceV2, 0, len(keyspaces))
+	for _, meta := range keyspaces {
+		items = append(items, newKeyspaceV2(meta))
+	}
+	h.respond(w, r, &ListV2{Count: len(items), Items: items})
+}
+
+func (h *v2Handler) GetKeyspace(w http.ResponseWriter, r *http.Request) {
+	meta, err := h.svr.GetKeyspace(mux.Vars(r)["name"])
+	if err != nil {
+		h.error(w, err)
+		return
+	}
+	h.respond(w, r, newKeyspaceV2(meta))
+}
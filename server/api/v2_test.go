@@ -0,0 +1,136 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/pd/server"
+)
+
+var _ = Suite(&testAPIV2Suite{})
+
+type testAPIV2Suite struct {
+	svr       *server.Server
+	cleanup   cleanUpFunc
+	urlPrefix string
+}
+
+func (s *testAPIV2Suite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c)
+	mustWaitLeader(c, []*server.Server{s.svr})
+
+	s.urlPrefix = fmt.Sprintf("%s%s/api/v2", s.svr.GetAddr(), apiPrefix)
+}
+
+func (s *testAPIV2Suite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+func (s *testAPIV2Suite) mustRequestError(c *C, url string, status int, code string) *ErrorV2 {
+	res, body := requestStatusBody(c, newHTTPClient(), "GET", url)
+	c.Assert(res, Equals, status)
+	e := &ErrorV2{}
+	c.Assert(json.Unmarshal(body, e), IsNil)
+	c.Assert(e.Code, Equals, code)
+	return e
+}
+
+func (s *testAPIV2Suite) TestAPIV2(c *C) {
+	e := s.mustRequestError(c, s.urlPrefix+"/regions", http.StatusServiceUnavailable, "state.not_bootstrapped")
+	c.Assert(e.Message, Equals, server.ErrNotBootstrapped.Error())
+	s.mustRequestError(c, s.urlPrefix+"/unknown", http.StatusNotFound, "missing")
+	// The v1 API responds with the same status.
+	resp, err := newHTTPClient().Get(fmt.Sprintf("%s%s/api/v1/regions", s.svr.GetAddr(), apiPrefix))
+	c.Assert(err, IsNil)
+	resp.Body.Close()
+	c.Assert(resp.StatusCode, Equals, http.StatusServiceUnavailable)
+
+	mustBootstrapCluster(c, s.svr)
+	r := newTestRegionInfo(2, 1, []byte("a"), []byte("b"))
+	mustRegionHeartbeat(c, s.svr, r)
+
+	// The whole region.
+	region := &regionInfo{}
+	c.Assert(readJSONWithURL(fmt.Sprintf("%s/regions/%d", s.urlPrefix, r.GetID()), region), IsNil)
+	c.Assert(region, DeepEquals, newRegionInfo(r))
+	e = s.mustRequestError(c, s.urlPrefix+"/regions/100", http.StatusNotFound, "missing")
+	c.Assert(e.Message, Equals, "region 100 not found")
+	s.mustRequestError(c, s.urlPrefix+"/regions/abc", http.StatusBadRequest, "input")
+	s.mustRequestError(c, s.urlPrefix+"/regions?limit=0", http.StatusBadRequest, "input")
+	s.mustRequestError(c, s.urlPrefix+"/regions?limit=-1", http.StatusBadRequest, "input")
+
+	// Only the fields in the mask.
+	var regions struct {
+		Count int                      `json:"count"`
+		Items []map[string]interface{} `json:"items"`
+	}
+	c.Assert(readJSONWithURL(s.urlPrefix+"/regions?fields=id,start_key,epoch.version", &regions), IsNil)
+	c.Assert(regions.Count, Equals, 1)
+	item := regions.Items[0]
+	c.Assert(item, HasLen, 3)
+	c.Assert(item["epoch"], HasLen, 1)
+	c.Assert(item["id"], Equals, float64(2))
+	c.Assert(item["start_key"], Equals, region.StartKey)
+
+	// Timestamps are in ISO8601.
+	_, err = s.svr.CreateKeyspace("ks1", nil)
+	c.Assert(err, IsNil)
+	var keyspace map[string]interface{}
+	c.Assert(readJSONWithURL(s.urlPrefix+"/keyspaces/ks1", &keyspace), IsNil)
+	_, err = time.Parse(time.RFC3339, keyspace["created_at"].(string))
+	c.Assert(err, IsNil)
+	c.Assert(keyspace["state"], Equals, "ENABLED")
+	s.mustRequestError(c, s.urlPrefix+"/keyspaces/ks2", http.StatusNotFound, "missing")
+}
+
+func (s *testAPIV2Suite) TestDeprecationHeader(c *C) {
+	resp, err := http.Get(fmt.Sprintf("%s%s/api/v1/config", s.svr.GetAddr(), apiPrefix))
+	c.Assert(err, IsNil)
+	resp.Body.Close()
+	c.Assert(resp.Header.Get("Deprecation"), Equals, "true")
+	c.Assert(resp.Header.Get("Link"), Equals, `</pd/api/v2>; rel="successor-version"`)
+
+	resp, err = http.Get(s.urlPrefix + "/cluster/status")
+	c.Assert(err, IsNil)
+	resp.Body.Close()
+	c.Assert(resp.Header.Get("Deprecation"), Equals, "")
+}
+
+func (s *testAPIV2Suite) TestFieldMask(c *C) {
+	c.Assert(parseFieldMask(""), IsNil)
+	c.Assert(parseFieldMask(" , "), IsNil)
+	mask := parseFieldMask("id, leader.store_id,peers.id")
+	c.Assert(mask, DeepEquals, fieldMask{
+		"id":     {},
+		"leader": {"store_id": {}},
+		"peers":  {"id": {}},
+	})
+
+	obj := map[string]interface{}{
+		"id":        uint64(1) << 60,
+		"start_key": "a",
+		"leader":    map[string]interface{}{"id": 1, "store_id": 2},
+		"peers":     []interface{}{map[string]interface{}{"id": 1, "store_id": 2}},
+	}
+	res, err := mask.apply(obj)
+	c.Assert(err, IsNil)
+	data, err := json.Marshal(res)
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, `{"id":1152921504606846976,"leader":{"store_id":2},"peers":[{"id":1}]}`)
+}
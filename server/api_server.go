@@ -0,0 +1,156 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/soheilhy/cmux"
+	"google.golang.org/grpc"
+)
+
+// apiServer serves the PD HTTP and gRPC APIs on the api urls, which are
+// separated from the etcd client urls.
+type apiServer struct {
+	urls       string
+	tlsConfig  *tls.Config
+	grpcServer *grpc.Server
+	handler    http.Handler
+
+	wg        sync.WaitGroup
+	mu        sync.Mutex
+	listeners []net.Listener
+	servers   []*http.Server
+}
+
+func newAPIServer(urls string, security SecurityConfig, handler http.Handler, opts ...grpc.ServerOption) (*apiServer, error) {
+	tlsConfig, err := security.ToServerTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	if handler != nil {
+		mux.Handle(pdAPIPrefix, handler)
+	}
+	return &apiServer{
+		urls:       urls,
+		tlsConfig:  tlsConfig,
+		grpcServer: grpc.NewServer(opts...),
+		handler:    mux,
+	}, nil
+}
+
+// start listens on all the api urls and serves them in the background.
+func (a *apiServer) start() error {
+	urls, err := ParseUrls(a.urls)
+	if err != nil {
+		return err
+	}
+	for _, u := range urls {
+		if u.Scheme == "https" && a.tlsConfig == nil {
+			return errors.Errorf("api url %s requires the cert and key in security config", u.String())
+		}
+	}
+	for _, u := range urls {
+		l, err := net.Listen("tcp", u.Host)
+		if err != nil {
+			a.close()
+			return errors.WithStack(err)
+		}
+		log.Infof("serve PD API on %s", u.String())
+		if u.Scheme == "https" {
+			a.serveTLS(l)
+		} else {
+			a.serveInsecure(l)
+		}
+	}
+	return nil
+}
+
+// serveInsecure splits the gRPC and HTTP connections by the preface, the
+// same as the etcd client listeners.
+func (a *apiServer) serveInsecure(l net.Listener) {
+	m := cmux.New(l)
+	grpcl := m.MatchWithWriters(cmux.HTTP2MatchHeaderFieldSendSettings("content-type", "application/grpc"))
+	httpl := m.Match(cmux.Any())
+	srv := &http.Server{Handler: a.handler}
+
+	a.mu.Lock()
+	a.listeners = append(a.listeners, l)
+	a.servers = append(a.servers, srv)
+	a.mu.Unlock()
+
+	a.serve(func() error { return a.grpcServer.Serve(grpcl) })
+	a.serve(func() error { return srv.Serve(httpl) })
+	a.serve(m.Serve)
+}
+
+// serveTLS serves the gRPC requests by the HTTP/2 server of net/http, which is
+// negotiated in the TLS handshake.
+func (a *apiServer) serveTLS(l net.Listener) {
+	tlsConfig := a.tlsConfig.Clone()
+	tlsConfig.NextProtos = []string{"h2", "http/1.1"}
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ProtoMajor == 2 && strings.Contains(r.Header.Get("Content-Type"), "application/grpc") {
+				a.grpcServer.ServeHTTP(w, r)
+				return
+			}
+			a.handler.ServeHTTP(w, r)
+		}),
+		TLSConfig: tlsConfig,
+	}
+	tlsl := tls.NewListener(l, tlsConfig)
+
+	a.mu.Lock()
+	a.listeners = append(a.listeners, l)
+	a.servers = append(a.servers, srv)
+	a.mu.Unlock()
+
+	a.serve(func() error { return srv.Serve(tlsl) })
+}
+
+func (a *apiServer) serve(f func() error) {
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		if err := f(); err != nil && !isClosedConnError(err) && err != http.ErrServerClosed && err != grpc.ErrServerStopped {
+			log.Errorf("serve PD API error: %v", err)
+		}
+	}()
+}
+
+func (a *apiServer) close() {
+	a.mu.Lock()
+	for _, srv := range a.servers {
+		srv.Close()
+	}
+	for _, l := range a.listeners {
+		l.Close()
+	}
+	a.servers, a.listeners = nil, nil
+	a.mu.Unlock()
+	a.grpcServer.Stop()
+	a.wg.Wait()
+}
+
+func isClosedConnError(err error) bool {
+	return strings.Contains(err.Error(), "use of closed network connection") || strings.Contains(err.Error(), "mux: listener closed")
+}
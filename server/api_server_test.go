@@ -0,0 +1,70 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/pkg/tempurl"
+	"google.golang.org/grpc"
+)
+
+var _ = Suite(&testAPIServerSuite{})
+
+type testAPIServerSuite struct{}
+
+func (s *testAPIServerSuite) TestSeparatedAPIUrls(c *C) {
+	cfg := NewTestSingleConfig()
+	cfg.APIUrls = tempurl.Alloc()
+	cfg.AdvertiseAPIUrls = cfg.APIUrls
+	defer cleanServer(cfg)
+
+	svr, err := CreateServer(cfg, func(*Server) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+	})
+	c.Assert(err, IsNil)
+	c.Assert(svr.Run(context.TODO()), IsNil)
+	defer svr.Close()
+	mustWaitLeader(c, []*Server{svr})
+	c.Assert(svr.GetAddr(), Equals, cfg.APIUrls)
+
+	// The HTTP APIs are served on the api urls only.
+	resp, err := http.Get(cfg.APIUrls + pdAPIPrefix + "ping")
+	c.Assert(err, IsNil)
+	resp.Body.Close()
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	resp, err = http.Get(cfg.ClientUrls + pdAPIPrefix + "ping")
+	c.Assert(err, IsNil)
+	resp.Body.Close()
+	c.Assert(resp.StatusCode, Equals, http.StatusNotFound)
+
+	// The gRPC APIs are served on the api urls and the members are advertised
+	// with the api urls.
+	u, err := url.Parse(cfg.APIUrls)
+	c.Assert(err, IsNil)
+	conn, err := grpc.Dial(u.Host, grpc.WithInsecure())
+	c.Assert(err, IsNil)
+	defer conn.Close()
+	members, err := pdpb.NewPDClient(conn).GetMembers(context.TODO(), &pdpb.GetMembersRequest{})
+	c.Assert(err, IsNil)
+	c.Assert(members.GetMembers(), HasLen, 1)
+	c.Assert(members.GetMembers()[0].GetClientUrls(), DeepEquals, []string{cfg.APIUrls})
+	c.Assert(members.GetLeader().GetClientUrls(), DeepEquals, []string{cfg.APIUrls})
+}
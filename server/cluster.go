@@ -16,6 +16,7 @@ package server
 import (
 	"fmt"
 	"path"
+	"strings"
 	"sync"
 	"time"
 
@@ -24,6 +25,7 @@ import (
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/pd/pkg/logutil"
 	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/keyvisual"
 	"github.com/pingcap/pd/server/namespace"
 	"github.com/pingcap/pd/server/region_syncer"
 	"github.com/pkg/errors"
@@ -42,7 +44,7 @@ const (
 // store 1 -> /1/raft/s/1, value is metapb.Store
 // region 1 -> /1/raft/r/1, value is metapb.Region
 type RaftCluster struct {
-	sync.RWMutex
+	rwLock
 
 	s *Server
 
@@ -56,6 +58,24 @@ type RaftCluster struct {
 
 	coordinator *coordinator
 
+	replicationMode *replicationModeManager
+
+	janitor *janitor
+	// loadSplitter splits the regions which are hot for a long time.
+	loadSplitter *loadSplitter
+	// snapshotBudget shares the snapshot bandwidth among the stores.
+	snapshotBudget *snapshotBudget
+
+	// keyVisual keeps the region flow over key ranges for the heatmap.
+	keyVisual *keyvisual.Stat
+	// downStores are the down stores found by the background jobs.
+	downStores map[uint64]struct{}
+	events     *eventLog
+
+	// hbWorkers apply the region heartbeats, nil if they are applied by the
+	// heartbeat streams.
+	hbWorkers *regionHeartbeatWorkers
+
 	wg           sync.WaitGroup
 	quit         chan struct{}
 	regionSyncer *syncer.RegionSyncer
@@ -64,20 +84,61 @@ type RaftCluster struct {
 // ClusterStatus saves some state information
 type ClusterStatus struct {
 	RaftBootstrapTime time.Time `json:"raft_bootstrap_time,omitempty"`
+	// IsPrepared means the leader has collected enough region heartbeats and
+	// the coordinator has started. It is false in a while after restarting.
+	IsPrepared bool `json:"is_prepared"`
+	// RegionLoadProgress is the percentage of the regions which are required
+	// to be reported before the coordinator starts.
+	RegionLoadProgress float64 `json:"region_load_progress"`
+	// StoreStates is the number of stores in each state, Disconnected and
+	// Down are also counted besides the store states.
+	StoreStates     map[string]int         `json:"store_states,omitempty"`
+	Leader          *pdpb.Member           `json:"leader,omitempty"`
+	ReplicationMode *ReplicationModeStatus `json:"replication_mode,omitempty"`
 }
 
 func newRaftCluster(s *Server, clusterID uint64) *RaftCluster {
-	return &RaftCluster{
+	c := &RaftCluster{
 		s:            s,
 		running:      false,
 		clusterID:    clusterID,
 		clusterRoot:  s.getClusterRootPath(),
 		regionSyncer: syncer.NewRegionSyncer(s),
+		events:       s.events,
+	}
+	c.setName("raft-cluster")
+	c.janitor = newJanitor(c)
+	c.keyVisual = newKeyVisualStat()
+	c.loadSplitter = newLoadSplitter(s.scheduleOpt)
+	c.snapshotBudget = newSnapshotBudget(s.scheduleOpt)
+	return c
+}
+
+// fillClusterStatus fills the runtime status of the cluster.
+func (c *RaftCluster) fillClusterStatus(status *ClusterStatus) {
+	cluster := c.cachedCluster
+	status.IsPrepared = cluster.isPrepared()
+	status.RegionLoadProgress = 100
+	if !status.IsPrepared {
+		status.RegionLoadProgress = cluster.prepareProgress() * 100
+	}
+	status.StoreStates = make(map[string]int)
+	for _, store := range cluster.GetStores() {
+		state := store.GetState().String()
+		if store.IsUp() {
+			if store.DownTime() > cluster.GetMaxStoreDownTime() {
+				state = "Down"
+			} else if store.IsDisconnected() {
+				state = "Disconnected"
+			}
+		}
+		status.StoreStates[state]++
 	}
+	status.ReplicationMode = c.GetReplicationModeStatus()
 }
 
 func (c *RaftCluster) loadClusterStatus() (*ClusterStatus, error) {
-	data, err := c.s.kv.Load((c.s.kv.ClusterStatePath("raft_bootstrap_time")))
+	data, err := c.s.kv.Load(c.s.kv.Context(), c.s.kv.ClusterStatePath("raft_bootstrap_time"))
 	if err != nil {
 		return nil, err
 	}
@@ -114,14 +175,33 @@ func (c *RaftCluster) start() error {
 	}
 
 	c.cachedCluster = cluster
+	c.cachedCluster.events = c.events
+	c.cachedCluster.core.Regions.Subscribe(func(e *core.RegionEvent) {
+		if e.Type == core.RegionDeleted {
+			c.loadSplitter.remove(e.Region.GetID())
+		}
+	})
+	c.cachedCluster.OnStoreVersionChange()
 	c.coordinator = newCoordinator(c.cachedCluster, c.s.hbStreams, c.s.classifier)
 	c.cachedCluster.regionStats = newRegionStatistics(c.s.scheduleOpt, c.s.classifier)
+	c.replicationMode, err = newReplicationModeManager(c.s.cfg.ReplicationMode, c.cachedCluster)
+	if err != nil {
+		return err
+	}
+	c.downStores = make(map[uint64]struct{})
 	c.quit = make(chan struct{})
 
-	c.wg.Add(3)
+	c.wg.Add(6)
 	go c.runCoordinator()
 	go c.runBackgroundJobs(backgroundJobInterval)
 	go c.syncRegions()
+	go c.runReplicationMode(replicationModeTickInterval)
+	go c.runJanitor(janitorInterval)
+	go c.runKeyVisual(keyVisualInterval)
+	if n := c.s.scheduleOpt.loadPDServerConfig().RegionHeartbeatWorkers; n > 0 {
+		c.hbWorkers = newRegionHeartbeatWorkers(c, int(n))
+		c.hbWorkers.run()
+	}
 	c.running = true
 
 	return nil
@@ -155,6 +235,8 @@ func (c *RaftCluster) stop() {
 	close(c.quit)
 	c.coordinator.stop()
 	c.wg.Wait()
+	c.hbWorkers = nil
+	c.cachedCluster.watchers.cancelAll()
 }
 
 func (c *RaftCluster) isRunning() bool {
@@ -227,15 +309,12 @@ func (c *RaftCluster) GetRegionByKey(regionKey []byte) (*metapb.Region, *metapb.
 	return region.GetMeta(), region.GetLeader()
 }
 
-// GetPrevRegionByKey gets previous region and leader peer by the region key from cluster.
-func (c *RaftCluster) GetPrevRegionByKey(regionKey []byte) (*metapb.Region, *metapb.Peer) {
+// GetPrevRegionInfoByKey gets regionInfo of the previous region by the region
+// key from cluster.
+func (c *RaftCluster) GetPrevRegionInfoByKey(regionKey []byte) *core.RegionInfo {
 	c.RLock()
 	defer c.RUnlock()
-	region := c.cachedCluster.searchPrevRegion(regionKey)
-	if region == nil {
-		return nil, nil
-	}
-	return region.GetMeta(), region.GetLeader()
+	return c.cachedCluster.searchPrevRegion(regionKey)
 }
 
 // GetRegionInfoByKey gets regionInfo by region key from cluster.
@@ -252,17 +331,6 @@ func (c *RaftCluster) ScanRegionsByKey(startKey []byte, limit int) []*core.Regio
 	return c.cachedCluster.ScanRegions(startKey, limit)
 }
 
-// GetRegionByID gets region and leader peer by regionID from cluster.
-func (c *RaftCluster) GetRegionByID(regionID uint64) (*metapb.Region, *metapb.Peer) {
-	c.RLock()
-	defer c.RUnlock()
-	region := c.cachedCluster.GetRegion(regionID)
-	if region == nil {
-		return nil, nil
-	}
-	return region.GetMeta(), region.GetLeader()
-}
-
 // GetRegionInfoByID gets regionInfo by regionID from cluster.
 func (c *RaftCluster) GetRegionInfoByID(regionID uint64) *core.RegionInfo {
 	c.RLock()
@@ -284,6 +352,15 @@ func (c *RaftCluster) GetRegions() []*core.RegionInfo {
 	return c.cachedCluster.getRegions()
 }
 
+// GetRegionsVersion returns the version of the regions, which changes whenever
+// the regions change. It should be got before the regions, so that the regions
+// are not older than the version.
+func (c *RaftCluster) GetRegionsVersion() string {
+	c.RLock()
+	defer c.RUnlock()
+	return c.cachedCluster.getRegionsVersion()
+}
+
 // GetStoreRegions returns all regions info with a given storeID.
 func (c *RaftCluster) GetStoreRegions(storeID uint64) []*core.RegionInfo {
 	c.RLock()
@@ -291,6 +368,22 @@ func (c *RaftCluster) GetStoreRegions(storeID uint64) []*core.RegionInfo {
 	return c.cachedCluster.getStoreRegions(storeID)
 }
 
+// GetRegionsInRange returns at most limit regions overlapping with the range
+// [startKey, endKey).
+func (c *RaftCluster) GetRegionsInRange(startKey, endKey []byte, limit int) []*core.RegionInfo {
+	c.RLock()
+	defer c.RUnlock()
+	return c.cachedCluster.getRegionsInRange(startKey, endKey, limit)
+}
+
+// GetRangeSummary returns the count, size and keys of the regions overlapping
+// with the range [startKey, endKey).
+func (c *RaftCluster) GetRangeSummary(startKey, endKey []byte, sampleLimit int) *core.RangeSummary {
+	c.RLock()
+	defer c.RUnlock()
+	return c.cachedCluster.getRangeSummary(startKey, endKey, sampleLimit)
+}
+
 // GetRegionStats returns region statistics from cluster.
 func (c *RaftCluster) GetRegionStats(startKey, endKey []byte) *core.RegionStats {
 	c.RLock()
@@ -312,6 +405,23 @@ func (c *RaftCluster) GetStores() []*metapb.Store {
 	return c.cachedCluster.getMetaStores()
 }
 
+// GetStoreInfos gets the stores with their stats, which are taken from the
+// same snapshot of the cluster.
+func (c *RaftCluster) GetStoreInfos() []*core.StoreInfo {
+	c.RLock()
+	defer c.RUnlock()
+	return c.cachedCluster.GetStores()
+}
+
+// GetStoresVersion returns the version of the stores, which changes whenever
+// the stores change. It should be got before the stores, so that the stores
+// are not older than the version.
+func (c *RaftCluster) GetStoresVersion() string {
+	c.RLock()
+	defer c.RUnlock()
+	return c.cachedCluster.getStoresVersion()
+}
+
 // GetStore gets store from cluster.
 func (c *RaftCluster) GetStore(storeID uint64) (*core.StoreInfo, error) {
 	c.RLock()
@@ -349,6 +459,64 @@ func (c *RaftCluster) UpdateStoreLabels(storeID uint64, labels []*metapb.StoreLa
 	return err
 }
 
+// DeleteStoreLabel deletes a label of the store. The location labels can not
+// be deleted.
+func (c *RaftCluster) DeleteStoreLabel(storeID uint64, labelKey string) error {
+	c.RLock()
+	defer c.RUnlock()
+	for _, k := range c.cachedCluster.GetLocationLabels() {
+		if strings.EqualFold(k, labelKey) {
+			return errors.Errorf("location label %q can not be deleted", labelKey)
+		}
+	}
+	store := c.cachedCluster.GetStore(storeID)
+	if store == nil {
+		return errors.Errorf("invalid store ID %d, not found", storeID)
+	}
+	labels := make([]*metapb.StoreLabel, 0, len(store.Labels))
+	for _, label := range store.Labels {
+		if !strings.EqualFold(label.GetKey(), labelKey) {
+			labels = append(labels, label)
+		}
+	}
+	if len(labels) == len(store.Labels) {
+		return errors.Errorf("label %q of store %d not found", labelKey, storeID)
+	}
+	store.Labels = labels
+	return c.cachedCluster.putStore(store)
+}
+
+// checkStoreLabels checks the store labels against the label schema and the
+// location labels. In the strict mode, every location label must be set, and other labels are not
+// allowed.
+func (c *RaftCluster) checkStoreLabels(s *core.StoreInfo) error {
+	for _, label := range s.Labels {
+		if reason := c.s.cfg.LabelSchema.check(label.GetKey(), label.GetValue()); reason != "" {
+			return core.InvalidStoreLabelErr{StoreID: s.GetId(), Key: label.GetKey(), Value: label.GetValue(), Reason: reason}
+		}
+	}
+	strict := c.cachedCluster.opt.GetStrictlyMatchLabel()
+	keys := make(map[string]struct{})
+	for _, k := range c.cachedCluster.GetLocationLabels() {
+		keys[strings.ToLower(k)] = struct{}{}
+		if v := s.GetLabelValue(k); len(v) == 0 {
+			if strict {
+				return errors.Errorf("missing location label %q in store %v", k, s.Store)
+			}
+			log.Warnf("missing location label %q in store %v", k, s)
+		}
+	}
+	if !strict {
+		return nil
+	}
+	for _, label := range s.Labels {
+		if _, ok := keys[strings.ToLower(label.GetKey())]; !ok {
+			return errors.Errorf("label %q of store %v is not in location labels", label.GetKey(), s.Store)
+		}
+	}
+	return nil
+}
+
 func (c *RaftCluster) putStore(store *metapb.Store) error {
 	c.RLock()
 	defer c.RUnlock()
@@ -375,27 +543,40 @@ func (c *RaftCluster) putStore(store *metapb.Store) error {
 			continue
 		}
 		if s.GetId() != store.GetId() && s.GetAddress() == store.GetAddress() {
-			return errors.Errorf("duplicated store address: %v, already registered by %v", store, s.Store)
+			return core.StoreAddressConflictedErr{StoreID: store.GetId(), Address: store.GetAddress(), ConflictStoreID: s.GetId()}
 		}
 	}
 
 	s := cluster.GetStore(store.GetId())
-	if s == nil {
+	isNew, oldVersion := s == nil, ""
+	if isNew {
 		// Add a new store.
 		s = core.NewStoreInfo(store)
 	} else {
+		// A store can move to another address only if it is not running at
+		// the registered one.
+		if !s.IsTombstone() && s.GetAddress() != store.GetAddress() && !s.IsDisconnected() {
+			return core.StoreIDConflictedErr{StoreID: store.GetId(), Address: store.GetAddress(), RegisteredAddress: s.GetAddress()}
+		}
 		// Update an existed store.
+		oldVersion = s.GetVersion()
 		s.Address = store.Address
 		s.Version = store.Version
 		s.MergeLabels(store.Labels)
 	}
 	// Check location labels.
-	for _, k := range c.cachedCluster.GetLocationLabels() {
-		if v := s.GetLabelValue(k); len(v) == 0 {
-			log.Warnf("missing location label %q in store %v", k, s)
-		}
+	if err := c.checkStoreLabels(s); err != nil {
+		return err
 	}
-	return cluster.putStore(s)
+	if err := cluster.putStore(s); err != nil {
+		return err
+	}
+	if isNew {
+		c.events.record(EventStoreUp, s.GetId(), 0, "store %d is registered with address %s, version %s", s.GetId(), s.GetAddress(), s.GetVersion())
+	} else if oldVersion != s.GetVersion() {
+		c.events.record(EventStoreVersionChanged, s.GetId(), 0, "store %d version changed from %s to %s", s.GetId(), oldVersion, s.GetVersion())
+	}
+	return nil
 }
 
 // RemoveStore marks a store as offline in cluster.
@@ -423,7 +604,11 @@ func (c *RaftCluster) RemoveStore(storeID uint64) error {
 
 	store.State = metapb.StoreState_Offline
 	log.Warnf("[store %d] store %s has been Offline", store.GetId(), store.GetAddress())
-	return cluster.putStore(store)
+	if err := cluster.putStore(store); err != nil {
+		return err
+	}
+	c.events.record(EventStoreOffline, storeID, 0, "store %d is offline", storeID)
+	return nil
 }
 
 // BuryStore marks a store as tombstone in cluster.
@@ -455,7 +640,15 @@ func (c *RaftCluster) BuryStore(storeID uint64, force bool) error { // revive:di
 
 	store.State = metapb.StoreState_Tombstone
 	log.Warnf("[store %d] store %s has been Tombstone", store.GetId(), store.GetAddress())
-	return cluster.putStore(store)
+	if err := cluster.putStore(store); err != nil {
+		return err
+	}
+	c.events.record(EventStoreTombstone, storeID, 0, "store %d is tombstone", storeID)
+	c.s.hbStreams.removeStore(storeID)
+	// The tombstone store may be the one with the lowest version, so the
+	// cluster version may be promoted.
+	cluster.OnStoreVersionChange()
+	return nil
 }
 
 // SetStoreState sets up a store's state.
@@ -470,9 +663,19 @@ func (c *RaftCluster) SetStoreState(storeID uint64, state metapb.StoreState) err
 		return core.NewStoreNotFoundErr(storeID)
 	}
 
+	oldState := store.GetState()
 	store.State = state
 	log.Warnf("[store %d] set state to %v", storeID, state.String())
-	return cluster.putStore(store)
+	if err := cluster.putStore(store); err != nil {
+		return err
+	}
+	if typ, ok := storeStateEvents[state]; ok && state != oldState {
+		c.events.record(typ, storeID, 0, "store %d state is set from %s to %s", storeID, oldState, state)
+	}
+	if state == metapb.StoreState_Tombstone {
+		c.s.hbStreams.removeStore(storeID)
+	}
+	return nil
 }
 
 // SetStoreWeight sets up a store's leader/region balance weight.
@@ -493,6 +696,61 @@ func (c *RaftCluster) SetStoreWeight(storeID uint64, leader, region float64) err
 	return c.cachedCluster.putStore(store)
 }
 
+// CordonStore stops scheduling new peers and leaders to the store until the
+// deadline, the existing peers are kept. Zero deadline means the store is
+// cordoned until it is uncordoned.
+func (c *RaftCluster) CordonStore(storeID uint64, deadline time.Time) error {
+	c.RLock()
+	defer c.RUnlock()
+
+	store := c.cachedCluster.GetStore(storeID)
+	if store == nil {
+		return core.NewStoreNotFoundErr(storeID)
+	}
+	if store.IsTombstone() {
+		return core.StoreTombstonedErr{StoreID: storeID}
+	}
+
+	if err := c.s.kv.SaveStoreCordon(storeID, deadline); err != nil {
+		return err
+	}
+
+	store.Cordon(deadline)
+	log.Infof("[store %d] cordoned, deadline: %v", storeID, deadline)
+	return c.cachedCluster.putStore(store)
+}
+
+// UncordonStore allows scheduling new peers and leaders to the store.
+func (c *RaftCluster) UncordonStore(storeID uint64) error {
+	c.RLock()
+	defer c.RUnlock()
+
+	store := c.cachedCluster.GetStore(storeID)
+	if store == nil {
+		return core.NewStoreNotFoundErr(storeID)
+	}
+
+	if err := c.s.kv.DeleteStoreCordon(storeID); err != nil {
+		return err
+	}
+
+	store.Uncordon()
+	log.Infof("[store %d] uncordoned", storeID)
+	return c.cachedCluster.putStore(store)
+}
+
+// checkCordonedStores uncordons the stores whose cordon deadline is passed.
+func (c *RaftCluster) checkCordonedStores() {
+	for _, store := range c.cachedCluster.GetStores() {
+		if !store.IsCordonExpired() {
+			continue
+		}
+		if err := c.UncordonStore(store.GetId()); err != nil {
+			log.Errorf("[store %d] failed to uncordon the store: %v", store.GetId(), err)
+		}
+	}
+}
+
 func (c *RaftCluster) checkStores() {
 	var offlineStores []*metapb.Store
 	var upStoreCount int
@@ -500,6 +758,7 @@ func (c *RaftCluster) checkStores() {
 	cluster := c.cachedCluster
 
 	for _, store := range cluster.GetStores() {
+		c.checkStoreDown(store)
 		if store.GetState() != metapb.StoreState_Offline {
 			if store.GetState() == metapb.StoreState_Up && !store.IsLowSpace(cluster.GetLowSpaceRatio()) {
 				upStoreCount++
@@ -531,6 +790,22 @@ func (c *RaftCluster) checkStores() {
 	}
 }
 
+// checkStoreDown records the events of up stores becoming down and
+// recovering.
+func (c *RaftCluster) checkStoreDown(store *core.StoreInfo) {
+	_, wasDown := c.downStores[store.GetId()]
+	isDown := store.IsUp() && store.DownTime() > c.cachedCluster.GetMaxStoreDownTime()
+	if isDown && !wasDown {
+		c.downStores[store.GetId()] = struct{}{}
+		c.events.record(EventStoreDown, store.GetId(), 0, "store %d is down, last heartbeat at %v", store.GetId(), store.LastHeartbeatTS)
+	} else if !isDown && wasDown {
+		delete(c.downStores, store.GetId())
+		if store.IsUp() {
+			c.events.record(EventStoreUp, store.GetId(), 0, "store %d recovers from down", store.GetId())
+		}
+	}
+}
+
 func (c *RaftCluster) checkOperators() {
 	opController := c.coordinator.opController
 	for _, op := range opController.GetOperators() {
@@ -564,8 +839,7 @@ func (c *RaftCluster) collectMetrics() {
 }
 
 func (c *RaftCluster) collectHealthStatus() {
-	client := c.s.GetClient()
-	members, err := GetMembers(client)
+	members, err := c.s.ListMembers()
 	if err != nil {
 		log.Info("get members error:", err)
 	}
@@ -593,12 +867,39 @@ func (c *RaftCluster) runBackgroundJobs(interval time.Duration) {
 		case <-ticker.C:
 			c.checkOperators()
 			c.checkStores()
+			c.checkCordonedStores()
+			c.checkScheduleHalt()
+			c.checkRegionConsistency()
 			c.collectMetrics()
 			c.coordinator.opController.PruneHistory()
 		}
 	}
 }
 
+func (c *RaftCluster) runReplicationMode(interval time.Duration) {
+	defer logutil.LogPanic()
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.quit:
+			return
+		case <-ticker.C:
+			c.replicationMode.tick()
+		}
+	}
+}
+
+// GetReplicationModeStatus returns the replication mode status.
+func (c *RaftCluster) GetReplicationModeStatus() *ReplicationModeStatus {
+	c.RLock()
+	defer c.RUnlock()
+	return c.replicationMode.getStatus()
+}
+
 // GetConfig gets config from cluster.
 func (c *RaftCluster) GetConfig() *metapb.Cluster {
 	c.RLock()
@@ -0,0 +1,258 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/pkg/logutil"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// The types of cluster events.
+const (
+	EventStoreUp               = "store-up"
+	EventStoreDown             = "store-down"
+	EventStoreOffline          = "store-offline"
+	EventStoreTombstone        = "store-tombstone"
+	EventStoreVersionChanged   = "store-version-changed"
+	EventClusterVersionChanged = "cluster-version-changed"
+	EventRegionSplit           = "region-split"
+	EventRegionMerge           = "region-merge"
+	EventLeaderChanged         = "pd-leader-changed"
+)
+
+// storeStateEvents are the events of store state changes.
+var storeStateEvents = map[metapb.StoreState]string{
+	metapb.StoreState_Up:        EventStoreUp,
+	metapb.StoreState_Offline:   EventStoreOffline,
+	metapb.StoreState_Tombstone: EventStoreTombstone,
+}
+
+// eventQueueSize is the count of events waiting to be persisted, the events
+// beyond it are only kept in memory.
+const eventQueueSize = 1024
+
+// ClusterEvent is a change of the cluster topology.
+type ClusterEvent struct {
+	ID       uint64    `json:"id"`
+	Type     string    `json:"type"`
+	Time     time.Time `json:"time"`
+	StoreID  uint64    `json:"store_id,omitempty"`
+	RegionID uint64    `json:"region_id,omitempty"`
+	Message  string    `json:"message"`
+}
+
+// eventLog keeps the latest cluster events recorded by the leader. The events
+// are persisted and posted to the webhook in the background.
+type eventLog struct {
+	sync.RWMutex
+	kv     *core.KV
+	cfg    EventLogConfig
+	client *http.Client
+	events []*ClusterEvent
+	nextID uint64
+	// queue is nil if the log is not started.
+	queue chan *ClusterEvent
+	// cancel aborts persisting and posting the queued events.
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newEventLog(kv *core.KV, cfg EventLogConfig) *eventLog {
+	return &eventLog{
+		kv:     kv,
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.WebhookTimeout.Duration},
+		nextID: 1,
+	}
+}
+
+// start loads the persisted events. It is called after the server becomes
+// leader.
+func (l *eventLog) start() error {
+	l.Lock()
+	defer l.Unlock()
+
+	var events []*ClusterEvent
+	err := l.kv.LoadEvents(func(data []byte) (uint64, error) {
+		event := &ClusterEvent{}
+		if err := json.Unmarshal(data, event); err != nil {
+			return 0, errors.WithStack(err)
+		}
+		events = append(events, event)
+		return event.ID, nil
+	})
+	if err != nil {
+		return err
+	}
+	if n := len(events) - int(l.cfg.MaxCount); n > 0 {
+		for _, event := range events[:n] {
+			if err = l.kv.DeleteEvent(event.ID); err != nil {
+				return err
+			}
+		}
+		events = events[n:]
+	}
+	l.events = events
+	if len(events) > 0 {
+		l.nextID = events[len(events)-1].ID + 1
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	l.queue, l.cancel = make(chan *ClusterEvent, eventQueueSize), cancel
+	l.wg.Add(1)
+	go l.run(ctx, l.queue)
+	return nil
+}
+
+// stop drops the queued events and aborts the one being persisted or posted,
+// so that it returns promptly. It is called after the server loses
+// leadership, when the events can not be persisted anyway.
+func (l *eventLog) stop() {
+	l.Lock()
+	if l.queue != nil {
+		l.cancel()
+		close(l.queue)
+	}
+	l.queue, l.cancel = nil, nil
+	l.Unlock()
+	l.wg.Wait()
+}
+
+// record appends an event to the log. It does nothing if the log is not
+// started, because only the leader records events.
+func (l *eventLog) record(typ string, storeID, regionID uint64, format string, args ...interface{}) {
+	if l == nil {
+		return
+	}
+	l.Lock()
+	defer l.Unlock()
+	if l.queue == nil {
+		return
+	}
+
+	event := &ClusterEvent{
+		ID:       l.nextID,
+		Type:     typ,
+		Time:     time.Now(),
+		StoreID:  storeID,
+		RegionID: regionID,
+		Message:  fmt.Sprintf(format, args...),
+	}
+	l.nextID++
+	l.events = append(l.events, event)
+	if n := len(l.events) - int(l.cfg.MaxCount); n > 0 {
+		l.events = l.events[n:]
+	}
+	select {
+	case l.queue <- event:
+		clusterEventCounter.WithLabelValues(typ, "recorded").Inc()
+	default:
+		log.Warnf("[event %d] event queue is full, %s event is not persisted", event.ID, typ)
+		clusterEventCounter.WithLabelValues(typ, "dropped").Inc()
+	}
+}
+
+// run persists and posts the queued events until the queue is closed. The
+// events are written by the leader transactions of the KV, which fail after
+// the leadership is lost, and they are dropped after ctx is done.
+func (l *eventLog) run(ctx context.Context, queue <-chan *ClusterEvent) {
+	defer logutil.LogPanic()
+	defer l.wg.Done()
+
+	kv := l.kv.WithContext(ctx)
+	dropped := 0
+	for event := range queue {
+		if ctx.Err() != nil {
+			clusterEventCounter.WithLabelValues(event.Type, "dropped").Inc()
+			dropped++
+			continue
+		}
+		if err := kv.SaveEvent(event.ID, event); err != nil {
+			log.Errorf("[event %d] failed to save the event: %v", event.ID, err)
+			clusterEventCounter.WithLabelValues(event.Type, "save-failed").Inc()
+		}
+		if event.ID > l.cfg.MaxCount {
+			if err := kv.DeleteEvent(event.ID - l.cfg.MaxCount); err != nil {
+				log.Errorf("[event %d] failed to delete the event: %v", event.ID-l.cfg.MaxCount, err)
+			}
+		}
+		if l.cfg.Webhook != "" {
+			if err := l.post(ctx, event); err != nil {
+				log.Errorf("[event %d] failed to post the event to %s: %v", event.ID, l.cfg.Webhook, err)
+				clusterEventCounter.WithLabelValues(event.Type, "webhook-failed").Inc()
+			}
+		}
+	}
+	if dropped > 0 {
+		log.Warnf("%d queued events are dropped since the event log is stopped", dropped)
+	}
+}
+
+func (l *eventLog) post(ctx context.Context, event *ClusterEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req, err := http.NewRequest(http.MethodPost, l.cfg.Webhook, bytes.NewBuffer(data))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := l.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return errors.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// list returns the events ordered by ID. Only the events of the type are
+// returned if typ is not empty, and the events whose ID is not greater than
+// sinceID are skipped. At most limit latest events are returned if limit is
+// positive.
+func (l *eventLog) list(typ string, sinceID uint64, limit int) []*ClusterEvent {
+	l.RLock()
+	defer l.RUnlock()
+
+	var events []*ClusterEvent
+	for _, event := range l.events {
+		if event.ID <= sinceID || (typ != "" && event.Type != typ) {
+			continue
+		}
+		events = append(events, event)
+	}
+	if limit > 0 && len(events) > limit {
+		events = events[len(events)-limit:]
+	}
+	return events
+}
+
+// GetClusterEvents returns the latest cluster events ordered by ID. See
+// eventLog.list for the arguments.
+func (s *Server) GetClusterEvents(typ string, sinceID uint64, limit int) []*ClusterEvent {
+	return s.events.list(typ, sinceID, limit)
+}
@@ -0,0 +1,132 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/pd/pkg/typeutil"
+	"github.com/pingcap/pd/server/core"
+)
+
+var _ = Suite(&testClusterEventSuite{})
+
+type testClusterEventSuite struct{}
+
+func (s *testClusterEventSuite) TestEventLog(c *C) {
+	received := make(chan *ClusterEvent, 10)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event := &ClusterEvent{}
+		c.Assert(json.NewDecoder(r.Body).Decode(event), IsNil)
+		received <- event
+	}))
+	defer webhook.Close()
+
+	kv := core.NewKV(core.NewMemoryKV())
+	cfg := EventLogConfig{
+		MaxCount:       3,
+		Webhook:        webhook.URL,
+		WebhookTimeout: typeutil.NewDuration(time.Second),
+	}
+	l := newEventLog(kv, cfg)
+
+	// Events are not recorded before started.
+	l.record(EventStoreUp, 1, 0, "store %d is up", 1)
+	c.Assert(l.list("", 0, 0), HasLen, 0)
+
+	c.Assert(l.start(), IsNil)
+	l.record(EventStoreUp, 1, 0, "store %d is up", 1)
+	l.record(EventRegionSplit, 0, 2, "region %d splits", 2)
+	l.record(EventStoreDown, 1, 0, "store %d is down", 1)
+	l.record(EventStoreUp, 3, 0, "store %d is up", 3)
+	for i := 1; i <= 4; i++ {
+		select {
+		case event := <-received:
+			c.Assert(event.ID, Equals, uint64(i))
+		case <-time.After(3 * time.Second):
+			c.Fatal("webhook is not called")
+		}
+	}
+	l.stop()
+
+	// Only the latest events are retained.
+	events := l.list("", 0, 0)
+	c.Assert(events, HasLen, 3)
+	c.Assert(events[0].ID, Equals, uint64(2))
+	c.Assert(events[0].RegionID, Equals, uint64(2))
+	c.Assert(events[0].Message, Equals, "region 2 splits")
+	c.Assert(l.list(EventStoreUp, 0, 0), HasLen, 1)
+	c.Assert(l.list("", 2, 0), HasLen, 2)
+	events = l.list("", 0, 1)
+	c.Assert(events, HasLen, 1)
+	c.Assert(events[0].ID, Equals, uint64(4))
+
+	// The events are loaded after restarted.
+	l = newEventLog(kv, cfg)
+	c.Assert(l.start(), IsNil)
+	c.Assert(l.list("", 0, 0), HasLen, 3)
+	l.record(EventStoreOffline, 3, 0, "store %d is offline", 3)
+	events = l.list("", 0, 0)
+	c.Assert(events, HasLen, 3)
+	c.Assert(events[2].ID, Equals, uint64(5))
+	// The event is posted after it is persisted.
+	select {
+	case event := <-received:
+		c.Assert(event.ID, Equals, uint64(5))
+	case <-time.After(3 * time.Second):
+		c.Fatal("webhook is not called")
+	}
+	l.stop()
+
+	var ids []uint64
+	err := kv.LoadEvents(func(data []byte) (uint64, error) {
+		event := &ClusterEvent{}
+		c.Assert(json.Unmarshal(data, event), IsNil)
+		ids = append(ids, event.ID)
+		return event.ID, nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(ids, DeepEquals, []uint64{3, 4, 5})
+}
+
+func (s *testClusterEventSuite) TestStopEventLog(c *C) {
+	block := make(chan struct{})
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+	}))
+	defer webhook.Close()
+	defer close(block)
+
+	cfg := EventLogConfig{
+		MaxCount:       100,
+		Webhook:        webhook.URL,
+		WebhookTimeout: typeutil.NewDuration(time.Minute),
+	}
+	l := newEventLog(core.NewKV(core.NewMemoryKV()), cfg)
+	c.Assert(l.start(), IsNil)
+	for i := 0; i < 10; i++ {
+		l.record(EventStoreUp, 1, 0, "store %d is up", 1)
+	}
+
+	// The queued events are dropped instead of waiting for the webhook.
+	start := time.Now()
+	l.stop()
+	c.Assert(time.Since(start), Less, 3*time.Second)
+	l.record(EventStoreUp, 1, 0, "store %d is up", 1)
+	c.Assert(l.list("", 0, 0), HasLen, 10)
+}
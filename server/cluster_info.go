@@ -14,23 +14,43 @@
 package server
 
 import (
-	"sync"
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
 	"time"
 
 	"github.com/coreos/go-semver/semver"
 	"github.com/gogo/protobuf/proto"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/pkg/storepb"
+	"github.com/pingcap/pd/pkg/typeutil"
 	"github.com/pingcap/pd/server/core"
 	"github.com/pingcap/pd/server/namespace"
 	"github.com/pingcap/pd/server/schedule"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
+// clusterInfo is the cached information of the cluster. The stores and the
+// regions are guarded by separate locks, so that reading the stores does not
+// wait for updating the region index. If both locks are required, regionsMu
+// must be locked before storesMu.
+//
+// The region lookups by ID and by key are served from regionIndex, which does
+// not take regionsMu, so that they do not wait for the region heartbeats.
 type clusterInfo struct {
-	sync.RWMutex
-	core *schedule.BasicCluster
-
+	// storesMu guards the stores and the cluster meta.
+	storesMu rwLock
+	// regionsMu guards the regions, the hot cache, the region statistics and
+	// the prepare checker. It also orders the region updates in the storage
+	// with those in the cache.
+	regionsMu rwLock
+	// regionIndex is updated with the regions, and read without locks.
+	regionIndex *core.RegionIndex
+
+	core            *schedule.BasicCluster
 	id              core.IDAllocator
 	kv              *core.KV
 	meta            *metapb.Cluster
@@ -39,12 +59,18 @@ type clusterInfo struct {
 	labelLevelStats *labelLevelStatistics
 	prepareChecker  *prepareChecker
 	changedRegions  chan *core.RegionInfo
+	watchers        *regionWatchers
+	events          *eventLog
+	storeTrends     *storeTrends
+	// createTime tells the versions of the cache from those of the caches
+	// created by the previous starts.
+	createTime time.Time
 }
 
 var defaultChangedRegionsLimit = 10000
 
 func newClusterInfo(id core.IDAllocator, opt *scheduleOption, kv *core.KV) *clusterInfo {
-	return &clusterInfo{
+	c := &clusterInfo{
 		core:            schedule.NewBasicCluster(),
 		id:              id,
 		opt:             opt,
@@ -52,7 +78,16 @@ func newClusterInfo(id core.IDAllocator, opt *scheduleOption, kv *core.KV) *clus
 		labelLevelStats: newLabelLevelStatistics(),
 		prepareChecker:  newPrepareChecker(),
 		changedRegions:  make(chan *core.RegionInfo, defaultChangedRegionsLimit),
+		watchers:        newRegionWatchers(),
+		storeTrends:     newStoreTrends(storeTrendInterval, storeTrendRetention),
+		regionIndex:     core.NewRegionIndex(),
+		createTime:      time.Now(),
 	}
+	c.storesMu.setName("stores")
+	c.regionsMu.setName("regions")
+	c.core.Regions.Subscribe(c.regionIndex.OnRegionEvent)
+	c.core.Regions.Subscribe(c.onRegionEvent)
+	return c
 }
 
 // Return nil if cluster is not bootstrapped.
@@ -78,6 +113,8 @@ func loadClusterInfo(id core.IDAllocator, kv *core.KV, opt *scheduleOption) (*cl
 	if err := kv.LoadRegions(c.core.Regions); err != nil {
 		return nil, err
 	}
+	// The loaded regions are not published, so the index is built at once.
+	c.regionIndex.Load(c.core.Regions.GetRegions())
 	log.Infof("load %v regions cost %v", c.core.Regions.GetRegionCount(), time.Since(start))
 
 	return c, nil
@@ -101,6 +138,9 @@ func (c *clusterInfo) OnStoreVersionChange() {
 			minVersion = v
 		}
 	}
+	if minVersion == nil {
+		return
+	}
 	if clusterVersion.LessThan(*minVersion) {
 		c.opt.SetClusterVersion(*minVersion)
 		err := c.opt.persist(c.kv)
@@ -108,6 +148,7 @@ func (c *clusterInfo) OnStoreVersionChange() {
 			log.Infof("persist cluster version meet error: %s", err)
 		}
 		log.Infof("cluster version changed from %s to %s", clusterVersion, minVersion)
+		c.events.record(EventClusterVersionChanged, 0, 0, "cluster version changed from %s to %s", clusterVersion, minVersion)
 		CheckPDVersion(c.opt)
 	}
 }
@@ -116,11 +157,33 @@ func (c *clusterInfo) changedRegionNotifier() <-chan *core.RegionInfo {
 	return c.changedRegions
 }
 
+// onRegionEvent is called with regionsMu held, when a region in the cache is
+// changed.
+func (c *clusterInfo) onRegionEvent(e *core.RegionEvent) {
+	switch e.Type {
+	case core.RegionCreated, core.RegionUpdated:
+		// Only the changes of meta are synchronized to the followers.
+		if e.Origin != nil && !isRegionMetaChanged(e.Origin, e.Region) {
+			return
+		}
+		select {
+		case c.changedRegions <- e.Region:
+		default:
+		}
+	case core.RegionDeleted:
+		id := e.Region.GetID()
+		if c.regionStats != nil {
+			c.regionStats.clearDefunctRegion(id)
+		}
+		c.labelLevelStats.clearDefunctRegion(id)
+		c.core.HotCache.Update(id, nil, schedule.WriteFlow)
+		c.core.HotCache.Update(id, nil, schedule.ReadFlow)
+	}
+}
+
 // IsFeatureSupported checks if the feature is supported by current cluster.
 func (c *clusterInfo) IsFeatureSupported(f Feature) bool {
-	clusterVersion := c.opt.loadClusterVersion()
-	minSupportVersion := MinSupportedVersion(f)
-	return !clusterVersion.LessThan(minSupportVersion)
+	return IsFeatureSupportedBy(c.opt.loadClusterVersion(), f)
 }
 
 func (c *clusterInfo) allocID() (uint64, error) {
@@ -142,20 +205,20 @@ func (c *clusterInfo) AllocPeer(storeID uint64) (*metapb.Peer, error) {
 }
 
 func (c *clusterInfo) getClusterID() uint64 {
-	c.RLock()
-	defer c.RUnlock()
+	c.storesMu.RLock()
+	defer c.storesMu.RUnlock()
 	return c.meta.GetId()
 }
 
 func (c *clusterInfo) getMeta() *metapb.Cluster {
-	c.RLock()
-	defer c.RUnlock()
+	c.storesMu.RLock()
+	defer c.storesMu.RUnlock()
 	return proto.Clone(c.meta).(*metapb.Cluster)
 }
 
 func (c *clusterInfo) putMeta(meta *metapb.Cluster) error {
-	c.Lock()
-	defer c.Unlock()
+	c.storesMu.Lock()
+	defer c.storesMu.Unlock()
 	return c.putMetaLocked(proto.Clone(meta).(*metapb.Cluster))
 }
 
@@ -171,14 +234,14 @@ func (c *clusterInfo) putMetaLocked(meta *metapb.Cluster) error {
 
 // GetStore searches for a store by ID.
 func (c *clusterInfo) GetStore(storeID uint64) *core.StoreInfo {
-	c.RLock()
-	defer c.RUnlock()
+	c.storesMu.RLock()
+	defer c.storesMu.RUnlock()
 	return c.core.GetStore(storeID)
 }
 
 func (c *clusterInfo) putStore(store *core.StoreInfo) error {
-	c.Lock()
-	defer c.Unlock()
+	c.storesMu.Lock()
+	defer c.storesMu.Unlock()
 	return c.putStoreLocked(store.Clone())
 }
 
@@ -193,93 +256,99 @@ func (c *clusterInfo) putStoreLocked(store *core.StoreInfo) error {
 
 // BlockStore stops balancer from selecting the store.
 func (c *clusterInfo) BlockStore(storeID uint64) error {
-	c.Lock()
-	defer c.Unlock()
+	c.storesMu.Lock()
+	defer c.storesMu.Unlock()
 	return c.core.BlockStore(storeID)
 }
 
 // UnblockStore allows balancer to select the store.
 func (c *clusterInfo) UnblockStore(storeID uint64) {
-	c.Lock()
-	defer c.Unlock()
+	c.storesMu.Lock()
+	defer c.storesMu.Unlock()
 	c.core.UnblockStore(storeID)
 }
 
 // GetStores returns all stores in the cluster.
 func (c *clusterInfo) GetStores() []*core.StoreInfo {
-	c.RLock()
-	defer c.RUnlock()
+	c.storesMu.RLock()
+	defer c.storesMu.RUnlock()
 	return c.core.GetStores()
 }
 
 func (c *clusterInfo) getMetaStores() []*metapb.Store {
-	c.RLock()
-	defer c.RUnlock()
+	c.storesMu.RLock()
+	defer c.storesMu.RUnlock()
 	return c.core.Stores.GetMetaStores()
 }
 
 func (c *clusterInfo) getStoreCount() int {
-	c.RLock()
-	defer c.RUnlock()
+	c.storesMu.RLock()
+	defer c.storesMu.RUnlock()
 	return c.core.Stores.GetStoreCount()
 }
 
 func (c *clusterInfo) getStoresBytesWriteStat() map[uint64]uint64 {
-	c.RLock()
-	defer c.RUnlock()
+	c.storesMu.RLock()
+	defer c.storesMu.RUnlock()
 	return c.core.Stores.GetStoresBytesWriteStat()
 }
 
 func (c *clusterInfo) getStoresBytesReadStat() map[uint64]uint64 {
-	c.RLock()
-	defer c.RUnlock()
+	c.storesMu.RLock()
+	defer c.storesMu.RUnlock()
 	return c.core.Stores.GetStoresBytesReadStat()
 }
 
 func (c *clusterInfo) getStoresKeysWriteStat() map[uint64]uint64 {
-	c.RLock()
-	defer c.RUnlock()
+	c.storesMu.RLock()
+	defer c.storesMu.RUnlock()
 	return c.core.Stores.GetStoresKeysWriteStat()
 }
 
 func (c *clusterInfo) getStoresKeysReadStat() map[uint64]uint64 {
-	c.RLock()
-	defer c.RUnlock()
+	c.storesMu.RLock()
+	defer c.storesMu.RUnlock()
 	return c.core.Stores.GetStoresKeysReadStat()
 }
 
+func (c *clusterInfo) getRegionsInRange(startKey, endKey []byte, limit int) []*core.RegionInfo {
+	return c.regionIndex.GetRegionsInRange(startKey, endKey, limit)
+}
+
+func (c *clusterInfo) getRangeSummary(startKey, endKey []byte, sampleLimit int) *core.RangeSummary {
+	c.regionsMu.RLock()
+	defer c.regionsMu.RUnlock()
+	return c.core.Regions.GetRangeSummary(startKey, endKey, sampleLimit)
+}
+
 // ScanRegions scans region with start key, until number greater than limit.
 func (c *clusterInfo) ScanRegions(startKey []byte, limit int) []*core.RegionInfo {
-	c.RLock()
-	defer c.RUnlock()
-	return c.core.Regions.ScanRange(startKey, limit)
+	return c.regionIndex.ScanRange(startKey, limit)
 }
 
 // GetAdjacentRegions returns region's info that is adjacent with specific region
 func (c *clusterInfo) GetAdjacentRegions(region *core.RegionInfo) (*core.RegionInfo, *core.RegionInfo) {
-	c.RLock()
-	defer c.RUnlock()
+	c.regionsMu.RLock()
+	defer c.regionsMu.RUnlock()
 	return c.core.GetAdjacentRegions(region)
 }
 
 // GetRegion searches for a region by ID.
 func (c *clusterInfo) GetRegion(regionID uint64) *core.RegionInfo {
-	c.RLock()
-	defer c.RUnlock()
-	return c.core.GetRegion(regionID)
+	return c.regionIndex.GetRegion(regionID)
 }
 
 // IsRegionHot checks if a region is in hot state.
 func (c *clusterInfo) IsRegionHot(id uint64) bool {
-	c.RLock()
-	defer c.RUnlock()
+	c.regionsMu.RLock()
+	defer c.regionsMu.RUnlock()
 	return c.core.IsRegionHot(id, c.GetHotRegionLowThreshold())
 }
 
 // RandHotRegionFromStore randomly picks a hot region in specified store.
 func (c *clusterInfo) RandHotRegionFromStore(store uint64, kind schedule.FlowKind) *core.RegionInfo {
-	c.RLock()
-	defer c.RUnlock()
+	c.regionsMu.RLock()
+	defer c.regionsMu.RUnlock()
 	r := c.core.HotCache.RandHotRegionFromStore(store, kind, c.GetHotRegionLowThreshold())
 	if r == nil {
 		return nil
@@ -288,20 +357,16 @@ func (c *clusterInfo) RandHotRegionFromStore(store uint64, kind schedule.FlowKin
 }
 
 func (c *clusterInfo) searchRegion(regionKey []byte) *core.RegionInfo {
-	c.RLock()
-	defer c.RUnlock()
-	return c.core.Regions.SearchRegion(regionKey)
+	return c.regionIndex.SearchRegion(regionKey)
 }
 
 func (c *clusterInfo) searchPrevRegion(regionKey []byte) *core.RegionInfo {
-	c.RLock()
-	defer c.RUnlock()
-	return c.core.Regions.SearchPrevRegion(regionKey)
+	return c.regionIndex.SearchPrevRegion(regionKey)
 }
 
 func (c *clusterInfo) putRegion(region *core.RegionInfo) error {
-	c.Lock()
-	defer c.Unlock()
+	c.regionsMu.Lock()
+	defer c.regionsMu.Unlock()
 	return c.putRegionLocked(region)
 }
 
@@ -314,75 +379,87 @@ func (c *clusterInfo) putRegionLocked(region *core.RegionInfo) error {
 	return c.core.PutRegion(region)
 }
 
+// getRegionsVersion returns the version of the cached regions, which changes
+// whenever the regions change.
+func (c *clusterInfo) getRegionsVersion() string {
+	c.regionsMu.RLock()
+	defer c.regionsMu.RUnlock()
+	return fmt.Sprintf("%x-%x", c.createTime.UnixNano(), c.core.Regions.Version())
+}
+
+// getStoresVersion returns the version of the cached stores, which changes
+// whenever the stores change.
+func (c *clusterInfo) getStoresVersion() string {
+	c.storesMu.RLock()
+	defer c.storesMu.RUnlock()
+	return fmt.Sprintf("%x-%x", c.createTime.UnixNano(), c.core.Stores.Version())
+}
+
 func (c *clusterInfo) getRegions() []*core.RegionInfo {
-	c.RLock()
-	defer c.RUnlock()
-	return c.core.Regions.GetRegions()
+	return c.regionIndex.GetRegions()
 }
 
 func (c *clusterInfo) getStoreRegions(storeID uint64) []*core.RegionInfo {
-	c.RLock()
-	defer c.RUnlock()
+	c.regionsMu.RLock()
+	defer c.regionsMu.RUnlock()
 	return c.core.Regions.GetStoreRegions(storeID)
 }
 
 func (c *clusterInfo) getMetaRegions() []*metapb.Region {
-	c.RLock()
-	defer c.RUnlock()
+	c.regionsMu.RLock()
+	defer c.regionsMu.RUnlock()
 	return c.core.Regions.GetMetaRegions()
 }
 
 func (c *clusterInfo) getRegionCount() int {
-	c.RLock()
-	defer c.RUnlock()
-	return c.core.Regions.GetRegionCount()
+	return c.regionIndex.GetRegionCount()
 }
 
 func (c *clusterInfo) getRegionStats(startKey, endKey []byte) *core.RegionStats {
-	c.RLock()
-	defer c.RUnlock()
+	c.regionsMu.RLock()
+	defer c.regionsMu.RUnlock()
 	return c.core.Regions.GetRegionStats(startKey, endKey)
 }
 
 func (c *clusterInfo) dropRegion(id uint64) {
-	c.Lock()
-	defer c.Unlock()
+	c.regionsMu.Lock()
+	defer c.regionsMu.Unlock()
 	if region := c.core.GetRegion(id); region != nil {
 		c.core.Regions.RemoveRegion(region)
 	}
 }
 
 func (c *clusterInfo) getStoreRegionCount(storeID uint64) int {
-	c.RLock()
-	defer c.RUnlock()
+	c.regionsMu.RLock()
+	defer c.regionsMu.RUnlock()
 	return c.core.Regions.GetStoreRegionCount(storeID)
 }
 
 // RandLeaderRegion returns a random region that has leader on the store.
 func (c *clusterInfo) RandLeaderRegion(storeID uint64, opts ...core.RegionOption) *core.RegionInfo {
-	c.RLock()
-	defer c.RUnlock()
+	c.regionsMu.RLock()
+	defer c.regionsMu.RUnlock()
 	return c.core.RandLeaderRegion(storeID, opts...)
 }
 
 // RandFollowerRegion returns a random region that has a follower on the store.
 func (c *clusterInfo) RandFollowerRegion(storeID uint64, opts ...core.RegionOption) *core.RegionInfo {
-	c.RLock()
-	defer c.RUnlock()
+	c.regionsMu.RLock()
+	defer c.regionsMu.RUnlock()
 	return c.core.RandFollowerRegion(storeID, opts...)
 }
 
 // GetAverageRegionSize returns the average region approximate size.
 func (c *clusterInfo) GetAverageRegionSize() int64 {
-	c.RLock()
-	defer c.RUnlock()
+	c.regionsMu.RLock()
+	defer c.regionsMu.RUnlock()
 	return c.core.GetAverageRegionSize()
 }
 
 // GetRegionStores returns all stores that contains the region's peer.
 func (c *clusterInfo) GetRegionStores(region *core.RegionInfo) []*core.StoreInfo {
-	c.RLock()
-	defer c.RUnlock()
+	c.storesMu.RLock()
+	defer c.storesMu.RUnlock()
 	return c.getRegionStoresLocked(region)
 }
 
@@ -408,15 +485,15 @@ func (c *clusterInfo) takeRegionStoresLocked(region *core.RegionInfo) []*core.St
 
 // GetLeaderStore returns all stores that contains the region's leader peer.
 func (c *clusterInfo) GetLeaderStore(region *core.RegionInfo) *core.StoreInfo {
-	c.RLock()
-	defer c.RUnlock()
+	c.storesMu.RLock()
+	defer c.storesMu.RUnlock()
 	return c.core.Stores.GetStore(region.GetLeader().GetStoreId())
 }
 
 // GetFollowerStores returns all stores that contains the region's follower peer.
 func (c *clusterInfo) GetFollowerStores(region *core.RegionInfo) []*core.StoreInfo {
-	c.RLock()
-	defer c.RUnlock()
+	c.storesMu.RLock()
+	defer c.storesMu.RUnlock()
 	var stores []*core.StoreInfo
 	for id := range region.GetFollowers() {
 		if store := c.core.Stores.GetStore(id); store != nil {
@@ -428,15 +505,50 @@ func (c *clusterInfo) GetFollowerStores(region *core.RegionInfo) []*core.StoreIn
 
 // isPrepared if the cluster information is collected
 func (c *clusterInfo) isPrepared() bool {
-	c.RLock()
-	defer c.RUnlock()
+	// Once prepared, the checker is not changed, so the read lock is enough.
+	c.regionsMu.RLock()
+	prepared := c.prepareChecker.isPrepared
+	c.regionsMu.RUnlock()
+	if prepared {
+		return true
+	}
+	c.regionsMu.Lock()
+	defer c.regionsMu.Unlock()
+	c.storesMu.RLock()
+	defer c.storesMu.RUnlock()
 	return c.prepareChecker.check(c)
 }
 
+// prepareProgress returns the progress of collecting the region information
+// in [0, 1].
+func (c *clusterInfo) prepareProgress() float64 {
+	c.regionsMu.RLock()
+	defer c.regionsMu.RUnlock()
+	return c.prepareChecker.progress(c)
+}
+
+// prepareStatus returns the progress of collecting the region information.
+func (c *clusterInfo) prepareStatus() *PrepareStatus {
+	c.regionsMu.Lock()
+	defer c.regionsMu.Unlock()
+	c.storesMu.RLock()
+	defer c.storesMu.RUnlock()
+	return c.prepareChecker.status(c)
+}
+
+// forcePrepare marks the cluster information collected, so that the
+// coordinator starts scheduling. It is used when the regions loaded from the
+// storage are wrong, and the cluster never gets prepared before the timeout.
+func (c *clusterInfo) forcePrepare() {
+	c.regionsMu.Lock()
+	defer c.regionsMu.Unlock()
+	c.prepareChecker.force()
+}
+
 // handleStoreHeartbeat updates the store status.
 func (c *clusterInfo) handleStoreHeartbeat(stats *pdpb.StoreStats) error {
-	c.Lock()
-	defer c.Unlock()
+	c.storesMu.Lock()
+	defer c.storesMu.Unlock()
 
 	storeID := stats.GetStoreId()
 	store := c.core.Stores.GetStore(storeID)
@@ -445,11 +557,34 @@ func (c *clusterInfo) handleStoreHeartbeat(stats *pdpb.StoreStats) error {
 	}
 	store.Stats = proto.Clone(stats).(*pdpb.StoreStats)
 	store.LastHeartbeatTS = time.Now()
+	c.storeTrends.observe(stats, store.LastHeartbeatTS)
 
 	c.core.Stores.SetStore(store)
 	return nil
 }
 
+// handleStoreDiskStats updates the disk stats of the store.
+func (c *clusterInfo) handleStoreDiskStats(stats *storepb.DiskStats) error {
+	c.storesMu.Lock()
+	defer c.storesMu.Unlock()
+
+	store := c.core.Stores.GetStore(stats.GetStoreId())
+	if store == nil {
+		return core.NewStoreNotFoundErr(stats.GetStoreId())
+	}
+	store.DiskStats = core.StoreDiskStats{
+		Interval:               stats.GetInterval().GetEndTimestamp() - stats.GetInterval().GetStartTimestamp(),
+		DiskReadBytes:          stats.GetDiskReadBytes(),
+		DiskWriteBytes:         stats.GetDiskWriteBytes(),
+		PendingCompactionBytes: stats.GetPendingCompactionBytes(),
+	}
+
+	c.core.Stores.SetStore(store)
+	return nil
+}
+
+// updateStoreStatusLocked updates the region counts of the store. It requires
+// regionsMu and storesMu locked for writing.
 func (c *clusterInfo) updateStoreStatusLocked(id uint64) {
 	c.core.Stores.SetLeaderCount(id, c.core.Regions.GetStoreLeaderCount(id))
 	c.core.Stores.SetRegionCount(id, c.core.Regions.GetStoreRegionCount(id))
@@ -460,19 +595,23 @@ func (c *clusterInfo) updateStoreStatusLocked(id uint64) {
 
 // handleRegionHeartbeat updates the region information.
 func (c *clusterInfo) handleRegionHeartbeat(region *core.RegionInfo) error {
-	c.RLock()
+	c.regionsMu.RLock()
+	c.storesMu.RLock()
 	origin := c.core.Regions.GetRegion(region.GetID())
 	isWriteUpdate, writeItem := c.core.CheckWriteStatus(region)
 	isReadUpdate, readItem := c.core.CheckReadStatus(region)
-	c.RUnlock()
+	c.storesMu.RUnlock()
+	c.regionsMu.RUnlock()
 
 	// Save to KV if meta is updated.
 	// Save to cache if meta or leader is updated, or contains any down/pending peer.
 	// Mark isNew if the region in cache does not have leader.
-	var saveKV, saveCache, isNew bool
+	// Notify watchers if meta or leader is updated.
+	var saveKV, saveCache, isNew, notify bool
 	if origin == nil {
 		log.Debugf("[region %d] Insert new region {%v}", region.GetID(), core.HexRegionMeta(region.GetMeta()))
-		saveKV, saveCache, isNew = true, true, true
+		saveKV, saveCache, isNew, notify = true, true, true, true
+		core.SetCreateTime(time.Now())(region)
 	} else {
 		r := region.GetRegionEpoch()
 		o := origin.GetRegionEpoch()
@@ -484,6 +623,11 @@ func (c *clusterInfo) handleRegionHeartbeat(region *core.RegionInfo) error {
 			log.Infof("[region %d] %s, Version changed from {%d} to {%d}", region.GetID(), core.DiffRegionKeyInfo(origin, region), o.GetVersion(), r.GetVersion())
 			saveKV, saveCache = true, true
 		}
+		if r.GetVersion() > o.GetVersion() && isRegionShrunk(origin, region) {
+			core.SetCreateTime(time.Now())(region)
+		} else {
+			core.SetCreateTime(origin.GetCreateTime())(region)
+		}
 		if r.GetConfVer() > o.GetConfVer() {
 			log.Infof("[region %d] %s, ConfVer changed from {%d} to {%d}", region.GetID(), core.DiffRegionPeersInfo(origin, region), o.GetConfVer(), r.GetConfVer())
 			saveKV, saveCache = true, true
@@ -494,7 +638,7 @@ func (c *clusterInfo) handleRegionHeartbeat(region *core.RegionInfo) error {
 			} else {
 				log.Infof("[region %d] Leader changed from store {%d} to {%d}", region.GetID(), origin.GetLeader().GetStoreId(), region.GetLeader().GetStoreId())
 			}
-			saveCache = true
+			saveCache, notify = true, true
 		}
 		if len(region.GetDownPeers()) > 0 || len(region.GetPendingPeers()) > 0 {
 			saveCache = true
@@ -505,37 +649,67 @@ func (c *clusterInfo) handleRegionHeartbeat(region *core.RegionInfo) error {
 		if len(region.GetPeers()) != len(origin.GetPeers()) {
 			saveKV, saveCache = true, true
 		}
-		if region.GetApproximateSize() != origin.GetApproximateSize() {
-			saveCache = true
-		}
-		if region.GetApproximateKeys() != origin.GetApproximateKeys() {
-			saveCache = true
+		if !saveCache {
+			// Only the stats are changed, skip the small changes to cut the
+			// cost of updating the cache.
+			ratio := c.opt.GetRegionStatsChangeRatio()
+			if isStatsChanged(origin.GetApproximateSize(), region.GetApproximateSize(), ratio) ||
+				isStatsChanged(origin.GetApproximateKeys(), region.GetApproximateKeys(), ratio) {
+				saveCache = true
+			} else if region.GetApproximateSize() != origin.GetApproximateSize() ||
+				region.GetApproximateKeys() != origin.GetApproximateKeys() {
+				regionHeartbeatSkipCounter.WithLabelValues("stats-below-ratio").Inc()
+			} else {
+				regionHeartbeatSkipCounter.WithLabelValues("unchanged").Inc()
+			}
 		}
 	}
 
-	if saveKV && c.kv != nil {
-		if err := c.kv.SaveRegion(region.GetMeta()); err != nil {
-			// Not successfully saved to kv is not fatal, it only leads to longer warm-up
-			// after restart. Here we only log the error then go on updating cache.
-			log.Errorf("[region %d] fail to save region %v: %v", region.GetID(), core.HexRegionMeta(region.GetMeta()), err)
-		}
-		select {
-		case c.changedRegions <- region:
-		default:
-		}
+	if saveKV || notify {
+		c.watchers.notify(region)
 	}
 	if !isWriteUpdate && !isReadUpdate && !saveCache && !isNew {
 		return nil
 	}
 
-	c.Lock()
-	defer c.Unlock()
+	overlaps := c.updateRegionCache(region, origin, isNew, saveKV, saveCache, isWriteUpdate, isReadUpdate, writeItem, readItem)
+	if origin != nil && len(overlaps) > 0 {
+		ids := make([]uint64, 0, len(overlaps))
+		for _, item := range overlaps {
+			ids = append(ids, item.GetId())
+		}
+		c.events.record(EventRegionMerge, 0, region.GetID(), "region %d merges regions %v", region.GetID(), ids)
+	}
+	return nil
+}
+
+// updateRegionCache applies the region heartbeat to the storage and the
+// cache, and returns the regions overlapped by it. The storage is updated
+// under regionsMu, so that the saves and the deletes of the heartbeats
+// handled in parallel are applied in the same order as the cache updates.
+// storesMu is only held for updating the store status.
+func (c *clusterInfo) updateRegionCache(region, origin *core.RegionInfo, isNew, saveKV, saveCache, isWriteUpdate, isReadUpdate bool, writeItem, readItem *core.RegionStat) []*metapb.Region {
+	c.regionsMu.Lock()
+	defer c.regionsMu.Unlock()
 	if isNew {
 		c.prepareChecker.collect(region)
 	}
 
+	if saveKV && c.kv != nil {
+		if err := c.kv.SaveRegion(region.GetMeta()); err != nil {
+			// Not successfully saved to kv is not fatal, it only leads to longer warm-up
+			// after restart. Here we only log the error then go on updating cache.
+			log.Errorf("[region %d] fail to save region %v: %v", region.GetID(), core.HexRegionMeta(region.GetMeta()), err)
+		}
+	}
+
+	var overlaps []*metapb.Region
 	if saveCache {
-		overlaps := c.core.Regions.SetRegion(region)
+		// The witnesses are reported apart from the heartbeats.
+		if cached := c.core.Regions.GetRegion(region.GetID()); cached != nil && len(cached.GetWitnesses()) > 0 {
+			core.WithWitnesses(keepWitnesses(cached, region))(region)
+		}
+		overlaps = c.core.Regions.SetRegion(region)
 		if c.kv != nil {
 			for _, item := range overlaps {
 				if err := c.kv.DeleteRegion(item); err != nil {
@@ -543,13 +717,10 @@ func (c *clusterInfo) handleRegionHeartbeat(region *core.RegionInfo) error {
 				}
 			}
 		}
-		for _, item := range overlaps {
-			if c.regionStats != nil {
-				c.regionStats.clearDefunctRegion(item.GetId())
-			}
-			c.labelLevelStats.clearDefunctRegion(item.GetId())
-		}
+	}
 
+	c.storesMu.Lock()
+	if saveCache {
 		// Update related stores.
 		if origin != nil {
 			for _, p := range origin.GetPeers() {
@@ -560,10 +731,10 @@ func (c *clusterInfo) handleRegionHeartbeat(region *core.RegionInfo) error {
 			c.updateStoreStatusLocked(p.GetStoreId())
 		}
 	}
-
 	if c.regionStats != nil {
 		c.regionStats.Observe(region, c.takeRegionStoresLocked(region))
 	}
+	c.storesMu.Unlock()
 
 	key := region.GetID()
 	if isWriteUpdate {
@@ -572,12 +743,83 @@ func (c *clusterInfo) handleRegionHeartbeat(region *core.RegionInfo) error {
 	if isReadUpdate {
 		c.core.HotCache.Update(key, readItem, schedule.ReadFlow)
 	}
+	return overlaps
+}
+
+// keepWitnesses returns the witnesses of the cached region which are still
+// voters of the region.
+func keepWitnesses(cached, region *core.RegionInfo) []*metapb.Peer {
+	var witnesses []*metapb.Peer
+	for _, w := range cached.GetWitnesses() {
+		if p := region.GetPeer(w.GetId()); p != nil && !p.GetIsLearner() {
+			witnesses = append(witnesses, p)
+		}
+	}
+	return witnesses
+}
+
+// handleWitnessReport updates the role of the peer reported by its store.
+func (c *clusterInfo) handleWitnessReport(regionID, peerID uint64, witness bool) error {
+	c.regionsMu.Lock()
+	defer c.regionsMu.Unlock()
+
+	region := c.core.Regions.GetRegion(regionID)
+	if region == nil {
+		return ErrRegionNotFound(regionID)
+	}
+	peer := region.GetPeer(peerID)
+	if peer == nil || peer.GetIsLearner() {
+		return errors.Errorf("region %d has no voter %d", regionID, peerID)
+	}
+	if (region.GetWitness(peerID) != nil) == witness {
+		return nil
+	}
+	witnesses := make([]*metapb.Peer, 0, len(region.GetWitnesses())+1)
+	for _, w := range region.GetWitnesses() {
+		if w.GetId() != peerID {
+			witnesses = append(witnesses, w)
+		}
+	}
+	if witness {
+		witnesses = append(witnesses, peer)
+	}
+	log.Infof("[region %d] peer %d on store %d is switched, witness: %v", regionID, peerID, peer.GetStoreId(), witness)
+	c.core.Regions.SetRegion(region.Clone(core.WithWitnesses(witnesses)))
 	return nil
 }
 
+// isRegionShrunk returns true if the key range of the region is shrunk, which
+// means the region is split.
+func isRegionShrunk(origin, region *core.RegionInfo) bool {
+	if bytes.Compare(region.GetStartKey(), origin.GetStartKey()) > 0 {
+		return true
+	}
+	end, originEnd := region.GetEndKey(), origin.GetEndKey()
+	return len(end) > 0 && (len(originEnd) == 0 || bytes.Compare(end, originEnd) < 0)
+}
+
+// isRegionMetaChanged returns true if the meta of the region needs to be
+// saved, which means the epoch or the peers are changed.
+func isRegionMetaChanged(origin, region *core.RegionInfo) bool {
+	r, o := region.GetRegionEpoch(), origin.GetRegionEpoch()
+	return r.GetVersion() != o.GetVersion() || r.GetConfVer() != o.GetConfVer() ||
+		len(region.GetPeers()) != len(origin.GetPeers())
+}
+
+// isStatsChanged returns if the change of the stats exceeds the ratio of the
+// origin value.
+func isStatsChanged(origin, current int64, ratio float64) bool {
+	if origin == current {
+		return false
+	}
+	return math.Abs(float64(current-origin)) > float64(origin)*ratio
+}
+
 func (c *clusterInfo) updateRegionsLabelLevelStats(regions []*core.RegionInfo) {
-	c.Lock()
-	defer c.Unlock()
+	c.regionsMu.Lock()
+	defer c.regionsMu.Unlock()
+	c.storesMu.RLock()
+	defer c.storesMu.RUnlock()
 	for _, region := range regions {
 		c.labelLevelStats.Observe(region, c.takeRegionStoresLocked(region), c.GetLocationLabels())
 	}
@@ -587,8 +829,10 @@ func (c *clusterInfo) collectMetrics() {
 	if c.regionStats == nil {
 		return
 	}
-	c.RLock()
-	defer c.RUnlock()
+	c.regionsMu.RLock()
+	defer c.regionsMu.RUnlock()
+	c.storesMu.RLock()
+	defer c.storesMu.RUnlock()
 	c.regionStats.Collect()
 	c.labelLevelStats.Collect()
 	// collect hot cache metrics
@@ -599,8 +843,8 @@ func (c *clusterInfo) GetRegionStatsByType(typ regionStatisticType) []*core.Regi
 	if c.regionStats == nil {
 		return nil
 	}
-	c.RLock()
-	defer c.RUnlock()
+	c.regionsMu.RLock()
+	defer c.regionsMu.RUnlock()
 	return c.regionStats.getRegionStatsByType(typ)
 }
 
@@ -624,6 +868,10 @@ func (c *clusterInfo) GetMergeScheduleLimit() uint64 {
 	return c.opt.GetMergeScheduleLimit(namespace.DefaultNamespace)
 }
 
+func (c *clusterInfo) GetLeaderScheduleBatch() uint64 {
+	return c.opt.GetLeaderScheduleBatch()
+}
+
 func (c *clusterInfo) GetTolerantSizeRatio() float64 {
 	return c.opt.GetTolerantSizeRatio()
 }
@@ -632,6 +880,10 @@ func (c *clusterInfo) GetLowSpaceRatio() float64 {
 	return c.opt.GetLowSpaceRatio()
 }
 
+func (c *clusterInfo) GetRegionCollectFactor() float64 {
+	return c.opt.GetRegionCollectFactor()
+}
+
 func (c *clusterInfo) GetHighSpaceRatio() float64 {
 	return c.opt.GetHighSpaceRatio()
 }
@@ -644,6 +896,14 @@ func (c *clusterInfo) GetMaxPendingPeerCount() uint64 {
 	return c.opt.GetMaxPendingPeerCount()
 }
 
+func (c *clusterInfo) GetMaxPendingCompactionBytes() uint64 {
+	return c.opt.GetMaxPendingCompactionBytes()
+}
+
+func (c *clusterInfo) GetMinSnapshotSpeed() uint64 {
+	return c.opt.GetMinSnapshotSpeed()
+}
+
 func (c *clusterInfo) GetMaxMergeRegionSize() uint64 {
 	return c.opt.GetMaxMergeRegionSize()
 }
@@ -656,6 +916,10 @@ func (c *clusterInfo) GetSplitMergeInterval() time.Duration {
 	return c.opt.GetSplitMergeInterval()
 }
 
+func (c *clusterInfo) GetMergeProtectionInterval(startKey, endKey []byte) time.Duration {
+	return c.opt.GetMergeProtectionInterval(startKey, endKey)
+}
+
 func (c *clusterInfo) GetPatrolRegionInterval() time.Duration {
 	return c.opt.GetPatrolRegionInterval()
 }
@@ -672,6 +936,10 @@ func (c *clusterInfo) GetLocationLabels() []string {
 	return c.opt.GetLocationLabels()
 }
 
+func (c *clusterInfo) GetIsolationLevel() string {
+	return c.opt.GetIsolationLevel()
+}
+
 func (c *clusterInfo) GetHotRegionLowThreshold() int {
 	return c.opt.GetHotRegionLowThreshold()
 }
@@ -683,6 +951,12 @@ func (c *clusterInfo) IsRaftLearnerEnabled() bool {
 	return c.opt.IsRaftLearnerEnabled()
 }
 
+// IsJointConsensusEnabled returns whether the voters are replaced by joint
+// consensus, which needs the new peers to be added as learners.
+func (c *clusterInfo) IsJointConsensusEnabled() bool {
+	return c.IsRaftLearnerEnabled() && c.opt.IsJointConsensusEnabled()
+}
+
 func (c *clusterInfo) IsRemoveDownReplicaEnabled() bool {
 	return c.opt.IsRemoveDownReplicaEnabled()
 }
@@ -728,6 +1002,8 @@ type prepareChecker struct {
 	start           time.Time
 	sum             int
 	isPrepared      bool
+	// forced is set if the checker is prepared by the admin.
+	forced bool
 }
 
 func newPrepareChecker() *prepareChecker {
@@ -741,7 +1017,8 @@ func (checker *prepareChecker) check(c *clusterInfo) bool {
 	if checker.isPrepared || time.Since(checker.start) > collectTimeout {
 		return true
 	}
-	if float64(c.core.Regions.Length())*collectFactor > float64(checker.sum) {
+	factor := c.GetRegionCollectFactor()
+	if float64(c.core.Regions.Length())*factor > float64(checker.sum) {
 		return false
 	}
 	for _, store := range c.core.GetStores() {
@@ -749,7 +1026,7 @@ func (checker *prepareChecker) check(c *clusterInfo) bool {
 			continue
 		}
 		storeID := store.GetId()
-		if float64(c.core.Regions.GetStoreRegionCount(storeID))*collectFactor > float64(checker.reactiveRegions[storeID]) {
+		if float64(c.core.Regions.GetStoreRegionCount(storeID))*factor > float64(checker.reactiveRegions[storeID]) {
 			return false
 		}
 	}
@@ -757,6 +1034,75 @@ func (checker *prepareChecker) check(c *clusterInfo) bool {
 	return true
 }
 
+// progress returns the ratio of the collected regions to the required ones.
+func (checker *prepareChecker) progress(c *clusterInfo) float64 {
+	required := float64(c.core.Regions.Length()) * c.GetRegionCollectFactor()
+	if checker.isPrepared || required == 0 {
+		return 1
+	}
+	return math.Min(float64(checker.sum)/required, 1)
+}
+
+// force marks the checker prepared, so that the coordinator starts
+// scheduling without waiting for the regions to be reported.
+func (checker *prepareChecker) force() {
+	checker.isPrepared, checker.forced = true, true
+}
+
+// PrepareStatus is the progress of collecting region heartbeats after the
+// leader starts, which the coordinator waits for before scheduling.
+type PrepareStatus struct {
+	IsPrepared bool `json:"is_prepared"`
+	// Forced means the coordinator is started by the admin without waiting
+	// for the regions to be reported.
+	Forced        bool    `json:"forced"`
+	CollectFactor float64 `json:"collect_factor"`
+	// LoadedRegions is the count of regions loaded from the storage or
+	// reported, and CollectedRegions is the count of regions reported since
+	// the leader starts.
+	LoadedRegions    int                   `json:"loaded_regions"`
+	CollectedRegions int                   `json:"collected_regions"`
+	Progress         float64               `json:"progress"`
+	StartTime        time.Time             `json:"start_time"`
+	Timeout          typeutil.Duration     `json:"timeout"`
+	Stores           []*StorePrepareStatus `json:"stores,omitempty"`
+}
+
+// StorePrepareStatus is the progress of collecting region heartbeats of a
+// store.
+type StorePrepareStatus struct {
+	StoreID          uint64 `json:"store_id"`
+	LoadedRegions    int    `json:"loaded_regions"`
+	CollectedRegions int    `json:"collected_regions"`
+}
+
+// status returns the progress of collecting the regions. Only the up stores,
+// which the checker waits for, are included.
+func (checker *prepareChecker) status(c *clusterInfo) *PrepareStatus {
+	status := &PrepareStatus{
+		IsPrepared:       checker.check(c),
+		Forced:           checker.forced,
+		CollectFactor:    c.GetRegionCollectFactor(),
+		LoadedRegions:    c.core.Regions.Length(),
+		CollectedRegions: checker.sum,
+		Progress:         checker.progress(c) * 100,
+		StartTime:        checker.start,
+		Timeout:          typeutil.NewDuration(collectTimeout),
+	}
+	for _, store := range c.core.GetStores() {
+		if !store.IsUp() {
+			continue
+		}
+		status.Stores = append(status.Stores, &StorePrepareStatus{
+			StoreID:          store.GetId(),
+			LoadedRegions:    c.core.Regions.GetStoreRegionCount(store.GetId()),
+			CollectedRegions: checker.reactiveRegions[store.GetId()],
+		})
+	}
+	sort.Slice(status.Stores, func(i, j int) bool { return status.Stores[i].StoreID < status.Stores[j].StoreID })
+	return status
+}
+
 func (checker *prepareChecker) collect(region *core.RegionInfo) {
 	for _, p := range region.GetPeers() {
 		checker.reactiveRegions[p.GetStoreId()]++
@@ -15,11 +15,14 @@ package server
 
 import (
 	"math/rand"
+	"time"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/pkg/storepb"
 	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/schedule"
 )
 
 var _ = Suite(&testStoresInfoSuite{})
@@ -339,6 +342,63 @@ func (s *testClusterInfoSuite) TestStoreHeartbeat(c *C) {
 	}
 }
 
+func (s *testClusterInfoSuite) TestStoreDiskStats(c *C) {
+	_, opt := newTestScheduleConfig()
+	cluster := newClusterInfo(core.NewMockIDAllocator(), opt, core.NewKV(core.NewMemoryKV()))
+	store := newTestStores(1)[0]
+	c.Assert(cluster.putStore(store), IsNil)
+
+	diskStats := &storepb.DiskStats{
+		StoreId:                store.GetId(),
+		Interval:               &pdpb.TimeInterval{StartTimestamp: 100, EndTimestamp: 110},
+		DiskReadBytes:          10 << 20,
+		DiskWriteBytes:         20 << 20,
+		PendingCompactionBytes: 1 << 30,
+	}
+	c.Assert(cluster.handleStoreDiskStats(diskStats), IsNil)
+	// The disk stats are kept by the store heartbeats.
+	c.Assert(cluster.handleStoreHeartbeat(&pdpb.StoreStats{StoreId: store.GetId()}), IsNil)
+	info := cluster.GetStore(store.GetId())
+	c.Assert(info.DiskReadRate(), Equals, float64(1<<20))
+	c.Assert(info.DiskWriteRate(), Equals, float64(2<<20))
+	c.Assert(info.DiskStats.PendingCompactionBytes, Equals, uint64(1<<30))
+
+	diskStats.StoreId = 2
+	c.Assert(cluster.handleStoreDiskStats(diskStats), NotNil)
+}
+
+func (s *testClusterInfoSuite) TestClusterVersionPromotion(c *C) {
+	_, opt := newTestScheduleConfig()
+	cluster := newClusterInfo(core.NewMockIDAllocator(), opt, core.NewKV(core.NewMemoryKV()))
+
+	// No store, the cluster version should not change.
+	cluster.OnStoreVersionChange()
+	c.Assert(opt.loadClusterVersion(), Equals, MinSupportedVersion(Version2_0))
+	c.Assert(cluster.IsFeatureSupported(BatchSplit), IsFalse)
+
+	stores := newTestStores(3)
+	versions := []string{"2.1.0", "2.1.0", "2.0.1"}
+	for i, store := range stores {
+		store.Version = versions[i]
+		c.Assert(cluster.putStore(store), IsNil)
+	}
+	cluster.OnStoreVersionChange()
+	c.Assert(opt.loadClusterVersion(), Equals, *MustParseVersion("2.0.1"))
+	c.Assert(cluster.IsFeatureSupported(BatchSplit), IsFalse)
+
+	// The store with the lowest version becomes tombstone.
+	stores[2].State = metapb.StoreState_Tombstone
+	c.Assert(cluster.putStore(stores[2]), IsNil)
+	cluster.OnStoreVersionChange()
+	c.Assert(opt.loadClusterVersion(), Equals, *MustParseVersion("2.1.0"))
+	c.Assert(cluster.IsFeatureSupported(BatchSplit), IsTrue)
+
+	for _, status := range GetFeatureStatuses(opt.loadClusterVersion()) {
+		c.Assert(status.Enabled, IsTrue)
+		c.Assert(status.Reason, Matches, "cluster version 2.1.0 >= .*")
+	}
+}
+
 func (s *testClusterInfoSuite) TestRegionHeartbeat(c *C) {
 	_, opt := newTestScheduleConfig()
 	cluster := newClusterInfo(core.NewMockIDAllocator(), opt, core.NewKV(core.NewMemoryKV()))
@@ -500,6 +560,39 @@ func (s *testClusterInfoSuite) TestRegionHeartbeat(c *C) {
 	}
 }
 
+func (s *testClusterInfoSuite) TestRegionHeartbeatStatsChange(c *C) {
+	cfg, opt := newTestScheduleConfig()
+	cfg.RegionStatsChangeRatio = 0.1
+	cluster := newClusterInfo(core.NewMockIDAllocator(), opt, core.NewKV(core.NewMemoryKV()))
+	for _, store := range newTestStores(3) {
+		cluster.putStore(store)
+	}
+
+	region := newTestRegions(3, 3)[1].Clone(core.SetApproximateSize(100), core.SetApproximateKeys(1000))
+	c.Assert(cluster.handleRegionHeartbeat(region), IsNil)
+	storeID := region.GetLeader().GetStoreId()
+
+	// Small changes are skipped.
+	c.Assert(cluster.handleRegionHeartbeat(region.Clone(core.SetApproximateSize(105), core.SetApproximateKeys(1050))), IsNil)
+	c.Assert(cluster.GetRegion(region.GetID()).GetApproximateSize(), Equals, int64(100))
+	c.Assert(cluster.GetRegion(region.GetID()).GetApproximateKeys(), Equals, int64(1000))
+	c.Assert(cluster.GetStore(storeID).RegionSize, Equals, int64(100))
+
+	// Changes of either size or keys above the ratio are updated.
+	region = region.Clone(core.SetApproximateSize(105), core.SetApproximateKeys(1200))
+	c.Assert(cluster.handleRegionHeartbeat(region), IsNil)
+	checkRegion(c, cluster.GetRegion(region.GetID()), region)
+	region = region.Clone(core.SetApproximateSize(120))
+	c.Assert(cluster.handleRegionHeartbeat(region), IsNil)
+	checkRegion(c, cluster.GetRegion(region.GetID()), region)
+	c.Assert(cluster.GetStore(storeID).RegionSize, Equals, int64(120))
+
+	// Other changes are updated with the stats.
+	region = region.Clone(core.SetApproximateSize(121), core.WithPendingPeers(region.GetPeers()[1:2]))
+	c.Assert(cluster.handleRegionHeartbeat(region), IsNil)
+	checkRegion(c, cluster.GetRegion(region.GetID()), region)
+}
+
 func heartbeatRegions(c *C, cluster *clusterInfo, regions []*metapb.Region) {
 	// Heartbeat and check region one by one.
 	for _, region := range regions {
@@ -570,6 +663,34 @@ func (s *testClusterInfoSuite) TestHeartbeatSplit(c *C) {
 	checkRegion(c, cluster.searchRegion([]byte("n")), region3)
 }
 
+func (s *testClusterInfoSuite) TestRegionCreateTime(c *C) {
+	_, opt := newTestScheduleConfig()
+	cluster := newClusterInfo(core.NewMockIDAllocator(), opt, nil)
+
+	// 1: [nil, nil)
+	region1 := core.NewRegionInfo(&metapb.Region{Id: 1, RegionEpoch: &metapb.RegionEpoch{Version: 1, ConfVer: 1}}, nil)
+	c.Assert(cluster.handleRegionHeartbeat(region1), IsNil)
+	createTime := cluster.GetRegion(1).GetCreateTime()
+	c.Assert(createTime.IsZero(), IsFalse)
+
+	// The create time is kept if the key range is not shrunk.
+	region1 = region1.Clone(core.WithIncConfVer())
+	c.Assert(cluster.handleRegionHeartbeat(region1), IsNil)
+	c.Assert(cluster.GetRegion(1).GetCreateTime(), Equals, createTime)
+
+	// split 1 to 2: [nil, m) 1: [m, nil)
+	time.Sleep(time.Millisecond)
+	region1 = region1.Clone(core.WithStartKey([]byte("m")), core.WithIncVersion())
+	c.Assert(cluster.handleRegionHeartbeat(region1), IsNil)
+	c.Assert(cluster.GetRegion(1).GetCreateTime().After(createTime), IsTrue)
+	createTime = cluster.GetRegion(1).GetCreateTime()
+
+	// merge 2 into 1: [nil, nil)
+	region1 = region1.Clone(core.WithStartKey(nil), core.WithIncVersion())
+	c.Assert(cluster.handleRegionHeartbeat(region1), IsNil)
+	c.Assert(cluster.GetRegion(1).GetCreateTime(), Equals, createTime)
+}
+
 func (s *testClusterInfoSuite) TestRegionSplitAndMerge(c *C) {
 	_, opt := newTestScheduleConfig()
 	cluster := newClusterInfo(core.NewMockIDAllocator(), opt, nil)
@@ -609,6 +730,41 @@ func (s *testClusterInfoSuite) TestRegionSplitAndMerge(c *C) {
 	}
 }
 
+func (s *testClusterInfoSuite) TestRegionEventObservers(c *C) {
+	_, opt := newTestScheduleConfig()
+	cluster := newClusterInfo(core.NewMockIDAllocator(), opt, core.NewKV(core.NewMemoryKV()))
+	changed := func() []uint64 {
+		var ids []uint64
+		for {
+			select {
+			case region := <-cluster.changedRegionNotifier():
+				ids = append(ids, region.GetID())
+			default:
+				return ids
+			}
+		}
+	}
+
+	peers := []*metapb.Peer{{Id: 11, StoreId: 1}}
+	region1 := core.NewRegionInfo(&metapb.Region{Id: 1, EndKey: []byte("m"), Peers: peers, RegionEpoch: &metapb.RegionEpoch{Version: 1, ConfVer: 1}}, peers[0])
+	region2 := core.NewRegionInfo(&metapb.Region{Id: 2, StartKey: []byte("m"), Peers: peers, RegionEpoch: &metapb.RegionEpoch{Version: 1, ConfVer: 1}}, peers[0])
+	c.Assert(cluster.handleRegionHeartbeat(region1), IsNil)
+	c.Assert(cluster.handleRegionHeartbeat(region2), IsNil)
+	c.Assert(changed(), DeepEquals, []uint64{1, 2})
+
+	// The change of stats is not synchronized.
+	region2 = region2.Clone(core.SetApproximateSize(100))
+	c.Assert(cluster.handleRegionHeartbeat(region2), IsNil)
+	c.Assert(changed(), HasLen, 0)
+
+	// The merged region is removed from the hot cache.
+	cluster.core.HotCache.Update(1, &core.RegionStat{RegionID: 1}, schedule.WriteFlow)
+	region2 = region2.Clone(core.WithStartKey(nil), core.WithIncVersion())
+	c.Assert(cluster.handleRegionHeartbeat(region2), IsNil)
+	c.Assert(changed(), DeepEquals, []uint64{2})
+	c.Assert(cluster.core.HotCache.RegionStats(schedule.WriteFlow), HasLen, 0)
+}
+
 func (s *testClusterInfoSuite) TestUpdateStorePendingPeerCount(c *C) {
 	_, opt := newTestScheduleConfig()
 	tc := newTestClusterInfo(opt)
@@ -642,6 +798,53 @@ func (s *testClusterInfoSuite) TestUpdateStorePendingPeerCount(c *C) {
 	checkPendingPeerCount([]int{0, 0, 0, 1}, tc.clusterInfo, c)
 }
 
+func (s *testClusterInfoSuite) TestSplitLocks(c *C) {
+	_, opt := newTestScheduleConfig()
+	tc := newTestClusterInfo(opt)
+	for _, store := range newTestStores(3) {
+		c.Assert(tc.putStore(store), IsNil)
+	}
+	for _, region := range newTestRegions(3, 3) {
+		c.Assert(tc.handleRegionHeartbeat(region), IsNil)
+	}
+
+	mustNotBlock := func(f func()) {
+		done := make(chan struct{})
+		go func() {
+			f()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			c.Fatal("blocked by the lock")
+		}
+	}
+
+	// Reading the stores does not wait for updating the regions.
+	tc.regionsMu.Lock()
+	mustNotBlock(func() {
+		c.Assert(tc.GetStores(), HasLen, 3)
+		c.Assert(tc.GetStore(1), NotNil)
+	})
+	tc.regionsMu.Unlock()
+
+	tc.forcePrepare()
+	c.Assert(tc.isPrepared(), IsTrue)
+	// Once prepared, the check does not wait for the region readers.
+	tc.regionsMu.RLock()
+	mustNotBlock(func() { c.Assert(tc.isPrepared(), IsTrue) })
+	tc.regionsMu.RUnlock()
+
+	// Reading the regions does not wait for updating the stores.
+	tc.storesMu.Lock()
+	mustNotBlock(func() {
+		c.Assert(tc.GetRegion(1), NotNil)
+		c.Assert(tc.getRegionCount(), Equals, 3)
+	})
+	tc.storesMu.Unlock()
+}
+
 func checkPendingPeerCount(expect []int, cluster *clusterInfo, c *C) {
 	for i, e := range expect {
 		s := cluster.core.Stores.GetStore(uint64(i + 1))
@@ -18,13 +18,20 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/coreos/etcd/clientv3"
+	"github.com/golang/protobuf/proto"
 	. "github.com/pingcap/check"
+	"github.com/pingcap/errcode"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/pkg/regionpb"
+	"github.com/pingcap/pd/pkg/storepb"
 	"github.com/pingcap/pd/server/core"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -132,9 +139,28 @@ func (s *testClusterSuite) TestBootstrap(c *C) {
 	c.Assert(resp, NotNil)
 	c.Assert(resp.GetBootstrapped(), IsFalse)
 
-	// Bootstrap the cluster.
+	// Validate the bootstrap request.
 	storeAddr := "127.0.0.1:0"
-	s.bootstrapCluster(c, clusterID, storeAddr)
+	bootReq := s.newBootstrapRequest(c, clusterID, storeAddr)
+	c.Assert(s.svr.PrepareBootstrap(bootReq), IsNil)
+	invalid := s.newBootstrapRequest(c, clusterID, "")
+	c.Assert(errcode.CodeChain(s.svr.PrepareBootstrap(invalid)).Code(), Equals, errcode.InvalidInputCode)
+	invalid = s.newBootstrapRequest(c, clusterID, strings.TrimPrefix(s.svr.GetAddr(), "http://"))
+	c.Assert(errcode.CodeChain(s.svr.PrepareBootstrap(invalid)).Code(), Equals, errcode.InvalidInputCode)
+	invalid = s.newBootstrapRequest(c, clusterID, storeAddr)
+	invalid.Store.Version = "invalid"
+	c.Assert(errcode.CodeChain(s.svr.PrepareBootstrap(invalid)).Code(), Equals, errcode.InvalidInputCode)
+
+	// Bootstrap the cluster.
+	respBoot, err := s.grpcPDClient.Bootstrap(context.Background(), bootReq)
+	c.Assert(err, IsNil)
+	c.Assert(respBoot.GetHeader().GetError(), IsNil)
+	c.Assert(errcode.CodeChain(s.svr.PrepareBootstrap(bootReq)).Code(), Equals, core.AlreadyBootstrappedCode)
+
+	// Retry of the same bootstrap succeeds.
+	respBoot, err = s.grpcPDClient.Bootstrap(context.Background(), bootReq)
+	c.Assert(err, IsNil)
+	c.Assert(respBoot.GetHeader().GetError(), IsNil)
 
 	// IsBootstrapped returns true.
 	req = s.newIsBootstrapRequest(clusterID)
@@ -144,7 +170,7 @@ func (s *testClusterSuite) TestBootstrap(c *C) {
 
 	// check bootstrapped error.
 	reqBoot := s.newBootstrapRequest(c, clusterID, storeAddr)
-	respBoot, err := s.grpcPDClient.Bootstrap(context.Background(), reqBoot)
+	respBoot, err = s.grpcPDClient.Bootstrap(context.Background(), reqBoot)
 	c.Assert(err, IsNil)
 	c.Assert(respBoot.GetHeader().GetError(), NotNil)
 	c.Assert(respBoot.GetHeader().GetError().GetType(), Equals, pdpb.ErrorType_ALREADY_BOOTSTRAPPED)
@@ -305,7 +331,7 @@ func (s *baseCluster) testPutStore(c *C, clusterID uint64, store *metapb.Store)
 
 	// Put new store with a duplicated address when old store is up will fail.
 	_, err = putStore(c, s.grpcPDClient, clusterID, s.newStore(c, 0, store.GetAddress()))
-	c.Assert(err, NotNil)
+	c.Assert(status.Code(err), Equals, codes.AlreadyExists)
 
 	// Put new store with a duplicated address when old store is offline will fail.
 	s.resetStoreState(c, store.GetId(), metapb.StoreState_Offline)
@@ -324,7 +350,36 @@ func (s *baseCluster) testPutStore(c *C, clusterID uint64, store *metapb.Store)
 	// Put an existed store with duplicated address with other old stores.
 	s.resetStoreState(c, store.GetId(), metapb.StoreState_Up)
 	_, err = putStore(c, s.grpcPDClient, clusterID, s.newStore(c, store.GetId(), "127.0.0.1:12345"))
-	c.Assert(err, NotNil)
+	c.Assert(status.Code(err), Equals, codes.AlreadyExists)
+
+	// Put an existed store with another address when it is still running will fail.
+	_, err = s.grpcPDClient.StoreHeartbeat(context.Background(), &pdpb.StoreHeartbeatRequest{
+		Header: newRequestHeader(clusterID),
+		Stats:  &pdpb.StoreStats{StoreId: store.GetId()},
+	})
+	c.Assert(err, IsNil)
+	moved := proto.Clone(store).(*metapb.Store)
+	moved.Address = "127.0.0.1:2"
+	_, err = putStore(c, s.grpcPDClient, clusterID, moved)
+	c.Assert(status.Code(err), Equals, codes.AlreadyExists)
+	c.Assert(s.getStore(c, clusterID, store.GetId()).GetAddress(), Equals, store.GetAddress())
+
+	// Put a store with labels violating the label schema will fail.
+	s.svr.cfg.LabelSchema = LabelSchemaConfig{Labels: []LabelSchema{{Key: "zone", ValuePattern: "z[0-9]+"}}}
+	defer func() { s.svr.cfg.LabelSchema = LabelSchemaConfig{} }()
+	for _, labels := range [][]*metapb.StoreLabel{
+		{{Key: "host", Value: "h1"}},
+		{{Key: "zone", Value: "z1x"}},
+	} {
+		labeled := s.newStore(c, 0, "127.0.0.1:12346")
+		labeled.Labels = labels
+		_, err = putStore(c, s.grpcPDClient, clusterID, labeled)
+		c.Assert(status.Code(err), Equals, codes.InvalidArgument)
+	}
+	labeled := s.newStore(c, 0, "127.0.0.1:12346")
+	labeled.Labels = []*metapb.StoreLabel{{Key: "Zone", Value: "z1"}}
+	_, err = putStore(c, s.grpcPDClient, clusterID, labeled)
+	c.Assert(err, IsNil)
 }
 
 func (s *baseCluster) resetStoreState(c *C, storeID uint64, state metapb.StoreState) {
@@ -429,6 +484,167 @@ func (s *testClusterSuite) TestRaftClusterRestart(c *C) {
 	cluster.stop()
 }
 
+func (s *testClusterSuite) TestStoreCordon(c *C) {
+	var err error
+	_, s.svr, s.cleanup, err = NewTestServer()
+	c.Assert(err, IsNil)
+	defer s.cleanup()
+	svr := s.svr
+	mustWaitLeader(c, []*Server{svr})
+	req := s.newBootstrapRequest(c, svr.clusterID, "127.0.0.1:0")
+	_, err = svr.bootstrapCluster(req)
+	c.Assert(err, IsNil)
+
+	cluster := svr.GetRaftCluster()
+	storeID := req.GetStore().GetId()
+	c.Assert(cluster.CordonStore(storeID, time.Time{}), IsNil)
+	c.Assert(cluster.cachedCluster.GetStore(storeID).IsCordoned(), IsTrue)
+	cluster.checkCordonedStores()
+	c.Assert(cluster.cachedCluster.GetStore(storeID).IsCordoned(), IsTrue)
+	c.Assert(cluster.CordonStore(storeID+1, time.Time{}), NotNil)
+
+	// The expired cordon is removed.
+	c.Assert(cluster.CordonStore(storeID, time.Now().Add(-time.Second)), IsNil)
+	c.Assert(cluster.cachedCluster.GetStore(storeID).IsCordoned(), IsFalse)
+	cluster.checkCordonedStores()
+	c.Assert(cluster.cachedCluster.GetStore(storeID).IsCordonExpired(), IsFalse)
+	stores := core.NewStoresInfo()
+	c.Assert(svr.kv.LoadStores(stores), IsNil)
+	c.Assert(stores.GetStore(storeID).IsCordonExpired(), IsFalse)
+}
+
+func (s *testClusterSuite) TestStoreReplacement(c *C) {
+	var err error
+	_, s.svr, s.cleanup, err = NewTestServer()
+	c.Assert(err, IsNil)
+	defer s.cleanup()
+	svr := s.svr
+	mustWaitLeader(c, []*Server{svr})
+	req := s.newBootstrapRequest(c, svr.clusterID, "127.0.0.1:0")
+	_, err = svr.bootstrapCluster(req)
+	c.Assert(err, IsNil)
+
+	cluster := svr.GetRaftCluster()
+	storeID := req.GetStore().GetId()
+	replacementID := storeID + 1
+	c.Assert(cluster.putStore(&metapb.Store{Id: replacementID, Address: "127.0.0.1:1", Version: "2.0.0"}), IsNil)
+	setRegionCount := func(count int) {
+		store := cluster.cachedCluster.GetStore(storeID)
+		store.RegionCount, store.RegionSize = count, int64(count*10)
+		c.Assert(cluster.cachedCluster.putStore(store), IsNil)
+	}
+	setRegionCount(10)
+
+	// Only an offline store can be paired.
+	c.Assert(cluster.SetStoreReplacement(storeID, replacementID), NotNil)
+	c.Assert(cluster.RemoveStore(storeID), IsNil)
+	c.Assert(cluster.SetStoreReplacement(storeID, replacementID), IsNil)
+	progress, err := cluster.GetStoreReplacementProgress(storeID)
+	c.Assert(err, IsNil)
+	c.Assert(progress.RegionCount, Equals, 10)
+	c.Assert(progress.RegionSize, Equals, int64(100))
+	c.Assert(progress.Progress, Equals, 0.0)
+	c.Assert(progress.LeftTime, IsNil)
+
+	setRegionCount(4)
+	progress, err = cluster.GetStoreReplacementProgress(storeID)
+	c.Assert(err, IsNil)
+	c.Assert(progress.LeftRegionCount, Equals, 4)
+	c.Assert(progress.LeftRegionSize, Equals, int64(40))
+	c.Assert(progress.Progress, Equals, 0.6)
+	c.Assert(progress.LeftTime, NotNil)
+
+	// The pairing is persisted.
+	stores := core.NewStoresInfo()
+	c.Assert(svr.kv.LoadStores(stores), IsNil)
+	c.Assert(stores.GetStore(storeID).GetReplacement().StoreID, Equals, replacementID)
+
+	c.Assert(cluster.DeleteStoreReplacement(storeID), IsNil)
+	_, err = cluster.GetStoreReplacementProgress(storeID)
+	c.Assert(err, NotNil)
+}
+
+func (s *testClusterSuite) TestGetAllStoresWithStats(c *C) {
+	var err error
+	_, s.svr, s.cleanup, err = NewTestServer()
+	c.Assert(err, IsNil)
+	defer s.cleanup()
+	svr := s.svr
+	mustWaitLeader(c, []*Server{svr})
+	req := s.newBootstrapRequest(c, svr.clusterID, "127.0.0.1:0")
+	_, err = svr.bootstrapCluster(req)
+	c.Assert(err, IsNil)
+
+	cluster := svr.GetRaftCluster()
+	storeID := req.GetStore().GetId()
+	tombstone := core.NewStoreInfo(&metapb.Store{Id: storeID + 1, Address: "127.0.0.1:1", State: metapb.StoreState_Tombstone})
+	c.Assert(cluster.cachedCluster.putStore(tombstone), IsNil)
+	c.Assert(cluster.cachedCluster.handleStoreHeartbeat(&pdpb.StoreStats{StoreId: storeID, Capacity: 100, Available: 50}), IsNil)
+
+	conn, err := grpc.Dial(strings.TrimPrefix(svr.GetAddr(), "http://"), grpc.WithInsecure())
+	c.Assert(err, IsNil)
+	defer conn.Close()
+	client := storepb.NewStoreClient(conn)
+	header := newRequestHeader(svr.clusterID)
+
+	resp, err := client.GetAllStores(context.Background(), &storepb.GetAllStoresRequest{Header: header})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetStores(), HasLen, 2)
+	c.Assert(resp.GetStores()[0].GetStore().GetId(), Equals, storeID)
+	c.Assert(resp.GetStores()[0].GetStats(), IsNil)
+	c.Assert(resp.GetStores()[1].GetStore().GetId(), Equals, storeID+1)
+
+	resp, err = client.GetAllStores(context.Background(), &storepb.GetAllStoresRequest{Header: header, ExcludeTombstoneStores: true, WithStats: true})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetStores(), HasLen, 1)
+	info := resp.GetStores()[0]
+	c.Assert(info.GetStore().GetId(), Equals, storeID)
+	c.Assert(info.GetStats().GetCapacity(), Equals, uint64(100))
+	c.Assert(info.GetStats().GetAvailable(), Equals, uint64(50))
+	c.Assert(info.GetLastHeartbeat(), Greater, int64(0))
+}
+
+func (s *testClusterSuite) TestReportDiskStats(c *C) {
+	var err error
+	_, s.svr, s.cleanup, err = NewTestServer()
+	c.Assert(err, IsNil)
+	defer s.cleanup()
+	svr := s.svr
+	mustWaitLeader(c, []*Server{svr})
+	req := s.newBootstrapRequest(c, svr.clusterID, "127.0.0.1:0")
+	_, err = svr.bootstrapCluster(req)
+	c.Assert(err, IsNil)
+
+	conn, err := grpc.Dial(strings.TrimPrefix(svr.GetAddr(), "http://"), grpc.WithInsecure())
+	c.Assert(err, IsNil)
+	defer conn.Close()
+	client := storepb.NewStoreClient(conn)
+	header := newRequestHeader(svr.clusterID)
+
+	storeID := req.GetStore().GetId()
+	stats := &storepb.DiskStats{
+		StoreId:                storeID,
+		Interval:               &pdpb.TimeInterval{StartTimestamp: 100, EndTimestamp: 110},
+		DiskReadBytes:          10 << 20,
+		DiskWriteBytes:         20 << 20,
+		PendingCompactionBytes: 1 << 30,
+	}
+	resp, err := client.ReportDiskStats(context.Background(), &storepb.ReportDiskStatsRequest{Header: header, Stats: stats})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetHeader().GetError(), IsNil)
+	store, err := svr.GetRaftCluster().GetStore(storeID)
+	c.Assert(err, IsNil)
+	c.Assert(store.DiskReadRate(), Equals, float64(1<<20))
+	c.Assert(store.DiskWriteRate(), Equals, float64(2<<20))
+	c.Assert(store.DiskStats.PendingCompactionBytes, Equals, uint64(1<<30))
+
+	stats.StoreId = storeID + 1
+	_, err = client.ReportDiskStats(context.Background(), &storepb.ReportDiskStatsRequest{Header: header, Stats: stats})
+	c.Assert(err, NotNil)
+	_, err = client.ReportDiskStats(context.Background(), &storepb.ReportDiskStatsRequest{Header: header})
+	c.Assert(err, NotNil)
+}
+
 func (s *testClusterSuite) TestGetPDMembers(c *C) {
 	var err error
 	_, s.svr, s.cleanup, err = NewTestServer()
@@ -447,6 +663,118 @@ func (s *testClusterSuite) TestGetPDMembers(c *C) {
 	c.Assert(len(resp.GetMembers()), Not(Equals), 0)
 }
 
+func (s *testClusterSuite) TestGetRegion(c *C) {
+	var err error
+	_, s.svr, s.cleanup, err = NewTestServer()
+	c.Assert(err, IsNil)
+	defer s.cleanup()
+	mustWaitLeader(c, []*Server{s.svr})
+	s.grpcPDClient = mustNewGrpcClient(c, s.svr.GetAddr())
+	clusterID := s.svr.clusterID
+
+	req := s.newBootstrapRequest(c, clusterID, "127.0.0.1:0")
+	_, err = s.svr.bootstrapCluster(req)
+	c.Assert(err, IsNil)
+	cluster := s.getRaftCluster(c)
+
+	// Split the region at "b".
+	storeID := req.GetStore().GetId()
+	left := req.GetRegion()
+	left.EndKey = []byte("b")
+	left.RegionEpoch.Version++
+	leader := s.newPeer(c, storeID, 0)
+	right := s.newRegion(c, 0, []byte("b"), []byte{}, []*metapb.Peer{leader}, left.GetRegionEpoch())
+	c.Assert(cluster.cachedCluster.putRegion(core.NewRegionInfo(left, left.GetPeers()[0])), IsNil)
+	c.Assert(cluster.cachedCluster.putRegion(core.NewRegionInfo(right, leader)), IsNil)
+
+	check := func(resp *pdpb.GetRegionResponse, err error, region *metapb.Region) {
+		c.Assert(err, IsNil)
+		c.Assert(resp.GetRegion().GetId(), Equals, region.GetId())
+		c.Assert(resp.GetLeader(), DeepEquals, region.GetPeers()[0])
+	}
+
+	ctx := context.Background()
+	resp, err := s.grpcPDClient.GetRegion(ctx, &pdpb.GetRegionRequest{Header: newRequestHeader(clusterID), RegionKey: []byte("c")})
+	check(resp, err, right)
+	resp, err = s.grpcPDClient.GetRegionByID(ctx, &pdpb.GetRegionByIDRequest{Header: newRequestHeader(clusterID), RegionId: right.GetId()})
+	check(resp, err, right)
+	resp, err = s.grpcPDClient.GetRegionByID(ctx, &pdpb.GetRegionByIDRequest{Header: newRequestHeader(clusterID), RegionId: left.GetId()})
+	check(resp, err, left)
+	resp, err = s.grpcPDClient.GetPrevRegion(ctx, &pdpb.GetRegionRequest{Header: newRequestHeader(clusterID), RegionKey: []byte("c")})
+	check(resp, err, left)
+
+	// Not found.
+	resp, err = s.grpcPDClient.GetRegionByID(ctx, &pdpb.GetRegionByIDRequest{Header: newRequestHeader(clusterID), RegionId: s.allocID(c)})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetRegion(), IsNil)
+	resp, err = s.grpcPDClient.GetPrevRegion(ctx, &pdpb.GetRegionRequest{Header: newRequestHeader(clusterID), RegionKey: []byte("a")})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetRegion(), IsNil)
+}
+
+func (s *testClusterSuite) TestRegionService(c *C) {
+	var err error
+	_, s.svr, s.cleanup, err = NewTestServer()
+	c.Assert(err, IsNil)
+	defer s.cleanup()
+	mustWaitLeader(c, []*Server{s.svr})
+	clusterID := s.svr.clusterID
+
+	req := s.newBootstrapRequest(c, clusterID, "127.0.0.1:0")
+	_, err = s.svr.bootstrapCluster(req)
+	c.Assert(err, IsNil)
+	cluster := s.getRaftCluster(c)
+
+	// Split the region at "b", the right one has a down peer and a pending peer.
+	storeID := req.GetStore().GetId()
+	left := req.GetRegion()
+	left.EndKey = []byte("b")
+	left.RegionEpoch.Version++
+	leader := s.newPeer(c, storeID, 0)
+	down, pending := s.newPeer(c, storeID+1, 0), s.newPeer(c, storeID+2, 0)
+	right := s.newRegion(c, 0, []byte("b"), []byte{}, []*metapb.Peer{leader, down, pending}, left.GetRegionEpoch())
+	downPeers := []*pdpb.PeerStats{{Peer: down, DownSeconds: 60}}
+	c.Assert(cluster.cachedCluster.putRegion(core.NewRegionInfo(left, left.GetPeers()[0])), IsNil)
+	c.Assert(cluster.cachedCluster.putRegion(core.NewRegionInfo(right, leader, core.WithDownPeers(downPeers), core.WithPendingPeers([]*metapb.Peer{pending}))), IsNil)
+
+	conn, err := grpc.Dial(strings.TrimPrefix(s.svr.GetAddr(), "http://"), grpc.WithInsecure())
+	c.Assert(err, IsNil)
+	defer conn.Close()
+	client := regionpb.NewRegionClient(conn)
+	header := newRequestHeader(clusterID)
+
+	check := func(resp *regionpb.GetRegionResponse, err error, region *metapb.Region) {
+		c.Assert(err, IsNil)
+		c.Assert(resp.GetRegion().GetId(), Equals, region.GetId())
+		c.Assert(resp.GetLeader(), DeepEquals, region.GetPeers()[0])
+		if region.GetId() == right.GetId() {
+			c.Assert(resp.GetDownPeers(), DeepEquals, downPeers)
+			c.Assert(resp.GetPendingPeers(), DeepEquals, []*metapb.Peer{pending})
+		} else {
+			c.Assert(resp.GetDownPeers(), HasLen, 0)
+			c.Assert(resp.GetPendingPeers(), HasLen, 0)
+		}
+	}
+
+	ctx := context.Background()
+	resp, err := client.GetRegion(ctx, &regionpb.GetRegionRequest{Header: header, RegionKey: []byte("c")})
+	check(resp, err, right)
+	resp, err = client.GetRegionByID(ctx, &regionpb.GetRegionByIDRequest{Header: header, RegionId: right.GetId()})
+	check(resp, err, right)
+	resp, err = client.GetRegionByID(ctx, &regionpb.GetRegionByIDRequest{Header: header, RegionId: left.GetId()})
+	check(resp, err, left)
+	resp, err = client.GetPrevRegion(ctx, &regionpb.GetRegionRequest{Header: header, RegionKey: []byte("c")})
+	check(resp, err, left)
+
+	// Not found.
+	resp, err = client.GetRegionByID(ctx, &regionpb.GetRegionByIDRequest{Header: header, RegionId: s.allocID(c)})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetRegion(), IsNil)
+	resp, err = client.GetPrevRegion(ctx, &regionpb.GetRegionRequest{Header: header, RegionKey: []byte("a")})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetRegion(), IsNil)
+}
+
 func (s *testClusterSuite) TestConcurrentHandleRegion(c *C) {
 	var err error
 	_, s.svr, s.cleanup, err = NewTestServer()
@@ -457,9 +785,9 @@ func (s *testClusterSuite) TestConcurrentHandleRegion(c *C) {
 	storeAddrs := []string{"127.0.1.1:0", "127.0.1.1:1", "127.0.1.1:2"}
 	s.svr.bootstrapCluster(s.newBootstrapRequest(c, s.svr.clusterID, "127.0.0.1:0"))
 	s.svr.cluster.RLock()
-	s.svr.cluster.cachedCluster.Lock()
+	s.svr.cluster.cachedCluster.regionsMu.Lock()
 	s.svr.cluster.cachedCluster.kv = core.NewKV(core.NewMemoryKV())
-	s.svr.cluster.cachedCluster.Unlock()
+	s.svr.cluster.cachedCluster.regionsMu.Unlock()
 	s.svr.cluster.RUnlock()
 	var stores []*metapb.Store
 	for _, addr := range storeAddrs {
@@ -531,7 +859,7 @@ func (s *testClusterSuite) TestConcurrentHandleRegion(c *C) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			err := s.svr.cluster.HandleRegionHeartbeat(core.NewRegionInfo(region, region.Peers[0]))
+			err := s.svr.cluster.HandleRegionHeartbeat(context.Background(), core.NewRegionInfo(region, region.Peers[0]))
 			c.Assert(err, IsNil)
 		}()
 	}
@@ -15,20 +15,39 @@ package server
 
 import (
 	"bytes"
+	"context"
+	"strconv"
 
 	"github.com/gogo/protobuf/proto"
+	"github.com/opentracing/opentracing-go"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/pkg/logutil"
 	"github.com/pingcap/pd/server/core"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
-// HandleRegionHeartbeat processes RegionInfo reports from client.
-func (c *RaftCluster) HandleRegionHeartbeat(region *core.RegionInfo) error {
+// regionHeartbeatWorkerQueueCap is the capacity of the queue of each region
+// heartbeat worker, the heartbeat streams are blocked if it is full.
+const regionHeartbeatWorkerQueueCap = 1024
+
+// HandleRegionHeartbeat processes RegionInfo reports from client. It is traced
+// under the span in ctx.
+func (c *RaftCluster) HandleRegionHeartbeat(ctx context.Context, region *core.RegionInfo) error {
 	c.RLock()
 	defer c.RUnlock()
-	if err := c.cachedCluster.handleRegionHeartbeat(region); err != nil {
+	return c.applyRegionHeartbeat(ctx, region)
+}
+
+// applyRegionHeartbeat applies the region heartbeat to the cluster. The
+// caller either holds the lock of the cluster, or is a heartbeat worker which
+// runs only while the cluster is running.
+func (c *RaftCluster) applyRegionHeartbeat(ctx context.Context, region *core.RegionInfo) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "pd.UpdateRegion")
+	err := c.cachedCluster.handleRegionHeartbeat(region)
+	span.Finish()
+	if err != nil {
 		return err
 	}
 
@@ -38,16 +57,118 @@ func (c *RaftCluster) HandleRegionHeartbeat(region *core.RegionInfo) error {
 		return errors.Errorf("invalid region, zero region peer count: %v", core.HexRegionMeta(region.GetMeta()))
 	}
 
+	span, _ = opentracing.StartSpanFromContext(ctx, "pd.Dispatch")
+	if op := c.coordinator.opController.GetOperator(region.GetID()); op != nil {
+		span.SetTag("operator", op.Desc())
+	}
 	c.coordinator.opController.Dispatch(region)
+	span.Finish()
+	c.checkLoadSplit(region)
+	c.replicationMode.observeRegion(region)
 	return nil
 }
 
+// regionHeartbeatTask is a region heartbeat to be applied by a worker.
+type regionHeartbeatTask struct {
+	region     *core.RegionInfo
+	storeLabel string
+	span       opentracing.Span
+}
+
+// regionHeartbeatWorkers apply the region heartbeats in the workers sharded
+// by region ID. The heartbeats of a region are applied in order by the same
+// worker, while the heartbeats of different regions are applied in parallel
+// without the lock of the cluster.
+type regionHeartbeatWorkers struct {
+	cluster   *RaftCluster
+	hbStreams *heartbeatStreams
+	queues    []chan *regionHeartbeatTask
+	quit      chan struct{}
+}
+
+func newRegionHeartbeatWorkers(c *RaftCluster, count int) *regionHeartbeatWorkers {
+	w := &regionHeartbeatWorkers{
+		cluster:   c,
+		hbStreams: c.coordinator.hbStreams,
+		queues:    make([]chan *regionHeartbeatTask, count),
+		quit:      c.quit,
+	}
+	for i := range w.queues {
+		w.queues[i] = make(chan *regionHeartbeatTask, regionHeartbeatWorkerQueueCap)
+	}
+	return w
+}
+
+// run starts the workers, they are added to the wait group of the cluster.
+func (w *regionHeartbeatWorkers) run() {
+	w.cluster.wg.Add(len(w.queues))
+	for i := range w.queues {
+		go w.work(i)
+	}
+}
+
+func (w *regionHeartbeatWorkers) work(i int) {
+	defer logutil.LogPanic()
+	defer w.cluster.wg.Done()
+
+	worker := strconv.Itoa(i)
+	for {
+		select {
+		case task := <-w.queues[i]:
+			regionHeartbeatApplyQueueGauge.WithLabelValues(worker).Set(float64(len(w.queues[i])))
+			ctx := opentracing.ContextWithSpan(context.Background(), task.span)
+			if err := w.cluster.applyRegionHeartbeat(ctx, task.region); err != nil {
+				msg := err.Error()
+				w.hbStreams.sendErr(task.region, pdpb.ErrorType_UNKNOWN, msg, task.storeLabel)
+				task.span.SetTag("error", true)
+				task.span.LogKV("error", msg)
+			}
+			task.span.Finish()
+		case <-w.quit:
+			return
+		}
+	}
+}
+
+// dispatch queues the heartbeat to the worker of the region, it blocks if the
+// queue is full. It returns false if the cluster is stopped.
+func (w *regionHeartbeatWorkers) dispatch(task *regionHeartbeatTask) bool {
+	select {
+	case <-w.quit:
+		return false
+	default:
+	}
+	select {
+	case w.queues[task.region.GetID()%uint64(len(w.queues))] <- task:
+		return true
+	case <-w.quit:
+		return false
+	}
+}
+
+// dispatchRegionHeartbeat queues the region heartbeat to the heartbeat
+// workers, the span is finished after the heartbeat is applied. It returns
+// false if there is no worker, then the caller should apply it by
+// HandleRegionHeartbeat.
+func (c *RaftCluster) dispatchRegionHeartbeat(region *core.RegionInfo, storeLabel string, span opentracing.Span) bool {
+	c.RLock()
+	workers := c.hbWorkers
+	c.RUnlock()
+	if workers == nil {
+		return false
+	}
+	return workers.dispatch(&regionHeartbeatTask{region: region, storeLabel: storeLabel, span: span})
+}
+
 func (c *RaftCluster) handleAskSplit(request *pdpb.AskSplitRequest) (*pdpb.AskSplitResponse, error) {
 	reqRegion := request.GetRegion()
 	err := c.validRequestRegion(reqRegion)
 	if err != nil {
 		return nil, err
 	}
+	if err = c.checkSplitQuota(reqRegion, 1); err != nil {
+		return nil, err
+	}
 
 	newRegionID, err := c.s.idAlloc.Alloc()
 	if err != nil {
@@ -91,6 +212,18 @@ func (c *RaftCluster) validRequestRegion(reqRegion *metapb.Region) error {
 	return nil
 }
 
+// checkSplitQuota checks the quota of the namespace of the region before
+// allocating IDs for the new regions.
+func (c *RaftCluster) checkSplitQuota(reqRegion *metapb.Region, count uint64) error {
+	c.RLock()
+	defer c.RUnlock()
+	region := c.cachedCluster.GetRegion(reqRegion.GetId())
+	if region == nil {
+		region = core.NewRegionInfo(reqRegion, nil)
+	}
+	return c.coordinator.quotas.checkSplit(region, count, true)
+}
+
 func (c *RaftCluster) handleAskBatchSplit(request *pdpb.AskBatchSplitRequest) (*pdpb.AskBatchSplitResponse, error) {
 	reqRegion := request.GetRegion()
 	splitCount := request.GetSplitCount()
@@ -98,6 +231,9 @@ func (c *RaftCluster) handleAskBatchSplit(request *pdpb.AskBatchSplitRequest) (*
 	if err != nil {
 		return nil, err
 	}
+	if err = c.checkSplitQuota(reqRegion, uint64(splitCount)); err != nil {
+		return nil, err
+	}
 	splitIDs := make([]*pdpb.SplitID, 0, splitCount)
 
 	c.RLock()
@@ -178,6 +314,7 @@ func (c *RaftCluster) handleReportSplit(request *pdpb.ReportSplitRequest) (*pdpb
 	originRegion.RegionEpoch = nil
 	originRegion.StartKey = left.GetStartKey()
 	log.Infof("[region %d] region split, generate new region: %v", originRegion.GetId(), core.HexRegionMeta(left))
+	c.events.record(EventRegionSplit, 0, originRegion.GetId(), "region %d splits, generate new region %d", originRegion.GetId(), left.GetId())
 	return &pdpb.ReportSplitResponse{}, nil
 }
 
@@ -196,5 +333,10 @@ func (c *RaftCluster) handleBatchReportSplit(request *pdpb.ReportBatchSplitReque
 	last := len(regions) - 1
 	originRegion := proto.Clone(regions[last]).(*metapb.Region)
 	log.Infof("[region %d] region split, generate %d new regions: %v", originRegion.GetId(), last, hexRegionMetas[:last])
+	newIDs := make([]uint64, 0, last)
+	for _, region := range regions[:last] {
+		newIDs = append(newIDs, region.GetId())
+	}
+	c.events.record(EventRegionSplit, 0, originRegion.GetId(), "region %d splits, generate new regions %v", originRegion.GetId(), newIDs)
 	return &pdpb.ReportBatchSplitResponse{}, nil
 }
@@ -14,9 +14,13 @@
 package server
 
 import (
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/pkg/testutil"
 )
 
 var _ = Suite(&testClusterWorkerSuite{})
@@ -44,3 +48,85 @@ func (s *testClusterWorkerSuite) TestReportBatchSplit(c *C) {
 	_, err := cluster.handleBatchReportSplit(&pdpb.ReportBatchSplitRequest{Regions: regions})
 	c.Assert(err, IsNil)
 }
+
+var _ = Suite(&testRegionHeartbeatWorkersSuite{})
+
+type testRegionHeartbeatWorkersSuite struct {
+	baseCluster
+}
+
+func (s *testRegionHeartbeatWorkersSuite) TestApplyInOrder(c *C) {
+	var err error
+	_, s.svr, s.cleanup, err = NewTestServer()
+	c.Assert(err, IsNil)
+	defer s.cleanup()
+	mustWaitLeader(c, []*Server{s.svr})
+	s.grpcPDClient = mustNewGrpcClient(c, s.svr.GetAddr())
+
+	cfg := *s.svr.scheduleOpt.loadPDServerConfig()
+	cfg.RegionHeartbeatWorkers = 4
+	c.Assert(s.svr.SetPDServerConfig(cfg), IsNil)
+	req := s.newBootstrapRequest(c, s.svr.clusterID, "127.0.0.1:0")
+	_, err = s.svr.bootstrapCluster(req)
+	c.Assert(err, IsNil)
+	cluster := s.svr.GetRaftCluster()
+	c.Assert(cluster.hbWorkers, NotNil)
+	c.Assert(cluster.hbWorkers.queues, HasLen, 4)
+
+	stream := newRegionheartbeatClient(c, s.grpcPDClient)
+	defer stream.close()
+	var regions []*metapb.Region
+	for i := 0; i < 10; i++ {
+		peer := s.newPeer(c, req.Store.GetId(), 0)
+		start, end := []byte(fmt.Sprintf("a%02d", i)), []byte(fmt.Sprintf("a%02d", i+1))
+		regions = append(regions, s.newRegion(c, 0, start, end, []*metapb.Peer{peer}, nil))
+	}
+	// The heartbeats of each region are applied in order, or the stale ones
+	// would be rejected.
+	const versions = 20
+	for v := uint64(1); v <= versions; v++ {
+		for _, region := range regions {
+			region.RegionEpoch.Version = v
+			err = stream.stream.Send(&pdpb.RegionHeartbeatRequest{
+				Header: newRequestHeader(s.svr.clusterID),
+				Leader: region.Peers[0],
+				Region: proto.Clone(region).(*metapb.Region),
+			})
+			c.Assert(err, IsNil)
+		}
+	}
+	testutil.WaitUntil(c, func(c *C) bool {
+		for _, region := range regions {
+			r := cluster.GetRegionInfoByID(region.GetId())
+			if r == nil || r.GetRegionEpoch().GetVersion() != versions {
+				return false
+			}
+		}
+		return true
+	})
+
+	// The stale heartbeat is rejected.
+	stale := proto.Clone(regions[0]).(*metapb.Region)
+	stale.RegionEpoch.Version = 1
+	err = stream.stream.Send(&pdpb.RegionHeartbeatRequest{
+		Header: newRequestHeader(s.svr.clusterID),
+		Leader: stale.Peers[0],
+		Region: stale,
+	})
+	c.Assert(err, IsNil)
+	// Sent after the stale one, it is applied after the stale one is handled.
+	regions[0].RegionEpoch.Version = versions + 1
+	err = stream.stream.Send(&pdpb.RegionHeartbeatRequest{
+		Header: newRequestHeader(s.svr.clusterID),
+		Leader: regions[0].Peers[0],
+		Region: regions[0],
+	})
+	c.Assert(err, IsNil)
+	testutil.WaitUntil(c, func(c *C) bool {
+		return cluster.GetRegionInfoByID(regions[0].GetId()).GetRegionEpoch().GetVersion() == versions+1
+	})
+
+	// The workers exit with the cluster.
+	s.svr.stopRaftCluster()
+	c.Assert(cluster.hbWorkers, IsNil)
+}
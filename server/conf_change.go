@@ -0,0 +1,40 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	"github.com/pingcap/pd/pkg/confchangepb"
+)
+
+// confChangeService implements gRPC ConfChangeServer.
+type confChangeService struct {
+	s *Server
+}
+
+// GetConfChangeCommands implements gRPC ConfChangeServer.
+func (cs *confChangeService) GetConfChangeCommands(ctx context.Context, request *confchangepb.GetConfChangeCommandsRequest) (*confchangepb.GetConfChangeCommandsResponse, error) {
+	if err := cs.s.validateRequest(request.GetHeader()); err != nil {
+		return nil, err
+	}
+	cluster := cs.s.GetRaftCluster()
+	if cluster == nil {
+		return &confchangepb.GetConfChangeCommandsResponse{Header: cs.s.notBootstrappedHeader()}, nil
+	}
+	return &confchangepb.GetConfChangeCommandsResponse{
+		Header:   cs.s.header(),
+		Commands: cluster.coordinator.opController.GetConfChangeCommands(request.GetStoreId()),
+	}, nil
+}
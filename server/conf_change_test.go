@@ -0,0 +1,92 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/eraftpb"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/pkg/confchangepb"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/schedule"
+	"google.golang.org/grpc"
+)
+
+var _ = Suite(&testConfChangeSuite{})
+
+type testConfChangeSuite struct{}
+
+func (s *testConfChangeSuite) TestDemotePromote(c *C) {
+	_, svr, cleanup, err := NewTestServer()
+	c.Assert(err, IsNil)
+	defer cleanup()
+	mustWaitLeader(c, []*Server{svr})
+	req := (&baseCluster{svr: svr}).newBootstrapRequest(c, svr.clusterID, "127.0.0.1:0")
+	_, err = svr.bootstrapCluster(req)
+	c.Assert(err, IsNil)
+
+	// The region has voters on 3 stores and a learner on the 4th one.
+	cluster := svr.GetRaftCluster()
+	ctx := context.Background()
+	meta := proto.Clone(req.GetRegion()).(*metapb.Region)
+	storeID := req.GetStore().GetId()
+	for i := uint64(1); i <= 3; i++ {
+		store := core.NewStoreInfo(&metapb.Store{Id: storeID + i, Address: fmt.Sprintf("127.0.0.1:%d", i)})
+		c.Assert(cluster.cachedCluster.putStore(store), IsNil)
+		meta.Peers = append(meta.Peers, &metapb.Peer{Id: meta.GetPeers()[0].GetId() + 100*i, StoreId: storeID + i, IsLearner: i == 3})
+	}
+	meta.RegionEpoch.ConfVer += 3
+	leader := meta.GetPeers()[0]
+	c.Assert(cluster.HandleRegionHeartbeat(ctx, core.NewRegionInfo(proto.Clone(meta).(*metapb.Region), leader)), IsNil)
+	demoted, promoted := meta.GetPeers()[1], meta.GetPeers()[3]
+	step := schedule.DemotePromote{FromStore: demoted.GetStoreId(), FromPeerID: demoted.GetId(), ToStore: promoted.GetStoreId(), ToPeerID: promoted.GetId()}
+	op := schedule.NewOperator("demotePromote", meta.GetId(), meta.GetRegionEpoch(), schedule.OpRegion, step)
+	c.Assert(cluster.coordinator.opController.AddOperator(op), IsTrue)
+
+	conn, err := grpc.Dial(strings.TrimPrefix(svr.GetAddr(), "http://"), grpc.WithInsecure())
+	c.Assert(err, IsNil)
+	defer conn.Close()
+	client := confchangepb.NewConfChangeClient(conn)
+	header := newRequestHeader(svr.clusterID)
+
+	// The conf change is only sent to the leader store.
+	resp, err := client.GetConfChangeCommands(ctx, &confchangepb.GetConfChangeCommandsRequest{Header: header, StoreId: demoted.GetStoreId()})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetCommands(), HasLen, 0)
+	resp, err = client.GetConfChangeCommands(ctx, &confchangepb.GetConfChangeCommandsRequest{Header: header, StoreId: storeID})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetCommands(), DeepEquals, []*confchangepb.ConfChangeCommand{{
+		RegionId:    meta.GetId(),
+		RegionEpoch: meta.GetRegionEpoch(),
+		Changes: []*pdpb.ChangePeer{
+			{ChangeType: eraftpb.ConfChangeType_AddLearnerNode, Peer: &metapb.Peer{Id: demoted.GetId(), StoreId: demoted.GetStoreId(), IsLearner: true}},
+			{ChangeType: eraftpb.ConfChangeType_AddNode, Peer: &metapb.Peer{Id: promoted.GetId(), StoreId: promoted.GetStoreId()}},
+		},
+	}})
+
+	// The conf change is finished by the heartbeat after applying it.
+	demoted.IsLearner, promoted.IsLearner = true, false
+	meta.RegionEpoch.ConfVer++
+	c.Assert(cluster.HandleRegionHeartbeat(ctx, core.NewRegionInfo(proto.Clone(meta).(*metapb.Region), leader)), IsNil)
+	c.Assert(step.IsFinish(cluster.GetRegionInfoByID(meta.GetId())), IsTrue)
+	resp, err = client.GetConfChangeCommands(ctx, &confchangepb.GetConfChangeCommandsRequest{Header: header, StoreId: storeID})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetCommands(), HasLen, 0)
+}
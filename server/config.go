@@ -14,12 +14,15 @@
 package server
 
 import (
+	"bytes"
 	"crypto/tls"
+	"encoding/hex"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"net/url"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
@@ -29,8 +32,11 @@ import (
 	"github.com/coreos/go-semver/semver"
 	"github.com/pingcap/pd/pkg/logutil"
 	"github.com/pingcap/pd/pkg/metricutil"
+	"github.com/pingcap/pd/pkg/tracing"
 	"github.com/pingcap/pd/pkg/typeutil"
+	"github.com/pingcap/pd/server/core"
 	"github.com/pingcap/pd/server/namespace"
+	"github.com/pingcap/pd/table"
 	"github.com/pkg/errors"
 )
 
@@ -44,6 +50,12 @@ type Config struct {
 	PeerUrls            string `toml:"peer-urls" json:"peer-urls"`
 	AdvertiseClientUrls string `toml:"advertise-client-urls" json:"advertise-client-urls"`
 	AdvertisePeerUrls   string `toml:"advertise-peer-urls" json:"advertise-peer-urls"`
+	// APIUrls are the urls to serve the PD HTTP and gRPC APIs. The APIs are
+	// served on the etcd client urls if it is empty. Otherwise only etcd is
+	// served on the client urls, so that etcd can be firewalled from the
+	// applications.
+	APIUrls          string `toml:"api-urls" json:"api-urls"`
+	AdvertiseAPIUrls string `toml:"advertise-api-urls" json:"advertise-api-urls"`
 
 	Name    string `toml:"name" json:"name"`
 	DataDir string `toml:"data-dir" json:"data-dir"`
@@ -54,6 +66,11 @@ type Config struct {
 	// Join to an existing pd cluster, a string of endpoints.
 	Join string `toml:"join" json:"join"`
 
+	// AllowDowngrade allows current PD to campaign leader when its binary
+	// version is lower than the cluster version. The leader of an older
+	// version may corrupt the metadata written by newer versions.
+	AllowDowngrade bool `toml:"allow-downgrade" json:"allow-downgrade"`
+
 	// LeaderLease time, if leader doesn't update its TTL
 	// in etcd after lease time, etcd will expire the leader key
 	// and other servers can campaign the leader again.
@@ -69,9 +86,25 @@ type Config struct {
 
 	// TsoSaveInterval is the interval to save timestamp.
 	TsoSaveInterval typeutil.Duration `toml:"tso-save-interval" json:"tso-save-interval"`
+	// TsoSaveGuard is the margin of the saved timestamp window. When the time
+	// left in the window is less than it, the next window is saved in the
+	// background, so that allocating timestamps does not wait for etcd.
+	TsoSaveGuard typeutil.Duration `toml:"tso-save-guard" json:"tso-save-guard"`
+
+	// EtcdReadTimeout is the timeout of reading from etcd.
+	EtcdReadTimeout typeutil.Duration `toml:"etcd-read-timeout" json:"etcd-read-timeout"`
+	// EtcdWriteTimeout is the timeout of etcd transactions.
+	EtcdWriteTimeout typeutil.Duration `toml:"etcd-write-timeout" json:"etcd-write-timeout"`
+
+	// DrainTimeout bounds each step of draining the server when it is closed:
+	// waiting for the leadership to move to another member, and waiting for
+	// the queued heartbeat responses to be sent.
+	DrainTimeout typeutil.Duration `toml:"drain-timeout" json:"drain-timeout"`
 
 	Metric metricutil.MetricConfig `toml:"metric" json:"metric"`
 
+	Tracing tracing.Config `toml:"tracing" json:"tracing"`
+
 	Schedule ScheduleConfig `toml:"schedule" json:"schedule"`
 
 	Replication ReplicationConfig `toml:"replication" json:"replication"`
@@ -80,6 +113,27 @@ type Config struct {
 
 	PDServerCfg PDServerConfig `toml:"pd-server" json:"pd-server"`
 
+	MetaSnapshot MetaSnapshotConfig `toml:"meta-snapshot" json:"meta-snapshot"`
+
+	RegionStorage RegionStorageConfig `toml:"region-storage" json:"region-storage"`
+
+	EtcdMaintenance EtcdMaintenanceConfig `toml:"etcd-maintenance" json:"etcd-maintenance"`
+
+	ReplicationMode ReplicationModeConfig `toml:"replication-mode" json:"replication-mode"`
+
+	EventLog EventLogConfig `toml:"event-log" json:"event-log"`
+
+	Encryption EncryptionConfig `toml:"encryption" json:"encryption"`
+
+	LabelSchema LabelSchemaConfig `toml:"label-schema" json:"label-schema"`
+
+	SchedulerPlugin SchedulerPluginConfig `toml:"scheduler-plugin" json:"scheduler-plugin"`
+
+	// Labels are the deployment labels of current member, such as the zone,
+	// which are shown in the member list and matched against
+	// leader-preferred-zone.
+	Labels map[string]string `toml:"labels" json:"labels"`
+
 	ClusterVersion semver.Version `json:"cluster-version"`
 
 	// QuotaBackendBytes Raise alarms when backend size exceeds the given quota. 0 means use the default quota.
@@ -109,6 +163,10 @@ type Config struct {
 
 	LabelProperty LabelPropertyConfig `toml:"label-property" json:"label-property"`
 
+	// MergeProtection keeps the newly split regions in the key ranges or
+	// tables from being merged for a while.
+	MergeProtection MergeProtectionConfig `toml:"merge-protection" json:"merge-protection"`
+
 	configFile string
 
 	// For all warnings during parsing.
@@ -144,8 +202,11 @@ func NewConfig() *Config {
 	fs.StringVar(&cfg.AdvertiseClientUrls, "advertise-client-urls", "", "advertise url for client traffic (default '${client-urls}')")
 	fs.StringVar(&cfg.PeerUrls, "peer-urls", defaultPeerUrls, "url for peer traffic")
 	fs.StringVar(&cfg.AdvertisePeerUrls, "advertise-peer-urls", "", "advertise url for peer traffic (default '${peer-urls}')")
+	fs.StringVar(&cfg.APIUrls, "api-urls", "", "url for PD API traffic (default to serve on '${client-urls}')")
+	fs.StringVar(&cfg.AdvertiseAPIUrls, "advertise-api-urls", "", "advertise url for PD API traffic (default '${api-urls}')")
 	fs.StringVar(&cfg.InitialCluster, "initial-cluster", "", "initial cluster configuration for bootstrapping, e,g. pd=http://127.0.0.1:2380")
 	fs.StringVar(&cfg.Join, "join", "", "join to an existing cluster (usage: cluster's '${advertise-client-urls}'")
+	fs.BoolVar(&cfg.AllowDowngrade, "allow-downgrade", false, "allow to campaign leader when the binary version is lower than the cluster version")
 
 	fs.StringVar(&cfg.Log.Level, "L", "", "log level: debug, info, warn, error, fatal (default 'info')")
 	fs.StringVar(&cfg.Log.File.Filename, "log-file", "", "log file path")
@@ -163,6 +224,7 @@ func NewConfig() *Config {
 
 const (
 	defaultLeaderLease             = int64(3)
+	defaultDrainTimeout            = 5 * time.Second
 	defaultNextRetryDelay          = time.Second
 	defaultCompactionMode          = "periodic"
 	defaultAutoCompactionRetention = "1h"
@@ -183,6 +245,22 @@ const (
 	defaultHeartbeatStreamRebindInterval = time.Minute
 
 	defaultLeaderPriorityCheckInterval = time.Minute
+
+	defaultMetaSnapshotInterval = time.Hour
+	defaultMaxMetaSnapshots     = 24
+
+	defaultEtcdMaintenanceInterval = 10 * time.Minute
+
+	defaultRegionFlushInterval  = 3 * time.Second
+	defaultRegionFlushBatchSize = 100
+
+	defaultDRWaitStoreTimeout = time.Minute
+	defaultDRWaitSyncTimeout  = time.Minute
+
+	defaultMaxClusterEvents    = 1000
+	defaultEventWebhookTimeout = 3 * time.Second
+
+	defaultSlowLogSampleRate = 1.0
 )
 
 func adjustString(v *string, defValue string) {
@@ -300,6 +378,7 @@ func (c *Config) Adjust(meta *toml.MetaData) error {
 	adjustString(&c.AdvertiseClientUrls, c.ClientUrls)
 	adjustString(&c.PeerUrls, defaultPeerUrls)
 	adjustString(&c.AdvertisePeerUrls, c.PeerUrls)
+	adjustString(&c.AdvertiseAPIUrls, c.APIUrls)
 
 	if len(c.InitialCluster) == 0 {
 		// The advertise peer urls may be http://127.0.0.1:2380,http://127.0.0.1:2381
@@ -324,6 +403,13 @@ func (c *Config) Adjust(meta *toml.MetaData) error {
 	adjustInt64(&c.LeaderLease, defaultLeaderLease)
 
 	adjustDuration(&c.TsoSaveInterval, time.Duration(defaultLeaderLease)*time.Second)
+	adjustDuration(&c.TsoSaveGuard, c.TsoSaveInterval.Duration/3)
+	adjustDuration(&c.DrainTimeout, defaultDrainTimeout)
+	if c.TsoSaveGuard.Duration >= c.TsoSaveInterval.Duration {
+		return errors.Errorf("tso-save-guard %v should be less than tso-save-interval %v", c.TsoSaveGuard.Duration, c.TsoSaveInterval.Duration)
+	}
+	adjustDuration(&c.EtcdReadTimeout, requestTimeout)
+	adjustDuration(&c.EtcdWriteTimeout, requestTimeout)
 
 	if c.nextRetryDelay == 0 {
 		c.nextRetryDelay = defaultNextRetryDelay
@@ -337,6 +423,9 @@ func (c *Config) Adjust(meta *toml.MetaData) error {
 	adjustString(&c.NamespaceClassifier, "table")
 
 	adjustString(&c.Metric.PushJob, c.Name)
+	if err := c.Metric.Adjust(); err != nil {
+		return err
+	}
 
 	if err := c.Schedule.adjust(); err != nil {
 		return err
@@ -349,6 +438,51 @@ func (c *Config) Adjust(meta *toml.MetaData) error {
 
 	adjustDuration(&c.LeaderPriorityCheckInterval, defaultLeaderPriorityCheckInterval)
 
+	adjustDuration(&c.MetaSnapshot.Interval, defaultMetaSnapshotInterval)
+	adjustUint64(&c.MetaSnapshot.MaxSnapshots, defaultMaxMetaSnapshots)
+
+	if err := c.RegionStorage.adjust(); err != nil {
+		return err
+	}
+	if err := c.EtcdMaintenance.adjust(); err != nil {
+		return err
+	}
+	if err := c.ReplicationMode.adjust(); err != nil {
+		return err
+	}
+	adjustUint64(&c.EventLog.MaxCount, defaultMaxClusterEvents)
+	adjustDuration(&c.EventLog.WebhookTimeout, defaultEventWebhookTimeout)
+	if c.Tracing.SampleRate == 0 && (meta == nil || !meta.IsDefined("tracing", "sample-rate")) {
+		c.Tracing.SampleRate = tracing.DefaultSampleRate
+	}
+	if err := c.Tracing.Adjust(); err != nil {
+		return err
+	}
+	if err := c.Encryption.adjust(); err != nil {
+		return err
+	}
+	if err := c.LabelSchema.validate(); err != nil {
+		return err
+	}
+	if err := c.SchedulerPlugin.validate(); err != nil {
+		return err
+	}
+	if err := c.MergeProtection.validate(); err != nil {
+		return err
+	}
+	for k, v := range c.Labels {
+		if err := ValidateLabelString(k); err != nil {
+			return err
+		}
+		if err := ValidateLabelString(v); err != nil {
+			return err
+		}
+	}
+	adjustFloat64(&c.PDServerCfg.SlowLogSampleRate, defaultSlowLogSampleRate)
+	if err := c.PDServerCfg.validate(); err != nil {
+		return err
+	}
+
 	// enable PreVote by default
 	if meta == nil || !meta.IsDefined("enable-prevote") {
 		c.PreVote = true
@@ -382,6 +516,17 @@ type ScheduleConfig struct {
 	// it will never be used as a source or target store.
 	MaxSnapshotCount    uint64 `toml:"max-snapshot-count,omitempty" json:"max-snapshot-count"`
 	MaxPendingPeerCount uint64 `toml:"max-pending-peer-count,omitempty" json:"max-pending-peer-count"`
+	// If the pending compaction bytes of one store is greater than this value,
+	// it will not be used as the target store of snapshots.
+	MaxPendingCompactionBytes typeutil.ByteSize `toml:"max-pending-compaction-bytes,omitempty" json:"max-pending-compaction-bytes"`
+	// MinSnapshotSpeed is the speed per second assumed to send a snapshot. The
+	// timeout of operators adding peers is extended by the time to send the
+	// region at this speed, so that huge regions do not time out halfway.
+	MinSnapshotSpeed typeutil.ByteSize `toml:"min-snapshot-speed,omitempty" json:"min-snapshot-speed"`
+	// SnapshotBandwidth is the bytes per second all the stores may use to send
+	// snapshots. It is shared by the stores through the bandwidth service, 0
+	// means no limit.
+	SnapshotBandwidth typeutil.ByteSize `toml:"snapshot-bandwidth,omitempty" json:"snapshot-bandwidth"`
 	// If both the size of region is smaller than MaxMergeRegionSize
 	// and the number of rows in region is smaller than MaxMergeRegionKeys,
 	// it will try to merge with adjacent regions.
@@ -389,6 +534,11 @@ type ScheduleConfig struct {
 	MaxMergeRegionKeys uint64 `toml:"max-merge-region-keys,omitempty" json:"max-merge-region-keys"`
 	// SplitMergeInterval is the minimum interval time to permit merge after split.
 	SplitMergeInterval typeutil.Duration `toml:"split-merge-interval,omitempty" json:"split-merge-interval"`
+	// SplitQPSThreshold is the keys read and written per second of a region,
+	// above which the region is split at the key bisecting the load if it
+	// lasts for SplitHotDuration.
+	SplitQPSThreshold uint64            `toml:"split-qps-threshold,omitempty" json:"split-qps-threshold"`
+	SplitHotDuration  typeutil.Duration `toml:"split-hot-duration,omitempty" json:"split-hot-duration"`
 	// PatrolRegionInterval is the interval for scanning region during patrol.
 	PatrolRegionInterval typeutil.Duration `toml:"patrol-region-interval,omitempty" json:"patrol-region-interval"`
 	// MaxStoreDownTime is the max duration after which
@@ -396,6 +546,9 @@ type ScheduleConfig struct {
 	MaxStoreDownTime typeutil.Duration `toml:"max-store-down-time,omitempty" json:"max-store-down-time"`
 	// LeaderScheduleLimit is the max coexist leader schedules.
 	LeaderScheduleLimit uint64 `toml:"leader-schedule-limit,omitempty" json:"leader-schedule-limit"`
+	// LeaderScheduleBatch is the max number of leader transfers the
+	// balance-leader scheduler creates in one schedule.
+	LeaderScheduleBatch uint64 `toml:"leader-schedule-batch,omitempty" json:"leader-schedule-batch"`
 	// RegionScheduleLimit is the max coexist region schedules.
 	RegionScheduleLimit uint64 `toml:"region-schedule-limit,omitempty" json:"region-schedule-limit"`
 	// ReplicaScheduleLimit is the max coexist replica schedules.
@@ -416,8 +569,30 @@ type ScheduleConfig struct {
 	// HighSpaceRatio is the highest usage ratio of store which regraded as high space.
 	// High space means there is a lot of spare capacity, and store region score varies directly with used size.
 	HighSpaceRatio float64 `toml:"high-space-ratio,omitempty" json:"high-space-ratio"`
+	// RegionStatsChangeRatio is the ratio of the approximate size or keys change,
+	// below which the region heartbeat is not updated to the cache if nothing
+	// else is changed. It cuts the cost of heartbeats from a lot of regions.
+	RegionStatsChangeRatio float64 `toml:"region-stats-change-ratio,omitempty" json:"region-stats-change-ratio"`
+	// RegionCollectFactor is the ratio of the regions, in total and on each
+	// store, which should be reported by heartbeats after the leader starts
+	// before the coordinator starts scheduling.
+	RegionCollectFactor float64 `toml:"region-collect-factor,omitempty" json:"region-collect-factor"`
+	// HaltLowSpaceStoreRatio is the ratio of low space stores in the up stores,
+	// above which the balance scheduling is halted. Set it to 1 to disable.
+	HaltLowSpaceStoreRatio float64 `toml:"halt-low-space-store-ratio,omitempty" json:"halt-low-space-store-ratio"`
+	// HaltEtcdLatency is the latency of etcd requests, above which the balance
+	// scheduling is halted.
+	HaltEtcdLatency typeutil.Duration `toml:"halt-etcd-latency,omitempty" json:"halt-etcd-latency"`
+	// RegionConsistencyCheckInterval is the interval to compare the cached
+	// regions with the persisted regions and report the divergence. 0 means
+	// the check only runs when it is started by the admin API.
+	RegionConsistencyCheckInterval typeutil.Duration `toml:"region-consistency-check-interval,omitempty" json:"region-consistency-check-interval"`
 	// DisableLearner is the option to disable using AddLearnerNode instead of AddNode
 	DisableLearner bool `toml:"disable-raft-learner" json:"disable-raft-learner,string"`
+	// EnableJointConsensus is the option to replace a voter by demoting it and
+	// promoting the new learner in a single conf change. The stores get the
+	// conf changes by the conf change service.
+	EnableJointConsensus bool `toml:"enable-joint-consensus" json:"enable-joint-consensus,string"`
 
 	// DisableRemoveDownReplica is the option to prevent replica checker from
 	// removing down replicas.
@@ -437,6 +612,13 @@ type ScheduleConfig struct {
 	// DisableNamespaceRelocation is the option to prevent namespace checker
 	// from moving replica to the target namespace.
 	DisableNamespaceRelocation bool `toml:"disable-namespace-relocation" json:"disable-namespace-relocation,string"`
+	// DisableLoadSplit is the option to prevent splitting the regions with
+	// sustained high load.
+	DisableLoadSplit bool `toml:"disable-load-split" json:"disable-load-split,string"`
+	// EnableDebugSchedulers is the option to allow the schedulers which move
+	// leaders and regions randomly, such as shuffle-leader. They are used to
+	// stress the failover of upper layers in test clusters.
+	EnableDebugSchedulers bool `toml:"enable-debug-schedulers" json:"enable-debug-schedulers,string"`
 
 	// Schedulers support for loding customized schedulers
 	Schedulers SchedulerConfigs `toml:"schedulers,omitempty" json:"schedulers-v2"` // json v2 is for the sake of compatible upgrade
@@ -446,28 +628,42 @@ func (c *ScheduleConfig) clone() *ScheduleConfig {
 	schedulers := make(SchedulerConfigs, len(c.Schedulers))
 	copy(schedulers, c.Schedulers)
 	return &ScheduleConfig{
-		MaxSnapshotCount:             c.MaxSnapshotCount,
-		MaxPendingPeerCount:          c.MaxPendingPeerCount,
-		MaxMergeRegionSize:           c.MaxMergeRegionSize,
-		MaxMergeRegionKeys:           c.MaxMergeRegionKeys,
-		SplitMergeInterval:           c.SplitMergeInterval,
-		PatrolRegionInterval:         c.PatrolRegionInterval,
-		MaxStoreDownTime:             c.MaxStoreDownTime,
-		LeaderScheduleLimit:          c.LeaderScheduleLimit,
-		RegionScheduleLimit:          c.RegionScheduleLimit,
-		ReplicaScheduleLimit:         c.ReplicaScheduleLimit,
-		MergeScheduleLimit:           c.MergeScheduleLimit,
-		TolerantSizeRatio:            c.TolerantSizeRatio,
-		LowSpaceRatio:                c.LowSpaceRatio,
-		HighSpaceRatio:               c.HighSpaceRatio,
-		DisableLearner:               c.DisableLearner,
-		DisableRemoveDownReplica:     c.DisableRemoveDownReplica,
-		DisableReplaceOfflineReplica: c.DisableReplaceOfflineReplica,
-		DisableMakeUpReplica:         c.DisableMakeUpReplica,
-		DisableRemoveExtraReplica:    c.DisableRemoveExtraReplica,
-		DisableLocationReplacement:   c.DisableLocationReplacement,
-		DisableNamespaceRelocation:   c.DisableNamespaceRelocation,
-		Schedulers:                   schedulers,
+		MaxSnapshotCount:               c.MaxSnapshotCount,
+		MaxPendingPeerCount:            c.MaxPendingPeerCount,
+		MaxPendingCompactionBytes:      c.MaxPendingCompactionBytes,
+		MinSnapshotSpeed:               c.MinSnapshotSpeed,
+		SnapshotBandwidth:              c.SnapshotBandwidth,
+		MaxMergeRegionSize:             c.MaxMergeRegionSize,
+		MaxMergeRegionKeys:             c.MaxMergeRegionKeys,
+		SplitMergeInterval:             c.SplitMergeInterval,
+		SplitQPSThreshold:              c.SplitQPSThreshold,
+		SplitHotDuration:               c.SplitHotDuration,
+		PatrolRegionInterval:           c.PatrolRegionInterval,
+		MaxStoreDownTime:               c.MaxStoreDownTime,
+		LeaderScheduleLimit:            c.LeaderScheduleLimit,
+		LeaderScheduleBatch:            c.LeaderScheduleBatch,
+		RegionScheduleLimit:            c.RegionScheduleLimit,
+		ReplicaScheduleLimit:           c.ReplicaScheduleLimit,
+		MergeScheduleLimit:             c.MergeScheduleLimit,
+		TolerantSizeRatio:              c.TolerantSizeRatio,
+		LowSpaceRatio:                  c.LowSpaceRatio,
+		HighSpaceRatio:                 c.HighSpaceRatio,
+		RegionStatsChangeRatio:         c.RegionStatsChangeRatio,
+		RegionCollectFactor:            c.RegionCollectFactor,
+		HaltLowSpaceStoreRatio:         c.HaltLowSpaceStoreRatio,
+		HaltEtcdLatency:                c.HaltEtcdLatency,
+		RegionConsistencyCheckInterval: c.RegionConsistencyCheckInterval,
+		DisableLearner:                 c.DisableLearner,
+		EnableJointConsensus:           c.EnableJointConsensus,
+		DisableRemoveDownReplica:       c.DisableRemoveDownReplica,
+		DisableReplaceOfflineReplica:   c.DisableReplaceOfflineReplica,
+		DisableMakeUpReplica:           c.DisableMakeUpReplica,
+		DisableRemoveExtraReplica:      c.DisableRemoveExtraReplica,
+		DisableLocationReplacement:     c.DisableLocationReplacement,
+		DisableNamespaceRelocation:     c.DisableNamespaceRelocation,
+		DisableLoadSplit:               c.DisableLoadSplit,
+		EnableDebugSchedulers:          c.EnableDebugSchedulers,
+		Schedulers:                     schedulers,
 	}
 }
 
@@ -475,35 +671,57 @@ const (
 	defaultMaxReplicas          = 3
 	defaultMaxSnapshotCount     = 3
 	defaultMaxPendingPeerCount  = 16
+	defaultMaxPendingCompaction = 64 * (1 << 30) // 64GiB
+	defaultMinSnapshotSpeed     = 10 * (1 << 20) // 10MiB
 	defaultMaxMergeRegionSize   = 20
 	defaultMaxMergeRegionKeys   = 200000
 	defaultSplitMergeInterval   = 1 * time.Hour
+	defaultSplitQPSThreshold    = 3000
+	defaultSplitHotDuration     = 3 * time.Minute
 	defaultPatrolRegionInterval = 100 * time.Millisecond
 	defaultMaxStoreDownTime     = 30 * time.Minute
 	defaultLeaderScheduleLimit  = 4
+	defaultLeaderScheduleBatch  = 1
 	defaultRegionScheduleLimit  = 4
 	defaultReplicaScheduleLimit = 8
 	defaultMergeScheduleLimit   = 8
 	defaultTolerantSizeRatio    = 5
 	defaultLowSpaceRatio        = 0.8
 	defaultHighSpaceRatio       = 0.6
+	defaultRegionStatsChange    = 0.05
+	defaultRegionCollectFactor  = 0.8
+	defaultHaltLowSpaceRatio    = 0.3
+	defaultHaltEtcdLatency      = time.Second
 )
 
 func (c *ScheduleConfig) adjust() error {
 	adjustUint64(&c.MaxSnapshotCount, defaultMaxSnapshotCount)
 	adjustUint64(&c.MaxPendingPeerCount, defaultMaxPendingPeerCount)
+	if c.MaxPendingCompactionBytes == 0 {
+		c.MaxPendingCompactionBytes = defaultMaxPendingCompaction
+	}
+	if c.MinSnapshotSpeed == 0 {
+		c.MinSnapshotSpeed = defaultMinSnapshotSpeed
+	}
 	adjustUint64(&c.MaxMergeRegionSize, defaultMaxMergeRegionSize)
 	adjustUint64(&c.MaxMergeRegionKeys, defaultMaxMergeRegionKeys)
 	adjustDuration(&c.SplitMergeInterval, defaultSplitMergeInterval)
+	adjustUint64(&c.SplitQPSThreshold, defaultSplitQPSThreshold)
+	adjustDuration(&c.SplitHotDuration, defaultSplitHotDuration)
 	adjustDuration(&c.PatrolRegionInterval, defaultPatrolRegionInterval)
 	adjustDuration(&c.MaxStoreDownTime, defaultMaxStoreDownTime)
 	adjustUint64(&c.LeaderScheduleLimit, defaultLeaderScheduleLimit)
+	adjustUint64(&c.LeaderScheduleBatch, defaultLeaderScheduleBatch)
 	adjustUint64(&c.RegionScheduleLimit, defaultRegionScheduleLimit)
 	adjustUint64(&c.ReplicaScheduleLimit, defaultReplicaScheduleLimit)
 	adjustUint64(&c.MergeScheduleLimit, defaultMergeScheduleLimit)
 	adjustFloat64(&c.TolerantSizeRatio, defaultTolerantSizeRatio)
 	adjustFloat64(&c.LowSpaceRatio, defaultLowSpaceRatio)
 	adjustFloat64(&c.HighSpaceRatio, defaultHighSpaceRatio)
+	adjustFloat64(&c.RegionStatsChangeRatio, defaultRegionStatsChange)
+	adjustFloat64(&c.RegionCollectFactor, defaultRegionCollectFactor)
+	adjustFloat64(&c.HaltLowSpaceStoreRatio, defaultHaltLowSpaceRatio)
+	adjustDuration(&c.HaltEtcdLatency, defaultHaltEtcdLatency)
 	adjustSchedulers(&c.Schedulers, defaultSchedulers)
 
 	return c.validate()
@@ -522,6 +740,15 @@ func (c *ScheduleConfig) validate() error {
 	if c.LowSpaceRatio <= c.HighSpaceRatio {
 		return errors.New("low-space-ratio should be larger than high-space-ratio")
 	}
+	if c.RegionStatsChangeRatio < 0 {
+		return errors.New("region-stats-change-ratio should be nonnegative")
+	}
+	if c.RegionCollectFactor < 0 || c.RegionCollectFactor > 1 {
+		return errors.New("region-collect-factor should between 0 and 1")
+	}
+	if c.HaltLowSpaceStoreRatio < 0 || c.HaltLowSpaceStoreRatio > 1 {
+		return errors.New("halt-low-space-store-ratio should between 0 and 1")
+	}
 	return nil
 }
 
@@ -533,6 +760,9 @@ type SchedulerConfig struct {
 	Type    string   `toml:"type" json:"type"`
 	Args    []string `toml:"args,omitempty" json:"args"`
 	Disable bool     `toml:"disable" json:"disable"`
+	// Config is the JSON encoded config of the scheduler, set through the
+	// scheduler config API.
+	Config string `toml:"config,omitempty" json:"config,omitempty"`
 }
 
 var defaultSchedulers = SchedulerConfigs{
@@ -562,14 +792,26 @@ type ReplicationConfig struct {
 	// For example, ["zone", "rack"] means that we should place replicas to
 	// different zones first, then to different racks if we don't have enough zones.
 	LocationLabels typeutil.StringSlice `toml:"location-labels,omitempty" json:"location-labels"`
+
+	// IsolationLevel is the minimum isolation level required for the replicas
+	// of a region. It must be one of the location labels. For example, if
+	// IsolationLevel is "zone", schedulers will not move a replica into a zone
+	// that already has another replica of the same region.
+	IsolationLevel string `toml:"isolation-level,omitempty" json:"isolation-level"`
+
+	// StrictlyMatchLabel is true to reject the stores which miss any of the
+	// location labels or have labels not in the location labels.
+	StrictlyMatchLabel bool `toml:"strictly-match-label,omitempty" json:"strictly-match-label,string"`
 }
 
 func (c *ReplicationConfig) clone() *ReplicationConfig {
 	locationLabels := make(typeutil.StringSlice, len(c.LocationLabels))
 	copy(locationLabels, c.LocationLabels)
 	return &ReplicationConfig{
-		MaxReplicas:    c.MaxReplicas,
-		LocationLabels: locationLabels,
+		MaxReplicas:        c.MaxReplicas,
+		LocationLabels:     locationLabels,
+		IsolationLevel:     c.IsolationLevel,
+		StrictlyMatchLabel: c.StrictlyMatchLabel,
 	}
 }
 
@@ -580,6 +822,14 @@ func (c *ReplicationConfig) validate() error {
 			return err
 		}
 	}
+	if c.IsolationLevel != "" {
+		for _, label := range c.LocationLabels {
+			if label == c.IsolationLevel {
+				return nil
+			}
+		}
+		return errors.Errorf("isolation level %s is not in location labels %v", c.IsolationLevel, c.LocationLabels)
+	}
 	return nil
 }
 
@@ -600,6 +850,23 @@ type NamespaceConfig struct {
 	MergeScheduleLimit uint64 `json:"merge-schedule-limit"`
 	// MaxReplicas is the number of replicas for each region.
 	MaxReplicas uint64 `json:"max-replicas"`
+	// NamespaceQuota limits the region growth of the namespace.
+	NamespaceQuota
+}
+
+// NamespaceQuota is the quota of a namespace, which is enforced on the
+// operations creating regions or operators for the namespace. The zero values
+// mean no limit.
+type NamespaceQuota struct {
+	// MaxRegionCount is the max number of regions. The splits are rejected
+	// when the namespace reaches it.
+	MaxRegionCount uint64 `json:"max-region-count"`
+	// MaxTotalSize is the max approximate size of the regions in MB. The
+	// splits are rejected when the namespace reaches it.
+	MaxTotalSize uint64 `json:"max-total-size"`
+	// MaxOperatorRate is the max number of split and scatter operators
+	// created per second.
+	MaxOperatorRate float64 `json:"max-operator-rate"`
 }
 
 func (c *NamespaceConfig) adjust(opt *scheduleOption) {
@@ -618,6 +885,9 @@ type SecurityConfig struct {
 	CertPath string `toml:"cert-path" json:"cert-path"`
 	// KeyPath is the path of file that contains X509 key in PEM format.
 	KeyPath string `toml:"key-path" json:"key-path"`
+	// AdminToken is the bearer token to access the admin-only APIs, such as
+	// the debug APIs. The admin-only APIs are disabled if it is empty.
+	AdminToken string `toml:"admin-token" json:"-"`
 }
 
 // ToTLSConfig generatres tls config.
@@ -637,10 +907,406 @@ func (s SecurityConfig) ToTLSConfig() (*tls.Config, error) {
 	return tlsConfig, nil
 }
 
+// ToServerTLSConfig generates tls config for the PD API listeners, the clients
+// are verified by the CA like the etcd client listeners.
+func (s SecurityConfig) ToServerTLSConfig() (*tls.Config, error) {
+	if len(s.CertPath) == 0 && len(s.KeyPath) == 0 {
+		return nil, nil
+	}
+	tlsInfo := transport.TLSInfo{
+		CertFile:       s.CertPath,
+		KeyFile:        s.KeyPath,
+		TrustedCAFile:  s.CAPath,
+		ClientCertAuth: len(s.CAPath) != 0,
+	}
+	tlsConfig, err := tlsInfo.ServerConfig()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return tlsConfig, nil
+}
+
 // PDServerConfig is the configuration for pd server.
 type PDServerConfig struct {
 	// EnableRegionStorage enables the independent region storage.
 	EnableRegionStorage bool `toml:"enable-region-storage" json:"enable-region-storage"`
+	// DisableForwarding disables forwarding the leader-only requests received
+	// by followers to the leader.
+	DisableForwarding bool `toml:"disable-forwarding" json:"disable-forwarding"`
+	// LeaderPreferredZone is the zone to keep the PD leader in. The leader
+	// out of it resigns the leadership to a healthy member in it, whose zone
+	// label matches.
+	LeaderPreferredZone string `toml:"leader-preferred-zone" json:"leader-preferred-zone"`
+	// RegionHeartbeatWorkers is the count of workers to apply the region
+	// heartbeats, sharded by region ID. 0 means the heartbeats are applied by
+	// the heartbeat streams of stores. It takes effect when the cluster is
+	// started by the next leader.
+	RegionHeartbeatWorkers uint64 `toml:"region-heartbeat-workers" json:"region-heartbeat-workers"`
+	// SlowLogThreshold is the duration above which the HTTP and gRPC requests
+	// are logged as slow requests. 0 disables the slow log.
+	SlowLogThreshold typeutil.Duration `toml:"slow-log-threshold" json:"slow-log-threshold"`
+	// SlowLogSampleRate is the fraction of the slow requests to log, in (0, 1].
+	SlowLogSampleRate float64 `toml:"slow-log-sample-rate" json:"slow-log-sample-rate"`
+}
+
+func (c *PDServerConfig) validate() error {
+	if c.LeaderPreferredZone != "" {
+		if err := ValidateLabelString(c.LeaderPreferredZone); err != nil {
+			return err
+		}
+	}
+	if c.SlowLogThreshold.Duration < 0 {
+		return errors.Errorf("slow-log-threshold should not be negative, got %v", c.SlowLogThreshold.Duration)
+	}
+	if c.SlowLogSampleRate <= 0 || c.SlowLogSampleRate > 1 {
+		return errors.Errorf("slow-log-sample-rate should be in (0, 1], got %v", c.SlowLogSampleRate)
+	}
+	return nil
+}
+
+// MetaSnapshotConfig is the configuration for uploading metadata snapshots to
+// external storage.
+type MetaSnapshotConfig struct {
+	// Storage is the URL of the external storage, such as `/path/to/dir`,
+	// `s3://bucket/prefix` or `gcs://bucket/prefix`. Empty means no snapshot
+	// is taken.
+	Storage string `toml:"storage" json:"storage"`
+	// Interval is the interval to take a snapshot.
+	Interval typeutil.Duration `toml:"interval" json:"interval"`
+	// MaxSnapshots is the count of snapshots to retain, the oldest ones are
+	// deleted.
+	MaxSnapshots uint64 `toml:"max-snapshots" json:"max-snapshots"`
+}
+
+// EtcdMaintenanceConfig is the configuration for compacting and
+// defragmenting the embedded etcd by the leader.
+type EtcdMaintenanceConfig struct {
+	// CheckInterval is the interval to check the backend of etcd members.
+	CheckInterval typeutil.Duration `toml:"check-interval" json:"check-interval"`
+	// CompactRevisionThreshold is the count of revisions since the last
+	// compaction to compact etcd to the current revision. 0 means etcd is
+	// only compacted by auto-compaction.
+	CompactRevisionThreshold uint64 `toml:"compact-revision-threshold" json:"compact-revision-threshold"`
+	// DefragThreshold is the size of the space wasted by the fragmentation of
+	// a member to defragment it. 0 means members are not defragmented
+	// automatically.
+	DefragThreshold typeutil.ByteSize `toml:"defrag-threshold" json:"defrag-threshold"`
+	// DefragWindows are the daily time windows in the local time, such as
+	// "02:00-04:00", in which members are defragmented automatically.
+	DefragWindows []string `toml:"defrag-windows" json:"defrag-windows"`
+}
+
+func (c *EtcdMaintenanceConfig) adjust() error {
+	adjustDuration(&c.CheckInterval, defaultEtcdMaintenanceInterval)
+	for _, w := range c.DefragWindows {
+		if _, err := parseMaintenanceWindow(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inDefragWindow reports whether t is in any of the defragmentation windows.
+func (c *EtcdMaintenanceConfig) inDefragWindow(t time.Time) bool {
+	for _, w := range c.DefragWindows {
+		if window, err := parseMaintenanceWindow(w); err == nil && window.contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// maintenanceWindow is a daily time window, the start and end are the
+// minutes from midnight. It crosses midnight if the end is before the start.
+type maintenanceWindow struct {
+	start, end int
+}
+
+func parseMaintenanceWindow(s string) (maintenanceWindow, error) {
+	parts := strings.Split(s, "-")
+	if len(parts) != 2 {
+		return maintenanceWindow{}, errors.Errorf("invalid maintenance window %q, should be like 02:00-04:00", s)
+	}
+	var minutes [2]int
+	for i, part := range parts {
+		t, err := time.Parse("15:04", strings.TrimSpace(part))
+		if err != nil {
+			return maintenanceWindow{}, errors.Errorf("invalid maintenance window %q, should be like 02:00-04:00", s)
+		}
+		minutes[i] = t.Hour()*60 + t.Minute()
+	}
+	if minutes[0] == minutes[1] {
+		return maintenanceWindow{}, errors.Errorf("empty maintenance window %q", s)
+	}
+	return maintenanceWindow{start: minutes[0], end: minutes[1]}, nil
+}
+
+func (w maintenanceWindow) contains(t time.Time) bool {
+	m := t.Hour()*60 + t.Minute()
+	if w.start < w.end {
+		return m >= w.start && m < w.end
+	}
+	return m >= w.start || m < w.end
+}
+
+// RegionStorageConfig is the configuration for persisting regions in the
+// independent region storage.
+type RegionStorageConfig struct {
+	// FlushPolicy is "sync" to write every region heartbeat to the disk
+	// synchronously, or "interval" to write regions in batches.
+	FlushPolicy string `toml:"flush-policy" json:"flush-policy"`
+	// FlushInterval is the max duration for a region to wait to be written,
+	// for the "interval" policy.
+	FlushInterval typeutil.Duration `toml:"flush-interval" json:"flush-interval"`
+	// FlushBatchSize is the count of regions to trigger a write, for the
+	// "interval" policy.
+	FlushBatchSize uint64 `toml:"flush-batch-size" json:"flush-batch-size"`
+	// FlushSync is true to fsync after each batch is written, for the
+	// "interval" policy.
+	FlushSync bool `toml:"flush-sync" json:"flush-sync"`
+}
+
+func (c *RegionStorageConfig) adjust() error {
+	adjustString(&c.FlushPolicy, core.FlushPolicyInterval)
+	if c.FlushPolicy != core.FlushPolicyInterval && c.FlushPolicy != core.FlushPolicySync {
+		return errors.Errorf("unknown region flush policy %q", c.FlushPolicy)
+	}
+	adjustDuration(&c.FlushInterval, defaultRegionFlushInterval)
+	adjustUint64(&c.FlushBatchSize, defaultRegionFlushBatchSize)
+	return nil
+}
+
+func (c *RegionStorageConfig) options() core.RegionKVOptions {
+	return core.RegionKVOptions{
+		FlushPolicy:   c.FlushPolicy,
+		FlushInterval: c.FlushInterval.Duration,
+		BatchSize:     int(c.FlushBatchSize),
+		SyncBatch:     c.FlushSync,
+	}
+}
+
+const (
+	// replicationModeMajority requires a majority of replicas to commit.
+	replicationModeMajority = "majority"
+	// replicationModeDRAutoSync is for a cluster deployed in a primary zone
+	// and a DR zone. Logs must be committed in both zones while the DR zone
+	// is healthy.
+	replicationModeDRAutoSync = "dr-auto-sync"
+)
+
+// ReplicationModeConfig is the configuration for the replication mode.
+type ReplicationModeConfig struct {
+	// ReplicationMode is "majority" or "dr-auto-sync".
+	ReplicationMode string                      `toml:"replication-mode" json:"replication-mode"`
+	DRAutoSync      DRAutoSyncReplicationConfig `toml:"dr-auto-sync" json:"dr-auto-sync"`
+}
+
+// DRAutoSyncReplicationConfig is the configuration for the dr-auto-sync mode.
+type DRAutoSyncReplicationConfig struct {
+	// LabelKey is the store label to distinguish the zones.
+	LabelKey string `toml:"label-key" json:"label-key"`
+	// Primary and DR are the label values of the two zones.
+	Primary string `toml:"primary" json:"primary"`
+	DR      string `toml:"dr" json:"dr"`
+	// DRReplicas is the count of replicas in the DR zone. The DR zone needs
+	// at least as many healthy stores to replicate synchronously.
+	DRReplicas int `toml:"dr-replicas" json:"dr-replicas"`
+	// WaitStoreTimeout is the duration for a store without heartbeats to be
+	// treated as down.
+	WaitStoreTimeout typeutil.Duration `toml:"wait-store-timeout" json:"wait-store-timeout"`
+	// WaitSyncTimeout is sent to TiKV as the duration to wait for the DR zone
+	// to commit logs in the sync state. The state switches back to sync once
+	// all regions in the DR zone report in sync, rather than after it.
+	WaitSyncTimeout typeutil.Duration `toml:"wait-sync-timeout" json:"wait-sync-timeout"`
+}
+
+func (c *ReplicationModeConfig) adjust() error {
+	adjustString(&c.ReplicationMode, replicationModeMajority)
+	switch c.ReplicationMode {
+	case replicationModeMajority:
+	case replicationModeDRAutoSync:
+		dr := &c.DRAutoSync
+		if dr.LabelKey == "" || dr.Primary == "" || dr.DR == "" {
+			return errors.New("label-key, primary and dr are required for dr-auto-sync replication mode")
+		}
+		if dr.Primary == dr.DR {
+			return errors.Errorf("primary and dr should be different zones, but both are %s", dr.Primary)
+		}
+	default:
+		return errors.Errorf("unknown replication mode %q", c.ReplicationMode)
+	}
+	adjustDuration(&c.DRAutoSync.WaitStoreTimeout, defaultDRWaitStoreTimeout)
+	adjustDuration(&c.DRAutoSync.WaitSyncTimeout, defaultDRWaitSyncTimeout)
+	return nil
+}
+
+// EventLogConfig is the configuration for the log of cluster events, such as
+// store state changes and region splits.
+type EventLogConfig struct {
+	// MaxCount is the count of events to retain, the oldest ones are deleted.
+	MaxCount uint64 `toml:"max-count" json:"max-count"`
+	// Webhook is the URL to post each event to as JSON. Empty means no
+	// webhook.
+	Webhook string `toml:"webhook" json:"webhook"`
+	// WebhookTimeout is the timeout to post an event to the webhook.
+	WebhookTimeout typeutil.Duration `toml:"webhook-timeout" json:"webhook-timeout"`
+}
+
+// The types of the master key.
+const (
+	masterKeyTypeFile = "file"
+	masterKeyTypeKMS  = "kms"
+)
+
+const defaultDataKeyRotationPeriod = 7 * 24 * time.Hour
+
+// EncryptionConfig is the configuration for the data keys which PD serves to
+// TiKV to encrypt the data at rest.
+type EncryptionConfig struct {
+	// DataKeyRotationPeriod is the period after which a new data key is
+	// created to encrypt the new data.
+	DataKeyRotationPeriod typeutil.Duration `toml:"data-key-rotation-period" json:"data-key-rotation-period"`
+	// ClientToken is the bearer token TiKV sends to get the data keys.
+	ClientToken string `toml:"client-token" json:"-"`
+	// MasterKey encrypts the data keys persisted in etcd. The data keys are
+	// not served if its type is empty.
+	MasterKey MasterKeyConfig `toml:"master-key" json:"master-key"`
+}
+
+// MasterKeyConfig is the configuration for the master key.
+type MasterKeyConfig struct {
+	// Type is "file" or "kms".
+	Type string `toml:"type" json:"type"`
+	// Path is the file containing the hex encoded 256 bits key, for the file
+	// type.
+	Path string `toml:"path" json:"path"`
+	// Endpoint is the address of the KMS for the kms type, which serves the
+	// API of the Vault transit secrets engine. KeyName is the name of the
+	// transit key, and Token is the Vault token.
+	Endpoint string `toml:"endpoint" json:"endpoint"`
+	KeyName  string `toml:"key-name" json:"key-name"`
+	Token    string `toml:"token" json:"-"`
+}
+
+func (c *EncryptionConfig) adjust() error {
+	adjustDuration(&c.DataKeyRotationPeriod, defaultDataKeyRotationPeriod)
+	switch c.MasterKey.Type {
+	case "":
+		return nil
+	case masterKeyTypeFile:
+		if c.MasterKey.Path == "" {
+			return errors.New("path is required for file master key")
+		}
+	case masterKeyTypeKMS:
+		if c.MasterKey.Endpoint == "" || c.MasterKey.KeyName == "" {
+			return errors.New("endpoint and key-name are required for kms master key")
+		}
+	default:
+		return errors.Errorf("unknown master key type %q", c.MasterKey.Type)
+	}
+	if c.ClientToken == "" {
+		return errors.New("client-token is required to serve the data keys")
+	}
+	return nil
+}
+
+// SchedulerPluginConfig is the configuration for the scheduler plugins, which
+// are loaded and unloaded by the API at runtime.
+type SchedulerPluginConfig struct {
+	// Dir is the directory of the plugins. Empty means no plugin is loaded.
+	Dir string `toml:"dir" json:"dir"`
+	// AllowList are the file names of the plugins in Dir which can be loaded.
+	AllowList []string `toml:"allow-list" json:"allow-list"`
+}
+
+// pluginPath returns the path of the plugin with the file name, which must be
+// in the allow list.
+func (c *SchedulerPluginConfig) pluginPath(name string) (string, error) {
+	for _, allowed := range c.AllowList {
+		if allowed == name {
+			return filepath.Join(c.Dir, name), nil
+		}
+	}
+	return "", errors.Errorf("scheduler plugin %q is not in the allow list", name)
+}
+
+func (c *SchedulerPluginConfig) validate() error {
+	if c.Dir == "" && len(c.AllowList) > 0 {
+		return errors.New("scheduler-plugin.dir is required to load the plugins")
+	}
+	for _, name := range c.AllowList {
+		if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+			return errors.Errorf("invalid scheduler plugin file name %q", name)
+		}
+	}
+	return nil
+}
+
+// LabelSchemaConfig is the schema of the store labels, which is checked when
+// the stores are registered or their labels are updated.
+type LabelSchemaConfig struct {
+	// Labels are the allowed label keys. Any label is allowed if it is empty.
+	Labels []LabelSchema `toml:"labels" json:"labels"`
+}
+
+// LabelSchema is an allowed label key, with the pattern its values should
+// match.
+type LabelSchema struct {
+	Key string `toml:"key" json:"key"`
+	// ValuePattern is a regular expression matching the whole value. Any
+	// value is allowed if it is empty.
+	ValuePattern string `toml:"value-pattern" json:"value-pattern"`
+}
+
+func (c *LabelSchemaConfig) validate() error {
+	keys := make(map[string]struct{})
+	for _, l := range c.Labels {
+		if err := ValidateLabelString(l.Key); err != nil {
+			return err
+		}
+		if _, ok := keys[strings.ToLower(l.Key)]; ok {
+			return errors.Errorf("duplicated label key %q in label schema", l.Key)
+		}
+		keys[strings.ToLower(l.Key)] = struct{}{}
+		if _, err := l.valueRegexp(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *LabelSchema) valueRegexp() (*regexp.Regexp, error) {
+	if l.ValuePattern == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile("^(?:" + l.ValuePattern + ")$")
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid value pattern of label %q", l.Key)
+	}
+	return re, nil
+}
+
+// check checks the label against the schema. It returns the reason if the
+// label is invalid.
+func (c *LabelSchemaConfig) check(key, value string) string {
+	if len(c.Labels) == 0 {
+		return ""
+	}
+	for i := range c.Labels {
+		l := &c.Labels[i]
+		if !strings.EqualFold(l.Key, key) {
+			continue
+		}
+		re, err := l.valueRegexp()
+		if err != nil {
+			return err.Error()
+		}
+		if re != nil && !re.MatchString(value) {
+			return fmt.Sprintf("value does not match %q", l.ValuePattern)
+		}
+		return ""
+	}
+	return "key is not in the label schema"
 }
 
 // StoreLabel is the config item of LabelPropertyConfig.
@@ -662,6 +1328,94 @@ func (c LabelPropertyConfig) clone() LabelPropertyConfig {
 	return m
 }
 
+// MergeProtectionRule protects the regions split in a key range, or in the
+// range of a table, from being merged until the interval passes. The rule is
+// either for a key range or for a table.
+type MergeProtectionRule struct {
+	// StartKey and EndKey are hex encoded, an empty EndKey means the end of
+	// the key space.
+	StartKey string            `toml:"start-key,omitempty" json:"start-key,omitempty"`
+	EndKey   string            `toml:"end-key,omitempty" json:"end-key,omitempty"`
+	TableID  int64             `toml:"table-id,omitempty" json:"table-id,omitempty"`
+	Interval typeutil.Duration `toml:"interval" json:"interval"`
+}
+
+// keyRange returns the key range protected by the rule.
+func (r *MergeProtectionRule) keyRange() ([]byte, []byte, error) {
+	if r.TableID != 0 {
+		start, end := table.TableRange(r.TableID)
+		return start, end, nil
+	}
+	start, err := hex.DecodeString(r.StartKey)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "invalid start-key")
+	}
+	end, err := hex.DecodeString(r.EndKey)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "invalid end-key")
+	}
+	return start, end, nil
+}
+
+func (r *MergeProtectionRule) validate() error {
+	if r.TableID != 0 && (r.StartKey != "" || r.EndKey != "") {
+		return errors.New("table-id and key range can not be set at the same time")
+	}
+	if r.TableID < 0 {
+		return errors.Errorf("invalid table-id %d", r.TableID)
+	}
+	if r.Interval.Duration <= 0 {
+		return errors.New("interval should be positive")
+	}
+	start, end, err := r.keyRange()
+	if err != nil {
+		return err
+	}
+	if len(end) > 0 && bytes.Compare(start, end) >= 0 {
+		return errors.New("start-key should be less than end-key")
+	}
+	return nil
+}
+
+// MergeProtectionConfig is the config section which maps the names to the
+// merge protection rules.
+type MergeProtectionConfig map[string]*MergeProtectionRule
+
+func (c MergeProtectionConfig) clone() MergeProtectionConfig {
+	m := make(map[string]*MergeProtectionRule, len(c))
+	for k, r := range c {
+		m[k] = r
+	}
+	return m
+}
+
+func (c MergeProtectionConfig) validate() error {
+	for name, r := range c {
+		if err := r.validate(); err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("merge protection rule %s", name))
+		}
+	}
+	return nil
+}
+
+// getInterval returns the longest interval of the rules overlapping with the
+// key range.
+func (c MergeProtectionConfig) getInterval(startKey, endKey []byte) time.Duration {
+	var interval time.Duration
+	for _, r := range c {
+		start, end, err := r.keyRange()
+		if err != nil {
+			continue
+		}
+		if (len(end) == 0 || bytes.Compare(startKey, end) < 0) &&
+			(len(endKey) == 0 || bytes.Compare(start, endKey) < 0) &&
+			r.Interval.Duration > interval {
+			interval = r.Interval.Duration
+		}
+	}
+	return interval
+}
+
 // ParseUrls parse a string into multiple urls.
 // Export for api.
 func ParseUrls(s string) ([]url.URL, error) {
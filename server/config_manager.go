@@ -0,0 +1,307 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pingcap/pd/pkg/configpb"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// configEntry is a config item set by users. Name is the dotted path of the
+// item and Value is in TOML format.
+type configEntry struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// componentConfig is the config of a component stored in PD. The config of an
+// instance is the default config overwritten by the global entries, then by
+// the entries of the instance.
+type componentConfig struct {
+	Version uint64                   `json:"version"`
+	Default string                   `json:"default"`
+	Global  []configEntry            `json:"global"`
+	Local   map[string][]configEntry `json:"local"`
+}
+
+// render generates the config of the component instance in TOML format.
+// Entries which are not in the default config any more are ignored.
+func (c *componentConfig) render(componentID string) (string, error) {
+	m := make(map[string]interface{})
+	if _, err := toml.Decode(c.Default, &m); err != nil {
+		return "", errors.WithStack(err)
+	}
+	entries := append(append([]configEntry{}, c.Global...), c.Local[componentID]...)
+	for _, e := range entries {
+		if err := setConfigItem(m, e); err != nil {
+			log.Warnf("ignore config entry %s: %v", e.Name, err)
+		}
+	}
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(m); err != nil {
+		return "", errors.WithStack(err)
+	}
+	return buf.String(), nil
+}
+
+// setConfigItem sets the config item in m. The item must exist in m and the
+// new value must have the same type.
+func setConfigItem(m map[string]interface{}, e configEntry) error {
+	v := make(map[string]interface{})
+	if _, err := toml.Decode("v = "+e.Value, &v); err != nil {
+		return errors.Errorf("invalid value %q", e.Value)
+	}
+	value := v["v"]
+
+	names := strings.Split(e.Name, ".")
+	for _, name := range names[:len(names)-1] {
+		sub, ok := m[name].(map[string]interface{})
+		if !ok {
+			return errors.Errorf("unknown config item %q", e.Name)
+		}
+		m = sub
+	}
+	name := names[len(names)-1]
+	old, ok := m[name]
+	if !ok {
+		return errors.Errorf("unknown config item %q", e.Name)
+	}
+	if reflect.TypeOf(old) != reflect.TypeOf(value) {
+		return errors.Errorf("mismatch type of config item %q, need %T but got %T", e.Name, old, value)
+	}
+	m[name] = value
+	return nil
+}
+
+// mergeConfigEntries replaces the entries with the same name and appends the
+// others.
+func mergeConfigEntries(entries []configEntry, updates []configEntry) []configEntry {
+	merged := append([]configEntry{}, entries...)
+	for _, u := range updates {
+		found := false
+		for i := range merged {
+			if merged[i].Name == u.Name {
+				merged[i].Value = u.Value
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, u)
+		}
+	}
+	return merged
+}
+
+// configManager manages the configs of other components, such as TiKV and
+// TiDB. A component registers its default config when it starts, then fetches
+// the config with the version it knows to check whether it is updated.
+type configManager struct {
+	sync.Mutex
+	kv *core.KV
+}
+
+func newConfigManager(kv *core.KV) *configManager {
+	return &configManager{kv: kv}
+}
+
+func configStatus(code configpb.StatusCode, err error) *configpb.Status {
+	status := &configpb.Status{Code: code}
+	if err != nil {
+		status.Message = err.Error()
+	}
+	return status
+}
+
+func (m *configManager) load(component string) (*componentConfig, bool, error) {
+	cfg := &componentConfig{}
+	ok, err := m.kv.LoadComponentConfig(component, cfg)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return cfg, true, nil
+}
+
+// create registers the default config of the component. The default config is
+// replaced if it is changed, but the entries set by users are kept.
+func (m *configManager) create(component, componentID, defaultConfig string) (*componentConfig, *configpb.Status) {
+	m.Lock()
+	defer m.Unlock()
+
+	if component == "" {
+		return nil, configStatus(configpb.StatusCode_UNKNOWN, errors.New("component is empty"))
+	}
+	if _, err := toml.Decode(defaultConfig, &map[string]interface{}{}); err != nil {
+		return nil, configStatus(configpb.StatusCode_INVALID_ENTRY, errors.WithStack(err))
+	}
+	cfg, ok, err := m.load(component)
+	if err != nil {
+		return nil, configStatus(configpb.StatusCode_UNKNOWN, err)
+	}
+	if !ok {
+		cfg = &componentConfig{Local: make(map[string][]configEntry)}
+	}
+	if cfg.Default != defaultConfig {
+		cfg.Default = defaultConfig
+		cfg.Version++
+		if err = m.kv.SaveComponentConfig(component, cfg); err != nil {
+			return nil, configStatus(configpb.StatusCode_UNKNOWN, err)
+		}
+		log.Infof("default config of component %s is registered by %s, version %d", component, componentID, cfg.Version)
+	}
+	return cfg, configStatus(configpb.StatusCode_OK, nil)
+}
+
+// get returns the config of the component. The status is NOT_CHANGE if the
+// version is the latest.
+func (m *configManager) get(component string, version uint64) (*componentConfig, *configpb.Status) {
+	m.Lock()
+	defer m.Unlock()
+
+	cfg, ok, err := m.load(component)
+	if err != nil {
+		return nil, configStatus(configpb.StatusCode_UNKNOWN, err)
+	}
+	if !ok {
+		return nil, configStatus(configpb.StatusCode_COMPONENT_NOT_FOUND, errors.Errorf("component %s not found", component))
+	}
+	if cfg.Version == version {
+		return cfg, configStatus(configpb.StatusCode_NOT_CHANGE, nil)
+	}
+	return cfg, configStatus(configpb.StatusCode_OK, nil)
+}
+
+// update sets the config entries of the component instance, or of all
+// instances if componentID is empty. The version must be the latest.
+func (m *configManager) update(component, componentID string, version uint64, entries []configEntry) (*componentConfig, *configpb.Status) {
+	m.Lock()
+	defer m.Unlock()
+
+	cfg, ok, err := m.load(component)
+	if err != nil {
+		return nil, configStatus(configpb.StatusCode_UNKNOWN, err)
+	}
+	if !ok {
+		return nil, configStatus(configpb.StatusCode_COMPONENT_NOT_FOUND, errors.Errorf("component %s not found", component))
+	}
+	if cfg.Version != version {
+		return cfg, configStatus(configpb.StatusCode_WRONG_VERSION, errors.Errorf("version %d is stale, the latest is %d", version, cfg.Version))
+	}
+	defaults := make(map[string]interface{})
+	if _, err = toml.Decode(cfg.Default, &defaults); err != nil {
+		return nil, configStatus(configpb.StatusCode_UNKNOWN, errors.WithStack(err))
+	}
+	for _, e := range entries {
+		if err = setConfigItem(defaults, e); err != nil {
+			return cfg, configStatus(configpb.StatusCode_INVALID_ENTRY, err)
+		}
+	}
+
+	if componentID == "" {
+		cfg.Global = mergeConfigEntries(cfg.Global, entries)
+	} else {
+		if cfg.Local == nil {
+			cfg.Local = make(map[string][]configEntry)
+		}
+		cfg.Local[componentID] = mergeConfigEntries(cfg.Local[componentID], entries)
+	}
+	cfg.Version++
+	if err = m.kv.SaveComponentConfig(component, cfg); err != nil {
+		return nil, configStatus(configpb.StatusCode_UNKNOWN, err)
+	}
+	log.Infof("config of component %s %s is updated with %v, version %d", component, componentID, entries, cfg.Version)
+	return cfg, configStatus(configpb.StatusCode_OK, nil)
+}
+
+// configService implements gRPC ConfigServer.
+type configService struct {
+	s *Server
+}
+
+// renderConfig fills the version and config of the instance to the response
+// if the status is OK.
+func renderConfig(cfg *componentConfig, componentID string, status *configpb.Status) (uint64, string, *configpb.Status) {
+	if cfg == nil {
+		return 0, "", status
+	}
+	if status.GetCode() != configpb.StatusCode_OK {
+		return cfg.Version, "", status
+	}
+	config, err := cfg.render(componentID)
+	if err != nil {
+		return cfg.Version, "", configStatus(configpb.StatusCode_UNKNOWN, err)
+	}
+	return cfg.Version, config, status
+}
+
+// Create implements gRPC ConfigServer.
+func (c *configService) Create(ctx context.Context, request *configpb.CreateRequest) (*configpb.CreateResponse, error) {
+	if err := c.s.validateRequest(request.GetHeader()); err != nil {
+		return nil, err
+	}
+	cfg, status := c.s.configManager.create(request.GetComponent(), request.GetComponentId(), request.GetConfig())
+	version, config, status := renderConfig(cfg, request.GetComponentId(), status)
+	return &configpb.CreateResponse{
+		Header:  c.s.header(),
+		Status:  status,
+		Version: version,
+		Config:  config,
+	}, nil
+}
+
+// Get implements gRPC ConfigServer.
+func (c *configService) Get(ctx context.Context, request *configpb.GetRequest) (*configpb.GetResponse, error) {
+	if err := c.s.validateRequest(request.GetHeader()); err != nil {
+		return nil, err
+	}
+	cfg, status := c.s.configManager.get(request.GetComponent(), request.GetVersion())
+	version, config, status := renderConfig(cfg, request.GetComponentId(), status)
+	return &configpb.GetResponse{
+		Header:  c.s.header(),
+		Status:  status,
+		Version: version,
+		Config:  config,
+	}, nil
+}
+
+// Update implements gRPC ConfigServer.
+func (c *configService) Update(ctx context.Context, request *configpb.UpdateRequest) (*configpb.UpdateResponse, error) {
+	if err := c.s.validateRequest(request.GetHeader()); err != nil {
+		return nil, err
+	}
+	if err := c.s.checkMaintenance(); err != nil {
+		return nil, err
+	}
+	entries := make([]configEntry, 0, len(request.GetEntries()))
+	for _, e := range request.GetEntries() {
+		entries = append(entries, configEntry{Name: e.GetName(), Value: e.GetValue()})
+	}
+	cfg, status := c.s.configManager.update(request.GetComponent(), request.GetComponentId(), request.GetVersion(), entries)
+	version, config, status := renderConfig(cfg, request.GetComponentId(), status)
+	return &configpb.UpdateResponse{
+		Header:  c.s.header(),
+		Status:  status,
+		Version: version,
+		Config:  config,
+	}, nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	. "github.com/pingcap/check"
+	"github.com/pingcap/pd/pkg/configpb"
+	"google.golang.org/grpc"
+)
+
+var _ = Suite(&testConfigManagerSuite{})
+
+type testConfigManagerSuite struct{}
+
+const testComponentConfig = `
+log-level = "info"
+
+[raftstore]
+sync-log = true
+capacity = 100
+`
+
+func (s *testConfigManagerSuite) TestConfigService(c *C) {
+	_, svr, cleanup, err := NewTestServer()
+	c.Assert(err, IsNil)
+	defer cleanup()
+	mustWaitLeader(c, []*Server{svr})
+
+	conn, err := grpc.Dial(strings.TrimPrefix(svr.GetAddr(), "http://"), grpc.WithInsecure())
+	c.Assert(err, IsNil)
+	defer conn.Close()
+	client := configpb.NewConfigClient(conn)
+	header := newRequestHeader(svr.clusterID)
+	ctx := context.Background()
+
+	getResp, err := client.Get(ctx, &configpb.GetRequest{Header: header, Component: "tikv", ComponentId: "tikv1"})
+	c.Assert(err, IsNil)
+	c.Assert(getResp.GetStatus().GetCode(), Equals, configpb.StatusCode_COMPONENT_NOT_FOUND)
+
+	createResp, err := client.Create(ctx, &configpb.CreateRequest{Header: header, Component: "tikv", ComponentId: "tikv1", Config: testComponentConfig})
+	c.Assert(err, IsNil)
+	c.Assert(createResp.GetStatus().GetCode(), Equals, configpb.StatusCode_OK)
+	version := createResp.GetVersion()
+	c.Assert(version, Equals, uint64(1))
+
+	getResp, err = client.Get(ctx, &configpb.GetRequest{Header: header, Component: "tikv", ComponentId: "tikv1", Version: version})
+	c.Assert(err, IsNil)
+	c.Assert(getResp.GetStatus().GetCode(), Equals, configpb.StatusCode_NOT_CHANGE)
+	c.Assert(getResp.GetConfig(), Equals, "")
+
+	// Update with a stale version.
+	updateResp, err := client.Update(ctx, &configpb.UpdateRequest{Header: header, Component: "tikv", Version: version - 1,
+		Entries: []*configpb.ConfigEntry{{Name: "raftstore.sync-log", Value: "false"}}})
+	c.Assert(err, IsNil)
+	c.Assert(updateResp.GetStatus().GetCode(), Equals, configpb.StatusCode_WRONG_VERSION)
+
+	// Update unknown item or with mismatch type.
+	for _, e := range []*configpb.ConfigEntry{
+		{Name: "raftstore.unknown", Value: "1"},
+		{Name: "raftstore.capacity", Value: `"1GB"`},
+		{Name: "log-level", Value: "debug"},
+	} {
+		updateResp, err = client.Update(ctx, &configpb.UpdateRequest{Header: header, Component: "tikv", Version: version,
+			Entries: []*configpb.ConfigEntry{e}})
+		c.Assert(err, IsNil)
+		c.Assert(updateResp.GetStatus().GetCode(), Equals, configpb.StatusCode_INVALID_ENTRY)
+	}
+
+	// Update all instances then a single instance.
+	updateResp, err = client.Update(ctx, &configpb.UpdateRequest{Header: header, Component: "tikv", Version: version,
+		Entries: []*configpb.ConfigEntry{{Name: "raftstore.sync-log", Value: "false"}}})
+	c.Assert(err, IsNil)
+	c.Assert(updateResp.GetStatus().GetCode(), Equals, configpb.StatusCode_OK)
+	version = updateResp.GetVersion()
+	updateResp, err = client.Update(ctx, &configpb.UpdateRequest{Header: header, Component: "tikv", ComponentId: "tikv2", Version: version,
+		Entries: []*configpb.ConfigEntry{{Name: "raftstore.capacity", Value: "200"}}})
+	c.Assert(err, IsNil)
+	c.Assert(updateResp.GetStatus().GetCode(), Equals, configpb.StatusCode_OK)
+	version = updateResp.GetVersion()
+	c.Assert(version, Equals, uint64(3))
+
+	for id, capacity := range map[string]int64{"tikv1": 100, "tikv2": 200} {
+		getResp, err = client.Get(ctx, &configpb.GetRequest{Header: header, Component: "tikv", ComponentId: id, Version: 1})
+		c.Assert(err, IsNil)
+		c.Assert(getResp.GetStatus().GetCode(), Equals, configpb.StatusCode_OK)
+		c.Assert(getResp.GetVersion(), Equals, version)
+		var cfg struct {
+			LogLevel  string `toml:"log-level"`
+			Raftstore struct {
+				SyncLog  bool  `toml:"sync-log"`
+				Capacity int64 `toml:"capacity"`
+			} `toml:"raftstore"`
+		}
+		_, err = toml.Decode(getResp.GetConfig(), &cfg)
+		c.Assert(err, IsNil)
+		c.Assert(cfg.LogLevel, Equals, "info")
+		c.Assert(cfg.Raftstore.SyncLog, IsFalse)
+		c.Assert(cfg.Raftstore.Capacity, Equals, capacity)
+	}
+
+	// Registering the same default config again does not change the version.
+	createResp, err = client.Create(ctx, &configpb.CreateRequest{Header: header, Component: "tikv", ComponentId: "tikv3", Config: testComponentConfig})
+	c.Assert(err, IsNil)
+	c.Assert(createResp.GetVersion(), Equals, version)
+}
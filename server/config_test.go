@@ -15,9 +15,14 @@ package server
 
 import (
 	"path"
+	"time"
 
+	"github.com/BurntSushi/toml"
 	. "github.com/pingcap/check"
+	"github.com/pingcap/pd/pkg/tracing"
+	"github.com/pingcap/pd/pkg/typeutil"
 	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/table"
 )
 
 var _ = Suite(&testConfigSuite{})
@@ -67,6 +72,15 @@ func (s *testConfigSuite) TestValidation(c *C) {
 	cfg.Log.File.Filename = path.Join(cfg.DataDir, "test")
 	c.Assert(cfg.validate(), NotNil)
 
+	// check tso config
+	c.Assert(cfg.TsoSaveGuard.Duration, Equals, time.Second)
+	cfg = NewConfig()
+	cfg.TsoSaveInterval.Duration = time.Second
+	cfg.TsoSaveGuard.Duration = time.Second
+	c.Assert(cfg.Adjust(nil), NotNil)
+	cfg.TsoSaveGuard.Duration = 500 * time.Millisecond
+	c.Assert(cfg.Adjust(nil), IsNil)
+
 	// check schedule config
 	cfg.Schedule.HighSpaceRatio = -0.1
 	c.Assert(cfg.Schedule.validate(), NotNil)
@@ -80,4 +94,91 @@ func (s *testConfigSuite) TestValidation(c *C) {
 	c.Assert(cfg.Schedule.validate(), IsNil)
 	cfg.Schedule.TolerantSizeRatio = -0.6
 	c.Assert(cfg.Schedule.validate(), NotNil)
+	cfg.Schedule.TolerantSizeRatio = 5
+	cfg.Schedule.RegionCollectFactor = 1.2
+	c.Assert(cfg.Schedule.validate(), NotNil)
+	cfg.Schedule.RegionCollectFactor = 0.8
+	c.Assert(cfg.Schedule.validate(), IsNil)
+
+	// check replication config
+	cfg.Replication.LocationLabels = []string{"zone", "host"}
+	cfg.Replication.IsolationLevel = "rack"
+	c.Assert(cfg.Replication.validate(), NotNil)
+	cfg.Replication.IsolationLevel = "zone"
+	c.Assert(cfg.Replication.validate(), IsNil)
+
+	// check pd server config
+	c.Assert(cfg.PDServerCfg.SlowLogSampleRate, Equals, 1.0)
+	cfg.PDServerCfg.SlowLogSampleRate = 0
+	c.Assert(cfg.PDServerCfg.validate(), NotNil)
+	cfg.PDServerCfg.SlowLogSampleRate = 0.1
+	c.Assert(cfg.PDServerCfg.validate(), IsNil)
+	cfg.PDServerCfg.SlowLogThreshold.Duration = -time.Second
+	c.Assert(cfg.PDServerCfg.validate(), NotNil)
+	cfg.PDServerCfg.SlowLogThreshold.Duration = time.Second
+	c.Assert(cfg.PDServerCfg.validate(), IsNil)
+
+	// check label schema
+	cfg.LabelSchema.Labels = []LabelSchema{{Key: "zone", ValuePattern: "z("}}
+	c.Assert(cfg.LabelSchema.validate(), NotNil)
+	cfg.LabelSchema.Labels = []LabelSchema{{Key: "zone", ValuePattern: "z[0-9]+"}, {Key: "Zone"}}
+	c.Assert(cfg.LabelSchema.validate(), NotNil)
+	cfg.LabelSchema.Labels = []LabelSchema{{Key: "zone", ValuePattern: "z[0-9]+"}, {Key: "host"}}
+	c.Assert(cfg.LabelSchema.validate(), IsNil)
+	c.Assert(cfg.LabelSchema.check("ZONE", "z1"), Equals, "")
+	c.Assert(cfg.LabelSchema.check("zone", "az1"), Not(Equals), "")
+	c.Assert(cfg.LabelSchema.check("host", "any"), Equals, "")
+	c.Assert(cfg.LabelSchema.check("rack", "r1"), Not(Equals), "")
+
+	cfg.SchedulerPlugin.AllowList = []string{"a.so"}
+	c.Assert(cfg.SchedulerPlugin.validate(), NotNil)
+	cfg.SchedulerPlugin.Dir = "/plugins"
+	c.Assert(cfg.SchedulerPlugin.validate(), IsNil)
+	path, err := cfg.SchedulerPlugin.pluginPath("a.so")
+	c.Assert(err, IsNil)
+	c.Assert(path, Equals, "/plugins/a.so")
+	_, err = cfg.SchedulerPlugin.pluginPath("b.so")
+	c.Assert(err, NotNil)
+	cfg.SchedulerPlugin.AllowList = []string{"a.so", "../b.so"}
+	c.Assert(cfg.SchedulerPlugin.validate(), NotNil)
+}
+
+func (s *testConfigSuite) TestTracingSampleRate(c *C) {
+	cfg := NewConfig()
+	meta, err := toml.Decode("[tracing]\nenable = true\nexporter = \"log\"\n", cfg)
+	c.Assert(err, IsNil)
+	c.Assert(cfg.Adjust(&meta), IsNil)
+	c.Assert(cfg.Tracing.SampleRate, Equals, tracing.DefaultSampleRate)
+
+	// The traces are not sampled if the rate is set to 0.
+	cfg = NewConfig()
+	meta, err = toml.Decode("[tracing]\nenable = true\nexporter = \"log\"\nsample-rate = 0.0\n", cfg)
+	c.Assert(err, IsNil)
+	c.Assert(cfg.Adjust(&meta), IsNil)
+	c.Assert(cfg.Tracing.SampleRate, Equals, 0.0)
+}
+
+func (s *testConfigSuite) TestMergeProtection(c *C) {
+	cfg := MergeProtectionConfig{
+		"r1": {StartKey: "61", EndKey: "63", Interval: typeutil.NewDuration(time.Minute)},
+		"r2": {StartKey: "62", Interval: typeutil.NewDuration(time.Hour)},
+	}
+	c.Assert(cfg.validate(), IsNil)
+	c.Assert(cfg.getInterval([]byte("a"), []byte("b")), Equals, time.Minute)
+	c.Assert(cfg.getInterval([]byte("b"), []byte("c")), Equals, time.Hour)
+	c.Assert(cfg.getInterval([]byte("z"), nil), Equals, time.Hour)
+	c.Assert(cfg.getInterval(nil, []byte("a")), Equals, time.Duration(0))
+
+	start, end := table.TableRange(10)
+	cfg = MergeProtectionConfig{"t10": {TableID: 10, Interval: typeutil.NewDuration(time.Minute)}}
+	c.Assert(cfg.validate(), IsNil)
+	c.Assert(cfg.getInterval(start, end), Equals, time.Minute)
+	c.Assert(cfg.getInterval(end, nil), Equals, time.Duration(0))
+
+	cfg["t10"].StartKey = "61"
+	c.Assert(cfg.validate(), NotNil)
+	cfg = MergeProtectionConfig{"r1": {StartKey: "63", EndKey: "61", Interval: typeutil.NewDuration(time.Minute)}}
+	c.Assert(cfg.validate(), NotNil)
+	cfg = MergeProtectionConfig{"r1": {StartKey: "61"}}
+	c.Assert(cfg.validate(), NotNil)
 }
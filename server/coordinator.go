@@ -15,10 +15,13 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
+	"github.com/opentracing/opentracing-go"
 	"github.com/pingcap/pd/pkg/logutil"
 	"github.com/pingcap/pd/server/core"
 	"github.com/pingcap/pd/server/namespace"
@@ -29,21 +32,33 @@ import (
 
 const (
 	runSchedulerCheckInterval = 3 * time.Second
-	collectFactor             = 0.8
 	collectTimeout            = 5 * time.Minute
 	maxScheduleRetries        = 10
 
-	regionheartbeatSendChanCap = 1024
-	hotRegionScheduleName      = "balance-hot-region-scheduler"
+	regionheartbeatSendQueueCap = 1024
+	hotRegionScheduleName       = "balance-hot-region-scheduler"
 
 	patrolScanRegionLimit = 128 // It takes about 14 minutes to iterate 1 million regions.
+	// urgentRepairCollectInterval is the interval to collect the regions to
+	// repair urgently, which are checked before the regular patrol.
+	urgentRepairCollectInterval = 10 * time.Second
 )
 
 var (
 	errSchedulerExisted  = errors.New("scheduler existed")
 	errSchedulerNotFound = errors.New("scheduler not found")
+	errDebugSchedulers   = errors.New("debug schedulers are disabled, set enable-debug-schedulers to enable them")
+	errSchedulerConfig   = errors.New("scheduler does not support config")
 )
 
+// debugSchedulerTypes are the types of schedulers which move leaders and
+// regions randomly, they are only allowed if enable-debug-schedulers is set.
+var debugSchedulerTypes = map[string]struct{}{
+	"shuffle-leader": {},
+	"shuffle-region": {},
+	"random-merge":   {},
+}
+
 type coordinator struct {
 	sync.RWMutex
 
@@ -58,22 +73,33 @@ type coordinator struct {
 	mergeChecker     *schedule.MergeChecker
 	schedulers       map[string]*scheduleController
 	opController     *schedule.OperatorController
+	guard            *scheduleGuard
+	quotas           *quotaController
 	classifier       namespace.Classifier
 	hbStreams        *heartbeatStreams
 }
 
 func newCoordinator(cluster *clusterInfo, hbStreams *heartbeatStreams, classifier namespace.Classifier) *coordinator {
 	ctx, cancel := context.WithCancel(context.Background())
+	opController := schedule.NewOperatorController(cluster, hbStreams)
+	if cluster.kv != nil {
+		opController.SetStorage(cluster.kv)
+	}
+	opController.SetClassifier(classifier)
+	replicaChecker := schedule.NewReplicaChecker(cluster, classifier)
+	replicaChecker.SetOperatorController(opController)
 	return &coordinator{
 		ctx:              ctx,
 		cancel:           cancel,
 		cluster:          cluster,
-		replicaChecker:   schedule.NewReplicaChecker(cluster, classifier),
+		replicaChecker:   replicaChecker,
 		regionScatterer:  schedule.NewRegionScatterer(cluster, classifier),
 		namespaceChecker: schedule.NewNamespaceChecker(cluster, classifier),
 		mergeChecker:     schedule.NewMergeChecker(cluster, classifier),
 		schedulers:       make(map[string]*scheduleController),
-		opController:     schedule.NewOperatorController(cluster, hbStreams),
+		opController:     opController,
+		guard:            newScheduleGuard(cluster),
+		quotas:           newQuotaController(cluster, classifier),
 		classifier:       classifier,
 		hbStreams:        hbStreams,
 	}
@@ -88,7 +114,11 @@ func (c *coordinator) patrolRegions() {
 
 	log.Info("coordinator: start patrol regions")
 	start := time.Now()
-	var key []byte
+	var (
+		key           []byte
+		urgent        []*core.RegionInfo
+		lastCollected time.Time
+	)
 	for {
 		select {
 		case <-timer.C:
@@ -97,6 +127,14 @@ func (c *coordinator) patrolRegions() {
 			return
 		}
 
+		// Repair the regions which are about to lose their quorum first, the
+		// scan below repairs the others in key order.
+		if time.Since(lastCollected) >= urgentRepairCollectInterval {
+			urgent = c.collectUrgentRepairRegions()
+			lastCollected = time.Now()
+		}
+		urgent = c.repairUrgentRegions(urgent)
+
 		regions := c.cluster.ScanRegions(key, patrolScanRegionLimit)
 		if len(regions) == 0 {
 			// reset scan key.
@@ -125,12 +163,67 @@ func (c *coordinator) patrolRegions() {
 	}
 }
 
+// collectUrgentRepairRegions returns the regions with down voters which are
+// about to lose their quorum. The regions which have lost their quorum are only
+// counted in the metrics, they are not repaired by adding voters, because the
+// conf changes can not be committed without the quorum.
+func (c *coordinator) collectUrgentRepairRegions() []*core.RegionInfo {
+	var (
+		urgent     []*core.RegionInfo
+		quorumLost []uint64
+	)
+	for _, region := range c.cluster.GetRegionStatsByType(downPeer) {
+		switch c.replicaChecker.GetRepairPriority(region) {
+		case schedule.RepairAtRisk:
+			urgent = append(urgent, region)
+		case schedule.RepairQuorumLost:
+			quorumLost = append(quorumLost, region.GetID())
+		}
+	}
+	sort.Slice(urgent, func(i, j int) bool { return urgent[i].GetID() < urgent[j].GetID() })
+	urgentRepairRegionGauge.WithLabelValues(schedule.RepairAtRisk.String()).Set(float64(len(urgent)))
+	urgentRepairRegionGauge.WithLabelValues(schedule.RepairQuorumLost.String()).Set(float64(len(quorumLost)))
+	if len(quorumLost) > 0 {
+		sort.Slice(quorumLost, func(i, j int) bool { return quorumLost[i] < quorumLost[j] })
+		log.Warnf("coordinator: %d regions have lost their quorum and need to be recovered manually: %v", len(quorumLost), quorumLost)
+	}
+	return urgent
+}
+
+// repairUrgentRegions creates the replica operators for the urgent regions in
+// order until the replica schedule limit is reached, and returns the regions
+// left to repair.
+func (c *coordinator) repairUrgentRegions(urgent []*core.RegionInfo) []*core.RegionInfo {
+	for len(urgent) > 0 {
+		if c.opController.OperatorCount(schedule.OpReplica) >= c.cluster.GetReplicaScheduleLimit() {
+			return urgent
+		}
+		region := c.cluster.GetRegion(urgent[0].GetID())
+		urgent = urgent[1:]
+		if region == nil || c.opController.GetOperator(region.GetID()) != nil {
+			continue
+		}
+		if op := c.replicaChecker.Check(region); op != nil {
+			c.opController.AddOperator(op)
+		}
+	}
+	return nil
+}
+
 func (c *coordinator) checkRegion(region *core.RegionInfo) bool {
+	span := opentracing.StartSpan("pd.CheckRegion")
+	span.SetTag("region_id", region.GetID())
+	span.SetTag("store_id", region.GetLeader().GetStoreId())
+	defer span.Finish()
+	opController := &tracedOperatorController{OperatorController: c.opController, span: span}
+
 	// If PD has restarted, it need to check learners added before and promote them.
 	// Don't check isRaftLearnerEnabled cause it may be disable learner feature but still some learners to promote.
-	opController := c.opController
+	// The learners which have not caught up or are unhealthy are not promoted,
+	// the unhealthy ones and the ones left by timeout operators are removed by
+	// the replica checker.
 	for _, p := range region.GetLearners() {
-		if region.GetPendingLearner(p.GetId()) != nil {
+		if !schedule.IsLearnerPromotable(c.cluster, region, p) {
 			continue
 		}
 		step := schedule.PromoteLearner{
@@ -188,6 +281,11 @@ func (c *coordinator) run() {
 	}
 	log.Info("coordinator: Run scheduler")
 
+	// Take over the operators which are running when the previous leader quits.
+	if err := c.opController.LoadOperators(); err != nil {
+		log.Errorf("can't load persisted operators: %v", err)
+	}
+
 	k := 0
 	scheduleCfg := c.cluster.opt.load().clone()
 	for _, schedulerCfg := range scheduleCfg.Schedulers {
@@ -202,6 +300,11 @@ func (c *coordinator) run() {
 			log.Errorf("can not create scheduler %s: %v", schedulerCfg.Type, err)
 		} else {
 			log.Infof("create scheduler %s", s.GetName())
+			if p, ok := s.(schedule.ConfigProvider); ok && schedulerCfg.Config != "" {
+				if err1 := p.SetConfig([]byte(schedulerCfg.Config)); err1 != nil {
+					log.Errorf("can not restore the config of scheduler %s: %v", s.GetName(), err1)
+				}
+			}
 			if err = c.addScheduler(s, schedulerCfg.Args...); err != nil {
 				log.Errorf("can not add scheduler %s: %v", s.GetName(), err)
 			}
@@ -262,6 +365,23 @@ func (c *coordinator) getHotReadRegions() *core.StoreHotRegionInfos {
 	return nil
 }
 
+// getSchedulersByTypes returns the names of the schedulers with the types.
+func (c *coordinator) getSchedulersByTypes(types []string) []string {
+	c.RLock()
+	defer c.RUnlock()
+
+	var names []string
+	for name, s := range c.schedulers {
+		for _, t := range types {
+			if s.GetType() == t {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	return names
+}
+
 func (c *coordinator) getSchedulers() []string {
 	c.RLock()
 	defer c.RUnlock()
@@ -352,6 +472,9 @@ func (c *coordinator) addScheduler(scheduler schedule.Scheduler, args ...string)
 	if _, ok := c.schedulers[scheduler.GetName()]; ok {
 		return errSchedulerExisted
 	}
+	if _, ok := debugSchedulerTypes[scheduler.GetType()]; ok && !c.cluster.opt.IsDebugSchedulersEnabled() {
+		return errDebugSchedulers
+	}
 
 	s := newScheduleController(c, scheduler)
 	if err := s.Prepare(c.cluster); err != nil {
@@ -382,6 +505,53 @@ func (c *coordinator) removeScheduler(name string) error {
 	return c.cluster.opt.RemoveSchedulerCfg(name)
 }
 
+func (c *coordinator) getSchedulerConfig(name string) (interface{}, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	p, err := c.getConfigProvider(name)
+	if err != nil {
+		return nil, err
+	}
+	return p.GetConfig(), nil
+}
+
+// setSchedulerConfig updates the config of a running scheduler and records it
+// in the schedule options, the caller is responsible for persisting them.
+func (c *coordinator) setSchedulerConfig(name string, data []byte) (interface{}, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	p, err := c.getConfigProvider(name)
+	if err != nil {
+		return nil, err
+	}
+	if err = p.SetConfig(data); err != nil {
+		return nil, err
+	}
+	cfg := p.GetConfig()
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err = c.cluster.opt.UpdateSchedulerCfg(name, string(b)); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *coordinator) getConfigProvider(name string) (schedule.ConfigProvider, error) {
+	s, ok := c.schedulers[name]
+	if !ok {
+		return nil, errSchedulerNotFound
+	}
+	p, ok := s.Scheduler.(schedule.ConfigProvider)
+	if !ok {
+		return nil, errSchedulerConfig
+	}
+	return p, nil
+}
+
 func (c *coordinator) runScheduler(s *scheduleController) {
 	defer logutil.LogPanic()
 	defer c.wg.Done()
@@ -394,12 +564,16 @@ func (c *coordinator) runScheduler(s *scheduleController) {
 		select {
 		case <-timer.C:
 			timer.Reset(s.GetInterval())
-			if !s.AllowSchedule() {
+			if !s.AllowSchedule() || !c.guard.allowSchedule(s.GetType()) {
 				continue
 			}
+			span := opentracing.StartSpan("pd.Schedule")
+			span.SetTag("scheduler", s.GetName())
 			if op := s.Schedule(); op != nil {
-				c.opController.AddOperator(op...)
+				opController := &tracedOperatorController{OperatorController: c.opController, span: span}
+				opController.AddOperator(op...)
 			}
+			span.Finish()
 
 		case <-s.Ctx().Done():
 			log.Infof("%v stopped: %v", s.GetName(), s.Ctx().Err())
@@ -408,6 +582,24 @@ func (c *coordinator) runScheduler(s *scheduleController) {
 	}
 }
 
+// tracedOperatorController adds the operators under the span of the checker
+// or scheduler creating them.
+type tracedOperatorController struct {
+	*schedule.OperatorController
+	span opentracing.Span
+}
+
+func (oc *tracedOperatorController) AddOperator(ops ...*schedule.Operator) bool {
+	for _, op := range ops {
+		op.SetTraceContext(oc.span.Context())
+	}
+	ok := oc.OperatorController.AddOperator(ops...)
+	for _, op := range ops {
+		oc.span.LogKV("operator", op.Desc(), "region_id", op.RegionID(), "added", ok)
+	}
+	return ok
+}
+
 type scheduleController struct {
 	schedule.Scheduler
 	cluster      *clusterInfo
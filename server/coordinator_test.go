@@ -14,8 +14,10 @@
 package server
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/rand"
+	"net/url"
 	"time"
 
 	. "github.com/pingcap/check"
@@ -28,6 +30,7 @@ import (
 	"github.com/pingcap/pd/server/schedule"
 	"github.com/pingcap/pd/server/schedulers"
 	"github.com/pkg/errors"
+	dto "github.com/prometheus/client_model/go"
 )
 
 func newTestOperator(regionID uint64, regionEpoch *metapb.RegionEpoch, kind schedule.OperatorKind) *schedule.Operator {
@@ -391,6 +394,65 @@ func (s *testCoordinatorSuite) TestReplica(c *C) {
 	waitNoResponse(c, stream)
 }
 
+func (s *testCoordinatorSuite) TestUrgentRepair(c *C) {
+	cfg, opt := newTestScheduleConfig()
+	cfg.ReplicaScheduleLimit = 1
+	tc := newTestClusterInfo(opt)
+	tc.regionStats = newRegionStatistics(opt, namespace.DefaultClassifier)
+	hbStreams := newHeartbeatStreams(tc.getClusterID())
+	defer hbStreams.Close()
+	co := newCoordinator(tc.clusterInfo, hbStreams, namespace.DefaultClassifier)
+
+	for i := uint64(1); i <= 7; i++ {
+		tc.addRegionStore(i, int(i))
+	}
+	tc.setStoreDown(3)
+	tc.setStoreDown(5)
+	withDownPeers := func(regionID uint64, storeIDs ...uint64) {
+		region := tc.GetRegion(regionID)
+		var downPeers []*pdpb.PeerStats
+		for _, id := range storeIDs {
+			downPeers = append(downPeers, &pdpb.PeerStats{Peer: region.GetStorePeer(id), DownSeconds: 24 * 60 * 60})
+		}
+		c.Assert(tc.handleRegionHeartbeat(region.Clone(core.WithDownPeers(downPeers))), IsNil)
+	}
+	// Region 1 loses its quorum if one more store fails.
+	tc.addLeaderRegion(1, 1, 2, 3)
+	withDownPeers(1, 3)
+	// Region 2 survives the failure of one more store.
+	tc.addLeaderRegion(2, 1, 2, 3, 4, 6)
+	withDownPeers(2, 3)
+	// Region 3 has lost its quorum.
+	tc.addLeaderRegion(3, 6, 3, 5)
+	withDownPeers(3, 3, 5)
+
+	// Region 4 is also about to lose its quorum.
+	tc.addLeaderRegion(4, 5, 6, 7)
+	withDownPeers(4, 5)
+
+	// The region which has lost its quorum is only reported.
+	urgent := co.collectUrgentRepairRegions()
+	c.Assert(urgent, HasLen, 2)
+	c.Assert(urgent[0].GetID(), Equals, uint64(1))
+	c.Assert(urgent[1].GetID(), Equals, uint64(4))
+	var m dto.Metric
+	c.Assert(urgentRepairRegionGauge.WithLabelValues(schedule.RepairQuorumLost.String()).Write(&m), IsNil)
+	c.Assert(m.GetGauge().GetValue(), Equals, 1.0)
+
+	// The urgent regions wait for the replica schedule limit.
+	urgent = co.repairUrgentRegions(urgent)
+	c.Assert(co.opController.GetOperator(1), NotNil)
+	c.Assert(co.opController.GetOperator(4), IsNil)
+	c.Assert(urgent, HasLen, 1)
+	c.Assert(urgent[0].GetID(), Equals, uint64(4))
+
+	cfg.ReplicaScheduleLimit = 2
+	c.Assert(co.repairUrgentRegions(urgent), HasLen, 0)
+	c.Assert(co.opController.GetOperator(4), NotNil)
+	c.Assert(co.opController.GetOperator(2), IsNil)
+	c.Assert(co.opController.GetOperator(3), IsNil)
+}
+
 func (s *testCoordinatorSuite) TestPeerState(c *C) {
 	_, opt := newTestScheduleConfig()
 	tc := newTestClusterInfo(opt)
@@ -489,6 +551,54 @@ func (s *testCoordinatorSuite) TestShouldRun(c *C) {
 
 }
 
+func (s *testCoordinatorSuite) TestForcePrepare(c *C) {
+	cfg, opt := newTestScheduleConfig()
+	cfg.RegionCollectFactor = 0.5
+	opt.store(cfg)
+	tc := newTestClusterInfo(opt)
+	hbStreams := newHeartbeatStreams(tc.getClusterID())
+	defer hbStreams.Close()
+
+	co := newCoordinator(tc.clusterInfo, hbStreams, namespace.DefaultClassifier)
+
+	tc.addLeaderStore(1, 4)
+	tc.addLeaderStore(2, 0)
+	tc.LoadRegion(1, 1, 2)
+	tc.LoadRegion(2, 1, 2)
+	tc.LoadRegion(3, 1, 2)
+	tc.LoadRegion(4, 1, 2)
+	r := tc.GetRegion(1)
+	tc.handleRegionHeartbeat(r.Clone(core.WithLeader(r.GetPeers()[0])))
+	c.Assert(co.shouldRun(), IsFalse)
+
+	status := tc.prepareStatus()
+	c.Assert(status.IsPrepared, IsFalse)
+	c.Assert(status.Forced, IsFalse)
+	c.Assert(status.CollectFactor, Equals, 0.5)
+	c.Assert(status.LoadedRegions, Equals, 4)
+	c.Assert(status.CollectedRegions, Equals, 1)
+	c.Assert(status.Progress, Equals, float64(50))
+	c.Assert(status.Stores, DeepEquals, []*StorePrepareStatus{
+		{StoreID: 1, LoadedRegions: 4, CollectedRegions: 1},
+		{StoreID: 2, LoadedRegions: 4, CollectedRegions: 1},
+	})
+
+	// Half of the regions are enough.
+	r = tc.GetRegion(2)
+	tc.handleRegionHeartbeat(r.Clone(core.WithLeader(r.GetPeers()[0])))
+	c.Assert(co.shouldRun(), IsTrue)
+	c.Assert(tc.prepareStatus().Forced, IsFalse)
+
+	tc.prepareChecker = newPrepareChecker()
+	c.Assert(co.shouldRun(), IsFalse)
+	tc.forcePrepare()
+	c.Assert(co.shouldRun(), IsTrue)
+	status = tc.prepareStatus()
+	c.Assert(status.IsPrepared, IsTrue)
+	c.Assert(status.Forced, IsTrue)
+	c.Assert(status.Progress, Equals, float64(100))
+}
+
 func (s *testCoordinatorSuite) TestAddScheduler(c *C) {
 	cfg, opt := newTestScheduleConfig()
 	cfg.ReplicaScheduleLimit = 0
@@ -635,6 +745,91 @@ func (s *testCoordinatorSuite) TestPersistScheduler(c *C) {
 	c.Assert(co.schedulers, HasLen, 3)
 }
 
+func (s *testCoordinatorSuite) TestPersistSchedulerConfig(c *C) {
+	_, opt := newTestScheduleConfig()
+	tc := newTestClusterInfo(opt)
+	hbStreams := newHeartbeatStreams(tc.getClusterID())
+	defer hbStreams.Close()
+
+	co := newCoordinator(tc.clusterInfo, hbStreams, namespace.DefaultClassifier)
+	co.run()
+	_, err := co.getSchedulerConfig("unknown-scheduler")
+	c.Assert(err, Equals, errSchedulerNotFound)
+	_, err = co.getSchedulerConfig("label-scheduler")
+	c.Assert(err, Equals, errSchedulerConfig)
+	_, err = co.setSchedulerConfig(hotRegionScheduleName, []byte(`{"limit-factor": 2}`))
+	c.Assert(err, NotNil)
+	_, err = co.setSchedulerConfig(hotRegionScheduleName, []byte(`{"limit-factor": 0.5}`))
+	c.Assert(err, IsNil)
+	cfg, err := co.getSchedulerConfig(hotRegionScheduleName)
+	c.Assert(err, IsNil)
+	expect, err := json.Marshal(cfg)
+	c.Assert(err, IsNil)
+	c.Assert(co.cluster.opt.persist(co.cluster.kv), IsNil)
+	co.stop()
+	co.wg.Wait()
+
+	// The config is restored after restart.
+	_, newOpt := newTestScheduleConfig()
+	c.Assert(newOpt.reload(co.cluster.kv), IsNil)
+	tc.clusterInfo.opt = newOpt
+	co = newCoordinator(tc.clusterInfo, hbStreams, namespace.DefaultClassifier)
+	co.run()
+	cfg, err = co.getSchedulerConfig(hotRegionScheduleName)
+	c.Assert(err, IsNil)
+	data, err := json.Marshal(cfg)
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, string(expect))
+
+	// The config is reset if the scheduler is removed and added again.
+	c.Assert(co.removeScheduler(hotRegionScheduleName), IsNil)
+	hb, err := schedule.CreateScheduler("hot-region", co.opController)
+	c.Assert(err, IsNil)
+	c.Assert(co.addScheduler(hb), IsNil)
+	for _, schedulerCfg := range co.cluster.opt.GetSchedulers() {
+		c.Assert(schedulerCfg.Config, Equals, "")
+	}
+	co.stop()
+	co.wg.Wait()
+}
+
+func (s *testCoordinatorSuite) TestPersistScatterRangeScheduler(c *C) {
+	_, opt := newTestScheduleConfig()
+	tc := newTestClusterInfo(opt)
+	hbStreams := newHeartbeatStreams(tc.getClusterID())
+	defer hbStreams.Close()
+
+	co := newCoordinator(tc.clusterInfo, hbStreams, namespace.DefaultClassifier)
+	co.run()
+	args := []string{url.QueryEscape("t1_\x00"), url.QueryEscape("t1_\xff"), "t1"}
+	srs, err := schedule.CreateScheduler("scatter-range", co.opController, args...)
+	c.Assert(err, IsNil)
+	c.Assert(co.addScheduler(srs, args...), IsNil)
+	c.Assert(co.addScheduler(srs, args...), Equals, errSchedulerExisted)
+	c.Assert(co.cluster.opt.persist(co.cluster.kv), IsNil)
+	co.stop()
+	co.wg.Wait()
+
+	// The named range is scattered again after restart.
+	_, newOpt := newTestScheduleConfig()
+	c.Assert(newOpt.reload(co.cluster.kv), IsNil)
+	tc.clusterInfo.opt = newOpt
+	co = newCoordinator(tc.clusterInfo, hbStreams, namespace.DefaultClassifier)
+	co.run()
+	c.Assert(co.schedulers, HasKey, "scatter-range-t1")
+	c.Assert(co.removeScheduler("scatter-range-t1"), IsNil)
+	c.Assert(co.schedulers, Not(HasKey), "scatter-range-t1")
+	c.Assert(co.cluster.opt.persist(co.cluster.kv), IsNil)
+	co.stop()
+	co.wg.Wait()
+
+	_, newOpt = newTestScheduleConfig()
+	c.Assert(newOpt.reload(co.cluster.kv), IsNil)
+	for _, cfg := range newOpt.GetSchedulers() {
+		c.Assert(cfg.Type, Not(Equals), "scatter-range")
+	}
+}
+
 func (s *testCoordinatorSuite) TestRestart(c *C) {
 	// Turn off balance, we test add replica only.
 	cfg, opt := newTestScheduleConfig()
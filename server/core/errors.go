@@ -32,10 +32,38 @@ var (
 
 	// StoreTombstonedCode is an invalid operation was attempted on a store which is in a removed state.
 	StoreTombstonedCode = storeStateCode.Child("state.store.tombstoned").SetHTTP(http.StatusGone)
+
+	// StoreAddressConflictedCode is an error due to registering a store with the address of another store.
+	StoreAddressConflictedCode = storeStateCode.Child("state.store.address_conflicted").SetHTTP(http.StatusConflict)
+
+	// StoreIDConflictedCode is an error due to registering a store with the ID of another running store.
+	StoreIDConflictedCode = storeStateCode.Child("state.store.id_conflicted").SetHTTP(http.StatusConflict)
+
+	// InvalidStoreLabelCode is an error due to a store label violating the label schema.
+	InvalidStoreLabelCode = errcode.InvalidInputCode.Child("input.store_label")
+
+	// NotLeaderCode is an error due to requesting an operation which can only be done by the leader.
+	NotLeaderCode = errcode.StateCode.Child("state.not_leader").SetHTTP(http.StatusServiceUnavailable)
+
+	// NotBootstrappedCode is an error due to requesting an operation which needs a bootstrapped cluster.
+	NotBootstrappedCode = errcode.StateCode.Child("state.not_bootstrapped").SetHTTP(http.StatusServiceUnavailable)
+
+	// AlreadyBootstrappedCode is an error due to bootstrapping a cluster which is bootstrapped already.
+	AlreadyBootstrappedCode = errcode.StateCode.Child("state.already_bootstrapped").SetHTTP(http.StatusConflict)
+
+	// QuotaExceededCode is an error due to an operation exceeding the quota of a namespace.
+	QuotaExceededCode = errcode.StateCode.Child("state.quota_exceeded").SetHTTP(http.StatusTooManyRequests)
 )
 
-var _ errcode.ErrorCode = (*StoreTombstonedErr)(nil) // assert implements interface
-var _ errcode.ErrorCode = (*StoreBlockedErr)(nil)    // assert implements interface
+var _ errcode.ErrorCode = (*StoreTombstonedErr)(nil)        // assert implements interface
+var _ errcode.ErrorCode = (*StoreBlockedErr)(nil)           // assert implements interface
+var _ errcode.ErrorCode = (*StoreAddressConflictedErr)(nil) // assert implements interface
+var _ errcode.ErrorCode = (*StoreIDConflictedErr)(nil)      // assert implements interface
+var _ errcode.ErrorCode = (*InvalidStoreLabelErr)(nil)      // assert implements interface
+var _ errcode.ErrorCode = (*NotLeaderErr)(nil)              // assert implements interface
+var _ errcode.ErrorCode = (*NotBootstrappedErr)(nil)        // assert implements interface
+var _ errcode.ErrorCode = (*AlreadyBootstrappedErr)(nil)    // assert implements interface
+var _ errcode.ErrorCode = (*QuotaExceededErr)(nil)          // assert implements interface
 
 // StoreErr can be newtyped or embedded in your own error
 type StoreErr struct {
@@ -61,3 +89,100 @@ func (e StoreBlockedErr) Error() string {
 
 // Code returns StoreBlockedCode
 func (e StoreBlockedErr) Code() errcode.Code { return StoreBlockedCode }
+
+// StoreAddressConflictedErr is a store was registered with the address of
+// another store which is not tombstone.
+type StoreAddressConflictedErr struct {
+	StoreID         uint64 `json:"storeId"`
+	Address         string `json:"address"`
+	ConflictStoreID uint64 `json:"conflictStoreId"`
+}
+
+func (e StoreAddressConflictedErr) Error() string {
+	return fmt.Sprintf("store %d can not use address %s, which is registered by store %d, remove store %d first or use another address",
+		e.StoreID, e.Address, e.ConflictStoreID, e.ConflictStoreID)
+}
+
+// Code returns StoreAddressConflictedCode
+func (e StoreAddressConflictedErr) Code() errcode.Code { return StoreAddressConflictedCode }
+
+// StoreIDConflictedErr is a store was registered with the ID of another store
+// which is still running at a different address, for example the data
+// directory of a store is copied to another node.
+type StoreIDConflictedErr struct {
+	StoreID           uint64 `json:"storeId"`
+	Address           string `json:"address"`
+	RegisteredAddress string `json:"registeredAddress"`
+}
+
+func (e StoreIDConflictedErr) Error() string {
+	return fmt.Sprintf("store %d at %s is still running at %s, stop the store at %s or use a new data directory",
+		e.StoreID, e.Address, e.RegisteredAddress, e.RegisteredAddress)
+}
+
+// Code returns StoreIDConflictedCode
+func (e StoreIDConflictedErr) Code() errcode.Code { return StoreIDConflictedCode }
+
+// InvalidStoreLabelErr is a store label violates the label schema.
+type InvalidStoreLabelErr struct {
+	StoreID uint64 `json:"storeId"`
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Reason  string `json:"reason"`
+}
+
+func (e InvalidStoreLabelErr) Error() string {
+	return fmt.Sprintf("label %s=%s of store %d is invalid: %s", e.Key, e.Value, e.StoreID, e.Reason)
+}
+
+// Code returns InvalidStoreLabelCode
+func (e InvalidStoreLabelErr) Code() errcode.Code { return InvalidStoreLabelCode }
+
+// NotLeaderErr is an operation which can only be done by the leader was
+// attempted on a follower, or the leadership was lost in the middle.
+type NotLeaderErr struct{}
+
+func (e NotLeaderErr) Error() string {
+	return "not leader"
+}
+
+// Code returns NotLeaderCode
+func (e NotLeaderErr) Code() errcode.Code { return NotLeaderCode }
+
+// NotBootstrappedErr is an operation which needs a bootstrapped cluster was
+// attempted before the cluster is bootstrapped.
+type NotBootstrappedErr struct{}
+
+func (e NotBootstrappedErr) Error() string {
+	return "TiKV cluster not bootstrapped, please start TiKV first"
+}
+
+// Code returns NotBootstrappedCode
+func (e NotBootstrappedErr) Code() errcode.Code { return NotBootstrappedCode }
+
+// AlreadyBootstrappedErr is a cluster was bootstrapped again.
+type AlreadyBootstrappedErr struct {
+	ClusterID uint64 `json:"clusterId"`
+}
+
+func (e AlreadyBootstrappedErr) Error() string {
+	return fmt.Sprintf("cluster %d already bootstrapped", e.ClusterID)
+}
+
+// Code returns AlreadyBootstrappedCode
+func (e AlreadyBootstrappedErr) Code() errcode.Code { return AlreadyBootstrappedCode }
+
+// QuotaExceededErr is an operation which grows the regions of a namespace was
+// rejected because the namespace reaches its quota.
+type QuotaExceededErr struct {
+	Namespace string `json:"namespace"`
+	Quota     string `json:"quota"`
+	Limit     string `json:"limit"`
+}
+
+func (e QuotaExceededErr) Error() string {
+	return fmt.Sprintf("namespace %s exceeds the quota %s: %s", e.Namespace, e.Quota, e.Limit)
+}
+
+// Code returns QuotaExceededCode
+func (e QuotaExceededErr) Code() errcode.Code { return QuotaExceededCode }
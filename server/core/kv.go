@@ -14,12 +14,14 @@
 package core
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math"
 	"path"
 	"strconv"
 	"sync/atomic"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/pingcap/kvproto/pkg/metapb"
@@ -31,6 +33,14 @@ const (
 	configPath   = "config"
 	schedulePath = "schedule"
 	gcPath       = "gc"
+
+	componentConfigPath = "component_config"
+	replicationPath     = "replication_mode"
+	keyspacePath        = "keyspaces"
+	maintenancePath     = "maintenance"
+	jobPath             = "jobs"
+	eventPath           = "events"
+	encryptionKeyPath   = "encryption_keys"
 )
 
 const (
@@ -43,15 +53,35 @@ type KV struct {
 	KVBase
 	regionKV    *RegionKV
 	useRegionKV int32
+	// ctx is passed to all operations of KVBase.
+	ctx context.Context
 }
 
 // NewKV creates KV instance with KVBase.
 func NewKV(base KVBase) *KV {
 	return &KV{
 		KVBase: base,
+		ctx:    context.Background(),
+	}
+}
+
+// WithContext returns a shallow copy of kv whose operations are canceled when
+// ctx is done. The copy is supposed to be used within a request, it doesn't
+// follow the later switch of the region storage.
+func (kv *KV) WithContext(ctx context.Context) *KV {
+	return &KV{
+		KVBase:      kv.KVBase,
+		regionKV:    kv.regionKV,
+		useRegionKV: atomic.LoadInt32(&kv.useRegionKV),
+		ctx:         ctx,
 	}
 }
 
+// Context returns the context of the operations.
+func (kv *KV) Context() context.Context {
+	return kv.ctx
+}
+
 // SetRegionKV sets the region storage.
 func (kv *KV) SetRegionKV(regionKV *RegionKV) *KV {
 	kv.regionKV = regionKV
@@ -94,40 +124,76 @@ func (kv *KV) storeRegionWeightPath(storeID uint64) string {
 	return path.Join(schedulePath, "store_weight", fmt.Sprintf("%020d", storeID), "region")
 }
 
+func (kv *KV) storeCordonPath(storeID uint64) string {
+	return path.Join(schedulePath, "store_cordon", fmt.Sprintf("%020d", storeID))
+}
+
+func (kv *KV) storeReplacementPath(storeID uint64) string {
+	return path.Join(schedulePath, "store_replacement", fmt.Sprintf("%020d", storeID))
+}
+
+func jobMetaPath(id uint64) string {
+	return path.Join(jobPath, fmt.Sprintf("%020d", id))
+}
+
+func clusterEventPath(id uint64) string {
+	return path.Join(eventPath, fmt.Sprintf("%020d", id))
+}
+
+func encryptionKeyIDPath(id uint64) string {
+	return path.Join(encryptionKeyPath, fmt.Sprintf("%020d", id))
+}
+
+func operatorPath(regionID uint64) string {
+	return path.Join(schedulePath, "operator", fmt.Sprintf("%020d", regionID))
+}
+
+func keyspaceMetaPath(id uint32) string {
+	return path.Join(keyspacePath, "meta", fmt.Sprintf("%010d", id))
+}
+
+func keyspaceNextIDPath() string {
+	return path.Join(keyspacePath, "next_id")
+}
+
+func keyspaceIDPath(name string) string {
+	return path.Join(keyspacePath, "id", name)
+}
+
 // LoadMeta loads cluster meta from KV store.
 func (kv *KV) LoadMeta(meta *metapb.Cluster) (bool, error) {
-	return loadProto(kv.KVBase, clusterPath, meta)
+	return loadProto(kv.ctx, kv.KVBase, clusterPath, meta)
 }
 
 // SaveMeta save cluster meta to KV store.
 func (kv *KV) SaveMeta(meta *metapb.Cluster) error {
-	return saveProto(kv.KVBase, clusterPath, meta)
+	return saveProto(kv.ctx, kv.KVBase, clusterPath, meta)
 }
 
 // LoadStore loads one store from KV.
 func (kv *KV) LoadStore(storeID uint64, store *metapb.Store) (bool, error) {
-	return loadProto(kv.KVBase, kv.storePath(storeID), store)
+	return loadProto(kv.ctx, kv.KVBase, kv.storePath(storeID), store)
 }
 
 // SaveStore saves one store to KV.
 func (kv *KV) SaveStore(store *metapb.Store) error {
-	return saveProto(kv.KVBase, kv.storePath(store.GetId()), store)
+	return saveProto(kv.ctx, kv.KVBase, kv.storePath(store.GetId()), store)
 }
 
 // LoadRegion loads one regoin from KV.
 func (kv *KV) LoadRegion(regionID uint64, region *metapb.Region) (bool, error) {
 	if atomic.LoadInt32(&kv.useRegionKV) > 0 {
-		return loadProto(kv.regionKV, regionPath(regionID), region)
+		return loadProto(kv.ctx, kv.regionKV, regionPath(regionID), region)
 	}
-	return loadProto(kv.KVBase, regionPath(regionID), region)
+	return loadProto(kv.ctx, kv.KVBase, regionPath(regionID), region)
 }
 
 // LoadRegions loads all regions from KV to RegionsInfo.
 func (kv *KV) LoadRegions(regions *RegionsInfo) error {
 	if atomic.LoadInt32(&kv.useRegionKV) > 0 {
-		return loadRegions(kv.regionKV, regions)
+		return loadRegions(kv.ctx, kv.regionKV, regions)
 	}
-	return loadRegions(kv.KVBase, regions)
+	return loadRegions(kv.ctx, kv.KVBase, regions)
 }
 
 // SaveRegion saves one region to KV.
@@ -135,15 +201,24 @@ func (kv *KV) SaveRegion(region *metapb.Region) error {
 	if atomic.LoadInt32(&kv.useRegionKV) > 0 {
 		return kv.regionKV.SaveRegion(region)
 	}
-	return saveProto(kv.KVBase, regionPath(region.GetId()), region)
+	return saveProto(kv.ctx, kv.KVBase, regionPath(region.GetId()), region)
 }
 
 // DeleteRegion deletes one region from KV.
 func (kv *KV) DeleteRegion(region *metapb.Region) error {
 	if atomic.LoadInt32(&kv.useRegionKV) > 0 {
-		return deleteRegion(kv.regionKV, region)
+		return deleteRegion(kv.ctx, kv.regionKV, region)
 	}
-	return deleteRegion(kv.KVBase, region)
+	return deleteRegion(kv.ctx, kv.KVBase, region)
+}
+
+// ScanRegions loads at most limit regions from KV, whose IDs are not less
+// than startID, ordered by ID.
+func (kv *KV) ScanRegions(startID uint64, limit int) ([]*metapb.Region, error) {
+	if atomic.LoadInt32(&kv.useRegionKV) > 0 {
+		return scanRegions(kv.ctx, kv.regionKV, startID, limit)
+	}
+	return scanRegions(kv.ctx, kv.KVBase, startID, limit)
 }
 
 // SaveConfig stores marshalable cfg to the configPath.
@@ -152,12 +227,12 @@ func (kv *KV) SaveConfig(cfg interface{}) error {
 	if err != nil {
 		return errors.WithStack(err)
 	}
-	return kv.Save(configPath, string(value))
+	return kv.Save(kv.ctx, configPath, string(value))
 }
 
 // LoadConfig loads config from configPath then unmarshal it to cfg.
 func (kv *KV) LoadConfig(cfg interface{}) (bool, error) {
-	value, err := kv.Load(configPath)
+	value, err := kv.Load(kv.ctx, configPath)
 	if err != nil {
 		return false, err
 	}
@@ -171,13 +246,291 @@ func (kv *KV) LoadConfig(cfg interface{}) (bool, error) {
 	return true, nil
 }
 
+// SaveComponentConfig stores the marshalable config of a component.
+func (kv *KV) SaveComponentConfig(component string, cfg interface{}) error {
+	value, err := json.Marshal(cfg)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return kv.Save(kv.ctx, path.Join(componentConfigPath, component), string(value))
+}
+
+// LoadComponentConfig loads the config of a component then unmarshal it to
+// cfg.
+func (kv *KV) LoadComponentConfig(component string, cfg interface{}) (bool, error) {
+	value, err := kv.Load(kv.ctx, path.Join(componentConfigPath, component))
+	if err != nil {
+		return false, err
+	}
+	if value == "" {
+		return false, nil
+	}
+	err = json.Unmarshal([]byte(value), cfg)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	return true, nil
+}
+
+// SaveReplicationStatus stores the marshalable status of a replication mode.
+func (kv *KV) SaveReplicationStatus(mode string, status interface{}) error {
+	value, err := json.Marshal(status)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return kv.Save(kv.ctx, path.Join(replicationPath, mode), string(value))
+}
+
+// LoadReplicationStatus loads the status of a replication mode then unmarshal
+// it to status.
+func (kv *KV) LoadReplicationStatus(mode string, status interface{}) (bool, error) {
+	value, err := kv.Load(kv.ctx, path.Join(replicationPath, mode))
+	if err != nil {
+		return false, err
+	}
+	if value == "" {
+		return false, nil
+	}
+	err = json.Unmarshal([]byte(value), status)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	return true, nil
+}
+
+// SaveMaintenance stores the marshalable maintenance mode status.
+func (kv *KV) SaveMaintenance(status interface{}) error {
+	value, err := json.Marshal(status)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return kv.Save(kv.ctx, maintenancePath, string(value))
+}
+
+// LoadMaintenance loads the maintenance mode status then unmarshal it to
+// status.
+func (kv *KV) LoadMaintenance(status interface{}) (bool, error) {
+	value, err := kv.Load(kv.ctx, maintenancePath)
+	if err != nil {
+		return false, err
+	}
+	if value == "" {
+		return false, nil
+	}
+	err = json.Unmarshal([]byte(value), status)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	return true, nil
+}
+
+// SaveKeyspace stores the metadata of a keyspace, and the index from its name
+// to its ID.
+func (kv *KV) SaveKeyspace(id uint32, name string, meta proto.Message) error {
+	if err := saveProto(kv.ctx, kv.KVBase, keyspaceMetaPath(id), meta); err != nil {
+		return err
+	}
+	return kv.Save(kv.ctx, keyspaceIDPath(name), strconv.FormatUint(uint64(id), 10))
+}
+
+// SaveKeyspaceNextID stores the ID to allocate to the next keyspace.
+func (kv *KV) SaveKeyspaceNextID(id uint32) error {
+	return kv.Save(kv.ctx, keyspaceNextIDPath(), strconv.FormatUint(uint64(id), 10))
+}
+
+// LoadKeyspaceNextID loads the ID to allocate to the next keyspace.
+func (kv *KV) LoadKeyspaceNextID() (uint32, bool, error) {
+	value, err := kv.Load(kv.ctx, keyspaceNextIDPath())
+	if err != nil || value == "" {
+		return 0, false, err
+	}
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, false, errors.WithStack(err)
+	}
+	return uint32(id), true, nil
+}
+
+// LoadKeyspaceID loads the ID of a keyspace by its name.
+func (kv *KV) LoadKeyspaceID(name string) (uint32, bool, error) {
+	value, err := kv.Load(kv.ctx, keyspaceIDPath(name))
+	if err != nil || value == "" {
+		return 0, false, err
+	}
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, false, errors.WithStack(err)
+	}
+	return uint32(id), true, nil
+}
+
+// LoadKeyspace loads the metadata of a keyspace.
+func (kv *KV) LoadKeyspace(id uint32, meta proto.Message) (bool, error) {
+	return loadProto(kv.ctx, kv.KVBase, keyspaceMetaPath(id), meta)
+}
+
+// LoadKeyspaces loads the metadata of all keyspaces from KV. The function f
+// should unmarshal the metadata and return the keyspace ID.
+func (kv *KV) LoadKeyspaces(f func(data []byte) (uint32, error)) error {
+	nextID := uint32(0)
+	endKey := keyspaceMetaPath(math.MaxUint32)
+	for {
+		key := keyspaceMetaPath(nextID)
+		res, err := kv.LoadRange(kv.ctx, key, endKey, minKVRangeLimit)
+		if err != nil {
+			return err
+		}
+		for _, s := range res {
+			id, err := f([]byte(s))
+			if err != nil {
+				return err
+			}
+			nextID = id + 1
+		}
+		if len(res) < minKVRangeLimit {
+			return nil
+		}
+	}
+}
+
+// SaveJob stores the marshalable metadata of an asynchronous job.
+func (kv *KV) SaveJob(id uint64, job interface{}) error {
+	value, err := json.Marshal(job)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return kv.Save(kv.ctx, jobMetaPath(id), string(value))
+}
+
+// DeleteJob deletes the metadata of an asynchronous job.
+func (kv *KV) DeleteJob(id uint64) error {
+	return kv.Delete(kv.ctx, jobMetaPath(id))
+}
+
+// LoadJobs loads the metadata of all asynchronous jobs from KV. The function
+// f should decode the metadata and return the job ID.
+func (kv *KV) LoadJobs(f func(data []byte) (uint64, error)) error {
+	nextID := uint64(0)
+	endKey := jobMetaPath(math.MaxUint64)
+	for {
+		key := jobMetaPath(nextID)
+		res, err := kv.LoadRange(kv.ctx, key, endKey, minKVRangeLimit)
+		if err != nil {
+			return err
+		}
+		for _, s := range res {
+			id, err := f([]byte(s))
+			if err != nil {
+				return err
+			}
+			nextID = id + 1
+		}
+		if len(res) < minKVRangeLimit {
+			return nil
+		}
+	}
+}
+
+// SaveEvent stores the marshalable cluster event.
+func (kv *KV) SaveEvent(id uint64, event interface{}) error {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return kv.Save(kv.ctx, clusterEventPath(id), string(value))
+}
+
+// DeleteEvent deletes a cluster event.
+func (kv *KV) DeleteEvent(id uint64) error {
+	return kv.Delete(kv.ctx, clusterEventPath(id))
+}
+
+// LoadEvents loads the cluster events ordered by ID from KV. The function f
+// should decode the event and return its ID.
+func (kv *KV) LoadEvents(f func(data []byte) (uint64, error)) error {
+	nextID := uint64(0)
+	endKey := clusterEventPath(math.MaxUint64)
+	for {
+		key := clusterEventPath(nextID)
+		res, err := kv.LoadRange(kv.ctx, key, endKey, minKVRangeLimit)
+		if err != nil {
+			return err
+		}
+		for _, s := range res {
+			id, err := f([]byte(s))
+			if err != nil {
+				return err
+			}
+			nextID = id + 1
+		}
+		if len(res) < minKVRangeLimit {
+			return nil
+		}
+	}
+}
+
+// SaveEncryptionKey stores the marshalable encrypted data key.
+func (kv *KV) SaveEncryptionKey(id uint64, key interface{}) error {
+	value, err := json.Marshal(key)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return kv.Save(kv.ctx, encryptionKeyIDPath(id), string(value))
+}
+
+// LoadEncryptionKeys loads the encrypted data keys ordered by ID from KV. The
+// function f should decode the key and return its ID.
+func (kv *KV) LoadEncryptionKeys(f func(data []byte) (uint64, error)) error {
+	nextID := uint64(0)
+	endKey := encryptionKeyIDPath(math.MaxUint64)
+	for {
+		key := encryptionKeyIDPath(nextID)
+		res, err := kv.LoadRange(kv.ctx, key, endKey, minKVRangeLimit)
+		if err != nil {
+			return err
+		}
+		for _, s := range res {
+			id, err := f([]byte(s))
+			if err != nil {
+				return err
+			}
+			nextID = id + 1
+		}
+		if len(res) < minKVRangeLimit {
+			return nil
+		}
+	}
+}
+
+// SaveSchedulerPlugins stores the file names of the loaded scheduler plugins.
+func (kv *KV) SaveSchedulerPlugins(names []string) error {
+	value, err := json.Marshal(names)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return kv.Save(kv.ctx, path.Join(schedulePath, "plugins"), string(value))
+}
+
+// LoadSchedulerPlugins loads the file names of the scheduler plugins.
+func (kv *KV) LoadSchedulerPlugins() ([]string, error) {
+	value, err := kv.Load(kv.ctx, path.Join(schedulePath, "plugins"))
+	if err != nil || value == "" {
+		return nil, err
+	}
+	var names []string
+	if err = json.Unmarshal([]byte(value), &names); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return names, nil
+}
+
 // LoadStores loads all stores from KV to StoresInfo.
 func (kv *KV) LoadStores(stores *StoresInfo) error {
 	nextID := uint64(0)
 	endKey := kv.storePath(math.MaxUint64)
 	for {
 		key := kv.storePath(nextID)
-		res, err := kv.LoadRange(key, endKey, minKVRangeLimit)
+		res, err := kv.LoadRange(kv.ctx, key, endKey, minKVRangeLimit)
 		if err != nil {
 			return err
 		}
@@ -197,6 +550,12 @@ func (kv *KV) LoadStores(stores *StoresInfo) error {
 				return err
 			}
 			storeInfo.RegionWeight = regionWeight
+			if err = kv.loadStoreCordon(storeInfo); err != nil {
+				return err
+			}
+			if err = kv.loadStoreReplacement(storeInfo); err != nil {
+				return err
+			}
 
 			nextID = store.GetId() + 1
 			stores.SetStore(storeInfo)
@@ -207,18 +566,131 @@ func (kv *KV) LoadStores(stores *StoresInfo) error {
 	}
 }
 
+// SaveOperator saves the encoded running operator of a region to KV.
+func (kv *KV) SaveOperator(regionID uint64, data []byte) error {
+	return kv.Save(kv.ctx, operatorPath(regionID), string(data))
+}
+
+// DeleteOperator deletes the persisted operator of a region from KV.
+func (kv *KV) DeleteOperator(regionID uint64) error {
+	return kv.Delete(kv.ctx, operatorPath(regionID))
+}
+
+// LoadOperators loads all encoded running operators from KV. The function f
+// should decode the operator and return its region ID.
+func (kv *KV) LoadOperators(f func(data []byte) (uint64, error)) error {
+	nextID := uint64(0)
+	endKey := operatorPath(math.MaxUint64)
+	for {
+		key := operatorPath(nextID)
+		res, err := kv.LoadRange(kv.ctx, key, endKey, minKVRangeLimit)
+		if err != nil {
+			return err
+		}
+		for _, s := range res {
+			regionID, err := f([]byte(s))
+			if err != nil {
+				return err
+			}
+			nextID = regionID + 1
+		}
+		if len(res) < minKVRangeLimit {
+			return nil
+		}
+	}
+}
+
 // SaveStoreWeight saves a store's leader and region weight to KV.
 func (kv *KV) SaveStoreWeight(storeID uint64, leader, region float64) error {
 	leaderValue := strconv.FormatFloat(leader, 'f', -1, 64)
-	if err := kv.Save(kv.storeLeaderWeightPath(storeID), leaderValue); err != nil {
+	if err := kv.Save(kv.ctx, kv.storeLeaderWeightPath(storeID), leaderValue); err != nil {
 		return err
 	}
 	regionValue := strconv.FormatFloat(region, 'f', -1, 64)
-	return kv.Save(kv.storeRegionWeightPath(storeID), regionValue)
+	return kv.Save(kv.ctx, kv.storeRegionWeightPath(storeID), regionValue)
+}
+
+// DeleteStoreWeight deletes the saved weights of the store. It returns false
+// if no weight is saved.
+func (kv *KV) DeleteStoreWeight(storeID uint64) (bool, error) {
+	var deleted bool
+	for _, path := range []string{kv.storeLeaderWeightPath(storeID), kv.storeRegionWeightPath(storeID)} {
+		value, err := kv.Load(kv.ctx, path)
+		if err != nil {
+			return deleted, err
+		}
+		if value == "" {
+			continue
+		}
+		if err = kv.Delete(kv.ctx, path); err != nil {
+			return deleted, err
+		}
+		deleted = true
+	}
+	return deleted, nil
+}
+
+// SaveStoreCordon saves the cordon of a store to KV, the deadline is saved as
+// a unix timestamp and 0 means no deadline.
+func (kv *KV) SaveStoreCordon(storeID uint64, deadline time.Time) error {
+	var value int64
+	if !deadline.IsZero() {
+		value = deadline.Unix()
+	}
+	return kv.Save(kv.ctx, kv.storeCordonPath(storeID), strconv.FormatInt(value, 10))
+}
+
+// DeleteStoreCordon deletes the saved cordon of a store.
+func (kv *KV) DeleteStoreCordon(storeID uint64) error {
+	return kv.Delete(kv.ctx, kv.storeCordonPath(storeID))
+}
+
+func (kv *KV) loadStoreCordon(store *StoreInfo) error {
+	res, err := kv.Load(kv.ctx, kv.storeCordonPath(store.GetId()))
+	if err != nil || res == "" {
+		return err
+	}
+	value, err := strconv.ParseInt(res, 10, 64)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	var deadline time.Time
+	if value != 0 {
+		deadline = time.Unix(value, 0)
+	}
+	store.Cordon(deadline)
+	return nil
+}
+
+// SaveStoreReplacement saves the replacement of a store to KV.
+func (kv *KV) SaveStoreReplacement(storeID uint64, replacement *StoreReplacement) error {
+	value, err := json.Marshal(replacement)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return kv.Save(kv.ctx, kv.storeReplacementPath(storeID), string(value))
+}
+
+// DeleteStoreReplacement deletes the saved replacement of a store.
+func (kv *KV) DeleteStoreReplacement(storeID uint64) error {
+	return kv.Delete(kv.ctx, kv.storeReplacementPath(storeID))
+}
+
+func (kv *KV) loadStoreReplacement(store *StoreInfo) error {
+	res, err := kv.Load(kv.ctx, kv.storeReplacementPath(store.GetId()))
+	if err != nil || res == "" {
+		return err
+	}
+	replacement := &StoreReplacement{}
+	if err = json.Unmarshal([]byte(res), replacement); err != nil {
+		return errors.WithStack(err)
+	}
+	store.SetReplacement(replacement)
+	return nil
 }
 
 func (kv *KV) loadFloatWithDefaultValue(path string, def float64) (float64, error) {
-	res, err := kv.Load(path)
+	res, err := kv.Load(kv.ctx, path)
 	if err != nil {
 		return 0, err
 	}
@@ -252,13 +724,13 @@ func (kv *KV) Close() error {
 func (kv *KV) SaveGCSafePoint(safePoint uint64) error {
 	key := path.Join(gcPath, "safe_point")
 	value := strconv.FormatUint(safePoint, 16)
-	return kv.Save(key, value)
+	return kv.Save(kv.ctx, key, value)
 }
 
 // LoadGCSafePoint loads current GC safe point from KV.
 func (kv *KV) LoadGCSafePoint() (uint64, error) {
 	key := path.Join(gcPath, "safe_point")
-	value, err := kv.Load(key)
+	value, err := kv.Load(kv.ctx, key)
 	if err != nil {
 		return 0, err
 	}
@@ -272,8 +744,49 @@ func (kv *KV) LoadGCSafePoint() (uint64, error) {
 	return safePoint, nil
 }
 
-func loadProto(kv KVBase, key string, msg proto.Message) (bool, error) {
-	value, err := kv.Load(key)
+func serviceGCSafePointPath(serviceID string) string {
+	return path.Join(gcPath, "safe_point", "service", serviceID)
+}
+
+// SaveServiceGCSafePoint stores the marshalable safe point of a service.
+func (kv *KV) SaveServiceGCSafePoint(serviceID string, safePoint interface{}) error {
+	value, err := json.Marshal(safePoint)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return kv.Save(kv.ctx, serviceGCSafePointPath(serviceID), string(value))
+}
+
+// DeleteServiceGCSafePoint deletes the safe point of a service.
+func (kv *KV) DeleteServiceGCSafePoint(serviceID string) error {
+	return kv.Delete(kv.ctx, serviceGCSafePointPath(serviceID))
+}
+
+// LoadServiceGCSafePoints loads the safe points of all services, f decodes
+// each of them and returns the service ID.
+func (kv *KV) LoadServiceGCSafePoints(f func(data []byte) (string, error)) error {
+	// The keys are in ["service/", "service0"), '0' is next to '/'.
+	key, endKey := serviceGCSafePointPath("")+"/", serviceGCSafePointPath("")+"0"
+	for {
+		res, err := kv.LoadRange(kv.ctx, key, endKey, minKVRangeLimit)
+		if err != nil {
+			return err
+		}
+		for _, s := range res {
+			serviceID, err := f([]byte(s))
+			if err != nil {
+				return err
+			}
+			key = serviceGCSafePointPath(serviceID) + "\x00"
+		}
+		if len(res) < minKVRangeLimit {
+			return nil
+		}
+	}
+}
+
+func loadProto(ctx context.Context, kv KVBase, key string, msg proto.Message) (bool, error) {
+	value, err := kv.Load(ctx, key)
 	if err != nil {
 		return false, err
 	}
@@ -284,10 +797,10 @@ func loadProto(kv KVBase, key string, msg proto.Message) (bool, error) {
 	return true, errors.WithStack(err)
 }
 
-func saveProto(kv KVBase, key string, msg proto.Message) error {
+func saveProto(ctx context.Context, kv KVBase, key string, msg proto.Message) error {
 	value, err := proto.Marshal(msg)
 	if err != nil {
 		return errors.WithStack(err)
 	}
-	return kv.Save(key, string(value))
+	return kv.Save(ctx, key, string(value))
 }
@@ -14,17 +14,19 @@
 package core
 
 import (
+	"context"
 	"sync"
 
 	"github.com/google/btree"
 )
 
-// KVBase is an abstract interface for load/save pd cluster data.
+// KVBase is an abstract interface for load/save pd cluster data. The
+// operations are canceled when ctx is done.
 type KVBase interface {
-	Load(key string) (string, error)
-	LoadRange(key, endKey string, limit int) ([]string, error)
-	Save(key, value string) error
-	Delete(key string) error
+	Load(ctx context.Context, key string) (string, error)
+	LoadRange(ctx context.Context, key, endKey string, limit int) ([]string, error)
+	Save(ctx context.Context, key, value string) error
+	Delete(ctx context.Context, key string) error
 }
 
 type memoryKV struct {
@@ -47,7 +49,7 @@ func (s memoryKVItem) Less(than btree.Item) bool {
 	return s.key < than.(memoryKVItem).key
 }
 
-func (kv *memoryKV) Load(key string) (string, error) {
+func (kv *memoryKV) Load(ctx context.Context, key string) (string, error) {
 	kv.RLock()
 	defer kv.RUnlock()
 	item := kv.tree.Get(memoryKVItem{key, ""})
@@ -57,7 +59,7 @@ func (kv *memoryKV) Load(key string) (string, error) {
 	return item.(memoryKVItem).value, nil
 }
 
-func (kv *memoryKV) LoadRange(key, endKey string, limit int) ([]string, error) {
+func (kv *memoryKV) LoadRange(ctx context.Context, key, endKey string, limit int) ([]string, error) {
 	kv.RLock()
 	defer kv.RUnlock()
 	res := make([]string, 0, limit)
@@ -68,14 +70,14 @@ func (kv *memoryKV) LoadRange(key, endKey string, limit int) ([]string, error) {
 	return res, nil
 }
 
-func (kv *memoryKV) Save(key, value string) error {
+func (kv *memoryKV) Save(ctx context.Context, key, value string) error {
 	kv.Lock()
 	defer kv.Unlock()
 	kv.tree.ReplaceOrInsert(memoryKVItem{key, value})
 	return nil
 }
 
-func (kv *memoryKV) Delete(key string) error {
+func (kv *memoryKV) Delete(ctx context.Context, key string) error {
 	kv.Lock()
 	defer kv.Unlock()
 
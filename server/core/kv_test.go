@@ -14,8 +14,12 @@
 package core
 
 import (
+	"context"
 	"fmt"
+	"io/ioutil"
 	"math"
+	"os"
+	"time"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/metapb"
@@ -72,6 +76,55 @@ func (s *testKVSuite) TestBasic(c *C) {
 	c.Assert(err, IsNil)
 }
 
+func (s *testKVSuite) TestRegionKVFlushPolicy(c *C) {
+	dir, err := ioutil.TempDir("/tmp", "test_region_kv")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+
+	_, err = NewRegionKVWithOptions(dir, RegionKVOptions{FlushPolicy: "unknown"})
+	c.Assert(err, NotNil)
+
+	kv, err := NewRegionKVWithOptions(dir, RegionKVOptions{
+		FlushPolicy:   FlushPolicyInterval,
+		FlushInterval: time.Hour,
+		BatchSize:     3,
+	})
+	c.Assert(err, IsNil)
+	regions := make([]*metapb.Region, 0, 4)
+	for i := 0; i < 4; i++ {
+		regions = append(regions, newTestRegionMeta(uint64(i)))
+	}
+	c.Assert(kv.SaveRegion(regions[0]), IsNil)
+	c.Assert(kv.SaveRegion(regions[1]), IsNil)
+	// The regions are cached but not written.
+	_, err = kv.leveldbKV.Load(context.Background(), regionPath(0))
+	c.Assert(err, NotNil)
+	region := &metapb.Region{}
+	ok, err := loadProto(context.Background(), kv, regionPath(0), region)
+	c.Assert(ok, IsTrue)
+	c.Assert(err, IsNil)
+	c.Assert(region, DeepEquals, regions[0])
+
+	// The deleted region is not written back.
+	c.Assert(deleteRegion(context.Background(), kv, regions[1]), IsNil)
+	c.Assert(kv.SaveRegion(regions[2]), IsNil)
+	c.Assert(kv.SaveRegion(regions[3]), IsNil)
+	for _, id := range []uint64{0, 2, 3} {
+		_, err = kv.leveldbKV.Load(context.Background(), regionPath(id))
+		c.Assert(err, IsNil)
+	}
+	_, err = kv.leveldbKV.Load(context.Background(), regionPath(1))
+	c.Assert(err, NotNil)
+	c.Assert(kv.Close(), IsNil)
+
+	kv, err = NewRegionKVWithOptions(dir, RegionKVOptions{FlushPolicy: FlushPolicySync})
+	c.Assert(err, IsNil)
+	defer kv.Close()
+	c.Assert(kv.SaveRegion(regions[1]), IsNil)
+	_, err = kv.leveldbKV.Load(context.Background(), regionPath(1))
+	c.Assert(err, IsNil)
+}
+
 func mustSaveStores(c *C, kv *KV, n int) []*metapb.Store {
 	stores := make([]*metapb.Store, 0, n)
 	for i := 0; i < n; i++ {
@@ -117,6 +170,48 @@ func (s *testKVSuite) TestStoreWeight(c *C) {
 	}
 }
 
+func (s *testKVSuite) TestStoreCordon(c *C) {
+	kv := NewKV(NewMemoryKV())
+	cache := NewStoresInfo()
+	const n = 3
+
+	mustSaveStores(c, kv, n)
+	deadline := time.Unix(time.Now().Add(time.Hour).Unix(), 0)
+	c.Assert(kv.SaveStoreCordon(1, time.Time{}), IsNil)
+	c.Assert(kv.SaveStoreCordon(2, deadline), IsNil)
+	c.Assert(kv.LoadStores(cache), IsNil)
+	c.Assert(cache.GetStore(0).IsCordoned(), IsFalse)
+	c.Assert(cache.GetStore(1).IsCordoned(), IsTrue)
+	c.Assert(cache.GetStore(1).GetCordonDeadline().IsZero(), IsTrue)
+	c.Assert(cache.GetStore(2).IsCordoned(), IsTrue)
+	c.Assert(cache.GetStore(2).GetCordonDeadline().Equal(deadline), IsTrue)
+
+	c.Assert(kv.DeleteStoreCordon(1), IsNil)
+	cache = NewStoresInfo()
+	c.Assert(kv.LoadStores(cache), IsNil)
+	c.Assert(cache.GetStore(1).IsCordoned(), IsFalse)
+}
+
+func (s *testKVSuite) TestStoreReplacement(c *C) {
+	kv := NewKV(NewMemoryKV())
+	cache := NewStoresInfo()
+	const n = 3
+
+	mustSaveStores(c, kv, n)
+	replacement := &StoreReplacement{StoreID: 2, RegionCount: 10, RegionSize: 100, StartTime: time.Unix(time.Now().Unix(), 0)}
+	c.Assert(kv.SaveStoreReplacement(1, replacement), IsNil)
+	c.Assert(kv.LoadStores(cache), IsNil)
+	c.Assert(cache.GetStore(0).GetReplacement(), IsNil)
+	c.Assert(cache.GetStore(1).GetReplacement().StartTime.Equal(replacement.StartTime), IsTrue)
+	cache.GetStore(1).GetReplacement().StartTime = replacement.StartTime
+	c.Assert(cache.GetStore(1).GetReplacement(), DeepEquals, replacement)
+
+	c.Assert(kv.DeleteStoreReplacement(1), IsNil)
+	cache = NewStoresInfo()
+	c.Assert(kv.LoadStores(cache), IsNil)
+	c.Assert(cache.GetStore(1).GetReplacement(), IsNil)
+}
+
 func mustSaveRegions(c *C, kv *KV, n int) []*metapb.Region {
 	regions := make([]*metapb.Region, 0, n)
 	for i := 0; i < n; i++ {
@@ -158,6 +253,21 @@ func (s *testKVSuite) TestLoadRegionsExceedRangeLimit(c *C) {
 	}
 }
 
+func (s *testKVSuite) TestScanRegions(c *C) {
+	kv := NewKV(NewMemoryKV())
+	regions := mustSaveRegions(c, kv, 10)
+
+	res, err := kv.ScanRegions(3, 4)
+	c.Assert(err, IsNil)
+	c.Assert(res, DeepEquals, regions[3:7])
+	res, err = kv.ScanRegions(8, 4)
+	c.Assert(err, IsNil)
+	c.Assert(res, DeepEquals, regions[8:])
+	res, err = kv.ScanRegions(10, 4)
+	c.Assert(err, IsNil)
+	c.Assert(res, HasLen, 0)
+}
+
 func (s *testKVSuite) TestLoadGCSafePoint(c *C) {
 	kv := NewKV(NewMemoryKV())
 	testData := []uint64{0, 1, 2, 233, 2333, 23333333333, math.MaxUint64}
@@ -179,11 +289,11 @@ type KVWithMaxRangeLimit struct {
 	rangeLimit int
 }
 
-func (kv *KVWithMaxRangeLimit) LoadRange(key, endKey string, limit int) ([]string, error) {
+func (kv *KVWithMaxRangeLimit) LoadRange(ctx context.Context, key, endKey string, limit int) ([]string, error) {
 	if limit > kv.rangeLimit {
 		return nil, errors.Errorf("limit %v exceed max rangeLimit %v", limit, kv.rangeLimit)
 	}
-	return kv.KVBase.LoadRange(key, endKey, limit)
+	return kv.KVBase.LoadRange(ctx, key, endKey, limit)
 }
 
 func newTestRegionMeta(regionID uint64) *metapb.Region {
@@ -14,10 +14,13 @@
 package core
 
 import (
+	"context"
+
 	"github.com/gogo/protobuf/proto"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pkg/errors"
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
 	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
@@ -34,7 +37,7 @@ func newLeveldbKV(path string) (*leveldbKV, error) {
 	return &leveldbKV{db: db}, nil
 }
 
-func (kv *leveldbKV) Load(key string) (string, error) {
+func (kv *leveldbKV) Load(ctx context.Context, key string) (string, error) {
 	v, err := kv.db.Get([]byte(key), nil)
 	if err != nil {
 		return "", errors.WithStack(err)
@@ -42,7 +45,7 @@ func (kv *leveldbKV) Load(key string) (string, error) {
 	return string(v), err
 }
 
-func (kv *leveldbKV) LoadRange(startKey, endKey string, limit int) ([]string, error) {
+func (kv *leveldbKV) LoadRange(ctx context.Context, startKey, endKey string, limit int) ([]string, error) {
 	iter := kv.db.NewIterator(&util.Range{Start: []byte(startKey), Limit: []byte(endKey)}, nil)
 	values := make([]string, 0, limit)
 	count := 0
@@ -57,15 +60,30 @@ func (kv *leveldbKV) LoadRange(startKey, endKey string, limit int) ([]string, er
 	return values, nil
 }
 
-func (kv *leveldbKV) Save(key, value string) error {
+// Scan calls f for all key-value pairs in ascending order of keys until f
+// returns an error.
+func (kv *leveldbKV) Scan(f func(key, value []byte) error) error {
+	iter := kv.db.NewIterator(nil, nil)
+	defer iter.Release()
+	for iter.Next() {
+		if err := f(iter.Key(), iter.Value()); err != nil {
+			return err
+		}
+	}
+	return errors.WithStack(iter.Error())
+}
+
+func (kv *leveldbKV) Save(ctx context.Context, key, value string) error {
 	return errors.WithStack(kv.db.Put([]byte(key), []byte(value), nil))
 }
 
-func (kv *leveldbKV) Delete(key string) error {
+func (kv *leveldbKV) Delete(ctx context.Context, key string) error {
 	return errors.WithStack(kv.db.Delete([]byte(key), nil))
 }
 
-func (kv *leveldbKV) SaveRegions(regions map[string]*metapb.Region) error {
+// SaveRegions writes the regions in a batch, sync is true to fsync after the
+// write.
+func (kv *leveldbKV) SaveRegions(regions map[string]*metapb.Region, sync bool) error {
 	batch := new(leveldb.Batch)
 
 	for key, r := range regions {
@@ -75,7 +93,7 @@ func (kv *leveldbKV) SaveRegions(regions map[string]*metapb.Region) error {
 		}
 		batch.Put([]byte(key), value)
 	}
-	return errors.WithStack(kv.db.Write(batch, nil))
+	return errors.WithStack(kv.db.Write(batch, &opt.WriteOptions{Sync: sync}))
 }
 
 func (kv *leveldbKV) Close() error {
@@ -0,0 +1,59 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	regionFlushCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "region_storage",
+			Name:      "flush_count",
+			Help:      "Counter of region storage flushes.",
+		}, []string{"reason", "result"})
+
+	regionFlushDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "pd",
+			Subsystem: "region_storage",
+			Name:      "flush_duration_seconds",
+			Help:      "Bucketed histogram of processing time (s) of region storage flushes.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 13),
+		}, []string{"reason"})
+
+	regionFlushBatchSize = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "pd",
+			Subsystem: "region_storage",
+			Name:      "flush_batch_size",
+			Help:      "Bucketed histogram of the count of regions in a flush.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+		})
+
+	regionPendingGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "region_storage",
+			Name:      "pending_regions",
+			Help:      "The count of regions waiting to be flushed.",
+		})
+)
+
+func init() {
+	prometheus.MustRegister(regionFlushCounter)
+	prometheus.MustRegister(regionFlushDuration)
+	prometheus.MustRegister(regionFlushBatchSize)
+	prometheus.MustRegister(regionPendingGauge)
+}
@@ -30,16 +30,25 @@ import (
 // RegionInfo records detail region info.
 // Read-Only once created.
 type RegionInfo struct {
-	meta            *metapb.Region
-	learners        []*metapb.Peer
-	voters          []*metapb.Peer
-	leader          *metapb.Peer
-	downPeers       []*pdpb.PeerStats
-	pendingPeers    []*metapb.Peer
+	meta         *metapb.Region
+	learners     []*metapb.Peer
+	voters       []*metapb.Peer
+	leader       *metapb.Peer
+	downPeers    []*pdpb.PeerStats
+	pendingPeers []*metapb.Peer
+	// witnesses are the voters which keep only the Raft log, as reported by
+	// the stores. They are kept by PD since metapb.Peer has no witness role.
+	witnesses       []*metapb.Peer
 	writtenBytes    uint64
 	readBytes       uint64
+	writtenKeys     uint64
+	readKeys        uint64
+	interval        uint64
 	approximateSize int64
 	approximateKeys int64
+	// createTime is when the region is created by splitting, it is zero for
+	// the regions loaded from the storage.
+	createTime time.Time
 }
 
 // NewRegionInfo creates RegionInfo with region's meta and leader peer.
@@ -91,9 +100,14 @@ func RegionFromHeartbeat(heartbeat *pdpb.RegionHeartbeatRequest) *RegionInfo {
 		pendingPeers:    heartbeat.GetPendingPeers(),
 		writtenBytes:    heartbeat.GetBytesWritten(),
 		readBytes:       heartbeat.GetBytesRead(),
+		writtenKeys:     heartbeat.GetKeysWritten(),
+		readKeys:        heartbeat.GetKeysRead(),
 		approximateSize: int64(regionSize),
 		approximateKeys: int64(heartbeat.GetApproximateKeys()),
 	}
+	if interval := heartbeat.GetInterval(); interval.GetEndTimestamp() > interval.GetStartTimestamp() {
+		region.interval = interval.GetEndTimestamp() - interval.GetStartTimestamp()
+	}
 
 	classifyVoterAndLearner(region)
 	return region
@@ -109,16 +123,25 @@ func (r *RegionInfo) Clone(opts ...RegionCreateOption) *RegionInfo {
 	for _, peer := range r.pendingPeers {
 		pendingPeers = append(pendingPeers, proto.Clone(peer).(*metapb.Peer))
 	}
+	var witnesses []*metapb.Peer
+	for _, peer := range r.witnesses {
+		witnesses = append(witnesses, proto.Clone(peer).(*metapb.Peer))
+	}
 
 	region := &RegionInfo{
 		meta:            proto.Clone(r.meta).(*metapb.Region),
 		leader:          proto.Clone(r.leader).(*metapb.Peer),
 		downPeers:       downPeers,
 		pendingPeers:    pendingPeers,
+		witnesses:       witnesses,
 		writtenBytes:    r.writtenBytes,
 		readBytes:       r.readBytes,
+		writtenKeys:     r.writtenKeys,
+		readKeys:        r.readKeys,
+		interval:        r.interval,
 		approximateSize: r.approximateSize,
 		approximateKeys: r.approximateKeys,
+		createTime:      r.createTime,
 	}
 
 	for _, opt := range opts {
@@ -188,6 +211,21 @@ func (r *RegionInfo) GetPendingPeer(peerID uint64) *metapb.Peer {
 	return nil
 }
 
+// GetWitnesses returns the witnesses.
+func (r *RegionInfo) GetWitnesses() []*metapb.Peer {
+	return r.witnesses
+}
+
+// GetWitness returns the witness with specified peer id.
+func (r *RegionInfo) GetWitness(peerID uint64) *metapb.Peer {
+	for _, peer := range r.witnesses {
+		if peer.GetId() == peerID {
+			return peer
+		}
+	}
+	return nil
+}
+
 // GetPendingVoter returns the pending voter with specified peer id.
 func (r *RegionInfo) GetPendingVoter(peerID uint64) *metapb.Peer {
 	for _, peer := range r.pendingPeers {
@@ -329,6 +367,22 @@ func (r *RegionInfo) GetBytesWritten() uint64 {
 	return r.writtenBytes
 }
 
+// GetKeysRead returns the read keys of the region.
+func (r *RegionInfo) GetKeysRead() uint64 {
+	return r.readKeys
+}
+
+// GetKeysWritten returns the written keys of the region.
+func (r *RegionInfo) GetKeysWritten() uint64 {
+	return r.writtenKeys
+}
+
+// GetInterval returns the seconds of the period in which the flow is
+// reported, 0 if it is unknown.
+func (r *RegionInfo) GetInterval() uint64 {
+	return r.interval
+}
+
 // GetLeader returns the leader of the region.
 func (r *RegionInfo) GetLeader() *metapb.Peer {
 	return r.leader
@@ -354,6 +408,11 @@ func (r *RegionInfo) GetRegionEpoch() *metapb.RegionEpoch {
 	return r.meta.RegionEpoch
 }
 
+// GetCreateTime returns the time when the region is created by splitting.
+func (r *RegionInfo) GetCreateTime() time.Time {
+	return r.createTime
+}
+
 // RegionStat records each hot region's statistics
 type RegionStat struct {
 	RegionID  uint64 `json:"region_id"`
@@ -488,6 +547,10 @@ type RegionsInfo struct {
 	followers    map[uint64]*regionMap // storeID -> regionID -> regionInfo
 	learners     map[uint64]*regionMap // storeID -> regionID -> regionInfo
 	pendingPeers map[uint64]*regionMap // storeID -> regionID -> regionInfo
+	// version is increased whenever a region is added or removed.
+	version uint64
+	// observers are notified of the region changes.
+	observers *regionObservers
 }
 
 // NewRegionsInfo creates RegionsInfo with tree, regions, leaders and followers
@@ -499,9 +562,21 @@ func NewRegionsInfo() *RegionsInfo {
 		followers:    make(map[uint64]*regionMap),
 		learners:     make(map[uint64]*regionMap),
 		pendingPeers: make(map[uint64]*regionMap),
+		observers:    newRegionObservers(),
 	}
 }
 
+// Subscribe adds an observer of the region changes, and returns its ID to
+// unsubscribe.
+func (r *RegionsInfo) Subscribe(observer RegionObserver) uint64 {
+	return r.observers.subscribe(observer)
+}
+
+// Unsubscribe removes the observer of the region changes.
+func (r *RegionsInfo) Unsubscribe(id uint64) {
+	r.observers.unsubscribe(id)
+}
+
 // GetRegion return the RegionInfo with regionID
 func (r *RegionsInfo) GetRegion(regionID uint64) *RegionInfo {
 	region := r.regions.Get(regionID)
@@ -513,10 +588,23 @@ func (r *RegionsInfo) GetRegion(regionID uint64) *RegionInfo {
 
 // SetRegion set the RegionInfo with regionID
 func (r *RegionsInfo) SetRegion(region *RegionInfo) []*metapb.Region {
-	if origin := r.regions.Get(region.GetID()); origin != nil {
-		r.RemoveRegion(origin)
+	origin, overlaps := r.setRegion(region)
+	if origin != nil {
+		r.observers.publish(&RegionEvent{Type: RegionUpdated, Region: region, Origin: origin})
+	} else {
+		r.observers.publish(&RegionEvent{Type: RegionCreated, Region: region})
 	}
-	return r.AddRegion(region)
+	return r.publishOverlaps(overlaps)
+}
+
+// setRegion is SetRegion without publishing the events, it returns the
+// replaced region and the overlapped regions.
+func (r *RegionsInfo) setRegion(region *RegionInfo) (*RegionInfo, []*RegionInfo) {
+	origin := r.regions.Get(region.GetID())
+	if origin != nil {
+		r.removeRegion(origin)
+	}
+	return origin, r.addRegion(region)
 }
 
 // Length return the RegionsInfo length
@@ -524,6 +612,12 @@ func (r *RegionsInfo) Length() int {
 	return r.regions.Len()
 }
 
+// Version returns the version of the regions, which changes whenever the
+// regions change.
+func (r *RegionsInfo) Version() uint64 {
+	return r.version
+}
+
 // TreeLength return the RegionsInfo tree length(now only used in test)
 func (r *RegionsInfo) TreeLength() int {
 	return r.tree.length()
@@ -531,10 +625,30 @@ func (r *RegionsInfo) TreeLength() int {
 
 // AddRegion add RegionInfo to regionTree and regionMap, also update leadres and followers by region peers
 func (r *RegionsInfo) AddRegion(region *RegionInfo) []*metapb.Region {
-	// Add to tree and regions.
-	overlaps := r.tree.update(region.meta)
+	overlaps := r.addRegion(region)
+	r.observers.publish(&RegionEvent{Type: RegionCreated, Region: region})
+	return r.publishOverlaps(overlaps)
+}
+
+// publishOverlaps publishes the deletion of the overlapped regions, and
+// returns their meta.
+func (r *RegionsInfo) publishOverlaps(overlaps []*RegionInfo) []*metapb.Region {
+	metas := make([]*metapb.Region, 0, len(overlaps))
 	for _, item := range overlaps {
-		r.RemoveRegion(r.GetRegion(item.Id))
+		r.observers.publish(&RegionEvent{Type: RegionDeleted, Region: item})
+		metas = append(metas, item.GetMeta())
+	}
+	return metas
+}
+
+func (r *RegionsInfo) addRegion(region *RegionInfo) []*RegionInfo {
+	r.version++
+	// Add to tree and regions.
+	var overlaps []*RegionInfo
+	for _, item := range r.tree.update(region.meta) {
+		origin := r.GetRegion(item.Id)
+		r.removeRegion(origin)
+		overlaps = append(overlaps, origin)
 	}
 
 	r.regions.Put(region)
@@ -587,6 +701,12 @@ func (r *RegionsInfo) AddRegion(region *RegionInfo) []*metapb.Region {
 
 // RemoveRegion remove RegionInfo from regionTree and regionMap
 func (r *RegionsInfo) RemoveRegion(region *RegionInfo) {
+	r.removeRegion(region)
+	r.observers.publish(&RegionEvent{Type: RegionDeleted, Region: region})
+}
+
+func (r *RegionsInfo) removeRegion(region *RegionInfo) {
+	r.version++
 	// Remove from tree and regions.
 	r.tree.remove(region.meta)
 	r.regions.Delete(region.GetID())
@@ -820,6 +940,68 @@ func (r *RegionsInfo) GetRegionStats(startKey, endKey []byte) *RegionStats {
 	return stats
 }
 
+// GetRegionsInRange returns at most limit regions overlapping with the range
+// [startKey, endKey), the first one may start before startKey. An empty endKey
+// means the end of the key space, and a non-positive limit means no limit.
+func (r *RegionsInfo) GetRegionsInRange(startKey, endKey []byte, limit int) []*RegionInfo {
+	var res []*RegionInfo
+	r.tree.scanOverlapped(startKey, endKey, func(meta *metapb.Region) bool {
+		if region := r.GetRegion(meta.GetId()); region != nil {
+			res = append(res, region)
+		}
+		return limit <= 0 || len(res) < limit
+	})
+	return res
+}
+
+// RangeSummary is the aggregation of the regions overlapping with a key range.
+type RangeSummary struct {
+	Count           int   `json:"count"`
+	ApproximateSize int64 `json:"approximate_size"`
+	ApproximateKeys int64 `json:"approximate_keys"`
+	// Sampled is true if the size and keys are estimated by a part of the
+	// regions.
+	Sampled bool `json:"sampled"`
+}
+
+// GetRangeSummary sums up the regions overlapping with the range [startKey,
+// endKey). The count is exact, but the size and keys are estimated by at most
+// sampleLimit regions evenly picked from the range if there are more regions.
+// A non-positive sampleLimit means no sampling.
+func (r *RegionsInfo) GetRangeSummary(startKey, endKey []byte, sampleLimit int) *RangeSummary {
+	summary := &RangeSummary{}
+	// Counting only walks the tree, which is much cheaper than looking up the
+	// region infos.
+	r.tree.scanOverlapped(startKey, endKey, func(*metapb.Region) bool {
+		summary.Count++
+		return true
+	})
+	step := 1
+	if sampleLimit > 0 && summary.Count > sampleLimit {
+		step = (summary.Count + sampleLimit - 1) / sampleLimit
+		summary.Sampled = true
+	}
+
+	var i, sampled int
+	r.tree.scanOverlapped(startKey, endKey, func(meta *metapb.Region) bool {
+		if i%step == 0 {
+			if region := r.GetRegion(meta.GetId()); region != nil {
+				summary.ApproximateSize += region.approximateSize
+				summary.ApproximateKeys += region.approximateKeys
+				sampled++
+			}
+		}
+		i++
+		return true
+	})
+	if summary.Sampled && sampled > 0 {
+		ratio := float64(summary.Count) / float64(sampled)
+		summary.ApproximateSize = int64(float64(summary.ApproximateSize) * ratio)
+		summary.ApproximateKeys = int64(float64(summary.ApproximateKeys) * ratio)
+	}
+	return summary
+}
+
 const randomRegionMaxRetry = 10
 
 func randRegion(regions *regionMap, opts ...RegionOption) *RegionInfo {
@@ -0,0 +1,88 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "sync"
+
+// RegionEventType is the type of the change of a region in RegionsInfo.
+type RegionEventType int
+
+// The types of region events.
+const (
+	// RegionCreated means the region is added.
+	RegionCreated RegionEventType = iota
+	// RegionUpdated means the region is replaced by a newer one.
+	RegionUpdated
+	// RegionDeleted means the region is removed, or is overlapped by a new
+	// region after split or merge.
+	RegionDeleted
+)
+
+func (t RegionEventType) String() string {
+	switch t {
+	case RegionCreated:
+		return "created"
+	case RegionUpdated:
+		return "updated"
+	case RegionDeleted:
+		return "deleted"
+	}
+	return "unknown"
+}
+
+// RegionEvent is a change of a region in RegionsInfo.
+type RegionEvent struct {
+	Type RegionEventType
+	// Region is the region after the change, or the deleted region.
+	Region *RegionInfo
+	// Origin is the region before the update, nil for other events.
+	Origin *RegionInfo
+}
+
+// RegionObserver observes the changes of regions. It is called synchronously
+// with the change, so it must be fast and must not change RegionsInfo.
+type RegionObserver func(e *RegionEvent)
+
+// regionObservers dispatches the region events to the subscribed observers.
+type regionObservers struct {
+	sync.RWMutex
+	nextID    uint64
+	observers map[uint64]RegionObserver
+}
+
+func newRegionObservers() *regionObservers {
+	return &regionObservers{observers: make(map[uint64]RegionObserver)}
+}
+
+func (o *regionObservers) subscribe(observer RegionObserver) uint64 {
+	o.Lock()
+	defer o.Unlock()
+	o.nextID++
+	o.observers[o.nextID] = observer
+	return o.nextID
+}
+
+func (o *regionObservers) unsubscribe(id uint64) {
+	o.Lock()
+	defer o.Unlock()
+	delete(o.observers, id)
+}
+
+func (o *regionObservers) publish(e *RegionEvent) {
+	o.RLock()
+	defer o.RUnlock()
+	for _, observer := range o.observers {
+		observer(e)
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/btree"
+)
+
+type indexItem struct {
+	startKey []byte
+	region   *RegionInfo
+}
+
+// Less returns true if the region start key is less than the other.
+func (i *indexItem) Less(other btree.Item) bool {
+	return bytes.Compare(i.startKey, other.(*indexItem).startKey) < 0
+}
+
+// RegionIndex keeps the regions by ID and by key for the readers which do not
+// lock RegionsInfo. It is updated by the events of RegionsInfo, and every
+// update publishes a copy-on-write snapshot of the key index, so that the
+// readers never wait for the writers. The regions read from it may be newer
+// than those in RegionsInfo the writers are working on, but never older than
+// the last published update.
+type RegionIndex struct {
+	byID sync.Map
+	// tree is only changed by the updater, the readers use the snapshot.
+	tree     *btree.BTree
+	snapshot atomic.Value
+}
+
+// NewRegionIndex creates an empty RegionIndex.
+func NewRegionIndex() *RegionIndex {
+	x := &RegionIndex{tree: btree.New(defaultBTreeDegree)}
+	x.snapshot.Store(x.tree.Clone())
+	return x
+}
+
+// OnRegionEvent applies the change of RegionsInfo. It must be subscribed to
+// RegionsInfo, whose events are published serially by its writers.
+func (x *RegionIndex) OnRegionEvent(e *RegionEvent) {
+	switch e.Type {
+	case RegionCreated, RegionUpdated:
+		if e.Origin != nil {
+			x.deleteItem(e.Origin)
+		}
+		x.tree.ReplaceOrInsert(&indexItem{startKey: e.Region.GetStartKey(), region: e.Region})
+		x.byID.Store(e.Region.GetID(), e.Region)
+	case RegionDeleted:
+		x.deleteItem(e.Region)
+		// The region replaced by another one with the same ID is also deleted.
+		if r, ok := x.byID.Load(e.Region.GetID()); ok && r.(*RegionInfo) == e.Region {
+			x.byID.Delete(e.Region.GetID())
+		}
+	}
+	x.snapshot.Store(x.tree.Clone())
+}
+
+// deleteItem deletes the region from the tree, unless its start key is taken
+// by a newer region which overlaps it.
+func (x *RegionIndex) deleteItem(region *RegionInfo) {
+	item := x.tree.Get(&indexItem{startKey: region.GetStartKey()})
+	if item != nil && item.(*indexItem).region == region {
+		x.tree.Delete(item)
+	}
+}
+
+// Load replaces the regions with the ones loaded without events. It must not
+// run with OnRegionEvent at the same time.
+func (x *RegionIndex) Load(regions []*RegionInfo) {
+	x.byID.Range(func(id, _ interface{}) bool {
+		x.byID.Delete(id)
+		return true
+	})
+	x.tree = btree.New(defaultBTreeDegree)
+	for _, r := range regions {
+		x.tree.ReplaceOrInsert(&indexItem{startKey: r.GetStartKey(), region: r})
+		x.byID.Store(r.GetID(), r)
+	}
+	x.snapshot.Store(x.tree.Clone())
+}
+
+func (x *RegionIndex) load() *btree.BTree {
+	return x.snapshot.Load().(*btree.BTree)
+}
+
+// GetRegion returns the region by ID.
+func (x *RegionIndex) GetRegion(regionID uint64) *RegionInfo {
+	if r, ok := x.byID.Load(regionID); ok {
+		return r.(*RegionInfo)
+	}
+	return nil
+}
+
+// GetRegionCount returns the count of the regions.
+func (x *RegionIndex) GetRegionCount() int {
+	return x.load().Len()
+}
+
+// GetRegions returns all the regions in key order.
+func (x *RegionIndex) GetRegions() []*RegionInfo {
+	tree := x.load()
+	regions := make([]*RegionInfo, 0, tree.Len())
+	tree.Ascend(func(i btree.Item) bool {
+		regions = append(regions, i.(*indexItem).region)
+		return true
+	})
+	return regions
+}
+
+// SearchRegion returns the region containing the key.
+func (x *RegionIndex) SearchRegion(key []byte) *RegionInfo {
+	return search(x.load(), key)
+}
+
+func search(tree *btree.BTree, key []byte) *RegionInfo {
+	var result *RegionInfo
+	tree.DescendLessOrEqual(&indexItem{startKey: key}, func(i btree.Item) bool {
+		result = i.(*indexItem).region
+		return false
+	})
+	if result == nil {
+		return nil
+	}
+	if end := result.GetEndKey(); len(end) > 0 && bytes.Compare(key, end) >= 0 {
+		return nil
+	}
+	return result
+}
+
+// SearchPrevRegion returns the region adjacent to the left of the region
+// containing the key.
+func (x *RegionIndex) SearchPrevRegion(key []byte) *RegionInfo {
+	tree := x.load()
+	cur := search(tree, key)
+	if cur == nil {
+		return nil
+	}
+	var prev *RegionInfo
+	tree.DescendLessOrEqual(&indexItem{startKey: cur.GetStartKey()}, func(i btree.Item) bool {
+		if r := i.(*indexItem).region; r != cur {
+			prev = r
+			return false
+		}
+		return true
+	})
+	if prev == nil || !bytes.Equal(prev.GetEndKey(), cur.GetStartKey()) {
+		return nil
+	}
+	return prev
+}
+
+// ScanRange returns at most limit regions starting from startKey in key
+// order.
+func (x *RegionIndex) ScanRange(startKey []byte, limit int) []*RegionInfo {
+	res := make([]*RegionInfo, 0, limit)
+	x.load().AscendGreaterOrEqual(&indexItem{startKey: startKey}, func(i btree.Item) bool {
+		res = append(res, i.(*indexItem).region)
+		return len(res) < limit
+	})
+	return res
+}
+
+// GetRegionsInRange returns at most limit regions overlapping with the range
+// [startKey, endKey), the first one may start before startKey. An empty endKey
+// means the end of the key space, and a non-positive limit means no limit.
+func (x *RegionIndex) GetRegionsInRange(startKey, endKey []byte, limit int) []*RegionInfo {
+	tree := x.load()
+	var res []*RegionInfo
+	if r := search(tree, startKey); r != nil && !bytes.Equal(r.GetStartKey(), startKey) {
+		res = append(res, r)
+	}
+	if limit > 0 && len(res) >= limit {
+		return res
+	}
+	tree.AscendGreaterOrEqual(&indexItem{startKey: startKey}, func(i btree.Item) bool {
+		r := i.(*indexItem).region
+		if len(endKey) > 0 && bytes.Compare(r.GetStartKey(), endKey) >= 0 {
+			return false
+		}
+		res = append(res, r)
+		return limit <= 0 || len(res) < limit
+	})
+	return res
+}
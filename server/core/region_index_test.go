@@ -0,0 +1,86 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+)
+
+var _ = Suite(&testRegionIndexSuite{})
+
+type testRegionIndexSuite struct{}
+
+func (*testRegionIndexSuite) newRegion(id uint64, start, end string, version uint64) *RegionInfo {
+	meta := &metapb.Region{
+		Id:          id,
+		StartKey:    []byte(start),
+		EndKey:      []byte(end),
+		RegionEpoch: &metapb.RegionEpoch{Version: version},
+	}
+	return NewRegionInfo(meta, nil)
+}
+
+func (*testRegionIndexSuite) ids(regions []*RegionInfo) []uint64 {
+	res := make([]uint64, 0, len(regions))
+	for _, r := range regions {
+		res = append(res, r.GetID())
+	}
+	return res
+}
+
+func (s *testRegionIndexSuite) TestRegionIndex(c *C) {
+	regions := NewRegionsInfo()
+	index := NewRegionIndex()
+	regions.Subscribe(index.OnRegionEvent)
+
+	// Regions ["", "b"), ["b", "d"), ["d", "f"), ["f", "").
+	regions.SetRegion(s.newRegion(1, "", "b", 1))
+	regions.SetRegion(s.newRegion(2, "b", "d", 1))
+	regions.SetRegion(s.newRegion(3, "d", "f", 1))
+	regions.SetRegion(s.newRegion(4, "f", "", 1))
+	c.Assert(index.GetRegionCount(), Equals, 4)
+	c.Assert(s.ids(index.GetRegions()), DeepEquals, []uint64{1, 2, 3, 4})
+	c.Assert(index.GetRegion(2), Equals, regions.GetRegion(2))
+	c.Assert(index.SearchRegion([]byte("c")).GetID(), Equals, uint64(2))
+	c.Assert(index.SearchRegion([]byte("z")).GetID(), Equals, uint64(4))
+	c.Assert(index.SearchPrevRegion([]byte("c")).GetID(), Equals, uint64(1))
+	c.Assert(index.SearchPrevRegion([]byte("a")), IsNil)
+	c.Assert(s.ids(index.ScanRange([]byte("c"), 2)), DeepEquals, []uint64{3, 4})
+	c.Assert(s.ids(index.GetRegionsInRange([]byte("c"), []byte("f"), 0)), DeepEquals, []uint64{2, 3})
+	c.Assert(s.ids(index.GetRegionsInRange([]byte("c"), nil, 2)), DeepEquals, []uint64{2, 3})
+
+	// The snapshot read before an update is not changed by it.
+	before := index.GetRegions()
+
+	// Region 2 is merged into region 3.
+	regions.SetRegion(s.newRegion(3, "b", "f", 2))
+	c.Assert(index.GetRegion(2), IsNil)
+	c.Assert(index.GetRegion(3).GetStartKey(), DeepEquals, []byte("b"))
+	c.Assert(s.ids(index.GetRegions()), DeepEquals, []uint64{1, 3, 4})
+	c.Assert(index.SearchRegion([]byte("c")).GetID(), Equals, uint64(3))
+	c.Assert(index.SearchPrevRegion([]byte("e")).GetID(), Equals, uint64(1))
+	c.Assert(s.ids(before), DeepEquals, []uint64{1, 2, 3, 4})
+
+	// Adding the region with the same ID replaces it.
+	region := s.newRegion(3, "b", "f", 2)
+	regions.AddRegion(region)
+	c.Assert(index.GetRegion(3), Equals, region)
+	c.Assert(s.ids(index.GetRegions()), DeepEquals, []uint64{1, 3, 4})
+
+	regions.RemoveRegion(regions.GetRegion(4))
+	c.Assert(index.GetRegion(4), IsNil)
+	c.Assert(index.SearchRegion([]byte("z")), IsNil)
+	c.Assert(index.GetRegionCount(), Equals, 2)
+}
@@ -19,6 +19,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -26,18 +27,14 @@ import (
 
 var dirtyFlushTick = time.Second
 
-// RegionKV is used to save regions.
-type RegionKV struct {
-	*leveldbKV
-	mu           sync.RWMutex
-	batchRegions map[string]*metapb.Region
-	batchSize    int
-	cacheSize    int
-	flushRate    time.Duration
-	flushTime    time.Time
-	ctx          context.Context
-	cancel       context.CancelFunc
-}
+// Flush policies of RegionKV.
+const (
+	// FlushPolicySync writes every region to the disk synchronously.
+	FlushPolicySync = "sync"
+	// FlushPolicyInterval caches the regions in memory, and writes them in a
+	// batch when the batch is full or the flush interval is reached.
+	FlushPolicyInterval = "interval"
+)
 
 const (
 	//DefaultFlushRegionRate is the ttl to sync the regions to kv storage.
@@ -46,8 +43,60 @@ const (
 	defaultBatchSize = 100
 )
 
+// RegionKVOptions is the options of RegionKV.
+type RegionKVOptions struct {
+	// FlushPolicy is FlushPolicySync or FlushPolicyInterval.
+	FlushPolicy string
+	// FlushInterval is the max duration for a region to be cached before it
+	// is written, for FlushPolicyInterval.
+	FlushInterval time.Duration
+	// BatchSize is the max count of cached regions, for FlushPolicyInterval.
+	BatchSize int
+	// SyncBatch is true to fsync after each batch is written, for
+	// FlushPolicyInterval.
+	SyncBatch bool
+}
+
+// DefaultRegionKVOptions returns the default options of RegionKV.
+func DefaultRegionKVOptions() RegionKVOptions {
+	return RegionKVOptions{
+		FlushPolicy:   FlushPolicyInterval,
+		FlushInterval: defaultFlushRegionRate,
+		BatchSize:     defaultBatchSize,
+	}
+}
+
+// RegionKV is used to save regions.
+type RegionKV struct {
+	*leveldbKV
+	opts         RegionKVOptions
+	mu           sync.RWMutex
+	batchRegions map[string]*metapb.Region
+	// flushTime is the deadline to flush the oldest cached region.
+	flushTime time.Time
+	ctx       context.Context
+	cancel    context.CancelFunc
+}
+
 // NewRegionKV returns a kv storage that is used to save regions.
 func NewRegionKV(path string) (*RegionKV, error) {
+	return NewRegionKVWithOptions(path, DefaultRegionKVOptions())
+}
+
+// NewRegionKVWithOptions returns a kv storage that is used to save regions
+// with the options.
+func NewRegionKVWithOptions(path string, opts RegionKVOptions) (*RegionKV, error) {
+	switch opts.FlushPolicy {
+	case FlushPolicySync, FlushPolicyInterval:
+	default:
+		return nil, errors.Errorf("unknown region flush policy %q", opts.FlushPolicy)
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultBatchSize
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = defaultFlushRegionRate
+	}
 	levelDB, err := newLeveldbKV(path)
 	if err != nil {
 		return nil, err
@@ -55,19 +104,23 @@ func NewRegionKV(path string) (*RegionKV, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 	kv := &RegionKV{
 		leveldbKV:    levelDB,
-		batchSize:    defaultBatchSize,
-		flushRate:    defaultFlushRegionRate,
-		batchRegions: make(map[string]*metapb.Region, defaultBatchSize),
-		flushTime:    time.Now().Add(defaultFlushRegionRate),
+		opts:         opts,
+		batchRegions: make(map[string]*metapb.Region, opts.BatchSize),
 		ctx:          ctx,
 		cancel:       cancel,
 	}
-	kv.backgroundFlush()
+	if opts.FlushPolicy == FlushPolicyInterval {
+		kv.backgroundFlush()
+	}
 	return kv, nil
 }
 
 func (kv *RegionKV) backgroundFlush() {
-	ticker := time.NewTicker(dirtyFlushTick)
+	tick := dirtyFlushTick
+	if kv.opts.FlushInterval < tick {
+		tick = kv.opts.FlushInterval
+	}
+	ticker := time.NewTicker(tick)
 	var (
 		isFlush bool
 		err     error
@@ -78,12 +131,15 @@ func (kv *RegionKV) backgroundFlush() {
 			select {
 			case <-ticker.C:
 				kv.mu.RLock()
-				isFlush = kv.flushTime.Before(time.Now())
+				isFlush = len(kv.batchRegions) > 0 && kv.flushTime.Before(time.Now())
 				kv.mu.RUnlock()
 				if !isFlush {
 					continue
 				}
-				if err = kv.FlushRegion(); err != nil {
+				kv.mu.Lock()
+				err = kv.flush("interval")
+				kv.mu.Unlock()
+				if err != nil {
 					log.Error("flush regions error: ", err)
 				}
 			case <-kv.ctx.Done():
@@ -93,31 +149,80 @@ func (kv *RegionKV) backgroundFlush() {
 	}()
 }
 
-// SaveRegion saves one region to KV.
+// SaveRegion saves one region to KV. With FlushPolicyInterval, the region is
+// cached and written later in a batch.
 func (kv *RegionKV) SaveRegion(region *metapb.Region) error {
 	kv.mu.Lock()
 	defer kv.mu.Unlock()
-	if kv.cacheSize < kv.batchSize-1 {
-		kv.batchRegions[regionPath(region.GetId())] = region
-		kv.cacheSize++
+	key := regionPath(region.GetId())
+	if kv.opts.FlushPolicy == FlushPolicySync {
+		kv.batchRegions[key] = region
+		return kv.flush("sync")
+	}
 
-		kv.flushTime = time.Now().Add(kv.flushRate)
+	if len(kv.batchRegions) == 0 {
+		kv.flushTime = time.Now().Add(kv.opts.FlushInterval)
+	}
+	kv.batchRegions[key] = region
+	regionPendingGauge.Set(float64(len(kv.batchRegions)))
+	if len(kv.batchRegions) < kv.opts.BatchSize {
 		return nil
 	}
-	kv.batchRegions[regionPath(region.GetId())] = region
-	err := kv.flush()
+	return kv.flush("size")
+}
 
-	if err != nil {
-		return err
+// Load loads the value of the key, the cached region is returned if the key
+// is a region waiting to be flushed.
+func (kv *RegionKV) Load(ctx context.Context, key string) (string, error) {
+	kv.mu.RLock()
+	region, ok := kv.batchRegions[key]
+	kv.mu.RUnlock()
+	if ok {
+		value, err := proto.Marshal(region)
+		return string(value), errors.WithStack(err)
 	}
-	return nil
+	return kv.leveldbKV.Load(ctx, key)
 }
 
-func deleteRegion(kv KVBase, region *metapb.Region) error {
-	return kv.Delete(regionPath(region.GetId()))
+// LoadRange loads the values in the range after flushing the cached regions.
+func (kv *RegionKV) LoadRange(ctx context.Context, startKey, endKey string, limit int) ([]string, error) {
+	if err := kv.FlushRegion(); err != nil {
+		return nil, err
+	}
+	return kv.leveldbKV.LoadRange(ctx, startKey, endKey, limit)
 }
 
-func loadRegions(kv KVBase, regions *RegionsInfo) error {
+// Delete deletes the key, the cached region of the key is dropped so that it
+// is not written back later.
+func (kv *RegionKV) Delete(ctx context.Context, key string) error {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	delete(kv.batchRegions, key)
+	regionPendingGauge.Set(float64(len(kv.batchRegions)))
+	return kv.leveldbKV.Delete(ctx, key)
+}
+
+func deleteRegion(ctx context.Context, kv KVBase, region *metapb.Region) error {
+	return kv.Delete(ctx, regionPath(region.GetId()))
+}
+
+func scanRegions(ctx context.Context, kv KVBase, startID uint64, limit int) ([]*metapb.Region, error) {
+	res, err := kv.LoadRange(ctx, regionPath(startID), regionPath(math.MaxUint64), limit)
+	if err != nil {
+		return nil, err
+	}
+	regions := make([]*metapb.Region, 0, len(res))
+	for _, s := range res {
+		region := &metapb.Region{}
+		if err := region.Unmarshal([]byte(s)); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		regions = append(regions, region)
+	}
+	return regions, nil
+}
+
+func loadRegions(ctx context.Context, kv KVBase, regions *RegionsInfo) error {
 	nextID := uint64(0)
 	endKey := regionPath(math.MaxUint64)
 
@@ -127,7 +232,7 @@ func loadRegions(kv KVBase, regions *RegionsInfo) error {
 	rangeLimit := maxKVRangeLimit
 	for {
 		startKey := regionPath(nextID)
-		res, err := kv.LoadRange(startKey, endKey, rangeLimit)
+		res, err := kv.LoadRange(ctx, startKey, endKey, rangeLimit)
 		if err != nil {
 			if rangeLimit /= 2; rangeLimit >= minKVRangeLimit {
 				continue
@@ -142,9 +247,11 @@ func loadRegions(kv KVBase, regions *RegionsInfo) error {
 			}
 
 			nextID = region.GetId() + 1
-			overlaps := regions.SetRegion(NewRegionInfo(region, nil))
+			// The loaded regions are not changes, so they are not published
+			// to the observers.
+			_, overlaps := regions.setRegion(NewRegionInfo(region, nil))
 			for _, item := range overlaps {
-				if err := deleteRegion(kv, item); err != nil {
+				if err := deleteRegion(ctx, kv, item.GetMeta()); err != nil {
 					return err
 				}
 			}
@@ -160,15 +267,24 @@ func loadRegions(kv KVBase, regions *RegionsInfo) error {
 func (kv *RegionKV) FlushRegion() error {
 	kv.mu.Lock()
 	defer kv.mu.Unlock()
-	return kv.flush()
+	return kv.flush("manual")
 }
 
-func (kv *RegionKV) flush() error {
-	if err := kv.SaveRegions(kv.batchRegions); err != nil {
+func (kv *RegionKV) flush(reason string) error {
+	if len(kv.batchRegions) == 0 {
+		return nil
+	}
+	start := time.Now()
+	sync := kv.opts.FlushPolicy == FlushPolicySync || kv.opts.SyncBatch
+	if err := kv.SaveRegions(kv.batchRegions, sync); err != nil {
+		regionFlushCounter.WithLabelValues(reason, "failed").Inc()
 		return err
 	}
-	kv.cacheSize = 0
-	kv.batchRegions = make(map[string]*metapb.Region, kv.batchSize)
+	regionFlushCounter.WithLabelValues(reason, "success").Inc()
+	regionFlushDuration.WithLabelValues(reason).Observe(time.Since(start).Seconds())
+	regionFlushBatchSize.Observe(float64(len(kv.batchRegions)))
+	kv.batchRegions = make(map[string]*metapb.Region, kv.opts.BatchSize)
+	regionPendingGauge.Set(0)
 	return nil
 }
 
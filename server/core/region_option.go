@@ -14,6 +14,8 @@
 package core
 
 import (
+	"time"
+
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
 )
@@ -45,6 +47,13 @@ func WithPendingPeers(pengdingPeers []*metapb.Peer) RegionCreateOption {
 	}
 }
 
+// WithWitnesses sets the witnesses for the region.
+func WithWitnesses(witnesses []*metapb.Peer) RegionCreateOption {
+	return func(region *RegionInfo) {
+		region.witnesses = witnesses
+	}
+}
+
 // WithLearners sets the learners for the region.
 func WithLearners(learners []*metapb.Peer) RegionCreateOption {
 	return func(region *RegionInfo) {
@@ -139,6 +148,27 @@ func SetReadBytes(v uint64) RegionCreateOption {
 	}
 }
 
+// SetWrittenKeys sets the written keys for the region.
+func SetWrittenKeys(v uint64) RegionCreateOption {
+	return func(region *RegionInfo) {
+		region.writtenKeys = v
+	}
+}
+
+// SetReadKeys sets the read keys for the region.
+func SetReadKeys(v uint64) RegionCreateOption {
+	return func(region *RegionInfo) {
+		region.readKeys = v
+	}
+}
+
+// SetInterval sets the seconds of the period in which the flow is reported.
+func SetInterval(v uint64) RegionCreateOption {
+	return func(region *RegionInfo) {
+		region.interval = v
+	}
+}
+
 // SetApproximateSize sets the approximate size for the region.
 func SetApproximateSize(v int64) RegionCreateOption {
 	return func(region *RegionInfo) {
@@ -153,6 +183,13 @@ func SetApproximateKeys(v int64) RegionCreateOption {
 	}
 }
 
+// SetCreateTime sets the time when the region is created by splitting.
+func SetCreateTime(t time.Time) RegionCreateOption {
+	return func(region *RegionInfo) {
+		region.createTime = t
+	}
+}
+
 // SetRegionConfVer sets the config version for the reigon.
 func SetRegionConfVer(confVer uint64) RegionCreateOption {
 	return func(region *RegionInfo) {
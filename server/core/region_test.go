@@ -136,3 +136,80 @@ func (*testRegionKey) TestRegionKey(c *C) {
 		c.Assert(strings.Contains(s, t.expect), IsTrue)
 	}
 }
+
+var _ = Suite(&testRegionRangeSuite{})
+
+type testRegionRangeSuite struct{}
+
+func (*testRegionRangeSuite) TestRegionsInRange(c *C) {
+	regions := NewRegionsInfo()
+	// Regions ["", "b"), ["b", "d"), ["d", "f"), ... ["x", "z"), ["z", "").
+	keys := []string{"", "b", "d", "f", "h", "j", "l", "n", "p", "r", "t", "v", "x", "z", ""}
+	for i := 0; i < len(keys)-1; i++ {
+		meta := &metapb.Region{Id: uint64(i + 1), StartKey: []byte(keys[i]), EndKey: []byte(keys[i+1])}
+		regions.AddRegion(NewRegionInfo(meta, nil, SetApproximateSize(int64(i+1)), SetApproximateKeys(int64(10*(i+1)))))
+	}
+
+	ids := func(rs []*RegionInfo) []uint64 {
+		res := make([]uint64, 0, len(rs))
+		for _, r := range rs {
+			res = append(res, r.GetID())
+		}
+		return res
+	}
+	// The region containing the start key is included.
+	c.Assert(ids(regions.GetRegionsInRange([]byte("c"), []byte("h"), 0)), DeepEquals, []uint64{2, 3, 4})
+	c.Assert(ids(regions.GetRegionsInRange([]byte("b"), []byte("d"), 0)), DeepEquals, []uint64{2})
+	c.Assert(ids(regions.GetRegionsInRange([]byte("c"), []byte("h"), 2)), DeepEquals, []uint64{2, 3})
+	c.Assert(ids(regions.GetRegionsInRange([]byte("y"), nil, 0)), DeepEquals, []uint64{13, 14})
+	c.Assert(regions.GetRegionsInRange(nil, nil, 0), HasLen, 14)
+
+	summary := regions.GetRangeSummary([]byte("c"), []byte("h"), 0)
+	c.Assert(summary, DeepEquals, &RangeSummary{Count: 3, ApproximateSize: 9, ApproximateKeys: 90})
+
+	// Sample 7 of the 14 regions: 1, 3, 5, ..., 13.
+	summary = regions.GetRangeSummary(nil, nil, 7)
+	c.Assert(summary.Count, Equals, 14)
+	c.Assert(summary.Sampled, IsTrue)
+	c.Assert(summary.ApproximateSize, Equals, int64(98))
+	c.Assert(summary.ApproximateKeys, Equals, int64(980))
+}
+
+func (*testRegionRangeSuite) TestVersion(c *C) {
+	regions := NewRegionsInfo()
+	version := regions.Version()
+	meta := &metapb.Region{Id: 1, StartKey: []byte("a"), EndKey: []byte("b")}
+	region := NewRegionInfo(meta, nil)
+	regions.SetRegion(region)
+	c.Assert(regions.Version(), Not(Equals), version)
+	version = regions.Version()
+	c.Assert(regions.GetRegion(1), NotNil)
+	c.Assert(regions.Version(), Equals, version)
+	regions.RemoveRegion(region)
+	c.Assert(regions.Version(), Not(Equals), version)
+}
+
+func (*testRegionRangeSuite) TestObserver(c *C) {
+	regions := NewRegionsInfo()
+	var events []string
+	id := regions.Subscribe(func(e *RegionEvent) {
+		events = append(events, fmt.Sprintf("%s %d", e.Type, e.Region.GetID()))
+		if e.Type == RegionUpdated {
+			c.Assert(e.Origin.GetID(), Equals, e.Region.GetID())
+		}
+	})
+
+	region1 := NewRegionInfo(&metapb.Region{Id: 1, StartKey: []byte("a"), EndKey: []byte("b")}, nil)
+	region2 := NewRegionInfo(&metapb.Region{Id: 2, StartKey: []byte("b"), EndKey: []byte("c")}, nil)
+	regions.AddRegion(region1)
+	regions.SetRegion(region2)
+	regions.SetRegion(region2.Clone(SetApproximateSize(10)))
+	// Merge 1 into 2.
+	regions.SetRegion(region2.Clone(WithStartKey([]byte("a"))))
+	regions.RemoveRegion(regions.GetRegion(2))
+	c.Assert(events, DeepEquals, []string{"created 1", "created 2", "updated 2", "updated 2", "deleted 1", "deleted 2"})
+
+	regions.Unsubscribe(id)
+	regions.AddRegion(region1)
+	c.Assert(events, HasLen, 6)
+}
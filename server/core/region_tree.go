@@ -157,6 +157,24 @@ func (t *regionTree) scanRange(startKey []byte, f func(*metapb.Region) bool) {
 	})
 }
 
+// scanOverlapped calls f with the regions overlapping with [startKey, endKey)
+// in key order until f returns false. An empty endKey means the end of the key
+// space.
+func (t *regionTree) scanOverlapped(startKey, endKey []byte, f func(*metapb.Region) bool) {
+	// The region containing startKey may start before it.
+	if region := t.search(startKey); region != nil && !bytes.Equal(region.GetStartKey(), startKey) {
+		if !f(region) {
+			return
+		}
+	}
+	t.scanRange(startKey, func(region *metapb.Region) bool {
+		if len(endKey) > 0 && bytes.Compare(region.GetStartKey(), endKey) >= 0 {
+			return false
+		}
+		return f(region)
+	})
+}
+
 func (t *regionTree) getAdjacentRegions(region *metapb.Region) (*regionItem, *regionItem) {
 	item := &regionItem{region: &metapb.Region{StartKey: region.StartKey}}
 	var prev, next *regionItem
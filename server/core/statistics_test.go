@@ -14,7 +14,11 @@
 package core
 
 import (
+	"time"
+
 	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
 )
 
 var _ = Suite(&testRollingStats{})
@@ -30,3 +34,37 @@ func (t *testRollingStats) TestRollingMedian(c *C) {
 		c.Assert(stats.Median(), Equals, expected[i])
 	}
 }
+
+func (t *testRollingStats) TestUsedSizeGrowthRate(c *C) {
+	store := NewStoreInfo(&metapb.Store{Id: 1})
+	_, ok := store.TimeToFull()
+	c.Assert(ok, IsFalse)
+
+	// The used size grows 1MB per 10 seconds.
+	for i := uint64(1); i <= 5; i++ {
+		store.Stats = &pdpb.StoreStats{
+			Capacity:  100 << 20,
+			Available: (100 - i) << 20,
+			UsedSize:  i << 20,
+			Interval:  &pdpb.TimeInterval{StartTimestamp: (i - 1) * 10, EndTimestamp: i * 10},
+		}
+		store.RollingStoreStats.Observe(store.Stats)
+	}
+	c.Assert(store.UsedSizeGrowthRate(), Equals, float64(1<<20)/10)
+	timeToFull, ok := store.TimeToFull()
+	c.Assert(ok, IsTrue)
+	c.Assert(timeToFull, Equals, 950*time.Second)
+
+	// The used size is shrinking.
+	for i := uint64(1); i <= 10; i++ {
+		store.Stats = &pdpb.StoreStats{
+			Capacity:  100 << 20,
+			Available: (95 + i) << 20,
+			UsedSize:  (5 - i/2) << 20,
+			Interval:  &pdpb.TimeInterval{StartTimestamp: 40 + i*10, EndTimestamp: 50 + i*10},
+		}
+		store.RollingStoreStats.Observe(store.Stats)
+	}
+	_, ok = store.TimeToFull()
+	c.Assert(ok, IsFalse)
+}
@@ -32,7 +32,12 @@ type StoreInfo struct {
 	*metapb.Store
 	Stats *pdpb.StoreStats
 	// Blocked means that the store is blocked from balance.
-	blocked           bool
+	blocked bool
+	// Cordoned means that no new peers or leaders are scheduled to the store,
+	// until the cordon deadline if it is not zero.
+	cordoned          bool
+	cordonDeadline    time.Time
+	replacement       *StoreReplacement
 	LeaderCount       int
 	RegionCount       int
 	LeaderSize        int64
@@ -42,6 +47,8 @@ type StoreInfo struct {
 	LeaderWeight      float64
 	RegionWeight      float64
 	RollingStoreStats *RollingStoreStats
+	// DiskStats is the disk statistics last reported by the store service.
+	DiskStats StoreDiskStats
 }
 
 // NewStoreInfo creates StoreInfo with meta data.
@@ -61,6 +68,9 @@ func (s *StoreInfo) Clone() *StoreInfo {
 		Store:             proto.Clone(s.Store).(*metapb.Store),
 		Stats:             proto.Clone(s.Stats).(*pdpb.StoreStats),
 		blocked:           s.blocked,
+		cordoned:          s.cordoned,
+		cordonDeadline:    s.cordonDeadline,
+		replacement:       s.replacement,
 		LeaderCount:       s.LeaderCount,
 		RegionCount:       s.RegionCount,
 		LeaderSize:        s.LeaderSize,
@@ -70,6 +80,7 @@ func (s *StoreInfo) Clone() *StoreInfo {
 		LeaderWeight:      s.LeaderWeight,
 		RegionWeight:      s.RegionWeight,
 		RollingStoreStats: s.RollingStoreStats,
+		DiskStats:         s.DiskStats,
 	}
 }
 
@@ -88,6 +99,56 @@ func (s *StoreInfo) IsBlocked() bool {
 	return s.blocked
 }
 
+// Cordon stops scheduling new peers and leaders to the store until the
+// deadline. Zero deadline means the store is cordoned until Uncordon.
+func (s *StoreInfo) Cordon(deadline time.Time) {
+	s.cordoned = true
+	s.cordonDeadline = deadline
+}
+
+// Uncordon allows scheduling new peers and leaders to the store.
+func (s *StoreInfo) Uncordon() {
+	s.cordoned = false
+	s.cordonDeadline = time.Time{}
+}
+
+// IsCordoned returns if the store is cordoned and the cordon is not expired.
+func (s *StoreInfo) IsCordoned() bool {
+	return s.cordoned && !s.IsCordonExpired()
+}
+
+// IsCordonExpired returns if the store was cordoned but the deadline is passed.
+func (s *StoreInfo) IsCordonExpired() bool {
+	return s.cordoned && !s.cordonDeadline.IsZero() && !time.Now().Before(s.cordonDeadline)
+}
+
+// GetCordonDeadline returns the cordon deadline, zero if there is no deadline.
+func (s *StoreInfo) GetCordonDeadline() time.Time {
+	return s.cordonDeadline
+}
+
+// StoreReplacement pairs an offline store with the store which the peers of
+// the offline store are moved to first.
+type StoreReplacement struct {
+	StoreID uint64 `json:"store_id"`
+	// RegionCount and RegionSize are those of the offline store when it is
+	// paired, to estimate the progress.
+	RegionCount int       `json:"region_count"`
+	RegionSize  int64     `json:"region_size"`
+	StartTime   time.Time `json:"start_time"`
+}
+
+// SetReplacement pairs the store with the replacement store, nil unpairs it.
+func (s *StoreInfo) SetReplacement(replacement *StoreReplacement) {
+	s.replacement = replacement
+}
+
+// GetReplacement returns the replacement of the store, nil if it is not
+// paired.
+func (s *StoreInfo) GetReplacement() *StoreReplacement {
+	return s.replacement
+}
+
 // IsUp checks if the store's state is Up.
 func (s *StoreInfo) IsUp() bool {
 	return s.GetState() == metapb.StoreState_Up
@@ -175,6 +236,48 @@ func (s *StoreInfo) AvailableRatio() float64 {
 	return float64(s.Stats.GetAvailable()) / float64(s.Stats.GetCapacity())
 }
 
+// UsedSizeGrowthRate returns the growth rate of the store's used size in
+// bytes per second, which is estimated by successive store heartbeats.
+func (s *StoreInfo) UsedSizeGrowthRate() float64 {
+	if s.RollingStoreStats == nil {
+		return 0
+	}
+	return s.RollingStoreStats.GetUsedSizeGrowthRate()
+}
+
+// DiskReadRate returns the bytes read from the disk per second in the last
+// reported disk stats.
+func (s *StoreInfo) DiskReadRate() float64 {
+	return s.diskRate(s.DiskStats.DiskReadBytes)
+}
+
+// DiskWriteRate returns the bytes written to the disk per second in the last
+// reported disk stats.
+func (s *StoreInfo) DiskWriteRate() float64 {
+	return s.diskRate(s.DiskStats.DiskWriteBytes)
+}
+
+func (s *StoreInfo) diskRate(bytes uint64) float64 {
+	if s.DiskStats.Interval == 0 {
+		return 0
+	}
+	return float64(bytes) / float64(s.DiskStats.Interval)
+}
+
+// TimeToFull predicts how long it takes to use up the available space with the
+// current growth rate. It returns false if the used size is not growing.
+func (s *StoreInfo) TimeToFull() (time.Duration, bool) {
+	rate := s.UsedSizeGrowthRate()
+	if rate <= 0 {
+		return 0, false
+	}
+	seconds := float64(s.Stats.GetAvailable()) / rate
+	if seconds > float64(math.MaxInt64/int64(time.Second)) {
+		return 0, false
+	}
+	return time.Duration(seconds * float64(time.Second)), true
+}
+
 // IsLowSpace checks if the store is lack of space.
 func (s *StoreInfo) IsLowSpace(lowSpaceRatio float64) bool {
 	return s.Stats != nil && s.AvailableRatio() < 1-lowSpaceRatio
@@ -334,6 +437,8 @@ type StoresInfo struct {
 	stores         map[uint64]*StoreInfo
 	bytesReadRate  float64
 	bytesWriteRate float64
+	// version is increased whenever a store changes.
+	version uint64
 }
 
 // NewStoresInfo create a StoresInfo with map of storeID to StoreInfo
@@ -363,6 +468,7 @@ func (s *StoresInfo) TakeStore(storeID uint64) *StoreInfo {
 
 // SetStore sets a StoreInfo with storeID.
 func (s *StoresInfo) SetStore(store *StoreInfo) {
+	s.version++
 	s.stores[store.GetId()] = store
 	store.RollingStoreStats.Observe(store.Stats)
 	s.updateTotalBytesReadRate()
@@ -379,6 +485,7 @@ func (s *StoresInfo) BlockStore(storeID uint64) errcode.ErrorCode {
 	if store.IsBlocked() {
 		return op.AddTo(StoreBlockedErr{StoreID: storeID})
 	}
+	s.version++
 	store.Block()
 	return nil
 }
@@ -389,6 +496,7 @@ func (s *StoresInfo) UnblockStore(storeID uint64) {
 	if !ok {
 		log.Fatalf("store %d is unblocked, but it is not found", storeID)
 	}
+	s.version++
 	store.Unblock()
 }
 
@@ -410,6 +518,12 @@ func (s *StoresInfo) GetMetaStores() []*metapb.Store {
 	return stores
 }
 
+// Version returns the version of the stores, which changes whenever a store
+// changes.
+func (s *StoresInfo) Version() uint64 {
+	return s.version
+}
+
 // GetStoreCount return the total count of storeInfo
 func (s *StoresInfo) GetStoreCount() int {
 	return len(s.stores)
@@ -418,6 +532,7 @@ func (s *StoresInfo) GetStoreCount() int {
 // SetLeaderCount set the leader count to a storeInfo
 func (s *StoresInfo) SetLeaderCount(storeID uint64, leaderCount int) {
 	if store, ok := s.stores[storeID]; ok {
+		s.version++
 		store.LeaderCount = leaderCount
 	}
 }
@@ -425,6 +540,7 @@ func (s *StoresInfo) SetLeaderCount(storeID uint64, leaderCount int) {
 // SetRegionCount set the region count to a storeInfo
 func (s *StoresInfo) SetRegionCount(storeID uint64, regionCount int) {
 	if store, ok := s.stores[storeID]; ok {
+		s.version++
 		store.RegionCount = regionCount
 	}
 }
@@ -432,6 +548,7 @@ func (s *StoresInfo) SetRegionCount(storeID uint64, regionCount int) {
 // SetPendingPeerCount sets the pending count to a storeInfo
 func (s *StoresInfo) SetPendingPeerCount(storeID uint64, pendingPeerCount int) {
 	if store, ok := s.stores[storeID]; ok {
+		s.version++
 		store.PendingPeerCount = pendingPeerCount
 	}
 }
@@ -439,6 +556,7 @@ func (s *StoresInfo) SetPendingPeerCount(storeID uint64, pendingPeerCount int) {
 // SetLeaderSize set the leader count to a storeInfo
 func (s *StoresInfo) SetLeaderSize(storeID uint64, leaderSize int64) {
 	if store, ok := s.stores[storeID]; ok {
+		s.version++
 		store.LeaderSize = leaderSize
 	}
 }
@@ -446,6 +564,7 @@ func (s *StoresInfo) SetLeaderSize(storeID uint64, leaderSize int64) {
 // SetRegionSize set the region count to a storeInfo
 func (s *StoresInfo) SetRegionSize(storeID uint64, regionSize int64) {
 	if store, ok := s.stores[storeID]; ok {
+		s.version++
 		store.RegionSize = regionSize
 	}
 }
@@ -519,20 +638,31 @@ func (s *StoresInfo) GetStoresKeysReadStat() map[uint64]uint64 {
 // RollingStoreStats are multiple sets of recent historical records with specified windows size.
 type RollingStoreStats struct {
 	sync.RWMutex
-	bytesWriteRate *RollingStats
-	bytesReadRate  *RollingStats
-	keysWriteRate  *RollingStats
-	keysReadRate   *RollingStats
-}
-
-const storeStatsRollingWindows = 3
+	bytesWriteRate     *RollingStats
+	bytesReadRate      *RollingStats
+	keysWriteRate      *RollingStats
+	keysReadRate       *RollingStats
+	usedSizeGrowthRate *RollingStats
+	// lastUsedSize and lastTimestamp are from the last heartbeat, which are
+	// used to calculate the growth rate of used size.
+	lastUsedSize  uint64
+	lastTimestamp uint64
+}
+
+const (
+	storeStatsRollingWindows = 3
+	// The used size changes slowly and is affected by compaction, so use a
+	// larger window to smooth it.
+	storeGrowthRollingWindows = 10
+)
 
 func newRollingStoreStats() *RollingStoreStats {
 	return &RollingStoreStats{
-		bytesWriteRate: NewRollingStats(storeStatsRollingWindows),
-		bytesReadRate:  NewRollingStats(storeStatsRollingWindows),
-		keysWriteRate:  NewRollingStats(storeStatsRollingWindows),
-		keysReadRate:   NewRollingStats(storeStatsRollingWindows),
+		bytesWriteRate:     NewRollingStats(storeStatsRollingWindows),
+		bytesReadRate:      NewRollingStats(storeStatsRollingWindows),
+		keysWriteRate:      NewRollingStats(storeStatsRollingWindows),
+		keysReadRate:       NewRollingStats(storeStatsRollingWindows),
+		usedSizeGrowthRate: NewRollingStats(storeGrowthRollingWindows),
 	}
 }
 
@@ -548,6 +678,13 @@ func (r *RollingStoreStats) Observe(stats *pdpb.StoreStats) {
 	r.bytesReadRate.Add(float64(stats.BytesRead / interval))
 	r.keysWriteRate.Add(float64(stats.KeysWritten / interval))
 	r.keysReadRate.Add(float64(stats.KeysRead / interval))
+
+	timestamp := stats.GetInterval().GetEndTimestamp()
+	if r.lastTimestamp != 0 && timestamp > r.lastTimestamp {
+		delta := float64(stats.GetUsedSize()) - float64(r.lastUsedSize)
+		r.usedSizeGrowthRate.Add(delta / float64(timestamp-r.lastTimestamp))
+	}
+	r.lastUsedSize, r.lastTimestamp = stats.GetUsedSize(), timestamp
 }
 
 // GetBytesWriteRate returns the bytes write rate.
@@ -577,3 +714,23 @@ func (r *RollingStoreStats) GetKeysReadRate() float64 {
 	defer r.RUnlock()
 	return r.keysReadRate.Median()
 }
+
+// GetUsedSizeGrowthRate returns the growth rate of used size.
+func (r *RollingStoreStats) GetUsedSizeGrowthRate() float64 {
+	r.RLock()
+	defer r.RUnlock()
+	return r.usedSizeGrowthRate.Median()
+}
+
+// StoreDiskStats is the disk statistics reported by the store service.
+type StoreDiskStats struct {
+	// Interval is the seconds during which the disk bytes are counted.
+	Interval uint64
+	// DiskReadBytes and DiskWriteBytes are the bytes read from and written to
+	// the disk during the reported interval.
+	DiskReadBytes  uint64
+	DiskWriteBytes uint64
+	// PendingCompactionBytes is the estimated bytes to be compacted by the
+	// storage engine.
+	PendingCompactionBytes uint64
+}
@@ -0,0 +1,51 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+)
+
+var _ = Suite(&testStoreSuite{})
+
+type testStoreSuite struct{}
+
+func (s *testStoreSuite) TestStoreDiskStats(c *C) {
+	store := NewStoreInfo(nil)
+	c.Assert(store.DiskReadRate(), Equals, float64(0))
+	c.Assert(store.DiskWriteRate(), Equals, float64(0))
+
+	store.DiskStats = StoreDiskStats{
+		Interval:               10,
+		DiskReadBytes:          1000,
+		DiskWriteBytes:         2000,
+		PendingCompactionBytes: 1 << 40,
+	}
+	c.Assert(store.DiskReadRate(), Equals, float64(100))
+	c.Assert(store.DiskWriteRate(), Equals, float64(200))
+	c.Assert(store.Clone().DiskStats, DeepEquals, store.DiskStats)
+}
+
+func (s *testStoreSuite) TestVersion(c *C) {
+	stores := NewStoresInfo()
+	version := stores.Version()
+	stores.SetStore(NewStoreInfo(&metapb.Store{Id: 1}))
+	c.Assert(stores.Version(), Not(Equals), version)
+	version = stores.Version()
+	c.Assert(stores.GetStore(1), NotNil)
+	c.Assert(stores.Version(), Equals, version)
+	stores.SetLeaderCount(1, 10)
+	c.Assert(stores.Version(), Not(Equals), version)
+}
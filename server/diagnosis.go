@@ -0,0 +1,318 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"sort"
+
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/schedule"
+)
+
+// LimitDiagnosis is the count of running operators of a kind and the limit.
+type LimitDiagnosis struct {
+	Kind    string `json:"kind"`
+	Running uint64 `json:"running"`
+	Limit   uint64 `json:"limit"`
+}
+
+// CheckerDiagnosis is the result of running a checker on a region without
+// adding the operators.
+type CheckerDiagnosis struct {
+	Checker string `json:"checker"`
+	// Allowed is false if the checker is not run by the coordinator now, for
+	// example, because of the operator limits.
+	Allowed   bool     `json:"allowed"`
+	Operators []string `json:"operators,omitempty"`
+}
+
+// SchedulerDiagnosis is whether a running scheduler is allowed to schedule.
+type SchedulerDiagnosis struct {
+	Name    string `json:"name"`
+	Allowed bool   `json:"allowed"`
+}
+
+// StoreFilterDiagnosis explains whether a store can be selected as the source
+// or the target of scheduling, with the filters which reject it.
+type StoreFilterDiagnosis struct {
+	StoreID          uint64   `json:"store_id"`
+	LeaderScore      float64  `json:"leader_score"`
+	RegionScore      float64  `json:"region_score"`
+	SourceRejectedBy []string `json:"source_rejected_by,omitempty"`
+	TargetRejectedBy []string `json:"target_rejected_by,omitempty"`
+}
+
+// RegionDiagnosis explains why a region is or is not scheduled.
+type RegionDiagnosis struct {
+	RegionID   uint64                 `json:"region_id"`
+	Namespace  string                 `json:"namespace"`
+	Operator   string                 `json:"operator,omitempty"`
+	Checkers   []CheckerDiagnosis     `json:"checkers"`
+	Limits     []LimitDiagnosis       `json:"limits"`
+	Schedulers []SchedulerDiagnosis   `json:"schedulers"`
+	Stores     []StoreFilterDiagnosis `json:"stores"`
+}
+
+// StoreDiagnosis explains why a store is or is not scheduled.
+type StoreDiagnosis struct {
+	StoreFilterDiagnosis
+	Namespace   string               `json:"namespace"`
+	State       string               `json:"state"`
+	LeaderCount int                  `json:"leader_count"`
+	RegionCount int                  `json:"region_count"`
+	Limits      []LimitDiagnosis     `json:"limits"`
+	Schedulers  []SchedulerDiagnosis `json:"schedulers"`
+}
+
+// storeFilters returns the filters to check a store in the namespace.
+// Filters depending on the source of a schedule are not included.
+func (c *coordinator) storeFilters(ns string) []schedule.Filter {
+	return []schedule.Filter{
+		schedule.NewStateFilter(),
+		schedule.NewDisconnectFilter(),
+		schedule.NewBlockFilter(),
+		schedule.NewHealthFilter(),
+		schedule.NewPendingPeerCountFilter(),
+		schedule.NewSnapshotCountFilter(),
+		schedule.NewStorageThresholdFilter(),
+		schedule.NewPendingCompactionFilter(),
+		schedule.NewRejectLeaderFilter(),
+		schedule.NewNamespaceFilter(c.classifier, ns),
+	}
+}
+
+// diagnoseStoreFilters checks the store with filters. The filters of the source
+// are only checked if the store can be the source.
+func (c *coordinator) diagnoseStoreFilters(store *core.StoreInfo, filters []schedule.Filter, source bool) StoreFilterDiagnosis {
+	opt := c.cluster
+	d := StoreFilterDiagnosis{
+		StoreID:          store.GetId(),
+		LeaderScore:      store.LeaderScore(0),
+		RegionScore:      store.RegionScore(opt.GetHighSpaceRatio(), opt.GetLowSpaceRatio(), 0),
+		TargetRejectedBy: schedule.TargetRejectedBy(opt, store, filters),
+	}
+	if source {
+		d.SourceRejectedBy = schedule.SourceRejectedBy(opt, store, filters)
+	}
+	return d
+}
+
+func (c *coordinator) diagnoseLimits(ns string) []LimitDiagnosis {
+	opt := c.cluster.opt
+	return []LimitDiagnosis{
+		{Kind: "leader", Running: c.opController.OperatorCount(schedule.OpLeader), Limit: opt.GetLeaderScheduleLimit(ns)},
+		{Kind: "region", Running: c.opController.OperatorCount(schedule.OpRegion), Limit: opt.GetRegionScheduleLimit(ns)},
+		{Kind: "replica", Running: c.opController.OperatorCount(schedule.OpReplica), Limit: opt.GetReplicaScheduleLimit(ns)},
+		{Kind: "merge", Running: c.opController.OperatorCount(schedule.OpMerge), Limit: opt.GetMergeScheduleLimit(ns)},
+	}
+}
+
+func (c *coordinator) diagnoseSchedulers() []SchedulerDiagnosis {
+	c.RLock()
+	defer c.RUnlock()
+	schedulers := make([]SchedulerDiagnosis, 0, len(c.schedulers))
+	for name, s := range c.schedulers {
+		schedulers = append(schedulers, SchedulerDiagnosis{Name: name, Allowed: s.AllowSchedule()})
+	}
+	sort.Slice(schedulers, func(i, j int) bool { return schedulers[i].Name < schedulers[j].Name })
+	return schedulers
+}
+
+func newCheckerDiagnosis(checker string, allowed bool, ops ...*schedule.Operator) CheckerDiagnosis {
+	d := CheckerDiagnosis{Checker: checker, Allowed: allowed}
+	for _, op := range ops {
+		if op != nil {
+			d.Operators = append(d.Operators, op.String())
+		}
+	}
+	return d
+}
+
+// diagnoseRegion runs the checkers on the region in dry-run mode, and checks
+// the stores with filters to explain where the region can be moved to.
+func (c *coordinator) diagnoseRegion(region *core.RegionInfo) *RegionDiagnosis {
+	ns := c.classifier.GetRegionNamespace(region)
+	d := &RegionDiagnosis{
+		RegionID:   region.GetID(),
+		Namespace:  ns,
+		Limits:     c.diagnoseLimits(ns),
+		Schedulers: c.diagnoseSchedulers(),
+	}
+	if op := c.opController.GetOperator(region.GetID()); op != nil {
+		d.Operator = op.String()
+	}
+
+	// The same conditions as checkRegion.
+	oc := c.opController
+	d.Checkers = []CheckerDiagnosis{
+		newCheckerDiagnosis("namespace-checker",
+			oc.OperatorCount(schedule.OpLeader) < c.cluster.GetLeaderScheduleLimit() &&
+				oc.OperatorCount(schedule.OpRegion) < c.cluster.GetRegionScheduleLimit() &&
+				oc.OperatorCount(schedule.OpReplica) < c.cluster.GetReplicaScheduleLimit(),
+			c.namespaceChecker.Check(region)),
+		newCheckerDiagnosis("replica-checker",
+			oc.OperatorCount(schedule.OpReplica) < c.cluster.GetReplicaScheduleLimit(),
+			c.replicaChecker.Check(region)),
+		newCheckerDiagnosis("merge-checker",
+			c.cluster.IsFeatureSupported(RegionMerge) && oc.OperatorCount(schedule.OpMerge) < c.cluster.GetMergeScheduleLimit(),
+			c.mergeChecker.Check(region)...),
+	}
+
+	filters := append(c.storeFilters(ns), schedule.NewExcludedFilter(nil, region.GetStoreIds()))
+	for _, store := range c.cluster.GetStores() {
+		if store.IsTombstone() {
+			continue
+		}
+		// Only the stores with peers of the region can be the source.
+		source := region.GetStorePeer(store.GetId()) != nil
+		d.Stores = append(d.Stores, c.diagnoseStoreFilters(store, filters, source))
+	}
+	sort.Slice(d.Stores, func(i, j int) bool { return d.Stores[i].StoreID < d.Stores[j].StoreID })
+	return d
+}
+
+// diagnoseStore checks the store with filters to explain whether it can be
+// selected by schedulers.
+func (c *coordinator) diagnoseStore(store *core.StoreInfo) *StoreDiagnosis {
+	ns := c.classifier.GetStoreNamespace(store)
+	return &StoreDiagnosis{
+		StoreFilterDiagnosis: c.diagnoseStoreFilters(store, c.storeFilters(ns), true),
+		Namespace:            ns,
+		State:                store.GetState().String(),
+		LeaderCount:          store.LeaderCount,
+		RegionCount:          store.RegionCount,
+		Limits:               c.diagnoseLimits(ns),
+		Schedulers:           c.diagnoseSchedulers(),
+	}
+}
+
+// OperatorStoreImpact is the estimated impact of an operator on a store.
+type OperatorStoreImpact struct {
+	StoreID     uint64 `json:"store_id"`
+	RegionSize  int64  `json:"region_size"`
+	RegionCount int64  `json:"region_count"`
+	LeaderSize  int64  `json:"leader_size"`
+	LeaderCount int64  `json:"leader_count"`
+	// ReceiveSnapshot is true if the store receives a snapshot of the region.
+	ReceiveSnapshot    bool   `json:"receive_snapshot"`
+	ReceivingSnapCount uint32 `json:"receiving_snap_count"`
+	PendingPeerCount   int    `json:"pending_peer_count"`
+	// RejectedBy is the filters which reject the store as the target.
+	RejectedBy []string `json:"rejected_by,omitempty"`
+}
+
+// OperatorDryRun is the result of creating an operator without adding it.
+type OperatorDryRun struct {
+	Operator string   `json:"operator"`
+	Kind     string   `json:"kind"`
+	Steps    []string `json:"steps"`
+	// Addable is false if the operator controller rejects the operator now, for
+	// example, because the region has an operator with higher priority.
+	Addable bool `json:"addable"`
+	// SnapshotSize is the approximate size in MB of the snapshots to send.
+	SnapshotSize        int64                 `json:"snapshot_size"`
+	MaxSnapshotCount    uint64                `json:"max_snapshot_count"`
+	MaxPendingPeerCount uint64                `json:"max_pending_peer_count"`
+	Limits              []LimitDiagnosis      `json:"limits"`
+	Stores              []OperatorStoreImpact `json:"stores"`
+}
+
+// dryRunOperator validates the operator with the operator controller and the
+// filters of the target stores, and estimates its impact on the stores.
+func (c *coordinator) dryRunOperator(op *schedule.Operator) *OperatorDryRun {
+	d := &OperatorDryRun{
+		Operator:            op.String(),
+		Kind:                op.Kind().String(),
+		Addable:             c.opController.CheckAddOperator(op),
+		MaxSnapshotCount:    c.cluster.GetMaxSnapshotCount(),
+		MaxPendingPeerCount: c.cluster.GetMaxPendingPeerCount(),
+	}
+	region := c.cluster.GetRegion(op.RegionID())
+	if region == nil {
+		return d
+	}
+	ns := c.classifier.GetRegionNamespace(region)
+	for _, l := range c.diagnoseLimits(ns) {
+		if op.Kind()&limitKinds[l.Kind] != 0 {
+			d.Limits = append(d.Limits, l)
+		}
+	}
+
+	leaderFilters := []schedule.Filter{
+		schedule.StoreStateFilter{TransferLeader: true},
+		schedule.NewNamespaceFilter(c.classifier, ns),
+	}
+	peerFilters := []schedule.Filter{
+		schedule.StoreStateFilter{MoveRegion: true},
+		schedule.NewStorageThresholdFilter(),
+		schedule.NewPendingCompactionFilter(),
+		schedule.NewNamespaceFilter(c.classifier, ns),
+	}
+	rejected := make(map[uint64][]string)
+	snapshots := make(map[uint64]bool)
+	for i := 0; i < op.Len(); i++ {
+		step := op.Step(i)
+		d.Steps = append(d.Steps, step.String())
+
+		var storeID uint64
+		var filters []schedule.Filter
+		switch s := step.(type) {
+		case schedule.TransferLeader:
+			storeID, filters = s.ToStore, leaderFilters
+		case schedule.AddPeer:
+			storeID, filters = s.ToStore, peerFilters
+			snapshots[storeID] = true
+		case schedule.AddLearner:
+			storeID, filters = s.ToStore, peerFilters
+			snapshots[storeID] = true
+		default:
+			continue
+		}
+		if store := c.cluster.GetStore(storeID); store != nil {
+			rejected[storeID] = append(rejected[storeID], schedule.TargetRejectedBy(c.cluster, store, filters)...)
+		}
+	}
+
+	influence := schedule.NewOpInfluence([]*schedule.Operator{op}, c.cluster)
+	for _, store := range c.cluster.GetStores() {
+		id := store.GetId()
+		si := influence.GetStoreInfluence(id)
+		if *si == (schedule.StoreInfluence{}) && !snapshots[id] && len(rejected[id]) == 0 {
+			continue
+		}
+		d.Stores = append(d.Stores, OperatorStoreImpact{
+			StoreID:            id,
+			RegionSize:         si.RegionSize,
+			RegionCount:        si.RegionCount,
+			LeaderSize:         si.LeaderSize,
+			LeaderCount:        si.LeaderCount,
+			ReceiveSnapshot:    snapshots[id],
+			ReceivingSnapCount: store.Stats.GetReceivingSnapCount(),
+			PendingPeerCount:   store.PendingPeerCount,
+			RejectedBy:         rejected[id],
+		})
+		if snapshots[id] {
+			d.SnapshotSize += region.GetApproximateSize()
+		}
+	}
+	sort.Slice(d.Stores, func(i, j int) bool { return d.Stores[i].StoreID < d.Stores[j].StoreID })
+	return d
+}
+
+var limitKinds = map[string]schedule.OperatorKind{
+	"leader":  schedule.OpLeader,
+	"region":  schedule.OpRegion,
+	"replica": schedule.OpReplica,
+	"merge":   schedule.OpMerge,
+}
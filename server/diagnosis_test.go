@@ -0,0 +1,79 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/pd/server/namespace"
+)
+
+var _ = Suite(&testDiagnosisSuite{})
+
+type testDiagnosisSuite struct{}
+
+func (s *testDiagnosisSuite) TestDiagnose(c *C) {
+	cfg, opt := newTestScheduleConfig()
+	tc := newTestClusterInfo(opt)
+	hbStreams := newHeartbeatStreams(tc.getClusterID())
+	defer hbStreams.Close()
+	co := newCoordinator(tc.clusterInfo, hbStreams, namespace.DefaultClassifier)
+
+	tc.addRegionStore(1, 1)
+	tc.addRegionStore(2, 1)
+	tc.addRegionStore(3, 0)
+	tc.addRegionStore(4, 0)
+	tc.setStoreOffline(4)
+	// The region misses a replica.
+	tc.addLeaderRegion(1, 1, 2)
+
+	d := co.diagnoseRegion(tc.GetRegion(1))
+	c.Assert(d.RegionID, Equals, uint64(1))
+	c.Assert(d.Namespace, Equals, namespace.DefaultNamespace)
+	c.Assert(d.Operator, Equals, "")
+	c.Assert(d.Checkers, HasLen, 3)
+	replicaChecker := d.Checkers[1]
+	c.Assert(replicaChecker.Checker, Equals, "replica-checker")
+	c.Assert(replicaChecker.Allowed, IsTrue)
+	c.Assert(replicaChecker.Operators, HasLen, 1)
+	// It is dry-run.
+	c.Assert(co.opController.GetOperator(1), IsNil)
+
+	c.Assert(d.Stores, HasLen, 4)
+	c.Assert(d.Stores[0].SourceRejectedBy, HasLen, 0)
+	c.Assert(d.Stores[0].TargetRejectedBy, DeepEquals, []string{"exclude-filter"})
+	c.Assert(d.Stores[2].TargetRejectedBy, HasLen, 0)
+	c.Assert(d.Stores[3].SourceRejectedBy, HasLen, 0)
+	c.Assert(d.Stores[3].TargetRejectedBy, DeepEquals, []string{"state-filter"})
+
+	// The replica checker is not allowed to run.
+	cfg.ReplicaScheduleLimit = 0
+	// The store with a peer is checked as the source, the others are not.
+	tc.setStoreDown(2)
+	tc.setStoreDown(3)
+	d = co.diagnoseRegion(tc.GetRegion(1))
+	c.Assert(d.Checkers[1].Allowed, IsFalse)
+	c.Assert(d.Stores[1].SourceRejectedBy, DeepEquals, []string{"disconnect-filter", "health-filter"})
+	c.Assert(d.Stores[2].SourceRejectedBy, HasLen, 0)
+	for _, l := range d.Limits {
+		if l.Kind == "replica" {
+			c.Assert(l.Limit, Equals, uint64(0))
+		}
+	}
+
+	sd := co.diagnoseStore(tc.GetStore(4))
+	c.Assert(sd.StoreID, Equals, uint64(4))
+	c.Assert(sd.State, Equals, "Offline")
+	c.Assert(sd.TargetRejectedBy, DeepEquals, []string{"state-filter"})
+	c.Assert(sd.Limits, HasLen, 4)
+}
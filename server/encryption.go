@@ -0,0 +1,334 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/pd/pkg/encryptionpb"
+	"github.com/pingcap/pd/pkg/logutil"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	dataKeyLength = 32
+	// keyRotationCheckInterval is the interval to check if the current data
+	// key should be rotated.
+	keyRotationCheckInterval = time.Minute
+	// keyLoadRetryInterval is the interval to retry loading the data keys,
+	// when the master key fails to decrypt them.
+	keyLoadRetryInterval = time.Second
+)
+
+var (
+	// ErrEncryptionDisabled is returned when the data keys are requested but
+	// the master key is not configured.
+	ErrEncryptionDisabled = errors.New("encryption is disabled, set encryption.master-key to enable it")
+	// ErrDataKeyNotFound is returned when the data key does not exist.
+	ErrDataKeyNotFound = errors.New("data key not found")
+	// ErrDataKeysNotLoaded is returned when the leader has not loaded the
+	// data keys yet.
+	ErrDataKeysNotLoaded = errors.New("data keys are not loaded yet")
+)
+
+// encryptedDataKey is a data key persisted in etcd, which is encrypted by the
+// master key.
+type encryptedDataKey struct {
+	ID           uint64                        `json:"id"`
+	Method       encryptionpb.EncryptionMethod `json:"method"`
+	CreationTime int64                         `json:"creation_time"`
+	Ciphertext   []byte                        `json:"ciphertext"`
+}
+
+// keyManager manages the data keys for TiKV to encrypt the data at rest. The
+// keys are loaded and rotated by the leader.
+type keyManager struct {
+	sync.RWMutex
+	kv      *core.KV
+	idAlloc core.IDAllocator
+	cfg     EncryptionConfig
+	// master is nil if the data keys are not served.
+	master  masterKey
+	keys    map[uint64]*encryptionpb.DataKey
+	current *encryptionpb.DataKey
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func newKeyManager(kv *core.KV, idAlloc core.IDAllocator, cfg EncryptionConfig) (*keyManager, error) {
+	master, err := newMasterKey(cfg.MasterKey)
+	if err != nil {
+		return nil, err
+	}
+	return &keyManager{
+		kv:      kv,
+		idAlloc: idAlloc,
+		cfg:     cfg,
+		master:  master,
+	}, nil
+}
+
+// start loads the data keys and starts rotating them in the background. It is
+// called after the server becomes leader. The keys are loaded with retry, the
+// data keys are unavailable until they are loaded, which does not affect the
+// leadership.
+func (m *keyManager) start() {
+	if m.master == nil {
+		return
+	}
+	m.Lock()
+	defer m.Unlock()
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.wg.Add(1)
+	go m.run(ctx)
+}
+
+// stop stops rotating the data keys. It is called after the server loses
+// leadership.
+func (m *keyManager) stop() {
+	if m.master == nil {
+		return
+	}
+	m.Lock()
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+	m.Unlock()
+	m.wg.Wait()
+	m.Lock()
+	m.keys, m.current = nil, nil
+	m.Unlock()
+}
+
+func (m *keyManager) run(ctx context.Context) {
+	defer logutil.LogPanic()
+	defer m.wg.Done()
+
+	for {
+		err := m.load()
+		if err == nil {
+			break
+		}
+		log.Errorf("failed to load data keys: %v", err)
+		select {
+		case <-time.After(keyLoadRetryInterval):
+		case <-ctx.Done():
+			return
+		}
+	}
+
+	ticker := time.NewTicker(keyRotationCheckInterval)
+	defer ticker.Stop()
+	for {
+		if err := m.rotateIfExpired(time.Now()); err != nil {
+			log.Errorf("failed to rotate data key: %v", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// load loads and decrypts the data keys. The lock is not held while
+// decrypting, which may request the KMS.
+func (m *keyManager) load() error {
+	keys := make(map[uint64]*encryptionpb.DataKey)
+	var current *encryptionpb.DataKey
+	err := m.kv.LoadEncryptionKeys(func(data []byte) (uint64, error) {
+		encrypted := &encryptedDataKey{}
+		if err := json.Unmarshal(data, encrypted); err != nil {
+			return 0, errors.WithStack(err)
+		}
+		key, err := m.master.decrypt(encrypted.Ciphertext)
+		if err != nil {
+			return 0, errors.WithMessage(err, "failed to decrypt data key, the master key may be wrong")
+		}
+		current = &encryptionpb.DataKey{
+			KeyId:        encrypted.ID,
+			Key:          key,
+			Method:       encrypted.Method,
+			CreationTime: encrypted.CreationTime,
+		}
+		keys[encrypted.ID] = current
+		return encrypted.ID, nil
+	})
+	if err != nil {
+		return err
+	}
+	m.Lock()
+	defer m.Unlock()
+	m.keys, m.current = keys, current
+	return nil
+}
+
+// rotateIfExpired creates a new data key if there is no key or the current
+// key is older than the rotation period.
+func (m *keyManager) rotateIfExpired(now time.Time) error {
+	m.RLock()
+	current := m.current
+	m.RUnlock()
+	if current != nil && now.Sub(time.Unix(current.GetCreationTime(), 0)) < m.cfg.DataKeyRotationPeriod.Duration {
+		return nil
+	}
+	return m.rotate(now)
+}
+
+func (m *keyManager) rotate(now time.Time) error {
+	key := make([]byte, dataKeyLength)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return errors.WithStack(err)
+	}
+	ciphertext, err := m.master.encrypt(key)
+	if err != nil {
+		return err
+	}
+	id, err := m.idAlloc.Alloc()
+	if err != nil {
+		return err
+	}
+	dataKey := &encryptionpb.DataKey{
+		KeyId:        id,
+		Key:          key,
+		Method:       encryptionpb.EncryptionMethod_AES256_CTR,
+		CreationTime: now.Unix(),
+	}
+	encrypted := &encryptedDataKey{
+		ID:           id,
+		Method:       dataKey.Method,
+		CreationTime: dataKey.CreationTime,
+		Ciphertext:   ciphertext,
+	}
+	if err = m.kv.SaveEncryptionKey(id, encrypted); err != nil {
+		return err
+	}
+	m.Lock()
+	m.keys[id], m.current = dataKey, dataKey
+	m.Unlock()
+	log.Infof("data key is rotated to %d", id)
+	return nil
+}
+
+// getCurrent returns the data key to encrypt the new data.
+func (m *keyManager) getCurrent() (*encryptionpb.DataKey, error) {
+	if m.master == nil {
+		return nil, ErrEncryptionDisabled
+	}
+	m.RLock()
+	defer m.RUnlock()
+	if m.cancel == nil {
+		return nil, errors.WithStack(ErrNotLeader)
+	}
+	if m.current == nil {
+		return nil, errors.WithStack(ErrDataKeysNotLoaded)
+	}
+	return m.current, nil
+}
+
+// get returns the data key by ID.
+func (m *keyManager) get(id uint64) (*encryptionpb.DataKey, error) {
+	if m.master == nil {
+		return nil, ErrEncryptionDisabled
+	}
+	m.RLock()
+	defer m.RUnlock()
+	if m.cancel == nil {
+		return nil, errors.WithStack(ErrNotLeader)
+	}
+	if m.keys == nil {
+		return nil, errors.WithStack(ErrDataKeysNotLoaded)
+	}
+	key, ok := m.keys[id]
+	if !ok {
+		return nil, errors.WithStack(ErrDataKeyNotFound)
+	}
+	return key, nil
+}
+
+// keyManagerService implements gRPC KeyManagerServer.
+type keyManagerService struct {
+	s *Server
+}
+
+// authenticate checks the bearer token in the request metadata.
+func (k *keyManagerService) authenticate(ctx context.Context) error {
+	token := k.s.cfg.Encryption.ClientToken
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, auth := range md.Get("authorization") {
+		if strings.HasPrefix(auth, "Bearer ") &&
+			subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(auth, "Bearer ")), []byte(token)) == 1 {
+			return nil
+		}
+	}
+	return status.Errorf(codes.Unauthenticated, "invalid encryption client token")
+}
+
+func dataKeyError(err error) error {
+	switch errors.Cause(err) {
+	case ErrEncryptionDisabled:
+		return status.Errorf(codes.FailedPrecondition, err.Error())
+	case ErrDataKeyNotFound:
+		return status.Errorf(codes.NotFound, err.Error())
+	case ErrDataKeysNotLoaded:
+		return status.Errorf(codes.Unavailable, err.Error())
+	case ErrNotLeader:
+		return notLeaderError
+	}
+	return status.Errorf(codes.Unknown, err.Error())
+}
+
+// GetCurrentKey implements gRPC KeyManagerServer.
+func (k *keyManagerService) GetCurrentKey(ctx context.Context, request *encryptionpb.GetCurrentKeyRequest) (*encryptionpb.GetKeyResponse, error) {
+	if err := k.authenticate(ctx); err != nil {
+		return nil, err
+	}
+	if err := k.s.validateRequest(request.GetHeader()); err != nil {
+		return nil, err
+	}
+	key, err := k.s.keys.getCurrent()
+	if err != nil {
+		return nil, dataKeyError(err)
+	}
+	return &encryptionpb.GetKeyResponse{Header: k.s.header(), Key: key}, nil
+}
+
+// GetKey implements gRPC KeyManagerServer.
+func (k *keyManagerService) GetKey(ctx context.Context, request *encryptionpb.GetKeyRequest) (*encryptionpb.GetKeyResponse, error) {
+	if err := k.authenticate(ctx); err != nil {
+		return nil, err
+	}
+	if err := k.s.validateRequest(request.GetHeader()); err != nil {
+		return nil, err
+	}
+	key, err := k.s.keys.get(request.GetKeyId())
+	if err != nil {
+		return nil, dataKeyError(err)
+	}
+	return &encryptionpb.GetKeyResponse{Header: k.s.header(), Key: key}, nil
+}
@@ -0,0 +1,236 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/pkg/encryptionpb"
+	"github.com/pingcap/pd/pkg/typeutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var _ = Suite(&testEncryptionSuite{})
+
+type testEncryptionSuite struct {
+	dir string
+}
+
+func (s *testEncryptionSuite) SetUpTest(c *C) {
+	var err error
+	s.dir, err = ioutil.TempDir("", "pd_encryption_test")
+	c.Assert(err, IsNil)
+}
+
+func (s *testEncryptionSuite) TearDownTest(c *C) {
+	os.RemoveAll(s.dir)
+}
+
+func (s *testEncryptionSuite) writeMasterKey(c *C, key string) string {
+	path := filepath.Join(s.dir, "master.key")
+	c.Assert(ioutil.WriteFile(path, []byte(key+"\n"), 0600), IsNil)
+	return path
+}
+
+func (s *testEncryptionSuite) TestFileMasterKey(c *C) {
+	key, err := newMasterKey(MasterKeyConfig{Type: masterKeyTypeFile, Path: s.writeMasterKey(c, strings.Repeat("ab", 32))})
+	c.Assert(err, IsNil)
+	ciphertext, err := key.encrypt([]byte("data key"))
+	c.Assert(err, IsNil)
+	c.Assert(strings.Contains(string(ciphertext), "data key"), IsFalse)
+	plaintext, err := key.decrypt(ciphertext)
+	c.Assert(err, IsNil)
+	c.Assert(string(plaintext), Equals, "data key")
+
+	// The ciphertext is authenticated.
+	ciphertext[len(ciphertext)-1] ^= 1
+	_, err = key.decrypt(ciphertext)
+	c.Assert(err, NotNil)
+
+	_, err = newMasterKey(MasterKeyConfig{Type: masterKeyTypeFile, Path: s.writeMasterKey(c, strings.Repeat("ab", 16))})
+	c.Assert(err, NotNil)
+	_, err = newMasterKey(MasterKeyConfig{Type: masterKeyTypeFile, Path: s.writeMasterKey(c, "not hex")})
+	c.Assert(err, NotNil)
+}
+
+func (s *testEncryptionSuite) TestKMSMasterKey(c *C) {
+	// The fake transit engine wraps the plaintext without encrypting it.
+	kms := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "vault-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var in kmsData
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var out kmsData
+		switch r.URL.Path {
+		case "/v1/transit/encrypt/pd":
+			out.Ciphertext = "vault:v1:" + in.Plaintext
+		case "/v1/transit/decrypt/pd":
+			out.Plaintext = strings.TrimPrefix(in.Ciphertext, "vault:v1:")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": out})
+	}))
+	defer kms.Close()
+
+	key, err := newMasterKey(MasterKeyConfig{Type: masterKeyTypeKMS, Endpoint: kms.URL + "/", KeyName: "pd", Token: "vault-token"})
+	c.Assert(err, IsNil)
+	ciphertext, err := key.encrypt([]byte("data key"))
+	c.Assert(err, IsNil)
+	c.Assert(strings.HasPrefix(string(ciphertext), "vault:v1:"), IsTrue)
+	plaintext, err := key.decrypt(ciphertext)
+	c.Assert(err, IsNil)
+	c.Assert(string(plaintext), Equals, "data key")
+
+	key, err = newMasterKey(MasterKeyConfig{Type: masterKeyTypeKMS, Endpoint: kms.URL, KeyName: "pd", Token: "wrong"})
+	c.Assert(err, IsNil)
+	_, err = key.encrypt([]byte("data key"))
+	c.Assert(err, NotNil)
+}
+
+func (s *testEncryptionSuite) TestKeyManagerService(c *C) {
+	cfg := NewTestSingleConfig()
+	cfg.Encryption = EncryptionConfig{
+		DataKeyRotationPeriod: typeutil.NewDuration(time.Hour),
+		ClientToken:           "tikv-token",
+		MasterKey:             MasterKeyConfig{Type: masterKeyTypeFile, Path: s.writeMasterKey(c, strings.Repeat("01", 32))},
+	}
+	svrs, cleanup := newTestServersWithCfgs(c, []*Config{cfg})
+	defer cleanup()
+	svr := svrs[0]
+
+	conn, err := grpc.Dial(strings.TrimPrefix(svr.GetAddr(), "http://"), grpc.WithInsecure())
+	c.Assert(err, IsNil)
+	defer conn.Close()
+	client := encryptionpb.NewKeyManagerClient(conn)
+	header := newRequestHeader(svr.clusterID)
+
+	_, err = client.GetCurrentKey(context.Background(), &encryptionpb.GetCurrentKeyRequest{Header: header})
+	c.Assert(status.Code(err), Equals, codes.Unauthenticated)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer wrong")
+	_, err = client.GetCurrentKey(ctx, &encryptionpb.GetCurrentKeyRequest{Header: header})
+	c.Assert(status.Code(err), Equals, codes.Unauthenticated)
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer tikv-token")
+	first := mustGetCurrentKey(c, ctx, client, header)
+	c.Assert(first.GetKeyId(), Greater, uint64(0))
+	c.Assert(first.GetKey(), HasLen, dataKeyLength)
+	c.Assert(first.GetMethod(), Equals, encryptionpb.EncryptionMethod_AES256_CTR)
+
+	// The key is kept after rotation.
+	c.Assert(svr.keys.rotateIfExpired(time.Now()), IsNil)
+	c.Assert(svr.keys.current, DeepEquals, first)
+	c.Assert(svr.keys.rotateIfExpired(time.Now().Add(time.Hour)), IsNil)
+	resp, err := client.GetCurrentKey(ctx, &encryptionpb.GetCurrentKeyRequest{Header: header})
+	c.Assert(err, IsNil)
+	second := resp.GetKey()
+	c.Assert(second.GetKeyId(), Greater, first.GetKeyId())
+	c.Assert(second.GetKey(), Not(DeepEquals), first.GetKey())
+	resp, err = client.GetKey(ctx, &encryptionpb.GetKeyRequest{Header: header, KeyId: first.GetKeyId()})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetKey(), DeepEquals, first)
+	_, err = client.GetKey(ctx, &encryptionpb.GetKeyRequest{Header: header, KeyId: second.GetKeyId() + 1})
+	c.Assert(status.Code(err), Equals, codes.NotFound)
+
+	// The keys are loaded by the next leader.
+	svr.keys.stop()
+	svr.keys.start()
+	c.Assert(mustGetCurrentKey(c, ctx, client, header), DeepEquals, second)
+	resp, err = client.GetKey(ctx, &encryptionpb.GetKeyRequest{Header: header, KeyId: first.GetKeyId()})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetKey(), DeepEquals, first)
+
+	// The keys are unavailable if the master key fails to decrypt them, but
+	// the leadership is not affected.
+	svr.keys.stop()
+	master := svr.keys.master
+	svr.keys.master, err = newMasterKey(MasterKeyConfig{Type: masterKeyTypeFile, Path: s.writeMasterKey(c, strings.Repeat("02", 32))})
+	c.Assert(err, IsNil)
+	svr.keys.start()
+	time.Sleep(100 * time.Millisecond)
+	_, err = client.GetCurrentKey(ctx, &encryptionpb.GetCurrentKeyRequest{Header: header})
+	c.Assert(status.Code(err), Equals, codes.Unavailable)
+	_, err = client.GetKey(ctx, &encryptionpb.GetKeyRequest{Header: header, KeyId: first.GetKeyId()})
+	c.Assert(status.Code(err), Equals, codes.Unavailable)
+	c.Assert(svr.IsLeader(), IsTrue)
+	svr.keys.stop()
+	svr.keys.master = master
+	svr.keys.start()
+	c.Assert(mustGetCurrentKey(c, ctx, client, header), DeepEquals, second)
+}
+
+// mustGetCurrentKey waits for the leader to load the data keys and returns the
+// current key.
+func mustGetCurrentKey(c *C, ctx context.Context, client encryptionpb.KeyManagerClient, header *pdpb.RequestHeader) *encryptionpb.DataKey {
+	for i := 0; i < 100; i++ {
+		resp, err := client.GetCurrentKey(ctx, &encryptionpb.GetCurrentKeyRequest{Header: header})
+		if status.Code(err) != codes.Unavailable {
+			c.Assert(err, IsNil)
+			return resp.GetKey()
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	c.Fatal("data keys are not loaded")
+	return nil
+}
+
+func (s *testEncryptionSuite) TestEncryptionDisabled(c *C) {
+	_, svr, cleanup, err := NewTestServer()
+	c.Assert(err, IsNil)
+	defer cleanup()
+	mustWaitLeader(c, []*Server{svr})
+
+	conn, err := grpc.Dial(strings.TrimPrefix(svr.GetAddr(), "http://"), grpc.WithInsecure())
+	c.Assert(err, IsNil)
+	defer conn.Close()
+	client := encryptionpb.NewKeyManagerClient(conn)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer ")
+	_, err = client.GetCurrentKey(ctx, &encryptionpb.GetCurrentKeyRequest{Header: newRequestHeader(svr.clusterID)})
+	c.Assert(status.Code(err), Equals, codes.FailedPrecondition)
+}
+
+func (s *testEncryptionSuite) TestConfig(c *C) {
+	cfg := &EncryptionConfig{}
+	c.Assert(cfg.adjust(), IsNil)
+	c.Assert(cfg.DataKeyRotationPeriod.Duration, Equals, defaultDataKeyRotationPeriod)
+	cfg.MasterKey.Type = masterKeyTypeFile
+	c.Assert(cfg.adjust(), NotNil)
+	cfg.MasterKey.Path = "master.key"
+	c.Assert(cfg.adjust(), NotNil)
+	cfg.ClientToken = "token"
+	c.Assert(cfg.adjust(), IsNil)
+	cfg.MasterKey.Type = masterKeyTypeKMS
+	c.Assert(cfg.adjust(), NotNil)
+	cfg.MasterKey.Type = "unknown"
+	c.Assert(cfg.adjust(), NotNil)
+}
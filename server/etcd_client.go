@@ -0,0 +1,163 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"path"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pingcap/pd/pkg/etcdutil"
+	"github.com/pingcap/pd/pkg/logutil"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+)
+
+// etcdEndpointsSyncInterval is the interval to sync the endpoints of the etcd
+// client with the etcd membership and the health of each endpoint.
+const etcdEndpointsSyncInterval = 10 * time.Second
+
+// pickEtcdEndpoints returns the healthy endpoints in `all`, with the local
+// ones first so that requests are served by the local etcd if possible. All
+// endpoints are returned if none of them is healthy, the client will keep
+// retrying them until one recovers.
+func pickEtcdEndpoints(local, all []string, healthy func(endpoint string) bool) []string {
+	var endpoints []string
+	seen := make(map[string]struct{})
+	for _, list := range [][]string{local, all} {
+		for _, ep := range list {
+			if _, ok := seen[ep]; ok {
+				continue
+			}
+			seen[ep] = struct{}{}
+			if healthy(ep) {
+				endpoints = append(endpoints, ep)
+			}
+		}
+	}
+	if len(endpoints) > 0 {
+		return endpoints
+	}
+	for _, list := range [][]string{local, all} {
+		for _, ep := range list {
+			if _, ok := seen[ep]; ok {
+				endpoints = append(endpoints, ep)
+				delete(seen, ep)
+			}
+		}
+	}
+	return endpoints
+}
+
+func equalEndpoints(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// localEtcdEndpoints returns the advertise client urls of the embedded etcd.
+func (s *Server) localEtcdEndpoints() []string {
+	endpoints := make([]string, 0, len(s.etcdCfg.ACUrls))
+	for _, u := range s.etcdCfg.ACUrls {
+		endpoints = append(endpoints, u.String())
+	}
+	return endpoints
+}
+
+// checkEtcdEndpoint reports whether the etcd endpoint is able to serve requests.
+func (s *Server) checkEtcdEndpoint(ctx context.Context, endpoint string) bool {
+	ctx, cancel := context.WithTimeout(ctx, etcdTimeout)
+	defer cancel()
+	if _, err := s.client.Status(ctx, endpoint); err != nil {
+		log.Warnf("etcd endpoint %s is unhealthy: %v", endpoint, err)
+		etcdEndpointCheckCounter.WithLabelValues(endpoint, "failed").Inc()
+		return false
+	}
+	etcdEndpointCheckCounter.WithLabelValues(endpoint, "ok").Inc()
+	return true
+}
+
+// etcdClientInterceptor records the latency and the failures of the unary
+// requests of the etcd client by the endpoint serving them, which is the
+// address of the peer picked by the balancer of the client.
+func etcdClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	var p peer.Peer
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Peer(&p))...)
+	endpoint := "unknown"
+	if p.Addr != nil {
+		endpoint = p.Addr.String()
+	}
+	method = path.Base(method)
+	etcdClientRequestDuration.WithLabelValues(endpoint, method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		etcdClientRequestFailedCounter.WithLabelValues(endpoint, method).Inc()
+	}
+	return err
+}
+
+func memberClientURLs(members *clientv3.MemberListResponse) []string {
+	var urls []string
+	for _, m := range members.Members {
+		urls = append(urls, m.ClientURLs...)
+	}
+	return urls
+}
+
+// syncEtcdEndpoints updates the endpoints of the etcd client with the client
+// urls of all etcd members, and rotates the unhealthy ones out. It replaces
+// the auto sync of the client, which brings the unhealthy endpoints back.
+func (s *Server) syncEtcdEndpoints(ctx context.Context) error {
+	members, err := etcdutil.ListEtcdMembers(s.client)
+	if err != nil {
+		return err
+	}
+	endpoints := pickEtcdEndpoints(s.localEtcdEndpoints(), memberClientURLs(members), func(ep string) bool {
+		return s.checkEtcdEndpoint(ctx, ep)
+	})
+	if old := s.client.Endpoints(); !equalEndpoints(old, endpoints) {
+		log.Infof("update etcd client endpoints from %v to %v", old, endpoints)
+		s.client.SetEndpoints(endpoints...)
+	}
+	return nil
+}
+
+func (s *Server) etcdEndpointsLoop() {
+	defer logutil.LogPanic()
+	defer s.serverLoopWg.Done()
+
+	ctx, cancel := context.WithCancel(s.serverLoopCtx)
+	defer cancel()
+	ticker := time.NewTicker(etcdEndpointsSyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.syncEtcdEndpoints(ctx); err != nil {
+				log.Errorf("failed to sync etcd client endpoints: %v", err)
+			}
+		case <-ctx.Done():
+			log.Info("server is closed, exit etcd endpoints loop")
+			return
+		}
+	}
+}
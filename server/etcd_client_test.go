@@ -0,0 +1,98 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/pd/pkg/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/grpc"
+)
+
+var _ = Suite(&testEtcdClientSuite{})
+
+type testEtcdClientSuite struct{}
+
+func (s *testEtcdClientSuite) TestPickEtcdEndpoints(c *C) {
+	local := []string{"http://a"}
+	all := []string{"http://b", "http://a", "http://c"}
+	healthy := func(unhealthy ...string) func(string) bool {
+		return func(ep string) bool {
+			for _, u := range unhealthy {
+				if ep == u {
+					return false
+				}
+			}
+			return true
+		}
+	}
+
+	c.Assert(pickEtcdEndpoints(local, all, healthy()), DeepEquals, []string{"http://a", "http://b", "http://c"})
+	c.Assert(pickEtcdEndpoints(local, all, healthy("http://a")), DeepEquals, []string{"http://b", "http://c"})
+	c.Assert(pickEtcdEndpoints(local, all, healthy("http://a", "http://c")), DeepEquals, []string{"http://b"})
+	c.Assert(pickEtcdEndpoints(local, all, healthy("http://a", "http://b", "http://c")), DeepEquals, []string{"http://a", "http://b", "http://c"})
+}
+
+func (s *testEtcdClientSuite) TestSyncEtcdEndpoints(c *C) {
+	cfgs := NewTestMultiConfig(3)
+	svrs, cleanup := newTestServersWithCfgs(c, cfgs)
+	defer cleanup()
+
+	for _, svr := range svrs {
+		endpoints := svr.GetEndpoints()
+		c.Assert(endpoints, HasLen, 3)
+		c.Assert(endpoints[0], Equals, svr.localEtcdEndpoints()[0])
+	}
+
+	// The endpoint of the closed server is rotated out. A live endpoint may
+	// fail the health check while the cluster is electing a new leader, so
+	// sync until it settles.
+	closed := svrs[2]
+	closed.Close()
+	for _, svr := range svrs[:2] {
+		var endpoints []string
+		testutil.WaitUntil(c, func(c *C) bool {
+			c.Assert(svr.syncEtcdEndpoints(context.Background()), IsNil)
+			endpoints = svr.GetEndpoints()
+			return len(endpoints) == 2
+		})
+		c.Assert(endpoints[0], Equals, svr.localEtcdEndpoints()[0])
+		for _, ep := range endpoints {
+			c.Assert(ep, Not(Equals), closed.localEtcdEndpoints()[0])
+		}
+	}
+}
+
+func (s *testEtcdClientSuite) TestEtcdClientInterceptor(c *C) {
+	failed := func() float64 {
+		var m dto.Metric
+		c.Assert(etcdClientRequestFailedCounter.WithLabelValues("unknown", "Range").Write(&m), IsNil)
+		return m.GetCounter().GetValue()
+	}
+	errFailed := errors.New("failed")
+	invoke := func(err error) grpc.UnaryInvoker {
+		return func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+			return err
+		}
+	}
+
+	base := failed()
+	c.Assert(etcdClientInterceptor(context.Background(), "/etcdserverpb.KV/Range", nil, nil, nil, invoke(nil)), IsNil)
+	c.Assert(failed(), Equals, base)
+	c.Assert(etcdClientInterceptor(context.Background(), "/etcdserverpb.KV/Range", nil, nil, nil, invoke(errFailed)), Equals, errFailed)
+	c.Assert(failed(), Equals, base+1)
+}
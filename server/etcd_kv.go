@@ -46,10 +46,12 @@ func newEtcdKVBase(s *Server) *etcdKVBase {
 	}
 }
 
-func (kv *etcdKVBase) Load(key string) (string, error) {
+func (kv *etcdKVBase) Load(ctx context.Context, key string) (string, error) {
 	key = path.Join(kv.rootPath, key)
 
-	resp, err := kvGet(kv.server.client, key)
+	ctx, cancel := kv.server.readContext(ctx)
+	defer cancel()
+	resp, err := kvGet(ctx, kv.client, key)
 	if err != nil {
 		return "", err
 	}
@@ -61,13 +63,15 @@ func (kv *etcdKVBase) Load(key string) (string, error) {
 	return string(resp.Kvs[0].Value), nil
 }
 
-func (kv *etcdKVBase) LoadRange(key, endKey string, limit int) ([]string, error) {
+func (kv *etcdKVBase) LoadRange(ctx context.Context, key, endKey string, limit int) ([]string, error) {
 	key = path.Join(kv.rootPath, key)
 	endKey = path.Join(kv.rootPath, endKey)
 
 	withRange := clientv3.WithRange(endKey)
 	withLimit := clientv3.WithLimit(int64(limit))
-	resp, err := kvGet(kv.server.client, key, withRange, withLimit)
+	ctx, cancel := kv.server.readContext(ctx)
+	defer cancel()
+	resp, err := kvGet(ctx, kv.client, key, withRange, withLimit)
 	if err != nil {
 		return nil, err
 	}
@@ -78,10 +82,10 @@ func (kv *etcdKVBase) LoadRange(key, endKey string, limit int) ([]string, error)
 	return res, nil
 }
 
-func (kv *etcdKVBase) Save(key, value string) error {
+func (kv *etcdKVBase) Save(ctx context.Context, key, value string) error {
 	key = path.Join(kv.rootPath, key)
 
-	resp, err := kv.server.leaderTxn().Then(clientv3.OpPut(key, value)).Commit()
+	resp, err := kv.server.leaderTxnWithContext(ctx).Then(clientv3.OpPut(key, value)).Commit()
 	if err != nil {
 		log.Errorf("save to etcd error: %v", err)
 		return errors.WithStack(err)
@@ -92,10 +96,10 @@ func (kv *etcdKVBase) Save(key, value string) error {
 	return nil
 }
 
-func (kv *etcdKVBase) Delete(key string) error {
+func (kv *etcdKVBase) Delete(ctx context.Context, key string) error {
 	key = path.Join(kv.rootPath, key)
 
-	resp, err := kv.server.leaderTxn().Then(clientv3.OpDelete(key)).Commit()
+	resp, err := kv.server.leaderTxnWithContext(ctx).Then(clientv3.OpDelete(key)).Commit()
 	if err != nil {
 		log.Errorf("delete from etcd error: %v", err)
 		return errors.WithStack(err)
@@ -106,14 +110,16 @@ func (kv *etcdKVBase) Delete(key string) error {
 	return nil
 }
 
-func kvGet(c *clientv3.Client, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
-	ctx, cancel := context.WithTimeout(c.Ctx(), kvRequestTimeout)
+// kvGet gets the key from etcd. The request is canceled when ctx is done,
+// or after kvRequestTimeout if ctx has no deadline.
+func kvGet(ctx context.Context, c *clientv3.Client, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	ctx, cancel := withDefaultTimeout(ctx, kvRequestTimeout)
 	defer cancel()
 
 	start := time.Now()
 	resp, err := clientv3.NewKV(c).Get(ctx, key, opts...)
 	if err != nil {
-		log.Errorf("load from etcd error: %v", err)
+		log.Errorf("load from etcd error: key %v err %v", key, err)
 	}
 	if cost := time.Since(start); cost > kvSlowRequestTime {
 		log.Warnf("kv gets too slow: key %v cost %v err %v", key, cost, err)
@@ -13,7 +13,11 @@
 
 package server
 
-import . "github.com/pingcap/check"
+import (
+	"context"
+
+	. "github.com/pingcap/check"
+)
 
 type testEtcdKVSuite struct{}
 
@@ -23,38 +27,51 @@ func (s *testEtcdKVSuite) TestEtcdKV(c *C) {
 	server, cleanup := mustRunTestServer(c)
 	defer cleanup()
 	kv := server.kv.KVBase
+	ctx := context.Background()
 
 	keys := []string{"test/key1", "test/key2", "test/key3", "test/key4", "test/key5"}
 	vals := []string{"val1", "val2", "val3", "val4", "val5"}
 
-	v, err := kv.Load(keys[0])
+	v, err := kv.Load(ctx, keys[0])
 	c.Assert(err, IsNil)
 	c.Assert(v, Equals, "")
 
 	for i := range keys {
-		err = kv.Save(keys[i], vals[i])
+		err = kv.Save(ctx, keys[i], vals[i])
 		c.Assert(err, IsNil)
 	}
 	for i := range keys {
-		v, err = kv.Load(keys[i])
+		v, err = kv.Load(ctx, keys[i])
 		c.Assert(err, IsNil)
 		c.Assert(v, Equals, vals[i])
 	}
-	values, err := kv.LoadRange(keys[0], "test/zzz", 100)
+	values, err := kv.LoadRange(ctx, keys[0], "test/zzz", 100)
 	c.Assert(err, IsNil)
 	c.Assert(values, DeepEquals, vals)
-	values, err = kv.LoadRange(keys[0], "test/zzz", 3)
+	values, err = kv.LoadRange(ctx, keys[0], "test/zzz", 3)
 	c.Assert(err, IsNil)
 	c.Assert(values, DeepEquals, vals[:3])
-	values, err = kv.LoadRange(keys[0], keys[3], 100)
+	values, err = kv.LoadRange(ctx, keys[0], keys[3], 100)
 	c.Assert(err, IsNil)
 	c.Assert(values, DeepEquals, vals[:3])
 
-	v, err = kv.Load(keys[1])
+	v, err = kv.Load(ctx, keys[1])
 	c.Assert(err, IsNil)
 	c.Assert(v, Equals, "val2")
-	c.Assert(kv.Delete(keys[1]), IsNil)
-	v, err = kv.Load(keys[1])
+	c.Assert(kv.Delete(ctx, keys[1]), IsNil)
+	v, err = kv.Load(ctx, keys[1])
 	c.Assert(err, IsNil)
 	c.Assert(v, Equals, "")
+
+	// The operations fail if the context is canceled.
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = kv.Load(ctx, keys[0])
+	c.Assert(err, NotNil)
+	c.Assert(kv.Save(ctx, keys[0], "val"), NotNil)
+	values, err = server.kv.WithContext(ctx).LoadRange(context.Background(), keys[0], "test/zzz", 1)
+	c.Assert(err, IsNil)
+	c.Assert(values, DeepEquals, vals[:1])
+	_, err = server.kv.WithContext(ctx).LoadGCSafePoint()
+	c.Assert(err, NotNil)
 }
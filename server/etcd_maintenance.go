@@ -0,0 +1,266 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/coreos/etcd/etcdserver/api/v3rpc/rpctypes"
+	"github.com/pingcap/pd/pkg/etcdutil"
+	"github.com/pingcap/pd/pkg/logutil"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// etcdDefragTimeout is the timeout to defragment a member, which blocks the
+// member until it finishes.
+const etcdDefragTimeout = 5 * time.Minute
+
+var (
+	// ErrEtcdMemberNotFound is error info for etcd member not found.
+	ErrEtcdMemberNotFound = errors.New("etcd member not found")
+	// ErrDefragLeader is error info for defragmenting the leader, which
+	// blocks the writes of the whole cluster.
+	ErrDefragLeader = errors.New("can not defragment the leader")
+	// ErrDefragUnhealthy is error info for defragmenting a member while some
+	// member is unhealthy, the cluster may lose its quorum.
+	ErrDefragUnhealthy = errors.New("can not defragment while some member is unhealthy")
+)
+
+// EtcdMemberStatus is the backend status of an etcd member.
+type EtcdMemberStatus struct {
+	Name     string `json:"name"`
+	MemberID uint64 `json:"member_id"`
+	Endpoint string `json:"endpoint"`
+	// IsLeader is true if the member is the etcd leader or the PD leader.
+	IsLeader    bool   `json:"is_leader"`
+	Revision    int64  `json:"revision"`
+	DbSize      int64  `json:"db_size"`
+	DbSizeInUse int64  `json:"db_size_in_use"`
+	Error       string `json:"error,omitempty"`
+}
+
+func (m *EtcdMemberStatus) healthy() bool {
+	return m.Error == ""
+}
+
+// fragmentedSize returns the size of the space wasted by the fragmentation.
+func (m *EtcdMemberStatus) fragmentedSize() int64 {
+	if m.DbSizeInUse == 0 || m.DbSize < m.DbSizeInUse {
+		return 0
+	}
+	return m.DbSize - m.DbSizeInUse
+}
+
+// EtcdMaintenanceStatus is the status of the etcd members and the last
+// compaction by PD.
+type EtcdMaintenanceStatus struct {
+	Members         []*EtcdMemberStatus `json:"members"`
+	CompactRevision int64               `json:"compact_revision"`
+}
+
+// etcdMaintenance serializes the compactions and defragmentations, so that
+// members are defragmented one at a time.
+type etcdMaintenance struct {
+	sync.Mutex
+	compactRevision int64
+}
+
+// getEtcdMemberStatus returns the status of all etcd members sorted by name.
+func (s *Server) getEtcdMemberStatus(ctx context.Context) ([]*EtcdMemberStatus, error) {
+	members, err := etcdutil.ListEtcdMembers(s.client)
+	if err != nil {
+		return nil, err
+	}
+	var leaderID uint64
+	statuses := make([]*EtcdMemberStatus, 0, len(members.Members))
+	for _, m := range members.Members {
+		status := &EtcdMemberStatus{Name: m.Name, MemberID: m.ID}
+		statuses = append(statuses, status)
+		if len(m.ClientURLs) == 0 {
+			status.Error = "member is not started"
+			continue
+		}
+		status.Endpoint = m.ClientURLs[0]
+		cctx, cancel := context.WithTimeout(ctx, etcdTimeout)
+		resp, err := s.client.Status(cctx, status.Endpoint)
+		cancel()
+		if err != nil {
+			status.Error = err.Error()
+			continue
+		}
+		if len(resp.Errors) > 0 {
+			status.Error = resp.Errors[0]
+		}
+		status.Revision = resp.Header.GetRevision()
+		status.DbSize = resp.DbSize
+		status.DbSizeInUse = resp.DbSizeInUse
+		leaderID = resp.Leader
+	}
+	for _, status := range statuses {
+		status.IsLeader = status.MemberID == leaderID || status.MemberID == s.ID()
+		etcdBackendGauge.WithLabelValues(status.Name, "db_size").Set(float64(status.DbSize))
+		etcdBackendGauge.WithLabelValues(status.Name, "db_size_in_use").Set(float64(status.DbSizeInUse))
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses, nil
+}
+
+// GetEtcdMaintenanceStatus returns the backend status of the etcd members.
+func (s *Server) GetEtcdMaintenanceStatus() (*EtcdMaintenanceStatus, error) {
+	members, err := s.getEtcdMemberStatus(s.serverLoopCtx)
+	if err != nil {
+		return nil, err
+	}
+	s.etcdMaintenance.Lock()
+	defer s.etcdMaintenance.Unlock()
+	return &EtcdMaintenanceStatus{
+		Members:         members,
+		CompactRevision: s.etcdMaintenance.compactRevision,
+	}, nil
+}
+
+// CompactEtcd compacts etcd to the current revision, and returns the revision.
+func (s *Server) CompactEtcd() (int64, error) {
+	if !s.IsLeader() {
+		return 0, errors.Wrap(ErrNotLeader, "compact etcd failed")
+	}
+	s.etcdMaintenance.Lock()
+	defer s.etcdMaintenance.Unlock()
+
+	ctx, cancel := context.WithTimeout(s.serverLoopCtx, etcdTimeout)
+	defer cancel()
+	resp, err := s.client.Get(ctx, s.getLeaderPath())
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	rev := resp.Header.GetRevision()
+	if _, err = s.client.Compact(ctx, rev); err != nil && err != rpctypes.ErrCompacted {
+		etcdMaintenanceCounter.WithLabelValues("compact", "failed").Inc()
+		return 0, errors.WithStack(err)
+	}
+	etcdMaintenanceCounter.WithLabelValues("compact", "ok").Inc()
+	log.Infof("etcd is compacted to revision %d", rev)
+	s.etcdMaintenance.compactRevision = rev
+	return rev, nil
+}
+
+// DefragEtcdMember defragments the etcd member. It is refused if the member
+// is the leader, or if any member is unhealthy.
+func (s *Server) DefragEtcdMember(name string) error {
+	if !s.IsLeader() {
+		return errors.Wrap(ErrNotLeader, "defragment etcd failed")
+	}
+	s.etcdMaintenance.Lock()
+	defer s.etcdMaintenance.Unlock()
+
+	members, err := s.getEtcdMemberStatus(s.serverLoopCtx)
+	if err != nil {
+		return err
+	}
+	var target *EtcdMemberStatus
+	for _, m := range members {
+		if !m.healthy() {
+			return errors.Wrapf(ErrDefragUnhealthy, "member %s: %s", m.Name, m.Error)
+		}
+		if m.Name == name {
+			target = m
+		}
+	}
+	if target == nil {
+		return errors.Wrap(ErrEtcdMemberNotFound, name)
+	}
+	if target.IsLeader {
+		return errors.Wrap(ErrDefragLeader, name)
+	}
+
+	ctx, cancel := context.WithTimeout(s.serverLoopCtx, etcdDefragTimeout)
+	defer cancel()
+	start := time.Now()
+	if _, err = s.client.Defragment(ctx, target.Endpoint); err != nil {
+		etcdMaintenanceCounter.WithLabelValues("defrag", "failed").Inc()
+		return errors.WithStack(err)
+	}
+	etcdMaintenanceCounter.WithLabelValues("defrag", "ok").Inc()
+	log.Infof("etcd member %s is defragmented in %v, db size was %d, in use %d", name, time.Since(start), target.DbSize, target.DbSizeInUse)
+	return nil
+}
+
+// maintainEtcd compacts etcd if there are too many revisions since the last
+// compaction, and defragments the fragmented members one by one in the
+// maintenance windows.
+func (s *Server) maintainEtcd(now time.Time) {
+	cfg := &s.cfg.EtcdMaintenance
+	members, err := s.getEtcdMemberStatus(s.serverLoopCtx)
+	if err != nil {
+		log.Errorf("failed to get the status of etcd members: %v", err)
+		return
+	}
+
+	if cfg.CompactRevisionThreshold > 0 {
+		var rev int64
+		for _, m := range members {
+			if m.Revision > rev {
+				rev = m.Revision
+			}
+		}
+		s.etcdMaintenance.Lock()
+		needCompact := rev-s.etcdMaintenance.compactRevision > int64(cfg.CompactRevisionThreshold)
+		s.etcdMaintenance.Unlock()
+		if needCompact {
+			if _, err = s.CompactEtcd(); err != nil {
+				log.Errorf("failed to compact etcd: %v", err)
+			}
+		}
+	}
+
+	if cfg.DefragThreshold == 0 || !cfg.inDefragWindow(now) {
+		return
+	}
+	for _, m := range members {
+		if m.IsLeader || m.fragmentedSize() < int64(cfg.DefragThreshold) {
+			continue
+		}
+		// The health of all members is checked again before each one.
+		if err = s.DefragEtcdMember(m.Name); err != nil {
+			log.Errorf("failed to defragment etcd member %s: %v", m.Name, err)
+			return
+		}
+	}
+}
+
+func (s *Server) etcdMaintenanceLoop() {
+	defer logutil.LogPanic()
+	defer s.serverLoopWg.Done()
+
+	ctx, cancel := context.WithCancel(s.serverLoopCtx)
+	defer cancel()
+	ticker := time.NewTicker(s.cfg.EtcdMaintenance.CheckInterval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if !s.IsLeader() {
+				continue
+			}
+			s.maintainEtcd(time.Now())
+		case <-ctx.Done():
+			log.Info("server is closed, exit etcd maintenance loop")
+			return
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pkg/errors"
+)
+
+var _ = Suite(&testEtcdMaintenanceSuite{})
+
+type testEtcdMaintenanceSuite struct{}
+
+func (s *testEtcdMaintenanceSuite) TestMaintenanceWindow(c *C) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2018, 1, 1, hour, minute, 0, 0, time.Local)
+	}
+	w, err := parseMaintenanceWindow("02:00-04:30")
+	c.Assert(err, IsNil)
+	c.Assert(w.contains(at(1, 59)), IsFalse)
+	c.Assert(w.contains(at(2, 0)), IsTrue)
+	c.Assert(w.contains(at(4, 29)), IsTrue)
+	c.Assert(w.contains(at(4, 30)), IsFalse)
+
+	// The window crosses midnight.
+	w, err = parseMaintenanceWindow("23:00-01:00")
+	c.Assert(err, IsNil)
+	c.Assert(w.contains(at(23, 30)), IsTrue)
+	c.Assert(w.contains(at(0, 30)), IsTrue)
+	c.Assert(w.contains(at(12, 0)), IsFalse)
+
+	for _, invalid := range []string{"", "02:00", "2-4", "02:00-25:00", "02:00-02:00"} {
+		_, err = parseMaintenanceWindow(invalid)
+		c.Assert(err, NotNil, Commentf("window %q", invalid))
+	}
+
+	cfg := &EtcdMaintenanceConfig{DefragWindows: []string{"02:00-04:00", "12:00-13:00"}}
+	c.Assert(cfg.adjust(), IsNil)
+	c.Assert(cfg.CheckInterval.Duration, Equals, defaultEtcdMaintenanceInterval)
+	c.Assert(cfg.inDefragWindow(at(12, 30)), IsTrue)
+	c.Assert(cfg.inDefragWindow(at(5, 0)), IsFalse)
+	cfg.DefragWindows = append(cfg.DefragWindows, "invalid")
+	c.Assert(cfg.adjust(), NotNil)
+}
+
+func (s *testEtcdMaintenanceSuite) TestCompactAndDefrag(c *C) {
+	cfgs := NewTestMultiConfig(3)
+	svrs, cleanup := newTestServersWithCfgs(c, cfgs)
+	defer cleanup()
+	leader := mustWaitLeader(c, svrs)
+
+	status, err := leader.GetEtcdMaintenanceStatus()
+	c.Assert(err, IsNil)
+	c.Assert(status.Members, HasLen, 3)
+	var follower *EtcdMemberStatus
+	for _, m := range status.Members {
+		c.Assert(m.Error, Equals, "")
+		c.Assert(m.DbSize, Greater, int64(0))
+		if m.MemberID == leader.ID() {
+			c.Assert(m.IsLeader, IsTrue)
+		}
+		if !m.IsLeader {
+			follower = m
+		}
+	}
+	c.Assert(follower, NotNil)
+
+	// Only the leader maintains etcd.
+	for _, svr := range svrs {
+		if svr != leader {
+			_, err = svr.CompactEtcd()
+			c.Assert(errors.Cause(err), Equals, ErrNotLeader)
+			break
+		}
+	}
+
+	// Compact once there are more revisions than the threshold.
+	leader.cfg.EtcdMaintenance.CompactRevisionThreshold = 10
+	for i := 0; i < 20; i++ {
+		_, err = leader.client.Put(context.TODO(), fmt.Sprintf("%s/test/%d", leader.rootPath, i), "v")
+		c.Assert(err, IsNil)
+	}
+	leader.maintainEtcd(time.Now())
+	status, err = leader.GetEtcdMaintenanceStatus()
+	c.Assert(err, IsNil)
+	compacted := status.CompactRevision
+	c.Assert(compacted, Greater, int64(20))
+	leader.maintainEtcd(time.Now())
+	status, err = leader.GetEtcdMaintenanceStatus()
+	c.Assert(err, IsNil)
+	c.Assert(status.CompactRevision, Equals, compacted)
+	rev, err := leader.CompactEtcd()
+	c.Assert(err, IsNil)
+	c.Assert(rev, GreaterEqual, compacted)
+
+	c.Assert(errors.Cause(leader.DefragEtcdMember("unknown")), Equals, ErrEtcdMemberNotFound)
+	c.Assert(errors.Cause(leader.DefragEtcdMember(leader.Name())), Equals, ErrDefragLeader)
+	c.Assert(leader.DefragEtcdMember(follower.Name), IsNil)
+
+	// No member is defragmented while some member is down.
+	for _, svr := range svrs {
+		if svr != leader && svr.Name() != follower.Name {
+			svr.Close()
+			break
+		}
+	}
+	c.Assert(errors.Cause(leader.DefragEtcdMember(follower.Name)), Equals, ErrDefragUnhealthy)
+}
@@ -0,0 +1,130 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// forwardedByKey is the gRPC metadata key set on forwarded requests, so
+	// that they are never forwarded again if the leader changes meanwhile.
+	forwardedByKey = "pd-forwarded-by"
+	forwardTimeout = 3 * time.Second
+)
+
+// forwarder relays the leader-only requests received by a follower to the
+// leader, so that clients behind a load balancer can reach any member.
+type forwarder struct {
+	sync.Mutex
+	// conns are the connections to the leaders, keyed by the client URL.
+	conns map[string]*grpc.ClientConn
+}
+
+func newForwarder() *forwarder {
+	return &forwarder{conns: make(map[string]*grpc.ClientConn)}
+}
+
+func (f *forwarder) getConn(addr string, security SecurityConfig) (*grpc.ClientConn, error) {
+	f.Lock()
+	defer f.Unlock()
+	if conn, ok := f.conns[addr]; ok {
+		return conn, nil
+	}
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	opt := grpc.WithInsecure()
+	tlsCfg, err := security.ToTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil {
+		opt = grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg))
+	}
+	conn, err := grpc.Dial(u.Host, opt)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	f.conns[addr] = conn
+	return conn, nil
+}
+
+// close closes the connections to the previous leaders except the current one.
+func (f *forwarder) close(except string) {
+	f.Lock()
+	defer f.Unlock()
+	for addr, conn := range f.conns {
+		if addr == except {
+			continue
+		}
+		if err := conn.Close(); err != nil {
+			log.Warnf("failed to close forward connection to %s: %v", addr, err)
+		}
+		delete(f.conns, addr)
+	}
+}
+
+func isForwarded(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	return ok && len(md[forwardedByKey]) > 0
+}
+
+// shouldForward returns true if the request should be forwarded to the leader.
+// A request is never forwarded twice.
+func (s *Server) shouldForward(ctx context.Context) bool {
+	return !s.IsLeader() && s.GetLeader() != nil &&
+		!s.scheduleOpt.loadPDServerConfig().DisableForwarding && !isForwarded(ctx)
+}
+
+// forward calls the leader with a client connected to it, and records the
+// result.
+func (s *Server) forward(ctx context.Context, method string, call func(context.Context, pdpb.PDClient) error) error {
+	leader := s.GetLeader()
+	if len(leader.GetClientUrls()) == 0 {
+		return notLeaderError
+	}
+	addr := leader.GetClientUrls()[0]
+	conn, err := s.forwarder.getConn(addr, s.cfg.Security)
+	if err != nil {
+		forwardedRequestCounter.WithLabelValues(method, "failed").Inc()
+		log.Errorf("failed to connect to leader %s for forwarding: %v", addr, err)
+		return notLeaderError
+	}
+	s.forwarder.close(addr)
+
+	ctx, cancel := context.WithTimeout(ctx, forwardTimeout)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, forwardedByKey, s.Name())
+	start := time.Now()
+	err = call(ctx, pdpb.NewPDClient(conn))
+	forwardedRequestDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		forwardedRequestCounter.WithLabelValues(method, "failed").Inc()
+		return err
+	}
+	forwardedRequestCounter.WithLabelValues(method, "ok").Inc()
+	return nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var _ = Suite(&testForwardSuite{})
+
+type testForwardSuite struct{}
+
+func (s *testForwardSuite) TestForward(c *C) {
+	svrs, cleanup := newTestServersWithCfgs(c, NewTestMultiConfig(3))
+	defer cleanup()
+
+	var follower *Server
+	for _, svr := range svrs {
+		if !svr.IsLeader() {
+			follower = svr
+			break
+		}
+	}
+	client := mustNewGrpcClient(c, follower.GetAddr())
+	req := &pdpb.AllocIDRequest{Header: &pdpb.RequestHeader{ClusterId: follower.ClusterID()}}
+
+	// The follower forwards the request to the leader.
+	resp, err := client.AllocID(context.Background(), req)
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetId(), Greater, uint64(0))
+	isBootstrapped, err := client.IsBootstrapped(context.Background(), &pdpb.IsBootstrappedRequest{Header: req.Header})
+	c.Assert(err, NotNil)
+	c.Assert(isBootstrapped, IsNil)
+
+	// A forwarded request is not forwarded again.
+	ctx := metadata.AppendToOutgoingContext(context.Background(), forwardedByKey, "pd0")
+	_, err = client.AllocID(ctx, req)
+	c.Assert(status.Code(err), Equals, codes.Unavailable)
+
+	// Forwarding is disabled.
+	follower.scheduleOpt.pdServerConfig.Store(&PDServerConfig{DisableForwarding: true})
+	_, err = client.AllocID(context.Background(), req)
+	c.Assert(status.Code(err), Equals, codes.Unavailable)
+}
@@ -0,0 +1,262 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pingcap/pd/pkg/gcpb"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrInvalidServiceSafePoint is error info for invalid service safe point
+// arguments.
+var ErrInvalidServiceSafePoint = errors.New("invalid service safe point")
+
+// gcSafePointManager manages the cluster GC safe point and the safe points of
+// services such as CDC and backup. The GC safe point never exceeds the min
+// safe point of the unexpired services, and a service can not register a safe
+// point less than the GC safe point, whose data may have been collected.
+type gcSafePointManager struct {
+	sync.Mutex
+	kv *core.KV
+}
+
+func newGCSafePointManager(kv *core.KV) *gcSafePointManager {
+	return &gcSafePointManager{kv: kv}
+}
+
+// loadServiceSafePoints loads the safe points of services ordered by service
+// ID, the expired ones are deleted.
+func (m *gcSafePointManager) loadServiceSafePoints(now time.Time) ([]*gcpb.ServiceSafePoint, error) {
+	var ssps, expired []*gcpb.ServiceSafePoint
+	err := m.kv.LoadServiceGCSafePoints(func(data []byte) (string, error) {
+		ssp := &gcpb.ServiceSafePoint{}
+		if err := json.Unmarshal(data, ssp); err != nil {
+			return "", errors.WithStack(err)
+		}
+		if ssp.GetExpiredAt() <= now.Unix() {
+			expired = append(expired, ssp)
+		} else {
+			ssps = append(ssps, ssp)
+		}
+		return ssp.GetServiceId(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, ssp := range expired {
+		if err := m.kv.DeleteServiceGCSafePoint(ssp.GetServiceId()); err != nil {
+			return nil, err
+		}
+		log.Infof("safe point %d of service %s is expired", ssp.GetSafePoint(), ssp.GetServiceId())
+	}
+	sort.Slice(ssps, func(i, j int) bool { return ssps[i].GetServiceId() < ssps[j].GetServiceId() })
+	return ssps, nil
+}
+
+func minServiceSafePoint(ssps []*gcpb.ServiceSafePoint) (uint64, bool) {
+	if len(ssps) == 0 {
+		return 0, false
+	}
+	min := ssps[0].GetSafePoint()
+	for _, ssp := range ssps[1:] {
+		if ssp.GetSafePoint() < min {
+			min = ssp.GetSafePoint()
+		}
+	}
+	return min, true
+}
+
+// updateServiceSafePoint registers or updates the safe point of the service,
+// which expires after ttl seconds, and removes it if ttl is not positive. It
+// returns the min safe point of services after the update, and whether the
+// safe point is updated.
+func (m *gcSafePointManager) updateServiceSafePoint(serviceID string, ttl int64, safePoint uint64) (uint64, bool, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	if serviceID == "" {
+		return 0, false, errors.Wrap(ErrInvalidServiceSafePoint, "service ID should not be empty")
+	}
+	now := time.Now()
+	ssps, err := m.loadServiceSafePoints(now)
+	if err != nil {
+		return 0, false, err
+	}
+	var others []*gcpb.ServiceSafePoint
+	for _, ssp := range ssps {
+		if ssp.GetServiceId() != serviceID {
+			others = append(others, ssp)
+		}
+	}
+
+	if ttl <= 0 {
+		if err = m.kv.DeleteServiceGCSafePoint(serviceID); err != nil {
+			return 0, false, err
+		}
+		log.Infof("safe point of service %s is removed", serviceID)
+		min, _ := minServiceSafePoint(others)
+		return min, true, nil
+	}
+
+	gcSafePoint, err := m.kv.LoadGCSafePoint()
+	if err != nil {
+		return 0, false, err
+	}
+	if safePoint < gcSafePoint {
+		log.Warnf("trying to set safe point of service %s to %d, which is less than gc safe point %d", serviceID, safePoint, gcSafePoint)
+		min, ok := minServiceSafePoint(ssps)
+		if !ok {
+			min = gcSafePoint
+		}
+		return min, false, nil
+	}
+
+	// A TTL which overflows the expiration time, such as math.MaxInt64, means
+	// the safe point never expires.
+	expiredAt := int64(math.MaxInt64)
+	if ttl < math.MaxInt64-now.Unix() {
+		expiredAt = now.Unix() + ttl
+	}
+	ssp := &gcpb.ServiceSafePoint{
+		ServiceId: serviceID,
+		SafePoint: safePoint,
+		ExpiredAt: expiredAt,
+	}
+	if err = m.kv.SaveServiceGCSafePoint(serviceID, ssp); err != nil {
+		return 0, false, err
+	}
+	log.Infof("safe point of service %s is updated to %d, expired in %ds", serviceID, safePoint, ttl)
+	min, _ := minServiceSafePoint(append(others, ssp))
+	return min, true, nil
+}
+
+// listServiceSafePoints returns the safe points of unexpired services and the
+// cluster GC safe point.
+func (m *gcSafePointManager) listServiceSafePoints() ([]*gcpb.ServiceSafePoint, uint64, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	ssps, err := m.loadServiceSafePoints(time.Now())
+	if err != nil {
+		return nil, 0, err
+	}
+	gcSafePoint, err := m.kv.LoadGCSafePoint()
+	if err != nil {
+		return nil, 0, err
+	}
+	return ssps, gcSafePoint, nil
+}
+
+// updateGCSafePoint advances the cluster GC safe point, which is limited by
+// the min safe point of services. It returns the GC safe point after the
+// update.
+func (m *gcSafePointManager) updateGCSafePoint(safePoint uint64) (uint64, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	oldSafePoint, err := m.kv.LoadGCSafePoint()
+	if err != nil {
+		return 0, err
+	}
+	ssps, err := m.loadServiceSafePoints(time.Now())
+	if err != nil {
+		return 0, err
+	}
+	if min, ok := minServiceSafePoint(ssps); ok && safePoint > min {
+		log.Infof("gc safe point %d is limited to %d by service safe points", safePoint, min)
+		safePoint = min
+	}
+
+	// Only save the safe point if it's greater than the previous one
+	if safePoint > oldSafePoint {
+		if err := m.kv.SaveGCSafePoint(safePoint); err != nil {
+			return 0, err
+		}
+		log.Infof("updated gc safe point to %d", safePoint)
+	} else if safePoint < oldSafePoint {
+		log.Warnf("trying to update gc safe point from %d to %d", oldSafePoint, safePoint)
+		safePoint = oldSafePoint
+	}
+	return safePoint, nil
+}
+
+// UpdateServiceGCSafePoint registers or updates the safe point of a service,
+// and removes it if ttl is not positive. It returns the min safe point of
+// services after the update, and whether the safe point is updated.
+func (s *Server) UpdateServiceGCSafePoint(serviceID string, ttl int64, safePoint uint64) (uint64, bool, error) {
+	return s.gcSafePointManager.updateServiceSafePoint(serviceID, ttl, safePoint)
+}
+
+// GetServiceGCSafePoints returns the safe points of unexpired services and
+// the cluster GC safe point.
+func (s *Server) GetServiceGCSafePoints() ([]*gcpb.ServiceSafePoint, uint64, error) {
+	return s.gcSafePointManager.listServiceSafePoints()
+}
+
+// gcService implements gRPC GCServer.
+type gcService struct {
+	s *Server
+}
+
+// UpdateServiceSafePoint implements gRPC GCServer.
+func (g *gcService) UpdateServiceSafePoint(ctx context.Context, request *gcpb.UpdateServiceSafePointRequest) (*gcpb.UpdateServiceSafePointResponse, error) {
+	if err := g.s.validateRequest(request.GetHeader()); err != nil {
+		return nil, err
+	}
+	min, updated, err := g.s.UpdateServiceGCSafePoint(request.GetServiceId(), request.GetTtl(), request.GetSafePoint())
+	if errors.Cause(err) == ErrInvalidServiceSafePoint {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	resp := &gcpb.UpdateServiceSafePointResponse{
+		Header:       g.s.header(),
+		ServiceId:    request.GetServiceId(),
+		MinSafePoint: min,
+	}
+	if updated && request.GetTtl() > 0 {
+		resp.Ttl = request.GetTtl()
+	}
+	return resp, nil
+}
+
+// ListServiceSafePoints implements gRPC GCServer.
+func (g *gcService) ListServiceSafePoints(ctx context.Context, request *gcpb.ListServiceSafePointsRequest) (*gcpb.ListServiceSafePointsResponse, error) {
+	if err := g.s.validateRequest(request.GetHeader()); err != nil {
+		return nil, err
+	}
+	ssps, gcSafePoint, err := g.s.GetServiceGCSafePoints()
+	if err != nil {
+		return nil, err
+	}
+	min, _ := minServiceSafePoint(ssps)
+	return &gcpb.ListServiceSafePointsResponse{
+		Header:            g.s.header(),
+		ServiceSafePoints: ssps,
+		GcSafePoint:       gcSafePoint,
+		MinSafePoint:      min,
+	}, nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/pd/pkg/gcpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ = Suite(&testGCSuite{})
+
+type testGCSuite struct{}
+
+func (s *testGCSuite) TestServiceSafePoint(c *C) {
+	_, svr, cleanup, err := NewTestServer()
+	c.Assert(err, IsNil)
+	defer cleanup()
+	mustWaitLeader(c, []*Server{svr})
+
+	conn, err := grpc.Dial(strings.TrimPrefix(svr.GetAddr(), "http://"), grpc.WithInsecure())
+	c.Assert(err, IsNil)
+	defer conn.Close()
+	client := gcpb.NewGCClient(conn)
+	header := newRequestHeader(svr.clusterID)
+	ctx := context.Background()
+
+	resp, err := client.UpdateServiceSafePoint(ctx, &gcpb.UpdateServiceSafePointRequest{Header: header, ServiceId: "cdc", Ttl: 100, SafePoint: 10})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetTtl(), Equals, int64(100))
+	c.Assert(resp.GetMinSafePoint(), Equals, uint64(10))
+	resp, err = client.UpdateServiceSafePoint(ctx, &gcpb.UpdateServiceSafePointRequest{Header: header, ServiceId: "br", Ttl: 100, SafePoint: 20})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetMinSafePoint(), Equals, uint64(10))
+	_, err = client.UpdateServiceSafePoint(ctx, &gcpb.UpdateServiceSafePointRequest{Header: header, Ttl: 100, SafePoint: 20})
+	c.Assert(status.Code(err), Equals, codes.InvalidArgument)
+
+	// The GC safe point is limited by the min service safe point.
+	safePoint, err := svr.gcSafePointManager.updateGCSafePoint(15)
+	c.Assert(err, IsNil)
+	c.Assert(safePoint, Equals, uint64(10))
+	resp, err = client.UpdateServiceSafePoint(ctx, &gcpb.UpdateServiceSafePointRequest{Header: header, ServiceId: "cdc", Ttl: 100, SafePoint: 18})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetMinSafePoint(), Equals, uint64(18))
+	safePoint, err = svr.gcSafePointManager.updateGCSafePoint(25)
+	c.Assert(err, IsNil)
+	c.Assert(safePoint, Equals, uint64(18))
+
+	// A safe point less than the GC safe point is not updated.
+	resp, err = client.UpdateServiceSafePoint(ctx, &gcpb.UpdateServiceSafePointRequest{Header: header, ServiceId: "br", Ttl: 100, SafePoint: 5})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetTtl(), Equals, int64(0))
+	c.Assert(resp.GetMinSafePoint(), Equals, uint64(18))
+
+	// Expired safe points are removed.
+	err = svr.kv.SaveServiceGCSafePoint("cdc", &gcpb.ServiceSafePoint{ServiceId: "cdc", SafePoint: 18, ExpiredAt: time.Now().Unix() - 1})
+	c.Assert(err, IsNil)
+	listResp, err := client.ListServiceSafePoints(ctx, &gcpb.ListServiceSafePointsRequest{Header: header})
+	c.Assert(err, IsNil)
+	c.Assert(listResp.GetServiceSafePoints(), HasLen, 1)
+	c.Assert(listResp.GetServiceSafePoints()[0].GetServiceId(), Equals, "br")
+	c.Assert(listResp.GetGcSafePoint(), Equals, uint64(18))
+	c.Assert(listResp.GetMinSafePoint(), Equals, uint64(20))
+
+	// The safe point with the max TTL never expires.
+	_, err = client.UpdateServiceSafePoint(ctx, &gcpb.UpdateServiceSafePointRequest{Header: header, ServiceId: "cdc", Ttl: math.MaxInt64, SafePoint: 30})
+	c.Assert(err, IsNil)
+	listResp, err = client.ListServiceSafePoints(ctx, &gcpb.ListServiceSafePointsRequest{Header: header})
+	c.Assert(err, IsNil)
+	c.Assert(listResp.GetServiceSafePoints(), HasLen, 2)
+	c.Assert(listResp.GetServiceSafePoints()[1].GetExpiredAt(), Equals, int64(math.MaxInt64))
+	_, err = client.UpdateServiceSafePoint(ctx, &gcpb.UpdateServiceSafePointRequest{Header: header, ServiceId: "cdc"})
+	c.Assert(err, IsNil)
+
+	// A non-positive TTL removes the safe point.
+	resp, err = client.UpdateServiceSafePoint(ctx, &gcpb.UpdateServiceSafePointRequest{Header: header, ServiceId: "br"})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetMinSafePoint(), Equals, uint64(0))
+	safePoint, err = svr.gcSafePointManager.updateGCSafePoint(25)
+	c.Assert(err, IsNil)
+	c.Assert(safePoint, Equals, uint64(25))
+}
@@ -17,12 +17,16 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"sort"
 	"strconv"
 	"sync/atomic"
 	"time"
 
+	"github.com/opentracing/opentracing-go"
+	"github.com/pingcap/errcode"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/pkg/storepb"
 	"github.com/pingcap/pd/server/core"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -33,17 +37,55 @@ import (
 //revive:disable:unused-parameter
 
 // notLeaderError is returned when current server is not the leader and not possible to process request.
-// TODO: work as proxy.
-var notLeaderError = status.Errorf(codes.Unavailable, "not leader")
+var notLeaderError = status.Errorf(codes.Unavailable, ErrNotLeader.Error())
+
+// errorCodeStr returns the errcode string of the error, it is empty if the
+// error has no code.
+func errorCodeStr(err error) errcode.CodeStr {
+	if errCode := errcode.CodeChain(err); errCode != nil {
+		return errCode.Code().CodeStr()
+	}
+	return ""
+}
+
+// pbError converts the error to the pdpb error in the response headers.
+func pbError(err error) *pdpb.Error {
+	errType := pdpb.ErrorType_UNKNOWN
+	switch errorCodeStr(err) {
+	case core.NotBootstrappedCode.CodeStr():
+		errType = pdpb.ErrorType_NOT_BOOTSTRAPPED
+	case core.AlreadyBootstrappedCode.CodeStr():
+		errType = pdpb.ErrorType_ALREADY_BOOTSTRAPPED
+	case core.StoreTombstonedCode.CodeStr():
+		errType = pdpb.ErrorType_STORE_TOMBSTONE
+	}
+	return &pdpb.Error{Type: errType, Message: err.Error()}
+}
+
+// grpcError converts the error to a gRPC status error. The errors of not
+// leader are Unavailable, so that clients can retry with the new leader.
+func grpcError(err error) error {
+	switch errorCodeStr(err) {
+	case core.NotLeaderCode.CodeStr():
+		return status.Errorf(codes.Unavailable, err.Error())
+	case core.NotBootstrappedCode.CodeStr():
+		return status.Errorf(codes.FailedPrecondition, err.Error())
+	case core.StoreAddressConflictedCode.CodeStr(), core.StoreIDConflictedCode.CodeStr():
+		return status.Errorf(codes.AlreadyExists, err.Error())
+	case core.InvalidStoreLabelCode.CodeStr():
+		return status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	return status.Errorf(codes.Unknown, err.Error())
+}
 
 // GetMembers implements gRPC PDServer.
 func (s *Server) GetMembers(context.Context, *pdpb.GetMembersRequest) (*pdpb.GetMembersResponse, error) {
 	if s.isClosed() {
 		return nil, status.Errorf(codes.Unknown, "server not started")
 	}
-	members, err := GetMembers(s.GetClient())
+	members, err := s.ListMembers()
 	if err != nil {
-		return nil, status.Errorf(codes.Unknown, err.Error())
+		return nil, grpcError(err)
 	}
 
 	var etcdLeader *pdpb.Member
@@ -51,8 +93,18 @@ func (s *Server) GetMembers(context.Context, *pdpb.GetMembersRequest) (*pdpb.Get
 	for _, m := range members {
 		if m.MemberId == leadID {
 			etcdLeader = m
-			break
 		}
+		// Fill leader priorities.
+		if leadID == 0 {
+			log.Warnf("no etcd leader, skip get leader priority, member: %v", m.GetMemberId())
+			continue
+		}
+		leaderPriority, e := s.GetMemberLeaderPriority(m.GetMemberId())
+		if e != nil {
+			log.Errorf("failed to load leader priority, member: %v, err: %v", m.GetMemberId(), e)
+			continue
+		}
+		m.LeaderPriority = int32(leaderPriority)
 	}
 
 	return &pdpb.GetMembersResponse{
@@ -79,7 +131,7 @@ func (s *Server) Tso(stream pdpb.PD_TsoServer) error {
 		count := request.GetCount()
 		ts, err := s.getRespTS(count)
 		if err != nil {
-			return status.Errorf(codes.Unknown, err.Error())
+			return grpcError(err)
 		}
 		response := &pdpb.TsoResponse{
 			Header:    s.header(),
@@ -94,22 +146,37 @@ func (s *Server) Tso(stream pdpb.PD_TsoServer) error {
 
 // Bootstrap implements gRPC PDServer.
 func (s *Server) Bootstrap(ctx context.Context, request *pdpb.BootstrapRequest) (*pdpb.BootstrapResponse, error) {
+	if s.shouldForward(ctx) {
+		var resp *pdpb.BootstrapResponse
+		err := s.forward(ctx, "Bootstrap", func(ctx context.Context, client pdpb.PDClient) (err error) {
+			resp, err = client.Bootstrap(ctx, request)
+			return
+		})
+		return resp, err
+	}
 	if err := s.validateRequest(request.GetHeader()); err != nil {
 		return nil, err
 	}
 
-	cluster := s.GetRaftCluster()
-	if cluster != nil {
-		err := &pdpb.Error{
-			Type:    pdpb.ErrorType_ALREADY_BOOTSTRAPPED,
-			Message: "cluster is already bootstrapped",
-		}
-		return &pdpb.BootstrapResponse{
-			Header: s.errorHeader(err),
-		}, nil
+	var err error
+	if s.GetRaftCluster() != nil {
+		err = core.AlreadyBootstrappedErr{ClusterID: s.clusterID}
+	} else {
+		_, err = s.bootstrapCluster(request)
 	}
-	if _, err := s.bootstrapCluster(request); err != nil {
-		return nil, status.Errorf(codes.Unknown, err.Error())
+	if err != nil {
+		if errorCodeStr(err) != core.AlreadyBootstrappedCode.CodeStr() {
+			return nil, grpcError(err)
+		}
+		// Retries of the bootstrap which has succeeded are successful.
+		retry, e := s.isBootstrapRetry(request)
+		if e != nil {
+			return nil, grpcError(e)
+		}
+		if !retry {
+			return &pdpb.BootstrapResponse{Header: s.errorHeader(pbError(err))}, nil
+		}
+		log.Infof("cluster %d is already bootstrapped by the same request", s.clusterID)
 	}
 
 	return &pdpb.BootstrapResponse{
@@ -132,14 +199,22 @@ func (s *Server) IsBootstrapped(ctx context.Context, request *pdpb.IsBootstrappe
 
 // AllocID implements gRPC PDServer.
 func (s *Server) AllocID(ctx context.Context, request *pdpb.AllocIDRequest) (*pdpb.AllocIDResponse, error) {
+	if s.shouldForward(ctx) {
+		var resp *pdpb.AllocIDResponse
+		err := s.forward(ctx, "AllocID", func(ctx context.Context, client pdpb.PDClient) (err error) {
+			resp, err = client.AllocID(ctx, request)
+			return
+		})
+		return resp, err
+	}
 	if err := s.validateRequest(request.GetHeader()); err != nil {
 		return nil, err
 	}
 
 	// We can use an allocator for all types ID allocation.
-	id, err := s.idAlloc.Alloc()
+	id, err := s.idAlloc.allocWithContext(ctx)
 	if err != nil {
-		return nil, status.Errorf(codes.Unknown, err.Error())
+		return nil, grpcError(err)
 	}
 
 	return &pdpb.AllocIDResponse{
@@ -161,7 +236,7 @@ func (s *Server) GetStore(ctx context.Context, request *pdpb.GetStoreRequest) (*
 
 	store, err := cluster.GetStore(request.GetStoreId())
 	if err != nil {
-		return nil, status.Errorf(codes.Unknown, err.Error())
+		return nil, grpcError(err)
 	}
 	return &pdpb.GetStoreResponse{
 		Header: s.header(),
@@ -176,10 +251,7 @@ func checkStore2(cluster *RaftCluster, storeID uint64) *pdpb.Error {
 	store, err := cluster.GetStore(storeID)
 	if err == nil && store != nil {
 		if store.GetState() == metapb.StoreState_Tombstone {
-			return &pdpb.Error{
-				Type:    pdpb.ErrorType_STORE_TOMBSTONE,
-				Message: "store is tombstone",
-			}
+			return pbError(core.StoreTombstonedErr{StoreID: storeID})
 		}
 	}
 	return nil
@@ -204,7 +276,7 @@ func (s *Server) PutStore(ctx context.Context, request *pdpb.PutStoreRequest) (*
 	}
 
 	if err := cluster.putStore(store); err != nil {
-		return nil, status.Errorf(codes.Unknown, err.Error())
+		return nil, grpcError(err)
 	}
 
 	log.Infof("put store ok - %v", store)
@@ -234,8 +306,88 @@ func (s *Server) GetAllStores(ctx context.Context, request *pdpb.GetAllStoresReq
 	}, nil
 }
 
+// storeService implements gRPC StoreServer.
+type storeService struct {
+	s *Server
+}
+
+// GetAllStores implements gRPC StoreServer.
+func (ss *storeService) GetAllStores(ctx context.Context, request *storepb.GetAllStoresRequest) (*storepb.GetAllStoresResponse, error) {
+	if err := ss.s.validateRequest(request.GetHeader()); err != nil {
+		return nil, err
+	}
+
+	cluster := ss.s.GetRaftCluster()
+	if cluster == nil {
+		return &storepb.GetAllStoresResponse{Header: ss.s.notBootstrappedHeader()}, nil
+	}
+
+	stores := cluster.GetStoreInfos()
+	sort.Slice(stores, func(i, j int) bool { return stores[i].GetId() < stores[j].GetId() })
+	opt := ss.s.scheduleOpt
+	infos := make([]*storepb.StoreInfo, 0, len(stores))
+	for _, store := range stores {
+		if request.GetExcludeTombstoneStores() && store.IsTombstone() {
+			continue
+		}
+		info := &storepb.StoreInfo{Store: store.Store}
+		if request.GetWithStats() {
+			info.Stats = store.Stats
+			if !store.LastHeartbeatTS.IsZero() {
+				info.LastHeartbeat = store.LastHeartbeatTS.UnixNano()
+			}
+			info.LeaderScore = store.LeaderScore(0)
+			info.RegionScore = store.RegionScore(opt.GetHighSpaceRatio(), opt.GetLowSpaceRatio(), 0)
+		}
+		infos = append(infos, info)
+	}
+	return &storepb.GetAllStoresResponse{
+		Header: ss.s.header(),
+		Stores: infos,
+	}, nil
+}
+
+// ReportDiskStats implements gRPC StoreServer.
+func (ss *storeService) ReportDiskStats(ctx context.Context, request *storepb.ReportDiskStatsRequest) (*storepb.ReportDiskStatsResponse, error) {
+	if err := ss.s.validateRequest(request.GetHeader()); err != nil {
+		return nil, err
+	}
+
+	if request.GetStats() == nil {
+		return nil, errors.Errorf("invalid disk stats report, but %v", request)
+	}
+	cluster := ss.s.GetRaftCluster()
+	if cluster == nil {
+		return &storepb.ReportDiskStatsResponse{Header: ss.s.notBootstrappedHeader()}, nil
+	}
+
+	if pberr := checkStore2(cluster, request.GetStats().GetStoreId()); pberr != nil {
+		return &storepb.ReportDiskStatsResponse{
+			Header: ss.s.errorHeader(pberr),
+		}, nil
+	}
+
+	cluster.RLock()
+	defer cluster.RUnlock()
+	if err := cluster.cachedCluster.handleStoreDiskStats(request.GetStats()); err != nil {
+		return nil, grpcError(err)
+	}
+
+	return &storepb.ReportDiskStatsResponse{
+		Header: ss.s.header(),
+	}, nil
+}
+
 // StoreHeartbeat implements gRPC PDServer.
 func (s *Server) StoreHeartbeat(ctx context.Context, request *pdpb.StoreHeartbeatRequest) (*pdpb.StoreHeartbeatResponse, error) {
+	if s.shouldForward(ctx) {
+		var resp *pdpb.StoreHeartbeatResponse
+		err := s.forward(ctx, "StoreHeartbeat", func(ctx context.Context, client pdpb.PDClient) (err error) {
+			resp, err = client.StoreHeartbeat(ctx, request)
+			return
+		})
+		return resp, err
+	}
 	if err := s.validateRequest(request.GetHeader()); err != nil {
 		return nil, err
 	}
@@ -258,7 +410,7 @@ func (s *Server) StoreHeartbeat(ctx context.Context, request *pdpb.StoreHeartbea
 	defer cluster.RUnlock()
 	err := cluster.cachedCluster.handleStoreHeartbeat(request.Stats)
 	if err != nil {
-		return nil, status.Errorf(codes.Unknown, err.Error())
+		return nil, grpcError(err)
 	}
 
 	return &pdpb.StoreHeartbeatResponse{
@@ -361,11 +513,21 @@ func (s *Server) RegionHeartbeat(stream pdpb.PD_RegionHeartbeatServer) error {
 			continue
 		}
 
-		err = cluster.HandleRegionHeartbeat(region)
+		span := opentracing.StartSpan("pd.RegionHeartbeat")
+		span.SetTag("region_id", region.GetID())
+		span.SetTag("store_id", storeID)
+		if cluster.dispatchRegionHeartbeat(region, storeLabel, span) {
+			regionHeartbeatCounter.WithLabelValues(storeLabel, "report", "ok").Inc()
+			continue
+		}
+		err = cluster.HandleRegionHeartbeat(opentracing.ContextWithSpan(stream.Context(), span), region)
 		if err != nil {
 			msg := err.Error()
 			hbStreams.sendErr(region, pdpb.ErrorType_UNKNOWN, msg, storeLabel)
+			span.SetTag("error", true)
+			span.LogKV("error", msg)
 		}
+		span.Finish()
 
 		regionHeartbeatCounter.WithLabelValues(storeLabel, "report", "ok").Inc()
 	}
@@ -381,12 +543,7 @@ func (s *Server) GetRegion(ctx context.Context, request *pdpb.GetRegionRequest)
 	if cluster == nil {
 		return &pdpb.GetRegionResponse{Header: s.notBootstrappedHeader()}, nil
 	}
-	region, leader := cluster.GetRegionByKey(request.GetRegionKey())
-	return &pdpb.GetRegionResponse{
-		Header: s.header(),
-		Region: region,
-		Leader: leader,
-	}, nil
+	return s.regionResponse(cluster.GetRegionInfoByKey(request.GetRegionKey()))
 }
 
 // GetPrevRegion implements gRPC PDServer
@@ -400,12 +557,7 @@ func (s *Server) GetPrevRegion(ctx context.Context, request *pdpb.GetRegionReque
 		return &pdpb.GetRegionResponse{Header: s.notBootstrappedHeader()}, nil
 	}
 
-	region, leader := cluster.GetPrevRegionByKey(request.GetRegionKey())
-	return &pdpb.GetRegionResponse{
-		Header: s.header(),
-		Region: region,
-		Leader: leader,
-	}, nil
+	return s.regionResponse(cluster.GetPrevRegionInfoByKey(request.GetRegionKey()))
 }
 
 // GetRegionByID implements gRPC PDServer.
@@ -418,13 +570,20 @@ func (s *Server) GetRegionByID(ctx context.Context, request *pdpb.GetRegionByIDR
 	if cluster == nil {
 		return &pdpb.GetRegionResponse{Header: s.notBootstrappedHeader()}, nil
 	}
-	id := request.GetRegionId()
-	region, leader := cluster.GetRegionByID(id)
-	return &pdpb.GetRegionResponse{
-		Header: s.header(),
-		Region: region,
-		Leader: leader,
-	}, nil
+	return s.regionResponse(cluster.GetRegionInfoByID(request.GetRegionId()))
+}
+
+// regionResponse builds the response of the region, nil region means it is not
+// found. GetRegionResponse of the vendored pdpb has no down and pending peers,
+// which are served by the region service.
+func (s *Server) regionResponse(region *core.RegionInfo) (*pdpb.GetRegionResponse, error) {
+	resp := &pdpb.GetRegionResponse{Header: s.header()}
+	if region == nil {
+		return resp, nil
+	}
+	resp.Region = region.GetMeta()
+	resp.Leader = region.GetLeader()
+	return resp, nil
 }
 
 // AskSplit implements gRPC PDServer.
@@ -445,7 +604,7 @@ func (s *Server) AskSplit(ctx context.Context, request *pdpb.AskSplitRequest) (*
 	}
 	split, err := cluster.handleAskSplit(req)
 	if err != nil {
-		return nil, status.Errorf(codes.Unknown, err.Error())
+		return nil, grpcError(err)
 	}
 
 	return &pdpb.AskSplitResponse{
@@ -480,7 +639,7 @@ func (s *Server) AskBatchSplit(ctx context.Context, request *pdpb.AskBatchSplitR
 	}
 	split, err := cluster.handleAskBatchSplit(req)
 	if err != nil {
-		return nil, status.Errorf(codes.Unknown, err.Error())
+		return nil, grpcError(err)
 	}
 
 	return &pdpb.AskBatchSplitResponse{
@@ -501,7 +660,7 @@ func (s *Server) ReportSplit(ctx context.Context, request *pdpb.ReportSplitReque
 	}
 	_, err := cluster.handleReportSplit(request)
 	if err != nil {
-		return nil, status.Errorf(codes.Unknown, err.Error())
+		return nil, grpcError(err)
 	}
 
 	return &pdpb.ReportSplitResponse{
@@ -522,7 +681,7 @@ func (s *Server) ReportBatchSplit(ctx context.Context, request *pdpb.ReportBatch
 
 	_, err := cluster.handleBatchReportSplit(request)
 	if err != nil {
-		return nil, status.Errorf(codes.Unknown, err.Error())
+		return nil, grpcError(err)
 	}
 
 	return &pdpb.ReportBatchSplitResponse{
@@ -551,6 +710,9 @@ func (s *Server) PutClusterConfig(ctx context.Context, request *pdpb.PutClusterC
 	if err := s.validateRequest(request.GetHeader()); err != nil {
 		return nil, err
 	}
+	if err := s.checkMaintenance(); err != nil {
+		return nil, err
+	}
 
 	cluster := s.GetRaftCluster()
 	if cluster == nil {
@@ -558,7 +720,7 @@ func (s *Server) PutClusterConfig(ctx context.Context, request *pdpb.PutClusterC
 	}
 	conf := request.GetCluster()
 	if err := cluster.putConfig(conf); err != nil {
-		return nil, status.Errorf(codes.Unknown, err.Error())
+		return nil, grpcError(err)
 	}
 
 	log.Infof("put cluster config ok - %v", conf)
@@ -573,6 +735,9 @@ func (s *Server) ScatterRegion(ctx context.Context, request *pdpb.ScatterRegionR
 	if err := s.validateRequest(request.GetHeader()); err != nil {
 		return nil, err
 	}
+	if err := s.checkMaintenance(); err != nil {
+		return nil, err
+	}
 
 	cluster := s.GetRaftCluster()
 	if cluster == nil {
@@ -589,6 +754,9 @@ func (s *Server) ScatterRegion(ctx context.Context, request *pdpb.ScatterRegionR
 	cluster.RLock()
 	defer cluster.RUnlock()
 	co := cluster.coordinator
+	if err := co.quotas.allowOperator(region); err != nil {
+		return nil, err
+	}
 	if op := co.regionScatterer.Scatter(region); op != nil {
 		co.opController.AddOperator(op)
 	}
@@ -609,7 +777,7 @@ func (s *Server) GetGCSafePoint(ctx context.Context, request *pdpb.GetGCSafePoin
 		return &pdpb.GetGCSafePointResponse{Header: s.notBootstrappedHeader()}, nil
 	}
 
-	safePoint, err := s.kv.LoadGCSafePoint()
+	safePoint, err := s.kv.WithContext(ctx).LoadGCSafePoint()
 	if err != nil {
 		return nil, err
 	}
@@ -651,24 +819,11 @@ func (s *Server) UpdateGCSafePoint(ctx context.Context, request *pdpb.UpdateGCSa
 		return &pdpb.UpdateGCSafePointResponse{Header: s.notBootstrappedHeader()}, nil
 	}
 
-	oldSafePoint, err := s.kv.LoadGCSafePoint()
+	newSafePoint, err := s.gcSafePointManager.updateGCSafePoint(request.GetSafePoint())
 	if err != nil {
 		return nil, err
 	}
 
-	newSafePoint := request.SafePoint
-
-	// Only save the safe point if it's greater than the previous one
-	if newSafePoint > oldSafePoint {
-		if err := s.kv.SaveGCSafePoint(newSafePoint); err != nil {
-			return nil, err
-		}
-		log.Infof("updated gc safe point to %d", newSafePoint)
-	} else if newSafePoint < oldSafePoint {
-		log.Warnf("trying to update gc safe point from %d to %d", oldSafePoint, newSafePoint)
-		newSafePoint = oldSafePoint
-	}
-
 	return &pdpb.UpdateGCSafePointResponse{
 		Header:       s.header(),
 		NewSafePoint: newSafePoint,
@@ -679,7 +834,7 @@ func (s *Server) UpdateGCSafePoint(ctx context.Context, request *pdpb.UpdateGCSa
 // TODO: Call it in gRPC intercepter.
 func (s *Server) validateRequest(header *pdpb.RequestHeader) error {
 	if !s.IsLeader() {
-		return errors.WithStack(notLeaderError)
+		return notLeaderError
 	}
 	if header.GetClusterId() != s.clusterID {
 		return status.Errorf(codes.FailedPrecondition, "mismatch cluster id, need %d but got %d", s.clusterID, header.GetClusterId())
@@ -687,6 +842,16 @@ func (s *Server) validateRequest(header *pdpb.RequestHeader) error {
 	return nil
 }
 
+// checkMaintenance rejects the mutating admin requests if the cluster is in
+// the maintenance mode. They are FailedPrecondition rather than Unavailable,
+// so that clients do not retry them.
+func (s *Server) checkMaintenance() error {
+	if err := s.maintenance.check(); err != nil {
+		return status.Errorf(codes.FailedPrecondition, err.Error())
+	}
+	return nil
+}
+
 func (s *Server) header() *pdpb.ResponseHeader {
 	return &pdpb.ResponseHeader{ClusterId: s.clusterID}
 }
@@ -699,10 +864,7 @@ func (s *Server) errorHeader(err *pdpb.Error) *pdpb.ResponseHeader {
 }
 
 func (s *Server) notBootstrappedHeader() *pdpb.ResponseHeader {
-	return s.errorHeader(&pdpb.Error{
-		Type:    pdpb.ErrorType_NOT_BOOTSTRAPPED,
-		Message: "cluster is not bootstrapped",
-	})
+	return s.errorHeader(pbError(ErrNotBootstrapped))
 }
 
 func (s *Server) incompatibleVersion(tag string) *pdpb.ResponseHeader {
@@ -0,0 +1,50 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ = Suite(&testGRPCErrorSuite{})
+
+type testGRPCErrorSuite struct{}
+
+func (s *testGRPCErrorSuite) TestErrorConversion(c *C) {
+	notLeader := errors.Wrap(ErrNotLeader, "generate id failed")
+	c.Assert(notLeader.Error(), Equals, "generate id failed: not leader")
+	c.Assert(errors.Cause(notLeader), Equals, ErrNotLeader)
+	c.Assert(status.Code(grpcError(notLeader)), Equals, codes.Unavailable)
+	c.Assert(pbError(notLeader).GetType(), Equals, pdpb.ErrorType_UNKNOWN)
+
+	notBootstrapped := errors.WithStack(ErrNotBootstrapped)
+	c.Assert(status.Code(grpcError(notBootstrapped)), Equals, codes.FailedPrecondition)
+	c.Assert(pbError(notBootstrapped).GetType(), Equals, pdpb.ErrorType_NOT_BOOTSTRAPPED)
+
+	bootstrapped := errors.WithStack(core.AlreadyBootstrappedErr{ClusterID: 1})
+	c.Assert(pbError(bootstrapped), DeepEquals, &pdpb.Error{
+		Type:    pdpb.ErrorType_ALREADY_BOOTSTRAPPED,
+		Message: "cluster 1 already bootstrapped",
+	})
+	c.Assert(pbError(core.StoreTombstonedErr{StoreID: 1}).GetType(), Equals, pdpb.ErrorType_STORE_TOMBSTONE)
+
+	unknown := errors.New("unknown")
+	c.Assert(status.Code(grpcError(unknown)), Equals, codes.Unknown)
+	c.Assert(pbError(unknown).GetType(), Equals, pdpb.ErrorType_UNKNOWN)
+}
@@ -15,6 +15,7 @@ package server
 
 import (
 	"bytes"
+	"fmt"
 	"strconv"
 	"strings"
 	"time"
@@ -23,6 +24,7 @@ import (
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/namespace"
 	"github.com/pingcap/pd/server/schedule"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -30,7 +32,10 @@ import (
 
 var (
 	// ErrNotBootstrapped is error info for cluster not bootstrapped
-	ErrNotBootstrapped = errors.New("TiKV cluster not bootstrapped, please start TiKV first")
+	ErrNotBootstrapped error = core.NotBootstrappedErr{}
+	// ErrNotLeader is error info for the operations which can only be done by
+	// the leader
+	ErrNotLeader error = core.NotLeaderErr{}
 	// ErrOperatorNotFound is error info for operator not found
 	ErrOperatorNotFound = errors.New("operator not found")
 	// ErrAddOperator is error info for already have an operator when adding operator
@@ -49,6 +54,18 @@ var (
 	ErrRegionIsStale = func(region *metapb.Region, origin *metapb.Region) error {
 		return errors.Errorf("region is stale: region %v origin %v", region, origin)
 	}
+	// ErrStoreLabelNotFound is error info for a label that no store has
+	ErrStoreLabelNotFound = errors.New("no store has the label")
+	// ErrInvalidMergeProtectionRule is error info for a merge protection rule
+	// with invalid fields
+	ErrInvalidMergeProtectionRule = errors.New("invalid merge protection rule")
+	// ErrMergeProtectionRuleNotFound is error info for a merge protection rule
+	// that does not exist
+	ErrMergeProtectionRuleNotFound = errors.New("merge protection rule not found")
+	// ErrFeatureNotSupported is error info for feature not supported by cluster version
+	ErrFeatureNotSupported = func(f Feature) error {
+		return errors.Errorf("feature %s is not supported by current cluster version", f)
+	}
 )
 
 // Handler is a helper to export methods to handle API/RPC requests.
@@ -80,22 +97,81 @@ func (h *Handler) GetSchedulers() ([]string, error) {
 	return c.getSchedulers(), nil
 }
 
+// GetPrepareStatus returns the progress of collecting region heartbeats,
+// which the coordinator waits for before scheduling.
+func (h *Handler) GetPrepareStatus() (*PrepareStatus, error) {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return nil, err
+	}
+	return c.cluster.prepareStatus(), nil
+}
+
+// GetQuotaUsage returns the quota usage of all namespaces.
+func (h *Handler) GetQuotaUsage() ([]*NamespaceQuotaUsage, error) {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return nil, err
+	}
+	return c.quotas.getUsage(c.classifier.GetAllNamespaces()), nil
+}
+
+// GetNamespaceQuotaUsage returns the quota usage of a namespace.
+func (h *Handler) GetNamespaceQuotaUsage(name string) (*NamespaceQuotaUsage, error) {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return nil, err
+	}
+	if name != namespace.DefaultNamespace && !c.classifier.IsNamespaceExist(name) {
+		return nil, errcode.NewNotFoundErr(errors.Errorf("namespace %s not found", name))
+	}
+	return c.quotas.getUsage([]string{name})[0], nil
+}
+
+// ForcePrepare starts scheduling without waiting for the regions to be
+// reported. It is used when the regions loaded from the storage are wrong,
+// for example after recovering from metadata loss.
+func (h *Handler) ForcePrepare() error {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return err
+	}
+	if !c.cluster.isPrepared() {
+		log.Warnf("scheduling is forced to start with region load progress %.2f%%", c.cluster.prepareProgress()*100)
+		c.cluster.forcePrepare()
+	}
+	return nil
+}
+
 // GetStores returns all stores in the cluster.
 func (h *Handler) GetStores() ([]*core.StoreInfo, error) {
 	cluster := h.s.GetRaftCluster()
 	if cluster == nil {
 		return nil, errors.WithStack(ErrNotBootstrapped)
 	}
-	storeMetas := cluster.GetStores()
-	stores := make([]*core.StoreInfo, 0, len(storeMetas))
-	for _, s := range storeMetas {
-		store, err := cluster.GetStore(s.GetId())
-		if err != nil {
-			return nil, err
-		}
-		stores = append(stores, store)
+	return cluster.GetStoreInfos(), nil
+}
+
+// GetRegion returns the region by ID.
+func (h *Handler) GetRegion(regionID uint64) (*core.RegionInfo, error) {
+	cluster := h.s.GetRaftCluster()
+	if cluster == nil {
+		return nil, errors.WithStack(ErrNotBootstrapped)
+	}
+	region := cluster.GetRegionInfoByID(regionID)
+	if region == nil {
+		return nil, errcode.NewNotFoundErr(ErrRegionNotFound(regionID))
+	}
+	return region, nil
+}
+
+// ScanRegions returns at most limit regions from the start key.
+func (h *Handler) ScanRegions(startKey []byte, limit int) ([]*core.RegionInfo, error) {
+	cluster := h.s.GetRaftCluster()
+	if cluster == nil {
+		return nil, errors.WithStack(ErrNotBootstrapped)
 	}
-	return stores, nil
+	return cluster.ScanRegionsByKey(startKey, limit), nil
 }
 
 // GetHotWriteRegions gets all hot write regions stats.
@@ -193,6 +269,46 @@ func (h *Handler) RemoveScheduler(name string) error {
 	return err
 }
 
+// GetSchedulerConfig returns the config of a running scheduler.
+func (h *Handler) GetSchedulerConfig(name string) (interface{}, error) {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := c.getSchedulerConfig(name)
+	return cfg, schedulerConfigErr(err)
+}
+
+// SetSchedulerConfig updates the config of a running scheduler with the JSON
+// encoded data and persists it. It returns the updated config.
+func (h *Handler) SetSchedulerConfig(name string, data []byte) (interface{}, error) {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := c.setSchedulerConfig(name, data)
+	if err != nil {
+		log.Errorf("can not set the config of scheduler %v: %v", name, err)
+		return nil, schedulerConfigErr(err)
+	}
+	if err = h.opt.persist(c.cluster.kv); err != nil {
+		log.Errorf("can not persist scheduler config: %v", err)
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func schedulerConfigErr(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case err == errSchedulerNotFound:
+		return errcode.NewNotFoundErr(err)
+	default:
+		return errcode.NewInvalidInputErr(err)
+	}
+}
+
 // AddBalanceLeaderScheduler adds a balance-leader-scheduler.
 func (h *Handler) AddBalanceLeaderScheduler() error {
 	return h.AddScheduler("balance-leader")
@@ -234,18 +350,18 @@ func (h *Handler) AddEvictLeaderScheduler(storeID uint64) error {
 }
 
 // AddShuffleLeaderScheduler adds a shuffle-leader-scheduler.
-func (h *Handler) AddShuffleLeaderScheduler() error {
-	return h.AddScheduler("shuffle-leader")
+func (h *Handler) AddShuffleLeaderScheduler(args ...string) error {
+	return h.AddScheduler("shuffle-leader", args...)
 }
 
 // AddShuffleRegionScheduler adds a shuffle-region-scheduler.
-func (h *Handler) AddShuffleRegionScheduler() error {
-	return h.AddScheduler("shuffle-region")
+func (h *Handler) AddShuffleRegionScheduler(args ...string) error {
+	return h.AddScheduler("shuffle-region", args...)
 }
 
 // AddRandomMergeScheduler adds a random-merge-scheduler.
-func (h *Handler) AddRandomMergeScheduler() error {
-	return h.AddScheduler("random-merge")
+func (h *Handler) AddRandomMergeScheduler(args ...string) error {
+	return h.AddScheduler("random-merge", args...)
 }
 
 // GetOperator returns the region operator.
@@ -329,6 +445,15 @@ func (h *Handler) GetHistory(start time.Time) ([]schedule.OperatorHistory, error
 
 var errAddOperator = errors.New("failed to add operator, maybe already have one")
 
+// allocAdminPeer allocates a peer on the store for admin operators. The peer
+// is not allocated in dry-run mode, and its ID is 0.
+func allocAdminPeer(c *coordinator, storeID uint64, dryRun bool) (*metapb.Peer, error) {
+	if dryRun {
+		return &metapb.Peer{StoreId: storeID}, nil
+	}
+	return c.cluster.AllocPeer(storeID)
+}
+
 // AddTransferLeaderOperator adds an operator to transfer leader to the store.
 func (h *Handler) AddTransferLeaderOperator(regionID uint64, storeID uint64) error {
 	c, err := h.getCoordinator()
@@ -336,23 +461,105 @@ func (h *Handler) AddTransferLeaderOperator(regionID uint64, storeID uint64) err
 		return err
 	}
 
+	op, err := createTransferLeaderOperator(c, regionID, storeID)
+	if err != nil {
+		return err
+	}
+	if ok := c.opController.AddOperator(op); !ok {
+		return errors.WithStack(errAddOperator)
+	}
+	return nil
+}
+
+// DryRunTransferLeaderOperator creates an operator to transfer leader to the
+// store without adding it, and estimates its impact.
+func (h *Handler) DryRunTransferLeaderOperator(regionID uint64, storeID uint64) (*OperatorDryRun, error) {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := createTransferLeaderOperator(c, regionID, storeID)
+	if err != nil {
+		return nil, err
+	}
+	return c.dryRunOperator(op), nil
+}
+
+func createTransferLeaderOperator(c *coordinator, regionID uint64, storeID uint64) (*schedule.Operator, error) {
 	region := c.cluster.GetRegion(regionID)
 	if region == nil {
-		return ErrRegionNotFound(regionID)
+		return nil, ErrRegionNotFound(regionID)
 	}
 	newLeader := region.GetStoreVoter(storeID)
 	if newLeader == nil {
-		return errors.Errorf("region has no voter in store %v", storeID)
+		return nil, errors.Errorf("region has no voter in store %v", storeID)
+	}
+	if store := c.cluster.GetStore(storeID); store != nil && schedule.NewRejectLeaderFilter().FilterTarget(c.cluster, store) {
+		return nil, errors.Errorf("store %v rejects leaders by label property", storeID)
 	}
 
 	step := schedule.TransferLeader{FromStore: region.GetLeader().GetStoreId(), ToStore: newLeader.GetStoreId()}
-	op := schedule.NewOperator("adminTransferLeader", regionID, region.GetRegionEpoch(), schedule.OpAdmin|schedule.OpLeader, step)
+	return schedule.NewOperator("adminTransferLeader", regionID, region.GetRegionEpoch(), schedule.OpAdmin|schedule.OpLeader, step), nil
+}
+
+// AddAutoTransferLeaderOperator adds an operator to transfer leader to the
+// follower picked by PD.
+func (h *Handler) AddAutoTransferLeaderOperator(regionID uint64) error {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return err
+	}
+
+	op, err := createAutoTransferLeaderOperator(c, regionID)
+	if err != nil {
+		return err
+	}
 	if ok := c.opController.AddOperator(op); !ok {
 		return errors.WithStack(errAddOperator)
 	}
 	return nil
 }
 
+// DryRunAutoTransferLeaderOperator creates an operator to transfer leader to
+// the follower picked by PD without adding it, and estimates its impact.
+func (h *Handler) DryRunAutoTransferLeaderOperator(regionID uint64) (*OperatorDryRun, error) {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := createAutoTransferLeaderOperator(c, regionID)
+	if err != nil {
+		return nil, err
+	}
+	return c.dryRunOperator(op), nil
+}
+
+// createAutoTransferLeaderOperator transfers leader to the follower with the
+// lowest leader score, as the balance-leader scheduler does. The followers on
+// the stores which are busy, disconnected, blocked or rejecting leaders, and
+// the down or pending followers are skipped.
+func createAutoTransferLeaderOperator(c *coordinator, regionID uint64) (*schedule.Operator, error) {
+	region := c.cluster.GetRegion(regionID)
+	if region == nil {
+		return nil, ErrRegionNotFound(regionID)
+	}
+	excluded := make(map[uint64]struct{})
+	for _, p := range region.GetDownPeers() {
+		excluded[p.GetPeer().GetStoreId()] = struct{}{}
+	}
+	for _, p := range region.GetPendingPeers() {
+		excluded[p.GetStoreId()] = struct{}{}
+	}
+	selector := schedule.NewBalanceSelector(core.LeaderKind, []schedule.Filter{schedule.StoreStateFilter{TransferLeader: true}})
+	target := selector.SelectTarget(c.cluster, c.cluster.GetFollowerStores(region), schedule.NewExcludedFilter(nil, excluded))
+	if target == nil {
+		return nil, errors.Errorf("region %v has no follower to transfer leader to", regionID)
+	}
+	return createTransferLeaderOperator(c, regionID, target.GetId())
+}
+
 // AddTransferRegionOperator adds an operator to transfer region to the stores.
 func (h *Handler) AddTransferRegionOperator(regionID uint64, storeIDs map[uint64]struct{}) error {
 	c, err := h.getCoordinator()
@@ -360,9 +567,35 @@ func (h *Handler) AddTransferRegionOperator(regionID uint64, storeIDs map[uint64
 		return err
 	}
 
+	op, err := createTransferRegionOperator(c, regionID, storeIDs, false)
+	if err != nil {
+		return err
+	}
+	if ok := c.opController.AddOperator(op); !ok {
+		return errors.WithStack(errAddOperator)
+	}
+	return nil
+}
+
+// DryRunTransferRegionOperator creates an operator to transfer region to the
+// stores without adding it, and estimates its impact.
+func (h *Handler) DryRunTransferRegionOperator(regionID uint64, storeIDs map[uint64]struct{}) (*OperatorDryRun, error) {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := createTransferRegionOperator(c, regionID, storeIDs, true)
+	if err != nil {
+		return nil, err
+	}
+	return c.dryRunOperator(op), nil
+}
+
+func createTransferRegionOperator(c *coordinator, regionID uint64, storeIDs map[uint64]struct{}, dryRun bool) (*schedule.Operator, error) {
 	region := c.cluster.GetRegion(regionID)
 	if region == nil {
-		return ErrRegionNotFound(regionID)
+		return nil, ErrRegionNotFound(regionID)
 	}
 
 	var steps []schedule.OperatorStep
@@ -371,17 +604,17 @@ func (h *Handler) AddTransferRegionOperator(regionID uint64, storeIDs map[uint64
 	for id := range storeIDs {
 		store := c.cluster.GetStore(id)
 		if store == nil {
-			return core.NewStoreNotFoundErr(id)
+			return nil, core.NewStoreNotFoundErr(id)
 		}
 		if region.GetStorePeer(id) != nil {
 			continue
 		}
 		if store.IsTombstone() {
-			return errcode.Op("operator.add").AddTo(core.StoreTombstonedErr{StoreID: id})
+			return nil, errcode.Op("operator.add").AddTo(core.StoreTombstonedErr{StoreID: id})
 		}
-		peer, err := c.cluster.AllocPeer(id)
+		peer, err := allocAdminPeer(c, id, dryRun)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if c.cluster.IsRaftLearnerEnabled() {
 			steps = append(steps,
@@ -401,11 +634,7 @@ func (h *Handler) AddTransferRegionOperator(regionID uint64, storeIDs map[uint64
 		steps = append(steps, schedule.RemovePeer{FromStore: peer.GetStoreId()})
 	}
 
-	op := schedule.NewOperator("adminMoveRegion", regionID, region.GetRegionEpoch(), schedule.OpAdmin|schedule.OpRegion, steps...)
-	if ok := c.opController.AddOperator(op); !ok {
-		return errors.WithStack(errAddOperator)
-	}
-	return nil
+	return schedule.NewOperator("adminMoveRegion", regionID, region.GetRegionEpoch(), schedule.OpAdmin|schedule.OpRegion, steps...), nil
 }
 
 // AddTransferPeerOperator adds an operator to transfer peer.
@@ -415,62 +644,95 @@ func (h *Handler) AddTransferPeerOperator(regionID uint64, fromStoreID, toStoreI
 		return err
 	}
 
+	op, err := createTransferPeerOperator(c, regionID, fromStoreID, toStoreID)
+	if err != nil {
+		return err
+	}
+	if ok := c.opController.AddOperator(op); !ok {
+		return errors.WithStack(errAddOperator)
+	}
+	return nil
+}
+
+func createTransferPeerOperator(c *coordinator, regionID uint64, fromStoreID, toStoreID uint64) (*schedule.Operator, error) {
 	region := c.cluster.GetRegion(regionID)
 	if region == nil {
-		return ErrRegionNotFound(regionID)
+		return nil, ErrRegionNotFound(regionID)
 	}
 
 	oldPeer := region.GetStorePeer(fromStoreID)
 	if oldPeer == nil {
-		return errors.Errorf("region has no peer in store %v", fromStoreID)
+		return nil, errors.Errorf("region has no peer in store %v", fromStoreID)
 	}
 
 	toStore := c.cluster.GetStore(toStoreID)
 	if toStore == nil {
-		return core.NewStoreNotFoundErr(toStoreID)
+		return nil, core.NewStoreNotFoundErr(toStoreID)
 	}
 	if toStore.IsTombstone() {
-		return errcode.Op("operator.add").AddTo(core.StoreTombstonedErr{StoreID: toStoreID})
+		return nil, errcode.Op("operator.add").AddTo(core.StoreTombstonedErr{StoreID: toStoreID})
 	}
 
 	newPeer, err := c.cluster.AllocPeer(toStoreID)
+	if err != nil {
+		return nil, err
+	}
+
+	return schedule.CreateMovePeerOperator("adminMovePeer", c.cluster, region, schedule.OpAdmin, fromStoreID, toStoreID, newPeer.GetId()), nil
+}
+
+// AddAddPeerOperator adds an operator to add peer.
+func (h *Handler) AddAddPeerOperator(regionID uint64, toStoreID uint64) error {
+	c, err := h.getCoordinator()
 	if err != nil {
 		return err
 	}
 
-	op := schedule.CreateMovePeerOperator("adminMovePeer", c.cluster, region, schedule.OpAdmin, fromStoreID, toStoreID, newPeer.GetId())
+	op, err := createAddPeerOperator(c, regionID, toStoreID, false)
+	if err != nil {
+		return err
+	}
 	if ok := c.opController.AddOperator(op); !ok {
 		return errors.WithStack(errAddOperator)
 	}
 	return nil
 }
 
-// AddAddPeerOperator adds an operator to add peer.
-func (h *Handler) AddAddPeerOperator(regionID uint64, toStoreID uint64) error {
+// DryRunAddPeerOperator creates an operator to add peer without adding it, and
+// estimates its impact.
+func (h *Handler) DryRunAddPeerOperator(regionID uint64, toStoreID uint64) (*OperatorDryRun, error) {
 	c, err := h.getCoordinator()
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	op, err := createAddPeerOperator(c, regionID, toStoreID, true)
+	if err != nil {
+		return nil, err
 	}
+	return c.dryRunOperator(op), nil
+}
 
+func createAddPeerOperator(c *coordinator, regionID uint64, toStoreID uint64, dryRun bool) (*schedule.Operator, error) {
 	region := c.cluster.GetRegion(regionID)
 	if region == nil {
-		return ErrRegionNotFound(regionID)
+		return nil, ErrRegionNotFound(regionID)
 	}
 
 	if region.GetStorePeer(toStoreID) != nil {
-		return errors.Errorf("region already has peer in store %v", toStoreID)
+		return nil, errors.Errorf("region already has peer in store %v", toStoreID)
 	}
 
 	toStore := c.cluster.GetStore(toStoreID)
 	if toStore == nil {
-		return core.NewStoreNotFoundErr(toStoreID)
+		return nil, core.NewStoreNotFoundErr(toStoreID)
 	}
 	if toStore.IsTombstone() {
-		return errcode.Op("operator.add").AddTo(core.StoreTombstonedErr{StoreID: toStoreID})
+		return nil, errcode.Op("operator.add").AddTo(core.StoreTombstonedErr{StoreID: toStoreID})
 	}
-	newPeer, err := c.cluster.AllocPeer(toStoreID)
+	newPeer, err := allocAdminPeer(c, toStoreID, dryRun)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var steps []schedule.OperatorStep
@@ -484,11 +746,7 @@ func (h *Handler) AddAddPeerOperator(regionID uint64, toStoreID uint64) error {
 			schedule.AddPeer{ToStore: toStoreID, PeerID: newPeer.GetId()},
 		}
 	}
-	op := schedule.NewOperator("adminAddPeer", regionID, region.GetRegionEpoch(), schedule.OpAdmin|schedule.OpRegion, steps...)
-	if ok := c.opController.AddOperator(op); !ok {
-		return errors.WithStack(errAddOperator)
-	}
-	return nil
+	return schedule.NewOperator("adminAddPeer", regionID, region.GetRegionEpoch(), schedule.OpAdmin|schedule.OpRegion, steps...), nil
 }
 
 // AddRemovePeerOperator adds an operator to remove peer.
@@ -498,20 +756,125 @@ func (h *Handler) AddRemovePeerOperator(regionID uint64, fromStoreID uint64) err
 		return err
 	}
 
+	op, err := createRemovePeerOperator(c, regionID, fromStoreID)
+	if err != nil {
+		return err
+	}
+	if ok := c.opController.AddOperator(op); !ok {
+		return errors.WithStack(errAddOperator)
+	}
+	return nil
+}
+
+func createRemovePeerOperator(c *coordinator, regionID uint64, fromStoreID uint64) (*schedule.Operator, error) {
 	region := c.cluster.GetRegion(regionID)
 	if region == nil {
-		return ErrRegionNotFound(regionID)
+		return nil, ErrRegionNotFound(regionID)
 	}
 
 	if region.GetStorePeer(fromStoreID) == nil {
-		return errors.Errorf("region has no peer in store %v", fromStoreID)
+		return nil, errors.Errorf("region has no peer in store %v", fromStoreID)
 	}
 
-	op := schedule.CreateRemovePeerOperator("adminRemovePeer", c.cluster, schedule.OpAdmin, region, fromStoreID)
-	if ok := c.opController.AddOperator(op); !ok {
-		return errors.WithStack(errAddOperator)
+	return schedule.CreateRemovePeerOperator("adminRemovePeer", c.cluster, schedule.OpAdmin, region, fromStoreID), nil
+}
+
+// BatchOperator is an admin operator submitted in a batch. The fields used
+// depend on the name, the same as adding the operator alone.
+type BatchOperator struct {
+	Name        string `json:"name"`
+	RegionID    uint64 `json:"region_id"`
+	StoreID     uint64 `json:"store_id,omitempty"`
+	FromStoreID uint64 `json:"from_store_id,omitempty"`
+	ToStoreID   uint64 `json:"to_store_id,omitempty"`
+	// Auto picks the target of transfer-leader instead of ToStoreID.
+	Auto bool `json:"auto,omitempty"`
+}
+
+// BatchOperatorResult is an operator added by a batch. A region has one
+// operator at most, so the operator is tracked by the ID of its region.
+type BatchOperatorResult struct {
+	RegionID uint64 `json:"region_id"`
+	Operator string `json:"operator"`
+}
+
+func createBatchOperator(c *coordinator, bo *BatchOperator) (*schedule.Operator, error) {
+	switch bo.Name {
+	case "transfer-leader":
+		if bo.Auto {
+			return createAutoTransferLeaderOperator(c, bo.RegionID)
+		}
+		return createTransferLeaderOperator(c, bo.RegionID, bo.ToStoreID)
+	case "transfer-peer":
+		return createTransferPeerOperator(c, bo.RegionID, bo.FromStoreID, bo.ToStoreID)
+	case "add-peer":
+		return createAddPeerOperator(c, bo.RegionID, bo.StoreID, false)
+	case "remove-peer":
+		return createRemovePeerOperator(c, bo.RegionID, bo.StoreID)
+	}
+	return nil, errors.Errorf("unknown operator %s", bo.Name)
+}
+
+// AddBatchOperators validates the admin operators together and adds all of
+// them, or none of them if any is invalid. The operators in a batch should be
+// on different regions, and should not make any store receive more snapshots
+// than max-snapshot-count.
+func (h *Handler) AddBatchOperators(batch []*BatchOperator) ([]*BatchOperatorResult, error) {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	if len(batch) == 0 {
+		return nil, errcode.NewInvalidInputErr(errors.New("no operator in the batch"))
+	}
+
+	ops := make([]*schedule.Operator, 0, len(batch))
+	regions := make(map[uint64]int, len(batch))
+	for i, bo := range batch {
+		if j, ok := regions[bo.RegionID]; ok {
+			return nil, errcode.NewInvalidInputErr(errors.Errorf("operator %d conflicts with operator %d on region %d", i, j, bo.RegionID))
+		}
+		regions[bo.RegionID] = i
+		op, err := createBatchOperator(c, bo)
+		if err != nil {
+			if errcode.CodeChain(err) == nil {
+				err = errcode.NewInvalidInputErr(err)
+			}
+			return nil, errors.WithMessage(err, fmt.Sprintf("operator %d", i))
+		}
+		ops = append(ops, op)
+	}
+
+	snapshots := make(map[uint64]uint64)
+	for _, op := range ops {
+		for i := 0; i < op.Len(); i++ {
+			switch s := op.Step(i).(type) {
+			case schedule.AddPeer:
+				snapshots[s.ToStore]++
+			case schedule.AddLearner:
+				snapshots[s.ToStore]++
+			}
+		}
+	}
+	for storeID, count := range snapshots {
+		store := c.cluster.GetStore(storeID)
+		if store == nil {
+			continue
+		}
+		count += uint64(store.Stats.GetReceivingSnapCount())
+		if limit := c.cluster.GetMaxSnapshotCount(); count > limit {
+			return nil, errcode.NewInvalidInputErr(errors.Errorf("store %d would receive %d snapshots, exceeding max-snapshot-count %d", storeID, count, limit))
+		}
+	}
+
+	if ok := c.opController.AddOperator(ops...); !ok {
+		return nil, errors.WithStack(errAddOperator)
+	}
+	results := make([]*BatchOperatorResult, 0, len(ops))
+	for _, op := range ops {
+		results = append(results, &BatchOperatorResult{RegionID: op.RegionID(), Operator: op.String()})
+	}
+	return results, nil
 }
 
 // AddMergeRegionOperator adds an operator to merge region.
@@ -521,6 +884,10 @@ func (h *Handler) AddMergeRegionOperator(regionID uint64, targetID uint64) error
 		return err
 	}
 
+	if !c.cluster.IsFeatureSupported(RegionMerge) {
+		return ErrFeatureNotSupported(RegionMerge)
+	}
+
 	region := c.cluster.GetRegion(regionID)
 	if region == nil {
 		return ErrRegionNotFound(regionID)
@@ -568,6 +935,13 @@ func (h *Handler) AddSplitRegionOperator(regionID uint64, policy string) error {
 	if region == nil {
 		return ErrRegionNotFound(regionID)
 	}
+	// The split is reserved in the quota when TiKV asks for the new IDs.
+	if err = c.quotas.checkSplit(region, 1, false); err != nil {
+		return err
+	}
+	if err = c.quotas.allowOperator(region); err != nil {
+		return err
+	}
 
 	step := schedule.SplitRegion{
 		StartKey: region.GetStartKey(),
@@ -592,6 +966,9 @@ func (h *Handler) AddScatterRegionOperator(regionID uint64) error {
 	if region == nil {
 		return ErrRegionNotFound(regionID)
 	}
+	if err = c.quotas.allowOperator(region); err != nil {
+		return err
+	}
 
 	op := c.regionScatterer.Scatter(region)
 	if op == nil {
@@ -657,3 +1034,39 @@ func (h *Handler) GetIncorrectNamespaceRegions() ([]*core.RegionInfo, error) {
 	defer c.RUnlock()
 	return c.cachedCluster.GetRegionStatsByType(incorrectNamespace), nil
 }
+
+// DiagnoseRegion explains why the region is or is not scheduled.
+func (h *Handler) DiagnoseRegion(regionID uint64) (*RegionDiagnosis, error) {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return nil, err
+	}
+	region := c.cluster.GetRegion(regionID)
+	if region == nil {
+		return nil, ErrRegionNotFound(regionID)
+	}
+	return c.diagnoseRegion(region), nil
+}
+
+// DiagnoseStore explains why the store is or is not scheduled.
+func (h *Handler) DiagnoseStore(storeID uint64) (*StoreDiagnosis, error) {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return nil, err
+	}
+	store := c.cluster.GetStore(storeID)
+	if store == nil {
+		return nil, errors.WithStack(core.NewStoreNotFoundErr(storeID))
+	}
+	return c.diagnoseStore(store), nil
+}
+
+// SimulateCapacity predicts the balance result of adding or removing stores
+// by running the schedulers on a copy of the cluster.
+func (h *Handler) SimulateCapacity(input *CapacitySimulationInput) (*CapacitySimulation, error) {
+	cluster := h.s.GetRaftCluster()
+	if cluster == nil {
+		return nil, errors.WithStack(ErrNotBootstrapped)
+	}
+	return cluster.cachedCluster.simulateCapacity(input)
+}
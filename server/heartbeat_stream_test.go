@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/pd/pkg/testutil"
 	"github.com/pingcap/pd/pkg/typeutil"
+	"github.com/pingcap/pd/server/core"
 )
 
 var _ = Suite(&testHeartbeatStreamSuite{})
@@ -126,3 +127,126 @@ func (c *regionHeartbeatClient) SendRecv(msg *pdpb.RegionHeartbeatRequest, timeo
 		return res
 	}
 }
+
+var _ = Suite(&testHeartbeatStreamQueueSuite{})
+
+type testHeartbeatStreamQueueSuite struct{}
+
+func newTestStreamRegion(regionID, storeID uint64) *core.RegionInfo {
+	leader := &metapb.Peer{Id: regionID + 100, StoreId: storeID}
+	return core.NewRegionInfo(&metapb.Region{
+		Id:          regionID,
+		Peers:       []*metapb.Peer{leader},
+		RegionEpoch: &metapb.RegionEpoch{ConfVer: 1, Version: 1},
+	}, leader)
+}
+
+func (s *testHeartbeatStreamQueueSuite) TestSlowStream(c *C) {
+	hbStreams := newHeartbeatStreams(1)
+	defer hbStreams.Close()
+
+	// Nobody receives from stream1, so it blocks on sending.
+	stream1, stream2 := newMockHeartbeatStream(), newMockHeartbeatStream()
+	hbStreams.bindStream(1, stream1)
+	hbStreams.bindStream(2, stream2)
+
+	for i := uint64(1); i <= 10; i++ {
+		hbStreams.SendMsg(newTestStreamRegion(i, 1), &pdpb.RegionHeartbeatResponse{})
+	}
+	hbStreams.SendMsg(newTestStreamRegion(11, 2), &pdpb.RegionHeartbeatResponse{})
+
+	select {
+	case res := <-stream2.ch:
+		c.Assert(res.GetRegionId(), Equals, uint64(11))
+	case <-time.After(100 * time.Millisecond):
+		c.Fatal("message to store 2 is delayed by store 1")
+	}
+}
+
+func (s *testHeartbeatStreamQueueSuite) TestStaleEpoch(c *C) {
+	hbStreams := newHeartbeatStreams(1)
+	defer hbStreams.Close()
+
+	// Messages are buffered until the stream is bound.
+	region := newTestStreamRegion(1, 1)
+	hbStreams.SendMsg(region, &pdpb.RegionHeartbeatResponse{ChangePeer: &pdpb.ChangePeer{}})
+	hbStreams.SendMsg(newTestStreamRegion(2, 1), &pdpb.RegionHeartbeatResponse{})
+	// The message with the same epoch replaces the queued one.
+	hbStreams.SendMsg(region, &pdpb.RegionHeartbeatResponse{TransferLeader: &pdpb.TransferLeader{}})
+	// The message with a stale epoch is replaced.
+	hbStreams.SendMsg(region.Clone(core.WithIncVersion()), &pdpb.RegionHeartbeatResponse{})
+	hbStreams.SendMsg(region, &pdpb.RegionHeartbeatResponse{})
+
+	stream := newMockHeartbeatStream()
+	hbStreams.bindStream(1, stream)
+	res := stream.Recv()
+	c.Assert(res.GetRegionId(), Equals, uint64(2))
+	res = stream.Recv()
+	c.Assert(res.GetRegionId(), Equals, uint64(1))
+	c.Assert(res.GetRegionEpoch().GetVersion(), Equals, uint64(2))
+	c.Assert(stream.Recv(), IsNil)
+}
+
+func (s *testHeartbeatStreamQueueSuite) TestReconnect(c *C) {
+	hbStreams := newHeartbeatStreams(1)
+	defer hbStreams.Close()
+
+	stream1 := newMockHeartbeatStream()
+	hbStreams.bindStream(1, stream1)
+	hbStreams.SendMsg(newTestStreamRegion(1, 1), &pdpb.RegionHeartbeatResponse{})
+	c.Assert(stream1.Recv().GetRegionId(), Equals, uint64(1))
+
+	// stream1 fails to send the message and is unbound.
+	hbStreams.SendMsg(newTestStreamRegion(2, 1), &pdpb.RegionHeartbeatResponse{})
+	time.Sleep(1500 * time.Millisecond)
+	hbStreams.SendMsg(newTestStreamRegion(3, 1), &pdpb.RegionHeartbeatResponse{})
+	c.Assert(stream1.Recv(), IsNil)
+
+	// The buffered message is sent after reconnecting.
+	stream2 := newMockHeartbeatStream()
+	hbStreams.bindStream(1, stream2)
+	c.Assert(stream2.Recv().GetRegionId(), Equals, uint64(3))
+}
+
+func (s *testHeartbeatStreamQueueSuite) TestDrain(c *C) {
+	hbStreams := newHeartbeatStreams(1)
+	defer hbStreams.Close()
+
+	// The messages to a store without a stream are not waited for.
+	hbStreams.SendMsg(newTestStreamRegion(1, 1), &pdpb.RegionHeartbeatResponse{})
+	c.Assert(hbStreams.drain(context.Background()), IsNil)
+
+	// Nobody receives from the stream, so it blocks on sending.
+	stream := newMockHeartbeatStream()
+	hbStreams.bindStream(2, stream)
+	hbStreams.SendMsg(newTestStreamRegion(2, 2), &pdpb.RegionHeartbeatResponse{})
+	hbStreams.SendMsg(newTestStreamRegion(3, 2), &pdpb.RegionHeartbeatResponse{})
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	c.Assert(hbStreams.drain(ctx), NotNil)
+	cancel()
+
+	go func() {
+		for i := 0; i < 2; i++ {
+			<-stream.ch
+		}
+	}()
+	c.Assert(hbStreams.drain(context.Background()), IsNil)
+}
+
+func (s *testHeartbeatStreamQueueSuite) TestRemoveStore(c *C) {
+	hbStreams := newHeartbeatStreams(1)
+	defer hbStreams.Close()
+
+	stream := newMockHeartbeatStream()
+	hbStreams.bindStream(1, stream)
+	hbStreams.SendMsg(newTestStreamRegion(1, 1), &pdpb.RegionHeartbeatResponse{})
+	c.Assert(stream.Recv().GetRegionId(), Equals, uint64(1))
+
+	// The queue is dropped with its stream.
+	hbStreams.removeStore(1)
+	hbStreams.Lock()
+	c.Assert(hbStreams.queues, HasLen, 0)
+	hbStreams.Unlock()
+	hbStreams.SendMsg(newTestStreamRegion(2, 1), &pdpb.RegionHeartbeatResponse{})
+	c.Assert(stream.Recv(), IsNil)
+}
@@ -22,28 +22,154 @@ import (
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/pd/pkg/logutil"
 	"github.com/pingcap/pd/server/core"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
-const heartbeatStreamKeepAliveInterval = time.Minute
+const (
+	heartbeatStreamKeepAliveInterval = time.Minute
+	// heartbeatStreamMsgTTL is how long a message waits in the queue of a
+	// store. The messages are buffered if the store has no bound stream, and
+	// are sent after the store reconnects.
+	heartbeatStreamMsgTTL = 30 * time.Second
+)
 
 type heartbeatStream interface {
 	Send(*pdpb.RegionHeartbeatResponse) error
 }
 
-type streamUpdate struct {
-	storeID uint64
-	stream  heartbeatStream
+type queuedMsg struct {
+	msg      *pdpb.RegionHeartbeatResponse
+	msgType  string
+	pushTime time.Time
+}
+
+// storeSendQueue is the bounded queue of the messages to a store. The messages
+// are sent through the bound stream of the store in a separate goroutine, so
+// a slow stream does not delay the messages to other stores.
+type storeSendQueue struct {
+	sync.Mutex
+	storeLabel string
+	stream     heartbeatStream
+	msgs       []queuedMsg
+	// sending is true if a popped message is being sent.
+	sending  bool
+	notifyCh chan struct{}
+	// stopCh is closed when the store is removed.
+	stopCh chan struct{}
+}
+
+func newStoreSendQueue(storeID uint64) *storeSendQueue {
+	return &storeSendQueue{
+		storeLabel: strconv.FormatUint(storeID, 10),
+		notifyCh:   make(chan struct{}, 1),
+		stopCh:     make(chan struct{}),
+	}
+}
+
+func (q *storeSendQueue) notify() {
+	select {
+	case q.notifyCh <- struct{}{}:
+	default:
+	}
+}
+
+func (q *storeSendQueue) bind(stream heartbeatStream) {
+	q.Lock()
+	q.stream = stream
+	q.Unlock()
+	q.notify()
+}
+
+// unbind removes the stream if it is still bound. The messages are buffered
+// until a new stream is bound.
+func (q *storeSendQueue) unbind(stream heartbeatStream) {
+	q.Lock()
+	defer q.Unlock()
+	if q.stream == stream {
+		q.stream = nil
+	}
+}
+
+// push adds the message to the queue. The message replaces the queued
+// messages of the same region, and is dropped if its region epoch is stale.
+// The oldest message is dropped if the queue is full.
+func (q *storeSendQueue) push(msg *pdpb.RegionHeartbeatResponse, msgType string) {
+	q.Lock()
+	defer q.Unlock()
+
+	if msg.GetRegionId() != 0 {
+		for _, m := range q.msgs {
+			if m.msg.GetRegionId() == msg.GetRegionId() && isNewerEpoch(m.msg, msg) {
+				regionHeartbeatDropCounter.WithLabelValues(q.storeLabel, "stale").Inc()
+				return
+			}
+		}
+		msgs := q.msgs[:0]
+		for _, m := range q.msgs {
+			if m.msg.GetRegionId() == msg.GetRegionId() {
+				regionHeartbeatDropCounter.WithLabelValues(q.storeLabel, "stale").Inc()
+				continue
+			}
+			msgs = append(msgs, m)
+		}
+		q.msgs = msgs
+	}
+	if len(q.msgs) >= regionheartbeatSendQueueCap {
+		regionHeartbeatDropCounter.WithLabelValues(q.storeLabel, "full").Inc()
+		q.msgs = q.msgs[1:]
+	}
+	q.msgs = append(q.msgs, queuedMsg{msg: msg, msgType: msgType, pushTime: time.Now()})
+	regionHeartbeatQueueGauge.WithLabelValues(q.storeLabel).Set(float64(len(q.msgs)))
+	q.notify()
+}
+
+// pop returns the first message which is not expired and the bound stream. It
+// returns nil if the queue is empty or no stream is bound.
+func (q *storeSendQueue) pop() (*queuedMsg, heartbeatStream) {
+	q.Lock()
+	defer q.Unlock()
+
+	q.sending = false
+	if q.stream == nil {
+		return nil, nil
+	}
+	for len(q.msgs) > 0 {
+		m := q.msgs[0]
+		q.msgs = q.msgs[1:]
+		regionHeartbeatQueueGauge.WithLabelValues(q.storeLabel).Set(float64(len(q.msgs)))
+		if time.Since(m.pushTime) > heartbeatStreamMsgTTL {
+			regionHeartbeatDropCounter.WithLabelValues(q.storeLabel, "expired").Inc()
+			continue
+		}
+		q.sending = true
+		return &m, q.stream
+	}
+	return nil, nil
+}
+
+// drained returns true if no message is waiting to be sent through the bound
+// stream. The messages to a store without a stream can not be sent.
+func (q *storeSendQueue) drained() bool {
+	q.Lock()
+	defer q.Unlock()
+	return !q.sending && (len(q.msgs) == 0 || q.stream == nil)
+}
+
+// isNewerEpoch returns true if the epoch of the region in m is newer than
+// that in other.
+func isNewerEpoch(m, other *pdpb.RegionHeartbeatResponse) bool {
+	epoch, otherEpoch := m.GetRegionEpoch(), other.GetRegionEpoch()
+	return epoch.GetVersion() > otherEpoch.GetVersion() || epoch.GetConfVer() > otherEpoch.GetConfVer()
 }
 
 type heartbeatStreams struct {
+	sync.Mutex
 	wg        sync.WaitGroup
 	ctx       context.Context
 	cancel    context.CancelFunc
 	clusterID uint64
-	streams   map[uint64]heartbeatStream
-	msgCh     chan *pdpb.RegionHeartbeatResponse
-	streamCh  chan streamUpdate
+	queues    map[uint64]*storeSendQueue
 }
 
 func newHeartbeatStreams(clusterID uint64) *heartbeatStreams {
@@ -52,9 +178,7 @@ func newHeartbeatStreams(clusterID uint64) *heartbeatStreams {
 		ctx:       ctx,
 		cancel:    cancel,
 		clusterID: clusterID,
-		streams:   make(map[uint64]heartbeatStream),
-		msgCh:     make(chan *pdpb.RegionHeartbeatResponse, regionheartbeatSendChanCap),
-		streamCh:  make(chan streamUpdate, 1),
+		queues:    make(map[uint64]*storeSendQueue),
 	}
 	hs.wg.Add(1)
 	go hs.run()
@@ -73,38 +197,79 @@ func (s *heartbeatStreams) run() {
 
 	for {
 		select {
-		case update := <-s.streamCh:
-			s.streams[update.storeID] = update.stream
-		case msg := <-s.msgCh:
-			storeID := msg.GetTargetPeer().GetStoreId()
-			storeLabel := strconv.FormatUint(storeID, 10)
-			if stream, ok := s.streams[storeID]; ok {
-				if err := stream.Send(msg); err != nil {
-					log.Errorf("[region %v] send heartbeat message fail: %v", msg.RegionId, err)
-					delete(s.streams, storeID)
-					regionHeartbeatCounter.WithLabelValues(storeLabel, "push", "err").Inc()
-				} else {
-					regionHeartbeatCounter.WithLabelValues(storeLabel, "push", "ok").Inc()
-				}
-			} else {
-				log.Debugf("[region %v] heartbeat stream not found for store %v, skip send message", msg.RegionId, storeID)
-				regionHeartbeatCounter.WithLabelValues(storeLabel, "push", "skip").Inc()
-			}
 		case <-keepAliveTicker.C:
-			for storeID, stream := range s.streams {
-				storeLabel := strconv.FormatUint(storeID, 10)
-				if err := stream.Send(keepAlive); err != nil {
-					log.Errorf("[store %v] send keepalive message fail: %v", storeID, err)
-					delete(s.streams, storeID)
-					regionHeartbeatCounter.WithLabelValues(storeLabel, "keepalive", "err").Inc()
-				} else {
-					regionHeartbeatCounter.WithLabelValues(storeLabel, "keepalive", "ok").Inc()
+			s.Lock()
+			for _, q := range s.queues {
+				q.Lock()
+				bound := q.stream != nil
+				q.Unlock()
+				if bound {
+					q.push(keepAlive, "keepalive")
 				}
 			}
+			s.Unlock()
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// sendLoop sends the messages in the queue of a store until the streams are
+// closed or the store is removed.
+func (s *heartbeatStreams) sendLoop(storeID uint64, q *storeSendQueue) {
+	defer logutil.LogPanic()
+
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-q.notifyCh:
+		case <-q.stopCh:
+			return
 		case <-s.ctx.Done():
 			return
 		}
+		for {
+			m, stream := q.pop()
+			if m == nil {
+				break
+			}
+			if err := stream.Send(m.msg); err != nil {
+				log.Errorf("[store %v] send %s message of region %v fail: %v", storeID, m.msgType, m.msg.GetRegionId(), err)
+				q.unbind(stream)
+				regionHeartbeatCounter.WithLabelValues(q.storeLabel, m.msgType, "err").Inc()
+			} else {
+				regionHeartbeatCounter.WithLabelValues(q.storeLabel, m.msgType, "ok").Inc()
+			}
+		}
+	}
+}
+
+// drain waits until the queued messages are sent, or ctx is done.
+func (s *heartbeatStreams) drain(ctx context.Context) error {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		if s.drained() {
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return errors.WithStack(ctx.Err())
+		}
+	}
+}
+
+func (s *heartbeatStreams) drained() bool {
+	s.Lock()
+	defer s.Unlock()
+	for _, q := range s.queues {
+		if !q.drained() {
+			return false
+		}
 	}
+	return true
 }
 
 func (s *heartbeatStreams) Close() {
@@ -112,14 +277,43 @@ func (s *heartbeatStreams) Close() {
 	s.wg.Wait()
 }
 
-func (s *heartbeatStreams) bindStream(storeID uint64, stream heartbeatStream) {
-	update := streamUpdate{
-		storeID: storeID,
-		stream:  stream,
+// getQueue returns the send queue of the store. It returns nil if the streams
+// are closed.
+func (s *heartbeatStreams) getQueue(storeID uint64) *storeSendQueue {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.ctx.Err() != nil {
+		return nil
 	}
-	select {
-	case s.streamCh <- update:
-	case <-s.ctx.Done():
+	q, ok := s.queues[storeID]
+	if !ok {
+		q = newStoreSendQueue(storeID)
+		s.queues[storeID] = q
+		s.wg.Add(1)
+		go s.sendLoop(storeID, q)
+	}
+	return q
+}
+
+// removeStore drops the queue of the store and stops sending to it. It is
+// called when the store becomes tombstone.
+func (s *heartbeatStreams) removeStore(storeID uint64) {
+	s.Lock()
+	defer s.Unlock()
+
+	q, ok := s.queues[storeID]
+	if !ok {
+		return
+	}
+	delete(s.queues, storeID)
+	close(q.stopCh)
+	regionHeartbeatQueueGauge.DeleteLabelValues(q.storeLabel)
+}
+
+func (s *heartbeatStreams) bindStream(storeID uint64, stream heartbeatStream) {
+	if q := s.getQueue(storeID); q != nil {
+		q.bind(stream)
 	}
 }
 
@@ -133,10 +327,7 @@ func (s *heartbeatStreams) SendMsg(region *core.RegionInfo, msg *pdpb.RegionHear
 	msg.RegionEpoch = region.GetRegionEpoch()
 	msg.TargetPeer = region.GetLeader()
 
-	select {
-	case s.msgCh <- msg:
-	case <-s.ctx.Done():
-	}
+	s.push(msg)
 }
 
 func (s *heartbeatStreams) sendErr(region *core.RegionInfo, errType pdpb.ErrorType, errMsg string, storeLabel string) {
@@ -152,8 +343,17 @@ func (s *heartbeatStreams) sendErr(region *core.RegionInfo, errType pdpb.ErrorTy
 		},
 	}
 
-	select {
-	case s.msgCh <- msg:
-	case <-s.ctx.Done():
+	s.push(msg)
+}
+
+func (s *heartbeatStreams) push(msg *pdpb.RegionHeartbeatResponse) {
+	storeID := msg.GetTargetPeer().GetStoreId()
+	if storeID == 0 {
+		log.Debugf("[region %v] heartbeat message has no target store, skip send message", msg.GetRegionId())
+		regionHeartbeatCounter.WithLabelValues(strconv.FormatUint(storeID, 10), "push", "skip").Inc()
+		return
+	}
+	if q := s.getQueue(storeID); q != nil {
+		q.push(msg, "push")
 	}
 }
@@ -14,11 +14,16 @@
 package server
 
 import (
+	"context"
 	"sync"
+	"time"
 
 	"github.com/coreos/etcd/clientv3"
+	"github.com/pingcap/pd/pkg/idpb"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -34,11 +39,17 @@ type idAllocator struct {
 }
 
 func (alloc *idAllocator) Alloc() (uint64, error) {
+	return alloc.allocWithContext(alloc.s.client.Ctx())
+}
+
+// allocWithContext allocates an ID, the etcd requests to generate new IDs are
+// canceled when ctx is done.
+func (alloc *idAllocator) allocWithContext(ctx context.Context) (uint64, error) {
 	alloc.mu.Lock()
 	defer alloc.mu.Unlock()
 
 	if alloc.base == alloc.end {
-		end, err := alloc.generate()
+		end, err := alloc.generate(ctx)
 		if err != nil {
 			return 0, err
 		}
@@ -52,9 +63,19 @@ func (alloc *idAllocator) Alloc() (uint64, error) {
 	return alloc.base, nil
 }
 
-func (alloc *idAllocator) generate() (uint64, error) {
+// reset drops the cached IDs, so the next allocation starts from the value in
+// etcd.
+func (alloc *idAllocator) reset() {
+	alloc.mu.Lock()
+	defer alloc.mu.Unlock()
+	alloc.base = alloc.end
+}
+
+func (alloc *idAllocator) generate(ctx context.Context) (uint64, error) {
 	key := alloc.s.getAllocIDPath()
-	value, err := getValue(alloc.s.client, key)
+	readCtx, cancel := alloc.s.readContext(ctx)
+	value, err := getValue(readCtx, alloc.s.client, key)
+	cancel()
 	if err != nil {
 		return 0, err
 	}
@@ -79,15 +100,149 @@ func (alloc *idAllocator) generate() (uint64, error) {
 
 	end += allocStep
 	value = uint64ToBytes(end)
-	resp, err := alloc.s.leaderTxn(cmp).Then(clientv3.OpPut(key, string(value))).Commit()
+	resp, err := alloc.s.leaderTxnWithContext(ctx, cmp).Then(clientv3.OpPut(key, string(value))).Commit()
 	if err != nil {
 		return 0, err
 	}
 	if !resp.Succeeded {
-		return 0, errors.New("generate id failed, we may not leader")
+		return 0, errors.Wrap(ErrNotLeader, "generate id failed")
 	}
 
 	log.Infof("idAllocator allocates a new id: %d", end)
 	metadataGauge.WithLabelValues("idalloc").Set(float64(end))
 	return end, nil
 }
+
+const (
+	// timeIDLogicalBits is the bits of the IDs allocated in a millisecond.
+	timeIDLogicalBits = 22
+	// timeIDAllocWindow is the IDs reserved in etcd at a time, which are
+	// allocated in about 3 seconds.
+	timeIDAllocWindow = uint64(3000) << timeIDLogicalBits
+)
+
+// timeIDEpoch is the time the physical part of time-ordered IDs starts from.
+var timeIDEpoch = time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// timeIDAllocator allocates the IDs roughly ordered by the allocation time.
+// The high bits of an ID are the milliseconds since timeIDEpoch, and the low
+// timeIDLogicalBits bits tell the IDs allocated in the same millisecond apart.
+// The IDs are far greater than the ones allocated by idAllocator, so the two
+// allocators never collide.
+type timeIDAllocator struct {
+	mu   sync.Mutex
+	last uint64
+	// end is the max ID reserved in etcd.
+	end uint64
+
+	s *Server
+}
+
+func (alloc *timeIDAllocator) Alloc() (uint64, error) {
+	return alloc.allocWithContext(alloc.s.client.Ctx())
+}
+
+// allocWithContext allocates an ID, the etcd requests to reserve new IDs are
+// canceled when ctx is done.
+func (alloc *timeIDAllocator) allocWithContext(ctx context.Context) (uint64, error) {
+	alloc.mu.Lock()
+	defer alloc.mu.Unlock()
+
+	id := timeToID(time.Now())
+	if id <= alloc.last {
+		id = alloc.last + 1
+	}
+	if id > alloc.end {
+		start, err := alloc.generate(ctx, id)
+		if err != nil {
+			return 0, err
+		}
+		id, alloc.end = start, start+timeIDAllocWindow
+	}
+
+	alloc.last = id
+	return id, nil
+}
+
+// generate reserves the IDs from start, which is after both id and the IDs
+// reserved before, and returns start.
+func (alloc *timeIDAllocator) generate(ctx context.Context, id uint64) (uint64, error) {
+	key := alloc.s.getTimeIDAllocPath()
+	readCtx, cancel := alloc.s.readContext(ctx)
+	value, err := getValue(readCtx, alloc.s.client, key)
+	cancel()
+	if err != nil {
+		return 0, err
+	}
+
+	var cmp clientv3.Cmp
+	if value == nil {
+		cmp = clientv3.Compare(clientv3.CreateRevision(key), "=", 0)
+	} else {
+		end, err := bytesToUint64(value)
+		if err != nil {
+			return 0, err
+		}
+		if id <= end {
+			id = end + 1
+		}
+		cmp = clientv3.Compare(clientv3.Value(key), "=", string(value))
+	}
+
+	end := id + timeIDAllocWindow
+	resp, err := alloc.s.leaderTxnWithContext(ctx, cmp).Then(clientv3.OpPut(key, string(uint64ToBytes(end)))).Commit()
+	if err != nil {
+		return 0, err
+	}
+	if !resp.Succeeded {
+		return 0, errors.Wrap(ErrNotLeader, "generate time-ordered id failed")
+	}
+
+	log.Infof("timeIDAllocator reserves ids to: %d", end)
+	metadataGauge.WithLabelValues("time_idalloc").Set(float64(end))
+	return id, nil
+}
+
+// timeToID returns the first time-ordered ID of the millisecond.
+func timeToID(t time.Time) uint64 {
+	return uint64(t.Sub(timeIDEpoch)/time.Millisecond) << timeIDLogicalBits
+}
+
+// IDToTime returns the time a time-ordered ID is allocated at, in
+// milliseconds.
+func IDToTime(id uint64) time.Time {
+	return timeIDEpoch.Add(time.Duration(id>>timeIDLogicalBits) * time.Millisecond)
+}
+
+// idService implements gRPC IDServer.
+type idService struct {
+	s *Server
+}
+
+// AllocID implements gRPC IDServer.
+func (is *idService) AllocID(ctx context.Context, request *idpb.AllocIDRequest) (*idpb.AllocIDResponse, error) {
+	if err := is.s.validateRequest(request.GetHeader()); err != nil {
+		return nil, err
+	}
+
+	var (
+		id  uint64
+		err error
+	)
+	switch request.GetKind() {
+	case idpb.IDKind_COUNTER:
+		id, err = is.s.idAlloc.allocWithContext(ctx)
+	case idpb.IDKind_TIME_ORDERED:
+		id, err = is.s.timeIDAlloc.allocWithContext(ctx)
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown id kind %v", request.GetKind())
+	}
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return &idpb.AllocIDResponse{
+		Header: is.s.header(),
+		Id:     id,
+	}, nil
+}
@@ -15,11 +15,15 @@ package server
 
 import (
 	"context"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/coreos/etcd/clientv3"
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/pkg/idpb"
+	"google.golang.org/grpc"
 )
 
 var _ = Suite(&testAllocIDSuite{})
@@ -95,3 +99,65 @@ func (s *testAllocIDSuite) TestCommand(c *C) {
 		last = resp.GetId()
 	}
 }
+
+func (s *testAllocIDSuite) TestTimeOrderedID(c *C) {
+	mustGetLeader(c, s.client, s.svr.getLeaderPath())
+
+	start := time.Now()
+	counterID, err := s.alloc.Alloc()
+	c.Assert(err, IsNil)
+	var last uint64
+	for i := 0; i < 1000; i++ {
+		id, err := s.svr.timeIDAlloc.Alloc()
+		c.Assert(err, IsNil)
+		c.Assert(id, Greater, last)
+		c.Assert(id, Greater, counterID)
+		last = id
+	}
+	c.Assert(IDToTime(last).Before(start.Add(-time.Millisecond)), IsFalse)
+	c.Assert(IDToTime(last).After(time.Now()), IsFalse)
+
+	// A new leader allocates the IDs after the reserved ones.
+	alloc := &timeIDAllocator{s: s.svr}
+	id, err := alloc.Alloc()
+	c.Assert(err, IsNil)
+	c.Assert(id, Greater, s.svr.timeIDAlloc.end)
+
+	// The counter is not affected.
+	next, err := s.alloc.Alloc()
+	c.Assert(err, IsNil)
+	c.Assert(next, Equals, counterID+1)
+}
+
+func (s *testAllocIDSuite) TestTimeOrderedIDCommand(c *C) {
+	conn, err := grpc.Dial(strings.TrimPrefix(s.svr.GetAddr(), "http://"), grpc.WithInsecure())
+	c.Assert(err, IsNil)
+	defer conn.Close()
+	client := idpb.NewIDClient(conn)
+	req := &idpb.AllocIDRequest{
+		Header: newRequestHeader(s.svr.clusterID),
+		Kind:   idpb.IDKind_TIME_ORDERED,
+	}
+
+	var last uint64
+	for i := 0; i < 100; i++ {
+		resp, err := client.AllocID(context.Background(), req)
+		c.Assert(err, IsNil)
+		c.Assert(resp.GetId(), Greater, last)
+		last = resp.GetId()
+	}
+	c.Assert(time.Since(IDToTime(last)), Less, time.Minute)
+
+	// The counter IDs are still allocated by default.
+	req.Kind = idpb.IDKind_COUNTER
+	resp, err := client.AllocID(context.Background(), req)
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetId(), Less, last)
+	pdResp, err := s.grpcPDClient.AllocID(context.Background(), &pdpb.AllocIDRequest{Header: newRequestHeader(s.svr.clusterID)})
+	c.Assert(err, IsNil)
+	c.Assert(pdResp.GetId(), Less, last)
+
+	req.Kind = idpb.IDKind(100)
+	_, err = client.AllocID(context.Background(), req)
+	c.Assert(err, NotNil)
+}
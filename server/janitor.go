@@ -0,0 +1,202 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pingcap/pd/pkg/logutil"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/namespace"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	janitorInterval = 10 * time.Minute
+	// The janitor deletes at most janitorBatchSize keys every
+	// janitorBatchInterval, to avoid putting pressure on etcd.
+	janitorBatchSize     = 64
+	janitorBatchInterval = time.Second
+	janitorMaxReports    = 10
+)
+
+// JanitorReport shows what is cleaned by the janitor in one round.
+type JanitorReport struct {
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	// Regions are the regions whose peers are all on tombstone stores.
+	Regions []uint64 `json:"regions,omitempty"`
+	// StoreWeights are the tombstone stores whose weights are deleted.
+	StoreWeights []uint64 `json:"store_weights,omitempty"`
+	// NamespaceStores are the tombstone or unknown stores removed from each
+	// namespace.
+	NamespaceStores map[string][]uint64 `json:"namespace_stores,omitempty"`
+	Error           string              `json:"error,omitempty"`
+}
+
+// janitor removes the orphaned keys left by tombstone stores. It runs in the
+// background, and keeps the reports of recent rounds.
+type janitor struct {
+	sync.RWMutex
+	cluster *RaftCluster
+	reports []*JanitorReport
+	// deleted is the count of keys deleted in the current batch.
+	deleted int
+}
+
+func newJanitor(cluster *RaftCluster) *janitor {
+	return &janitor{cluster: cluster}
+}
+
+func (j *janitor) getReports() []*JanitorReport {
+	j.RLock()
+	defer j.RUnlock()
+	reports := make([]*JanitorReport, len(j.reports))
+	copy(reports, j.reports)
+	return reports
+}
+
+func (j *janitor) addReport(report *JanitorReport) {
+	j.Lock()
+	defer j.Unlock()
+	j.reports = append(j.reports, report)
+	if len(j.reports) > janitorMaxReports {
+		j.reports = j.reports[len(j.reports)-janitorMaxReports:]
+	}
+}
+
+// throttle waits for the next batch if the current batch is full. It returns
+// false if the cluster is stopped.
+func (j *janitor) throttle(quit <-chan struct{}) bool {
+	j.deleted++
+	if j.deleted < janitorBatchSize {
+		return true
+	}
+	j.deleted = 0
+	select {
+	case <-quit:
+		return false
+	case <-time.After(janitorBatchInterval):
+		return true
+	}
+}
+
+// run cleans the orphaned keys in one round.
+func (j *janitor) run(cluster *clusterInfo, classifier namespace.Classifier, kv *core.KV, quit <-chan struct{}) *JanitorReport {
+	report := &JanitorReport{StartTime: time.Now()}
+	j.deleted = 0
+	if err := j.clean(report, cluster, classifier, kv, quit); err != nil {
+		log.Errorf("janitor failed to clean orphaned keys: %v", err)
+		report.Error = err.Error()
+	}
+	report.EndTime = time.Now()
+	if len(report.Regions) > 0 || len(report.StoreWeights) > 0 || len(report.NamespaceStores) > 0 {
+		log.Infof("janitor cleaned regions %v, store weights %v, namespace stores %v",
+			report.Regions, report.StoreWeights, report.NamespaceStores)
+	}
+	j.addReport(report)
+	return report
+}
+
+func (j *janitor) clean(report *JanitorReport, cluster *clusterInfo, classifier namespace.Classifier, kv *core.KV, quit <-chan struct{}) error {
+	isTombstone := func(storeID uint64) bool {
+		store := cluster.GetStore(storeID)
+		return store != nil && store.IsTombstone()
+	}
+
+	for _, region := range cluster.getRegions() {
+		peers := region.GetPeers()
+		orphaned := len(peers) > 0
+		for _, peer := range peers {
+			if !isTombstone(peer.GetStoreId()) {
+				orphaned = false
+				break
+			}
+		}
+		if !orphaned {
+			continue
+		}
+		if err := kv.DeleteRegion(region.GetMeta()); err != nil {
+			return err
+		}
+		cluster.dropRegion(region.GetID())
+		report.Regions = append(report.Regions, region.GetID())
+		if !j.throttle(quit) {
+			return nil
+		}
+	}
+
+	for _, store := range cluster.GetStores() {
+		if !store.IsTombstone() {
+			continue
+		}
+		deleted, err := kv.DeleteStoreWeight(store.GetId())
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			continue
+		}
+		report.StoreWeights = append(report.StoreWeights, store.GetId())
+		if !j.throttle(quit) {
+			return nil
+		}
+	}
+
+	binder, ok := classifier.(namespace.StoreBinder)
+	if !ok {
+		return nil
+	}
+	for ns, storeIDs := range binder.GetNamespaceStoreIDs() {
+		for _, storeID := range storeIDs {
+			if cluster.GetStore(storeID) != nil && !isTombstone(storeID) {
+				continue
+			}
+			if err := binder.RemoveNamespaceStoreID(ns, storeID); err != nil {
+				return err
+			}
+			if report.NamespaceStores == nil {
+				report.NamespaceStores = make(map[string][]uint64)
+			}
+			report.NamespaceStores[ns] = append(report.NamespaceStores[ns], storeID)
+			if !j.throttle(quit) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+func (c *RaftCluster) runJanitor(interval time.Duration) {
+	defer logutil.LogPanic()
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.quit:
+			return
+		case <-ticker.C:
+			c.janitor.run(c.cachedCluster, c.s.classifier, c.s.kv, c.quit)
+		}
+	}
+}
+
+// GetJanitorReports returns the reports of the recent rounds of the janitor.
+func (c *RaftCluster) GetJanitorReports() []*JanitorReport {
+	return c.janitor.getReports()
+}
@@ -0,0 +1,97 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/namespace"
+	"github.com/pkg/errors"
+)
+
+var _ = Suite(&testJanitorSuite{})
+
+type testJanitorSuite struct{}
+
+type mockStoreBinder struct {
+	namespace.Classifier
+	storeIDs map[string][]uint64
+}
+
+func (b *mockStoreBinder) GetNamespaceStoreIDs() map[string][]uint64 {
+	res := make(map[string][]uint64)
+	for name, storeIDs := range b.storeIDs {
+		res[name] = append([]uint64(nil), storeIDs...)
+	}
+	return res
+}
+
+func (b *mockStoreBinder) RemoveNamespaceStoreID(name string, storeID uint64) error {
+	for i, id := range b.storeIDs[name] {
+		if id == storeID {
+			b.storeIDs[name] = append(b.storeIDs[name][:i], b.storeIDs[name][i+1:]...)
+			return nil
+		}
+	}
+	return errors.Errorf("store %d is not in namespace %s", storeID, name)
+}
+
+func (s *testJanitorSuite) TestJanitor(c *C) {
+	_, opt := newTestScheduleConfig()
+	kv := core.NewKV(core.NewMemoryKV())
+	cluster := newClusterInfo(core.NewMockIDAllocator(), opt, kv)
+	for _, store := range newTestStores(3) {
+		if store.GetId() != 3 {
+			store.State = metapb.StoreState_Tombstone
+		}
+		c.Assert(cluster.putStore(store), IsNil)
+	}
+	newRegion := func(id uint64, storeIDs ...uint64) *core.RegionInfo {
+		meta := &metapb.Region{Id: id, StartKey: []byte{byte(id)}, EndKey: []byte{byte(id + 1)}}
+		for _, storeID := range storeIDs {
+			meta.Peers = append(meta.Peers, &metapb.Peer{Id: id*10 + storeID, StoreId: storeID})
+		}
+		return core.NewRegionInfo(meta, meta.Peers[0])
+	}
+	c.Assert(cluster.putRegion(newRegion(1, 1, 2)), IsNil)
+	c.Assert(cluster.putRegion(newRegion(2, 2, 3)), IsNil)
+	c.Assert(kv.SaveStoreWeight(1, 2, 2), IsNil)
+	c.Assert(kv.SaveStoreWeight(3, 2, 2), IsNil)
+	classifier := &mockStoreBinder{
+		Classifier: namespace.DefaultClassifier,
+		storeIDs:   map[string][]uint64{"ns1": {1, 3, 9}, "ns2": {3}},
+	}
+
+	j := newJanitor(nil)
+	report := j.run(cluster, classifier, kv, make(chan struct{}))
+	c.Assert(report.Error, Equals, "")
+	c.Assert(report.Regions, DeepEquals, []uint64{1})
+	c.Assert(report.StoreWeights, DeepEquals, []uint64{1})
+	c.Assert(report.NamespaceStores, DeepEquals, map[string][]uint64{"ns1": {1, 9}})
+
+	c.Assert(cluster.GetRegion(1), IsNil)
+	c.Assert(cluster.GetRegion(2), NotNil)
+	ok, err := kv.LoadRegion(1, &metapb.Region{})
+	c.Assert(err, IsNil)
+	c.Assert(ok, IsFalse)
+	c.Assert(classifier.storeIDs, DeepEquals, map[string][]uint64{"ns1": {3}, "ns2": {3}})
+
+	// Nothing to clean in the next round.
+	report = j.run(cluster, classifier, kv, make(chan struct{}))
+	c.Assert(report.Regions, HasLen, 0)
+	c.Assert(report.StoreWeights, HasLen, 0)
+	c.Assert(report.NamespaceStores, HasLen, 0)
+	c.Assert(j.getReports(), HasLen, 2)
+}
@@ -0,0 +1,481 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pingcap/pd/pkg/logutil"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// jobRetention is how long a finished job is kept.
+const jobRetention = 7 * 24 * time.Hour
+
+// Job status.
+const (
+	JobRunning  = "running"
+	JobPaused   = "paused"
+	JobFinished = "finished"
+	JobFailed   = "failed"
+	JobCanceled = "canceled"
+)
+
+var (
+	// ErrJobNotFound is error info for job not found.
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobNotRunning is error info for canceling a job which is not running.
+	ErrJobNotRunning = errors.New("job is not running")
+	// ErrJobNotPaused is error info for resuming a job which is not paused.
+	ErrJobNotPaused = errors.New("job is not paused")
+	// ErrInvalidJob is error info for invalid job arguments.
+	ErrInvalidJob = errors.New("invalid job argument")
+)
+
+// Job is an asynchronous admin action which takes a long time, such as
+// pre-splitting regions. Jobs are run by the leader, and the progress is
+// persisted, so that a new leader resumes the running jobs.
+type Job struct {
+	ID     uint64 `json:"id"`
+	Type   string `json:"type"`
+	Status string `json:"status"`
+	// Progress is the percentage of the job done.
+	Progress float64 `json:"progress"`
+	// Args are the arguments to start the job, and State is the progress of
+	// the job to resume it. Both are defined by the job type.
+	Args       json.RawMessage `json:"args,omitempty"`
+	State      json.RawMessage `json:"state,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	CreateTime time.Time       `json:"create_time"`
+	UpdateTime time.Time       `json:"update_time"`
+	FinishTime time.Time       `json:"finish_time,omitempty"`
+}
+
+func (j *Job) clone() *Job {
+	clone := *j
+	return &clone
+}
+
+// JobRunner runs a job until it is done or the context is canceled. When the
+// leader changes, the runner is called again with the saved state by the new
+// leader, so it should be idempotent.
+type JobRunner func(jc *JobContext) error
+
+var jobRunners = make(map[string]JobRunner)
+
+// registerJobRunner registers the runner of a job type.
+func registerJobRunner(typ string, runner JobRunner) {
+	if _, ok := jobRunners[typ]; ok {
+		log.Fatalf("duplicated job type %s", typ)
+	}
+	jobRunners[typ] = runner
+}
+
+// JobContext is the context of a running job. It is canceled when the job is
+// canceled or paused, or the server loses leadership.
+type JobContext struct {
+	context.Context
+	cancel context.CancelFunc
+	m      *jobManager
+	job    *Job
+}
+
+// ID returns the ID of the job.
+func (jc *JobContext) ID() uint64 {
+	return jc.job.ID
+}
+
+// Handler returns the handler to schedule the cluster.
+func (jc *JobContext) Handler() *Handler {
+	return jc.m.s.GetHandler()
+}
+
+// Args unmarshals the arguments of the job to v.
+func (jc *JobContext) Args(v interface{}) error {
+	return errors.WithStack(json.Unmarshal(jc.job.Args, v))
+}
+
+// State unmarshals the saved state of the job to v. It returns false if there
+// is no saved state.
+func (jc *JobContext) State(v interface{}) (bool, error) {
+	if len(jc.job.State) == 0 {
+		return false, nil
+	}
+	return true, errors.WithStack(json.Unmarshal(jc.job.State, v))
+}
+
+// Update saves the progress and the state of the job.
+func (jc *JobContext) Update(progress float64, state interface{}) error {
+	// The job may be resumed by another runner once it is paused.
+	if err := jc.Err(); err != nil {
+		return errors.WithStack(err)
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = jc.m.update(jc.job.ID, func(job *Job) {
+		job.Progress, job.State = progress, data
+	})
+	if err != nil {
+		return err
+	}
+	jc.job.Progress, jc.job.State = progress, data
+	return nil
+}
+
+// jobManager runs the jobs on the leader and persists their progress.
+type jobManager struct {
+	sync.RWMutex
+	s    *Server
+	kv   *core.KV
+	jobs map[uint64]*Job
+	// ctx is nil if the manager is not started.
+	ctx    context.Context
+	cancel context.CancelFunc
+	// runs are the contexts of the running jobs.
+	runs map[uint64]*JobContext
+	wg   sync.WaitGroup
+}
+
+func newJobManager(s *Server) *jobManager {
+	return &jobManager{
+		s:    s,
+		kv:   s.kv,
+		jobs: make(map[uint64]*Job),
+		runs: make(map[uint64]*JobContext),
+	}
+}
+
+// start loads the jobs and resumes the running ones. It is called after the
+// server becomes leader.
+func (m *jobManager) start() error {
+	m.Lock()
+	defer m.Unlock()
+
+	jobs := make(map[uint64]*Job)
+	err := m.kv.LoadJobs(func(data []byte) (uint64, error) {
+		job := &Job{}
+		if err := json.Unmarshal(data, job); err != nil {
+			return 0, errors.WithStack(err)
+		}
+		jobs[job.ID] = job
+		return job.ID, nil
+	})
+	if err != nil {
+		return err
+	}
+	m.jobs = jobs
+	m.ctx, m.cancel = context.WithCancel(m.s.serverLoopCtx)
+	m.gc()
+	for _, job := range m.jobs {
+		if job.Status != JobRunning {
+			continue
+		}
+		if _, ok := jobRunners[job.Type]; !ok {
+			log.Errorf("[job %d] failed to resume the job of unknown type %s", job.ID, job.Type)
+			continue
+		}
+		log.Infof("[job %d] resume %s job", job.ID, job.Type)
+		m.run(job)
+	}
+	return nil
+}
+
+// stop stops the running jobs without changing their status, so that they are
+// resumed by the next leader. It is called after the server loses leadership.
+func (m *jobManager) stop() {
+	m.Lock()
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.ctx, m.cancel = nil, nil
+	m.Unlock()
+	m.wg.Wait()
+}
+
+// gc deletes the finished jobs out of the retention.
+func (m *jobManager) gc() {
+	for id, job := range m.jobs {
+		if job.Status == JobRunning || job.Status == JobPaused || time.Since(job.FinishTime) < jobRetention {
+			continue
+		}
+		if err := m.kv.DeleteJob(id); err != nil {
+			log.Errorf("[job %d] failed to delete the finished job: %v", id, err)
+			continue
+		}
+		delete(m.jobs, id)
+	}
+}
+
+// create saves a job and runs it.
+func (m *jobManager) create(typ string, args, state interface{}) (*Job, error) {
+	if _, ok := jobRunners[typ]; !ok {
+		return nil, errors.Wrapf(ErrInvalidJob, "unknown job type %s", typ)
+	}
+	argsData, err := json.Marshal(args)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var stateData json.RawMessage
+	if state != nil {
+		if stateData, err = json.Marshal(state); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	if m.ctx == nil {
+		return nil, errors.WithStack(ErrNotLeader)
+	}
+	id, err := m.s.idAlloc.Alloc()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	job := &Job{
+		ID:         id,
+		Type:       typ,
+		Status:     JobRunning,
+		Args:       argsData,
+		State:      stateData,
+		CreateTime: now,
+		UpdateTime: now,
+	}
+	if err = m.kv.SaveJob(id, job); err != nil {
+		return nil, err
+	}
+	m.jobs[id] = job
+	m.gc()
+	log.Infof("[job %d] start %s job, args: %s", id, typ, argsData)
+	m.run(job)
+	return job.clone(), nil
+}
+
+// run runs the job in a goroutine, the lock should be held.
+func (m *jobManager) run(job *Job) {
+	ctx, cancel := context.WithCancel(m.ctx)
+	jc := &JobContext{Context: ctx, cancel: cancel, m: m, job: job.clone()}
+	m.runs[job.ID] = jc
+	runner := jobRunners[job.Type]
+	m.wg.Add(1)
+	go func() {
+		defer logutil.LogPanic()
+		defer m.wg.Done()
+		defer cancel()
+		err := runner(jc)
+		m.finish(jc, err)
+	}()
+}
+
+// finish records the result of the job, unless the job is canceled or paused,
+// or the leadership is lost.
+func (m *jobManager) finish(jc *JobContext, err error) {
+	m.Lock()
+	defer m.Unlock()
+	// The job is resumed by another runner.
+	if m.runs[jc.ID()] != jc {
+		return
+	}
+	delete(m.runs, jc.ID())
+	job, ok := m.jobs[jc.ID()]
+	if !ok || job.Status != JobRunning || m.ctx == nil || m.ctx.Err() != nil {
+		return
+	}
+	err = m.save(job, func(job *Job) {
+		if err != nil {
+			job.Status, job.Error = JobFailed, err.Error()
+		} else {
+			job.Status, job.Progress = JobFinished, 100
+		}
+		job.FinishTime = job.UpdateTime
+	})
+	if err != nil {
+		log.Errorf("[job %d] failed to save the result: %v", job.ID, err)
+		return
+	}
+	if job.Status == JobFailed {
+		log.Errorf("[job %d] %s job failed: %s", job.ID, job.Type, job.Error)
+	} else {
+		log.Infof("[job %d] %s job finished", job.ID, job.Type)
+	}
+}
+
+// save updates the job by f and saves it, the lock should be held. The job is
+// not changed if it fails to save. The update time is set before f is called,
+// so f can use it as the time of the update.
+func (m *jobManager) save(job *Job, f func(job *Job)) error {
+	updated := job.clone()
+	updated.UpdateTime = time.Now()
+	f(updated)
+	if err := m.kv.SaveJob(updated.ID, updated); err != nil {
+		return err
+	}
+	*job = *updated
+	return nil
+}
+
+func (m *jobManager) update(id uint64, f func(job *Job)) error {
+	m.Lock()
+	defer m.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return errors.WithStack(ErrJobNotFound)
+	}
+	if job.Status != JobRunning {
+		return errors.WithStack(ErrJobNotRunning)
+	}
+	return m.save(job, f)
+}
+
+// cancelJob cancels a running or paused job.
+func (m *jobManager) cancelJob(id uint64) (*Job, error) {
+	m.Lock()
+	defer m.Unlock()
+	job, err := m.getJob(id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != JobRunning && job.Status != JobPaused {
+		return nil, errors.WithStack(ErrJobNotRunning)
+	}
+	err = m.save(job, func(job *Job) {
+		job.Status = JobCanceled
+		job.FinishTime = job.UpdateTime
+	})
+	if err != nil {
+		return nil, err
+	}
+	m.stopRun(id)
+	log.Infof("[job %d] %s job is canceled", id, job.Type)
+	return job.clone(), nil
+}
+
+// pauseJob stops a running job and keeps its state. A paused job is not
+// resumed by the next leader until resumeJob is called.
+func (m *jobManager) pauseJob(id uint64) (*Job, error) {
+	m.Lock()
+	defer m.Unlock()
+	job, err := m.getJob(id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != JobRunning {
+		return nil, errors.WithStack(ErrJobNotRunning)
+	}
+	if err = m.save(job, func(job *Job) { job.Status = JobPaused }); err != nil {
+		return nil, err
+	}
+	m.stopRun(id)
+	log.Infof("[job %d] %s job is paused", id, job.Type)
+	return job.clone(), nil
+}
+
+// resumeJob runs a paused job with its saved state.
+func (m *jobManager) resumeJob(id uint64) (*Job, error) {
+	m.Lock()
+	defer m.Unlock()
+	job, err := m.getJob(id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != JobPaused {
+		return nil, errors.WithStack(ErrJobNotPaused)
+	}
+	if _, ok := jobRunners[job.Type]; !ok {
+		return nil, errors.Wrapf(ErrInvalidJob, "unknown job type %s", job.Type)
+	}
+	if err = m.save(job, func(job *Job) { job.Status = JobRunning }); err != nil {
+		return nil, err
+	}
+	m.run(job)
+	log.Infof("[job %d] %s job is resumed", id, job.Type)
+	return job.clone(), nil
+}
+
+// getJob returns the job to change, the lock should be held.
+func (m *jobManager) getJob(id uint64) (*Job, error) {
+	if m.ctx == nil {
+		return nil, errors.WithStack(ErrNotLeader)
+	}
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, errors.WithStack(ErrJobNotFound)
+	}
+	return job, nil
+}
+
+// stopRun cancels the runner of a job, the lock should be held.
+func (m *jobManager) stopRun(id uint64) {
+	if jc, ok := m.runs[id]; ok {
+		jc.cancel()
+		delete(m.runs, id)
+	}
+}
+
+func (m *jobManager) get(id uint64) (*Job, error) {
+	m.RLock()
+	defer m.RUnlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, errors.WithStack(ErrJobNotFound)
+	}
+	return job.clone(), nil
+}
+
+// list returns the jobs of the type ordered by ID, all jobs if typ is empty.
+func (m *jobManager) list(typ string) []*Job {
+	m.RLock()
+	defer m.RUnlock()
+	jobs := make([]*Job, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		if typ == "" || job.Type == typ {
+			jobs = append(jobs, job.clone())
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].ID < jobs[j].ID })
+	return jobs
+}
+
+// GetJob returns the job with the ID.
+func (s *Server) GetJob(id uint64) (*Job, error) {
+	return s.jobs.get(id)
+}
+
+// GetJobs returns the jobs of the type, all jobs if typ is empty.
+func (s *Server) GetJobs(typ string) []*Job {
+	return s.jobs.list(typ)
+}
+
+// CancelJob cancels a running or paused job.
+func (s *Server) CancelJob(id uint64) (*Job, error) {
+	return s.jobs.cancelJob(id)
+}
+
+// PauseJob pauses a running job.
+func (s *Server) PauseJob(id uint64) (*Job, error) {
+	return s.jobs.pauseJob(id)
+}
+
+// ResumeJob resumes a paused job.
+func (s *Server) ResumeJob(id uint64) (*Job, error) {
+	return s.jobs.resumeJob(id)
+}
@@ -0,0 +1,216 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/pd/pkg/testutil"
+	"github.com/pkg/errors"
+)
+
+var _ = Suite(&testJobSuite{})
+
+type testJobSuite struct{}
+
+type testJobArgs struct {
+	Steps int `json:"steps"`
+}
+
+type testJobState struct {
+	Step int `json:"step"`
+}
+
+// testJobSteps receives the steps to run from the test, and testJobResumed
+// reports the state a job starts with.
+var (
+	testJobSteps   = make(chan int)
+	testJobResumed = make(chan int, 10)
+)
+
+func init() {
+	registerJobRunner("test", func(jc *JobContext) error {
+		var args testJobArgs
+		if err := jc.Args(&args); err != nil {
+			return err
+		}
+		var state testJobState
+		if _, err := jc.State(&state); err != nil {
+			return err
+		}
+		testJobResumed <- state.Step
+		for state.Step < args.Steps {
+			select {
+			case step := <-testJobSteps:
+				if step < 0 {
+					return errors.New("step failed")
+				}
+				state.Step += step
+			case <-jc.Done():
+				return jc.Err()
+			}
+			if err := jc.Update(float64(state.Step)*100/float64(args.Steps), state); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *testJobSuite) TestJob(c *C) {
+	_, svr, cleanup, err := NewTestServer()
+	c.Assert(err, IsNil)
+	defer cleanup()
+	mustWaitLeader(c, []*Server{svr})
+
+	_, err = svr.jobs.create("unknown", nil, nil)
+	c.Assert(errors.Cause(err), Equals, ErrInvalidJob)
+
+	job, err := svr.jobs.create("test", &testJobArgs{Steps: 2}, nil)
+	c.Assert(err, IsNil)
+	c.Assert(job.Status, Equals, JobRunning)
+	c.Assert(<-testJobResumed, Equals, 0)
+	testJobSteps <- 1
+	testutil.WaitUntil(c, func(c *C) bool {
+		job, err = svr.GetJob(job.ID)
+		c.Assert(err, IsNil)
+		return job.Progress == 50
+	})
+
+	// The job is resumed with the saved state after the leader changes.
+	svr.jobs.stop()
+	job, err = svr.GetJob(job.ID)
+	c.Assert(err, IsNil)
+	c.Assert(job.Status, Equals, JobRunning)
+	c.Assert(svr.jobs.start(), IsNil)
+	c.Assert(<-testJobResumed, Equals, 1)
+	testJobSteps <- 1
+	testutil.WaitUntil(c, func(c *C) bool {
+		job, err = svr.GetJob(job.ID)
+		c.Assert(err, IsNil)
+		return job.Status == JobFinished
+	})
+	c.Assert(job.Progress, Equals, float64(100))
+	c.Assert(job.FinishTime.IsZero(), IsFalse)
+	c.Assert(job.FinishTime, Equals, job.UpdateTime)
+
+	// Failed job.
+	failed, err := svr.jobs.create("test", &testJobArgs{Steps: 2}, nil)
+	c.Assert(err, IsNil)
+	<-testJobResumed
+	testJobSteps <- -1
+	testutil.WaitUntil(c, func(c *C) bool {
+		failed, err = svr.GetJob(failed.ID)
+		c.Assert(err, IsNil)
+		return failed.Status == JobFailed
+	})
+	c.Assert(failed.Error, Equals, "step failed")
+
+	// Canceled job.
+	canceled, err := svr.jobs.create("test", &testJobArgs{Steps: 2}, nil)
+	c.Assert(err, IsNil)
+	<-testJobResumed
+	canceled, err = svr.CancelJob(canceled.ID)
+	c.Assert(err, IsNil)
+	c.Assert(canceled.Status, Equals, JobCanceled)
+	c.Assert(canceled.FinishTime, Equals, canceled.UpdateTime)
+	_, err = svr.CancelJob(canceled.ID)
+	c.Assert(errors.Cause(err), Equals, ErrJobNotRunning)
+	_, err = svr.CancelJob(canceled.ID + 100)
+	c.Assert(errors.Cause(err), Equals, ErrJobNotFound)
+
+	// The results are persisted.
+	m := newJobManager(svr)
+	c.Assert(m.start(), IsNil)
+	defer m.stop()
+	c.Assert(m.list("test"), HasLen, 3)
+	for _, j := range []*Job{job, failed, canceled} {
+		loaded, err := m.get(j.ID)
+		c.Assert(err, IsNil)
+		c.Assert(loaded.Status, Equals, j.Status)
+	}
+	c.Assert(svr.GetJobs("test"), HasLen, 3)
+	c.Assert(svr.GetJobs("unknown"), HasLen, 0)
+
+	// Finished jobs are deleted after the retention.
+	svr.jobs.Lock()
+	for _, j := range svr.jobs.jobs {
+		j.FinishTime = time.Now().Add(-jobRetention)
+	}
+	svr.jobs.gc()
+	svr.jobs.Unlock()
+	c.Assert(svr.GetJobs(""), HasLen, 0)
+}
+
+func (s *testJobSuite) TestPauseJob(c *C) {
+	_, svr, cleanup, err := NewTestServer()
+	c.Assert(err, IsNil)
+	defer cleanup()
+	mustWaitLeader(c, []*Server{svr})
+
+	job, err := svr.jobs.create("test", &testJobArgs{Steps: 2}, nil)
+	c.Assert(err, IsNil)
+	c.Assert(<-testJobResumed, Equals, 0)
+	testJobSteps <- 1
+	testutil.WaitUntil(c, func(c *C) bool {
+		job, err = svr.GetJob(job.ID)
+		c.Assert(err, IsNil)
+		return job.Progress == 50
+	})
+
+	_, err = svr.ResumeJob(job.ID)
+	c.Assert(errors.Cause(err), Equals, ErrJobNotPaused)
+	job, err = svr.PauseJob(job.ID)
+	c.Assert(err, IsNil)
+	c.Assert(job.Status, Equals, JobPaused)
+	_, err = svr.PauseJob(job.ID)
+	c.Assert(errors.Cause(err), Equals, ErrJobNotRunning)
+
+	// The paused job is not resumed after the leader changes.
+	svr.jobs.stop()
+	c.Assert(svr.jobs.start(), IsNil)
+	select {
+	case <-testJobResumed:
+		c.Fatal("the paused job is resumed")
+	case <-time.After(100 * time.Millisecond):
+	}
+	job, err = svr.GetJob(job.ID)
+	c.Assert(err, IsNil)
+	c.Assert(job.Status, Equals, JobPaused)
+
+	// The job is resumed with the saved state.
+	job, err = svr.ResumeJob(job.ID)
+	c.Assert(err, IsNil)
+	c.Assert(job.Status, Equals, JobRunning)
+	c.Assert(<-testJobResumed, Equals, 1)
+	testJobSteps <- 1
+	testutil.WaitUntil(c, func(c *C) bool {
+		job, err = svr.GetJob(job.ID)
+		c.Assert(err, IsNil)
+		return job.Status == JobFinished
+	})
+
+	// A paused job can be canceled.
+	paused, err := svr.jobs.create("test", &testJobArgs{Steps: 2}, nil)
+	c.Assert(err, IsNil)
+	<-testJobResumed
+	_, err = svr.PauseJob(paused.ID)
+	c.Assert(err, IsNil)
+	paused, err = svr.CancelJob(paused.ID)
+	c.Assert(err, IsNil)
+	c.Assert(paused.Status, Equals, JobCanceled)
+	_, err = svr.ResumeJob(paused.ID)
+	c.Assert(errors.Cause(err), Equals, ErrJobNotPaused)
+}
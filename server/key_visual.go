@@ -0,0 +1,71 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/pd/pkg/logutil"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/keyvisual"
+)
+
+const (
+	// keyVisualInterval is close to the region heartbeat interval of TiKV, so
+	// each snapshot records the flow reported by one round of heartbeats.
+	keyVisualInterval = time.Minute
+	// keyVisualRetention is the time range of the snapshots kept in memory.
+	keyVisualRetention = 24 * time.Hour
+	// keyVisualMaxSpans is the max number of key ranges in a snapshot, adjacent
+	// regions are merged to bound the memory usage.
+	keyVisualMaxSpans = 256
+)
+
+func newKeyVisualStat() *keyvisual.Stat {
+	return keyvisual.NewStat(int(keyVisualRetention/keyVisualInterval), keyVisualMaxSpans)
+}
+
+func (c *RaftCluster) runKeyVisual(interval time.Duration) {
+	defer logutil.LogPanic()
+	defer c.wg.Done()
+
+	// The regions are scanned again only if some region is changed since the
+	// last scan, the flow of a region is not changed without an update.
+	changed := int32(1)
+	regionsInfo := c.cachedCluster.core.Regions
+	id := regionsInfo.Subscribe(func(*core.RegionEvent) { atomic.StoreInt32(&changed, 1) })
+	defer regionsInfo.Unsubscribe(id)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var regions []*core.RegionInfo
+	for {
+		select {
+		case <-c.quit:
+			return
+		case <-ticker.C:
+			if atomic.SwapInt32(&changed, 0) == 1 {
+				regions = c.cachedCluster.getRegions()
+			}
+			c.keyVisual.Append(time.Now(), regions)
+		}
+	}
+}
+
+// GetKeyVisualHeatmap returns the heatmap of the region flow over key ranges.
+func (c *RaftCluster) GetKeyVisualHeatmap(q *keyvisual.Query) *keyvisual.Matrix {
+	return c.keyVisual.Heatmap(q)
+}
@@ -0,0 +1,344 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pingcap/pd/pkg/keyspacepb"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// maxKeyspaceID is the max ID of keyspaces. The keyspace ID is encoded in 3
+// bytes after the mode prefix of keys.
+const maxKeyspaceID = 1<<24 - 1
+
+var keyspaceNameRegexp = regexp.MustCompile(`^[\w-]{1,64}$`)
+
+var (
+	// ErrKeyspaceNotFound is error info for keyspace not found.
+	ErrKeyspaceNotFound = errors.New("keyspace not found")
+	// ErrKeyspaceExists is error info for creating a keyspace with a used name.
+	ErrKeyspaceExists = errors.New("keyspace already exists")
+	// ErrInvalidKeyspace is error info for invalid keyspace arguments.
+	ErrInvalidKeyspace = errors.New("invalid keyspace argument")
+)
+
+// keyspaceStateTransitions are the allowed state changes of keyspaces.
+var keyspaceStateTransitions = map[keyspacepb.KeyspaceState][]keyspacepb.KeyspaceState{
+	keyspacepb.KeyspaceState_ENABLED:  {keyspacepb.KeyspaceState_DISABLED},
+	keyspacepb.KeyspaceState_DISABLED: {keyspacepb.KeyspaceState_ENABLED, keyspacepb.KeyspaceState_ARCHIVED},
+}
+
+// keyspaceManager manages the keyspaces of a multi-tenant cluster. Keyspace
+// IDs are allocated from a counter of their own, so that they are dense and
+// never reused.
+type keyspaceManager struct {
+	sync.Mutex
+	kv *core.KV
+}
+
+func newKeyspaceManager(kv *core.KV) *keyspaceManager {
+	return &keyspaceManager{kv: kv}
+}
+
+func (m *keyspaceManager) load(name string) (*keyspacepb.KeyspaceMeta, error) {
+	id, ok, err := m.kv.LoadKeyspaceID(name)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.Wrapf(ErrKeyspaceNotFound, "keyspace %s", name)
+	}
+	meta := &keyspacepb.KeyspaceMeta{}
+	ok, err = m.kv.LoadKeyspace(id, meta)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.Wrapf(ErrKeyspaceNotFound, "keyspace %s", name)
+	}
+	return meta, nil
+}
+
+// create allocates an ID for the keyspace and saves its metadata.
+func (m *keyspaceManager) create(name string, config map[string]string) (*keyspacepb.KeyspaceMeta, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	if !keyspaceNameRegexp.MatchString(name) {
+		return nil, errors.Wrapf(ErrInvalidKeyspace, "name %q should be 1-64 characters of 0-9, a-z, A-Z, _ or -", name)
+	}
+	if _, ok, err := m.kv.LoadKeyspaceID(name); err != nil {
+		return nil, err
+	} else if ok {
+		return nil, errors.Wrapf(ErrKeyspaceExists, "keyspace %s", name)
+	}
+	id, err := m.allocID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	meta := &keyspacepb.KeyspaceMeta{
+		Id:             id,
+		Name:           name,
+		State:          keyspacepb.KeyspaceState_ENABLED,
+		CreatedAt:      now,
+		StateChangedAt: now,
+		Config:         config,
+	}
+	if err = m.kv.SaveKeyspace(meta.GetId(), name, meta); err != nil {
+		return nil, err
+	}
+	log.Infof("keyspace %s is created with ID %d", name, meta.GetId())
+	return meta, nil
+}
+
+// allocID allocates the next keyspace ID. The counter is saved before the
+// keyspace, so an ID is not reused even if saving the keyspace fails. Without
+// the counter, IDs start after the largest ID of the existing keyspaces.
+func (m *keyspaceManager) allocID() (uint32, error) {
+	id, ok, err := m.kv.LoadKeyspaceNextID()
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		id = 1
+		err = m.kv.LoadKeyspaces(func(data []byte) (uint32, error) {
+			meta := &keyspacepb.KeyspaceMeta{}
+			if err := meta.Unmarshal(data); err != nil {
+				return 0, errors.WithStack(err)
+			}
+			if meta.GetId() >= id {
+				id = meta.GetId() + 1
+			}
+			return meta.GetId(), nil
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	if id > maxKeyspaceID {
+		return 0, errors.Errorf("keyspace IDs are exhausted, the max ID is %d", maxKeyspaceID)
+	}
+	if err = m.kv.SaveKeyspaceNextID(id + 1); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// get returns the metadata of the keyspace.
+func (m *keyspaceManager) get(name string) (*keyspacepb.KeyspaceMeta, error) {
+	m.Lock()
+	defer m.Unlock()
+	return m.load(name)
+}
+
+// list returns the metadata of all keyspaces ordered by ID.
+func (m *keyspaceManager) list() ([]*keyspacepb.KeyspaceMeta, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	var keyspaces []*keyspacepb.KeyspaceMeta
+	err := m.kv.LoadKeyspaces(func(data []byte) (uint32, error) {
+		meta := &keyspacepb.KeyspaceMeta{}
+		if err := meta.Unmarshal(data); err != nil {
+			return 0, errors.WithStack(err)
+		}
+		keyspaces = append(keyspaces, meta)
+		return meta.GetId(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(keyspaces, func(i, j int) bool { return keyspaces[i].GetId() < keyspaces[j].GetId() })
+	return keyspaces, nil
+}
+
+// updateState changes the state of the keyspace. An archived keyspace can not
+// be changed any more.
+func (m *keyspaceManager) updateState(name string, state keyspacepb.KeyspaceState) (*keyspacepb.KeyspaceMeta, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	meta, err := m.load(name)
+	if err != nil {
+		return nil, err
+	}
+	if meta.GetState() == state {
+		return meta, nil
+	}
+	allowed := false
+	for _, s := range keyspaceStateTransitions[meta.GetState()] {
+		if s == state {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return nil, errors.Wrapf(ErrInvalidKeyspace, "cannot change state of keyspace %s from %s to %s", name, meta.GetState(), state)
+	}
+
+	old := meta.GetState()
+	meta.State = state
+	meta.StateChangedAt = time.Now().Unix()
+	if err = m.kv.SaveKeyspace(meta.GetId(), name, meta); err != nil {
+		return nil, err
+	}
+	log.Infof("state of keyspace %s is changed from %s to %s", name, old, state)
+	return meta, nil
+}
+
+// updateConfig puts and removes the config overrides of the keyspace.
+func (m *keyspaceManager) updateConfig(name string, put map[string]string, remove []string) (*keyspacepb.KeyspaceMeta, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	meta, err := m.load(name)
+	if err != nil {
+		return nil, err
+	}
+	if meta.GetState() == keyspacepb.KeyspaceState_ARCHIVED {
+		return nil, errors.Wrapf(ErrInvalidKeyspace, "keyspace %s is archived", name)
+	}
+
+	if meta.Config == nil {
+		meta.Config = make(map[string]string)
+	}
+	for k, v := range put {
+		meta.Config[k] = v
+	}
+	for _, k := range remove {
+		delete(meta.Config, k)
+	}
+	if err = m.kv.SaveKeyspace(meta.GetId(), name, meta); err != nil {
+		return nil, err
+	}
+	log.Infof("config of keyspace %s is updated, put %v, remove %v", name, put, remove)
+	return meta, nil
+}
+
+// CreateKeyspace creates a keyspace with the config overrides.
+func (s *Server) CreateKeyspace(name string, config map[string]string) (*keyspacepb.KeyspaceMeta, error) {
+	return s.keyspaceManager.create(name, config)
+}
+
+// GetKeyspace returns the metadata of a keyspace.
+func (s *Server) GetKeyspace(name string) (*keyspacepb.KeyspaceMeta, error) {
+	return s.keyspaceManager.get(name)
+}
+
+// GetKeyspaces returns the metadata of all keyspaces.
+func (s *Server) GetKeyspaces() ([]*keyspacepb.KeyspaceMeta, error) {
+	return s.keyspaceManager.list()
+}
+
+// UpdateKeyspaceState changes the state of a keyspace.
+func (s *Server) UpdateKeyspaceState(name string, state keyspacepb.KeyspaceState) (*keyspacepb.KeyspaceMeta, error) {
+	return s.keyspaceManager.updateState(name, state)
+}
+
+// UpdateKeyspaceConfig puts and removes the config overrides of a keyspace.
+func (s *Server) UpdateKeyspaceConfig(name string, put map[string]string, remove []string) (*keyspacepb.KeyspaceMeta, error) {
+	return s.keyspaceManager.updateConfig(name, put, remove)
+}
+
+// keyspaceService implements gRPC KeyspaceServer.
+type keyspaceService struct {
+	s *Server
+}
+
+func keyspaceStatus(err error) *keyspacepb.Status {
+	if err == nil {
+		return &keyspacepb.Status{Code: keyspacepb.StatusCode_OK}
+	}
+	code := keyspacepb.StatusCode_UNKNOWN
+	switch errors.Cause(err) {
+	case ErrKeyspaceNotFound:
+		code = keyspacepb.StatusCode_KEYSPACE_NOT_FOUND
+	case ErrKeyspaceExists:
+		code = keyspacepb.StatusCode_KEYSPACE_EXISTS
+	case ErrInvalidKeyspace:
+		code = keyspacepb.StatusCode_INVALID_ARGUMENT
+	}
+	return &keyspacepb.Status{Code: code, Message: err.Error()}
+}
+
+func (k *keyspaceService) keyspaceResponse(meta *keyspacepb.KeyspaceMeta, err error) *keyspacepb.KeyspaceResponse {
+	return &keyspacepb.KeyspaceResponse{
+		Header:   k.s.header(),
+		Status:   keyspaceStatus(err),
+		Keyspace: meta,
+	}
+}
+
+// Create implements gRPC KeyspaceServer.
+func (k *keyspaceService) Create(ctx context.Context, request *keyspacepb.CreateRequest) (*keyspacepb.KeyspaceResponse, error) {
+	if err := k.s.validateRequest(request.GetHeader()); err != nil {
+		return nil, err
+	}
+	if err := k.s.checkMaintenance(); err != nil {
+		return nil, err
+	}
+	return k.keyspaceResponse(k.s.CreateKeyspace(request.GetName(), request.GetConfig())), nil
+}
+
+// Load implements gRPC KeyspaceServer.
+func (k *keyspaceService) Load(ctx context.Context, request *keyspacepb.LoadRequest) (*keyspacepb.KeyspaceResponse, error) {
+	if err := k.s.validateRequest(request.GetHeader()); err != nil {
+		return nil, err
+	}
+	return k.keyspaceResponse(k.s.GetKeyspace(request.GetName())), nil
+}
+
+// List implements gRPC KeyspaceServer.
+func (k *keyspaceService) List(ctx context.Context, request *keyspacepb.ListRequest) (*keyspacepb.ListResponse, error) {
+	if err := k.s.validateRequest(request.GetHeader()); err != nil {
+		return nil, err
+	}
+	keyspaces, err := k.s.GetKeyspaces()
+	return &keyspacepb.ListResponse{
+		Header:    k.s.header(),
+		Status:    keyspaceStatus(err),
+		Keyspaces: keyspaces,
+	}, nil
+}
+
+// UpdateState implements gRPC KeyspaceServer.
+func (k *keyspaceService) UpdateState(ctx context.Context, request *keyspacepb.UpdateStateRequest) (*keyspacepb.KeyspaceResponse, error) {
+	if err := k.s.validateRequest(request.GetHeader()); err != nil {
+		return nil, err
+	}
+	if err := k.s.checkMaintenance(); err != nil {
+		return nil, err
+	}
+	return k.keyspaceResponse(k.s.UpdateKeyspaceState(request.GetName(), request.GetState())), nil
+}
+
+// UpdateConfig implements gRPC KeyspaceServer.
+func (k *keyspaceService) UpdateConfig(ctx context.Context, request *keyspacepb.UpdateConfigRequest) (*keyspacepb.KeyspaceResponse, error) {
+	if err := k.s.validateRequest(request.GetHeader()); err != nil {
+		return nil, err
+	}
+	if err := k.s.checkMaintenance(); err != nil {
+		return nil, err
+	}
+	return k.keyspaceResponse(k.s.UpdateKeyspaceConfig(request.GetName(), request.GetPut(), request.GetRemove())), nil
+}
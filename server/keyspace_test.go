@@ -0,0 +1,121 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/pd/pkg/keyspacepb"
+	"github.com/pingcap/pd/server/core"
+	"google.golang.org/grpc"
+)
+
+var _ = Suite(&testKeyspaceSuite{})
+
+type testKeyspaceSuite struct{}
+
+func (s *testKeyspaceSuite) TestKeyspaceService(c *C) {
+	_, svr, cleanup, err := NewTestServer()
+	c.Assert(err, IsNil)
+	defer cleanup()
+	mustWaitLeader(c, []*Server{svr})
+
+	conn, err := grpc.Dial(strings.TrimPrefix(svr.GetAddr(), "http://"), grpc.WithInsecure())
+	c.Assert(err, IsNil)
+	defer conn.Close()
+	client := keyspacepb.NewKeyspaceClient(conn)
+	header := newRequestHeader(svr.clusterID)
+	ctx := context.Background()
+
+	resp, err := client.Create(ctx, &keyspacepb.CreateRequest{Header: header, Name: "ks1", Config: map[string]string{"a": "1"}})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetStatus().GetCode(), Equals, keyspacepb.StatusCode_OK)
+	ks1 := resp.GetKeyspace()
+	c.Assert(ks1.GetId(), Equals, uint32(1))
+	c.Assert(ks1.GetState(), Equals, keyspacepb.KeyspaceState_ENABLED)
+	c.Assert(ks1.GetConfig(), DeepEquals, map[string]string{"a": "1"})
+
+	resp, err = client.Create(ctx, &keyspacepb.CreateRequest{Header: header, Name: "ks1"})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetStatus().GetCode(), Equals, keyspacepb.StatusCode_KEYSPACE_EXISTS)
+	resp, err = client.Create(ctx, &keyspacepb.CreateRequest{Header: header, Name: "ks/2"})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetStatus().GetCode(), Equals, keyspacepb.StatusCode_INVALID_ARGUMENT)
+	resp, err = client.Create(ctx, &keyspacepb.CreateRequest{Header: header, Name: "ks2"})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetStatus().GetCode(), Equals, keyspacepb.StatusCode_OK)
+	c.Assert(resp.GetKeyspace().GetId(), Equals, ks1.GetId()+1)
+
+	resp, err = client.Load(ctx, &keyspacepb.LoadRequest{Header: header, Name: "ks1"})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetKeyspace().GetId(), Equals, ks1.GetId())
+	resp, err = client.Load(ctx, &keyspacepb.LoadRequest{Header: header, Name: "ks3"})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetStatus().GetCode(), Equals, keyspacepb.StatusCode_KEYSPACE_NOT_FOUND)
+
+	listResp, err := client.List(ctx, &keyspacepb.ListRequest{Header: header})
+	c.Assert(err, IsNil)
+	c.Assert(listResp.GetKeyspaces(), HasLen, 2)
+	c.Assert(listResp.GetKeyspaces()[0].GetName(), Equals, "ks1")
+	c.Assert(listResp.GetKeyspaces()[1].GetName(), Equals, "ks2")
+
+	resp, err = client.UpdateConfig(ctx, &keyspacepb.UpdateConfigRequest{Header: header, Name: "ks1",
+		Put: map[string]string{"b": "2"}, Remove: []string{"a"}})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetKeyspace().GetConfig(), DeepEquals, map[string]string{"b": "2"})
+
+	// An enabled keyspace must be disabled before archived.
+	resp, err = client.UpdateState(ctx, &keyspacepb.UpdateStateRequest{Header: header, Name: "ks1", State: keyspacepb.KeyspaceState_ARCHIVED})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetStatus().GetCode(), Equals, keyspacepb.StatusCode_INVALID_ARGUMENT)
+	for _, state := range []keyspacepb.KeyspaceState{keyspacepb.KeyspaceState_DISABLED, keyspacepb.KeyspaceState_ARCHIVED} {
+		resp, err = client.UpdateState(ctx, &keyspacepb.UpdateStateRequest{Header: header, Name: "ks1", State: state})
+		c.Assert(err, IsNil)
+		c.Assert(resp.GetStatus().GetCode(), Equals, keyspacepb.StatusCode_OK)
+		c.Assert(resp.GetKeyspace().GetState(), Equals, state)
+	}
+	resp, err = client.UpdateState(ctx, &keyspacepb.UpdateStateRequest{Header: header, Name: "ks1", State: keyspacepb.KeyspaceState_ENABLED})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetStatus().GetCode(), Equals, keyspacepb.StatusCode_INVALID_ARGUMENT)
+	resp, err = client.UpdateConfig(ctx, &keyspacepb.UpdateConfigRequest{Header: header, Name: "ks1", Put: map[string]string{"a": "1"}})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetStatus().GetCode(), Equals, keyspacepb.StatusCode_INVALID_ARGUMENT)
+
+	// The name of an archived keyspace is not reused.
+	resp, err = client.Create(ctx, &keyspacepb.CreateRequest{Header: header, Name: "ks1"})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetStatus().GetCode(), Equals, keyspacepb.StatusCode_KEYSPACE_EXISTS)
+}
+
+func (s *testKeyspaceSuite) TestAllocID(c *C) {
+	kv := core.NewKV(core.NewMemoryKV())
+	// The keyspaces created before the counter.
+	c.Assert(kv.SaveKeyspace(5, "ks5", &keyspacepb.KeyspaceMeta{Id: 5, Name: "ks5"}), IsNil)
+	m := newKeyspaceManager(kv)
+	for _, id := range []uint32{6, 7} {
+		meta, err := m.create(fmt.Sprintf("ks%d", id), nil)
+		c.Assert(err, IsNil)
+		c.Assert(meta.GetId(), Equals, id)
+	}
+
+	c.Assert(kv.SaveKeyspaceNextID(maxKeyspaceID), IsNil)
+	meta, err := m.create("last", nil)
+	c.Assert(err, IsNil)
+	c.Assert(meta.GetId(), Equals, uint32(maxKeyspaceID))
+	_, err = m.create("exhausted", nil)
+	c.Assert(err, NotNil)
+}
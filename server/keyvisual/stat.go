@@ -0,0 +1,233 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyvisual
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pingcap/pd/server/core"
+)
+
+// Tags of the flow.
+const (
+	WrittenBytes = "written_bytes"
+	ReadBytes    = "read_bytes"
+)
+
+// span is the flow of a key range. An empty end key means the end of the key
+// space.
+type span struct {
+	startKey, endKey []byte
+	writtenBytes     uint64
+	readBytes        uint64
+}
+
+func (s *span) value(tag string) uint64 {
+	if tag == ReadBytes {
+		return s.readBytes
+	}
+	return s.writtenBytes
+}
+
+// snapshot is the flow of the key space at a point of time.
+type snapshot struct {
+	time  time.Time
+	spans []span
+}
+
+// newSnapshot compacts the regions into at most maxSpans spans, each of which
+// consists of the same number of adjacent regions.
+func newSnapshot(t time.Time, regions []*core.RegionInfo, maxSpans int) *snapshot {
+	sorted := make([]*core.RegionInfo, len(regions))
+	copy(sorted, regions)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].GetStartKey(), sorted[j].GetStartKey()) < 0
+	})
+
+	n := len(sorted)
+	if n > maxSpans {
+		n = maxSpans
+	}
+	snap := &snapshot{time: t, spans: make([]span, 0, n)}
+	for i := 0; i < n; i++ {
+		from, to := i*len(sorted)/n, (i+1)*len(sorted)/n
+		s := span{
+			startKey: sorted[from].GetStartKey(),
+			endKey:   sorted[to-1].GetEndKey(),
+		}
+		for _, region := range sorted[from:to] {
+			s.writtenBytes += region.GetBytesWritten()
+			s.readBytes += region.GetBytesRead()
+		}
+		snap.spans = append(snap.spans, s)
+	}
+	return snap
+}
+
+// Stat keeps the flow of the key space in a ring buffer of snapshots.
+type Stat struct {
+	sync.RWMutex
+	maxSpans  int
+	snapshots []*snapshot
+	// next is the position of the next snapshot in the ring buffer.
+	next int
+}
+
+// NewStat creates a Stat which keeps at most capacity snapshots, the key
+// space is divided into at most maxSpans spans in each snapshot.
+func NewStat(capacity, maxSpans int) *Stat {
+	return &Stat{
+		maxSpans:  maxSpans,
+		snapshots: make([]*snapshot, 0, capacity),
+	}
+}
+
+// Append records the flow of the regions at the time. The oldest snapshot is
+// dropped if the buffer is full.
+func (s *Stat) Append(t time.Time, regions []*core.RegionInfo) {
+	snap := newSnapshot(t, regions, s.maxSpans)
+	s.Lock()
+	defer s.Unlock()
+	if len(s.snapshots) < cap(s.snapshots) {
+		s.snapshots = append(s.snapshots, snap)
+		return
+	}
+	s.snapshots[s.next] = snap
+	s.next = (s.next + 1) % len(s.snapshots)
+}
+
+// Query is the range of the heatmap.
+type Query struct {
+	StartKey, EndKey   []byte
+	StartTime, EndTime time.Time
+	// Buckets is the max number of key ranges of the heatmap.
+	Buckets int
+	Tag     string
+}
+
+// Matrix is the heatmap of the flow. Data[i][j] is the flow in the key range
+// from Keys[j] to Keys[j+1] at Times[i].
+type Matrix struct {
+	Keys  []string    `json:"keys"`
+	Times []time.Time `json:"times"`
+	Data  [][]uint64  `json:"data"`
+}
+
+// Heatmap aggregates the flow in the snapshots within the time range into the
+// buckets of the key range.
+func (s *Stat) Heatmap(q *Query) *Matrix {
+	s.RLock()
+	var snapshots []*snapshot
+	for i := range s.snapshots {
+		snap := s.snapshots[(s.next+i)%len(s.snapshots)]
+		if snap.time.Before(q.StartTime) || (!q.EndTime.IsZero() && snap.time.After(q.EndTime)) {
+			continue
+		}
+		snapshots = append(snapshots, snap)
+	}
+	s.RUnlock()
+
+	axis := buildAxis(q, snapshots)
+	m := &Matrix{
+		Keys:  make([]string, 0, len(axis)),
+		Times: make([]time.Time, 0, len(snapshots)),
+		Data:  make([][]uint64, 0, len(snapshots)),
+	}
+	for _, key := range axis {
+		m.Keys = append(m.Keys, string(core.HexRegionKey(key)))
+	}
+	for _, snap := range snapshots {
+		m.Times = append(m.Times, snap.time)
+		m.Data = append(m.Data, fillBuckets(axis, q, snap, q.Tag))
+	}
+	return m
+}
+
+// inRange returns true if the key is strictly inside the key range of q.
+func inRange(q *Query, key []byte) bool {
+	return bytes.Compare(key, q.StartKey) > 0 && (len(q.EndKey) == 0 || bytes.Compare(key, q.EndKey) < 0)
+}
+
+// buildAxis returns the boundaries of the buckets. The boundaries of the spans
+// are merged so that each bucket covers the same number of them.
+func buildAxis(q *Query, snapshots []*snapshot) [][]byte {
+	keys := make(map[string]struct{})
+	for _, snap := range snapshots {
+		for _, s := range snap.spans {
+			for _, key := range [][]byte{s.startKey, s.endKey} {
+				if len(key) > 0 && inRange(q, key) {
+					keys[string(key)] = struct{}{}
+				}
+			}
+		}
+	}
+	inner := make([]string, 0, len(keys))
+	for key := range keys {
+		inner = append(inner, key)
+	}
+	sort.Strings(inner)
+
+	buckets := len(inner) + 1
+	if q.Buckets > 0 && buckets > q.Buckets {
+		buckets = q.Buckets
+	}
+	axis := make([][]byte, 0, buckets+1)
+	axis = append(axis, q.StartKey)
+	for i := 1; i < buckets; i++ {
+		axis = append(axis, []byte(inner[i*(len(inner)+1)/buckets-1]))
+	}
+	return append(axis, q.EndKey)
+}
+
+// fillBuckets distributes the flow of each span evenly to the buckets it
+// overlaps.
+func fillBuckets(axis [][]byte, q *Query, snap *snapshot, tag string) []uint64 {
+	n := len(axis) - 1
+	data := make([]uint64, n)
+	// upperAbove returns true if the upper bound of the bucket is greater than
+	// the start key, or not less than the end key.
+	upperAbove := func(bucket int, key []byte, isEnd bool) bool {
+		upper := axis[bucket+1]
+		if bucket == n-1 && len(upper) == 0 {
+			return true
+		}
+		if isEnd {
+			return len(key) > 0 && bytes.Compare(upper, key) >= 0
+		}
+		return bytes.Compare(upper, key) > 0
+	}
+	for i := range snap.spans {
+		s := &snap.spans[i]
+		if (len(s.endKey) > 0 && bytes.Compare(s.endKey, q.StartKey) <= 0) ||
+			(len(q.EndKey) > 0 && bytes.Compare(s.startKey, q.EndKey) >= 0) {
+			continue
+		}
+		first := sort.Search(n, func(b int) bool { return upperAbove(b, s.startKey, false) })
+		last := sort.Search(n, func(b int) bool { return upperAbove(b, s.endKey, true) })
+		if last >= n {
+			last = n - 1
+		}
+		if first > last {
+			continue
+		}
+		v := s.value(tag) / uint64(last-first+1)
+		for b := first; b <= last; b++ {
+			data[b] += v
+		}
+	}
+	return data
+}
@@ -0,0 +1,79 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyvisual
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/server/core"
+)
+
+func TestKeyVisual(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testStatSuite{})
+
+type testStatSuite struct{}
+
+func newTestRegion(start, end string, written uint64) *core.RegionInfo {
+	meta := &metapb.Region{StartKey: []byte(start), EndKey: []byte(end)}
+	return core.NewRegionInfo(meta, nil, core.SetWrittenBytes(written), core.SetReadBytes(written*2))
+}
+
+func (s *testStatSuite) TestSnapshot(c *C) {
+	regions := []*core.RegionInfo{
+		newTestRegion("c", "", 1),
+		newTestRegion("", "a", 2),
+		newTestRegion("b", "c", 3),
+		newTestRegion("a", "b", 4),
+	}
+	snap := newSnapshot(time.Now(), regions, 2)
+	c.Assert(snap.spans, DeepEquals, []span{
+		{startKey: []byte(""), endKey: []byte("b"), writtenBytes: 6, readBytes: 12},
+		{startKey: []byte("b"), endKey: []byte(""), writtenBytes: 4, readBytes: 8},
+	})
+	c.Assert(newSnapshot(time.Now(), regions, 10).spans, HasLen, 4)
+}
+
+func (s *testStatSuite) TestHeatmap(c *C) {
+	stat := NewStat(2, 10)
+	start := time.Now()
+	stat.Append(start, []*core.RegionInfo{newTestRegion("", "b", 10), newTestRegion("b", "", 20)})
+	stat.Append(start.Add(time.Minute), []*core.RegionInfo{newTestRegion("", "a", 10), newTestRegion("a", "", 20)})
+
+	m := stat.Heatmap(&Query{Tag: WrittenBytes})
+	c.Assert(m.Keys, DeepEquals, []string{"", "61", "62", ""})
+	c.Assert(m.Times, HasLen, 2)
+	c.Assert(m.Data, DeepEquals, [][]uint64{{5, 5, 20}, {10, 10, 10}})
+
+	// Buckets are merged.
+	m = stat.Heatmap(&Query{Tag: ReadBytes, Buckets: 2})
+	c.Assert(m.Keys, DeepEquals, []string{"", "61", ""})
+	c.Assert(m.Data, DeepEquals, [][]uint64{{10, 50}, {20, 40}})
+
+	// The key range and the time range.
+	m = stat.Heatmap(&Query{StartKey: []byte("a"), EndKey: []byte("c"), StartTime: start.Add(time.Second), Tag: WrittenBytes})
+	c.Assert(m.Keys, DeepEquals, []string{"61", "63"})
+	c.Assert(m.Data, DeepEquals, [][]uint64{{20}})
+
+	// The oldest snapshot is dropped.
+	stat.Append(start.Add(2*time.Minute), []*core.RegionInfo{newTestRegion("", "", 30)})
+	m = stat.Heatmap(&Query{Tag: WrittenBytes})
+	c.Assert(m.Times, DeepEquals, []time.Time{start.Add(time.Minute), start.Add(2 * time.Minute)})
+	c.Assert(m.Data, DeepEquals, [][]uint64{{10, 20}, {15, 15}})
+}
@@ -19,6 +19,7 @@ import (
 	"math/rand"
 	"path"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/coreos/etcd/clientv3"
@@ -26,6 +27,7 @@ import (
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/pd/pkg/etcdutil"
 	"github.com/pingcap/pd/pkg/logutil"
+	"github.com/pingcap/pd/server/core"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
@@ -82,7 +84,9 @@ func (s *Server) leaderLoop() {
 			continue
 		}
 
-		leader, rev, err := getLeader(s.client, s.getLeaderPath())
+		ctx, cancel := s.readContext(s.client.Ctx())
+		leader, rev, err := getLeader(ctx, s.client, s.getLeaderPath())
+		cancel()
 		if err != nil {
 			log.Errorf("get leader err %v", err)
 			time.Sleep(200 * time.Millisecond)
@@ -112,6 +116,16 @@ func (s *Server) leaderLoop() {
 			continue
 		}
 
+		if err = s.checkBinaryVersion(); err != nil {
+			log.Errorf("refuse to campaign leader: %v", err)
+			// Let the other members campaign.
+			if err = s.ResignLeader(""); err != nil {
+				log.Errorf("failed to transfer etcd leader: %v", err)
+			}
+			time.Sleep(time.Second)
+			continue
+		}
+
 		if err = s.campaignLeader(); err != nil {
 			log.Errorf("campaign leader err %s", fmt.Sprintf("%+v", err))
 		}
@@ -128,7 +142,13 @@ func (s *Server) etcdLeaderLoop() {
 		select {
 		case <-time.After(s.cfg.LeaderPriorityCheckInterval.Duration):
 			etcdLeader := s.GetEtcdLeader()
-			if etcdLeader == s.ID() || etcdLeader == 0 {
+			if etcdLeader == 0 {
+				break
+			}
+			if etcdLeader == s.ID() {
+				if s.IsLeader() {
+					s.checkLeaderPreferredZone()
+				}
 				break
 			}
 			myPriority, err := s.GetMemberLeaderPriority(s.ID())
@@ -141,7 +161,7 @@ func (s *Server) etcdLeaderLoop() {
 				log.Errorf("failed to load leader priority: %v", err)
 				break
 			}
-			if myPriority > leaderPriority {
+			if myPriority > leaderPriority && !s.isLeaderZonePreferred(etcdLeader) {
 				err := s.etcd.Server.MoveLeader(ctx, etcdLeader, s.ID())
 				if err != nil {
 					log.Errorf("failed to transfer etcd leader: %v", err)
@@ -157,9 +177,9 @@ func (s *Server) etcdLeaderLoop() {
 }
 
 // getLeader gets server leader from etcd.
-func getLeader(c *clientv3.Client, leaderPath string) (*pdpb.Member, int64, error) {
+func getLeader(ctx context.Context, c *clientv3.Client, leaderPath string) (*pdpb.Member, int64, error) {
 	leader := &pdpb.Member{}
-	ok, rev, err := getProtoMsgWithModRev(c, leaderPath, leader)
+	ok, rev, err := getProtoMsgWithModRev(ctx, c, leaderPath, leader)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -183,7 +203,7 @@ func (s *Server) memberInfo() (member *pdpb.Member, marshalStr string) {
 	leader := &pdpb.Member{
 		Name:       s.Name(),
 		MemberId:   s.ID(),
-		ClientUrls: strings.Split(s.cfg.AdvertiseClientUrls, ","),
+		ClientUrls: strings.Split(s.GetAddr(), ","),
 		PeerUrls:   strings.Split(s.cfg.AdvertisePeerUrls, ","),
 	}
 
@@ -196,6 +216,41 @@ func (s *Server) memberInfo() (member *pdpb.Member, marshalStr string) {
 	return leader, string(data)
 }
 
+// ErrDowngrade is error info for campaigning leader with a binary version lower
+// than the cluster version.
+var ErrDowngrade = errors.New("downgrade is not allowed")
+
+// checkBinaryVersion returns an error if the binary version of current PD is
+// lower than the persisted cluster version and the downgrade is not allowed.
+func (s *Server) checkBinaryVersion() error {
+	if s.cfg.AllowDowngrade {
+		return nil
+	}
+	return checkDowngrade(s.kv, PDReleaseVersion)
+}
+
+// checkDowngrade returns ErrDowngrade if the binary version is lower than the
+// cluster version in kv. The binary version is unknown in the builds without
+// version information, which are never checked.
+func checkDowngrade(kv *core.KV, binaryVersion string) error {
+	if binaryVersion == "None" {
+		return nil
+	}
+	v, err := ParseVersion(binaryVersion)
+	if err != nil {
+		return err
+	}
+	cfg := &Config{}
+	isExist, err := kv.LoadConfig(cfg)
+	if err != nil || !isExist {
+		return err
+	}
+	if v.LessThan(cfg.ClusterVersion) {
+		return errors.Wrapf(ErrDowngrade, "binary version %s is lower than cluster version %s", v, cfg.ClusterVersion)
+	}
+	return nil
+}
+
 func (s *Server) campaignLeader() error {
 	log.Debugf("begin to campaign leader %s", s.Name())
 
@@ -227,6 +282,9 @@ func (s *Server) campaignLeader() error {
 	if !resp.Succeeded {
 		return errors.New("campaign leader failed, other server may campaign ok")
 	}
+	atomic.StoreInt32(&s.resigning, 0)
+	s.updateLeaderLease(leaseResp.TTL, start.Add(time.Duration(leaseResp.TTL)*time.Second))
+	defer s.leaderLease.Store((*LeaderLease)(nil))
 
 	// Make the leader keepalived.
 	ctx, cancel = context.WithCancel(s.serverLoopCtx)
@@ -242,6 +300,14 @@ func (s *Server) campaignLeader() error {
 	if err != nil {
 		return err
 	}
+	if err = s.maintenance.start(); err != nil {
+		return err
+	}
+	defer s.maintenance.stop()
+	if err = s.events.start(); err != nil {
+		return err
+	}
+	defer s.events.stop()
 	// Try to create raft cluster.
 	err = s.createRaftCluster()
 	if err != nil {
@@ -256,9 +322,24 @@ func (s *Server) campaignLeader() error {
 	defer s.ts.Store(&atomicObject{
 		physical: zeroTime,
 	})
+	defer s.waitTimestampSaving()
 
+	// The leader key is put with a new lease in each leadership.
+	s.metaCache.reset(resp.Header.Revision)
+	defer s.metaCache.reset(0)
 	s.enableLeader()
 	defer s.disableLeader()
+	if err = s.jobs.start(); err != nil {
+		return err
+	}
+	defer s.jobs.stop()
+	s.keys.start()
+	defer s.keys.stop()
+	term, err := s.startLeaderTerm(resp.Header.Revision)
+	if err != nil {
+		return err
+	}
+	s.events.record(EventLeaderChanged, 0, 0, "%s becomes PD leader", s.Name())
 
 	log.Infof("cluster version is %s", s.scheduleOpt.loadClusterVersion())
 	log.Infof("PD cluster leader %s is ready to serve", s.Name())
@@ -269,21 +350,26 @@ func (s *Server) campaignLeader() error {
 
 	for {
 		select {
-		case _, ok := <-ch:
+		case keepAlive, ok := <-ch:
 			if !ok {
 				log.Info("keep alive channel is closed")
+				s.endLeaderTerm(term, LeaderLeaseExpired)
 				return nil
 			}
+			s.updateLeaderLease(keepAlive.TTL, time.Now().Add(time.Duration(keepAlive.TTL)*time.Second))
 		case <-tsTicker.C:
 			if err = s.updateTimestamp(); err != nil {
+				s.endLeaderTerm(term, LeaderError)
 				return err
 			}
 			etcdLeader := s.GetEtcdLeader()
 			if etcdLeader != s.ID() {
 				log.Infof("etcd leader changed, %s resigns leadership", s.Name())
+				s.endLeaderTerm(term, s.leaderStepDownReason())
 				return nil
 			}
 		case <-ctx.Done():
+			s.endLeaderTerm(term, LeaderServerClosed)
 			return errors.New("server closed")
 		}
 	}
@@ -336,6 +422,12 @@ func (s *Server) watchLeader(leader *pdpb.Member, revision int64) {
 // ResignLeader resigns current PD's leadership. If nextLeader is empty, all
 // other pd-servers can campaign.
 func (s *Server) ResignLeader(nextLeader string) error {
+	return s.resignLeader(s.serverLoopCtx, nextLeader)
+}
+
+// resignLeader is like ResignLeader, but moving the etcd leader is canceled
+// when ctx is done.
+func (s *Server) resignLeader(ctx context.Context, nextLeader string) error {
 	log.Infof("%s tries to resign leader with next leader directive: %v", s.Name(), nextLeader)
 	// Determine next leaders.
 	var leaderIDs []uint64
@@ -353,7 +445,11 @@ func (s *Server) ResignLeader(nextLeader string) error {
 	}
 	nextLeaderID := leaderIDs[rand.Intn(len(leaderIDs))]
 	log.Infof("%s ready to resign leader, next leader: %v", s.Name(), nextLeaderID)
-	err = s.etcd.Server.MoveLeader(s.serverLoopCtx, s.ID(), nextLeaderID)
+	atomic.StoreInt32(&s.resigning, 1)
+	err = s.etcd.Server.MoveLeader(ctx, s.ID(), nextLeaderID)
+	if err != nil {
+		atomic.StoreInt32(&s.resigning, 0)
+	}
 	return errors.WithStack(err)
 }
 
@@ -365,7 +461,7 @@ func (s *Server) deleteLeaderKey() error {
 		return errors.WithStack(err)
 	}
 	if !resp.Succeeded {
-		return errors.New("resign leader failed, we are not leader already")
+		return errors.Wrap(ErrNotLeader, "resign leader failed")
 	}
 
 	return nil
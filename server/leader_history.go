@@ -0,0 +1,191 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sync/atomic"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pingcap/pd/pkg/typeutil"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// The reasons why a leader steps down.
+const (
+	LeaderResigned          = "resign"
+	LeaderLeaseExpired      = "lease-expired"
+	LeaderEtcdLeaderChanged = "etcd-leader-change"
+	LeaderServerClosed      = "server-closed"
+	LeaderError             = "error"
+	// LeaderUnknown is recorded by the next leader if the term is not ended
+	// by its leader, for example the leader is killed.
+	LeaderUnknown = "unknown"
+)
+
+// maxLeaderTerms is the count of the latest leader terms to keep.
+const maxLeaderTerms = 100
+
+// LeaderTerm is the period a PD server serves as the leader, from it is
+// elected to it steps down.
+type LeaderTerm struct {
+	// ID is the revision the leader key is put at, which increases with the
+	// terms.
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	MemberID  uint64    `json:"member_id"`
+	StartTime time.Time `json:"start_time"`
+	// EndTime and Reason are empty if the term is not ended.
+	EndTime time.Time `json:"end_time,omitempty"`
+	Reason  string    `json:"reason,omitempty"`
+}
+
+// LeaderLease is the lease of the leader key kept alive by the leader.
+type LeaderLease struct {
+	Name       string            `json:"name"`
+	TTL        int64             `json:"ttl"`
+	ExpireTime time.Time         `json:"expire_time"`
+	Remaining  typeutil.Duration `json:"remaining"`
+}
+
+func (s *Server) getLeaderHistoryPath() string {
+	return path.Join(s.rootPath, "leader_history")
+}
+
+func (s *Server) getLeaderTermPath(id int64) string {
+	return path.Join(s.getLeaderHistoryPath(), fmt.Sprintf("%020d", id))
+}
+
+// loadLeaderTerms loads the leader terms ordered by ID, with the mod
+// revisions of their keys.
+func (s *Server) loadLeaderTerms() ([]*LeaderTerm, []int64, error) {
+	resp, err := kvGet(s.client.Ctx(), s.client, s.getLeaderHistoryPath()+"/", clientv3.WithPrefix())
+	if err != nil {
+		return nil, nil, err
+	}
+	terms := make([]*LeaderTerm, 0, len(resp.Kvs))
+	revisions := make([]int64, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		term := &LeaderTerm{}
+		if err = json.Unmarshal(kv.Value, term); err != nil {
+			return nil, nil, errors.WithStack(err)
+		}
+		terms = append(terms, term)
+		revisions = append(revisions, kv.ModRevision)
+	}
+	return terms, revisions, nil
+}
+
+// saveLeaderTerm saves the term without the leader check, because the term
+// is ended after the leader key may be deleted.
+func (s *Server) saveLeaderTerm(term *LeaderTerm, cs ...clientv3.Cmp) error {
+	value, err := json.Marshal(term)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	_, err = s.txn().If(cs...).Then(clientv3.OpPut(s.getLeaderTermPath(term.ID), string(value))).Commit()
+	return errors.WithStack(err)
+}
+
+// startLeaderTerm records the term starting at the revision. The terms not
+// ended by the previous leaders are ended with the unknown reason, and the
+// terms beyond maxLeaderTerms are deleted.
+func (s *Server) startLeaderTerm(id int64) (*LeaderTerm, error) {
+	terms, revisions, err := s.loadLeaderTerms()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for i, term := range terms {
+		if !term.EndTime.IsZero() {
+			continue
+		}
+		term.EndTime, term.Reason = now, LeaderUnknown
+		// Skip the term if the previous leader ends it in the meantime.
+		modCmp := clientv3.Compare(clientv3.ModRevision(s.getLeaderTermPath(term.ID)), "=", revisions[i])
+		if err = s.saveLeaderTerm(term, modCmp); err != nil {
+			return nil, err
+		}
+	}
+	for i := 0; i < len(terms)+1-maxLeaderTerms; i++ {
+		if _, err = s.leaderTxn().Then(clientv3.OpDelete(s.getLeaderTermPath(terms[i].ID))).Commit(); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	term := &LeaderTerm{
+		ID:        id,
+		Name:      s.Name(),
+		MemberID:  s.ID(),
+		StartTime: now,
+	}
+	if err = s.saveLeaderTerm(term); err != nil {
+		return nil, err
+	}
+	return term, nil
+}
+
+// endLeaderTerm records the reason why the leader steps down.
+func (s *Server) endLeaderTerm(term *LeaderTerm, reason string) {
+	term.EndTime, term.Reason = time.Now(), reason
+	if err := s.saveLeaderTerm(term); err != nil {
+		log.Errorf("failed to save leader term %d: %v", term.ID, err)
+	}
+	log.Infof("%s steps down from leader, reason: %s", s.Name(), reason)
+}
+
+// leaderStepDownReason returns the reason of the leader stepping down because
+// the etcd leader changes.
+func (s *Server) leaderStepDownReason() string {
+	if atomic.LoadInt32(&s.resigning) != 0 {
+		return LeaderResigned
+	}
+	return LeaderEtcdLeaderChanged
+}
+
+// updateLeaderLease updates the expire time of the leader lease.
+func (s *Server) updateLeaderLease(ttl int64, expire time.Time) {
+	s.leaderLease.Store(&LeaderLease{Name: s.Name(), TTL: ttl, ExpireTime: expire})
+}
+
+// GetLeaderLease returns the lease of the leader key, nil if the server is
+// not leader.
+func (s *Server) GetLeaderLease() *LeaderLease {
+	lease, ok := s.leaderLease.Load().(*LeaderLease)
+	if !ok || lease == nil || !s.IsLeader() {
+		return nil
+	}
+	res := *lease
+	if remaining := time.Until(res.ExpireTime); remaining > 0 {
+		res.Remaining = typeutil.NewDuration(remaining)
+	}
+	return &res
+}
+
+// GetLeaderHistory returns the latest leader terms ordered by ID. At most
+// limit latest terms are returned if limit is positive.
+func (s *Server) GetLeaderHistory(limit int) ([]*LeaderTerm, error) {
+	terms, _, err := s.loadLeaderTerms()
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(terms) > limit {
+		terms = terms[len(terms)-limit:]
+	}
+	return terms, nil
+}
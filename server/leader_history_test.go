@@ -0,0 +1,98 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/pd/pkg/testutil"
+)
+
+var _ = Suite(&testLeaderHistorySuite{})
+
+type testLeaderHistorySuite struct{}
+
+func (s *testLeaderHistorySuite) TestResign(c *C) {
+	svrs, cleanup := newTestServersWithCfgs(c, NewTestMultiConfig(2))
+	defer cleanup()
+
+	leader := mustWaitLeader(c, svrs)
+	lease := leader.GetLeaderLease()
+	c.Assert(lease, NotNil)
+	c.Assert(lease.Name, Equals, leader.Name())
+	c.Assert(lease.TTL, Greater, int64(0))
+	c.Assert(lease.Remaining.Duration, Greater, time.Duration(0))
+	c.Assert(lease.Remaining.Duration <= time.Duration(lease.TTL)*time.Second, IsTrue)
+	terms, err := leader.GetLeaderHistory(0)
+	c.Assert(err, IsNil)
+	c.Assert(terms, HasLen, 1)
+	c.Assert(terms[0].Name, Equals, leader.Name())
+	c.Assert(terms[0].Reason, Equals, "")
+
+	c.Assert(leader.ResignLeader(""), IsNil)
+	var next *Server
+	testutil.WaitUntil(c, func(c *C) bool {
+		for _, svr := range svrs {
+			if svr != leader && svr.IsLeader() {
+				next = svr
+				return true
+			}
+		}
+		return false
+	})
+	c.Assert(leader.GetLeaderLease(), IsNil)
+	testutil.WaitUntil(c, func(c *C) bool {
+		terms, err = next.GetLeaderHistory(0)
+		c.Assert(err, IsNil)
+		return len(terms) == 2 && terms[0].Reason != ""
+	})
+	c.Assert(terms[0].Name, Equals, leader.Name())
+	c.Assert(terms[0].Reason, Equals, LeaderResigned)
+	c.Assert(terms[0].EndTime.IsZero(), IsFalse)
+	c.Assert(terms[1].Name, Equals, next.Name())
+	c.Assert(terms[1].ID, Greater, terms[0].ID)
+
+	terms, err = next.GetLeaderHistory(1)
+	c.Assert(err, IsNil)
+	c.Assert(terms, HasLen, 1)
+	c.Assert(terms[0].Name, Equals, next.Name())
+}
+
+func (s *testLeaderHistorySuite) TestStartTerm(c *C) {
+	_, svr, cleanup, err := NewTestServer()
+	c.Assert(err, IsNil)
+	defer cleanup()
+	mustWaitLeader(c, []*Server{svr})
+
+	// The term not ended by its leader is ended by the next one.
+	term, err := svr.startLeaderTerm(1 << 40)
+	c.Assert(err, IsNil)
+	terms, err := svr.GetLeaderHistory(0)
+	c.Assert(err, IsNil)
+	c.Assert(terms, HasLen, 2)
+	c.Assert(terms[0].Reason, Equals, LeaderUnknown)
+	c.Assert(terms[1].ID, Equals, term.ID)
+	c.Assert(terms[1].StartTime.Equal(term.StartTime), IsTrue)
+	c.Assert(terms[1].Reason, Equals, "")
+
+	for i := 0; i < maxLeaderTerms; i++ {
+		_, err = svr.startLeaderTerm(1<<40 + int64(i) + 1)
+		c.Assert(err, IsNil)
+	}
+	terms, err = svr.GetLeaderHistory(0)
+	c.Assert(err, IsNil)
+	c.Assert(terms, HasLen, maxLeaderTerms)
+	c.Assert(terms[maxLeaderTerms-1].ID, Equals, int64(1<<40+maxLeaderTerms))
+}
@@ -20,6 +20,8 @@ import (
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pkg/errors"
 )
 
 var _ = Suite(&testGetLeaderSuite{})
@@ -83,3 +85,23 @@ func (s *testGetLeaderSuite) sendRequest(c *C, addr string) {
 		time.Sleep(10 * time.Millisecond)
 	}
 }
+
+var _ = Suite(&testDowngradeSuite{})
+
+type testDowngradeSuite struct{}
+
+func (s *testDowngradeSuite) TestCheckDowngrade(c *C) {
+	kv := core.NewKV(core.NewMemoryKV())
+	// The cluster version is not persisted yet.
+	c.Assert(checkDowngrade(kv, "v2.0.0"), IsNil)
+
+	cfg := NewConfig()
+	cfg.ClusterVersion = *MustParseVersion("2.1.0")
+	c.Assert(kv.SaveConfig(cfg), IsNil)
+	c.Assert(checkDowngrade(kv, "v2.1.0"), IsNil)
+	c.Assert(checkDowngrade(kv, "v2.1.1"), IsNil)
+	c.Assert(checkDowngrade(kv, "None"), IsNil)
+	err := checkDowngrade(kv, "v2.0.5")
+	c.Assert(errors.Cause(err), Equals, ErrDowngrade)
+	c.Assert(checkDowngrade(kv, "x.y"), NotNil)
+}
@@ -0,0 +1,112 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	log "github.com/sirupsen/logrus"
+)
+
+// memberZoneLabel is the member label matched against leader-preferred-zone.
+const memberZoneLabel = "zone"
+
+// getMemberZone returns the zone label of a member.
+func (s *Server) getMemberZone(id uint64) (string, error) {
+	if id == s.ID() {
+		return s.cfg.Labels[memberZoneLabel], nil
+	}
+	info, err := s.GetMemberDeployInfo(id)
+	if err != nil {
+		return "", err
+	}
+	return info.Labels[memberZoneLabel], nil
+}
+
+// getPreferredLeader returns the healthy member in the zone with the highest
+// leader priority, nil if there is none.
+func (s *Server) getPreferredLeader(zone string) (*pdpb.Member, error) {
+	members, err := s.ListMembers()
+	if err != nil {
+		return nil, err
+	}
+	unhealthy := s.CheckHealth(members)
+	var (
+		preferred *pdpb.Member
+		priority  int
+	)
+	for _, m := range members {
+		if m.GetMemberId() == s.ID() {
+			continue
+		}
+		if _, ok := unhealthy[m.GetMemberId()]; ok {
+			continue
+		}
+		memberZone, err := s.getMemberZone(m.GetMemberId())
+		if err != nil {
+			return nil, err
+		}
+		if memberZone != zone {
+			continue
+		}
+		p, err := s.GetMemberLeaderPriority(m.GetMemberId())
+		if err != nil {
+			return nil, err
+		}
+		if preferred == nil || p > priority || (p == priority && m.GetName() < preferred.GetName()) {
+			preferred, priority = m, p
+		}
+	}
+	return preferred, nil
+}
+
+// checkLeaderPreferredZone resigns the leadership to a healthy member in the
+// preferred zone if current leader is out of it. The leader keeps the
+// leadership if no member in the zone is healthy.
+func (s *Server) checkLeaderPreferredZone() {
+	zone := s.scheduleOpt.loadPDServerConfig().LeaderPreferredZone
+	if zone == "" || s.cfg.Labels[memberZoneLabel] == zone {
+		return
+	}
+	next, err := s.getPreferredLeader(zone)
+	if err != nil {
+		log.Errorf("failed to find the member in the preferred zone %s: %v", zone, err)
+		return
+	}
+	if next == nil {
+		log.Warnf("no healthy member in the preferred zone %s, %s keeps the leadership", zone, s.Name())
+		return
+	}
+	log.Infof("%s is out of the preferred zone %s, resign the leadership to %s", s.Name(), zone, next.GetName())
+	if err = s.ResignLeader(next.GetName()); err != nil {
+		log.Errorf("failed to resign the leadership to %s: %v", next.GetName(), err)
+	}
+}
+
+// isLeaderZonePreferred returns true if the leader is in the preferred zone
+// while current member is out of it, in which case the leader priority of
+// current member is ignored, so the leadership does not bounce between the
+// zones.
+func (s *Server) isLeaderZonePreferred(leaderID uint64) bool {
+	zone := s.scheduleOpt.loadPDServerConfig().LeaderPreferredZone
+	if zone == "" || s.cfg.Labels[memberZoneLabel] == zone {
+		return false
+	}
+	leaderZone, err := s.getMemberZone(leaderID)
+	if err != nil {
+		log.Errorf("failed to load the zone of member %d: %v", leaderID, err)
+		// Keep current leader, it is retried in the next round.
+		return true
+	}
+	return leaderZone == zone
+}
@@ -0,0 +1,84 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/pd/pkg/testutil"
+	"github.com/pingcap/pd/pkg/typeutil"
+)
+
+var _ = Suite(&testLeaderZoneSuite{})
+
+type testLeaderZoneSuite struct{}
+
+func (s *testLeaderZoneSuite) TestLeaderPreferredZone(c *C) {
+	cfgs := NewTestMultiConfig(3)
+	for i, cfg := range cfgs {
+		cfg.Labels = map[string]string{memberZoneLabel: fmt.Sprintf("z%d", i+1)}
+		cfg.LeaderPriorityCheckInterval = typeutil.NewDuration(100 * time.Millisecond)
+	}
+	// The health of members is checked by the ping API.
+	ping := func(*Server) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	}
+	ch := make(chan *Server, len(cfgs))
+	for _, cfg := range cfgs {
+		go func(cfg *Config) {
+			svr, err := CreateServer(cfg, ping)
+			c.Assert(err, IsNil)
+			c.Assert(svr.Run(context.TODO()), IsNil)
+			ch <- svr
+		}(cfg)
+	}
+	svrs := make([]*Server, 0, len(cfgs))
+	for range cfgs {
+		svr := <-ch
+		defer cleanServer(svr.cfg)
+		defer svr.Close()
+		svrs = append(svrs, svr)
+	}
+
+	leader := mustWaitLeader(c, svrs)
+	info, err := leader.GetMemberDeployInfo(leader.ID())
+	c.Assert(err, IsNil)
+	c.Assert(info.Labels, DeepEquals, leader.cfg.Labels)
+
+	var target *Server
+	for _, svr := range svrs {
+		if svr != leader {
+			target = svr
+			break
+		}
+	}
+	zone := target.cfg.Labels[memberZoneLabel]
+	cfg := *leader.scheduleOpt.loadPDServerConfig()
+	cfg.LeaderPreferredZone = "invalid zone"
+	c.Assert(leader.SetPDServerConfig(cfg), NotNil)
+	cfg.LeaderPreferredZone = zone
+	c.Assert(leader.SetPDServerConfig(cfg), IsNil)
+
+	// The leadership moves to the preferred zone and stays there.
+	testutil.WaitUntil(c, func(c *C) bool {
+		return target.IsLeader()
+	})
+	time.Sleep(500 * time.Millisecond)
+	c.Assert(target.IsLeader(), IsTrue)
+	c.Assert(target.scheduleOpt.loadPDServerConfig().LeaderPreferredZone, Equals, zone)
+}
@@ -0,0 +1,219 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/pkg/splitpb"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/schedule"
+	log "github.com/sirupsen/logrus"
+)
+
+// loadSplitStaleTime is the time to keep the load of a region which does not
+// heartbeat.
+const loadSplitStaleTime = 10 * time.Minute
+
+// loadSplitter finds the regions whose load stays above the threshold, and
+// picks the key bisecting their load from the key samples reported by the
+// stores, so that the load of a single hot region is spread by splitting.
+type loadSplitter struct {
+	sync.Mutex
+	opt *scheduleOption
+	// regions are the hot regions, they are removed once the load drops, the
+	// regions are removed from the cache or they stop heartbeating.
+	regions   map[uint64]*regionLoad
+	lastSweep time.Time
+}
+
+type regionLoad struct {
+	hotSince time.Time
+	lastSeen time.Time
+	// version is the region version when the samples are reported, the
+	// samples are stale once the region is split or merged.
+	version uint64
+	samples []*splitpb.KeySample
+}
+
+func newLoadSplitter(opt *scheduleOption) *loadSplitter {
+	return &loadSplitter{
+		opt:     opt,
+		regions: make(map[uint64]*regionLoad),
+	}
+}
+
+// regionQPS returns the keys read and written per second of the region.
+func regionQPS(region *core.RegionInfo) uint64 {
+	if region.GetInterval() == 0 {
+		return 0
+	}
+	return (region.GetKeysRead() + region.GetKeysWritten()) / region.GetInterval()
+}
+
+// observe records the load of a region heartbeat, and returns the key to split
+// the region at if the load has been high for long enough.
+func (l *loadSplitter) observe(region *core.RegionInfo, now time.Time) []byte {
+	l.Lock()
+	defer l.Unlock()
+	l.sweepLocked(now)
+	if !l.opt.IsLoadSplitEnabled() || regionQPS(region) < l.opt.GetSplitQPSThreshold() {
+		delete(l.regions, region.GetID())
+		return nil
+	}
+	load, ok := l.regions[region.GetID()]
+	if !ok {
+		load = &regionLoad{hotSince: now}
+		l.regions[region.GetID()] = load
+	}
+	load.lastSeen = now
+	if now.Sub(load.hotSince) < l.opt.GetSplitHotDuration() || load.version != region.GetRegionEpoch().GetVersion() {
+		return nil
+	}
+	key := bisectLoad(region.GetStartKey(), region.GetEndKey(), load.samples)
+	if key != nil {
+		delete(l.regions, region.GetID())
+	}
+	return key
+}
+
+// sweepLocked removes the regions which stop heartbeating.
+func (l *loadSplitter) sweepLocked(now time.Time) {
+	if now.Sub(l.lastSweep) < loadSplitStaleTime {
+		return
+	}
+	for id, load := range l.regions {
+		if now.Sub(load.lastSeen) >= loadSplitStaleTime {
+			delete(l.regions, id)
+		}
+	}
+	l.lastSweep = now
+}
+
+// remove removes the region which is removed from the cache, such as merged.
+func (l *loadSplitter) remove(regionID uint64) {
+	l.Lock()
+	defer l.Unlock()
+	delete(l.regions, regionID)
+}
+
+// report saves the key samples of a region. The samples are ignored unless
+// the region is hot.
+func (l *loadSplitter) report(region *metapb.Region, samples []*splitpb.KeySample) bool {
+	l.Lock()
+	defer l.Unlock()
+	load, ok := l.regions[region.GetId()]
+	if !ok {
+		return false
+	}
+	load.version = region.GetRegionEpoch().GetVersion()
+	load.samples = samples
+	return true
+}
+
+// bisectLoad returns the sampled key which splits the requests into two
+// halves as even as possible, nil if no key inside the range splits them.
+func bisectLoad(startKey, endKey []byte, samples []*splitpb.KeySample) []byte {
+	inRange := make([]*splitpb.KeySample, 0, len(samples))
+	var total uint64
+	for _, s := range samples {
+		if bytes.Compare(s.GetKey(), startKey) < 0 || (len(endKey) > 0 && bytes.Compare(s.GetKey(), endKey) >= 0) {
+			continue
+		}
+		inRange = append(inRange, s)
+		total += s.GetCount()
+	}
+	sort.Slice(inRange, func(i, j int) bool { return bytes.Compare(inRange[i].GetKey(), inRange[j].GetKey()) < 0 })
+
+	// The requests of a key go to the right half if the region is split at
+	// it, so the left half of the i-th key has the requests before it.
+	var key []byte
+	var left uint64
+	best := total
+	for _, s := range inRange {
+		if left > 0 && !bytes.Equal(s.GetKey(), startKey) {
+			right := total - left
+			diff := left - right
+			if left < right {
+				diff = right - left
+			}
+			if diff < best {
+				best, key = diff, s.GetKey()
+			}
+		}
+		left += s.GetCount()
+	}
+	if key == nil {
+		return nil
+	}
+	return append([]byte(nil), key...)
+}
+
+// checkLoadSplit splits the region at the key bisecting its load if it has
+// been hot for long enough.
+func (c *RaftCluster) checkLoadSplit(region *core.RegionInfo) {
+	key := c.loadSplitter.observe(region, time.Now())
+	if key == nil || c.coordinator.opController.GetOperator(region.GetID()) != nil {
+		return
+	}
+	step := schedule.SplitRegion{
+		StartKey:  region.GetStartKey(),
+		EndKey:    region.GetEndKey(),
+		SplitKeys: [][]byte{key},
+	}
+	op := schedule.NewOperator("load-split-region", region.GetID(), region.GetRegionEpoch(), schedule.OpRegion, step)
+	if c.coordinator.opController.AddOperator(op) {
+		log.Infof("[region %d] split at key %s for the sustained load %d", region.GetID(), core.HexRegionKey(key), regionQPS(region))
+	}
+}
+
+// splitService implements gRPC SplitServer.
+type splitService struct {
+	s *Server
+}
+
+// ReportKeySamples implements gRPC SplitServer.
+func (ss *splitService) ReportKeySamples(ctx context.Context, request *splitpb.ReportKeySamplesRequest) (*splitpb.ReportKeySamplesResponse, error) {
+	if err := ss.s.validateRequest(request.GetHeader()); err != nil {
+		return nil, err
+	}
+	cluster := ss.s.GetRaftCluster()
+	if cluster == nil {
+		return &splitpb.ReportKeySamplesResponse{Header: ss.s.notBootstrappedHeader()}, nil
+	}
+	return &splitpb.ReportKeySamplesResponse{
+		Header:   ss.s.header(),
+		Accepted: cluster.loadSplitter.report(request.GetRegion(), request.GetSamples()),
+	}, nil
+}
+
+// GetSplitCommands implements gRPC SplitServer.
+func (ss *splitService) GetSplitCommands(ctx context.Context, request *splitpb.GetSplitCommandsRequest) (*splitpb.GetSplitCommandsResponse, error) {
+	if err := ss.s.validateRequest(request.GetHeader()); err != nil {
+		return nil, err
+	}
+	cluster := ss.s.GetRaftCluster()
+	if cluster == nil {
+		return &splitpb.GetSplitCommandsResponse{Header: ss.s.notBootstrappedHeader()}, nil
+	}
+	return &splitpb.GetSplitCommandsResponse{
+		Header:   ss.s.header(),
+		Commands: cluster.coordinator.opController.GetSplitCommands(request.GetStoreId()),
+	}, nil
+}
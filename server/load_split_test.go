@@ -0,0 +1,147 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/pkg/splitpb"
+	"github.com/pingcap/pd/server/core"
+	"google.golang.org/grpc"
+)
+
+var _ = Suite(&testLoadSplitSuite{})
+
+type testLoadSplitSuite struct{}
+
+func newTestSamples(kvs ...interface{}) []*splitpb.KeySample {
+	var samples []*splitpb.KeySample
+	for i := 0; i < len(kvs); i += 2 {
+		samples = append(samples, &splitpb.KeySample{Key: []byte(kvs[i].(string)), Count: uint64(kvs[i+1].(int))})
+	}
+	return samples
+}
+
+func (s *testLoadSplitSuite) TestBisectLoad(c *C) {
+	c.Assert(bisectLoad([]byte("a"), []byte("z"), nil), IsNil)
+	// A single key can not be split.
+	c.Assert(bisectLoad([]byte("a"), []byte("z"), newTestSamples("b", 10)), IsNil)
+	c.Assert(bisectLoad([]byte("a"), []byte("z"), newTestSamples("c", 10, "b", 10)), DeepEquals, []byte("c"))
+	c.Assert(bisectLoad([]byte("a"), []byte("z"), newTestSamples("b", 10, "c", 1, "d", 8, "e", 1)), DeepEquals, []byte("c"))
+	c.Assert(bisectLoad([]byte("a"), []byte("z"), newTestSamples("b", 1, "c", 1, "d", 8, "e", 10)), DeepEquals, []byte("e"))
+	// The keys out of the range and the start key are ignored.
+	c.Assert(bisectLoad([]byte("b"), []byte("d"), newTestSamples("a", 10, "b", 1, "c", 1, "d", 10)), DeepEquals, []byte("c"))
+	c.Assert(bisectLoad([]byte("b"), []byte("d"), newTestSamples("a", 10, "b", 1, "d", 10)), IsNil)
+	c.Assert(bisectLoad([]byte("b"), nil, newTestSamples("b", 1, "z", 1)), DeepEquals, []byte("z"))
+}
+
+func (s *testLoadSplitSuite) TestObserve(c *C) {
+	cfg, opt := newTestScheduleConfig()
+	cfg.SplitQPSThreshold = 100
+	cfg.SplitHotDuration.Duration = time.Minute
+	opt.store(cfg)
+	l := newLoadSplitter(opt)
+
+	meta := &metapb.Region{Id: 1, StartKey: []byte("a"), EndKey: []byte("z"), RegionEpoch: &metapb.RegionEpoch{Version: 1}}
+	cold := core.NewRegionInfo(meta, nil, core.SetReadKeys(100), core.SetInterval(10))
+	hot := core.NewRegionInfo(meta, nil, core.SetReadKeys(1000), core.SetWrittenKeys(1000), core.SetInterval(10))
+	samples := newTestSamples("b", 10, "c", 10)
+	now := time.Now()
+
+	// The samples of a cold region are not accepted.
+	c.Assert(l.observe(cold, now), IsNil)
+	c.Assert(l.report(meta, samples), IsFalse)
+	c.Assert(l.observe(hot, now), IsNil)
+	c.Assert(l.report(meta, samples), IsTrue)
+	c.Assert(l.observe(hot, now.Add(time.Second)), IsNil)
+	c.Assert(l.observe(hot, now.Add(time.Minute)), DeepEquals, []byte("c"))
+	// The region is hot again since the split.
+	c.Assert(l.report(meta, samples), IsFalse)
+
+	// The load drops.
+	c.Assert(l.observe(hot, now), IsNil)
+	c.Assert(l.report(meta, samples), IsTrue)
+	c.Assert(l.observe(cold, now.Add(time.Second)), IsNil)
+	c.Assert(l.observe(hot, now.Add(time.Minute)), IsNil)
+
+	// The samples are stale.
+	c.Assert(l.report(meta, samples), IsTrue)
+	split := hot.Clone(core.WithIncVersion())
+	c.Assert(l.observe(split, now.Add(2*time.Minute)), IsNil)
+
+	// The region is removed from the cache.
+	l.remove(meta.GetId())
+	c.Assert(l.report(meta, samples), IsFalse)
+
+	// The region stops heartbeating.
+	c.Assert(l.observe(hot, now.Add(3*time.Minute)), IsNil)
+	other := core.NewRegionInfo(&metapb.Region{Id: 2}, nil)
+	c.Assert(l.observe(other, now.Add(3*time.Minute+loadSplitStaleTime)), IsNil)
+	c.Assert(l.report(meta, samples), IsFalse)
+
+	// Disabled.
+	cfg.DisableLoadSplit = true
+	opt.store(cfg)
+	c.Assert(l.observe(hot, now.Add(3*time.Minute)), IsNil)
+	c.Assert(l.report(meta, samples), IsFalse)
+}
+
+func (s *testLoadSplitSuite) TestGetSplitCommands(c *C) {
+	_, svr, cleanup, err := NewTestServer()
+	c.Assert(err, IsNil)
+	defer cleanup()
+	mustWaitLeader(c, []*Server{svr})
+	req := (&baseCluster{svr: svr}).newBootstrapRequest(c, svr.clusterID, "127.0.0.1:0")
+	_, err = svr.bootstrapCluster(req)
+	c.Assert(err, IsNil)
+	cfg := svr.scheduleOpt.load().clone()
+	cfg.SplitQPSThreshold = 1
+	cfg.SplitHotDuration.Duration = 0
+	svr.scheduleOpt.store(cfg)
+
+	conn, err := grpc.Dial(strings.TrimPrefix(svr.GetAddr(), "http://"), grpc.WithInsecure())
+	c.Assert(err, IsNil)
+	defer conn.Close()
+	client := splitpb.NewSplitClient(conn)
+	header := newRequestHeader(svr.clusterID)
+	ctx := context.Background()
+
+	// The region is split at the key bisecting the reported load.
+	cluster := svr.GetRaftCluster()
+	meta := req.GetRegion()
+	region := core.NewRegionInfo(meta, meta.GetPeers()[0], core.SetReadKeys(1000), core.SetInterval(10))
+	c.Assert(cluster.HandleRegionHeartbeat(ctx, region), IsNil)
+	resp, err := client.ReportKeySamples(ctx, &splitpb.ReportKeySamplesRequest{Header: header, Region: meta, Samples: newTestSamples("b", 10, "c", 10)})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetAccepted(), IsTrue)
+	c.Assert(cluster.HandleRegionHeartbeat(ctx, region), IsNil)
+
+	storeID := req.GetStore().GetId()
+	cmds, err := client.GetSplitCommands(ctx, &splitpb.GetSplitCommandsRequest{Header: header, StoreId: storeID})
+	c.Assert(err, IsNil)
+	c.Assert(cmds.GetCommands(), HasLen, 1)
+	cmd := cmds.GetCommands()[0]
+	c.Assert(cmd.GetRegionId(), Equals, meta.GetId())
+	c.Assert(cmd.GetRegionEpoch(), DeepEquals, meta.GetRegionEpoch())
+	c.Assert(cmd.GetSplitKeys(), DeepEquals, [][]byte{[]byte("c")})
+
+	// The split is only sent to the leader store.
+	cmds, err = client.GetSplitCommands(ctx, &splitpb.GetSplitCommandsRequest{Header: header, StoreId: storeID + 1})
+	c.Assert(err, IsNil)
+	c.Assert(cmds.GetCommands(), HasLen, 0)
+}
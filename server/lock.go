@@ -0,0 +1,54 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// rwLock is a sync.RWMutex which records the time waiting for the lock. The
+// zero value is an unnamed lock without metrics.
+type rwLock struct {
+	sync.RWMutex
+	readWait  prometheus.Histogram
+	writeWait prometheus.Histogram
+}
+
+// setName names the lock in the lock wait metrics. It should be called before
+// the lock is used.
+func (l *rwLock) setName(name string) {
+	l.readWait = lockWaitDuration.WithLabelValues(name, "read")
+	l.writeWait = lockWaitDuration.WithLabelValues(name, "write")
+}
+
+// Lock locks for writing.
+func (l *rwLock) Lock() {
+	start := time.Now()
+	l.RWMutex.Lock()
+	if l.writeWait != nil {
+		l.writeWait.Observe(time.Since(start).Seconds())
+	}
+}
+
+// RLock locks for reading.
+func (l *rwLock) RLock() {
+	start := time.Now()
+	l.RWMutex.RLock()
+	if l.readWait != nil {
+		l.readWait.Observe(time.Since(start).Seconds())
+	}
+}
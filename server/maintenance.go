@@ -0,0 +1,203 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pingcap/pd/server/core"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrMaintenance is error info for the mutating requests rejected in the
+// maintenance mode.
+var ErrMaintenance = errors.New("cluster is in maintenance mode")
+
+// MaintenanceStatus is the maintenance mode status of the cluster.
+type MaintenanceStatus struct {
+	Enabled   bool      `json:"enabled"`
+	Reason    string    `json:"reason"`
+	Requester string    `json:"requester"`
+	StartTime time.Time `json:"start_time"`
+	// Deadline is when the cluster exits the maintenance mode automatically.
+	// Zero means no deadline.
+	Deadline time.Time `json:"deadline"`
+}
+
+func (s *MaintenanceStatus) expired(now time.Time) bool {
+	return !s.Deadline.IsZero() && !now.Before(s.Deadline)
+}
+
+// maintenanceManager manages the maintenance mode of the cluster. In the
+// maintenance mode, the mutating admin APIs are rejected, while heartbeats,
+// TSO and reads continue. The status is persisted, and is enforced by the
+// leader.
+type maintenanceManager struct {
+	sync.RWMutex
+	kv     *core.KV
+	status MaintenanceStatus
+	// exitTimer exits the maintenance mode at the deadline.
+	exitTimer *time.Timer
+}
+
+func newMaintenanceManager(kv *core.KV) *maintenanceManager {
+	return &maintenanceManager{kv: kv}
+}
+
+// start loads the status and schedules the exit at the deadline. It is called
+// after the server becomes leader.
+func (m *maintenanceManager) start() error {
+	m.Lock()
+	defer m.Unlock()
+
+	var status MaintenanceStatus
+	if _, err := m.kv.LoadMaintenance(&status); err != nil {
+		return err
+	}
+	m.status = status
+	if m.status.Enabled {
+		log.Warnf("cluster is in maintenance mode, reason: %s, requester: %s", status.Reason, status.Requester)
+	}
+	m.scheduleExit()
+	return nil
+}
+
+// stop cancels the scheduled exit. It is called after the server loses
+// leadership.
+func (m *maintenanceManager) stop() {
+	m.Lock()
+	defer m.Unlock()
+	if m.exitTimer != nil {
+		m.exitTimer.Stop()
+		m.exitTimer = nil
+	}
+}
+
+func (m *maintenanceManager) scheduleExit() {
+	if m.exitTimer != nil {
+		m.exitTimer.Stop()
+		m.exitTimer = nil
+	}
+	if !m.status.Enabled || m.status.Deadline.IsZero() {
+		return
+	}
+	m.exitTimer = time.AfterFunc(time.Until(m.status.Deadline), m.exitExpired)
+}
+
+func (m *maintenanceManager) exitExpired() {
+	m.Lock()
+	defer m.Unlock()
+	if !m.status.Enabled || !m.status.expired(time.Now()) {
+		return
+	}
+	if err := m.save(MaintenanceStatus{}); err != nil {
+		log.Errorf("failed to exit maintenance mode at deadline %v: %v", m.status.Deadline, err)
+		return
+	}
+	log.Infof("cluster exits maintenance mode at deadline %v", m.status.Deadline)
+}
+
+func (m *maintenanceManager) save(status MaintenanceStatus) error {
+	if err := m.kv.SaveMaintenance(status); err != nil {
+		return err
+	}
+	m.status = status
+	m.scheduleExit()
+	return nil
+}
+
+// enter enters the maintenance mode, or updates the reason and the deadline if
+// it is already in the maintenance mode.
+func (m *maintenanceManager) enter(reason, requester string, deadline time.Time) error {
+	m.Lock()
+	defer m.Unlock()
+
+	now := time.Now()
+	if !deadline.IsZero() && !deadline.After(now) {
+		return errors.Errorf("deadline %v is in the past", deadline)
+	}
+	status := MaintenanceStatus{
+		Enabled:   true,
+		Reason:    reason,
+		Requester: requester,
+		StartTime: now,
+		Deadline:  deadline,
+	}
+	if m.status.Enabled && !m.status.expired(now) {
+		status.StartTime = m.status.StartTime
+	}
+	if err := m.save(status); err != nil {
+		return err
+	}
+	log.Warnf("cluster enters maintenance mode, reason: %s, requester: %s, deadline: %v", reason, requester, deadline)
+	return nil
+}
+
+// exit exits the maintenance mode.
+func (m *maintenanceManager) exit() error {
+	m.Lock()
+	defer m.Unlock()
+
+	if !m.status.Enabled {
+		return nil
+	}
+	if err := m.save(MaintenanceStatus{}); err != nil {
+		return err
+	}
+	log.Info("cluster exits maintenance mode")
+	return nil
+}
+
+// get returns the maintenance mode status.
+func (m *maintenanceManager) get() MaintenanceStatus {
+	m.RLock()
+	defer m.RUnlock()
+	if m.status.expired(time.Now()) {
+		return MaintenanceStatus{}
+	}
+	return m.status
+}
+
+// check returns ErrMaintenance if the cluster is in the maintenance mode.
+func (m *maintenanceManager) check() error {
+	status := m.get()
+	if !status.Enabled {
+		return nil
+	}
+	return errors.Wrapf(ErrMaintenance, "reason: %s, requester: %s", status.Reason, status.Requester)
+}
+
+// EnterMaintenance makes the cluster enter the maintenance mode. The cluster
+// exits the maintenance mode automatically at the deadline if it is not zero.
+func (s *Server) EnterMaintenance(reason, requester string, deadline time.Time) error {
+	return s.maintenance.enter(reason, requester, deadline)
+}
+
+// ExitMaintenance makes the cluster exit the maintenance mode.
+func (s *Server) ExitMaintenance() error {
+	return s.maintenance.exit()
+}
+
+// GetMaintenanceStatus returns the maintenance mode status of the cluster.
+func (s *Server) GetMaintenanceStatus() MaintenanceStatus {
+	return s.maintenance.get()
+}
+
+// CheckMaintenance returns ErrMaintenance with the reason and the requester if
+// the cluster is in the maintenance mode.
+func (s *Server) CheckMaintenance() error {
+	return s.maintenance.check()
+}
@@ -0,0 +1,61 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/pd/pkg/keyspacepb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ = Suite(&testMaintenanceSuite{})
+
+type testMaintenanceSuite struct{}
+
+func (s *testMaintenanceSuite) TestMaintenance(c *C) {
+	_, svr, cleanup, err := NewTestServer()
+	c.Assert(err, IsNil)
+	defer cleanup()
+	mustWaitLeader(c, []*Server{svr})
+
+	conn, err := grpc.Dial(strings.TrimPrefix(svr.GetAddr(), "http://"), grpc.WithInsecure())
+	c.Assert(err, IsNil)
+	defer conn.Close()
+	client := keyspacepb.NewKeyspaceClient(conn)
+	header := newRequestHeader(svr.clusterID)
+
+	c.Assert(svr.EnterMaintenance("upgrade", "alice", time.Now().Add(-time.Second)), NotNil)
+	c.Assert(svr.EnterMaintenance("upgrade", "alice", time.Time{}), IsNil)
+	_, err = client.Create(context.Background(), &keyspacepb.CreateRequest{Header: header, Name: "ks1"})
+	c.Assert(status.Code(err), Equals, codes.FailedPrecondition)
+	_, err = client.List(context.Background(), &keyspacepb.ListRequest{Header: header})
+	c.Assert(err, IsNil)
+
+	// The status is persisted.
+	m := newMaintenanceManager(svr.kv)
+	c.Assert(m.start(), IsNil)
+	defer m.stop()
+	c.Assert(m.get().Reason, Equals, "upgrade")
+	c.Assert(m.get().Requester, Equals, "alice")
+
+	c.Assert(svr.ExitMaintenance(), IsNil)
+	_, err = client.Create(context.Background(), &keyspacepb.CreateRequest{Header: header, Name: "ks1"})
+	c.Assert(err, IsNil)
+}
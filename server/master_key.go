@@ -0,0 +1,163 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// kmsRequestTimeout is the timeout of a request to the KMS.
+const kmsRequestTimeout = 10 * time.Second
+
+// masterKey encrypts the data keys persisted in etcd.
+type masterKey interface {
+	encrypt(plaintext []byte) ([]byte, error)
+	decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// newMasterKey creates the master key, nil if the data keys are not served.
+func newMasterKey(cfg MasterKeyConfig) (masterKey, error) {
+	switch cfg.Type {
+	case "":
+		return nil, nil
+	case masterKeyTypeFile:
+		return newFileMasterKey(cfg.Path)
+	case masterKeyTypeKMS:
+		return &kmsMasterKey{
+			client:   &http.Client{Timeout: kmsRequestTimeout},
+			endpoint: strings.TrimSuffix(cfg.Endpoint, "/"),
+			keyName:  cfg.KeyName,
+			token:    cfg.Token,
+		}, nil
+	}
+	return nil, errors.Errorf("unknown master key type %q", cfg.Type)
+}
+
+// fileMasterKey encrypts the data keys with AES256-GCM by the key read from a
+// file. The ciphertext is prefixed with the nonce.
+type fileMasterKey struct {
+	aead cipher.AEAD
+}
+
+func newFileMasterKey(path string) (*fileMasterKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, errors.Wrapf(err, "master key file %s is not hex encoded", path)
+	}
+	if len(key) != 32 {
+		return nil, errors.Errorf("master key in %s should be 256 bits, but it has %d bits", path, len(key)*8)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &fileMasterKey{aead: aead}, nil
+}
+
+func (k *fileMasterKey) encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, k.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return k.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (k *fileMasterKey) decrypt(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < k.aead.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+	nonce, sealed := ciphertext[:k.aead.NonceSize()], ciphertext[k.aead.NonceSize():]
+	plaintext, err := k.aead.Open(nil, nonce, sealed, nil)
+	return plaintext, errors.WithStack(err)
+}
+
+// kmsMasterKey encrypts the data keys by the transit secrets engine of Vault,
+// so that the master key never leaves the KMS.
+type kmsMasterKey struct {
+	client   *http.Client
+	endpoint string
+	keyName  string
+	token    string
+}
+
+type kmsData struct {
+	Plaintext  string `json:"plaintext,omitempty"`
+	Ciphertext string `json:"ciphertext,omitempty"`
+}
+
+func (k *kmsMasterKey) encrypt(plaintext []byte) ([]byte, error) {
+	data, err := k.post("encrypt", &kmsData{Plaintext: base64.StdEncoding.EncodeToString(plaintext)})
+	if err != nil {
+		return nil, err
+	}
+	return []byte(data.Ciphertext), nil
+}
+
+func (k *kmsMasterKey) decrypt(ciphertext []byte) ([]byte, error) {
+	data, err := k.post("decrypt", &kmsData{Ciphertext: string(ciphertext)})
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := base64.StdEncoding.DecodeString(data.Plaintext)
+	return plaintext, errors.WithStack(err)
+}
+
+func (k *kmsMasterKey) post(op string, in *kmsData) (*kmsData, error) {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	req, err := http.NewRequest(http.MethodPost, k.endpoint+"/v1/transit/"+op+"/"+k.keyName, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", k.token)
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return nil, errors.Errorf("kms failed to %s the data key: %s", op, resp.Status)
+	}
+	var out struct {
+		Data kmsData `json:"data"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &out.Data, nil
+}
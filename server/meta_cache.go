@@ -0,0 +1,105 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Kinds of the cached metadata.
+const (
+	metaCacheLeaderPriority  = "leader-priority"
+	metaCacheClusterStatus   = "cluster-status"
+	metaCacheLabelProperty   = "label-property"
+	metaCacheNamespaceConfig = "namespace-config"
+)
+
+// metaCache is a read-through cache of the metadata in etcd. The metadata is
+// only written by the leader, so the cache is coherent during a leadership and
+// it is only enabled on the leader. It is flushed when the leadership changes,
+// that is, the leader key is put with a new lease.
+//
+// The label property and the namespace configs are part of the schedule
+// option, which is reloaded from etcd before the cache is enabled, so they are
+// read from the schedule option and invalidated by their setters.
+type metaCache struct {
+	sync.RWMutex
+	// epoch is the revision of the leader key put by this server, 0 if the
+	// server is not leader.
+	epoch int64
+	// gen is increased whenever items are flushed or invalidated, the value
+	// read before that is not cached.
+	gen   uint64
+	items map[string]interface{}
+}
+
+func newMetaCache() *metaCache {
+	return &metaCache{items: make(map[string]interface{})}
+}
+
+func metaCacheKey(kind string, id interface{}) string {
+	return fmt.Sprintf("%s/%v", kind, id)
+}
+
+// reset flushes the cache and starts a new epoch, 0 disables the cache.
+func (c *metaCache) reset(epoch int64) {
+	c.Lock()
+	defer c.Unlock()
+	c.epoch = epoch
+	c.gen++
+	c.items = make(map[string]interface{})
+}
+
+func (c *metaCache) getEpoch() int64 {
+	c.RLock()
+	defer c.RUnlock()
+	return c.epoch
+}
+
+// invalidate removes the item, it should be called after the metadata is
+// written.
+func (c *metaCache) invalidate(key string) {
+	c.Lock()
+	defer c.Unlock()
+	c.gen++
+	delete(c.items, key)
+}
+
+// load returns the cached item of the key, or reads it by fn and caches it.
+func (c *metaCache) load(kind, key string, fn func() (interface{}, error)) (interface{}, error) {
+	c.RLock()
+	enabled, gen := c.epoch != 0, c.gen
+	v, ok := c.items[key]
+	c.RUnlock()
+	if ok {
+		metaCacheCounter.WithLabelValues(kind, "hit").Inc()
+		return v, nil
+	}
+	if !enabled {
+		return fn()
+	}
+
+	metaCacheCounter.WithLabelValues(kind, "miss").Inc()
+	v, err := fn()
+	if err != nil {
+		return nil, err
+	}
+	c.Lock()
+	defer c.Unlock()
+	if c.gen == gen {
+		c.items[key] = v
+	}
+	return v, nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	. "github.com/pingcap/check"
+)
+
+var _ = Suite(&testMetaCacheSuite{})
+
+type testMetaCacheSuite struct{}
+
+func (s *testMetaCacheSuite) TestMetaCache(c *C) {
+	cache := newMetaCache()
+	reads := 0
+	read := func() (interface{}, error) {
+		reads++
+		return reads, nil
+	}
+	key := metaCacheKey(metaCacheLeaderPriority, 1)
+
+	// Disabled if not leader.
+	for i := 1; i <= 2; i++ {
+		v, err := cache.load(metaCacheLeaderPriority, key, read)
+		c.Assert(err, IsNil)
+		c.Assert(v, Equals, i)
+	}
+
+	cache.reset(10)
+	c.Assert(cache.getEpoch(), Equals, int64(10))
+	for i := 0; i < 2; i++ {
+		v, err := cache.load(metaCacheLeaderPriority, key, read)
+		c.Assert(err, IsNil)
+		c.Assert(v, Equals, 3)
+	}
+
+	cache.invalidate(key)
+	v, err := cache.load(metaCacheLeaderPriority, key, read)
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, 4)
+
+	// The value read before the invalidation is not cached.
+	v, err = cache.load(metaCacheLeaderPriority, metaCacheKey(metaCacheLeaderPriority, 2), func() (interface{}, error) {
+		cache.invalidate(metaCacheKey(metaCacheLeaderPriority, 2))
+		return 0, nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, 0)
+	_, ok := cache.items[metaCacheKey(metaCacheLeaderPriority, 2)]
+	c.Assert(ok, IsFalse)
+
+	// Flushed when the leadership changes.
+	cache.reset(0)
+	c.Assert(cache.items, HasLen, 0)
+	v, err = cache.load(metaCacheLeaderPriority, key, read)
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, 5)
+}
+
+func (s *testMetaCacheSuite) TestLeaderPriority(c *C) {
+	_, svr, cleanup, err := NewTestServer()
+	c.Assert(err, IsNil)
+	defer cleanup()
+	mustWaitLeader(c, []*Server{svr})
+	c.Assert(svr.metaCache.getEpoch(), Not(Equals), int64(0))
+
+	id := svr.ID()
+	priority, err := svr.GetMemberLeaderPriority(id)
+	c.Assert(err, IsNil)
+	c.Assert(priority, Equals, 0)
+	_, ok := svr.metaCache.items[metaCacheKey(metaCacheLeaderPriority, id)]
+	c.Assert(ok, IsTrue)
+
+	c.Assert(svr.SetMemberLeaderPriority(id, 5), IsNil)
+	priority, err = svr.GetMemberLeaderPriority(id)
+	c.Assert(err, IsNil)
+	c.Assert(priority, Equals, 5)
+
+	c.Assert(svr.DeleteMemberLeaderPriority(id), IsNil)
+	priority, err = svr.GetMemberLeaderPriority(id)
+	c.Assert(err, IsNil)
+	c.Assert(priority, Equals, 0)
+}
+
+func (s *testMetaCacheSuite) TestScheduleOption(c *C) {
+	_, svr, cleanup, err := NewTestServer()
+	c.Assert(err, IsNil)
+	defer cleanup()
+	mustWaitLeader(c, []*Server{svr})
+
+	c.Assert(svr.GetLabelProperty(), HasLen, 0)
+	_, ok := svr.metaCache.items[metaCacheKey(metaCacheLabelProperty, svr.clusterID)]
+	c.Assert(ok, IsTrue)
+	c.Assert(svr.SetLabelProperty("reject-leader", "zone", "z1"), IsNil)
+	c.Assert(svr.GetLabelProperty()["reject-leader"], DeepEquals, []StoreLabel{{Key: "zone", Value: "z1"}})
+	c.Assert(svr.DeleteLabelProperty("reject-leader", "zone", "z1"), IsNil)
+	c.Assert(svr.GetLabelProperty()["reject-leader"], HasLen, 0)
+
+	c.Assert(*svr.GetNamespaceConfig("ns1"), DeepEquals, NamespaceConfig{})
+	_, ok = svr.metaCache.items[metaCacheKey(metaCacheNamespaceConfig, "ns1")]
+	c.Assert(ok, IsTrue)
+	svr.SetNamespaceConfig("ns1", NamespaceConfig{LeaderScheduleLimit: 8})
+	c.Assert(svr.GetNamespaceConfig("ns1").LeaderScheduleLimit, Equals, uint64(8))
+	// The adjusted config is not cached.
+	c.Assert(svr.GetNamespaceConfigWithAdjust("ns1").RegionScheduleLimit, Not(Equals), uint64(0))
+	c.Assert(svr.GetNamespaceConfig("ns1").RegionScheduleLimit, Equals, uint64(0))
+	svr.DeleteNamespaceConfig("ns1")
+	c.Assert(*svr.GetNamespaceConfig("ns1"), DeepEquals, NamespaceConfig{})
+}
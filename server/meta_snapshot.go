@@ -0,0 +1,360 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pingcap/pd/pkg/extstorage"
+	"github.com/pingcap/pd/pkg/logutil"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	metaSnapshotTimeFormat = "20060102150405"
+	// maxRestoreTxnOps limits the operations in one etcd transaction when
+	// restoring a snapshot.
+	maxRestoreTxnOps = 128
+)
+
+// metaKV is a key-value pair of metadata. The key is relative to the root path
+// of the cluster.
+type metaKV struct {
+	Key   string `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// metaSnapshot is the content of a metadata snapshot.
+type metaSnapshot struct {
+	ClusterID  uint64    `json:"cluster_id"`
+	CreateTime time.Time `json:"create_time"`
+	// Revision is the etcd revision of EtcdKVs.
+	Revision  int64    `json:"revision"`
+	EtcdKVs   []metaKV `json:"etcd_kvs"`
+	RegionKVs []metaKV `json:"region_kvs"`
+}
+
+// MetaSnapshotInfo is the information of a metadata snapshot in the external
+// storage.
+type MetaSnapshotInfo struct {
+	Name       string    `json:"name"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+// metaSnapshots manages the metadata snapshots in the external storage.
+type metaSnapshots struct {
+	// mu serializes the snapshot operations.
+	mu      sync.Mutex
+	storage extstorage.ExternalStorage
+}
+
+func (s *Server) metaSnapshotPrefix() string {
+	return fmt.Sprintf("pd-meta-%d-", s.clusterID)
+}
+
+func (s *Server) getMetaSnapshotStorage() (extstorage.ExternalStorage, error) {
+	if s.metaSnapshots.storage == nil {
+		return nil, errors.New("meta snapshot storage is not configured")
+	}
+	return s.metaSnapshots.storage, nil
+}
+
+// CreateMetaSnapshot takes a snapshot of the metadata in etcd and the region
+// storage, then uploads it to the external storage. Old snapshots exceeding
+// the retention are deleted.
+func (s *Server) CreateMetaSnapshot() (*MetaSnapshotInfo, error) {
+	storage, err := s.getMetaSnapshotStorage()
+	if err != nil {
+		return nil, err
+	}
+	if !s.IsLeader() {
+		return nil, errors.Wrap(ErrNotLeader, "create meta snapshot failed")
+	}
+	s.metaSnapshots.mu.Lock()
+	defer s.metaSnapshots.mu.Unlock()
+
+	start := time.Now()
+	snapshot := &metaSnapshot{
+		ClusterID:  s.clusterID,
+		CreateTime: start,
+	}
+	ctx, cancel := s.readContext(s.client.Ctx())
+	defer cancel()
+	resp, err := kvGet(ctx, s.client, s.rootPath+"/", clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Revision = resp.Header.GetRevision()
+	for _, kv := range resp.Kvs {
+		key := strings.TrimPrefix(string(kv.Key), s.rootPath+"/")
+		snapshot.EtcdKVs = append(snapshot.EtcdKVs, metaKV{Key: key, Value: kv.Value})
+	}
+	if regionKV := s.kv.GetRegionKV(); regionKV != nil {
+		if err = regionKV.FlushRegion(); err != nil {
+			return nil, err
+		}
+		err = regionKV.Scan(func(key, value []byte) error {
+			snapshot.RegionKVs = append(snapshot.RegionKVs, metaKV{Key: string(key), Value: append([]byte{}, value...)})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := encodeMetaSnapshot(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	info := &MetaSnapshotInfo{
+		Name:       s.metaSnapshotPrefix() + start.UTC().Format(metaSnapshotTimeFormat),
+		CreateTime: start,
+	}
+	if err = storage.Write(info.Name, data); err != nil {
+		return nil, err
+	}
+	log.Infof("meta snapshot %s is created with %d etcd kvs and %d region kvs, size %d, cost %v",
+		info.Name, len(snapshot.EtcdKVs), len(snapshot.RegionKVs), len(data), time.Since(start))
+
+	s.gcMetaSnapshots(storage)
+	return info, nil
+}
+
+// gcMetaSnapshots deletes the oldest snapshots exceeding the retention.
+func (s *Server) gcMetaSnapshots(storage extstorage.ExternalStorage) {
+	names, err := s.listMetaSnapshotNames(storage)
+	if err != nil {
+		log.Errorf("failed to list meta snapshots: %v", err)
+		return
+	}
+	for i := 0; i < len(names)-int(s.cfg.MetaSnapshot.MaxSnapshots); i++ {
+		if err := storage.Delete(names[i]); err != nil {
+			log.Errorf("failed to delete meta snapshot %s: %v", names[i], err)
+			continue
+		}
+		log.Infof("meta snapshot %s is deleted", names[i])
+	}
+}
+
+func (s *Server) listMetaSnapshotNames(storage extstorage.ExternalStorage) ([]string, error) {
+	all, err := storage.List()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, name := range all {
+		if strings.HasPrefix(name, s.metaSnapshotPrefix()) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// GetMetaSnapshots lists the metadata snapshots of the cluster in the
+// external storage.
+func (s *Server) GetMetaSnapshots() ([]*MetaSnapshotInfo, error) {
+	storage, err := s.getMetaSnapshotStorage()
+	if err != nil {
+		return nil, err
+	}
+	names, err := s.listMetaSnapshotNames(storage)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]*MetaSnapshotInfo, 0, len(names))
+	for _, name := range names {
+		t, err := time.Parse(metaSnapshotTimeFormat, strings.TrimPrefix(name, s.metaSnapshotPrefix()))
+		if err != nil {
+			continue
+		}
+		infos = append(infos, &MetaSnapshotInfo{Name: name, CreateTime: t})
+	}
+	return infos, nil
+}
+
+// RestoreMetaSnapshot restores the metadata from the snapshot. It is used to
+// recover a PD cluster with the same cluster ID, so it is only allowed before
+// the cluster is bootstrapped. The leader and member information are not
+// restored, and the allocated ID is restored only if it is larger.
+func (s *Server) RestoreMetaSnapshot(name string) error {
+	storage, err := s.getMetaSnapshotStorage()
+	if err != nil {
+		return err
+	}
+	if !s.IsLeader() {
+		return errors.Wrap(ErrNotLeader, "restore meta snapshot failed")
+	}
+	if s.GetRaftCluster() != nil {
+		return errors.WithStack(core.AlreadyBootstrappedErr{ClusterID: s.clusterID})
+	}
+	s.metaSnapshots.mu.Lock()
+	defer s.metaSnapshots.mu.Unlock()
+
+	data, err := storage.Read(name)
+	if err != nil {
+		return err
+	}
+	snapshot, err := decodeMetaSnapshot(data)
+	if err != nil {
+		return err
+	}
+	if snapshot.ClusterID != s.clusterID {
+		return errors.Errorf("mismatch cluster id, need %d but got %d", s.clusterID, snapshot.ClusterID)
+	}
+
+	var ops []clientv3.Op
+	for _, kv := range snapshot.EtcdKVs {
+		if kv.Key == "leader" || kv.Key == "timestamp" || strings.HasPrefix(kv.Key, "member/") {
+			continue
+		}
+		if kv.Key == "alloc_id" {
+			if err = s.restoreAllocID(kv.Value); err != nil {
+				return err
+			}
+			continue
+		}
+		ops = append(ops, clientv3.OpPut(s.rootPath+"/"+kv.Key, string(kv.Value)))
+		if len(ops) == maxRestoreTxnOps {
+			if err = s.commitRestoreOps(ops); err != nil {
+				return err
+			}
+			ops = ops[:0]
+		}
+	}
+	if err = s.commitRestoreOps(ops); err != nil {
+		return err
+	}
+
+	if regionKV := s.kv.GetRegionKV(); regionKV != nil {
+		for _, kv := range snapshot.RegionKVs {
+			if err = regionKV.Save(s.kv.Context(), kv.Key, string(kv.Value)); err != nil {
+				return err
+			}
+		}
+	}
+	log.Infof("meta snapshot %s is restored", name)
+	return s.createRaftCluster()
+}
+
+func (s *Server) commitRestoreOps(ops []clientv3.Op) error {
+	if len(ops) == 0 {
+		return nil
+	}
+	resp, err := s.leaderTxn().Then(ops...).Commit()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if !resp.Succeeded {
+		return errors.Wrap(ErrNotLeader, "restore meta snapshot failed")
+	}
+	return nil
+}
+
+func (s *Server) restoreAllocID(value []byte) error {
+	restored, err := bytesToUint64(value)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := s.readContext(s.client.Ctx())
+	defer cancel()
+	current, err := getValue(ctx, s.client, s.getAllocIDPath())
+	if err != nil {
+		return err
+	}
+	if current != nil {
+		end, err := bytesToUint64(current)
+		if err != nil {
+			return err
+		}
+		if end >= restored {
+			return nil
+		}
+	}
+	resp, err := s.leaderTxn().Then(clientv3.OpPut(s.getAllocIDPath(), string(value))).Commit()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if !resp.Succeeded {
+		return errors.Wrap(ErrNotLeader, "restore meta snapshot failed")
+	}
+	// Drop the cached IDs which may be allocated by the restored cluster.
+	s.idAlloc.reset()
+	return nil
+}
+
+func (s *Server) metaSnapshotLoop() {
+	defer logutil.LogPanic()
+	defer s.serverLoopWg.Done()
+
+	if s.metaSnapshots.storage == nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(s.serverLoopCtx)
+	defer cancel()
+	ticker := time.NewTicker(s.cfg.MetaSnapshot.Interval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if !s.IsLeader() || s.GetRaftCluster() == nil {
+				continue
+			}
+			if _, err := s.CreateMetaSnapshot(); err != nil {
+				log.Errorf("failed to create meta snapshot: %v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func encodeMetaSnapshot(snapshot *metaSnapshot) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(w).Encode(snapshot); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeMetaSnapshot(data []byte) (*metaSnapshot, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer r.Close()
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	snapshot := &metaSnapshot{}
+	if err := json.Unmarshal(content, snapshot); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/coreos/etcd/clientv3"
+	. "github.com/pingcap/check"
+)
+
+var _ = Suite(&testMetaSnapshotSuite{})
+
+type testMetaSnapshotSuite struct {
+	baseCluster
+}
+
+func (s *testMetaSnapshotSuite) TestCreateAndRestore(c *C) {
+	dir, err := ioutil.TempDir("/tmp", "test_pd_meta_snapshot")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+
+	cfg := NewTestSingleConfig()
+	cfg.MetaSnapshot.Storage = dir
+	svrs, cleanup := newTestServersWithCfgs(c, []*Config{cfg})
+	defer cleanup()
+	s.svr = svrs[0]
+
+	req := s.newBootstrapRequest(c, s.svr.clusterID, "127.0.0.1:0")
+	_, err = s.svr.bootstrapCluster(req)
+	c.Assert(err, IsNil)
+
+	info, err := s.svr.CreateMetaSnapshot()
+	c.Assert(err, IsNil)
+	infos, err := s.svr.GetMetaSnapshots()
+	c.Assert(err, IsNil)
+	c.Assert(infos, HasLen, 1)
+	c.Assert(infos[0].Name, Equals, info.Name)
+
+	// Restore is not allowed after bootstrapped.
+	c.Assert(s.svr.RestoreMetaSnapshot(info.Name), NotNil)
+
+	// Drop the cluster metadata.
+	s.svr.stopRaftCluster()
+	_, err = s.svr.client.Delete(context.TODO(), path.Join(s.svr.rootPath, "raft"), clientv3.WithPrefix())
+	c.Assert(err, IsNil)
+	c.Assert(s.svr.GetRaftCluster(), IsNil)
+
+	c.Assert(s.svr.RestoreMetaSnapshot(info.Name), IsNil)
+	cluster := s.svr.GetRaftCluster()
+	c.Assert(cluster, NotNil)
+	store, err := cluster.GetStore(req.GetStore().GetId())
+	c.Assert(err, IsNil)
+	c.Assert(store.GetAddress(), Equals, req.GetStore().GetAddress())
+	c.Assert(cluster.GetRegionInfoByKey([]byte("a")).GetID(), Equals, req.GetRegion().GetId())
+}
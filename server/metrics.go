@@ -65,6 +65,14 @@ var (
 			Help:      "Number of regions in the different label level.",
 		}, []string{"type"})
 
+	quotaExceededCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "cluster",
+			Name:      "quota_exceeded_total",
+			Help:      "Counter of the operations rejected by the namespace quotas.",
+		}, []string{"namespace", "quota"})
+
 	timeJumpBackCounter = prometheus.NewCounter(
 		prometheus.CounterOpts{
 			Namespace: "pd",
@@ -73,6 +81,14 @@ var (
 			Help:      "Counter of system time jumps backward.",
 		})
 
+	regionInconsistencyGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "cluster",
+			Name:      "region_inconsistency",
+			Help:      "The divergent regions between the cache and the storage found by the last consistency check.",
+		}, []string{"type"})
+
 	schedulerStatusGauge = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: "pd",
@@ -81,6 +97,14 @@ var (
 			Help:      "Status of the scheduler.",
 		}, []string{"kind", "type"})
 
+	scheduleHaltGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "scheduler",
+			Name:      "halt",
+			Help:      "Whether the balance scheduling is halted for the reason.",
+		}, []string{"reason"})
+
 	regionHeartbeatCounter = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: "pd",
@@ -89,6 +113,38 @@ var (
 			Help:      "Counter of region hearbeat.",
 		}, []string{"store", "type", "status"})
 
+	regionHeartbeatQueueGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "scheduler",
+			Name:      "region_heartbeat_queue",
+			Help:      "Number of queued region heartbeat responses of stores.",
+		}, []string{"store"})
+
+	regionHeartbeatApplyQueueGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "scheduler",
+			Name:      "region_heartbeat_apply_queue",
+			Help:      "The length of the region heartbeat queue of each apply worker.",
+		}, []string{"worker"})
+
+	regionHeartbeatDropCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "scheduler",
+			Name:      "region_heartbeat_dropped",
+			Help:      "Counter of dropped region heartbeat responses.",
+		}, []string{"store", "reason"})
+
+	regionHeartbeatSkipCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "scheduler",
+			Name:      "region_heartbeat_skipped",
+			Help:      "Counter of region heartbeats skipped as unchanged.",
+		}, []string{"reason"})
+
 	regionHeartbeatLatency = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Namespace: "pd",
@@ -122,6 +178,23 @@ var (
 			Help:      "Counter of tso events",
 		}, []string{"type"})
 
+	tsoWindowWaitDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "pd",
+			Subsystem: "server",
+			Name:      "tso_window_wait_duration_seconds",
+			Help:      "Bucketed histogram of waiting time (s) of saving the exhausted tso window.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 13),
+		})
+
+	clusterEventCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "server",
+			Name:      "cluster_events_total",
+			Help:      "Counter of cluster events.",
+		}, []string{"type", "result"})
+
 	metadataGauge = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: "pd",
@@ -138,6 +211,72 @@ var (
 			Help:      "Etcd raft states.",
 		}, []string{"type"})
 
+	etcdEndpointCheckCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "server",
+			Name:      "etcd_endpoint_check_total",
+			Help:      "Counter of health checks of etcd client endpoints.",
+		}, []string{"endpoint", "result"})
+
+	etcdClientRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "pd",
+			Subsystem: "server",
+			Name:      "etcd_client_request_duration_seconds",
+			Help:      "Bucketed histogram of latency (s) of etcd client requests to each endpoint.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 13),
+		}, []string{"endpoint", "method"})
+
+	etcdClientRequestFailedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "server",
+			Name:      "etcd_client_request_failed_total",
+			Help:      "Counter of failed etcd client requests to each endpoint.",
+		}, []string{"endpoint", "method"})
+
+	etcdBackendGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "server",
+			Name:      "etcd_backend",
+			Help:      "Backend size of the etcd members.",
+		}, []string{"member", "type"})
+
+	etcdMaintenanceCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "server",
+			Name:      "etcd_maintenance_total",
+			Help:      "Counter of the compactions and defragmentations of etcd.",
+		}, []string{"type", "result"})
+
+	forwardedRequestCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "server",
+			Name:      "forwarded_requests_total",
+			Help:      "Counter of requests forwarded to the leader.",
+		}, []string{"method", "result"})
+
+	forwardedRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "pd",
+			Subsystem: "server",
+			Name:      "forwarded_requests_duration_seconds",
+			Help:      "Bucketed histogram of processing time (s) of requests forwarded to the leader.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 13),
+		}, []string{"method"})
+
+	metaCacheCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "server",
+			Name:      "meta_cache_total",
+			Help:      "Counter of the reads of the metadata cache, by the member leader priority, the cluster status, the label property and the namespace config.",
+		}, []string{"type", "result"})
+
 	patrolCheckRegionsHistogram = prometheus.NewHistogram(
 		prometheus.HistogramOpts{
 			Namespace: "pd",
@@ -146,22 +285,67 @@ var (
 			Help:      "Bucketed histogram of time spend(s) of patrol checks region.",
 			Buckets:   prometheus.ExponentialBuckets(1, 2, 15),
 		})
+
+	urgentRepairRegionGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "patrol",
+			Name:      "urgent_repair_regions",
+			Help:      "The number of regions which are about to lose or have lost their quorum.",
+		}, []string{"priority"})
+
+	slowRequestCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "server",
+			Name:      "slow_requests_total",
+			Help:      "Counter of the logged slow requests.",
+		}, []string{"protocol"})
+
+	lockWaitDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "pd",
+			Subsystem: "server",
+			Name:      "lock_wait_duration_seconds",
+			Help:      "Bucketed histogram of the time (s) waiting for the cluster locks.",
+			Buckets:   prometheus.ExponentialBuckets(0.000001, 4, 13),
+		}, []string{"lock", "mode"})
 )
 
 func init() {
 	prometheus.MustRegister(txnCounter)
 	prometheus.MustRegister(txnDuration)
+	prometheus.MustRegister(slowRequestCounter)
+	prometheus.MustRegister(lockWaitDuration)
 	prometheus.MustRegister(clusterStatusGauge)
 	prometheus.MustRegister(timeJumpBackCounter)
 	prometheus.MustRegister(schedulerStatusGauge)
+	prometheus.MustRegister(regionInconsistencyGauge)
+	prometheus.MustRegister(scheduleHaltGauge)
 	prometheus.MustRegister(regionHeartbeatCounter)
+	prometheus.MustRegister(regionHeartbeatQueueGauge)
+	prometheus.MustRegister(regionHeartbeatApplyQueueGauge)
+	prometheus.MustRegister(regionHeartbeatDropCounter)
+	prometheus.MustRegister(regionHeartbeatSkipCounter)
 	prometheus.MustRegister(regionHeartbeatLatency)
 	prometheus.MustRegister(hotSpotStatusGauge)
 	prometheus.MustRegister(tsoCounter)
+	prometheus.MustRegister(tsoWindowWaitDuration)
+	prometheus.MustRegister(clusterEventCounter)
 	prometheus.MustRegister(storeStatusGauge)
 	prometheus.MustRegister(regionStatusGauge)
 	prometheus.MustRegister(regionLabelLevelGauge)
 	prometheus.MustRegister(metadataGauge)
 	prometheus.MustRegister(etcdStateGauge)
+	prometheus.MustRegister(etcdEndpointCheckCounter)
+	prometheus.MustRegister(etcdClientRequestDuration)
+	prometheus.MustRegister(etcdClientRequestFailedCounter)
+	prometheus.MustRegister(etcdBackendGauge)
+	prometheus.MustRegister(etcdMaintenanceCounter)
+	prometheus.MustRegister(forwardedRequestCounter)
+	prometheus.MustRegister(forwardedRequestDuration)
 	prometheus.MustRegister(patrolCheckRegionsHistogram)
+	prometheus.MustRegister(urgentRepairRegionGauge)
+	prometheus.MustRegister(metaCacheCounter)
+	prometheus.MustRegister(quotaExceededCounter)
 }
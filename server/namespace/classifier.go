@@ -40,6 +40,15 @@ type Classifier interface {
 	ReloadNamespaces() error
 }
 
+// StoreBinder is implemented by the classifiers which bind stores to
+// namespaces explicitly.
+type StoreBinder interface {
+	// GetNamespaceStoreIDs returns the IDs of the stores bound to each
+	// namespace.
+	GetNamespaceStoreIDs() map[string][]uint64
+	RemoveNamespaceStoreID(name string, storeID uint64) error
+}
+
 type defaultClassifier struct{}
 
 func (c defaultClassifier) GetAllNamespaces() []string {
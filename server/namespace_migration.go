@@ -0,0 +1,323 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/pingcap/errcode"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/namespace"
+	"github.com/pingcap/pd/server/schedule"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultMigrationConcurrency is the running operators of a namespace
+	// migration job when it is not specified.
+	defaultMigrationConcurrency    = 4
+	namespaceMigrationInterval     = 5 * time.Second
+	namespaceMigrationOperatorDesc = "migrate-namespace-region"
+)
+
+// namespaceMigrationJobType is the job type of namespace migration.
+const namespaceMigrationJobType = "namespace-migration"
+
+func init() {
+	registerJobRunner(namespaceMigrationJobType, runNamespaceMigrationJob)
+}
+
+// NamespaceMigrationJob is an asynchronous job which moves the peers of all
+// regions in a namespace to a set of stores. It moves the regions much faster
+// than the namespace checker, with the running operators and the operators
+// created per minute throttled.
+type NamespaceMigrationJob struct {
+	ID               uint64    `json:"id"`
+	Namespace        string    `json:"namespace"`
+	StoreIDs         []uint64  `json:"store_ids"`
+	Concurrency      int       `json:"concurrency"`
+	RegionsPerMinute int       `json:"regions_per_minute"`
+	Status           string    `json:"status"`
+	Progress         float64   `json:"progress"`
+	TotalRegions     int       `json:"total_regions"`
+	MigratedRegions  int       `json:"migrated_regions"`
+	RunningOperators int       `json:"running_operators"`
+	Error            string    `json:"error,omitempty"`
+	CreateTime       time.Time `json:"create_time"`
+	FinishTime       time.Time `json:"finish_time,omitempty"`
+}
+
+// namespaceMigrationArgs are the arguments of a namespace migration job.
+type namespaceMigrationArgs struct {
+	Namespace string   `json:"namespace"`
+	StoreIDs  []uint64 `json:"store_ids"`
+	// Concurrency is the max running operators.
+	Concurrency int `json:"concurrency"`
+	// RegionsPerMinute is the max operators created per minute, 0 means no
+	// limit.
+	RegionsPerMinute int `json:"regions_per_minute"`
+}
+
+// namespaceMigrationState is the progress of a namespace migration job.
+type namespaceMigrationState struct {
+	TotalRegions     int `json:"total_regions"`
+	MigratedRegions  int `json:"migrated_regions"`
+	RunningOperators int `json:"running_operators"`
+}
+
+func newNamespaceMigrationJob(job *Job) *NamespaceMigrationJob {
+	var args namespaceMigrationArgs
+	if err := json.Unmarshal(job.Args, &args); err != nil {
+		log.Errorf("[job %d] failed to decode the namespace migration args: %v", job.ID, err)
+	}
+	var state namespaceMigrationState
+	if len(job.State) > 0 {
+		if err := json.Unmarshal(job.State, &state); err != nil {
+			log.Errorf("[job %d] failed to decode the namespace migration state: %v", job.ID, err)
+		}
+	}
+	return &NamespaceMigrationJob{
+		ID:               job.ID,
+		Namespace:        args.Namespace,
+		StoreIDs:         args.StoreIDs,
+		Concurrency:      args.Concurrency,
+		RegionsPerMinute: args.RegionsPerMinute,
+		Status:           job.Status,
+		Progress:         job.Progress,
+		TotalRegions:     state.TotalRegions,
+		MigratedRegions:  state.MigratedRegions,
+		RunningOperators: state.RunningOperators,
+		Error:            job.Error,
+		CreateTime:       job.CreateTime,
+		FinishTime:       job.FinishTime,
+	}
+}
+
+// MigrateNamespace starts a job to move all regions of the namespace to the
+// stores. The stores should belong to the namespace, otherwise the namespace
+// checker moves the regions back. All stores of the namespace are used if
+// storeIDs is empty, and concurrency is set to the default value if it is 0.
+func (h *Handler) MigrateNamespace(name string, storeIDs []uint64, concurrency, regionsPerMinute int) (*NamespaceMigrationJob, error) {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return nil, err
+	}
+	if name != namespace.DefaultNamespace && !c.classifier.IsNamespaceExist(name) {
+		return nil, errors.Errorf("namespace %s does not exist", name)
+	}
+	if concurrency < 0 || regionsPerMinute < 0 {
+		return nil, errors.Errorf("invalid concurrency %d or regions per minute %d", concurrency, regionsPerMinute)
+	}
+	if concurrency == 0 {
+		concurrency = defaultMigrationConcurrency
+	}
+	if len(storeIDs) == 0 {
+		for _, store := range c.cluster.GetStores() {
+			if !store.IsTombstone() && c.classifier.GetStoreNamespace(store) == name {
+				storeIDs = append(storeIDs, store.GetId())
+			}
+		}
+	}
+	for _, id := range storeIDs {
+		store := c.cluster.GetStore(id)
+		if store == nil {
+			return nil, core.NewStoreNotFoundErr(id)
+		}
+		if store.IsTombstone() {
+			return nil, errcode.Op("namespace.migrate").AddTo(core.StoreTombstonedErr{StoreID: id})
+		}
+		if ns := c.classifier.GetStoreNamespace(store); ns != name {
+			return nil, errors.Errorf("store %d belongs to namespace %s", id, ns)
+		}
+	}
+	if maxReplicas := c.cluster.GetOpt().GetMaxReplicas(name); len(storeIDs) < maxReplicas {
+		return nil, errors.Errorf("%d stores are not enough for %d replicas", len(storeIDs), maxReplicas)
+	}
+	sort.Slice(storeIDs, func(i, j int) bool { return storeIDs[i] < storeIDs[j] })
+
+	args := &namespaceMigrationArgs{
+		Namespace:        name,
+		StoreIDs:         storeIDs,
+		Concurrency:      concurrency,
+		RegionsPerMinute: regionsPerMinute,
+	}
+	job, err := h.s.jobs.create(namespaceMigrationJobType, args, nil)
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("[job %d] start to migrate namespace %s to stores %v", job.ID, name, storeIDs)
+	return newNamespaceMigrationJob(job), nil
+}
+
+// GetNamespaceMigrationJob returns the namespace migration job with the ID.
+func (h *Handler) GetNamespaceMigrationJob(id uint64) *NamespaceMigrationJob {
+	job, err := h.s.jobs.get(id)
+	if err != nil || job.Type != namespaceMigrationJobType {
+		return nil
+	}
+	return newNamespaceMigrationJob(job)
+}
+
+// GetNamespaceMigrationJobs returns all namespace migration jobs.
+func (h *Handler) GetNamespaceMigrationJobs() []*NamespaceMigrationJob {
+	jobs := h.s.jobs.list(namespaceMigrationJobType)
+	res := make([]*NamespaceMigrationJob, 0, len(jobs))
+	for _, job := range jobs {
+		res = append(res, newNamespaceMigrationJob(job))
+	}
+	return res
+}
+
+func runNamespaceMigrationJob(jc *JobContext) error {
+	var args namespaceMigrationArgs
+	if err := jc.Args(&args); err != nil {
+		return err
+	}
+	var state namespaceMigrationState
+	if _, err := jc.State(&state); err != nil {
+		return err
+	}
+	h := jc.Handler()
+
+	ticker := time.NewTicker(namespaceMigrationInterval)
+	defer ticker.Stop()
+	// allowance is the operators allowed to create by RegionsPerMinute.
+	allowance := float64(args.Concurrency)
+	for {
+		c, err := h.getCoordinator()
+		if err != nil {
+			return err
+		}
+		var pending []*core.RegionInfo
+		current := namespaceMigrationState{}
+		for _, region := range c.cluster.getRegions() {
+			if c.classifier.GetRegionNamespace(region) != args.Namespace {
+				continue
+			}
+			current.TotalRegions++
+			if !hasPeerOutside(region, args.StoreIDs) {
+				current.MigratedRegions++
+				continue
+			}
+			if op := c.opController.GetOperator(region.GetID()); op != nil {
+				if op.Desc() == namespaceMigrationOperatorDesc {
+					current.RunningOperators++
+				}
+				continue
+			}
+			pending = append(pending, region)
+		}
+		if current != state {
+			state = current
+			progress := float64(100)
+			if state.TotalRegions > 0 {
+				progress = float64(state.MigratedRegions) * 100 / float64(state.TotalRegions)
+			}
+			if err = jc.Update(progress, state); err != nil {
+				return err
+			}
+		}
+		if state.MigratedRegions == state.TotalRegions {
+			log.Infof("[job %d] namespace %s is migrated with %d regions", jc.ID(), args.Namespace, state.TotalRegions)
+			return nil
+		}
+
+		budget := args.Concurrency - state.RunningOperators
+		if args.RegionsPerMinute > 0 && budget > int(allowance) {
+			budget = int(allowance)
+		}
+		created := createNamespaceMigrationOperators(jc, c, pending, args.StoreIDs, budget)
+		if args.RegionsPerMinute > 0 {
+			allowance -= float64(created)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-jc.Done():
+			return jc.Err()
+		}
+		if args.RegionsPerMinute > 0 {
+			allowance += float64(args.RegionsPerMinute) * namespaceMigrationInterval.Minutes()
+			if allowance > float64(args.Concurrency) {
+				allowance = float64(args.Concurrency)
+			}
+		}
+	}
+}
+
+// createNamespaceMigrationOperators moves a peer outside the target stores for
+// at most budget regions, and returns the count of operators created.
+func createNamespaceMigrationOperators(jc *JobContext, c *coordinator, regions []*core.RegionInfo, storeIDs []uint64, budget int) int {
+	// The sizes moved to the stores, which are not reported by heartbeats yet.
+	deltas := make(map[uint64]int64)
+	var created int
+	for _, region := range regions {
+		if created >= budget {
+			break
+		}
+		var source uint64
+		for _, peer := range region.GetPeers() {
+			if !containsStoreID(storeIDs, peer.GetStoreId()) {
+				source = peer.GetStoreId()
+				break
+			}
+		}
+		var target *core.StoreInfo
+		for _, id := range storeIDs {
+			store := c.cluster.GetStore(id)
+			if store == nil || !store.IsUp() || store.IsDisconnected() || region.GetStorePeer(id) != nil {
+				continue
+			}
+			if target == nil || store.RegionScore(c.cluster.GetHighSpaceRatio(), c.cluster.GetLowSpaceRatio(), deltas[id]) <
+				target.RegionScore(c.cluster.GetHighSpaceRatio(), c.cluster.GetLowSpaceRatio(), deltas[target.GetId()]) {
+				target = store
+			}
+		}
+		if target == nil {
+			log.Warnf("[job %d] no store to move region %d to", jc.ID(), region.GetID())
+			continue
+		}
+		peer, err := c.cluster.AllocPeer(target.GetId())
+		if err != nil {
+			log.Warnf("[job %d] failed to allocate peer for region %d: %v", jc.ID(), region.GetID(), err)
+			continue
+		}
+		op := schedule.CreateMovePeerOperator(namespaceMigrationOperatorDesc, c.cluster, region, schedule.OpAdmin, source, target.GetId(), peer.GetId())
+		if !c.opController.AddOperator(op) {
+			continue
+		}
+		deltas[target.GetId()] += region.GetApproximateSize()
+		created++
+	}
+	return created
+}
+
+// hasPeerOutside checks if the region has a peer outside the stores.
+func hasPeerOutside(region *core.RegionInfo, storeIDs []uint64) bool {
+	for _, peer := range region.GetPeers() {
+		if !containsStoreID(storeIDs, peer.GetStoreId()) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsStoreID checks if the sorted store IDs contain the ID.
+func containsStoreID(storeIDs []uint64, id uint64) bool {
+	i := sort.Search(len(storeIDs), func(i int) bool { return storeIDs[i] >= id })
+	return i < len(storeIDs) && storeIDs[i] == id
+}
@@ -183,6 +183,49 @@ func (s *testNamespaceSuite) TestSchedulerBalanceLeader(c *C) {
 	c.Assert(op, IsNil)
 }
 
+func (s *testNamespaceSuite) TestOperatorLimit(c *C) {
+	s.tc.addRegionStore(1, 0)
+	s.tc.addRegionStore(2, 0)
+	for id := uint64(1); id <= 4; id++ {
+		s.tc.addLeaderRegion(id, 1, 2)
+	}
+	s.classifier.setRegion(1, "ns1")
+	s.classifier.setRegion(2, "ns1")
+	s.opt.ns["ns1"] = newNamespaceOption(&NamespaceConfig{LeaderScheduleLimit: 1})
+
+	hbStreams := newHeartbeatStreams(s.tc.getClusterID())
+	defer hbStreams.Close()
+	co := newCoordinator(s.tc.clusterInfo, hbStreams, s.classifier)
+	oc := co.opController
+	newOp := func(regionID uint64) *schedule.Operator {
+		region := s.tc.GetRegion(regionID)
+		return schedule.NewOperator("test", regionID, region.GetRegionEpoch(), schedule.OpLeader, schedule.TransferLeader{FromStore: 1, ToStore: 2})
+	}
+
+	// The limit of ns1 is reached.
+	c.Assert(oc.AddOperator(newOp(1)), IsTrue)
+	c.Assert(oc.AddOperator(newOp(2)), IsFalse)
+	c.Assert(oc.OperatorNamespaceCount("ns1", schedule.OpLeader), Equals, uint64(1))
+
+	// The other namespace is not affected.
+	c.Assert(oc.AddOperator(newOp(3)), IsTrue)
+	c.Assert(oc.OperatorNamespaceCount(namespace.DefaultNamespace, schedule.OpLeader), Equals, uint64(1))
+	c.Assert(oc.OperatorCount(schedule.OpLeader), Equals, uint64(2))
+
+	// The operators of high priority are not limited.
+	op := newOp(2)
+	op.SetPriorityLevel(core.HighPriority)
+	c.Assert(oc.AddOperator(op), IsTrue)
+	c.Assert(oc.OperatorNamespaceCount("ns1", schedule.OpLeader), Equals, uint64(2))
+
+	// An operator can replace the one of lower priority in the same namespace.
+	oc.RemoveOperator(op)
+	op = newOp(1)
+	op.SetPriorityLevel(core.HighPriority)
+	c.Assert(oc.AddOperator(op), IsTrue)
+	c.Assert(oc.OperatorNamespaceCount("ns1", schedule.OpLeader), Equals, uint64(1))
+}
+
 type mapClassifer struct {
 	stores  map[uint64]string
 	regions map[uint64]string
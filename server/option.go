@@ -26,12 +26,13 @@ import (
 
 // scheduleOption is a wrapper to access the configuration safely.
 type scheduleOption struct {
-	v              atomic.Value
-	rep            *Replication
-	ns             map[string]*namespaceOption
-	labelProperty  atomic.Value
-	clusterVersion atomic.Value
-	pdServerConfig atomic.Value
+	v               atomic.Value
+	rep             *Replication
+	ns              map[string]*namespaceOption
+	labelProperty   atomic.Value
+	mergeProtection atomic.Value
+	clusterVersion  atomic.Value
+	pdServerConfig  atomic.Value
 }
 
 func newScheduleOption(cfg *Config) *scheduleOption {
@@ -45,6 +46,7 @@ func newScheduleOption(cfg *Config) *scheduleOption {
 	o.rep = newReplication(&cfg.Replication)
 	o.pdServerConfig.Store(&cfg.PDServerCfg)
 	o.labelProperty.Store(cfg.LabelProperty)
+	o.mergeProtection.Store(cfg.MergeProtection)
 	o.clusterVersion.Store(cfg.ClusterVersion)
 	return o
 }
@@ -76,6 +78,14 @@ func (o *scheduleOption) GetLocationLabels() []string {
 	return o.rep.GetLocationLabels()
 }
 
+func (o *scheduleOption) GetIsolationLevel() string {
+	return o.rep.GetIsolationLevel()
+}
+
+func (o *scheduleOption) GetStrictlyMatchLabel() bool {
+	return o.rep.GetStrictlyMatchLabel()
+}
+
 func (o *scheduleOption) GetMaxSnapshotCount() uint64 {
 	return o.load().MaxSnapshotCount
 }
@@ -84,6 +94,18 @@ func (o *scheduleOption) GetMaxPendingPeerCount() uint64 {
 	return o.load().MaxPendingPeerCount
 }
 
+func (o *scheduleOption) GetMaxPendingCompactionBytes() uint64 {
+	return uint64(o.load().MaxPendingCompactionBytes)
+}
+
+func (o *scheduleOption) GetMinSnapshotSpeed() uint64 {
+	return uint64(o.load().MinSnapshotSpeed)
+}
+
+func (o *scheduleOption) GetSnapshotBandwidth() uint64 {
+	return uint64(o.load().SnapshotBandwidth)
+}
+
 func (o *scheduleOption) GetMaxMergeRegionSize() uint64 {
 	return o.load().MaxMergeRegionSize
 }
@@ -96,6 +118,14 @@ func (o *scheduleOption) GetSplitMergeInterval() time.Duration {
 	return o.load().SplitMergeInterval.Duration
 }
 
+func (o *scheduleOption) GetSplitQPSThreshold() uint64 {
+	return o.load().SplitQPSThreshold
+}
+
+func (o *scheduleOption) GetSplitHotDuration() time.Duration {
+	return o.load().SplitHotDuration.Duration
+}
+
 func (o *scheduleOption) GetPatrolRegionInterval() time.Duration {
 	return o.load().PatrolRegionInterval.Duration
 }
@@ -132,6 +162,18 @@ func (o *scheduleOption) GetMergeScheduleLimit(name string) uint64 {
 	return o.load().MergeScheduleLimit
 }
 
+// GetNamespaceQuota returns the quota of the namespace.
+func (o *scheduleOption) GetNamespaceQuota(name string) NamespaceQuota {
+	if n, ok := o.ns[name]; ok {
+		return n.load().NamespaceQuota
+	}
+	return NamespaceQuota{}
+}
+
+func (o *scheduleOption) GetLeaderScheduleBatch() uint64 {
+	return o.load().LeaderScheduleBatch
+}
+
 func (o *scheduleOption) GetTolerantSizeRatio() float64 {
 	return o.load().TolerantSizeRatio
 }
@@ -140,6 +182,26 @@ func (o *scheduleOption) GetLowSpaceRatio() float64 {
 	return o.load().LowSpaceRatio
 }
 
+func (o *scheduleOption) GetRegionStatsChangeRatio() float64 {
+	return o.load().RegionStatsChangeRatio
+}
+
+func (o *scheduleOption) GetRegionCollectFactor() float64 {
+	return o.load().RegionCollectFactor
+}
+
+func (o *scheduleOption) GetRegionConsistencyCheckInterval() time.Duration {
+	return o.load().RegionConsistencyCheckInterval.Duration
+}
+
+func (o *scheduleOption) GetHaltLowSpaceStoreRatio() float64 {
+	return o.load().HaltLowSpaceStoreRatio
+}
+
+func (o *scheduleOption) GetHaltEtcdLatency() time.Duration {
+	return o.load().HaltEtcdLatency.Duration
+}
+
 func (o *scheduleOption) GetHighSpaceRatio() float64 {
 	return o.load().HighSpaceRatio
 }
@@ -148,6 +210,10 @@ func (o *scheduleOption) IsRaftLearnerEnabled() bool {
 	return !o.load().DisableLearner
 }
 
+func (o *scheduleOption) IsJointConsensusEnabled() bool {
+	return o.load().EnableJointConsensus
+}
+
 func (o *scheduleOption) IsRemoveDownReplicaEnabled() bool {
 	return !o.load().DisableRemoveDownReplica
 }
@@ -172,6 +238,14 @@ func (o *scheduleOption) IsNamespaceRelocationEnabled() bool {
 	return !o.load().DisableNamespaceRelocation
 }
 
+func (o *scheduleOption) IsLoadSplitEnabled() bool {
+	return !o.load().DisableLoadSplit
+}
+
+func (o *scheduleOption) IsDebugSchedulersEnabled() bool {
+	return o.load().EnableDebugSchedulers
+}
+
 func (o *scheduleOption) GetSchedulers() SchedulerConfigs {
 	return o.load().Schedulers
 }
@@ -183,16 +257,18 @@ func (o *scheduleOption) AddSchedulerCfg(tp string, args []string) error {
 		// comparing args is to cover the case that there are schedulers in same type but not with same name
 		// such as two schedulers of type "evict-leader",
 		// one name is "evict-leader-scheduler-1" and the other is "evict-leader-scheduler-2"
-		if reflect.DeepEqual(schedulerCfg, SchedulerConfig{Type: tp, Args: args, Disable: false}) {
-			return nil
+		if schedulerCfg.Type != tp || !reflect.DeepEqual(schedulerCfg.Args, args) {
+			continue
 		}
-
-		if reflect.DeepEqual(schedulerCfg, SchedulerConfig{Type: tp, Args: args, Disable: true}) {
-			schedulerCfg.Disable = false
-			v.Schedulers[i] = schedulerCfg
-			o.store(v)
+		if !schedulerCfg.Disable {
 			return nil
 		}
+		// a re-enabled scheduler starts with its default config
+		schedulerCfg.Disable = false
+		schedulerCfg.Config = ""
+		v.Schedulers[i] = schedulerCfg
+		o.store(v)
+		return nil
 	}
 	v.Schedulers = append(v.Schedulers, SchedulerConfig{Type: tp, Args: args, Disable: false})
 	o.store(v)
@@ -222,6 +298,26 @@ func (o *scheduleOption) RemoveSchedulerCfg(name string) error {
 	return nil
 }
 
+// UpdateSchedulerCfg records the JSON config of the scheduler with the given
+// name, so that it is restored when the scheduler is created again.
+func (o *scheduleOption) UpdateSchedulerCfg(name string, config string) error {
+	c := o.load()
+	v := c.clone()
+	for i, schedulerCfg := range v.Schedulers {
+		// To create a temporary scheduler is just used to get scheduler's name
+		tmp, err := schedule.CreateScheduler(schedulerCfg.Type, schedule.NewOperatorController(nil, nil), schedulerCfg.Args...)
+		if err != nil {
+			return err
+		}
+		if tmp.GetName() == name {
+			v.Schedulers[i].Config = config
+			o.store(v)
+			return nil
+		}
+	}
+	return errSchedulerNotFound
+}
+
 func (o *scheduleOption) SetLabelProperty(typ, labelKey, labelValue string) {
 	cfg := o.loadLabelPropertyConfig().clone()
 	for _, l := range cfg[typ] {
@@ -253,6 +349,28 @@ func (o *scheduleOption) loadLabelPropertyConfig() LabelPropertyConfig {
 	return o.labelProperty.Load().(LabelPropertyConfig)
 }
 
+func (o *scheduleOption) SetMergeProtectionRule(name string, rule *MergeProtectionRule) {
+	cfg := o.loadMergeProtectionConfig().clone()
+	cfg[name] = rule
+	o.mergeProtection.Store(cfg)
+}
+
+func (o *scheduleOption) DeleteMergeProtectionRule(name string) {
+	cfg := o.loadMergeProtectionConfig().clone()
+	delete(cfg, name)
+	o.mergeProtection.Store(cfg)
+}
+
+func (o *scheduleOption) loadMergeProtectionConfig() MergeProtectionConfig {
+	return o.mergeProtection.Load().(MergeProtectionConfig)
+}
+
+// GetMergeProtectionInterval returns the interval in which the newly split
+// regions in the key range are protected from merging.
+func (o *scheduleOption) GetMergeProtectionInterval(startKey, endKey []byte) time.Duration {
+	return o.loadMergeProtectionConfig().getInterval(startKey, endKey)
+}
+
 func (o *scheduleOption) SetClusterVersion(v semver.Version) {
 	o.clusterVersion.Store(v)
 }
@@ -271,12 +389,13 @@ func (o *scheduleOption) persist(kv *core.KV) error {
 		namespaces[name] = *ns.load()
 	}
 	cfg := &Config{
-		Schedule:       *o.load(),
-		Replication:    *o.rep.load(),
-		Namespace:      namespaces,
-		LabelProperty:  o.loadLabelPropertyConfig(),
-		ClusterVersion: o.loadClusterVersion(),
-		PDServerCfg:    *o.loadPDServerConfig(),
+		Schedule:        *o.load(),
+		Replication:     *o.rep.load(),
+		Namespace:       namespaces,
+		LabelProperty:   o.loadLabelPropertyConfig(),
+		MergeProtection: o.loadMergeProtectionConfig(),
+		ClusterVersion:  o.loadClusterVersion(),
+		PDServerCfg:     *o.loadPDServerConfig(),
 	}
 	err := kv.SaveConfig(cfg)
 	return err
@@ -288,12 +407,13 @@ func (o *scheduleOption) reload(kv *core.KV) error {
 		namespaces[name] = *ns.load()
 	}
 	cfg := &Config{
-		Schedule:       *o.load().clone(),
-		Replication:    *o.rep.load(),
-		Namespace:      namespaces,
-		LabelProperty:  o.loadLabelPropertyConfig().clone(),
-		ClusterVersion: o.loadClusterVersion(),
-		PDServerCfg:    *o.loadPDServerConfig(),
+		Schedule:        *o.load().clone(),
+		Replication:     *o.rep.load(),
+		Namespace:       namespaces,
+		LabelProperty:   o.loadLabelPropertyConfig().clone(),
+		MergeProtection: o.loadMergeProtectionConfig().clone(),
+		ClusterVersion:  o.loadClusterVersion(),
+		PDServerCfg:     *o.loadPDServerConfig(),
 	}
 	isExist, err := kv.LoadConfig(cfg)
 	if err != nil {
@@ -308,7 +428,10 @@ func (o *scheduleOption) reload(kv *core.KV) error {
 			o.ns[name] = newNamespaceOption(&nsCfg)
 		}
 		o.labelProperty.Store(cfg.LabelProperty)
+		o.mergeProtection.Store(cfg.MergeProtection)
 		o.clusterVersion.Store(cfg.ClusterVersion)
+		// The config persisted by an older version has no sample rate.
+		adjustFloat64(&cfg.PDServerCfg.SlowLogSampleRate, defaultSlowLogSampleRate)
 		o.pdServerConfig.Store(&cfg.PDServerCfg)
 	}
 	return nil
@@ -320,6 +443,7 @@ func (o *scheduleOption) adjustScheduleCfg(persistentCfg *Config) {
 		for _, ps := range persistentCfg.Schedule.Schedulers {
 			if s.Type == ps.Type && reflect.DeepEqual(s.Args, ps.Args) {
 				scheduleCfg.Schedulers[i].Disable = ps.Disable
+				scheduleCfg.Schedulers[i].Config = ps.Config
 				break
 			}
 		}
@@ -395,6 +519,17 @@ func (r *Replication) GetLocationLabels() []string {
 	return r.load().LocationLabels
 }
 
+// GetIsolationLevel returns the minimum isolation level for each region
+func (r *Replication) GetIsolationLevel() string {
+	return r.load().IsolationLevel
+}
+
+// GetStrictlyMatchLabel returns whether the store labels must match the
+// location labels strictly.
+func (r *Replication) GetStrictlyMatchLabel() bool {
+	return r.load().StrictlyMatchLabel
+}
+
 // namespaceOption is a wrapper to access the configuration safely.
 type namespaceOption struct {
 	namespaceCfg atomic.Value
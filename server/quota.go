@@ -0,0 +1,199 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/namespace"
+	"golang.org/x/time/rate"
+)
+
+// quotaUsageRefreshInterval is the max age of the region usage of the
+// namespaces, counting the regions is expensive for large clusters.
+const quotaUsageRefreshInterval = 10 * time.Second
+
+// Names of the quotas in QuotaExceededErr.
+const (
+	quotaMaxRegionCount  = "max-region-count"
+	quotaMaxTotalSize    = "max-total-size"
+	quotaMaxOperatorRate = "max-operator-rate"
+)
+
+// NamespaceQuotaUsage shows the quota of a namespace, the usage and the
+// remaining quota.
+type NamespaceQuotaUsage struct {
+	Namespace   string         `json:"namespace"`
+	Quota       NamespaceQuota `json:"quota"`
+	RegionCount uint64         `json:"region_count"`
+	// TotalSize is the approximate size of the regions in MB.
+	TotalSize uint64 `json:"total_size"`
+	// RemainingRegionCount and RemainingTotalSize are absent if there is no
+	// limit.
+	RemainingRegionCount *uint64   `json:"remaining_region_count,omitempty"`
+	RemainingTotalSize   *uint64   `json:"remaining_total_size,omitempty"`
+	UpdateTime           time.Time `json:"update_time"`
+}
+
+type regionUsage struct {
+	regionCount uint64
+	totalSize   uint64
+}
+
+// quotaController enforces the namespace quotas on the operations which grow
+// the regions of a namespace, so one tenant can not trigger unbounded region
+// growth. The region usage is recounted periodically, and the approved splits
+// are added to it in the meantime.
+type quotaController struct {
+	sync.Mutex
+	cluster    *clusterInfo
+	classifier namespace.Classifier
+	usage      map[string]*regionUsage
+	updateTime time.Time
+	limiters   map[string]*rate.Limiter
+}
+
+func newQuotaController(cluster *clusterInfo, classifier namespace.Classifier) *quotaController {
+	return &quotaController{
+		cluster:    cluster,
+		classifier: classifier,
+		usage:      make(map[string]*regionUsage),
+		limiters:   make(map[string]*rate.Limiter),
+	}
+}
+
+// refreshLocked recounts the regions of the namespaces if the usage is stale.
+func (q *quotaController) refreshLocked() {
+	if time.Since(q.updateTime) < quotaUsageRefreshInterval {
+		return
+	}
+	usage := make(map[string]*regionUsage)
+	for _, region := range q.cluster.getRegions() {
+		ns := q.classifier.GetRegionNamespace(region)
+		u, ok := usage[ns]
+		if !ok {
+			u = &regionUsage{}
+			usage[ns] = u
+		}
+		u.regionCount++
+		u.totalSize += uint64(region.GetApproximateSize())
+	}
+	q.usage = usage
+	q.updateTime = time.Now()
+}
+
+func (q *quotaController) getUsageLocked(ns string) *regionUsage {
+	u, ok := q.usage[ns]
+	if !ok {
+		u = &regionUsage{}
+		q.usage[ns] = u
+	}
+	return u
+}
+
+// checkSplit checks whether the region can be split into count more regions
+// under the quota of its namespace. If reserve is true, the new regions are
+// added to the usage.
+func (q *quotaController) checkSplit(region *core.RegionInfo, count uint64, reserve bool) error {
+	ns := q.classifier.GetRegionNamespace(region)
+	quota := q.cluster.opt.GetNamespaceQuota(ns)
+	if quota.MaxRegionCount == 0 && quota.MaxTotalSize == 0 {
+		return nil
+	}
+
+	q.Lock()
+	defer q.Unlock()
+	q.refreshLocked()
+	u := q.getUsageLocked(ns)
+	if quota.MaxRegionCount > 0 && u.regionCount+count > quota.MaxRegionCount {
+		return quotaExceeded(ns, quotaMaxRegionCount, fmt.Sprintf("%d regions", quota.MaxRegionCount))
+	}
+	if quota.MaxTotalSize > 0 && u.totalSize >= quota.MaxTotalSize {
+		return quotaExceeded(ns, quotaMaxTotalSize, fmt.Sprintf("%d MB", quota.MaxTotalSize))
+	}
+	if reserve {
+		u.regionCount += count
+	}
+	return nil
+}
+
+// allowOperator checks whether a split or scatter operator can be created for
+// the region under the operator rate quota of its namespace.
+func (q *quotaController) allowOperator(region *core.RegionInfo) error {
+	ns := q.classifier.GetRegionNamespace(region)
+	limit := q.cluster.opt.GetNamespaceQuota(ns).MaxOperatorRate
+
+	q.Lock()
+	defer q.Unlock()
+	if limit <= 0 {
+		delete(q.limiters, ns)
+		return nil
+	}
+	l, ok := q.limiters[ns]
+	if !ok || l.Limit() != rate.Limit(limit) {
+		l = rate.NewLimiter(rate.Limit(limit), int(math.Ceil(limit)))
+		q.limiters[ns] = l
+	}
+	if !l.Allow() {
+		return quotaExceeded(ns, quotaMaxOperatorRate, fmt.Sprintf("%v operators per second", limit))
+	}
+	return nil
+}
+
+// getUsage returns the quota usage of the namespaces.
+func (q *quotaController) getUsage(names []string) []*NamespaceQuotaUsage {
+	q.Lock()
+	defer q.Unlock()
+	q.refreshLocked()
+
+	usages := make([]*NamespaceQuotaUsage, 0, len(names))
+	for _, ns := range names {
+		u := q.getUsageLocked(ns)
+		usage := &NamespaceQuotaUsage{
+			Namespace:   ns,
+			Quota:       q.cluster.opt.GetNamespaceQuota(ns),
+			RegionCount: u.regionCount,
+			TotalSize:   u.totalSize,
+			UpdateTime:  q.updateTime,
+		}
+		if usage.Quota.MaxRegionCount > 0 {
+			remaining := remainingQuota(usage.Quota.MaxRegionCount, u.regionCount)
+			usage.RemainingRegionCount = &remaining
+		}
+		if usage.Quota.MaxTotalSize > 0 {
+			remaining := remainingQuota(usage.Quota.MaxTotalSize, u.totalSize)
+			usage.RemainingTotalSize = &remaining
+		}
+		usages = append(usages, usage)
+	}
+	sort.Slice(usages, func(i, j int) bool { return usages[i].Namespace < usages[j].Namespace })
+	return usages
+}
+
+func remainingQuota(limit, used uint64) uint64 {
+	if used >= limit {
+		return 0
+	}
+	return limit - used
+}
+
+func quotaExceeded(ns, quota, limit string) error {
+	quotaExceededCounter.WithLabelValues(ns, quota).Inc()
+	return core.QuotaExceededErr{Namespace: ns, Quota: quota, Limit: limit}
+}
@@ -0,0 +1,97 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/namespace"
+)
+
+var _ = Suite(&testQuotaSuite{})
+
+type testQuotaSuite struct{}
+
+func (s *testQuotaSuite) TestRegionQuota(c *C) {
+	_, opt := newTestScheduleConfig()
+	tc := newTestClusterInfo(opt)
+	tc.addRegionStore(1, 0)
+	for id := uint64(1); id <= 3; id++ {
+		tc.addLeaderRegion(id, 1)
+	}
+	quotas := newQuotaController(tc.clusterInfo, namespace.DefaultClassifier)
+	region := tc.GetRegion(1)
+
+	// The splits are not counted without quota.
+	c.Assert(quotas.checkSplit(region, 100, true), IsNil)
+	usage := quotas.getUsage([]string{namespace.DefaultNamespace})[0]
+	c.Assert(usage.RegionCount, Equals, uint64(3))
+	c.Assert(usage.RemainingRegionCount, IsNil)
+
+	opt.ns[namespace.DefaultNamespace] = newNamespaceOption(&NamespaceConfig{
+		NamespaceQuota: NamespaceQuota{MaxRegionCount: 5, MaxTotalSize: 100},
+	})
+	quotas.updateTime = time.Time{}
+	c.Assert(quotas.checkSplit(region, 1, false), IsNil)
+	c.Assert(quotas.checkSplit(region, 2, true), IsNil)
+	err := quotas.checkSplit(region, 1, true)
+	c.Assert(err, FitsTypeOf, core.QuotaExceededErr{})
+	c.Assert(err.(core.QuotaExceededErr).Quota, Equals, quotaMaxRegionCount)
+	usage = quotas.getUsage([]string{namespace.DefaultNamespace})[0]
+	c.Assert(usage.RegionCount, Equals, uint64(5))
+	c.Assert(usage.TotalSize, Equals, uint64(30))
+	c.Assert(*usage.RemainingRegionCount, Equals, uint64(0))
+	c.Assert(*usage.RemainingTotalSize, Equals, uint64(70))
+
+	// The reserved splits are dropped when the regions are recounted.
+	quotas.updateTime = time.Time{}
+	c.Assert(quotas.checkSplit(region, 2, true), IsNil)
+
+	// The namespace reaches the size quota.
+	opt.ns[namespace.DefaultNamespace] = newNamespaceOption(&NamespaceConfig{
+		NamespaceQuota: NamespaceQuota{MaxTotalSize: 30},
+	})
+	err = quotas.checkSplit(region, 1, true)
+	c.Assert(err, FitsTypeOf, core.QuotaExceededErr{})
+	c.Assert(err.(core.QuotaExceededErr).Quota, Equals, quotaMaxTotalSize)
+}
+
+func (s *testQuotaSuite) TestOperatorRate(c *C) {
+	_, opt := newTestScheduleConfig()
+	tc := newTestClusterInfo(opt)
+	tc.addRegionStore(1, 0)
+	tc.addLeaderRegion(1, 1)
+	quotas := newQuotaController(tc.clusterInfo, namespace.DefaultClassifier)
+	region := tc.GetRegion(1)
+
+	for i := 0; i < 10; i++ {
+		c.Assert(quotas.allowOperator(region), IsNil)
+	}
+
+	opt.ns[namespace.DefaultNamespace] = newNamespaceOption(&NamespaceConfig{
+		NamespaceQuota: NamespaceQuota{MaxOperatorRate: 2},
+	})
+	c.Assert(quotas.allowOperator(region), IsNil)
+	c.Assert(quotas.allowOperator(region), IsNil)
+	err := quotas.allowOperator(region)
+	c.Assert(err, FitsTypeOf, core.QuotaExceededErr{})
+	c.Assert(err.(core.QuotaExceededErr).Quota, Equals, quotaMaxOperatorRate)
+
+	// The limiter is dropped with the quota.
+	opt.ns[namespace.DefaultNamespace] = newNamespaceOption(&NamespaceConfig{})
+	c.Assert(quotas.allowOperator(region), IsNil)
+	c.Assert(quotas.limiters, HasLen, 0)
+}
@@ -0,0 +1,369 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultConsistencyCheckRate is the regions checked per second when it
+	// is not specified.
+	defaultConsistencyCheckRate = 1000
+	// consistencyCheckBatch is the max regions loaded from the storage at a
+	// time.
+	consistencyCheckBatch = 256
+	// maxConsistencyReportRegions is the max region IDs kept for each kind of
+	// divergence.
+	maxConsistencyReportRegions = 100
+)
+
+// regionConsistencyJobType is the job type of region consistency check.
+const regionConsistencyJobType = "region-consistency-check"
+
+func init() {
+	registerJobRunner(regionConsistencyJobType, runRegionConsistencyJob)
+}
+
+// RegionConsistencyJob is an asynchronous job which compares the regions in
+// the cache with the regions persisted in the storage, and optionally repairs
+// the storage by the cache, which is updated by the heartbeats.
+type RegionConsistencyJob struct {
+	ID               uint64  `json:"id"`
+	Repair           bool    `json:"repair"`
+	RegionsPerSecond int     `json:"regions_per_second"`
+	Status           string  `json:"status"`
+	Progress         float64 `json:"progress"`
+	regionConsistencyState
+	Error      string    `json:"error,omitempty"`
+	CreateTime time.Time `json:"create_time"`
+	FinishTime time.Time `json:"finish_time,omitempty"`
+}
+
+// regionConsistencyArgs are the arguments of a region consistency check job.
+type regionConsistencyArgs struct {
+	Repair           bool `json:"repair"`
+	RegionsPerSecond int  `json:"regions_per_second"`
+}
+
+// regionConsistencyState is the progress of a region consistency check job.
+// The regions are checked in the order of IDs.
+type regionConsistencyState struct {
+	NextID  uint64 `json:"next_id"`
+	Checked int    `json:"checked"`
+	// OrphanRegions are persisted but not in the cache.
+	OrphanRegions int `json:"orphan_regions"`
+	// MissingRegions are in the cache but not persisted.
+	MissingRegions int `json:"missing_regions"`
+	// EpochMismatchRegions are persisted with an epoch different from the
+	// cache.
+	EpochMismatchRegions int `json:"epoch_mismatch_regions"`
+	Repaired             int `json:"repaired"`
+	// The IDs of the first regions of each kind.
+	OrphanRegionIDs        []uint64 `json:"orphan_region_ids,omitempty"`
+	MissingRegionIDs       []uint64 `json:"missing_region_ids,omitempty"`
+	EpochMismatchRegionIDs []uint64 `json:"epoch_mismatch_region_ids,omitempty"`
+}
+
+func newRegionConsistencyJob(job *Job) *RegionConsistencyJob {
+	var args regionConsistencyArgs
+	if err := json.Unmarshal(job.Args, &args); err != nil {
+		log.Errorf("[job %d] failed to decode the region consistency check args: %v", job.ID, err)
+	}
+	res := &RegionConsistencyJob{
+		ID:               job.ID,
+		Repair:           args.Repair,
+		RegionsPerSecond: args.RegionsPerSecond,
+		Status:           job.Status,
+		Progress:         job.Progress,
+		Error:            job.Error,
+		CreateTime:       job.CreateTime,
+		FinishTime:       job.FinishTime,
+	}
+	if len(job.State) > 0 {
+		if err := json.Unmarshal(job.State, &res.regionConsistencyState); err != nil {
+			log.Errorf("[job %d] failed to decode the region consistency check state: %v", job.ID, err)
+		}
+	}
+	return res
+}
+
+// CheckRegionConsistency starts a job to compare the regions in the cache with
+// the persisted regions. The divergent persisted regions are overwritten or
+// deleted if repair is true. The regions checked per second is set to the
+// default value if it is 0.
+func (h *Handler) CheckRegionConsistency(repair bool, regionsPerSecond int) (*RegionConsistencyJob, error) {
+	if _, err := h.getCoordinator(); err != nil {
+		return nil, err
+	}
+	if regionsPerSecond < 0 {
+		return nil, errors.Errorf("invalid regions per second %d", regionsPerSecond)
+	}
+	if regionsPerSecond == 0 {
+		regionsPerSecond = defaultConsistencyCheckRate
+	}
+	for _, job := range h.s.jobs.list(regionConsistencyJobType) {
+		if job.Status == JobRunning || job.Status == JobPaused {
+			return nil, errors.Wrapf(ErrInvalidJob, "region consistency check job %d is not finished", job.ID)
+		}
+	}
+	args := &regionConsistencyArgs{Repair: repair, RegionsPerSecond: regionsPerSecond}
+	job, err := h.s.jobs.create(regionConsistencyJobType, args, nil)
+	if err != nil {
+		return nil, err
+	}
+	return newRegionConsistencyJob(job), nil
+}
+
+// GetRegionConsistencyJob returns the region consistency check job with the ID.
+func (h *Handler) GetRegionConsistencyJob(id uint64) *RegionConsistencyJob {
+	job, err := h.s.jobs.get(id)
+	if err != nil || job.Type != regionConsistencyJobType {
+		return nil
+	}
+	return newRegionConsistencyJob(job)
+}
+
+// GetRegionConsistencyJobs returns all region consistency check jobs.
+func (h *Handler) GetRegionConsistencyJobs() []*RegionConsistencyJob {
+	jobs := h.s.jobs.list(regionConsistencyJobType)
+	res := make([]*RegionConsistencyJob, 0, len(jobs))
+	for _, job := range jobs {
+		res = append(res, newRegionConsistencyJob(job))
+	}
+	return res
+}
+
+// checkRegionConsistency starts a check without repairing if the last one is
+// created longer than the interval ago. It is called periodically by the
+// leader.
+func (c *RaftCluster) checkRegionConsistency() {
+	interval := c.cachedCluster.opt.GetRegionConsistencyCheckInterval()
+	if interval == 0 {
+		return
+	}
+	var last time.Time
+	for _, job := range c.s.jobs.list(regionConsistencyJobType) {
+		if job.Status == JobRunning || job.Status == JobPaused {
+			return
+		}
+		if job.CreateTime.After(last) {
+			last = job.CreateTime
+		}
+	}
+	if time.Since(last) < interval {
+		return
+	}
+	if _, err := c.s.GetHandler().CheckRegionConsistency(false, 0); err != nil {
+		log.Errorf("failed to start region consistency check: %v", err)
+	}
+}
+
+func runRegionConsistencyJob(jc *JobContext) error {
+	var args regionConsistencyArgs
+	if err := jc.Args(&args); err != nil {
+		return err
+	}
+	var state regionConsistencyState
+	if _, err := jc.State(&state); err != nil {
+		return err
+	}
+	h := jc.Handler()
+	c, err := h.getCoordinator()
+	if err != nil {
+		return err
+	}
+	kv := h.s.kv
+
+	// The IDs of the cached regions, which are compared with the persisted
+	// regions batch by batch.
+	regions := c.cluster.getRegions()
+	cachedIDs := make([]uint64, 0, len(regions))
+	for _, region := range regions {
+		cachedIDs = append(cachedIDs, region.GetID())
+	}
+	sort.Slice(cachedIDs, func(i, j int) bool { return cachedIDs[i] < cachedIDs[j] })
+
+	batch := consistencyCheckBatch
+	if args.RegionsPerSecond < batch {
+		batch = args.RegionsPerSecond
+	}
+	for {
+		persisted, err := kv.ScanRegions(state.NextID, batch)
+		if err != nil {
+			return err
+		}
+		// The persisted regions cover the IDs in [NextID, endID).
+		endID := uint64(0)
+		done := len(persisted) < batch
+		if !done {
+			endID = persisted[len(persisted)-1].GetId() + 1
+		}
+		checker := &regionConsistencyChecker{jc: jc, c: c, kv: kv, repair: args.Repair, state: &state}
+		if err = checker.check(persisted, idsInRange(cachedIDs, state.NextID, endID)); err != nil {
+			return err
+		}
+		state.Checked += len(persisted)
+		if done {
+			break
+		}
+		state.NextID = endID
+		progress := float64(state.Checked) * 100 / float64(len(cachedIDs)+1)
+		if progress > 99 {
+			progress = 99
+		}
+		if err = jc.Update(progress, state); err != nil {
+			return err
+		}
+		select {
+		case <-time.After(time.Duration(len(persisted)) * time.Second / time.Duration(args.RegionsPerSecond)):
+		case <-jc.Done():
+			return jc.Err()
+		}
+	}
+	if err = jc.Update(100, state); err != nil {
+		return err
+	}
+	regionInconsistencyGauge.WithLabelValues("orphan").Set(float64(state.OrphanRegions))
+	regionInconsistencyGauge.WithLabelValues("missing").Set(float64(state.MissingRegions))
+	regionInconsistencyGauge.WithLabelValues("epoch-mismatch").Set(float64(state.EpochMismatchRegions))
+	log.Infof("[job %d] %d regions are checked, %d orphan, %d missing, %d epoch mismatch, %d repaired",
+		jc.ID(), state.Checked, state.OrphanRegions, state.MissingRegions, state.EpochMismatchRegions, state.Repaired)
+	return nil
+}
+
+// idsInRange returns the sorted IDs in [start, end), end is unbounded if it is
+// 0.
+func idsInRange(ids []uint64, start, end uint64) []uint64 {
+	i := sort.Search(len(ids), func(i int) bool { return ids[i] >= start })
+	j := len(ids)
+	if end != 0 {
+		j = sort.Search(len(ids), func(i int) bool { return ids[i] >= end })
+	}
+	return ids[i:j]
+}
+
+// regionConsistencyChecker checks a batch of persisted regions.
+type regionConsistencyChecker struct {
+	jc     *JobContext
+	c      *coordinator
+	kv     *core.KV
+	repair bool
+	state  *regionConsistencyState
+}
+
+// check compares the persisted regions with the cached regions whose IDs are
+// in the same range. Both are sorted by ID.
+func (rc *regionConsistencyChecker) check(persisted []*metapb.Region, cachedIDs []uint64) error {
+	for _, region := range persisted {
+		for len(cachedIDs) > 0 && cachedIDs[0] < region.GetId() {
+			if err := rc.checkCached(cachedIDs[0]); err != nil {
+				return err
+			}
+			cachedIDs = cachedIDs[1:]
+		}
+		if len(cachedIDs) > 0 && cachedIDs[0] == region.GetId() {
+			cachedIDs = cachedIDs[1:]
+		}
+		if err := rc.checkPersisted(region); err != nil {
+			return err
+		}
+	}
+	for _, id := range cachedIDs {
+		if err := rc.checkCached(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkCached checks a cached region which is not persisted.
+func (rc *regionConsistencyChecker) checkCached(id uint64) error {
+	// The region may be merged after the IDs are collected.
+	cached := rc.c.cluster.GetRegion(id)
+	if cached == nil {
+		return nil
+	}
+	// It may be persisted after the batch is loaded.
+	if ok, err := rc.kv.LoadRegion(id, &metapb.Region{}); err != nil || ok {
+		return err
+	}
+	rc.state.MissingRegions++
+	rc.state.MissingRegionIDs = appendConsistencyReport(rc.state.MissingRegionIDs, id)
+	log.Warnf("[job %d] region %d is not persisted", rc.jc.ID(), id)
+	if rc.repair {
+		return rc.save(cached.GetMeta())
+	}
+	return nil
+}
+
+// checkPersisted checks a persisted region against the cache.
+func (rc *regionConsistencyChecker) checkPersisted(region *metapb.Region) error {
+	cached := rc.c.cluster.GetRegion(region.GetId())
+	if cached == nil {
+		rc.state.OrphanRegions++
+		rc.state.OrphanRegionIDs = appendConsistencyReport(rc.state.OrphanRegionIDs, region.GetId())
+		log.Warnf("[job %d] persisted region %d is not in the cache", rc.jc.ID(), region.GetId())
+		if rc.repair {
+			if err := rc.kv.DeleteRegion(region); err != nil {
+				return err
+			}
+			rc.state.Repaired++
+		}
+		return nil
+	}
+	if proto.Equal(region.GetRegionEpoch(), cached.GetRegionEpoch()) {
+		return nil
+	}
+	// The region may be saved by a heartbeat after the batch is loaded.
+	latest := &metapb.Region{}
+	if ok, err := rc.kv.LoadRegion(region.GetId(), latest); err != nil || !ok {
+		return err
+	}
+	cached = rc.c.cluster.GetRegion(region.GetId())
+	if cached == nil || proto.Equal(latest.GetRegionEpoch(), cached.GetRegionEpoch()) {
+		return nil
+	}
+	rc.state.EpochMismatchRegions++
+	rc.state.EpochMismatchRegionIDs = appendConsistencyReport(rc.state.EpochMismatchRegionIDs, region.GetId())
+	log.Warnf("[job %d] region %d is persisted with epoch %v, but it is %v in the cache",
+		rc.jc.ID(), region.GetId(), latest.GetRegionEpoch(), cached.GetRegionEpoch())
+	if rc.repair {
+		return rc.save(cached.GetMeta())
+	}
+	return nil
+}
+
+func (rc *regionConsistencyChecker) save(region *metapb.Region) error {
+	if err := rc.kv.SaveRegion(region); err != nil {
+		return err
+	}
+	rc.state.Repaired++
+	return nil
+}
+
+func appendConsistencyReport(ids []uint64, id uint64) []uint64 {
+	if len(ids) >= maxConsistencyReportRegions {
+		return ids
+	}
+	return append(ids, id)
+}
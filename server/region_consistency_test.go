@@ -0,0 +1,154 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/pkg/testutil"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pkg/errors"
+)
+
+var _ = Suite(&testRegionConsistencySuite{})
+
+type testRegionConsistencySuite struct {
+	baseCluster
+}
+
+func (s *testRegionConsistencySuite) waitJob(c *C, id uint64) *RegionConsistencyJob {
+	var job *RegionConsistencyJob
+	testutil.WaitUntil(c, func(c *C) bool {
+		job = s.svr.GetHandler().GetRegionConsistencyJob(id)
+		c.Assert(job, NotNil)
+		return job.Status == JobFinished
+	})
+	return job
+}
+
+func (s *testRegionConsistencySuite) TestCheckAndRepair(c *C) {
+	var cleanup func()
+	_, s.svr, cleanup, _ = NewTestServer()
+	defer cleanup()
+	mustWaitLeader(c, []*Server{s.svr})
+
+	req := s.newBootstrapRequest(c, s.svr.clusterID, "127.0.0.1:0")
+	_, err := s.svr.bootstrapCluster(req)
+	c.Assert(err, IsNil)
+	cluster := s.svr.GetRaftCluster()
+	kv := s.svr.kv
+	// The bootstrap region is replaced by the regions below in the cache.
+	c.Assert(kv.DeleteRegion(req.GetRegion()), IsNil)
+
+	regions := make([]*core.RegionInfo, 0, 5)
+	for i := uint64(0); i < 5; i++ {
+		id := 100 + i
+		meta := &metapb.Region{
+			Id:          id,
+			StartKey:    []byte{byte(i)},
+			EndKey:      []byte{byte(i + 1)},
+			Peers:       []*metapb.Peer{{Id: id + 100, StoreId: req.GetStore().GetId()}},
+			RegionEpoch: &metapb.RegionEpoch{ConfVer: 1, Version: 1},
+		}
+		region := core.NewRegionInfo(meta, meta.Peers[0])
+		c.Assert(cluster.cachedCluster.putRegion(region), IsNil)
+		regions = append(regions, region)
+	}
+	// Region 101 is not persisted, 102 is persisted with a stale epoch and 200
+	// is not in the cache.
+	c.Assert(kv.DeleteRegion(regions[1].GetMeta()), IsNil)
+	stale := proto.Clone(regions[2].GetMeta()).(*metapb.Region)
+	stale.RegionEpoch = &metapb.RegionEpoch{ConfVer: 1, Version: 0}
+	c.Assert(kv.SaveRegion(stale), IsNil)
+	c.Assert(kv.SaveRegion(&metapb.Region{Id: 200, RegionEpoch: &metapb.RegionEpoch{}}), IsNil)
+
+	h := s.svr.GetHandler()
+	_, err = h.CheckRegionConsistency(false, -1)
+	c.Assert(err, NotNil)
+	job, err := h.CheckRegionConsistency(false, 3)
+	c.Assert(err, IsNil)
+	c.Assert(job.Status, Equals, JobRunning)
+	// Only one check runs at a time.
+	_, err = h.CheckRegionConsistency(true, 0)
+	c.Assert(errors.Cause(err), Equals, ErrInvalidJob)
+	job = s.waitJob(c, job.ID)
+	c.Assert(job.Progress, Equals, float64(100))
+	c.Assert(job.Checked, Equals, 5)
+	c.Assert(job.OrphanRegionIDs, DeepEquals, []uint64{200})
+	c.Assert(job.MissingRegionIDs, DeepEquals, []uint64{101})
+	c.Assert(job.EpochMismatchRegionIDs, DeepEquals, []uint64{102})
+	c.Assert(job.Repaired, Equals, 0)
+
+	job, err = h.CheckRegionConsistency(true, 0)
+	c.Assert(err, IsNil)
+	c.Assert(job.RegionsPerSecond, Equals, defaultConsistencyCheckRate)
+	job = s.waitJob(c, job.ID)
+	c.Assert(job.OrphanRegions, Equals, 1)
+	c.Assert(job.MissingRegions, Equals, 1)
+	c.Assert(job.EpochMismatchRegions, Equals, 1)
+	c.Assert(job.Repaired, Equals, 3)
+	ok, err := kv.LoadRegion(200, &metapb.Region{})
+	c.Assert(err, IsNil)
+	c.Assert(ok, IsFalse)
+	persisted := &metapb.Region{}
+	ok, err = kv.LoadRegion(102, persisted)
+	c.Assert(err, IsNil)
+	c.Assert(ok, IsTrue)
+	c.Assert(persisted.GetRegionEpoch().GetVersion(), Equals, uint64(1))
+
+	// Nothing diverges after the repair.
+	job, err = h.CheckRegionConsistency(false, 0)
+	c.Assert(err, IsNil)
+	job = s.waitJob(c, job.ID)
+	c.Assert(job.Checked, Equals, 5)
+	c.Assert(job.OrphanRegions+job.MissingRegions+job.EpochMismatchRegions, Equals, 0)
+	c.Assert(h.GetRegionConsistencyJobs(), HasLen, 3)
+	c.Assert(h.GetRegionConsistencyJob(job.ID+100), IsNil)
+}
+
+func (s *testRegionConsistencySuite) TestPeriodicCheck(c *C) {
+	var cleanup func()
+	_, s.svr, cleanup, _ = NewTestServer()
+	defer cleanup()
+	mustWaitLeader(c, []*Server{s.svr})
+
+	req := s.newBootstrapRequest(c, s.svr.clusterID, "127.0.0.1:0")
+	_, err := s.svr.bootstrapCluster(req)
+	c.Assert(err, IsNil)
+	cluster := s.svr.GetRaftCluster()
+	h := s.svr.GetHandler()
+
+	// Disabled by default.
+	cluster.checkRegionConsistency()
+	c.Assert(h.GetRegionConsistencyJobs(), HasLen, 0)
+
+	cfg := s.svr.scheduleOpt.load().clone()
+	cfg.RegionConsistencyCheckInterval.Duration = time.Hour
+	s.svr.scheduleOpt.store(cfg)
+	cluster.checkRegionConsistency()
+	jobs := h.GetRegionConsistencyJobs()
+	c.Assert(jobs, HasLen, 1)
+	c.Assert(jobs[0].Repair, IsFalse)
+	job := s.waitJob(c, jobs[0].ID)
+	// The persisted bootstrap region is the same as the cached one.
+	c.Assert(job.Checked, Equals, 1)
+	c.Assert(job.OrphanRegions+job.MissingRegions+job.EpochMismatchRegions, Equals, 0)
+
+	// The next check starts after the interval.
+	cluster.checkRegionConsistency()
+	c.Assert(h.GetRegionConsistencyJobs(), HasLen, 1)
+}
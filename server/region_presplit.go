@@ -0,0 +1,325 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"time"
+
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/schedule"
+	"github.com/pingcap/pd/table"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultPreSplitRowsPerRegion is used to calculate the region count when
+	// it is not specified.
+	defaultPreSplitRowsPerRegion = 1 << 20
+	// maxPreSplitRegionCount limits the regions created by one pre-split job.
+	maxPreSplitRegionCount = 4096
+	preSplitScanLimit      = 1024
+	preSplitCheckInterval  = time.Second
+	preSplitTimeout        = 10 * time.Minute
+)
+
+// preSplitJobType is the job type of pre-split.
+const preSplitJobType = "pre-split"
+
+func init() {
+	registerJobRunner(preSplitJobType, runPreSplitJob)
+}
+
+// Pre-split job status.
+const (
+	PreSplitJobSplitting  = "splitting"
+	PreSplitJobScattering = "scattering"
+	PreSplitJobPaused     = "paused"
+	PreSplitJobFinished   = "finished"
+	PreSplitJobFailed     = "failed"
+	PreSplitJobCanceled   = "canceled"
+)
+
+// PreSplitJob is an asynchronous job which splits the key range of a prefix
+// into the expected number of regions and then scatters them.
+type PreSplitJob struct {
+	ID          uint64    `json:"id"`
+	StartKey    string    `json:"start_key"`
+	EndKey      string    `json:"end_key"`
+	TargetCount int       `json:"target_region_count"`
+	RegionCount int       `json:"region_count"`
+	Status      string    `json:"status"`
+	Error       string    `json:"error,omitempty"`
+	CreateTime  time.Time `json:"create_time"`
+	FinishTime  time.Time `json:"finish_time,omitempty"`
+}
+
+// preSplitArgs are the arguments of a pre-split job.
+type preSplitArgs struct {
+	// Prefix is hex encoded.
+	Prefix      string `json:"prefix"`
+	RowCount    int64  `json:"row_count"`
+	RegionCount int    `json:"region_count"`
+}
+
+// preSplitState is the progress of a pre-split job.
+type preSplitState struct {
+	StartKey    string `json:"start_key"`
+	EndKey      string `json:"end_key"`
+	TargetCount int    `json:"target_region_count"`
+	RegionCount int    `json:"region_count"`
+	Phase       string `json:"phase"`
+}
+
+func newPreSplitJob(job *Job) *PreSplitJob {
+	var state preSplitState
+	if err := json.Unmarshal(job.State, &state); err != nil {
+		log.Errorf("[job %d] failed to decode the pre-split state: %v", job.ID, err)
+	}
+	res := &PreSplitJob{
+		ID:          job.ID,
+		StartKey:    state.StartKey,
+		EndKey:      state.EndKey,
+		TargetCount: state.TargetCount,
+		RegionCount: state.RegionCount,
+		Status:      state.Phase,
+		Error:       job.Error,
+		CreateTime:  job.CreateTime,
+		FinishTime:  job.FinishTime,
+	}
+	switch job.Status {
+	case JobPaused:
+		res.Status = PreSplitJobPaused
+	case JobFinished:
+		res.Status = PreSplitJobFinished
+	case JobFailed:
+		res.Status = PreSplitJobFailed
+	case JobCanceled:
+		res.Status = PreSplitJobCanceled
+	}
+	return res
+}
+
+// PreSplitRegions starts a job to split the rows under the key prefix into
+// regionCount regions and scatter them. The rows are assumed to have int64
+// handles in [0, rowCount). If regionCount is 0, it is calculated by rowCount.
+// The regions are split at the computed split keys, so that the empty tables
+// are split at the table boundaries too. The leader stores get the keys by
+// splitpb.Split/GetSplitCommands.
+func (h *Handler) PreSplitRegions(prefix []byte, rowCount int64, regionCount int) (*PreSplitJob, error) {
+	if _, err := h.getCoordinator(); err != nil {
+		return nil, err
+	}
+	if rowCount <= 0 {
+		return nil, errors.Errorf("invalid row count %d", rowCount)
+	}
+	if regionCount <= 0 {
+		regionCount = int((rowCount + defaultPreSplitRowsPerRegion - 1) / defaultPreSplitRowsPerRegion)
+	}
+	if regionCount > maxPreSplitRegionCount {
+		return nil, errors.Errorf("region count %d exceeds the limit %d", regionCount, maxPreSplitRegionCount)
+	}
+
+	startKey, endKey := table.PrefixRange(prefix)
+	splitKeys := table.GenerateSplitKeys(prefix, rowCount, regionCount)
+	args := &preSplitArgs{
+		Prefix:      hex.EncodeToString(prefix),
+		RowCount:    rowCount,
+		RegionCount: regionCount,
+	}
+	state := &preSplitState{
+		StartKey:    string(core.HexRegionKey(startKey)),
+		EndKey:      string(core.HexRegionKey(endKey)),
+		TargetCount: len(splitKeys) + 1,
+		Phase:       PreSplitJobSplitting,
+	}
+	job, err := h.s.jobs.create(preSplitJobType, args, state)
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("[job %d] start to split [%s, %s) into %d regions", job.ID, state.StartKey, state.EndKey, state.TargetCount)
+	return newPreSplitJob(job), nil
+}
+
+// GetPreSplitJob returns the pre-split job with the ID.
+func (h *Handler) GetPreSplitJob(id uint64) *PreSplitJob {
+	job, err := h.s.jobs.get(id)
+	if err != nil || job.Type != preSplitJobType {
+		return nil
+	}
+	return newPreSplitJob(job)
+}
+
+// GetPreSplitJobs returns all pre-split jobs.
+func (h *Handler) GetPreSplitJobs() []*PreSplitJob {
+	jobs := h.s.jobs.list(preSplitJobType)
+	res := make([]*PreSplitJob, 0, len(jobs))
+	for _, job := range jobs {
+		res = append(res, newPreSplitJob(job))
+	}
+	return res
+}
+
+func runPreSplitJob(jc *JobContext) error {
+	var args preSplitArgs
+	if err := jc.Args(&args); err != nil {
+		return err
+	}
+	var state preSplitState
+	if _, err := jc.State(&state); err != nil {
+		return err
+	}
+	prefix, err := hex.DecodeString(args.Prefix)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	startKey, endKey := table.PrefixRange(prefix)
+	splitKeys := table.GenerateSplitKeys(prefix, args.RowCount, args.RegionCount)
+	h := jc.Handler()
+
+	ticker := time.NewTicker(preSplitCheckInterval)
+	defer ticker.Stop()
+	timeout := time.After(preSplitTimeout)
+
+	var regions []*core.RegionInfo
+	for state.Phase == PreSplitJobSplitting {
+		c, err := h.getCoordinator()
+		if err != nil {
+			return err
+		}
+		regions = h.scanRegionsInRange(c, startKey, endKey)
+		if len(regions) != state.RegionCount {
+			state.RegionCount = len(regions)
+			progress := math.Min(float64(state.RegionCount)/float64(state.TargetCount), 1) * 100
+			if err = jc.Update(progress, state); err != nil {
+				return err
+			}
+		}
+		if len(regions) >= state.TargetCount {
+			break
+		}
+
+		budget := state.TargetCount - len(regions)
+		for _, region := range regions {
+			if budget <= 0 {
+				break
+			}
+			keys := splitKeysInRegion(region, splitKeys)
+			if len(keys) == 0 || c.opController.GetOperator(region.GetID()) != nil {
+				continue
+			}
+			if len(keys) > budget {
+				keys = keys[:budget]
+			}
+			if err := h.addSplitKeysOperator(c, region, keys); err != nil {
+				log.Warnf("[job %d] failed to split region %d: %v", jc.ID(), region.GetID(), err)
+				continue
+			}
+			budget -= len(keys)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-timeout:
+			return errors.New("timeout")
+		case <-jc.Done():
+			return jc.Err()
+		}
+	}
+
+	state.Phase = PreSplitJobScattering
+	if err = jc.Update(100, state); err != nil {
+		return err
+	}
+	c, err := h.getCoordinator()
+	if err != nil {
+		return err
+	}
+	regions = h.scanRegionsInRange(c, startKey, endKey)
+	for _, region := range regions {
+		if err := h.AddScatterRegionOperator(region.GetID()); err != nil {
+			log.Warnf("[job %d] failed to scatter region %d: %v", jc.ID(), region.GetID(), err)
+		}
+	}
+	log.Infof("[job %d] pre-split finished with %d regions", jc.ID(), len(regions))
+	return nil
+}
+
+// scanRegionsInRange returns all regions overlapping with [startKey, endKey).
+func (h *Handler) scanRegionsInRange(c *coordinator, startKey, endKey []byte) []*core.RegionInfo {
+	var regions []*core.RegionInfo
+	key := startKey
+	// The first region may start before startKey.
+	if region := c.cluster.searchRegion(startKey); region != nil {
+		regions = append(regions, region)
+		key = region.GetEndKey()
+		if len(key) == 0 || (len(endKey) > 0 && bytes.Compare(key, endKey) >= 0) {
+			return regions
+		}
+	}
+	for {
+		scanned := c.cluster.ScanRegions(key, preSplitScanLimit)
+		for _, region := range scanned {
+			if len(endKey) > 0 && bytes.Compare(region.GetStartKey(), endKey) >= 0 {
+				return regions
+			}
+			regions = append(regions, region)
+			key = region.GetEndKey()
+			if len(key) == 0 {
+				return regions
+			}
+		}
+		if len(scanned) < preSplitScanLimit {
+			return regions
+		}
+	}
+}
+
+// splitKeysInRegion returns the split keys inside the region but not on its
+// start boundary.
+func splitKeysInRegion(region *core.RegionInfo, splitKeys []table.Key) [][]byte {
+	var keys [][]byte
+	for _, key := range splitKeys {
+		if bytes.Compare(key, region.GetStartKey()) > 0 &&
+			(len(region.GetEndKey()) == 0 || bytes.Compare(key, region.GetEndKey()) < 0) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// addSplitKeysOperator adds an operator to split the region at the keys.
+func (h *Handler) addSplitKeysOperator(c *coordinator, region *core.RegionInfo, keys [][]byte) error {
+	// The split is reserved in the quota when TiKV asks for the new IDs.
+	if err := c.quotas.checkSplit(region, uint64(len(keys)), false); err != nil {
+		return err
+	}
+	if err := c.quotas.allowOperator(region); err != nil {
+		return err
+	}
+	step := schedule.SplitRegion{
+		StartKey:  region.GetStartKey(),
+		EndKey:    region.GetEndKey(),
+		SplitKeys: keys,
+	}
+	op := schedule.NewOperator("pre-split-region", region.GetID(), region.GetRegionEpoch(), schedule.OpAdmin, step)
+	if ok := c.opController.AddOperator(op); !ok {
+		return errors.WithStack(errAddOperator)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	"github.com/pingcap/pd/pkg/regionpb"
+	"github.com/pingcap/pd/server/core"
+)
+
+// regionService implements gRPC RegionServer.
+type regionService struct {
+	s *Server
+}
+
+// GetRegion implements gRPC RegionServer.
+func (rs *regionService) GetRegion(ctx context.Context, request *regionpb.GetRegionRequest) (*regionpb.GetRegionResponse, error) {
+	if err := rs.s.validateRequest(request.GetHeader()); err != nil {
+		return nil, err
+	}
+	cluster := rs.s.GetRaftCluster()
+	if cluster == nil {
+		return &regionpb.GetRegionResponse{Header: rs.s.notBootstrappedHeader()}, nil
+	}
+	return rs.regionResponse(cluster.GetRegionInfoByKey(request.GetRegionKey())), nil
+}
+
+// GetPrevRegion implements gRPC RegionServer.
+func (rs *regionService) GetPrevRegion(ctx context.Context, request *regionpb.GetRegionRequest) (*regionpb.GetRegionResponse, error) {
+	if err := rs.s.validateRequest(request.GetHeader()); err != nil {
+		return nil, err
+	}
+	cluster := rs.s.GetRaftCluster()
+	if cluster == nil {
+		return &regionpb.GetRegionResponse{Header: rs.s.notBootstrappedHeader()}, nil
+	}
+	return rs.regionResponse(cluster.GetPrevRegionInfoByKey(request.GetRegionKey())), nil
+}
+
+// GetRegionByID implements gRPC RegionServer.
+func (rs *regionService) GetRegionByID(ctx context.Context, request *regionpb.GetRegionByIDRequest) (*regionpb.GetRegionResponse, error) {
+	if err := rs.s.validateRequest(request.GetHeader()); err != nil {
+		return nil, err
+	}
+	cluster := rs.s.GetRaftCluster()
+	if cluster == nil {
+		return &regionpb.GetRegionResponse{Header: rs.s.notBootstrappedHeader()}, nil
+	}
+	return rs.regionResponse(cluster.GetRegionInfoByID(request.GetRegionId())), nil
+}
+
+// regionResponse builds the response of the region, nil region means it is not
+// found.
+func (rs *regionService) regionResponse(region *core.RegionInfo) *regionpb.GetRegionResponse {
+	resp := &regionpb.GetRegionResponse{Header: rs.s.header()}
+	if region == nil {
+		return resp
+	}
+	resp.Region = region.GetMeta()
+	resp.Leader = region.GetLeader()
+	resp.DownPeers = region.GetDownPeers()
+	resp.PendingPeers = region.GetPendingPeers()
+	return resp
+}
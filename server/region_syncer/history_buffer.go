@@ -14,6 +14,7 @@
 package syncer
 
 import (
+	"context"
 	"strconv"
 
 	"github.com/pingcap/pd/server/core"
@@ -90,7 +91,7 @@ func (h *historyBuffer) get(index uint64) *core.RegionInfo {
 }
 
 func (h *historyBuffer) reload() {
-	v, err := h.kv.Load(historyKey)
+	v, err := h.kv.Load(context.Background(), historyKey)
 	if err != nil {
 		log.Warnf("load history index failed: %s", err)
 	}
@@ -103,7 +104,7 @@ func (h *historyBuffer) reload() {
 }
 
 func (h *historyBuffer) persist() {
-	err := h.kv.Save(historyKey, strconv.FormatUint(h.nextIndex(), 10))
+	err := h.kv.Save(context.Background(), historyKey, strconv.FormatUint(h.nextIndex(), 10))
 	if err != nil {
 		log.Warnf("persist history index (%d) failed: %v", h.nextIndex(), err)
 	}
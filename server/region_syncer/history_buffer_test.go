@@ -14,6 +14,7 @@
 package syncer
 
 import (
+	"context"
 	"testing"
 
 	. "github.com/pingcap/check"
@@ -78,7 +79,7 @@ func (t *testHistoryBuffer) TestBufferSize(c *C) {
 
 	c.Assert(h2.nextIndex(), Equals, uint64(107))
 	c.Assert(h2.get(h2.nextIndex()), IsNil)
-	s, err := h2.kv.Load(historyKey)
+	s, err := h2.kv.Load(context.Background(), historyKey)
 	c.Assert(err, IsNil)
 	// flush in index 106
 	c.Assert(s, Equals, "106")
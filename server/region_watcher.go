@@ -0,0 +1,175 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/pingcap/pd/pkg/watchpb"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// regionWatcherBufferSize is the count of changed regions can be buffered
+	// by a watcher. A watcher will be canceled if it is too slow to consume
+	// them, and the client should watch again.
+	regionWatcherBufferSize  = 1024
+	maxWatchRegionsBatchSize = 100
+)
+
+// regionWatcher receives the changed regions in a key range.
+type regionWatcher struct {
+	startKey, endKey []byte
+	ch               chan *core.RegionInfo
+}
+
+func (w *regionWatcher) overlaps(region *core.RegionInfo) bool {
+	if len(w.endKey) > 0 && bytes.Compare(region.GetStartKey(), w.endKey) >= 0 {
+		return false
+	}
+	return len(region.GetEndKey()) == 0 || bytes.Compare(region.GetEndKey(), w.startKey) > 0
+}
+
+// regionWatchers dispatches the changed regions to watchers.
+type regionWatchers struct {
+	sync.RWMutex
+	nextID   uint64
+	watchers map[uint64]*regionWatcher
+}
+
+func newRegionWatchers() *regionWatchers {
+	return &regionWatchers{
+		watchers: make(map[uint64]*regionWatcher),
+	}
+}
+
+// watch creates a watcher for [startKey, endKey). An empty endKey means no
+// upper bound.
+func (r *regionWatchers) watch(startKey, endKey []byte) (uint64, *regionWatcher) {
+	r.Lock()
+	defer r.Unlock()
+	r.nextID++
+	w := &regionWatcher{
+		startKey: startKey,
+		endKey:   endKey,
+		ch:       make(chan *core.RegionInfo, regionWatcherBufferSize),
+	}
+	r.watchers[r.nextID] = w
+	return r.nextID, w
+}
+
+// cancel removes the watcher and closes its channel.
+func (r *regionWatchers) cancel(id uint64) {
+	r.Lock()
+	defer r.Unlock()
+	r.cancelLocked(id)
+}
+
+func (r *regionWatchers) cancelLocked(id uint64) {
+	if w, ok := r.watchers[id]; ok {
+		close(w.ch)
+		delete(r.watchers, id)
+	}
+}
+
+// cancelAll cancels all watchers, it is called when the cluster is stopped.
+func (r *regionWatchers) cancelAll() {
+	r.Lock()
+	defer r.Unlock()
+	for id := range r.watchers {
+		r.cancelLocked(id)
+	}
+}
+
+// notify sends the changed region to the watchers whose key range overlaps
+// with it. It never blocks.
+func (r *regionWatchers) notify(region *core.RegionInfo) {
+	var slow []uint64
+	r.RLock()
+	for id, w := range r.watchers {
+		if !w.overlaps(region) {
+			continue
+		}
+		select {
+		case w.ch <- region:
+		default:
+			slow = append(slow, id)
+		}
+	}
+	r.RUnlock()
+
+	if len(slow) > 0 {
+		r.Lock()
+		for _, id := range slow {
+			log.Warnf("region watcher %d is too slow, cancel it", id)
+			r.cancelLocked(id)
+		}
+		r.Unlock()
+	}
+}
+
+// WatchRegions implements gRPC WatchServer.
+func (s *Server) WatchRegions(request *watchpb.WatchRegionsRequest, stream watchpb.Watch_WatchRegionsServer) error {
+	if err := s.validateRequest(request.GetHeader()); err != nil {
+		return err
+	}
+	cluster := s.GetRaftCluster()
+	if cluster == nil {
+		return status.Errorf(codes.Unavailable, "not bootstrapped")
+	}
+	startKey, endKey := request.GetStartKey(), request.GetEndKey()
+	if len(endKey) > 0 && bytes.Compare(startKey, endKey) >= 0 {
+		return status.Errorf(codes.InvalidArgument, "invalid key range [%q, %q)", startKey, endKey)
+	}
+
+	watchers := cluster.cachedCluster.watchers
+	id, w := watchers.watch(startKey, endKey)
+	defer watchers.cancel(id)
+	log.Infof("region watcher %d watches [%s, %s)", id, core.HexRegionKey(startKey), core.HexRegionKey(endKey))
+
+	for {
+		var region *core.RegionInfo
+		var ok bool
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case region, ok = <-w.ch:
+			if !ok {
+				return status.Errorf(codes.Aborted, "region watcher is canceled")
+			}
+		}
+
+		events := []*watchpb.RegionEvent{{Region: region.GetMeta(), Leader: region.GetLeader()}}
+		pending := len(w.ch)
+		for i := 0; i < pending && i < maxWatchRegionsBatchSize; i++ {
+			region, ok = <-w.ch
+			if !ok {
+				break
+			}
+			events = append(events, &watchpb.RegionEvent{Region: region.GetMeta(), Leader: region.GetLeader()})
+		}
+		resp := &watchpb.WatchRegionsResponse{
+			Header: s.header(),
+			Events: events,
+		}
+		if err := stream.Send(resp); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/pd/pkg/replicationpb"
+	"github.com/pingcap/pd/server/core"
+	log "github.com/sirupsen/logrus"
+)
+
+const replicationModeTickInterval = 10 * time.Second
+
+// States of the dr-auto-sync replication mode.
+const (
+	// drStateSync requires logs to be committed in both zones.
+	drStateSync = "sync"
+	// drStateAsync only requires logs to be committed in the primary zone,
+	// because the DR zone is unhealthy.
+	drStateAsync = "async"
+	// drStateSyncRecover is the state when the DR zone recovers, it switches
+	// to sync after the DR zone catches up.
+	drStateSyncRecover = "sync_recover"
+)
+
+var drStateToPB = map[string]replicationpb.DRAutoSyncState{
+	drStateSync:        replicationpb.DRAutoSyncState_SYNC,
+	drStateAsync:       replicationpb.DRAutoSyncState_ASYNC,
+	drStateSyncRecover: replicationpb.DRAutoSyncState_SYNC_RECOVER,
+}
+
+// ReplicationModeStatus is the replication mode status of the cluster.
+type ReplicationModeStatus struct {
+	Mode       string            `json:"mode"`
+	DRAutoSync *DRAutoSyncStatus `json:"dr-auto-sync,omitempty"`
+}
+
+// DRAutoSyncStatus is the status of the dr-auto-sync replication mode.
+type DRAutoSyncStatus struct {
+	State string `json:"state"`
+	// StateID is allocated every time the state is switched, so that TiKV
+	// can tell which state a region is replicated in.
+	StateID uint64 `json:"state-id"`
+}
+
+// replicationModeManager manages the replication mode of the cluster. In the
+// dr-auto-sync mode, it watches the stores in the DR zone and switches the
+// state between sync and async. The state is persisted and served to TiKV by
+// the Replication service.
+type replicationModeManager struct {
+	sync.RWMutex
+	cfg     ReplicationModeConfig
+	cluster *clusterInfo
+
+	drAutoSync DRAutoSyncStatus
+	// startTime is when the manager starts. Stores are not checked in the
+	// first WaitStoreTimeout, since they may not send heartbeats to the new
+	// leader yet.
+	startTime time.Time
+
+	// syncMu protects drStores and syncedRegions, which are updated by the
+	// region heartbeats in the sync_recover state.
+	syncMu sync.Mutex
+	// drStores are the stores in the DR zone.
+	drStores map[uint64]struct{}
+	// syncedRegions are the regions which have peers in the DR zone, and have
+	// reported that the peers in the DR zone are neither pending nor down
+	// since the state switched to sync_recover. It is nil in other states.
+	syncedRegions map[uint64]struct{}
+}
+
+func newReplicationModeManager(cfg ReplicationModeConfig, cluster *clusterInfo) (*replicationModeManager, error) {
+	m := &replicationModeManager{
+		cfg:       cfg,
+		cluster:   cluster,
+		startTime: time.Now(),
+	}
+	if cfg.ReplicationMode != replicationModeDRAutoSync {
+		return m, nil
+	}
+	ok, err := cluster.kv.LoadReplicationStatus(replicationModeDRAutoSync, &m.drAutoSync)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if err = m.drSwitchState(drStateSync); err != nil {
+			return nil, err
+		}
+	} else if m.drAutoSync.State == drStateSyncRecover {
+		// The regions need to report again to the new leader.
+		m.syncedRegions = make(map[uint64]struct{})
+	}
+	return m, nil
+}
+
+// getReplicationStatus returns the status to send to TiKV.
+func (m *replicationModeManager) getReplicationStatus() *replicationpb.ReplicationStatus {
+	m.RLock()
+	defer m.RUnlock()
+	if m.cfg.ReplicationMode != replicationModeDRAutoSync {
+		return &replicationpb.ReplicationStatus{Mode: replicationpb.ReplicationMode_MAJORITY}
+	}
+	return &replicationpb.ReplicationStatus{
+		Mode: replicationpb.ReplicationMode_DR_AUTO_SYNC,
+		DrAutoSync: &replicationpb.DRAutoSync{
+			LabelKey:            m.cfg.DRAutoSync.LabelKey,
+			State:               drStateToPB[m.drAutoSync.State],
+			StateId:             m.drAutoSync.StateID,
+			WaitSyncTimeoutHint: int32(m.cfg.DRAutoSync.WaitSyncTimeout.Seconds()),
+		},
+	}
+}
+
+// getStatus returns the status to show to users.
+func (m *replicationModeManager) getStatus() *ReplicationModeStatus {
+	m.RLock()
+	defer m.RUnlock()
+	status := &ReplicationModeStatus{Mode: m.cfg.ReplicationMode}
+	if m.cfg.ReplicationMode == replicationModeDRAutoSync {
+		drAutoSync := m.drAutoSync
+		status.DRAutoSync = &drAutoSync
+	}
+	return status
+}
+
+// drSwitchState persists the new state with a new state ID.
+func (m *replicationModeManager) drSwitchState(state string) error {
+	id, err := m.cluster.allocID()
+	if err != nil {
+		return err
+	}
+	status := DRAutoSyncStatus{State: state, StateID: id}
+	if err = m.cluster.kv.SaveReplicationStatus(replicationModeDRAutoSync, status); err != nil {
+		return err
+	}
+	log.Infof("dr-auto-sync replication switches from %q to %q, state id %d", m.drAutoSync.State, state, id)
+	m.drAutoSync = status
+	m.syncMu.Lock()
+	if state == drStateSyncRecover {
+		m.syncedRegions = make(map[uint64]struct{})
+	} else {
+		m.syncedRegions = nil
+	}
+	m.syncMu.Unlock()
+	return nil
+}
+
+// observeRegion records whether the peers of the region in the DR zone are in
+// sync, it is called for each region heartbeat.
+func (m *replicationModeManager) observeRegion(region *core.RegionInfo) {
+	m.syncMu.Lock()
+	defer m.syncMu.Unlock()
+	if m.syncedRegions == nil {
+		return
+	}
+	if m.regionInSyncLocked(region) {
+		m.syncedRegions[region.GetID()] = struct{}{}
+	} else {
+		delete(m.syncedRegions, region.GetID())
+	}
+}
+
+// regionInSyncLocked checks whether the region has peers in the DR zone, and
+// none of them is pending or down.
+func (m *replicationModeManager) regionInSyncLocked(region *core.RegionInfo) bool {
+	var inDR bool
+	for _, p := range region.GetPeers() {
+		if _, ok := m.drStores[p.GetStoreId()]; !ok {
+			continue
+		}
+		inDR = true
+		if region.GetPendingPeer(p.GetId()) != nil || region.GetDownPeer(p.GetId()) != nil {
+			return false
+		}
+	}
+	return inDR
+}
+
+// drIsSynced checks whether all regions with peers in the DR zone have
+// reported in sync.
+func (m *replicationModeManager) drIsSynced() bool {
+	m.syncMu.Lock()
+	defer m.syncMu.Unlock()
+	if m.syncedRegions == nil {
+		return false
+	}
+	for storeID := range m.drStores {
+		for _, region := range m.cluster.getStoreRegions(storeID) {
+			if _, ok := m.syncedRegions[region.GetID()]; !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// updateDRStores refreshes the stores in the DR zone.
+func (m *replicationModeManager) updateDRStores() {
+	stores := make(map[uint64]struct{})
+	for _, s := range m.cluster.GetStores() {
+		if !s.IsTombstone() && s.GetLabelValue(m.cfg.DRAutoSync.LabelKey) == m.cfg.DRAutoSync.DR {
+			stores[s.GetId()] = struct{}{}
+		}
+	}
+	m.syncMu.Lock()
+	m.drStores = stores
+	m.syncMu.Unlock()
+}
+
+// drIsHealthy checks whether the DR zone has enough stores which send
+// heartbeats in time.
+func (m *replicationModeManager) drIsHealthy() bool {
+	cfg := m.cfg.DRAutoSync
+	var up int
+	for _, s := range m.cluster.GetStores() {
+		if s.IsTombstone() || s.GetLabelValue(cfg.LabelKey) != cfg.DR {
+			continue
+		}
+		if s.DownTime() >= cfg.WaitStoreTimeout.Duration {
+			return false
+		}
+		up++
+	}
+	return up > 0 && up >= cfg.DRReplicas
+}
+
+// tick checks the DR zone and switches the state if needed.
+func (m *replicationModeManager) tick() {
+	m.Lock()
+	defer m.Unlock()
+	if m.cfg.ReplicationMode != replicationModeDRAutoSync {
+		return
+	}
+	m.updateDRStores()
+	if time.Since(m.startTime) < m.cfg.DRAutoSync.WaitStoreTimeout.Duration {
+		return
+	}
+
+	healthy := m.drIsHealthy()
+	var state string
+	switch m.drAutoSync.State {
+	case drStateSync:
+		if !healthy {
+			state = drStateAsync
+		}
+	case drStateAsync:
+		if healthy {
+			state = drStateSyncRecover
+		}
+	case drStateSyncRecover:
+		if !healthy {
+			state = drStateAsync
+		} else if m.drIsSynced() {
+			state = drStateSync
+		}
+	}
+	if state == "" {
+		return
+	}
+	if err := m.drSwitchState(state); err != nil {
+		log.Errorf("failed to switch dr-auto-sync state to %q: %v", state, err)
+	}
+}
+
+// replicationService implements gRPC ReplicationServer.
+type replicationService struct {
+	s *Server
+}
+
+// GetReplicationStatus implements gRPC ReplicationServer.
+func (r *replicationService) GetReplicationStatus(ctx context.Context, request *replicationpb.GetReplicationStatusRequest) (*replicationpb.GetReplicationStatusResponse, error) {
+	if err := r.s.validateRequest(request.GetHeader()); err != nil {
+		return nil, err
+	}
+	cluster := r.s.GetRaftCluster()
+	if cluster == nil {
+		return &replicationpb.GetReplicationStatusResponse{Header: r.s.notBootstrappedHeader()}, nil
+	}
+	return &replicationpb.GetReplicationStatusResponse{
+		Header: r.s.header(),
+		Status: cluster.replicationMode.getReplicationStatus(),
+	}, nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/pkg/replicationpb"
+	"github.com/pingcap/pd/pkg/typeutil"
+	"github.com/pingcap/pd/server/core"
+)
+
+var _ = Suite(&testReplicationModeSuite{})
+
+type testReplicationModeSuite struct{}
+
+func (s *testReplicationModeSuite) TestMajority(c *C) {
+	_, opt := newTestScheduleConfig()
+	cluster := newClusterInfo(core.NewMockIDAllocator(), opt, core.NewKV(core.NewMemoryKV()))
+	cfg := ReplicationModeConfig{}
+	c.Assert(cfg.adjust(), IsNil)
+	m, err := newReplicationModeManager(cfg, cluster)
+	c.Assert(err, IsNil)
+	c.Assert(m.getStatus(), DeepEquals, &ReplicationModeStatus{Mode: replicationModeMajority})
+	c.Assert(m.getReplicationStatus().GetMode(), Equals, replicationpb.ReplicationMode_MAJORITY)
+}
+
+func (s *testReplicationModeSuite) TestDRAutoSync(c *C) {
+	_, opt := newTestScheduleConfig()
+	kv := core.NewKV(core.NewMemoryKV())
+	cluster := newClusterInfo(core.NewMockIDAllocator(), opt, kv)
+	cfg := ReplicationModeConfig{
+		ReplicationMode: replicationModeDRAutoSync,
+		DRAutoSync: DRAutoSyncReplicationConfig{
+			LabelKey:         "zone",
+			Primary:          "east",
+			DR:               "west",
+			DRReplicas:       2,
+			WaitStoreTimeout: typeutil.NewDuration(time.Minute),
+			WaitSyncTimeout:  typeutil.NewDuration(time.Minute),
+		},
+	}
+	c.Assert(cfg.adjust(), IsNil)
+	for i, zone := range []string{"east", "east", "west", "west"} {
+		store := core.NewStoreInfo(&metapb.Store{
+			Id:     uint64(i + 1),
+			Labels: []*metapb.StoreLabel{{Key: "zone", Value: zone}},
+		})
+		store.LastHeartbeatTS = time.Now()
+		c.Assert(cluster.putStore(store), IsNil)
+	}
+
+	m, err := newReplicationModeManager(cfg, cluster)
+	c.Assert(err, IsNil)
+	c.Assert(m.drAutoSync.State, Equals, drStateSync)
+	stateID := m.drAutoSync.StateID
+	c.Assert(stateID, Not(Equals), uint64(0))
+
+	// Stores are not checked until they have a chance to send heartbeats.
+	s.setHeartbeat(cluster, 4, time.Now().Add(-2*time.Minute))
+	m.tick()
+	c.Assert(m.drAutoSync.State, Equals, drStateSync)
+
+	m.startTime = time.Now().Add(-time.Hour)
+	m.tick()
+	c.Assert(m.drAutoSync.State, Equals, drStateAsync)
+	c.Assert(m.drAutoSync.StateID, Greater, stateID)
+
+	// The state is persisted.
+	m2, err := newReplicationModeManager(cfg, cluster)
+	c.Assert(err, IsNil)
+	c.Assert(m2.getStatus(), DeepEquals, m.getStatus())
+
+	s.setHeartbeat(cluster, 4, time.Now())
+	m.tick()
+	c.Assert(m.drAutoSync.State, Equals, drStateSyncRecover)
+	// It switches back to sync only after all regions in the DR zone report
+	// in sync.
+	region1 := s.newRegion(1, 1, 3)
+	region2 := s.newRegion(2, 2, 4)
+	c.Assert(cluster.putRegion(region1), IsNil)
+	c.Assert(cluster.putRegion(region2), IsNil)
+	m.observeRegion(region1)
+	m.observeRegion(region2.Clone(core.WithPendingPeers(region2.GetPeers()[1:])))
+	m.tick()
+	c.Assert(m.drAutoSync.State, Equals, drStateSyncRecover)
+	m.observeRegion(region2)
+	m.tick()
+	c.Assert(m.drAutoSync.State, Equals, drStateSync)
+
+	status := m.getReplicationStatus()
+	c.Assert(status.GetMode(), Equals, replicationpb.ReplicationMode_DR_AUTO_SYNC)
+	c.Assert(status.GetDrAutoSync().GetLabelKey(), Equals, "zone")
+	c.Assert(status.GetDrAutoSync().GetState(), Equals, replicationpb.DRAutoSyncState_SYNC)
+	c.Assert(status.GetDrAutoSync().GetStateId(), Equals, m.drAutoSync.StateID)
+	c.Assert(status.GetDrAutoSync().GetWaitSyncTimeoutHint(), Equals, int32(60))
+}
+
+func (s *testReplicationModeSuite) newRegion(regionID uint64, storeIDs ...uint64) *core.RegionInfo {
+	region := &metapb.Region{Id: regionID, StartKey: []byte{byte(regionID)}, EndKey: []byte{byte(regionID + 1)}}
+	for _, storeID := range storeIDs {
+		region.Peers = append(region.Peers, &metapb.Peer{Id: regionID*10 + storeID, StoreId: storeID})
+	}
+	return core.NewRegionInfo(region, region.Peers[0])
+}
+
+func (s *testReplicationModeSuite) setHeartbeat(cluster *clusterInfo, storeID uint64, ts time.Time) {
+	store := cluster.GetStore(storeID)
+	store.LastHeartbeatTS = ts
+	cluster.putStore(store)
+}
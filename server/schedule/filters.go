@@ -59,6 +59,32 @@ func FilterTarget(opt Options, store *core.StoreInfo, filters []Filter) bool {
 	return false
 }
 
+// SourceRejectedBy returns the types of the Filters which reject the store as
+// source store. It is used to explain the scheduling, so no metrics are
+// recorded.
+func SourceRejectedBy(opt Options, store *core.StoreInfo, filters []Filter) []string {
+	var types []string
+	for _, filter := range filters {
+		if filter.FilterSource(opt, store) {
+			types = append(types, filter.Type())
+		}
+	}
+	return types
+}
+
+// TargetRejectedBy returns the types of the Filters which reject the store as
+// target store. It is used to explain the scheduling, so no metrics are
+// recorded.
+func TargetRejectedBy(opt Options, store *core.StoreInfo, filters []Filter) []string {
+	var types []string
+	for _, filter := range filters {
+		if filter.FilterTarget(opt, store) {
+			types = append(types, filter.Type())
+		}
+	}
+	return types
+}
+
 type excludedFilter struct {
 	sources map[uint64]struct{}
 	targets map[uint64]struct{}
@@ -121,7 +147,7 @@ func (f *stateFilter) FilterSource(opt Options, store *core.StoreInfo) bool {
 }
 
 func (f *stateFilter) FilterTarget(opt Options, store *core.StoreInfo) bool {
-	return !store.IsUp()
+	return !store.IsUp() || store.IsCordoned()
 }
 
 type healthFilter struct{}
@@ -263,6 +289,28 @@ func (f *storageThresholdFilter) FilterTarget(opt Options, store *core.StoreInfo
 	return store.IsLowSpace(opt.GetLowSpaceRatio())
 }
 
+type pendingCompactionFilter struct{}
+
+// NewPendingCompactionFilter creates a Filter that filters all stores that have
+// too many pending compaction bytes, since applying snapshots makes the
+// compaction worse.
+func NewPendingCompactionFilter() Filter {
+	return &pendingCompactionFilter{}
+}
+
+func (f *pendingCompactionFilter) Type() string {
+	return "pending-compaction-filter"
+}
+
+func (f *pendingCompactionFilter) FilterSource(opt Options, store *core.StoreInfo) bool {
+	return false
+}
+
+func (f *pendingCompactionFilter) FilterTarget(opt Options, store *core.StoreInfo) bool {
+	return opt.GetMaxPendingCompactionBytes() > 0 &&
+		store.DiskStats.PendingCompactionBytes > opt.GetMaxPendingCompactionBytes()
+}
+
 // distinctScoreFilter ensures that distinct score will not decrease.
 type distinctScoreFilter struct {
 	labels    []string
@@ -300,6 +348,71 @@ func (f *distinctScoreFilter) FilterTarget(opt Options, store *core.StoreInfo) b
 	return DistinctScore(f.labels, f.stores, store) < f.safeScore
 }
 
+// isolationFilter ensures that the isolation level of a region will not be
+// reduced below the required level.
+type isolationFilter struct {
+	labels     []string
+	stores     []*core.StoreInfo
+	levelIndex int
+	isolated   bool
+}
+
+// NewIsolationFilter creates a filter that filters all stores that would make
+// the region not isolated at the specified level any more. It does nothing if
+// the source replica is not isolated at the level already.
+func NewIsolationFilter(labels []string, isolationLevel string, stores []*core.StoreInfo, source *core.StoreInfo) Filter {
+	levelIndex := -1
+	for i, label := range labels {
+		if label == isolationLevel {
+			levelIndex = i
+			break
+		}
+	}
+	newStores := make([]*core.StoreInfo, 0, len(stores))
+	for _, s := range stores {
+		if s.GetId() == source.GetId() {
+			continue
+		}
+		newStores = append(newStores, s)
+	}
+	f := &isolationFilter{
+		labels:     labels,
+		stores:     newStores,
+		levelIndex: levelIndex,
+	}
+	f.isolated = f.isIsolated(source)
+	return f
+}
+
+func (f *isolationFilter) Type() string {
+	return "isolation-filter"
+}
+
+func (f *isolationFilter) FilterSource(opt Options, store *core.StoreInfo) bool {
+	return false
+}
+
+func (f *isolationFilter) FilterTarget(opt Options, store *core.StoreInfo) bool {
+	if f.levelIndex == -1 {
+		return false
+	}
+	return f.isolated && !f.isIsolated(store)
+}
+
+// isIsolated checks if the store is different from all other stores at or
+// above the isolation level.
+func (f *isolationFilter) isIsolated(store *core.StoreInfo) bool {
+	for _, s := range f.stores {
+		if s.GetId() == store.GetId() {
+			continue
+		}
+		if index := s.CompareLocation(store, f.labels); index == -1 || index > f.levelIndex {
+			return false
+		}
+	}
+	return true
+}
+
 type namespaceFilter struct {
 	classifier namespace.Classifier
 	namespace  string
@@ -333,7 +446,9 @@ func (f *namespaceFilter) FilterTarget(opt Options, store *core.StoreInfo) bool
 type rejectLeaderFilter struct{}
 
 // NewRejectLeaderFilter creates a Filter that filters stores that marked as
-// rejectLeader from being the target of leader transfer.
+// rejectLeader or witness from being the target of leader transfer. All the schedules
+// transferring leaders should apply it, StoreStateFilter with TransferLeader
+// applies it as well.
 func NewRejectLeaderFilter() Filter {
 	return rejectLeaderFilter{}
 }
@@ -347,7 +462,8 @@ func (f rejectLeaderFilter) FilterSource(opt Options, store *core.StoreInfo) boo
 }
 
 func (f rejectLeaderFilter) FilterTarget(opt Options, store *core.StoreInfo) bool {
-	return opt.CheckLabelProperty(RejectLeader, store.Labels)
+	return opt.CheckLabelProperty(RejectLeader, store.Labels) ||
+		opt.CheckLabelProperty(WitnessReplica, store.Labels)
 }
 
 // StoreStateFilter is used to determine whether a store can be selected as the
@@ -386,6 +502,7 @@ func (f StoreStateFilter) FilterSource(opt Options, store *core.StoreInfo) bool
 func (f StoreStateFilter) FilterTarget(opt Options, store *core.StoreInfo) bool {
 	if store.IsTombstone() ||
 		store.IsOffline() ||
+		store.IsCordoned() ||
 		store.DownTime() > opt.GetMaxStoreDownTime() {
 		return true
 	}
@@ -393,7 +510,7 @@ func (f StoreStateFilter) FilterTarget(opt Options, store *core.StoreInfo) bool
 		(store.IsDisconnected() ||
 			store.IsBlocked() ||
 			store.Stats.GetIsBusy() ||
-			opt.CheckLabelProperty(RejectLeader, store.Labels)) {
+			rejectLeaderFilter{}.FilterTarget(opt, store)) {
 		return true
 	}
 
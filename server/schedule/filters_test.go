@@ -13,6 +13,8 @@
 package schedule
 
 import (
+	"time"
+
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/pd/server/core"
@@ -36,3 +38,76 @@ func (s *testReplicationSuite) TestPendingPeerFilter(c *C) {
 	c.Assert(filter.FilterSource(tc, store), IsFalse)
 	c.Assert(filter.FilterTarget(tc, store), IsFalse)
 }
+
+func (s *testFiltersSuite) TestPendingCompactionFilter(c *C) {
+	filter := NewPendingCompactionFilter()
+	opt := NewMockSchedulerOptions()
+	tc := NewMockCluster(opt)
+	tc.AddRegionStore(1, 10)
+	c.Assert(filter.FilterTarget(tc, tc.GetStore(1)), IsFalse)
+	tc.UpdatePendingCompactionBytes(1, opt.MaxPendingCompactionBytes+1)
+	c.Assert(filter.FilterSource(tc, tc.GetStore(1)), IsFalse)
+	c.Assert(filter.FilterTarget(tc, tc.GetStore(1)), IsTrue)
+	// set to 0 means no limit
+	opt.MaxPendingCompactionBytes = 0
+	c.Assert(filter.FilterTarget(tc, tc.GetStore(1)), IsFalse)
+}
+
+func (s *testFiltersSuite) TestCordonedStore(c *C) {
+	opt := NewMockSchedulerOptions()
+	tc := NewMockCluster(opt)
+	tc.AddRegionStore(1, 10)
+	store := tc.GetStore(1)
+	filters := []Filter{
+		NewStateFilter(),
+		StoreStateFilter{TransferLeader: true},
+		StoreStateFilter{MoveRegion: true},
+	}
+
+	store.Cordon(time.Time{})
+	for _, filter := range filters {
+		c.Assert(filter.FilterSource(tc, store), IsFalse)
+		c.Assert(filter.FilterTarget(tc, store), IsTrue)
+	}
+	// The cordon is expired.
+	store.Cordon(time.Now().Add(-time.Second))
+	c.Assert(store.IsCordonExpired(), IsTrue)
+	for _, filter := range filters {
+		c.Assert(filter.FilterTarget(tc, store), IsFalse)
+	}
+	store.Uncordon()
+	for _, filter := range filters {
+		c.Assert(filter.FilterTarget(tc, store), IsFalse)
+	}
+}
+
+func (s *testFiltersSuite) TestIsolationFilter(c *C) {
+	opt := NewMockSchedulerOptions()
+	tc := NewMockCluster(opt)
+	labels := []string{"zone", "rack", "host"}
+	tc.AddLabelsStore(1, 1, map[string]string{"zone": "z1", "rack": "r1", "host": "h1"})
+	tc.AddLabelsStore(2, 1, map[string]string{"zone": "z2", "rack": "r1", "host": "h1"})
+	tc.AddLabelsStore(3, 1, map[string]string{"zone": "z3", "rack": "r1", "host": "h1"})
+	tc.AddLabelsStore(4, 1, map[string]string{"zone": "z1", "rack": "r2", "host": "h1"})
+	tc.AddLabelsStore(5, 1, map[string]string{"zone": "z2", "rack": "r2", "host": "h2"})
+	tc.AddLabelsStore(6, 1, map[string]string{"zone": "z2", "rack": "r1", "host": "h2"})
+
+	stores := []*core.StoreInfo{tc.GetStore(1), tc.GetStore(2), tc.GetStore(3)}
+	filter := NewIsolationFilter(labels, "zone", stores, tc.GetStore(1))
+	c.Assert(filter.FilterTarget(tc, tc.GetStore(4)), IsFalse)
+	c.Assert(filter.FilterTarget(tc, tc.GetStore(5)), IsTrue)
+	c.Assert(filter.FilterTarget(tc, tc.GetStore(6)), IsTrue)
+
+	filter = NewIsolationFilter(labels, "rack", stores, tc.GetStore(1))
+	c.Assert(filter.FilterTarget(tc, tc.GetStore(5)), IsFalse)
+	c.Assert(filter.FilterTarget(tc, tc.GetStore(6)), IsTrue)
+
+	// No isolation level is required.
+	filter = NewIsolationFilter(labels, "", stores, tc.GetStore(1))
+	c.Assert(filter.FilterTarget(tc, tc.GetStore(6)), IsFalse)
+
+	// The source replica is not isolated at zone level already.
+	stores = []*core.StoreInfo{tc.GetStore(1), tc.GetStore(2), tc.GetStore(4)}
+	filter = NewIsolationFilter(labels, "zone", stores, tc.GetStore(1))
+	c.Assert(filter.FilterTarget(tc, tc.GetStore(6)), IsFalse)
+}
@@ -62,6 +62,11 @@ func (m *MergeChecker) Check(region *core.RegionInfo) []*Operator {
 		return nil
 	}
 
+	if m.isMergeProtected(region) {
+		checkerCounter.WithLabelValues("merge_checker", "protected").Inc()
+		return nil
+	}
+
 	checkerCounter.WithLabelValues("merge_checker", "check").Inc()
 
 	// when pd just started, it will load region meta from etcd
@@ -120,7 +125,7 @@ func (m *MergeChecker) Check(region *core.RegionInfo) []*Operator {
 func (m *MergeChecker) checkTarget(region, adjacent, target *core.RegionInfo) *core.RegionInfo {
 	// if is not hot region and under same namesapce
 	if adjacent != nil && !m.cluster.IsRegionHot(adjacent.GetID()) &&
-		m.classifier.AllowMerge(region, adjacent) &&
+		m.classifier.AllowMerge(region, adjacent) && !m.isMergeProtected(adjacent) &&
 		len(adjacent.GetDownPeers()) == 0 && len(adjacent.GetPendingPeers()) == 0 && len(adjacent.GetLearners()) == 0 {
 		// if both region is not hot, prefer the one with smaller size
 		if target == nil || target.GetApproximateSize() > adjacent.GetApproximateSize() {
@@ -132,3 +137,10 @@ func (m *MergeChecker) checkTarget(region, adjacent, target *core.RegionInfo) *c
 	}
 	return target
 }
+
+// isMergeProtected returns true if the region is split recently in a key range
+// protected from merging.
+func (m *MergeChecker) isMergeProtected(region *core.RegionInfo) bool {
+	interval := m.cluster.GetMergeProtectionInterval(region.GetStartKey(), region.GetEndKey())
+	return interval > 0 && time.Since(region.GetCreateTime()) < interval
+}
@@ -65,6 +65,22 @@ var (
 			Help:      "Bucketed histogram of processing time (s) of finished operator.",
 			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 16),
 		}, []string{"type"})
+
+	namespaceOperatorGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "schedule",
+			Name:      "namespace_operators",
+			Help:      "Number of in-flight operators of each namespace.",
+		}, []string{"namespace", "type"})
+
+	namespaceOperatorCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "schedule",
+			Name:      "namespace_operators_count",
+			Help:      "Counter of schedule operators of each namespace.",
+		}, []string{"namespace", "type", "event"})
 )
 
 func init() {
@@ -74,4 +90,6 @@ func init() {
 	prometheus.MustRegister(filterCounter)
 	prometheus.MustRegister(operatorCounter)
 	prometheus.MustRegister(operatorDuration)
+	prometheus.MustRegister(namespaceOperatorGauge)
+	prometheus.MustRegister(namespaceOperatorCounter)
 }
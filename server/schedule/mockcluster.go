@@ -244,6 +244,13 @@ func (mc *MockCluster) UpdateRegionCount(storeID uint64, regionCount int) {
 	mc.PutStore(store)
 }
 
+// UpdatePendingCompactionBytes updates store pending compaction bytes.
+func (mc *MockCluster) UpdatePendingCompactionBytes(storeID uint64, bytes uint64) {
+	store := mc.GetStore(storeID)
+	store.DiskStats.PendingCompactionBytes = bytes
+	mc.PutStore(store)
+}
+
 // UpdateSnapshotCount updates store snapshot count.
 func (mc *MockCluster) UpdateSnapshotCount(storeID uint64, snapshotCount int) {
 	store := mc.GetStore(storeID)
@@ -360,6 +367,21 @@ func (mc *MockCluster) ApplyOperator(op *Operator) {
 					StoreId: s.ToStore,
 				}
 				region = region.Clone(core.WithRemoveStorePeer(s.ToStore), core.WithAddPeer(peer))
+			case DemotePromote:
+				if region.GetStoreVoter(s.FromStore) == nil || region.GetStoreLearner(s.ToStore) == nil {
+					panic("demote or promote peer that doesn't exist")
+				}
+				demoted := &metapb.Peer{
+					Id:        s.FromPeerID,
+					StoreId:   s.FromStore,
+					IsLearner: true,
+				}
+				promoted := &metapb.Peer{
+					Id:      s.ToPeerID,
+					StoreId: s.ToStore,
+				}
+				region = region.Clone(core.WithRemoveStorePeer(s.FromStore), core.WithRemoveStorePeer(s.ToStore),
+					core.WithAddPeer(demoted), core.WithAddPeer(promoted))
 			default:
 				panic("Unknown operator step")
 			}
@@ -420,11 +442,14 @@ const (
 	defaultMaxReplicas          = 3
 	defaultMaxSnapshotCount     = 3
 	defaultMaxPendingPeerCount  = 16
+	defaultMaxPendingCompaction = 64 * (1 << 30)
+	defaultMinSnapshotSpeed     = 10 * (1 << 20)
 	defaultMaxMergeRegionSize   = 0
 	defaultMaxMergeRegionKeys   = 0
 	defaultSplitMergeInterval   = 0
 	defaultMaxStoreDownTime     = 30 * time.Minute
 	defaultLeaderScheduleLimit  = 4
+	defaultLeaderScheduleBatch  = 1
 	defaultRegionScheduleLimit  = 4
 	defaultReplicaScheduleLimit = 8
 	defaultMergeScheduleLimit   = 8
@@ -438,21 +463,27 @@ const (
 type MockSchedulerOptions struct {
 	RegionScheduleLimit          uint64
 	LeaderScheduleLimit          uint64
+	LeaderScheduleBatch          uint64
 	ReplicaScheduleLimit         uint64
 	MergeScheduleLimit           uint64
 	MaxSnapshotCount             uint64
 	MaxPendingPeerCount          uint64
+	MaxPendingCompactionBytes    uint64
+	MinSnapshotSpeed             uint64
 	MaxMergeRegionSize           uint64
 	MaxMergeRegionKeys           uint64
 	SplitMergeInterval           time.Duration
+	MergeProtectionInterval      time.Duration
 	MaxStoreDownTime             time.Duration
 	MaxReplicas                  int
 	LocationLabels               []string
+	IsolationLevel               string
 	HotRegionLowThreshold        int
 	TolerantSizeRatio            float64
 	LowSpaceRatio                float64
 	HighSpaceRatio               float64
 	DisableLearner               bool
+	EnableJointConsensus         bool
 	DisableRemoveDownReplica     bool
 	DisableReplaceOfflineReplica bool
 	DisableMakeUpReplica         bool
@@ -467,6 +498,7 @@ func NewMockSchedulerOptions() *MockSchedulerOptions {
 	mso := &MockSchedulerOptions{}
 	mso.RegionScheduleLimit = defaultRegionScheduleLimit
 	mso.LeaderScheduleLimit = defaultLeaderScheduleLimit
+	mso.LeaderScheduleBatch = defaultLeaderScheduleBatch
 	mso.ReplicaScheduleLimit = defaultReplicaScheduleLimit
 	mso.MergeScheduleLimit = defaultMergeScheduleLimit
 	mso.MaxSnapshotCount = defaultMaxSnapshotCount
@@ -477,6 +509,8 @@ func NewMockSchedulerOptions() *MockSchedulerOptions {
 	mso.MaxReplicas = defaultMaxReplicas
 	mso.HotRegionLowThreshold = HotRegionLowThreshold
 	mso.MaxPendingPeerCount = defaultMaxPendingPeerCount
+	mso.MaxPendingCompactionBytes = defaultMaxPendingCompaction
+	mso.MinSnapshotSpeed = defaultMinSnapshotSpeed
 	mso.TolerantSizeRatio = defaultTolerantSizeRatio
 	mso.LowSpaceRatio = defaultLowSpaceRatio
 	mso.HighSpaceRatio = defaultHighSpaceRatio
@@ -513,6 +547,16 @@ func (mso *MockSchedulerOptions) GetMaxPendingPeerCount() uint64 {
 	return mso.MaxPendingPeerCount
 }
 
+// GetMaxPendingCompactionBytes mock method
+func (mso *MockSchedulerOptions) GetMaxPendingCompactionBytes() uint64 {
+	return mso.MaxPendingCompactionBytes
+}
+
+// GetMinSnapshotSpeed mock method
+func (mso *MockSchedulerOptions) GetMinSnapshotSpeed() uint64 {
+	return mso.MinSnapshotSpeed
+}
+
 // GetMaxMergeRegionSize mock method
 func (mso *MockSchedulerOptions) GetMaxMergeRegionSize() uint64 {
 	return mso.MaxMergeRegionSize
@@ -523,11 +567,21 @@ func (mso *MockSchedulerOptions) GetMaxMergeRegionKeys() uint64 {
 	return mso.MaxMergeRegionKeys
 }
 
+// GetLeaderScheduleBatch mock method
+func (mso *MockSchedulerOptions) GetLeaderScheduleBatch() uint64 {
+	return mso.LeaderScheduleBatch
+}
+
 // GetSplitMergeInterval mock method
 func (mso *MockSchedulerOptions) GetSplitMergeInterval() time.Duration {
 	return mso.SplitMergeInterval
 }
 
+// GetMergeProtectionInterval mock method
+func (mso *MockSchedulerOptions) GetMergeProtectionInterval(startKey, endKey []byte) time.Duration {
+	return mso.MergeProtectionInterval
+}
+
 // GetMaxStoreDownTime mock method
 func (mso *MockSchedulerOptions) GetMaxStoreDownTime() time.Duration {
 	return mso.MaxStoreDownTime
@@ -543,6 +597,11 @@ func (mso *MockSchedulerOptions) GetLocationLabels() []string {
 	return mso.LocationLabels
 }
 
+// GetIsolationLevel mock method
+func (mso *MockSchedulerOptions) GetIsolationLevel() string {
+	return mso.IsolationLevel
+}
+
 // GetHotRegionLowThreshold mock method
 func (mso *MockSchedulerOptions) GetHotRegionLowThreshold() int {
 	return mso.HotRegionLowThreshold
@@ -573,6 +632,11 @@ func (mso *MockSchedulerOptions) IsRaftLearnerEnabled() bool {
 	return !mso.DisableLearner
 }
 
+// IsJointConsensusEnabled mock method
+func (mso *MockSchedulerOptions) IsJointConsensusEnabled() bool {
+	return mso.EnableJointConsensus && !mso.DisableLearner
+}
+
 // IsRemoveDownReplicaEnabled mock method.
 func (mso *MockSchedulerOptions) IsRemoveDownReplicaEnabled() bool {
 	return !mso.DisableRemoveDownReplica
@@ -20,6 +20,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/opentracing/opentracing-go"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	log "github.com/sirupsen/logrus"
@@ -32,7 +33,9 @@ const (
 	// longer than it, the operator will be considered timeout.
 	LeaderOperatorWaitTime = 10 * time.Second
 	// RegionOperatorWaitTime is the duration that when a region operator lives
-	// longer than it, the operator will be considered timeout.
+	// longer than it, the operator will be considered timeout. It is extended
+	// by the time to send snapshots for the steps adding peers, see
+	// SetSnapshotSpeed.
 	RegionOperatorWaitTime = 10 * time.Minute
 )
 
@@ -113,7 +116,9 @@ func (al AddLearner) IsFinish(region *core.RegionInfo) bool {
 			log.Warnf("expect %v, but obtain learner %v", al.String(), p.GetId())
 			return false
 		}
-		return region.GetPendingLearner(p.GetId()) == nil
+		// The learner is promoted after it catches up with the leader, that is,
+		// it is neither pending nor down in the region heartbeat.
+		return region.GetPendingLearner(p.GetId()) == nil && region.GetDownLearner(p.GetId()) == nil
 	}
 	return false
 }
@@ -149,6 +154,30 @@ func (pl PromoteLearner) IsFinish(region *core.RegionInfo) bool {
 // Influence calculates the store difference that current step make
 func (pl PromoteLearner) Influence(opInfluence OpInfluence, region *core.RegionInfo) {}
 
+// DemotePromote is an OperatorStep that demotes a voter to learner and
+// promotes a learner to voter in a single conf change by joint consensus, so
+// that the number of voters does not drop while the voter is replaced.
+type DemotePromote struct {
+	FromStore, FromPeerID uint64
+	ToStore, ToPeerID     uint64
+}
+
+func (dp DemotePromote) String() string {
+	return fmt.Sprintf("demote voter peer %v on store %v to learner and promote learner peer %v on store %v to voter",
+		dp.FromPeerID, dp.FromStore, dp.ToPeerID, dp.ToStore)
+}
+
+// IsFinish checks if current step is finished.
+func (dp DemotePromote) IsFinish(region *core.RegionInfo) bool {
+	if region.GetStoreVoter(dp.FromStore) != nil {
+		return false
+	}
+	return PromoteLearner{ToStore: dp.ToStore, PeerID: dp.ToPeerID}.IsFinish(region)
+}
+
+// Influence calculates the store difference that current step make
+func (dp DemotePromote) Influence(opInfluence OpInfluence, region *core.RegionInfo) {}
+
 // RemovePeer is an OperatorStep that removes a region peer.
 type RemovePeer struct {
 	FromStore uint64
@@ -171,6 +200,51 @@ func (rp RemovePeer) Influence(opInfluence OpInfluence, region *core.RegionInfo)
 	from.RegionCount--
 }
 
+// SwitchToWitness is an OperatorStep that switches a voter to a witness, which
+// keeps only the Raft log. The store gets it from the witness service.
+type SwitchToWitness struct {
+	StoreID, PeerID uint64
+}
+
+func (sw SwitchToWitness) String() string {
+	return fmt.Sprintf("switch peer %v on store %v to witness", sw.PeerID, sw.StoreID)
+}
+
+// IsFinish checks if current step is finished.
+func (sw SwitchToWitness) IsFinish(region *core.RegionInfo) bool {
+	return region.GetWitness(sw.PeerID) != nil
+}
+
+// Influence calculates the store difference that current step make
+func (sw SwitchToWitness) Influence(opInfluence OpInfluence, region *core.RegionInfo) {
+	store := opInfluence.GetStoreInfluence(sw.StoreID)
+
+	store.RegionSize -= region.GetApproximateSize()
+}
+
+// SwitchToVoter is an OperatorStep that switches a witness back to a full
+// voter, which receives the region data by a snapshot. The store gets it from
+// the witness service.
+type SwitchToVoter struct {
+	StoreID, PeerID uint64
+}
+
+func (sv SwitchToVoter) String() string {
+	return fmt.Sprintf("switch witness peer %v on store %v to voter", sv.PeerID, sv.StoreID)
+}
+
+// IsFinish checks if current step is finished.
+func (sv SwitchToVoter) IsFinish(region *core.RegionInfo) bool {
+	return region.GetWitness(sv.PeerID) == nil
+}
+
+// Influence calculates the store difference that current step make
+func (sv SwitchToVoter) Influence(opInfluence OpInfluence, region *core.RegionInfo) {
+	store := opInfluence.GetStoreInfluence(sv.StoreID)
+
+	store.RegionSize += region.GetApproximateSize()
+}
+
 // MergeRegion is an OperatorStep that merge two regions.
 type MergeRegion struct {
 	FromRegion *metapb.Region
@@ -213,9 +287,21 @@ func (mr MergeRegion) Influence(opInfluence OpInfluence, region *core.RegionInfo
 type SplitRegion struct {
 	StartKey, EndKey []byte
 	Policy           pdpb.CheckPolicy
+	// SplitKeys are the keys to split the region at, the policy is ignored if
+	// they are set. The split is not sent by the region heartbeat responses,
+	// since pdpb.SplitRegion can not carry the keys, the leader store gets it
+	// from splitpb.Split/GetSplitCommands instead.
+	SplitKeys [][]byte
 }
 
 func (sr SplitRegion) String() string {
+	if len(sr.SplitKeys) > 0 {
+		keys := make([]string, 0, len(sr.SplitKeys))
+		for _, key := range sr.SplitKeys {
+			keys = append(keys, string(core.HexRegionKey(key)))
+		}
+		return fmt.Sprintf("split region at keys %v", keys)
+	}
 	return fmt.Sprintf("split region with policy %s", sr.Policy.String())
 }
 
@@ -246,6 +332,14 @@ type Operator struct {
 	createTime  time.Time
 	stepTime    int64
 	level       core.PriorityLevel
+	// snapshotTime is the time to send a snapshot of the region, which
+	// extends the timeout for each step adding a peer.
+	snapshotTime time.Duration
+	// namespace is the namespace of the region when the operator is added.
+	namespace string
+	// traceContext is the span context of the checker or scheduler creating
+	// the operator, and then of the span adding the operator.
+	traceContext opentracing.SpanContext
 }
 
 // NewOperator creates a new operator.
@@ -283,6 +377,16 @@ func (o *Operator) Desc() string {
 	return o.desc
 }
 
+// SetTraceContext sets the span context which the operator is traced under.
+func (o *Operator) SetTraceContext(ctx opentracing.SpanContext) {
+	o.traceContext = ctx
+}
+
+// TraceContext returns the span context which the operator is traced under.
+func (o *Operator) TraceContext() opentracing.SpanContext {
+	return o.traceContext
+}
+
 // SetDesc sets the description for the operator.
 func (o *Operator) SetDesc(desc string) {
 	o.desc = desc
@@ -357,18 +461,40 @@ func (o *Operator) IsFinish() bool {
 	return atomic.LoadInt32(&o.currentStep) >= int32(len(o.steps))
 }
 
+// SetSnapshotSpeed estimates the time to send a snapshot of the region, whose
+// size is in MB, at the speed in bytes per second. The timeout of the operator
+// is extended by the time for each step adding a peer, so that the operators
+// of huge regions do not time out halfway and leave learners behind.
+func (o *Operator) SetSnapshotSpeed(regionSize int64, speed uint64) {
+	if regionSize <= 0 || speed == 0 {
+		o.snapshotTime = 0
+		return
+	}
+	o.snapshotTime = time.Duration(float64(regionSize) * (1 << 20) / float64(speed) * float64(time.Second))
+}
+
+// Timeout returns the duration that the operator is considered timeout when
+// it lives longer than.
+func (o *Operator) Timeout() time.Duration {
+	if o.kind&OpRegion == 0 {
+		return LeaderOperatorWaitTime
+	}
+	timeout := RegionOperatorWaitTime
+	for _, step := range o.steps {
+		switch step.(type) {
+		case AddPeer, AddLearner:
+			timeout += o.snapshotTime
+		}
+	}
+	return timeout
+}
+
 // IsTimeout checks the operator's create time and determines if it is timeout.
 func (o *Operator) IsTimeout() bool {
-	var timeout bool
 	if o.IsFinish() {
 		return false
 	}
-	if o.kind&OpRegion != 0 {
-		timeout = time.Since(o.createTime) > RegionOperatorWaitTime
-	} else {
-		timeout = time.Since(o.createTime) > LeaderOperatorWaitTime
-	}
-	if timeout {
+	if time.Since(o.createTime) > o.Timeout() {
 		operatorCounter.WithLabelValues(o.Desc(), "timeout").Inc()
 		return true
 	}
@@ -433,9 +559,22 @@ func CreateRemovePeerOperator(desc string, cluster Cluster, kind OperatorKind, r
 }
 
 // CreateMovePeerOperator creates an Operator that replaces an old peer with a new peer.
+// The new peer is added as a learner and promoted after it catches up, then the
+// old peer is removed. If joint consensus is enabled, a voter is replaced by
+// demoting it and promoting the new peer in a single conf change before it is
+// removed, otherwise each of them takes a separate conf change.
 func CreateMovePeerOperator(desc string, cluster Cluster, region *core.RegionInfo, kind OperatorKind, oldStore, newStore uint64, peerID uint64) *Operator {
 	removeKind, steps := removePeerSteps(cluster, region, oldStore)
 	var st []OperatorStep
+	if old := region.GetStoreVoter(oldStore); old != nil && cluster.IsJointConsensusEnabled() {
+		// The leader is transferred before the demotion, and the demoted peer
+		// is removed at last.
+		last := len(steps) - 1
+		st = append(st, AddLearner{ToStore: newStore, PeerID: peerID})
+		st = append(st, steps[:last]...)
+		st = append(st, DemotePromote{FromStore: oldStore, FromPeerID: old.GetId(), ToStore: newStore, ToPeerID: peerID}, steps[last])
+		return NewOperator(desc, region.GetID(), region.GetRegionEpoch(), removeKind|kind|OpRegion, st...)
+	}
 	if cluster.IsRaftLearnerEnabled() {
 		st = []OperatorStep{
 			AddLearner{ToStore: newStore, PeerID: peerID},
@@ -455,7 +594,7 @@ func removePeerSteps(cluster Cluster, region *core.RegionInfo, storeID uint64) (
 	if region.GetLeader() != nil && region.GetLeader().GetStoreId() == storeID {
 		for id := range region.GetFollowers() {
 			follower := cluster.GetStore(id)
-			if follower != nil && !cluster.CheckLabelProperty(RejectLeader, follower.Labels) {
+			if follower != nil && !NewRejectLeaderFilter().FilterTarget(cluster, follower) {
 				steps = append(steps, TransferLeader{FromStore: storeID, ToStore: id})
 				kind = OpLeader
 				break
@@ -0,0 +1,148 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pkg/errors"
+)
+
+// Short names of operator steps used by the persisted operators.
+const (
+	stepTransferLeader = "tl"
+	stepAddPeer        = "ap"
+	stepAddLearner     = "al"
+	stepPromoteLearner = "pl"
+	stepDemotePromote  = "dp"
+	stepRemovePeer     = "rp"
+	stepMergeRegion    = "mr"
+	stepSplitRegion    = "sr"
+)
+
+// operatorMeta is the compact persisted form of an operator.
+type operatorMeta struct {
+	Desc        string             `json:"d"`
+	RegionID    uint64             `json:"r"`
+	ConfVer     uint64             `json:"c"`
+	Version     uint64             `json:"v"`
+	Kind        OperatorKind       `json:"k"`
+	Level       core.PriorityLevel `json:"l"`
+	CurrentStep int32              `json:"i,omitempty"`
+	CreateTime  int64              `json:"t"`
+	Steps       []stepMeta         `json:"s"`
+}
+
+// stepMeta is the compact persisted form of an operator step. Only the fields
+// used by the step type are set.
+type stepMeta struct {
+	Type       string           `json:"t"`
+	FromStore  uint64           `json:"f,omitempty"`
+	ToStore    uint64           `json:"to,omitempty"`
+	PeerID     uint64           `json:"p,omitempty"`
+	FromPeerID uint64           `json:"fp,omitempty"`
+	FromRegion *metapb.Region   `json:"fr,omitempty"`
+	ToRegion   *metapb.Region   `json:"tr,omitempty"`
+	IsPassive  bool             `json:"ps,omitempty"`
+	StartKey   []byte           `json:"sk,omitempty"`
+	EndKey     []byte           `json:"ek,omitempty"`
+	Policy     pdpb.CheckPolicy `json:"po,omitempty"`
+	SplitKeys  [][]byte         `json:"ks,omitempty"`
+}
+
+// EncodeOperator encodes the operator with its progress, so that it can be
+// restored by DecodeOperator later.
+func EncodeOperator(op *Operator) ([]byte, error) {
+	meta := operatorMeta{
+		Desc:        op.desc,
+		RegionID:    op.regionID,
+		ConfVer:     op.regionEpoch.GetConfVer(),
+		Version:     op.regionEpoch.GetVersion(),
+		Kind:        op.kind,
+		Level:       op.level,
+		CurrentStep: atomic.LoadInt32(&op.currentStep),
+		CreateTime:  op.createTime.UnixNano(),
+		Steps:       make([]stepMeta, 0, len(op.steps)),
+	}
+	for _, step := range op.steps {
+		var s stepMeta
+		switch st := step.(type) {
+		case TransferLeader:
+			s = stepMeta{Type: stepTransferLeader, FromStore: st.FromStore, ToStore: st.ToStore}
+		case AddPeer:
+			s = stepMeta{Type: stepAddPeer, ToStore: st.ToStore, PeerID: st.PeerID}
+		case AddLearner:
+			s = stepMeta{Type: stepAddLearner, ToStore: st.ToStore, PeerID: st.PeerID}
+		case PromoteLearner:
+			s = stepMeta{Type: stepPromoteLearner, ToStore: st.ToStore, PeerID: st.PeerID}
+		case DemotePromote:
+			s = stepMeta{Type: stepDemotePromote, FromStore: st.FromStore, FromPeerID: st.FromPeerID, ToStore: st.ToStore, PeerID: st.ToPeerID}
+		case RemovePeer:
+			s = stepMeta{Type: stepRemovePeer, FromStore: st.FromStore}
+		case MergeRegion:
+			s = stepMeta{Type: stepMergeRegion, FromRegion: st.FromRegion, ToRegion: st.ToRegion, IsPassive: st.IsPassive}
+		case SplitRegion:
+			s = stepMeta{Type: stepSplitRegion, StartKey: st.StartKey, EndKey: st.EndKey, Policy: st.Policy, SplitKeys: st.SplitKeys}
+		default:
+			return nil, errors.Errorf("unknown operator step %v", step)
+		}
+		meta.Steps = append(meta.Steps, s)
+	}
+	data, err := json.Marshal(meta)
+	return data, errors.WithStack(err)
+}
+
+// DecodeOperator decodes an operator encoded by EncodeOperator.
+func DecodeOperator(data []byte) (*Operator, error) {
+	var meta operatorMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	steps := make([]OperatorStep, 0, len(meta.Steps))
+	for _, s := range meta.Steps {
+		var step OperatorStep
+		switch s.Type {
+		case stepTransferLeader:
+			step = TransferLeader{FromStore: s.FromStore, ToStore: s.ToStore}
+		case stepAddPeer:
+			step = AddPeer{ToStore: s.ToStore, PeerID: s.PeerID}
+		case stepAddLearner:
+			step = AddLearner{ToStore: s.ToStore, PeerID: s.PeerID}
+		case stepPromoteLearner:
+			step = PromoteLearner{ToStore: s.ToStore, PeerID: s.PeerID}
+		case stepDemotePromote:
+			step = DemotePromote{FromStore: s.FromStore, FromPeerID: s.FromPeerID, ToStore: s.ToStore, ToPeerID: s.PeerID}
+		case stepRemovePeer:
+			step = RemovePeer{FromStore: s.FromStore}
+		case stepMergeRegion:
+			step = MergeRegion{FromRegion: s.FromRegion, ToRegion: s.ToRegion, IsPassive: s.IsPassive}
+		case stepSplitRegion:
+			step = SplitRegion{StartKey: s.StartKey, EndKey: s.EndKey, Policy: s.Policy, SplitKeys: s.SplitKeys}
+		default:
+			return nil, errors.Errorf("unknown operator step type %q of region %d", s.Type, meta.RegionID)
+		}
+		steps = append(steps, step)
+	}
+	epoch := &metapb.RegionEpoch{ConfVer: meta.ConfVer, Version: meta.Version}
+	op := NewOperator(meta.Desc, meta.RegionID, epoch, meta.Kind, steps...)
+	op.level = meta.Level
+	op.currentStep = meta.CurrentStep
+	op.createTime = time.Unix(0, meta.CreateTime)
+	return op, nil
+}
@@ -15,23 +15,44 @@ package schedule
 
 import (
 	"container/list"
+	"fmt"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/opentracing/opentracing-go"
 	"github.com/pingcap/kvproto/pkg/eraftpb"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/pkg/confchangepb"
+	"github.com/pingcap/pd/pkg/splitpb"
+	"github.com/pingcap/pd/pkg/witnesspb"
 	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/namespace"
 	log "github.com/sirupsen/logrus"
 )
 
 var historyKeepTime = 5 * time.Minute
 
+// orphanLearnerKeepTime is the duration to keep the learners left by timeout
+// operators, in which they are removed by the replica checker unless they are
+// promoted.
+var orphanLearnerKeepTime = 30 * time.Minute
+
 // HeartbeatStreams is an interface of async region heartbeat.
 type HeartbeatStreams interface {
 	SendMsg(region *core.RegionInfo, msg *pdpb.RegionHeartbeatResponse)
 }
 
+// OperatorStorage is used to persist the running operators, so that they can
+// be taken over by the next leader.
+type OperatorStorage interface {
+	SaveOperator(regionID uint64, data []byte) error
+	DeleteOperator(regionID uint64) error
+	LoadOperators(f func(data []byte) (uint64, error)) error
+}
+
 // OperatorController is used to limit the speed of scheduling.
 type OperatorController struct {
 	sync.RWMutex
@@ -40,6 +61,20 @@ type OperatorController struct {
 	hbStreams HeartbeatStreams
 	histories *list.List
 	counts    map[OperatorKind]uint64
+	storage   OperatorStorage
+	// classifier attributes the operators to namespaces.
+	classifier namespace.Classifier
+	// nsCounts is the counts of operators in each namespace.
+	nsCounts map[string]map[OperatorKind]uint64
+	// orphanLearners is the time when the learners, which are added by the
+	// timeout operators but not promoted, are left.
+	orphanLearners map[uint64]time.Time
+	// pendingPersists are the operators to persist, or nil to delete the
+	// persisted operator of the region. They are written by persistLoop
+	// outside the lock, so that the heartbeats do not wait for the storage.
+	pendingPersists map[uint64]*Operator
+	persisting      bool
+	persistWg       sync.WaitGroup
 }
 
 // NewOperatorController creates a OperatorController.
@@ -50,17 +85,48 @@ func NewOperatorController(cluster Cluster, hbStreams HeartbeatStreams) *Operato
 		hbStreams: hbStreams,
 		histories: list.New(),
 		counts:    make(map[OperatorKind]uint64),
+		nsCounts:  make(map[string]map[OperatorKind]uint64),
+
+		orphanLearners:  make(map[uint64]time.Time),
+		pendingPersists: make(map[uint64]*Operator),
 	}
 }
 
+// SetStorage sets the storage to persist the running operators.
+func (oc *OperatorController) SetStorage(storage OperatorStorage) {
+	oc.Lock()
+	defer oc.Unlock()
+	oc.storage = storage
+}
+
+// SetClassifier sets the classifier to attribute the operators to namespaces.
+// All operators belong to the default namespace if it is not set.
+func (oc *OperatorController) SetClassifier(classifier namespace.Classifier) {
+	oc.Lock()
+	defer oc.Unlock()
+	oc.classifier = classifier
+}
+
+func (oc *OperatorController) getRegionNamespace(region *core.RegionInfo) string {
+	if oc.classifier == nil {
+		return namespace.DefaultNamespace
+	}
+	return oc.classifier.GetRegionNamespace(region)
+}
+
 // Dispatch is used to dispatch the operator of a region.
 func (oc *OperatorController) Dispatch(region *core.RegionInfo) {
 	// Check existed operator.
 	if op := oc.GetOperator(region.GetID()); op != nil {
 		timeout := op.IsTimeout()
-		if step := op.Check(region); step != nil && !timeout {
+		currentStep := atomic.LoadInt32(&op.currentStep)
+		step := op.Check(region)
+		if step != nil && currentStep != atomic.LoadInt32(&op.currentStep) {
+			oc.updatePersistedOperator(op)
+		}
+		if step != nil && !timeout {
 			operatorCounter.WithLabelValues(op.Desc(), "check").Inc()
-			oc.SendScheduleCommand(region, step)
+			oc.sendStep(op, region, step)
 			return
 		}
 		if op.IsFinish() {
@@ -72,6 +138,7 @@ func (oc *OperatorController) Dispatch(region *core.RegionInfo) {
 		} else if timeout {
 			log.Infof("[region %v] operator timeout: %s", region.GetID(), op)
 			oc.RemoveOperator(op)
+			oc.recordOrphanLearners(op, region)
 		}
 	}
 }
@@ -94,6 +161,20 @@ func (oc *OperatorController) AddOperator(ops ...*Operator) bool {
 	return true
 }
 
+// CheckAddOperator checks whether the operators can be added without adding
+// them.
+func (oc *OperatorController) CheckAddOperator(ops ...*Operator) bool {
+	oc.RLock()
+	defer oc.RUnlock()
+
+	for _, op := range ops {
+		if !oc.checkAddOperator(op) {
+			return false
+		}
+	}
+	return true
+}
+
 func (oc *OperatorController) checkAddOperator(op *Operator) bool {
 	region := oc.cluster.GetRegion(op.RegionID())
 	if region == nil {
@@ -108,9 +189,73 @@ func (oc *OperatorController) checkAddOperator(op *Operator) bool {
 		log.Debugf("[region %v] already have operator %s, cancel add operator", op.RegionID(), old)
 		return false
 	}
+	if ns := oc.getRegionNamespace(region); oc.exceedNamespaceLimit(ns, op) {
+		log.Debugf("[region %v] namespace %s reaches the operator limit, cancel add operator %s", op.RegionID(), ns, op)
+		namespaceOperatorCounter.WithLabelValues(ns, namespaceLimitType(op.Kind()), "exceed").Inc()
+		return false
+	}
 	return true
 }
 
+// namespaceLimitType returns the type of schedule limit which applies to the
+// operators of the kind. It is empty if no namespace limit applies.
+func namespaceLimitType(kind OperatorKind) string {
+	switch {
+	case kind&OpMerge != 0:
+		return "merge"
+	case kind&OpReplica != 0:
+		return "replica"
+	case kind&OpHotRegion != 0:
+		// The hot region limit is not configured per namespace.
+		return ""
+	case kind&OpRegion != 0:
+		return "region"
+	case kind&OpLeader != 0:
+		return "leader"
+	}
+	return ""
+}
+
+var namespaceLimitTypes = map[string]OperatorKind{
+	"merge":   OpMerge,
+	"replica": OpReplica,
+	"region":  OpRegion,
+	"leader":  OpLeader,
+}
+
+func (oc *OperatorController) getNamespaceLimit(ns string, limitType string) uint64 {
+	opt := oc.cluster.GetOpt()
+	switch limitType {
+	case "merge":
+		return opt.GetMergeScheduleLimit(ns)
+	case "replica":
+		return opt.GetReplicaScheduleLimit(ns)
+	case "region":
+		return opt.GetRegionScheduleLimit(ns)
+	default:
+		return opt.GetLeaderScheduleLimit(ns)
+	}
+}
+
+// exceedNamespaceLimit returns true if the namespace can not have more
+// operators like op in flight. The admin operators and the operators of high
+// priority, which repair the cluster, are not limited.
+func (oc *OperatorController) exceedNamespaceLimit(ns string, op *Operator) bool {
+	if op.Kind()&OpAdmin != 0 || op.GetPriorityLevel() == core.HighPriority {
+		return false
+	}
+	limitType := namespaceLimitType(op.Kind())
+	if limitType == "" {
+		return false
+	}
+	count := oc.namespaceCountLocked(ns, namespaceLimitTypes[limitType])
+	// The replaced operator no longer counts.
+	if old := oc.operators[op.RegionID()]; old != nil && old.namespace == ns && old.Kind()&namespaceLimitTypes[limitType] != 0 {
+		count--
+	}
+	return count >= oc.getNamespaceLimit(ns, limitType)
+}
+
 func isHigherPriorityOperator(new, old *Operator) bool {
 	return new.GetPriorityLevel() < old.GetPriorityLevel()
 }
@@ -119,6 +264,13 @@ func (oc *OperatorController) addOperatorLocked(op *Operator) bool {
 	regionID := op.RegionID()
 
 	log.Infof("[region %v] add operator: %s", regionID, op)
+	span := opentracing.StartSpan("pd.AddOperator", opentracing.ChildOf(op.TraceContext()))
+	span.SetTag("region_id", regionID)
+	span.SetTag("operator", op.Desc())
+	span.SetTag("kind", op.Kind().String())
+	span.SetTag("steps", fmt.Sprint(op.steps))
+	op.SetTraceContext(span.Context())
+	defer span.Finish()
 
 	// If there is an old operator, replace it. The priority should be checked
 	// already.
@@ -128,16 +280,23 @@ func (oc *OperatorController) addOperatorLocked(op *Operator) bool {
 		oc.removeOperatorLocked(old)
 	}
 
+	region := oc.cluster.GetRegion(op.RegionID())
+	if region != nil {
+		op.namespace = oc.getRegionNamespace(region)
+		op.SetSnapshotSpeed(region.GetApproximateSize(), oc.cluster.GetMinSnapshotSpeed())
+	}
 	oc.operators[regionID] = op
 	oc.updateCounts(oc.operators)
+	oc.persistOperatorLocked(op)
 
-	if region := oc.cluster.GetRegion(op.RegionID()); region != nil {
+	if region != nil {
 		if step := op.Check(region); step != nil {
-			oc.SendScheduleCommand(region, step)
+			oc.sendStep(op, region, step)
 		}
 	}
 
 	operatorCounter.WithLabelValues(op.Desc(), "create").Inc()
+	namespaceOperatorCounter.WithLabelValues(op.namespace, namespaceLimitType(op.Kind()), "create").Inc()
 	return true
 }
 
@@ -152,9 +311,171 @@ func (oc *OperatorController) removeOperatorLocked(op *Operator) {
 	regionID := op.RegionID()
 	delete(oc.operators, regionID)
 	oc.updateCounts(oc.operators)
+	oc.deletePersistedOperatorLocked(regionID)
 	operatorCounter.WithLabelValues(op.Desc(), "remove").Inc()
 }
 
+func (oc *OperatorController) persistOperatorLocked(op *Operator) {
+	oc.queuePersistLocked(op.RegionID(), op)
+}
+
+func (oc *OperatorController) deletePersistedOperatorLocked(regionID uint64) {
+	oc.queuePersistLocked(regionID, nil)
+}
+
+// queuePersistLocked queues the operator to persist, and starts persistLoop
+// if it is not running. Only the latest operator of a region is persisted.
+func (oc *OperatorController) queuePersistLocked(regionID uint64, op *Operator) {
+	if oc.storage == nil {
+		return
+	}
+	oc.pendingPersists[regionID] = op
+	if !oc.persisting {
+		oc.persisting = true
+		oc.persistWg.Add(1)
+		go oc.persistLoop()
+	}
+}
+
+// persistLoop writes the queued operators to the storage until the queue is
+// empty.
+func (oc *OperatorController) persistLoop() {
+	defer oc.persistWg.Done()
+	for {
+		oc.Lock()
+		pending, storage := oc.pendingPersists, oc.storage
+		if len(pending) == 0 {
+			oc.persisting = false
+			oc.Unlock()
+			return
+		}
+		oc.pendingPersists = make(map[uint64]*Operator)
+		oc.Unlock()
+
+		for regionID, op := range pending {
+			if op == nil {
+				if err := storage.DeleteOperator(regionID); err != nil {
+					log.Errorf("[region %v] failed to delete persisted operator: %v", regionID, err)
+				}
+				continue
+			}
+			data, err := EncodeOperator(op)
+			if err == nil {
+				err = storage.SaveOperator(regionID, data)
+			}
+			if err != nil {
+				log.Errorf("[region %v] failed to persist operator %s: %v", regionID, op, err)
+			}
+		}
+	}
+}
+
+// updatePersistedOperator persists the progress of the operator if it is
+// still running.
+func (oc *OperatorController) updatePersistedOperator(op *Operator) {
+	oc.Lock()
+	defer oc.Unlock()
+	if oc.operators[op.RegionID()] == op {
+		oc.persistOperatorLocked(op)
+	}
+}
+
+// LoadOperators takes over the operators persisted by the previous leader. An
+// operator is resumed if it can still be executed. Otherwise it is canceled,
+// and the peers it has added halfway are removed by a cleanup operator.
+func (oc *OperatorController) LoadOperators() error {
+	oc.Lock()
+	defer oc.Unlock()
+	if oc.storage == nil {
+		return nil
+	}
+	return oc.storage.LoadOperators(func(data []byte) (uint64, error) {
+		op, err := DecodeOperator(data)
+		if err != nil {
+			return 0, err
+		}
+		oc.restoreOperatorLocked(op)
+		return op.RegionID(), nil
+	})
+}
+
+func (oc *OperatorController) restoreOperatorLocked(op *Operator) {
+	regionID := op.RegionID()
+	if _, ok := oc.operators[regionID]; ok {
+		// A new operator is created by current leader already.
+		return
+	}
+	region := oc.cluster.GetRegion(regionID)
+	if region == nil {
+		log.Infof("[region %v] region not found, drop persisted operator: %s", regionID, op)
+		oc.deletePersistedOperatorLocked(regionID)
+		return
+	}
+	op.SetSnapshotSpeed(region.GetApproximateSize(), oc.cluster.GetMinSnapshotSpeed())
+	step := op.Check(region)
+	if op.IsFinish() {
+		log.Infof("[region %v] persisted operator finish: %s", regionID, op)
+		oc.deletePersistedOperatorLocked(regionID)
+		return
+	}
+	if op.IsTimeout() || region.GetRegionEpoch().GetVersion() != op.RegionEpoch().GetVersion() {
+		log.Infof("[region %v] cancel persisted operator: %s", regionID, op)
+		operatorCounter.WithLabelValues(op.Desc(), "canceled").Inc()
+		oc.deletePersistedOperatorLocked(regionID)
+		if cleanup := createCleanupOperator(op, region); cleanup != nil {
+			oc.addOperatorLocked(cleanup)
+		}
+		return
+	}
+
+	log.Infof("[region %v] resume persisted operator: %s", regionID, op)
+	op.namespace = oc.getRegionNamespace(region)
+	oc.operators[regionID] = op
+	oc.updateCounts(oc.operators)
+	oc.SendScheduleCommand(region, step)
+	operatorCounter.WithLabelValues(op.Desc(), "resume").Inc()
+}
+
+// createCleanupOperator creates an operator to remove the peers which are added
+// by the canceled operator but can not serve yet, so that the region will not
+// be left half-moved.
+func createCleanupOperator(op *Operator, region *core.RegionInfo) *Operator {
+	var removing bool
+	for _, step := range op.steps {
+		if rp, ok := step.(RemovePeer); ok && region.GetStorePeer(rp.FromStore) != nil {
+			removing = true
+		}
+	}
+
+	var steps []OperatorStep
+	for _, step := range op.steps {
+		var storeID, peerID uint64
+		switch st := step.(type) {
+		case AddPeer:
+			storeID, peerID = st.ToStore, st.PeerID
+		case AddLearner:
+			storeID, peerID = st.ToStore, st.PeerID
+		default:
+			continue
+		}
+		peer := region.GetStorePeer(storeID)
+		if peer == nil || peer.GetId() != peerID || peer.GetId() == region.GetLeader().GetId() {
+			continue
+		}
+		// A learner is always redundant, while a pending voter is redundant
+		// only if the peer it replaces is not removed.
+		if peer.GetIsLearner() || (removing && region.GetPendingPeer(peerID) != nil) {
+			steps = append(steps, RemovePeer{FromStore: storeID})
+		}
+	}
+	if len(steps) == 0 {
+		return nil
+	}
+	cleanup := NewOperator("cleanup-operator", region.GetID(), region.GetRegionEpoch(), OpRegion|OpAdmin, steps...)
+	cleanup.SetPriorityLevel(core.HighPriority)
+	return cleanup
+}
+
 // GetOperator gets a operator from the given region.
 func (oc *OperatorController) GetOperator(regionID uint64) *Operator {
 	oc.RLock()
@@ -175,6 +496,18 @@ func (oc *OperatorController) GetOperators() []*Operator {
 	return operators
 }
 
+// sendStep sends the step of the operator to the region. The span follows
+// from the span adding the operator, so that the steps of an operator are
+// traced together.
+func (oc *OperatorController) sendStep(op *Operator, region *core.RegionInfo, step OperatorStep) {
+	span := opentracing.StartSpan("pd.SendStep", opentracing.FollowsFrom(op.TraceContext()))
+	span.SetTag("region_id", region.GetID())
+	span.SetTag("store_id", region.GetLeader().GetStoreId())
+	span.SetTag("step", step.String())
+	oc.SendScheduleCommand(region, step)
+	span.Finish()
+}
+
 // SendScheduleCommand sends a command to the region.
 func (oc *OperatorController) SendScheduleCommand(region *core.RegionInfo, step OperatorStep) {
 	log.Infof("[region %v] send schedule command: %s", region.GetID(), step)
@@ -248,17 +581,146 @@ func (oc *OperatorController) SendScheduleCommand(region *core.RegionInfo, step
 		}
 		oc.hbStreams.SendMsg(region, cmd)
 	case SplitRegion:
+		if len(st.SplitKeys) > 0 {
+			// The leader store gets it by GetSplitCommands.
+			return
+		}
 		cmd := &pdpb.RegionHeartbeatResponse{
 			SplitRegion: &pdpb.SplitRegion{
 				Policy: st.Policy,
 			},
 		}
 		oc.hbStreams.SendMsg(region, cmd)
+	case SwitchToWitness, SwitchToVoter:
+		// The store gets it by GetWitnessCommands.
+	case DemotePromote:
+		// The leader store gets it by GetConfChangeCommands.
 	default:
 		log.Errorf("unknown operatorStep: %v", step)
 	}
 }
 
+// GetSplitCommands returns the splits at keys of the running operators, whose
+// regions are led by the store.
+func (oc *OperatorController) GetSplitCommands(storeID uint64) []*splitpb.SplitCommand {
+	var steps []SplitRegion
+	var regionIDs []uint64
+	oc.RLock()
+	for id, op := range oc.operators {
+		if step, ok := op.Step(int(atomic.LoadInt32(&op.currentStep))).(SplitRegion); ok && len(step.SplitKeys) > 0 {
+			steps = append(steps, step)
+			regionIDs = append(regionIDs, id)
+		}
+	}
+	oc.RUnlock()
+
+	var cmds []*splitpb.SplitCommand
+	for i, step := range steps {
+		region := oc.cluster.GetRegion(regionIDs[i])
+		if region == nil || region.GetLeader().GetStoreId() != storeID || step.IsFinish(region) {
+			continue
+		}
+		cmds = append(cmds, &splitpb.SplitCommand{
+			RegionId:    region.GetID(),
+			RegionEpoch: region.GetRegionEpoch(),
+			SplitKeys:   step.SplitKeys,
+		})
+	}
+	sort.Slice(cmds, func(i, j int) bool { return cmds[i].GetRegionId() < cmds[j].GetRegionId() })
+	return cmds
+}
+
+// GetConfChangeCommands returns the joint consensus conf changes of the running
+// operators, whose regions are led by the store.
+func (oc *OperatorController) GetConfChangeCommands(storeID uint64) []*confchangepb.ConfChangeCommand {
+	var steps []DemotePromote
+	var regionIDs []uint64
+	oc.RLock()
+	for id, op := range oc.operators {
+		if step, ok := op.Step(int(atomic.LoadInt32(&op.currentStep))).(DemotePromote); ok {
+			steps = append(steps, step)
+			regionIDs = append(regionIDs, id)
+		}
+	}
+	oc.RUnlock()
+
+	var cmds []*confchangepb.ConfChangeCommand
+	for i, step := range steps {
+		region := oc.cluster.GetRegion(regionIDs[i])
+		if region == nil || region.GetLeader().GetStoreId() != storeID || step.IsFinish(region) {
+			continue
+		}
+		cmds = append(cmds, &confchangepb.ConfChangeCommand{
+			RegionId:    region.GetID(),
+			RegionEpoch: region.GetRegionEpoch(),
+			Changes: []*pdpb.ChangePeer{
+				{
+					ChangeType: eraftpb.ConfChangeType_AddLearnerNode,
+					Peer: &metapb.Peer{
+						Id:        step.FromPeerID,
+						StoreId:   step.FromStore,
+						IsLearner: true,
+					},
+				},
+				{
+					// reuse AddNode type
+					ChangeType: eraftpb.ConfChangeType_AddNode,
+					Peer: &metapb.Peer{
+						Id:      step.ToPeerID,
+						StoreId: step.ToStore,
+					},
+				},
+			},
+		})
+	}
+	sort.Slice(cmds, func(i, j int) bool { return cmds[i].GetRegionId() < cmds[j].GetRegionId() })
+	return cmds
+}
+
+// GetWitnessCommands returns the witness switches of the running operators,
+// whose peers are on the store.
+func (oc *OperatorController) GetWitnessCommands(storeID uint64) []*witnesspb.WitnessCommand {
+	var steps []OperatorStep
+	var regionIDs []uint64
+	oc.RLock()
+	for id, op := range oc.operators {
+		switch step := op.Step(int(atomic.LoadInt32(&op.currentStep))).(type) {
+		case SwitchToWitness:
+			if step.StoreID == storeID {
+				steps = append(steps, step)
+				regionIDs = append(regionIDs, id)
+			}
+		case SwitchToVoter:
+			if step.StoreID == storeID {
+				steps = append(steps, step)
+				regionIDs = append(regionIDs, id)
+			}
+		}
+	}
+	oc.RUnlock()
+
+	var cmds []*witnesspb.WitnessCommand
+	for i, step := range steps {
+		region := oc.cluster.GetRegion(regionIDs[i])
+		if region == nil || step.IsFinish(region) {
+			continue
+		}
+		cmd := &witnesspb.WitnessCommand{
+			RegionId:    region.GetID(),
+			RegionEpoch: region.GetRegionEpoch(),
+		}
+		switch st := step.(type) {
+		case SwitchToWitness:
+			cmd.PeerId, cmd.Witness = st.PeerID, true
+		case SwitchToVoter:
+			cmd.PeerId = st.PeerID
+		}
+		cmds = append(cmds, cmd)
+	}
+	sort.Slice(cmds, func(i, j int) bool { return cmds[i].GetRegionId() < cmds[j].GetRegionId() })
+	return cmds
+}
+
 func (oc *OperatorController) pushHistory(op *Operator) {
 	oc.Lock()
 	defer oc.Unlock()
@@ -277,6 +739,36 @@ func (oc *OperatorController) PruneHistory() {
 		oc.histories.Remove(p)
 		p = prev
 	}
+	for peerID, t := range oc.orphanLearners {
+		if time.Since(t) > orphanLearnerKeepTime {
+			delete(oc.orphanLearners, peerID)
+		}
+	}
+}
+
+// recordOrphanLearners records the learners added by the timeout operator,
+// which are still not promoted.
+func (oc *OperatorController) recordOrphanLearners(op *Operator, region *core.RegionInfo) {
+	oc.Lock()
+	defer oc.Unlock()
+	for _, step := range op.steps {
+		al, ok := step.(AddLearner)
+		if !ok {
+			continue
+		}
+		if p := region.GetStoreLearner(al.ToStore); p != nil && p.GetId() == al.PeerID {
+			log.Infof("[region %v] learner %d is left by timeout operator", region.GetID(), al.PeerID)
+			oc.orphanLearners[al.PeerID] = time.Now()
+		}
+	}
+}
+
+// IsOrphanLearner returns true if the learner is left by a timeout operator.
+func (oc *OperatorController) IsOrphanLearner(peerID uint64) bool {
+	oc.RLock()
+	defer oc.RUnlock()
+	_, ok := oc.orphanLearners[peerID]
+	return ok
 }
 
 // GetHistory gets operators' history.
@@ -299,9 +791,44 @@ func (oc *OperatorController) updateCounts(operators map[uint64]*Operator) {
 	for k := range oc.counts {
 		delete(oc.counts, k)
 	}
+	// Keep the namespaces to reset their metrics.
+	for _, counts := range oc.nsCounts {
+		for k := range counts {
+			delete(counts, k)
+		}
+	}
 	for _, op := range operators {
 		oc.counts[op.Kind()]++
+		counts, ok := oc.nsCounts[op.namespace]
+		if !ok {
+			counts = make(map[OperatorKind]uint64)
+			oc.nsCounts[op.namespace] = counts
+		}
+		counts[op.Kind()]++
+	}
+	for ns := range oc.nsCounts {
+		for limitType, kind := range namespaceLimitTypes {
+			namespaceOperatorGauge.WithLabelValues(ns, limitType).Set(float64(oc.namespaceCountLocked(ns, kind)))
+		}
+	}
+}
+
+func (oc *OperatorController) namespaceCountLocked(ns string, mask OperatorKind) uint64 {
+	var total uint64
+	for k, count := range oc.nsCounts[ns] {
+		if k&mask != 0 {
+			total += count
+		}
 	}
+	return total
+}
+
+// OperatorNamespaceCount gets the count of operators in the namespace filtered
+// by mask.
+func (oc *OperatorController) OperatorNamespaceCount(ns string, mask OperatorKind) uint64 {
+	oc.RLock()
+	defer oc.RUnlock()
+	return oc.namespaceCountLocked(ns, mask)
 }
 
 // OperatorCount gets the count of operators filtered by mask.
@@ -20,6 +20,7 @@ import (
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/pd/server/core"
 )
 
@@ -110,6 +111,69 @@ func (s *testOperatorSuite) TestOperator(c *C) {
 	c.Assert(op.IsTimeout(), IsTrue)
 }
 
+func (s *testOperatorSuite) TestSnapshotTimeout(c *C) {
+	steps := []OperatorStep{
+		AddLearner{ToStore: 3, PeerID: 3},
+		PromoteLearner{ToStore: 3, PeerID: 3},
+		RemovePeer{FromStore: 2},
+	}
+	op := s.newTestOperator(1, OpRegion, steps...)
+	c.Assert(op.Timeout(), Equals, RegionOperatorWaitTime)
+	// 10GiB at 10MiB/s.
+	op.SetSnapshotSpeed(10*1024, 10*(1<<20))
+	c.Assert(op.Timeout(), Equals, RegionOperatorWaitTime+1024*time.Second)
+	op.createTime = op.createTime.Add(-RegionOperatorWaitTime - time.Second)
+	c.Assert(op.IsTimeout(), IsFalse)
+	op.createTime = op.createTime.Add(-1024 * time.Second)
+	c.Assert(op.IsTimeout(), IsTrue)
+
+	// Transferring leader does not send snapshots.
+	op = s.newTestOperator(1, OpLeader, TransferLeader{FromStore: 2, ToStore: 1})
+	op.SetSnapshotSpeed(10*1024, 10*(1<<20))
+	c.Assert(op.Timeout(), Equals, LeaderOperatorWaitTime)
+}
+
+func (s *testOperatorSuite) TestOrphanLearner(c *C) {
+	tc := NewMockCluster(NewMockSchedulerOptions())
+	tc.AddRegionStore(1, 1)
+	tc.AddRegionStore(2, 1)
+	tc.AddRegionStore(3, 1)
+	tc.AddRegionStore(4, 0)
+	tc.AddLeaderRegion(1, 1, 2, 3)
+	oc := NewOperatorController(tc, NewMockHeartbeatStreams(tc.ID))
+	rc := NewReplicaChecker(tc, nil)
+	rc.SetOperatorController(oc)
+
+	op := NewOperator("test", 1, tc.GetRegion(1).GetRegionEpoch(), OpRegion,
+		AddLearner{ToStore: 4, PeerID: 100},
+		PromoteLearner{ToStore: 4, PeerID: 100},
+		RemovePeer{FromStore: 3},
+	)
+	c.Assert(oc.AddOperator(op), IsTrue)
+	learner := &metapb.Peer{Id: 100, StoreId: 4, IsLearner: true}
+	region := tc.GetRegion(1).Clone(core.WithAddPeer(learner), core.WithPendingPeers([]*metapb.Peer{learner}))
+	tc.PutRegion(region)
+	oc.Dispatch(region)
+	c.Assert(oc.GetOperator(1), Equals, op)
+	c.Assert(oc.IsOrphanLearner(100), IsFalse)
+	// The pending learner is not removed while it is not orphan.
+	c.Assert(rc.Check(region), IsNil)
+
+	// The learner is left after the operator is timeout.
+	op.createTime = op.createTime.Add(-op.Timeout() - time.Second)
+	oc.Dispatch(region)
+	c.Assert(oc.GetOperator(1), IsNil)
+	c.Assert(oc.IsOrphanLearner(100), IsTrue)
+	cleanup := rc.Check(region)
+	c.Assert(cleanup, NotNil)
+	c.Assert(cleanup.Desc(), Equals, "removeOrphanLearner")
+	s.checkSteps(c, cleanup, []OperatorStep{RemovePeer{FromStore: 4}})
+
+	oc.orphanLearners[100] = time.Now().Add(-orphanLearnerKeepTime - time.Second)
+	oc.PruneHistory()
+	c.Assert(oc.IsOrphanLearner(100), IsFalse)
+}
+
 func (s *testOperatorSuite) TestInfluence(c *C) {
 	region := s.newTestRegion(1, 1, [2]uint64{1, 1}, [2]uint64{2, 2})
 	opInfluence := OpInfluence{storesInfluence: make(map[uint64]*StoreInfluence)}
@@ -193,3 +257,216 @@ func (s *testOperatorSuite) TestOperatorKind(c *C) {
 	_, err = ParseOperatorKind("foobar")
 	c.Assert(err, NotNil)
 }
+
+func (s *testOperatorSuite) TestAddLearner(c *C) {
+	region := s.newTestRegion(1, 1, [2]uint64{1, 1}, [2]uint64{2, 2})
+	step := AddLearner{ToStore: 3, PeerID: 3}
+	c.Assert(step.IsFinish(region), IsFalse)
+
+	learner := &metapb.Peer{Id: 3, StoreId: 3, IsLearner: true}
+	region = region.Clone(core.WithAddPeer(learner), core.WithPendingPeers([]*metapb.Peer{learner}))
+	c.Assert(step.IsFinish(region), IsFalse)
+	region = region.Clone(core.WithPendingPeers(nil), core.WithDownPeers([]*pdpb.PeerStats{{Peer: learner}}))
+	c.Assert(step.IsFinish(region), IsFalse)
+	region = region.Clone(core.WithDownPeers(nil))
+	c.Assert(step.IsFinish(region), IsTrue)
+}
+
+func (s *testOperatorSuite) TestJointConsensus(c *C) {
+	opt := NewMockSchedulerOptions()
+	tc := NewMockCluster(opt)
+	for i := uint64(1); i <= 4; i++ {
+		tc.AddRegionStore(i, 1)
+	}
+	tc.AddLeaderRegion(1, 1, 2, 3)
+	region := tc.GetRegion(1)
+	oldPeer := region.GetStorePeer(1)
+
+	// Each change takes a separate conf change by default.
+	op := CreateMovePeerOperator("test", tc, region, OpReplica, 1, 4, 10)
+	c.Assert(op.Len(), Equals, 4)
+	c.Assert(op.Step(1), Equals, PromoteLearner{ToStore: 4, PeerID: 10})
+
+	// The leader is transferred before the voter is demoted.
+	opt.EnableJointConsensus = true
+	op = CreateMovePeerOperator("test", tc, region, OpReplica, 1, 4, 10)
+	step := DemotePromote{FromStore: 1, FromPeerID: oldPeer.GetId(), ToStore: 4, ToPeerID: 10}
+	c.Assert(op.Len(), Equals, 4)
+	c.Assert(op.Step(0), Equals, AddLearner{ToStore: 4, PeerID: 10})
+	c.Assert(op.Step(1), FitsTypeOf, TransferLeader{})
+	c.Assert(op.Step(2), Equals, step)
+	c.Assert(op.Step(3), Equals, RemovePeer{FromStore: 1})
+
+	oc := NewOperatorController(tc, NewMockHeartbeatStreams(tc.ID))
+	c.Assert(oc.AddOperator(op), IsTrue)
+	learner := &metapb.Peer{Id: 10, StoreId: 4, IsLearner: true}
+	region = region.Clone(core.WithAddPeer(learner), core.WithLeader(region.GetStorePeer(op.Step(1).(TransferLeader).ToStore)))
+	tc.PutRegion(region)
+	c.Assert(op.Check(region), Equals, step)
+
+	// The conf change is sent to the leader store.
+	c.Assert(oc.GetConfChangeCommands(1), HasLen, 0)
+	cmds := oc.GetConfChangeCommands(region.GetLeader().GetStoreId())
+	c.Assert(cmds, HasLen, 1)
+	c.Assert(cmds[0].GetRegionEpoch(), DeepEquals, region.GetRegionEpoch())
+	c.Assert(cmds[0].GetChanges(), HasLen, 2)
+	c.Assert(cmds[0].GetChanges()[0].GetPeer(), DeepEquals, &metapb.Peer{Id: oldPeer.GetId(), StoreId: 1, IsLearner: true})
+	c.Assert(cmds[0].GetChanges()[1].GetPeer(), DeepEquals, &metapb.Peer{Id: 10, StoreId: 4})
+
+	// The demoted learner is removed at last.
+	region = region.Clone(core.WithRemoveStorePeer(1), core.WithRemoveStorePeer(4),
+		core.WithAddPeer(&metapb.Peer{Id: oldPeer.GetId(), StoreId: 1, IsLearner: true}),
+		core.WithAddPeer(&metapb.Peer{Id: 10, StoreId: 4}))
+	tc.PutRegion(region)
+	c.Assert(op.Check(region), Equals, RemovePeer{FromStore: 1})
+	c.Assert(oc.GetConfChangeCommands(region.GetLeader().GetStoreId()), HasLen, 0)
+}
+
+func (s *testOperatorSuite) TestOperatorCodec(c *C) {
+	steps := []OperatorStep{
+		AddLearner{ToStore: 3, PeerID: 3},
+		PromoteLearner{ToStore: 3, PeerID: 3},
+		DemotePromote{FromStore: 2, FromPeerID: 2, ToStore: 3, ToPeerID: 3},
+		TransferLeader{FromStore: 1, ToStore: 3},
+		RemovePeer{FromStore: 1},
+		MergeRegion{FromRegion: &metapb.Region{Id: 1}, ToRegion: &metapb.Region{Id: 2}, IsPassive: true},
+		SplitRegion{StartKey: []byte("a"), EndKey: []byte("b"), Policy: pdpb.CheckPolicy_APPROXIMATE},
+		SplitRegion{StartKey: []byte("a"), EndKey: []byte("b"), Policy: pdpb.CheckPolicy_APPROXIMATE, SplitKeys: [][]byte{[]byte("aa")}},
+	}
+	op := NewOperator("test", 1, &metapb.RegionEpoch{ConfVer: 2, Version: 3}, OpRegion|OpLeader, steps...)
+	op.SetPriorityLevel(core.HighPriority)
+	atomic.StoreInt32(&op.currentStep, 2)
+
+	data, err := EncodeOperator(op)
+	c.Assert(err, IsNil)
+	res, err := DecodeOperator(data)
+	c.Assert(err, IsNil)
+	c.Assert(res.Desc(), Equals, op.Desc())
+	c.Assert(res.RegionID(), Equals, op.RegionID())
+	c.Assert(res.RegionEpoch(), DeepEquals, op.RegionEpoch())
+	c.Assert(res.Kind(), Equals, op.Kind())
+	c.Assert(res.GetPriorityLevel(), Equals, core.HighPriority)
+	c.Assert(res.currentStep, Equals, int32(2))
+	c.Assert(res.createTime.Equal(op.createTime), IsTrue)
+	c.Assert(res.Len(), Equals, len(steps))
+	for i := range steps {
+		c.Assert(res.Step(i), DeepEquals, steps[i])
+	}
+
+	_, err = DecodeOperator([]byte(`{"r":1,"s":[{"t":"unknown"}]}`))
+	c.Assert(err, NotNil)
+}
+
+// blockingStorage blocks saving the operators until it is released.
+type blockingStorage struct {
+	OperatorStorage
+	release chan struct{}
+}
+
+func (s *blockingStorage) SaveOperator(regionID uint64, data []byte) error {
+	<-s.release
+	return s.OperatorStorage.SaveOperator(regionID, data)
+}
+
+func (s *testOperatorSuite) TestPersistOperatorsAsync(c *C) {
+	kv := core.NewKV(core.NewMemoryKV())
+	tc := NewMockCluster(NewMockSchedulerOptions())
+	tc.AddLeaderStore(1, 1)
+	tc.AddLeaderStore(2, 1)
+	tc.AddLeaderRegion(1, 1, 2)
+	tc.AddLeaderRegion(2, 1, 2)
+
+	storage := &blockingStorage{OperatorStorage: kv, release: make(chan struct{})}
+	oc := NewOperatorController(tc, NewMockHeartbeatStreams(tc.ID))
+	oc.SetStorage(storage)
+	// The operators are added while the storage is blocked.
+	c.Assert(oc.AddOperator(NewOperator("test", 1, &metapb.RegionEpoch{}, OpLeader, TransferLeader{FromStore: 1, ToStore: 2})), IsTrue)
+	c.Assert(oc.AddOperator(NewOperator("test", 2, &metapb.RegionEpoch{}, OpLeader, TransferLeader{FromStore: 1, ToStore: 2})), IsTrue)
+	c.Assert(oc.GetOperator(1), NotNil)
+	c.Assert(oc.GetOperator(2), NotNil)
+
+	close(storage.release)
+	oc.persistWg.Wait()
+	var regionIDs []uint64
+	c.Assert(kv.LoadOperators(func(data []byte) (uint64, error) {
+		op, err := DecodeOperator(data)
+		c.Assert(err, IsNil)
+		regionIDs = append(regionIDs, op.RegionID())
+		return op.RegionID(), nil
+	}), IsNil)
+	c.Assert(regionIDs, DeepEquals, []uint64{1, 2})
+}
+
+func (s *testOperatorSuite) TestPersistOperators(c *C) {
+	kv := core.NewKV(core.NewMemoryKV())
+	tc := NewMockCluster(NewMockSchedulerOptions())
+	tc.AddLeaderStore(1, 1)
+	tc.AddLeaderStore(2, 1)
+	tc.AddLeaderStore(3, 0)
+	tc.AddLeaderRegion(1, 1, 2)
+	tc.AddLeaderRegion(2, 1, 2)
+	tc.AddLeaderRegion(3, 1, 2)
+
+	oc := NewOperatorController(tc, NewMockHeartbeatStreams(tc.ID))
+	oc.SetStorage(kv)
+	newOp := func(regionID uint64) *Operator {
+		return NewOperator("test", regionID, &metapb.RegionEpoch{}, OpRegion,
+			AddLearner{ToStore: 3, PeerID: 100 + regionID},
+			PromoteLearner{ToStore: 3, PeerID: 100 + regionID},
+			RemovePeer{FromStore: 2},
+		)
+	}
+	op1, op2, op3 := newOp(1), newOp(2), newOp(3)
+	// op2 is created long ago and will be canceled.
+	op2.createTime = op2.createTime.Add(-RegionOperatorWaitTime - time.Second)
+	c.Assert(oc.AddOperator(op1, op2, op3), IsTrue)
+
+	// The learners are added, and the progress of op1 is persisted.
+	for _, id := range []uint64{1, 2} {
+		region := tc.GetRegion(id).Clone(core.WithAddPeer(&metapb.Peer{Id: 100 + id, StoreId: 3, IsLearner: true}))
+		tc.PutRegion(region)
+	}
+	oc.Dispatch(tc.GetRegion(1))
+	oc.persistWg.Wait()
+	// Region 3 is merged.
+	tc.Regions.RemoveRegion(tc.GetRegion(3))
+
+	// The new leader takes over the operators.
+	oc = NewOperatorController(tc, NewMockHeartbeatStreams(tc.ID))
+	oc.SetStorage(kv)
+	c.Assert(oc.LoadOperators(), IsNil)
+
+	op := oc.GetOperator(1)
+	c.Assert(op, NotNil)
+	c.Assert(op.Desc(), Equals, "test")
+	c.Assert(op.Check(tc.GetRegion(1)), Equals, PromoteLearner{ToStore: 3, PeerID: 101})
+
+	op = oc.GetOperator(2)
+	c.Assert(op, NotNil)
+	c.Assert(op.Desc(), Equals, "cleanup-operator")
+	c.Assert(op.GetPriorityLevel(), Equals, core.HighPriority)
+	s.checkSteps(c, op, []OperatorStep{RemovePeer{FromStore: 3}})
+
+	c.Assert(oc.GetOperator(3), IsNil)
+	oc.persistWg.Wait()
+	var regionIDs []uint64
+	c.Assert(kv.LoadOperators(func(data []byte) (uint64, error) {
+		op, err := DecodeOperator(data)
+		c.Assert(err, IsNil)
+		regionIDs = append(regionIDs, op.RegionID())
+		return op.RegionID(), nil
+	}), IsNil)
+	c.Assert(regionIDs, DeepEquals, []uint64{1, 2})
+
+	// Finished operators are removed from storage.
+	oc.RemoveOperator(oc.GetOperator(2))
+	oc.persistWg.Wait()
+	regionIDs = regionIDs[:0]
+	c.Assert(kv.LoadOperators(func(data []byte) (uint64, error) {
+		op, err := DecodeOperator(data)
+		c.Assert(err, IsNil)
+		regionIDs = append(regionIDs, op.RegionID())
+		return op.RegionID(), nil
+	}), IsNil)
+	c.Assert(regionIDs, DeepEquals, []uint64{1})
+}
@@ -29,16 +29,21 @@ type Options interface {
 	GetRegionScheduleLimit() uint64
 	GetReplicaScheduleLimit() uint64
 	GetMergeScheduleLimit() uint64
+	GetLeaderScheduleBatch() uint64
 
 	GetMaxSnapshotCount() uint64
 	GetMaxPendingPeerCount() uint64
+	GetMaxPendingCompactionBytes() uint64
+	GetMinSnapshotSpeed() uint64
 	GetMaxStoreDownTime() time.Duration
 	GetMaxMergeRegionSize() uint64
 	GetMaxMergeRegionKeys() uint64
 	GetSplitMergeInterval() time.Duration
+	GetMergeProtectionInterval(startKey, endKey []byte) time.Duration
 
 	GetMaxReplicas() int
 	GetLocationLabels() []string
+	GetIsolationLevel() string
 
 	GetHotRegionLowThreshold() int
 	GetTolerantSizeRatio() float64
@@ -46,6 +51,7 @@ type Options interface {
 	GetHighSpaceRatio() float64
 
 	IsRaftLearnerEnabled() bool
+	IsJointConsensusEnabled() bool
 
 	IsRemoveDownReplicaEnabled() bool
 	IsReplaceOfflineReplicaEnabled() bool
@@ -70,4 +76,8 @@ const (
 	// RejectLeader is the label property type that sugguests a store should not
 	// have any region leaders.
 	RejectLeader = "reject-leader"
+	// WitnessReplica is the label property type that the voters on a store
+	// should be witnesses, which keep only the Raft log. The stores reject
+	// leaders as well.
+	WitnessReplica = "witness"
 )
@@ -0,0 +1,159 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"plugin"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// A scheduler plugin is a Go plugin built with `go build -buildmode=plugin`
+// against the same PD source. Like the built-in schedulers, it registers its
+// schedulers in the init() func:
+//
+//	func init() {
+//		schedule.RegisterSchedulerWithArgs("my-scheduler", func(opController *schedule.OperatorController, args []string) (schedule.Scheduler, error) {
+//			return newMyScheduler(opController, args)
+//		}, []schedule.SchedulerArg{{Name: "store-id", Description: "the store to schedule"}})
+//	}
+//
+// After the plugin is loaded, its schedulers can be added by type like the
+// built-in ones. Go does not support unloading a plugin, so unloading only
+// unregisters its scheduler types, and loading it again registers them back.
+
+// openPlugin opens the Go plugin. It is replaced in tests.
+var openPlugin = func(path string) error {
+	_, err := plugin.Open(path)
+	return errors.WithStack(err)
+}
+
+// pluginMu serializes the plugin loading, so that loadingPlugin is only
+// accessed by the goroutine running the init() func of the plugin.
+var (
+	pluginMu      sync.Mutex
+	loadingPlugin *schedulerPlugin
+	plugins       = make(map[string]*schedulerPlugin)
+)
+
+// schedulerPlugin is the schedulers registered by a plugin.
+type schedulerPlugin struct {
+	path          string
+	registrations map[string]*schedulerRegistration
+	err           error
+	// loaded tells whether the schedulers are registered.
+	loaded bool
+}
+
+func (p *schedulerPlugin) register(name string, r *schedulerRegistration) {
+	if _, ok := p.registrations[name]; ok {
+		p.err = errors.Errorf("duplicated scheduler name: %v", name)
+		return
+	}
+	r.plugin = p.path
+	p.registrations[name] = r
+}
+
+func (p *schedulerPlugin) types() []string {
+	types := make([]string, 0, len(p.registrations))
+	for name := range p.registrations {
+		types = append(types, name)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// LoadSchedulerPlugin loads the scheduler plugin and registers its schedulers.
+// It returns the registered scheduler types.
+func LoadSchedulerPlugin(path string) ([]string, error) {
+	pluginMu.Lock()
+	defer pluginMu.Unlock()
+
+	p, ok := plugins[path]
+	if !ok {
+		p = &schedulerPlugin{
+			path:          path,
+			registrations: make(map[string]*schedulerRegistration),
+		}
+		loadingPlugin = p
+		err := openPlugin(path)
+		loadingPlugin = nil
+		if err == nil {
+			err = p.err
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(p.registrations) == 0 {
+			return nil, errors.Errorf("plugin %s registers no scheduler", path)
+		}
+		// The init() func of a plugin only runs once, keep the registrations
+		// for loading it again.
+		plugins[path] = p
+	}
+
+	schedulerMu.Lock()
+	defer schedulerMu.Unlock()
+	for name, r := range p.registrations {
+		if registered, ok := schedulerMap[name]; ok && registered != r {
+			return nil, errors.Errorf("duplicated scheduler name: %v", name)
+		}
+	}
+	for name, r := range p.registrations {
+		schedulerMap[name] = r
+	}
+	p.loaded = true
+	log.Infof("scheduler plugin %s is loaded with %v", path, p.types())
+	return p.types(), nil
+}
+
+// UnloadSchedulerPlugin unregisters the schedulers of the plugin. It returns
+// the unregistered scheduler types.
+func UnloadSchedulerPlugin(path string) ([]string, error) {
+	pluginMu.Lock()
+	defer pluginMu.Unlock()
+
+	p, ok := plugins[path]
+	if !ok || !p.loaded {
+		return nil, errors.Errorf("plugin %s is not loaded", path)
+	}
+	schedulerMu.Lock()
+	defer schedulerMu.Unlock()
+	for name, r := range p.registrations {
+		if schedulerMap[name] == r {
+			delete(schedulerMap, name)
+		}
+	}
+	p.loaded = false
+	log.Infof("scheduler plugin %s is unloaded", path)
+	return p.types(), nil
+}
+
+// GetSchedulerPlugins returns the paths of the loaded scheduler plugins.
+func GetSchedulerPlugins() []string {
+	pluginMu.Lock()
+	defer pluginMu.Unlock()
+
+	paths := make([]string, 0, len(plugins))
+	for path, p := range plugins {
+		if p.loaded {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,102 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pkg/errors"
+)
+
+var _ = Suite(&testPluginSuite{})
+
+type testPluginSuite struct{}
+
+type testPluginScheduler struct {
+	Scheduler
+}
+
+func (s *testPluginSuite) TestLoadAndUnload(c *C) {
+	opened := 0
+	defer func(fn func(string) error) { openPlugin = fn }(openPlugin)
+	openPlugin = func(path string) error {
+		opened++
+		switch path {
+		case "test-plugin.so":
+			RegisterSchedulerWithArgs("test-plugin-scheduler", func(opController *OperatorController, args []string) (Scheduler, error) {
+				return &testPluginScheduler{}, nil
+			}, []SchedulerArg{{Name: "store-id", Description: "the store to schedule"}})
+		case "test-duplicated.so":
+			RegisterScheduler("test-duplicated-scheduler", nil)
+			RegisterScheduler("test-duplicated-scheduler", nil)
+		case "test-builtin.so":
+			RegisterScheduler("test-plugin-scheduler", nil)
+		case "test-empty.so":
+		default:
+			return errors.New("plugin not found")
+		}
+		return nil
+	}
+
+	_, err := LoadSchedulerPlugin("test-not-found.so")
+	c.Assert(err, NotNil)
+	_, err = LoadSchedulerPlugin("test-duplicated.so")
+	c.Assert(err, NotNil)
+	_, err = LoadSchedulerPlugin("test-empty.so")
+	c.Assert(err, NotNil)
+	_, err = UnloadSchedulerPlugin("test-plugin.so")
+	c.Assert(err, NotNil)
+	c.Assert(GetSchedulerPlugins(), HasLen, 0)
+
+	types, err := LoadSchedulerPlugin("test-plugin.so")
+	c.Assert(err, IsNil)
+	c.Assert(types, DeepEquals, []string{"test-plugin-scheduler"})
+	c.Assert(GetSchedulerPlugins(), DeepEquals, []string{"test-plugin.so"})
+	c.Assert(s.findType("test-plugin-scheduler"), DeepEquals, &SchedulerType{
+		Type:   "test-plugin-scheduler",
+		Args:   []SchedulerArg{{Name: "store-id", Description: "the store to schedule"}},
+		Plugin: "test-plugin.so",
+	})
+	_, err = CreateScheduler("test-plugin-scheduler", nil)
+	c.Assert(err, IsNil)
+	// The scheduler types can't be overridden.
+	_, err = LoadSchedulerPlugin("test-builtin.so")
+	c.Assert(err, NotNil)
+
+	types, err = UnloadSchedulerPlugin("test-plugin.so")
+	c.Assert(err, IsNil)
+	c.Assert(types, DeepEquals, []string{"test-plugin-scheduler"})
+	c.Assert(GetSchedulerPlugins(), HasLen, 0)
+	c.Assert(s.findType("test-plugin-scheduler"), IsNil)
+	_, err = CreateScheduler("test-plugin-scheduler", nil)
+	c.Assert(err, NotNil)
+
+	// Loading again registers the schedulers without opening the plugin.
+	opened = 0
+	_, err = LoadSchedulerPlugin("test-plugin.so")
+	c.Assert(err, IsNil)
+	c.Assert(opened, Equals, 0)
+	_, err = CreateScheduler("test-plugin-scheduler", nil)
+	c.Assert(err, IsNil)
+	_, err = UnloadSchedulerPlugin("test-plugin.so")
+	c.Assert(err, IsNil)
+}
+
+func (s *testPluginSuite) findType(name string) *SchedulerType {
+	for _, t := range GetSchedulerTypes() {
+		if t.Type == name {
+			return &t
+		}
+	}
+	return nil
+}
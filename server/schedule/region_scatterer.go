@@ -118,7 +118,9 @@ func (r *RegionScatterer) scatterRegion(region *core.RegionInfo) *Operator {
 		op := CreateMovePeerOperator("scatter-peer", r.cluster, region, OpAdmin,
 			peer.GetStoreId(), newPeer.GetStoreId(), newPeer.GetId())
 		steps = append(steps, op.steps...)
-		steps = append(steps, TransferLeader{ToStore: newPeer.GetStoreId()})
+		if !NewRejectLeaderFilter().FilterTarget(r.cluster, r.cluster.GetStore(newPeer.GetStoreId())) {
+			steps = append(steps, TransferLeader{ToStore: newPeer.GetStoreId()})
+		}
 		kind |= op.Kind()
 	}
 
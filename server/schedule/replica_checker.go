@@ -23,10 +23,14 @@ import (
 )
 
 // ReplicaChecker ensures region has the best replicas.
+// The voters on the stores with the witness label property are switched to
+// witnesses, which keep only the Raft log.
 type ReplicaChecker struct {
 	cluster    Cluster
 	classifier namespace.Classifier
 	filters    []Filter
+	// opController tells the learners left by timeout operators.
+	opController *OperatorController
 }
 
 // NewReplicaChecker creates a replica checker.
@@ -34,6 +38,7 @@ func NewReplicaChecker(cluster Cluster, classifier namespace.Classifier) *Replic
 	filters := []Filter{
 		NewHealthFilter(),
 		NewSnapshotCountFilter(),
+		NewPendingCompactionFilter(),
 	}
 
 	return &ReplicaChecker{
@@ -43,9 +48,20 @@ func NewReplicaChecker(cluster Cluster, classifier namespace.Classifier) *Replic
 	}
 }
 
+// SetOperatorController sets the controller of operators, so that the checker
+// removes the orphan learners left by the timeout operators.
+func (r *ReplicaChecker) SetOperatorController(oc *OperatorController) {
+	r.opController = oc
+}
+
 // Check verifies a region's replicas, creating an Operator if need.
 func (r *ReplicaChecker) Check(region *core.RegionInfo) *Operator {
 	checkerCounter.WithLabelValues("replica_checker", "check").Inc()
+	if op := r.checkLearnerPeer(region); op != nil {
+		checkerCounter.WithLabelValues("replica_checker", "new_operator").Inc()
+		op.SetPriorityLevel(core.HighPriority)
+		return op
+	}
 	if op := r.checkDownPeer(region); op != nil {
 		checkerCounter.WithLabelValues("replica_checker", "new_operator").Inc()
 		op.SetPriorityLevel(core.HighPriority)
@@ -92,6 +108,11 @@ func (r *ReplicaChecker) Check(region *core.RegionInfo) *Operator {
 		return CreateRemovePeerOperator("removeExtraReplica", r.cluster, OpReplica, region, oldPeer.GetStoreId())
 	}
 
+	if op := r.checkWitness(region); op != nil {
+		checkerCounter.WithLabelValues("replica_checker", "new_operator").Inc()
+		return op
+	}
+
 	return r.checkBestReplacement(region)
 }
 
@@ -102,6 +123,33 @@ func (r *ReplicaChecker) SelectBestReplacementStore(region *core.RegionInfo, old
 	return r.selectBestStoreToAddReplica(newRegion, filters...)
 }
 
+// selectPairedReplacementStore returns the replacement store paired with the
+// offline store of the old peer, if the paired store can accept the peer and
+// its distinct score is not lower than the best score. It returns 0 if the
+// peer should be moved to the best store.
+func (r *ReplicaChecker) selectPairedReplacementStore(region *core.RegionInfo, oldPeer *metapb.Peer, bestScore float64) uint64 {
+	store := r.cluster.GetStore(oldPeer.GetStoreId())
+	if store == nil || !store.IsOffline() || store.GetReplacement() == nil {
+		return 0
+	}
+	paired := r.cluster.GetStore(store.GetReplacement().StoreID)
+	if paired == nil {
+		checkerCounter.WithLabelValues("replica_checker", "no_paired_store").Inc()
+		return 0
+	}
+	if FilterTarget(r.cluster, paired, r.addReplicaFilters(region, NewStorageThresholdFilter())) {
+		checkerCounter.WithLabelValues("replica_checker", "paired_store_filtered").Inc()
+		return 0
+	}
+	newRegion := region.Clone(core.WithRemoveStorePeer(oldPeer.GetStoreId()))
+	if DistinctScore(r.cluster.GetLocationLabels(), r.cluster.GetRegionStores(newRegion), paired) < bestScore {
+		checkerCounter.WithLabelValues("replica_checker", "paired_store_worse").Inc()
+		return 0
+	}
+	checkerCounter.WithLabelValues("replica_checker", "paired_store").Inc()
+	return paired.GetId()
+}
+
 // selectBestPeerToAddReplica returns a new peer that to be used to add a replica and distinct score.
 func (r *ReplicaChecker) selectBestPeerToAddReplica(region *core.RegionInfo, filters ...Filter) (*metapb.Peer, float64) {
 	storeID, score := r.selectBestStoreToAddReplica(region, filters...)
@@ -118,6 +166,18 @@ func (r *ReplicaChecker) selectBestPeerToAddReplica(region *core.RegionInfo, fil
 
 // selectBestStoreToAddReplica returns the store to add a replica.
 func (r *ReplicaChecker) selectBestStoreToAddReplica(region *core.RegionInfo, filters ...Filter) (uint64, float64) {
+	regionStores := r.cluster.GetRegionStores(region)
+	selector := NewReplicaSelector(regionStores, r.cluster.GetLocationLabels(), r.filters...)
+	target := selector.SelectTarget(r.cluster, r.cluster.GetStores(), r.addReplicaFilters(region, filters...)...)
+	if target == nil {
+		return 0, 0
+	}
+	return target.GetId(), DistinctScore(r.cluster.GetLocationLabels(), regionStores, target)
+}
+
+// addReplicaFilters appends the filters which the store to add a replica of
+// the region must pass.
+func (r *ReplicaChecker) addReplicaFilters(region *core.RegionInfo, filters ...Filter) []Filter {
 	// Add some must have filters.
 	newFilters := []Filter{
 		NewStateFilter(),
@@ -129,13 +189,7 @@ func (r *ReplicaChecker) selectBestStoreToAddReplica(region *core.RegionInfo, fi
 	if r.classifier != nil {
 		filters = append(filters, NewNamespaceFilter(r.classifier, r.classifier.GetRegionNamespace(region)))
 	}
-	regionStores := r.cluster.GetRegionStores(region)
-	selector := NewReplicaSelector(regionStores, r.cluster.GetLocationLabels(), r.filters...)
-	target := selector.SelectTarget(r.cluster, r.cluster.GetStores(), filters...)
-	if target == nil {
-		return 0, 0
-	}
-	return target.GetId(), DistinctScore(r.cluster.GetLocationLabels(), regionStores, target)
+	return filters
 }
 
 // selectWorstPeer returns the worst peer in the region.
@@ -177,6 +231,44 @@ func (r *ReplicaChecker) checkDownPeer(region *core.RegionInfo) *Operator {
 	return nil
 }
 
+// checkLearnerPeer removes the learner on an unhealthy store, it can not catch
+// up and be promoted, and it blocks the other replica checks. It also removes
+// the orphan learner left by a timeout operator, which is not promoted since
+// it has not caught up.
+func (r *ReplicaChecker) checkLearnerPeer(region *core.RegionInfo) *Operator {
+	for _, peer := range region.GetLearners() {
+		store := r.cluster.GetStore(peer.GetStoreId())
+		if store == nil {
+			log.Infof("lost the store %d, maybe you are recovering the PD cluster.", peer.GetStoreId())
+			return nil
+		}
+		if !isHealthyLearner(r.cluster, region, peer, store) {
+			return CreateRemovePeerOperator("removeUnhealthyLearner", r.cluster, OpReplica, region, peer.GetStoreId())
+		}
+		if r.opController != nil && r.opController.IsOrphanLearner(peer.GetId()) {
+			checkerCounter.WithLabelValues("replica_checker", "orphan_learner").Inc()
+			return CreateRemovePeerOperator("removeOrphanLearner", r.cluster, OpReplica, region, peer.GetStoreId())
+		}
+	}
+	return nil
+}
+
+// IsLearnerPromotable returns if the learner has caught up with the leader
+// and is healthy, so it can be promoted to a voter.
+func IsLearnerPromotable(cluster Cluster, region *core.RegionInfo, peer *metapb.Peer) bool {
+	if region.GetPendingLearner(peer.GetId()) != nil {
+		return false
+	}
+	store := cluster.GetStore(peer.GetStoreId())
+	return store != nil && isHealthyLearner(cluster, region, peer, store)
+}
+
+func isHealthyLearner(cluster Cluster, region *core.RegionInfo, peer *metapb.Peer, store *core.StoreInfo) bool {
+	return store.IsUp() &&
+		store.DownTime() < cluster.GetMaxStoreDownTime() &&
+		region.GetDownLearner(peer.GetId()) == nil
+}
+
 func (r *ReplicaChecker) checkOfflinePeer(region *core.RegionInfo) *Operator {
 	if !r.cluster.IsReplaceOfflineReplicaEnabled() {
 		return nil
@@ -232,6 +324,36 @@ func (r *ReplicaChecker) checkBestReplacement(region *core.RegionInfo) *Operator
 	return CreateMovePeerOperator("moveToBetterLocation", r.cluster, region, OpReplica, oldPeer.GetStoreId(), newPeer.GetStoreId(), newPeer.GetId())
 }
 
+// checkWitness switches the voters on the witness stores to witnesses, and the
+// witnesses on the other stores back to full voters. The witnesses are kept
+// fewer than half of the voters, so that the full voters are the majority.
+func (r *ReplicaChecker) checkWitness(region *core.RegionInfo) *Operator {
+	for _, peer := range region.GetWitnesses() {
+		if store := r.cluster.GetStore(peer.GetStoreId()); store != nil && !r.cluster.CheckLabelProperty(WitnessReplica, store.Labels) {
+			step := SwitchToVoter{StoreID: peer.GetStoreId(), PeerID: peer.GetId()}
+			return NewOperator("switchToVoter", region.GetID(), region.GetRegionEpoch(), OpReplica|OpRegion, step)
+		}
+	}
+
+	for _, peer := range region.GetVoters() {
+		// The leader is moved away from the witness store first.
+		if peer.GetId() == region.GetLeader().GetId() || region.GetWitness(peer.GetId()) != nil || region.GetPendingPeer(peer.GetId()) != nil {
+			continue
+		}
+		store := r.cluster.GetStore(peer.GetStoreId())
+		if store == nil || !r.cluster.CheckLabelProperty(WitnessReplica, store.Labels) {
+			continue
+		}
+		if 2*(len(region.GetWitnesses())+1) >= len(region.GetVoters()) {
+			checkerCounter.WithLabelValues("replica_checker", "too_many_witnesses").Inc()
+			return nil
+		}
+		step := SwitchToWitness{StoreID: peer.GetStoreId(), PeerID: peer.GetId()}
+		return NewOperator("switchToWitness", region.GetID(), region.GetRegionEpoch(), OpReplica|OpRegion, step)
+	}
+	return nil
+}
+
 func (r *ReplicaChecker) fixPeer(region *core.RegionInfo, peer *metapb.Peer, status string) *Operator {
 	removeExtra := fmt.Sprintf("removeExtra%sReplica", status)
 	// Check the number of replicas first.
@@ -248,11 +370,14 @@ func (r *ReplicaChecker) fixPeer(region *core.RegionInfo, peer *metapb.Peer, sta
 		return CreateRemovePeerOperator(removePending, r.cluster, OpReplica, region, peer.GetStoreId())
 	}
 
-	storeID, _ := r.SelectBestReplacementStore(region, peer, NewStorageThresholdFilter())
+	storeID, score := r.SelectBestReplacementStore(region, peer, NewStorageThresholdFilter())
 	if storeID == 0 {
 		log.Debugf("[region %d] no best store to add replica", region.GetID())
 		return nil
 	}
+	if pairedID := r.selectPairedReplacementStore(region, peer, score); pairedID != 0 {
+		storeID = pairedID
+	}
 	newPeer, err := r.cluster.AllocPeer(storeID)
 	if err != nil {
 		return nil
@@ -261,3 +386,80 @@ func (r *ReplicaChecker) fixPeer(region *core.RegionInfo, peer *metapb.Peer, sta
 	replace := fmt.Sprintf("replace%sReplica", status)
 	return CreateMovePeerOperator(replace, r.cluster, region, OpReplica, peer.GetStoreId(), newPeer.GetStoreId(), newPeer.GetId())
 }
+
+// RepairPriority is the urgency to repair the lost voters of a region.
+type RepairPriority int
+
+// The repair priorities, a higher one is more urgent.
+const (
+	// RepairNotNeeded means all the voters are healthy.
+	RepairNotNeeded RepairPriority = iota
+	// RepairNormal means some voters are lost, but the region survives the
+	// failure of any failure domain it still spans.
+	RepairNormal
+	// RepairAtRisk means the region loses its quorum if one more failure
+	// domain it spans fails.
+	RepairAtRisk
+	// RepairQuorumLost means the majority of voters are lost.
+	RepairQuorumLost
+)
+
+func (p RepairPriority) String() string {
+	switch p {
+	case RepairNotNeeded:
+		return "not-needed"
+	case RepairNormal:
+		return "normal"
+	case RepairAtRisk:
+		return "at-risk"
+	case RepairQuorumLost:
+		return "quorum-lost"
+	}
+	return "unknown"
+}
+
+// GetRepairPriority classifies how urgent it is to repair the region by the
+// failure domains its healthy voters still span. A failure domain is the top
+// level location label of the stores, or the store itself if it has no such
+// label.
+func (r *ReplicaChecker) GetRepairPriority(region *core.RegionInfo) RepairPriority {
+	voters := region.GetVoters()
+	domains := make(map[string]int)
+	var healthy int
+	for _, peer := range voters {
+		store := r.cluster.GetStore(peer.GetStoreId())
+		if !r.isHealthyVoter(region, peer, store) {
+			continue
+		}
+		healthy++
+		domains[r.getFailureDomain(store)]++
+	}
+	if healthy == len(voters) {
+		return RepairNotNeeded
+	}
+	quorum := len(voters)/2 + 1
+	if healthy < quorum {
+		return RepairQuorumLost
+	}
+	for _, count := range domains {
+		if healthy-count < quorum {
+			return RepairAtRisk
+		}
+	}
+	return RepairNormal
+}
+
+func (r *ReplicaChecker) isHealthyVoter(region *core.RegionInfo, peer *metapb.Peer, store *core.StoreInfo) bool {
+	return store != nil && !store.IsTombstone() &&
+		store.DownTime() < r.cluster.GetMaxStoreDownTime() &&
+		region.GetDownVoter(peer.GetId()) == nil
+}
+
+func (r *ReplicaChecker) getFailureDomain(store *core.StoreInfo) string {
+	if labels := r.cluster.GetLocationLabels(); len(labels) > 0 {
+		if value := store.GetLabelValue(labels[0]); value != "" {
+			return labels[0] + "=" + value
+		}
+	}
+	return fmt.Sprintf("store=%d", store.GetId())
+}
@@ -14,6 +14,8 @@
 package schedule
 
 import (
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/pingcap/kvproto/pkg/metapb"
@@ -67,25 +69,88 @@ type Scheduler interface {
 	IsScheduleAllowed(cluster Cluster) bool
 }
 
+// ConfigProvider is implemented by the schedulers whose config can be updated
+// while they are running.
+type ConfigProvider interface {
+	// GetConfig returns a copy of the config, which is encoded as JSON.
+	GetConfig() interface{}
+	// SetConfig updates the fields in the JSON data, the config is not changed
+	// if the data is invalid.
+	SetConfig(data []byte) error
+}
+
 // CreateSchedulerFunc is for creating scheudler.
 type CreateSchedulerFunc func(opController *OperatorController, args []string) (Scheduler, error)
 
-var schedulerMap = make(map[string]CreateSchedulerFunc)
+// SchedulerArg describes an argument of a scheduler, the arguments are passed
+// to CreateSchedulerFunc in order.
+type SchedulerArg struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Optional    bool   `json:"optional"`
+}
+
+// SchedulerType is a registered scheduler type.
+type SchedulerType struct {
+	Type string         `json:"type"`
+	Args []SchedulerArg `json:"args,omitempty"`
+	// Plugin is the path of the plugin which registers the scheduler, empty
+	// for built-in schedulers.
+	Plugin string `json:"plugin,omitempty"`
+}
+
+type schedulerRegistration struct {
+	createFn CreateSchedulerFunc
+	args     []SchedulerArg
+	plugin   string
+}
+
+var (
+	schedulerMu  sync.RWMutex
+	schedulerMap = make(map[string]*schedulerRegistration)
+)
 
 // RegisterScheduler binds a scheduler creator. It should be called in init()
 // func of a package.
 func RegisterScheduler(name string, createFn CreateSchedulerFunc) {
+	RegisterSchedulerWithArgs(name, createFn, nil)
+}
+
+// RegisterSchedulerWithArgs binds a scheduler creator with the schema of its
+// arguments. It should be called in init() func of a package.
+func RegisterSchedulerWithArgs(name string, createFn CreateSchedulerFunc, args []SchedulerArg) {
+	r := &schedulerRegistration{createFn: createFn, args: args}
+	if loadingPlugin != nil {
+		loadingPlugin.register(name, r)
+		return
+	}
+	schedulerMu.Lock()
+	defer schedulerMu.Unlock()
 	if _, ok := schedulerMap[name]; ok {
 		log.Fatalf("duplicated scheduler name: %v", name)
 	}
-	schedulerMap[name] = createFn
+	schedulerMap[name] = r
 }
 
 // CreateScheduler creates a scheduler with registered creator func.
 func CreateScheduler(name string, opController *OperatorController, args ...string) (Scheduler, error) {
-	fn, ok := schedulerMap[name]
+	schedulerMu.RLock()
+	r, ok := schedulerMap[name]
+	schedulerMu.RUnlock()
 	if !ok {
 		return nil, errors.Errorf("create func of %v is not registered", name)
 	}
-	return fn(opController, args)
+	return r.createFn(opController, args)
+}
+
+// GetSchedulerTypes returns all registered scheduler types.
+func GetSchedulerTypes() []SchedulerType {
+	schedulerMu.RLock()
+	defer schedulerMu.RUnlock()
+	types := make([]SchedulerType, 0, len(schedulerMap))
+	for name, r := range schedulerMap {
+		types = append(types, SchedulerType{Type: name, Args: r.args, Plugin: r.plugin})
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Type < types[j].Type })
+	return types
 }
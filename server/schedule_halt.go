@@ -0,0 +1,195 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	log "github.com/sirupsen/logrus"
+)
+
+// Reasons to halt the balance scheduling.
+const (
+	haltReasonLowSpace    = "low-space"
+	haltReasonVersionSkew = "version-skew"
+	haltReasonEtcdLatency = "etcd-latency"
+)
+
+var haltReasons = []string{haltReasonLowSpace, haltReasonVersionSkew, haltReasonEtcdLatency}
+
+// balanceSchedulerTypes are the types of schedulers halted by the guard. The
+// schedulers added for the purpose of operation, such as evict-leader, and
+// the checkers which repair replicas keep running.
+var balanceSchedulerTypes = map[string]struct{}{
+	"balance-leader":  {},
+	"balance-region":  {},
+	"hot-region":      {},
+	"shuffle-leader":  {},
+	"shuffle-region":  {},
+	"random-merge":    {},
+	"scatter-range":   {},
+	"adjacent-region": {},
+}
+
+// ScheduleHaltStatus shows whether the balance scheduling is halted and why.
+type ScheduleHaltStatus struct {
+	Halted bool `json:"halted"`
+	// Reasons maps the halt reasons to the details.
+	Reasons map[string]string `json:"reasons,omitempty"`
+	Since   *time.Time        `json:"since,omitempty"`
+}
+
+// scheduleGuard halts the balance scheduling when cluster-wide emergencies are
+// detected, moving data around only makes things worse in such cases.
+type scheduleGuard struct {
+	sync.RWMutex
+	cluster *clusterInfo
+	status  ScheduleHaltStatus
+}
+
+func newScheduleGuard(cluster *clusterInfo) *scheduleGuard {
+	return &scheduleGuard{cluster: cluster}
+}
+
+// checkLowSpace returns the detail if too many up stores are low on space.
+func (g *scheduleGuard) checkLowSpace() string {
+	var up, lowSpace int
+	lowSpaceRatio := g.cluster.GetLowSpaceRatio()
+	for _, s := range g.cluster.GetStores() {
+		if !s.IsUp() {
+			continue
+		}
+		up++
+		if s.IsLowSpace(lowSpaceRatio) {
+			lowSpace++
+		}
+	}
+	if up == 0 || float64(lowSpace)/float64(up) <= g.cluster.opt.GetHaltLowSpaceStoreRatio() {
+		return ""
+	}
+	return fmt.Sprintf("%d of %d up stores are low on space", lowSpace, up)
+}
+
+// checkVersionSkew returns the detail if stores of different major versions
+// coexist, which happens in the middle of upgrades.
+func (g *scheduleGuard) checkVersionSkew() string {
+	majors := make(map[int64]struct{})
+	for _, s := range g.cluster.GetStores() {
+		if s.GetState() == metapb.StoreState_Tombstone {
+			continue
+		}
+		v, err := ParseVersion(s.GetVersion())
+		if err != nil {
+			continue
+		}
+		majors[v.Major] = struct{}{}
+	}
+	if len(majors) <= 1 {
+		return ""
+	}
+	versions := make([]int64, 0, len(majors))
+	for m := range majors {
+		versions = append(versions, m)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+	return fmt.Sprintf("stores of major versions %v coexist", versions)
+}
+
+func (g *scheduleGuard) checkEtcdLatency(latency time.Duration) string {
+	if limit := g.cluster.opt.GetHaltEtcdLatency(); latency > limit {
+		return fmt.Sprintf("etcd latency %v exceeds %v", latency, limit)
+	}
+	return ""
+}
+
+// check updates the halt status with the latest etcd latency.
+func (g *scheduleGuard) check(etcdLatency time.Duration) {
+	reasons := make(map[string]string)
+	for reason, detail := range map[string]string{
+		haltReasonLowSpace:    g.checkLowSpace(),
+		haltReasonVersionSkew: g.checkVersionSkew(),
+		haltReasonEtcdLatency: g.checkEtcdLatency(etcdLatency),
+	} {
+		if detail != "" {
+			reasons[reason] = detail
+		}
+	}
+
+	g.Lock()
+	defer g.Unlock()
+	halted := len(reasons) > 0
+	if halted && !g.status.Halted {
+		now := time.Now()
+		g.status.Since = &now
+		log.Warnf("balance scheduling is halted: %v", reasons)
+	} else if !halted && g.status.Halted {
+		g.status.Since = nil
+		log.Infof("balance scheduling is resumed")
+	}
+	g.status.Halted = halted
+	g.status.Reasons = reasons
+	for _, reason := range haltReasons {
+		v := 0.0
+		if _, ok := reasons[reason]; ok {
+			v = 1
+		}
+		scheduleHaltGauge.WithLabelValues(reason).Set(v)
+	}
+}
+
+// allowSchedule returns false if the scheduler of the type should be halted.
+func (g *scheduleGuard) allowSchedule(schedulerType string) bool {
+	if _, ok := balanceSchedulerTypes[schedulerType]; !ok {
+		return true
+	}
+	g.RLock()
+	defer g.RUnlock()
+	return !g.status.Halted
+}
+
+func (g *scheduleGuard) getStatus() *ScheduleHaltStatus {
+	g.RLock()
+	defer g.RUnlock()
+	status := &ScheduleHaltStatus{Halted: g.status.Halted, Since: g.status.Since}
+	if len(g.status.Reasons) > 0 {
+		status.Reasons = make(map[string]string, len(g.status.Reasons))
+		for k, v := range g.status.Reasons {
+			status.Reasons[k] = v
+		}
+	}
+	return status
+}
+
+// checkScheduleHalt measures the etcd latency and checks whether to halt the
+// balance scheduling.
+func (c *RaftCluster) checkScheduleHalt() {
+	start := time.Now()
+	if _, err := c.s.kv.LoadMeta(&metapb.Cluster{}); err != nil {
+		log.Errorf("failed to measure etcd latency: %v", err)
+	}
+	c.coordinator.guard.check(time.Since(start))
+}
+
+// GetScheduleHaltStatus returns whether the balance scheduling is halted.
+func (h *Handler) GetScheduleHaltStatus() (*ScheduleHaltStatus, error) {
+	c, err := h.getCoordinator()
+	if err != nil {
+		return nil, err
+	}
+	return c.guard.getStatus(), nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"time"
+
+	. "github.com/pingcap/check"
+)
+
+var _ = Suite(&testScheduleHaltSuite{})
+
+type testScheduleHaltSuite struct{}
+
+func (s *testScheduleHaltSuite) TestScheduleGuard(c *C) {
+	_, opt := newTestScheduleConfig()
+	tc := newTestClusterInfo(opt)
+	for id := uint64(1); id <= 5; id++ {
+		tc.addRegionStore(id, 10)
+		store := tc.GetStore(id)
+		store.Version = "2.1.0"
+		tc.putStore(store)
+	}
+	guard := newScheduleGuard(tc.clusterInfo)
+
+	guard.check(0)
+	c.Assert(guard.getStatus(), DeepEquals, &ScheduleHaltStatus{})
+	c.Assert(guard.allowSchedule("balance-region"), IsTrue)
+
+	// 1 of 5 stores is low on space.
+	store := tc.GetStore(1)
+	store.Stats.Available = store.Stats.Capacity / 10
+	tc.putStore(store)
+	guard.check(0)
+	c.Assert(guard.getStatus().Halted, IsFalse)
+
+	// 2 of 5 stores are low on space.
+	store = tc.GetStore(2)
+	store.Stats.Available = store.Stats.Capacity / 10
+	tc.putStore(store)
+	guard.check(0)
+	status := guard.getStatus()
+	c.Assert(status.Halted, IsTrue)
+	c.Assert(status.Since, NotNil)
+	c.Assert(status.Reasons, DeepEquals, map[string]string{haltReasonLowSpace: "2 of 5 up stores are low on space"})
+	c.Assert(guard.allowSchedule("balance-region"), IsFalse)
+	c.Assert(guard.allowSchedule("hot-region"), IsFalse)
+	c.Assert(guard.allowSchedule("evict-leader"), IsTrue)
+
+	// The offline stores are not counted.
+	tc.setStoreOffline(2)
+	guard.check(0)
+	c.Assert(guard.getStatus(), DeepEquals, &ScheduleHaltStatus{})
+
+	// Stores of different major versions.
+	store = tc.GetStore(4)
+	store.Version = "3.0.0-rc.1"
+	tc.putStore(store)
+	guard.check(2 * time.Second)
+	status = guard.getStatus()
+	c.Assert(status.Reasons, DeepEquals, map[string]string{
+		haltReasonVersionSkew: "stores of major versions [2 3] coexist",
+		haltReasonEtcdLatency: "etcd latency 2s exceeds 1s",
+	})
+	c.Assert(guard.allowSchedule("balance-leader"), IsFalse)
+}
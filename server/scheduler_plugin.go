@@ -0,0 +1,119 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"sync"
+
+	"github.com/pingcap/errcode"
+	"github.com/pingcap/pd/server/schedule"
+	log "github.com/sirupsen/logrus"
+)
+
+// schedulerPluginMu serializes loading and unloading the plugins, so that the
+// persisted plugins match the loaded ones.
+var schedulerPluginMu sync.Mutex
+
+// loadSchedulerPlugins loads the persisted scheduler plugins, so that the
+// persisted schedulers of the plugins can be created by the coordinator. The
+// plugins removed from the allow list are skipped.
+func (s *Server) loadSchedulerPlugins() {
+	schedulerPluginMu.Lock()
+	defer schedulerPluginMu.Unlock()
+
+	names, err := s.kv.LoadSchedulerPlugins()
+	if err != nil {
+		log.Errorf("failed to load scheduler plugins: %v", err)
+		return
+	}
+	for _, name := range names {
+		path, err := s.cfg.SchedulerPlugin.pluginPath(name)
+		if err == nil {
+			_, err = schedule.LoadSchedulerPlugin(path)
+		}
+		if err != nil {
+			log.Errorf("failed to load scheduler plugin %s: %v", name, err)
+		}
+	}
+}
+
+// GetSchedulerTypes returns the scheduler types which can be added.
+func (h *Handler) GetSchedulerTypes() []schedule.SchedulerType {
+	return schedule.GetSchedulerTypes()
+}
+
+// GetSchedulerPlugins returns the paths of the loaded scheduler plugins.
+func (h *Handler) GetSchedulerPlugins() []string {
+	return schedule.GetSchedulerPlugins()
+}
+
+// LoadSchedulerPlugin loads the scheduler plugin with the file name in the
+// allow list, and persists it so that it is loaded again after restart. It
+// returns the registered scheduler types.
+func (h *Handler) LoadSchedulerPlugin(name string) ([]string, error) {
+	path, err := h.s.cfg.SchedulerPlugin.pluginPath(name)
+	if err != nil {
+		return nil, errcode.NewInvalidInputErr(err)
+	}
+	schedulerPluginMu.Lock()
+	defer schedulerPluginMu.Unlock()
+
+	types, err := schedule.LoadSchedulerPlugin(path)
+	if err != nil {
+		return nil, err
+	}
+	names, err := h.s.kv.LoadSchedulerPlugins()
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range names {
+		if n == name {
+			return types, nil
+		}
+	}
+	return types, h.s.kv.SaveSchedulerPlugins(append(names, name))
+}
+
+// UnloadSchedulerPlugin removes the schedulers of the plugin and unregisters
+// their types.
+func (h *Handler) UnloadSchedulerPlugin(name string) error {
+	path, err := h.s.cfg.SchedulerPlugin.pluginPath(name)
+	if err != nil {
+		return errcode.NewInvalidInputErr(err)
+	}
+	schedulerPluginMu.Lock()
+	defer schedulerPluginMu.Unlock()
+
+	types, err := schedule.UnloadSchedulerPlugin(path)
+	if err != nil {
+		return errcode.NewInvalidInputErr(err)
+	}
+	if c, err := h.getCoordinator(); err == nil {
+		for _, scheduler := range c.getSchedulersByTypes(types) {
+			if err = h.RemoveScheduler(scheduler); err != nil {
+				return err
+			}
+		}
+	}
+	names, err := h.s.kv.LoadSchedulerPlugins()
+	if err != nil {
+		return err
+	}
+	for i, n := range names {
+		if n == name {
+			return h.s.kv.SaveSchedulerPlugins(append(names[:i], names[i+1:]...))
+		}
+	}
+	return nil
+}
@@ -33,7 +33,7 @@ const (
 )
 
 func init() {
-	schedule.RegisterScheduler("adjacent-region", func(opController *schedule.OperatorController, args []string) (schedule.Scheduler, error) {
+	schedule.RegisterSchedulerWithArgs("adjacent-region", func(opController *schedule.OperatorController, args []string) (schedule.Scheduler, error) {
 		l := len(args)
 		if l == 2 {
 			leaderLimit, err := strconv.ParseUint(args[0], 10, 64)
@@ -53,6 +53,9 @@ func init() {
 			return newBalanceAdjacentRegionScheduler(opController, leaderLimit), nil
 		}
 		return newBalanceAdjacentRegionScheduler(opController), nil
+	}, []schedule.SchedulerArg{
+		{Name: "leader-limit", Description: "the max count of leaders to schedule", Optional: true},
+		{Name: "peer-limit", Description: "the max count of peers to schedule", Optional: true},
 	})
 }
 
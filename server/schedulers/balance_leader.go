@@ -34,6 +34,7 @@ const balanceLeaderRetryLimit = 10
 
 type balanceLeaderScheduler struct {
 	*baseScheduler
+	*balanceConfig
 	selector     *schedule.BalanceSelector
 	taintStores  *cache.TTLUint64
 	opController *schedule.OperatorController
@@ -50,6 +51,7 @@ func newBalanceLeaderScheduler(opController *schedule.OperatorController) schedu
 	base := newBaseScheduler(opController)
 	s := &balanceLeaderScheduler{
 		baseScheduler: base,
+		balanceConfig: &balanceConfig{},
 		selector:      schedule.NewBalanceSelector(core.LeaderKind, filters),
 		taintStores:   taintStores,
 		opController:  opController,
@@ -71,7 +73,53 @@ func (l *balanceLeaderScheduler) IsScheduleAllowed(cluster schedule.Cluster) boo
 
 func (l *balanceLeaderScheduler) Schedule(cluster schedule.Cluster) []*schedule.Operator {
 	schedulerCounter.WithLabelValues(l.GetName(), "schedule").Inc()
+	cluster = l.wrapCluster(cluster)
+
+	// The influence of the operators created in the same batch is added to
+	// opInfluence, so that the stores are not over balanced.
+	batch := &leaderBatch{
+		opInfluence: l.opController.GetOpInfluence(cluster),
+		pairs:       make(map[storePair]struct{}),
+		regions:     make(map[uint64]struct{}),
+	}
+	// The operators of the batch are not added to the controller until they
+	// are returned, so the batch is capped by the room left under the limit.
+	size := cluster.GetLeaderScheduleBatch()
+	limit, count := cluster.GetLeaderScheduleLimit(), l.opController.OperatorCount(schedule.OpLeader)
+	if count >= limit {
+		return nil
+	}
+	if limit-count < size {
+		size = limit - count
+	}
+	var ops []*schedule.Operator
+	for i := uint64(0); i < size; i++ {
+		op := l.scheduleOnce(cluster, batch, i == 0)
+		if op == nil {
+			break
+		}
+		ops = append(ops, op...)
+	}
+	return ops
+}
+
+// storePair is the source and target store of a leader transfer.
+type storePair struct {
+	source, target uint64
+}
+
+// leaderBatch records the operators created in a batch, a region or a pair of
+// stores is scheduled at most once in a batch.
+type leaderBatch struct {
+	opInfluence schedule.OpInfluence
+	pairs       map[storePair]struct{}
+	regions     map[uint64]struct{}
+}
 
+// scheduleOnce creates a leader transfer of a region and a pair of stores which
+// are not scheduled in the batch. The selected stores are tainted if no
+// operator is created for them in the first round of a batch.
+func (l *balanceLeaderScheduler) scheduleOnce(cluster schedule.Cluster, batch *leaderBatch, first bool) []*schedule.Operator {
 	stores := cluster.GetStores()
 
 	// source/target is the store with highest/lowest leader score in the list that
@@ -96,17 +144,19 @@ func (l *balanceLeaderScheduler) Schedule(cluster schedule.Cluster) []*schedule.
 	balanceLeaderCounter.WithLabelValues("high_score", sourceStoreLabel).Inc()
 	balanceLeaderCounter.WithLabelValues("low_score", targetStoreLabel).Inc()
 
-	opInfluence := l.opController.GetOpInfluence(cluster)
 	for i := 0; i < balanceLeaderRetryLimit; i++ {
-		if op := l.transferLeaderOut(source, cluster, opInfluence); op != nil {
+		if op := l.transferLeaderOut(source, cluster, batch); op != nil {
 			balanceLeaderCounter.WithLabelValues("transfer_out", sourceStoreLabel).Inc()
 			return op
 		}
-		if op := l.transferLeaderIn(target, cluster, opInfluence); op != nil {
+		if op := l.transferLeaderIn(target, cluster, batch); op != nil {
 			balanceLeaderCounter.WithLabelValues("transfer_in", targetStoreLabel).Inc()
 			return op
 		}
 	}
+	if !first {
+		return nil
+	}
 
 	// If no operator can be created for the selected stores, ignore them for a while.
 	log.Debugf("[%s] no operator created for selected store%d and store%d", l.GetName(), source.GetId(), target.GetId())
@@ -117,23 +167,30 @@ func (l *balanceLeaderScheduler) Schedule(cluster schedule.Cluster) []*schedule.
 	return nil
 }
 
-func (l *balanceLeaderScheduler) transferLeaderOut(source *core.StoreInfo, cluster schedule.Cluster, opInfluence schedule.OpInfluence) []*schedule.Operator {
+func (l *balanceLeaderScheduler) transferLeaderOut(source *core.StoreInfo, cluster schedule.Cluster, batch *leaderBatch) []*schedule.Operator {
 	region := cluster.RandLeaderRegion(source.GetId(), core.HealthRegion())
 	if region == nil {
 		log.Debugf("[%s] store%d has no leader", l.GetName(), source.GetId())
 		schedulerCounter.WithLabelValues(l.GetName(), "no_leader_region").Inc()
 		return nil
 	}
-	target := l.selector.SelectTarget(cluster, cluster.GetFollowerStores(region))
+	// Skip the stores which are already the target of the source in the batch.
+	excluded := make(map[uint64]struct{})
+	for pair := range batch.pairs {
+		if pair.source == source.GetId() {
+			excluded[pair.target] = struct{}{}
+		}
+	}
+	target := l.selector.SelectTarget(cluster, cluster.GetFollowerStores(region), schedule.NewExcludedFilter(nil, excluded))
 	if target == nil {
 		log.Debugf("[%s] region %d has no target store", l.GetName(), region.GetID())
 		schedulerCounter.WithLabelValues(l.GetName(), "no_target_store").Inc()
 		return nil
 	}
-	return l.createOperator(region, source, target, cluster, opInfluence)
+	return l.createOperator(region, source, target, cluster, batch)
 }
 
-func (l *balanceLeaderScheduler) transferLeaderIn(target *core.StoreInfo, cluster schedule.Cluster, opInfluence schedule.OpInfluence) []*schedule.Operator {
+func (l *balanceLeaderScheduler) transferLeaderIn(target *core.StoreInfo, cluster schedule.Cluster, batch *leaderBatch) []*schedule.Operator {
 	region := cluster.RandFollowerRegion(target.GetId(), core.HealthRegion())
 	if region == nil {
 		log.Debugf("[%s] store%d has no follower", l.GetName(), target.GetId())
@@ -146,10 +203,21 @@ func (l *balanceLeaderScheduler) transferLeaderIn(target *core.StoreInfo, cluste
 		schedulerCounter.WithLabelValues(l.GetName(), "no_leader").Inc()
 		return nil
 	}
-	return l.createOperator(region, source, target, cluster, opInfluence)
+	return l.createOperator(region, source, target, cluster, batch)
 }
 
-func (l *balanceLeaderScheduler) createOperator(region *core.RegionInfo, source, target *core.StoreInfo, cluster schedule.Cluster, opInfluence schedule.OpInfluence) []*schedule.Operator {
+func (l *balanceLeaderScheduler) createOperator(region *core.RegionInfo, source, target *core.StoreInfo, cluster schedule.Cluster, batch *leaderBatch) []*schedule.Operator {
+	if _, ok := batch.regions[region.GetID()]; ok {
+		schedulerCounter.WithLabelValues(l.GetName(), "region_scheduled").Inc()
+		return nil
+	}
+	pair := storePair{source: source.GetId(), target: target.GetId()}
+	if _, ok := batch.pairs[pair]; ok {
+		schedulerCounter.WithLabelValues(l.GetName(), "pair_scheduled").Inc()
+		return nil
+	}
+	opInfluence := batch.opInfluence
+
 	if cluster.IsRegionHot(region.GetID()) {
 		log.Debugf("[%s] region %d is hot region, ignore it", l.GetName(), region.GetID())
 		schedulerCounter.WithLabelValues(l.GetName(), "region_hot").Inc()
@@ -171,5 +239,8 @@ func (l *balanceLeaderScheduler) createOperator(region *core.RegionInfo, source,
 	balanceLeaderCounter.WithLabelValues("move_leader", fmt.Sprintf("store%d-in", target.GetId())).Inc()
 	step := schedule.TransferLeader{FromStore: region.GetLeader().GetStoreId(), ToStore: target.GetId()}
 	op := schedule.NewOperator("balance-leader", region.GetID(), region.GetRegionEpoch(), schedule.OpBalance|schedule.OpLeader, step)
+	step.Influence(opInfluence, region)
+	batch.pairs[pair] = struct{}{}
+	batch.regions[region.GetID()] = struct{}{}
 	return []*schedule.Operator{op}
 }
@@ -35,6 +35,7 @@ const balanceRegionRetryLimit = 10
 
 type balanceRegionScheduler struct {
 	*baseScheduler
+	*balanceConfig
 	selector     *schedule.BalanceSelector
 	taintStores  *cache.TTLUint64
 	opController *schedule.OperatorController
@@ -46,11 +47,13 @@ func newBalanceRegionScheduler(opController *schedule.OperatorController) schedu
 	taintStores := newTaintCache()
 	filters := []schedule.Filter{
 		schedule.StoreStateFilter{MoveRegion: true},
+		schedule.NewPendingCompactionFilter(),
 		schedule.NewCacheFilter(taintStores),
 	}
 	base := newBaseScheduler(opController)
 	s := &balanceRegionScheduler{
 		baseScheduler: base,
+		balanceConfig: &balanceConfig{},
 		selector:      schedule.NewBalanceSelector(core.RegionKind, filters),
 		taintStores:   taintStores,
 		opController:  opController,
@@ -72,6 +75,7 @@ func (s *balanceRegionScheduler) IsScheduleAllowed(cluster schedule.Cluster) boo
 
 func (s *balanceRegionScheduler) Schedule(cluster schedule.Cluster) []*schedule.Operator {
 	schedulerCounter.WithLabelValues(s.GetName(), "schedule").Inc()
+	cluster = s.wrapCluster(cluster)
 
 	stores := cluster.GetStores()
 
@@ -140,12 +144,14 @@ func (s *balanceRegionScheduler) Schedule(cluster schedule.Cluster) []*schedule.
 
 func (s *balanceRegionScheduler) transferPeer(cluster schedule.Cluster, region *core.RegionInfo, oldPeer *metapb.Peer, opInfluence schedule.OpInfluence) *schedule.Operator {
 	// scoreGuard guarantees that the distinct score will not decrease.
+	// isolationGuard guarantees that the isolation level will not be reduced.
 	stores := cluster.GetRegionStores(region)
 	source := cluster.GetStore(oldPeer.GetStoreId())
 	scoreGuard := schedule.NewDistinctScoreFilter(cluster.GetLocationLabels(), stores, source)
+	isolationGuard := schedule.NewIsolationFilter(cluster.GetLocationLabels(), cluster.GetIsolationLevel(), stores, source)
 
 	checker := schedule.NewReplicaChecker(cluster, nil)
-	storeID, _ := checker.SelectBestReplacementStore(region, oldPeer, scoreGuard)
+	storeID, _ := checker.SelectBestReplacementStore(region, oldPeer, scoreGuard, isolationGuard)
 	if storeID == 0 {
 		schedulerCounter.WithLabelValues(s.GetName(), "no_replacement").Inc()
 		return nil
@@ -178,13 +184,14 @@ func (s *balanceRegionScheduler) transferPeer(cluster schedule.Cluster, region *
 
 // hasPotentialTarget is used to determine whether the specified sourceStore
 // cannot find a matching targetStore in the long term.
-// The main factor for judgment includes StoreState, DistinctScore, and
-// ResourceScore, while excludes factors such as ServerBusy, too many snapshot,
+// The main factor for judgment includes StoreState, DistinctScore,
+// IsolationLevel and ResourceScore, while excludes factors such as ServerBusy, too many snapshot,
 // which may recover soon.
 func (s *balanceRegionScheduler) hasPotentialTarget(cluster schedule.Cluster, region *core.RegionInfo, source *core.StoreInfo, opInfluence schedule.OpInfluence) bool {
 	filters := []schedule.Filter{
 		schedule.NewExcludedFilter(nil, region.GetStoreIds()),
 		schedule.NewDistinctScoreFilter(cluster.GetLocationLabels(), cluster.GetRegionStores(region), source),
+		schedule.NewIsolationFilter(cluster.GetLocationLabels(), cluster.GetIsolationLevel(), cluster.GetRegionStores(region), source),
 	}
 
 	for _, store := range cluster.GetStores() {
@@ -197,6 +197,88 @@ func (s *testBalanceLeaderSchedulerSuite) TestScheduleWithOpInfluence(c *C) {
 	c.Check(s.schedule(), IsNil)
 }
 
+func (s *testBalanceLeaderSchedulerSuite) TestConfig(c *C) {
+	// Stores:     1    2    3    4
+	// Leaders:    8    8    9   13
+	// Region1:    F    F    F    L
+	s.tc.AddLeaderStore(1, 8)
+	s.tc.AddLeaderStore(2, 8)
+	s.tc.AddLeaderStore(3, 9)
+	s.tc.AddLeaderStore(4, 13)
+	s.tc.AddLeaderRegion(1, 4, 1, 2, 3)
+	c.Check(s.schedule(), IsNil)
+
+	p := s.lb.(schedule.ConfigProvider)
+	c.Assert(p.SetConfig([]byte(`{"tolerant-size-ratio": -1}`)), NotNil)
+	c.Assert(p.SetConfig([]byte(`{"unknown": 1}`)), NotNil)
+	c.Assert(p.GetConfig(), DeepEquals, &balanceSchedulerConfig{})
+
+	// The tolerant size ratio of the scheduler overrides the cluster's.
+	c.Assert(p.SetConfig([]byte(`{"tolerant-size-ratio": 1}`)), IsNil)
+	c.Assert(p.GetConfig(), DeepEquals, &balanceSchedulerConfig{TolerantSizeRatio: 1})
+	c.Check(s.schedule(), NotNil)
+
+	c.Assert(p.SetConfig([]byte(`{"tolerant-size-ratio": 0}`)), IsNil)
+	c.Check(s.schedule(), IsNil)
+}
+
+func (s *testBalanceLeaderSchedulerSuite) TestBatch(c *C) {
+	// Stores:     1    2    3    4
+	// Leaders:    16   0    0    0
+	// Region1:    L    F    F    F
+	// Region2:    L    F    F    F
+	// Region3:    L    F    F    F
+	s.tc.AddLeaderStore(1, 16)
+	s.tc.AddLeaderStore(2, 0)
+	s.tc.AddLeaderStore(3, 0)
+	s.tc.AddLeaderStore(4, 0)
+	s.tc.AddLeaderRegion(1, 1, 2, 3, 4)
+	s.tc.AddLeaderRegion(2, 1, 2, 3, 4)
+	s.tc.AddLeaderRegion(3, 1, 2, 3, 4)
+	c.Assert(s.schedule(), HasLen, 1)
+
+	s.tc.LeaderScheduleBatch = 3
+	ops := s.schedule()
+	c.Assert(len(ops), Greater, 1)
+	regions := make(map[uint64]struct{})
+	targets := make(map[uint64]struct{})
+	for _, op := range ops {
+		regions[op.RegionID()] = struct{}{}
+		targets[op.Step(0).(schedule.TransferLeader).ToStore] = struct{}{}
+	}
+	// Each operator transfers a different region to a different store.
+	c.Assert(regions, HasLen, len(ops))
+	c.Assert(targets, HasLen, len(ops))
+}
+
+func (s *testBalanceLeaderSchedulerSuite) TestBatchLimit(c *C) {
+	// Stores:     1    2    3    4
+	// Leaders:    16   0    0    0
+	// Region1:    L    F    F    F
+	// Region2:    L    F    F    F
+	// Region3:    L    F    F    F
+	s.tc.AddLeaderStore(1, 16)
+	s.tc.AddLeaderStore(2, 0)
+	s.tc.AddLeaderStore(3, 0)
+	s.tc.AddLeaderStore(4, 0)
+	s.tc.AddLeaderRegion(1, 1, 2, 3, 4)
+	s.tc.AddLeaderRegion(2, 1, 2, 3, 4)
+	s.tc.AddLeaderRegion(3, 1, 2, 3, 4)
+	s.tc.LeaderScheduleBatch = 3
+	s.tc.LeaderScheduleLimit = 2
+	oc := schedule.NewOperatorController(s.tc, schedule.NewMockHeartbeatStreams(s.tc.ID))
+	lb, err := schedule.CreateScheduler("balance-leader", oc)
+	c.Assert(err, IsNil)
+
+	// The batch does not exceed the room left under the limit.
+	ops := lb.Schedule(s.tc)
+	c.Assert(ops, HasLen, 2)
+	c.Assert(oc.AddOperator(ops[0]), IsTrue)
+	c.Assert(lb.Schedule(s.tc), HasLen, 1)
+	c.Assert(oc.AddOperator(ops[1]), IsTrue)
+	c.Assert(lb.Schedule(s.tc), HasLen, 0)
+}
+
 func (s *testBalanceLeaderSchedulerSuite) TestBalanceFilter(c *C) {
 	// Stores:     1    2    3    4
 	// Leaders:    1    2    3   16
@@ -563,6 +645,83 @@ func (s *testReplicaCheckerSuite) TestBasic(c *C) {
 	testutil.CheckTransferPeer(c, rc.Check(region), schedule.OpReplica, 3, 1)
 }
 
+func (s *testReplicaCheckerSuite) TestLearner(c *C) {
+	opt := schedule.NewMockSchedulerOptions()
+	tc := schedule.NewMockCluster(opt)
+	rc := schedule.NewReplicaChecker(tc, namespace.DefaultClassifier)
+
+	tc.AddRegionStore(1, 1)
+	tc.AddRegionStore(2, 1)
+	tc.AddRegionStore(3, 1)
+	tc.AddRegionStore(4, 1)
+	tc.AddLeaderRegion(1, 1, 2, 3)
+	learner, _ := tc.AllocPeer(4)
+	learner.IsLearner = true
+	region := tc.GetRegion(1).Clone(core.WithAddPeer(learner), core.WithPendingPeers([]*metapb.Peer{learner}))
+
+	// The pending learner is not promoted until it catches up.
+	c.Assert(rc.Check(region), IsNil)
+	c.Assert(schedule.IsLearnerPromotable(tc, region, learner), IsFalse)
+	region = region.Clone(core.WithPendingPeers(nil))
+	c.Assert(schedule.IsLearnerPromotable(tc, region, learner), IsTrue)
+
+	// The learner is down.
+	downPeer := &pdpb.PeerStats{Peer: learner, DownSeconds: 24 * 60 * 60}
+	downRegion := region.Clone(core.WithDownPeers([]*pdpb.PeerStats{downPeer}))
+	c.Assert(schedule.IsLearnerPromotable(tc, downRegion, learner), IsFalse)
+	testutil.CheckRemovePeer(c, rc.Check(downRegion), 4)
+
+	// The store of the learner is offline.
+	tc.SetStoreOffline(4)
+	c.Assert(schedule.IsLearnerPromotable(tc, region, learner), IsFalse)
+	op := rc.Check(region)
+	testutil.CheckRemovePeer(c, op, 4)
+	c.Assert(op.GetPriorityLevel(), Equals, core.HighPriority)
+}
+
+func (s *testReplicaCheckerSuite) TestWitness(c *C) {
+	opt := schedule.NewMockSchedulerOptions()
+	opt.LabelProperties = map[string][]*metapb.StoreLabel{
+		schedule.WitnessReplica: {{Key: "zone", Value: "z3"}},
+	}
+	tc := schedule.NewMockCluster(opt)
+	rc := schedule.NewReplicaChecker(tc, namespace.DefaultClassifier)
+
+	tc.AddLabelsStore(1, 1, map[string]string{"zone": "z1"})
+	tc.AddLabelsStore(2, 1, map[string]string{"zone": "z2"})
+	tc.AddLabelsStore(3, 1, map[string]string{"zone": "z3"})
+	tc.AddLabelsStore(4, 1, map[string]string{"zone": "z3"})
+	tc.AddLeaderRegion(1, 1, 2, 3)
+	region := tc.GetRegion(1)
+
+	// The voter on the witness store is switched to a witness.
+	op := rc.Check(region)
+	c.Assert(op, NotNil)
+	c.Assert(op.Step(0), Equals, schedule.SwitchToWitness{StoreID: 3, PeerID: region.GetStorePeer(3).GetId()})
+	region = region.Clone(core.WithWitnesses([]*metapb.Peer{region.GetStorePeer(3)}))
+	c.Assert(op.Step(0).IsFinish(region), IsTrue)
+	c.Assert(rc.Check(region), IsNil)
+
+	// The leader on the witness store is not switched.
+	tc.AddLeaderRegion(2, 3, 1, 2)
+	c.Assert(rc.Check(tc.GetRegion(2)), IsNil)
+
+	// The witnesses are fewer than half of the voters.
+	tc.AddLeaderRegion(3, 1, 3, 4)
+	region3 := tc.GetRegion(3)
+	region3 = region3.Clone(core.WithWitnesses([]*metapb.Peer{region3.GetStorePeer(3)}))
+	c.Assert(rc.Check(region3), IsNil)
+
+	// The witness is switched back to a full voter if the store is no longer
+	// for witnesses.
+	opt.LabelProperties = nil
+	op = rc.Check(region)
+	c.Assert(op, NotNil)
+	c.Assert(op.Step(0), Equals, schedule.SwitchToVoter{StoreID: 3, PeerID: region.GetStorePeer(3).GetId()})
+	c.Assert(op.Step(0).IsFinish(region), IsFalse)
+	c.Assert(op.Step(0).IsFinish(region.Clone(core.WithWitnesses(nil))), IsTrue)
+}
+
 func (s *testReplicaCheckerSuite) TestLostStore(c *C) {
 	opt := schedule.NewMockSchedulerOptions()
 	tc := schedule.NewMockCluster(opt)
@@ -638,6 +797,52 @@ func (s *testReplicaCheckerSuite) TestOffline(c *C) {
 	c.Assert(rc.Check(region), IsNil)
 }
 
+func (s *testReplicaCheckerSuite) TestOfflineReplacement(c *C) {
+	opt := schedule.NewMockSchedulerOptions()
+	tc := schedule.NewMockCluster(opt)
+
+	newTestReplication(opt, 3, "zone", "host")
+
+	rc := schedule.NewReplicaChecker(tc, namespace.DefaultClassifier)
+
+	tc.AddLabelsStore(1, 1, map[string]string{"zone": "z1", "host": "h1"})
+	tc.AddLabelsStore(2, 1, map[string]string{"zone": "z2", "host": "h1"})
+	tc.AddLabelsStore(3, 1, map[string]string{"zone": "z3", "host": "h1"})
+	tc.AddLabelsStore(4, 1, map[string]string{"zone": "z3", "host": "h2"})
+	tc.AddLabelsStore(5, 10, map[string]string{"zone": "z3", "host": "h3"})
+	tc.AddLabelsStore(6, 1, map[string]string{"zone": "z1", "host": "h2"})
+	tc.AddLeaderRegion(1, 1, 2, 3)
+	region := tc.GetRegion(1)
+
+	pair := func(storeID, replacementID uint64) {
+		store := tc.GetStore(storeID)
+		store.SetReplacement(&core.StoreReplacement{StoreID: replacementID})
+		tc.PutStore(store)
+	}
+
+	// Store 4 is the best replacement.
+	tc.SetStoreOffline(3)
+	testutil.CheckTransferPeer(c, rc.Check(region), schedule.OpReplica, 3, 4)
+
+	// Store 5 is paired, its distinct score is the same as store 4.
+	pair(3, 5)
+	testutil.CheckTransferPeer(c, rc.Check(region), schedule.OpReplica, 3, 5)
+
+	// Store 5 has too many snapshots.
+	tc.UpdateSnapshotCount(5, 10)
+	testutil.CheckTransferPeer(c, rc.Check(region), schedule.OpReplica, 3, 4)
+	tc.UpdateSnapshotCount(5, 0)
+
+	// Store 6 is paired, but it is in the same zone as store 1.
+	pair(3, 6)
+	testutil.CheckTransferPeer(c, rc.Check(region), schedule.OpReplica, 3, 4)
+
+	// Store 5 is paired but down.
+	pair(3, 5)
+	tc.SetStoreDown(5)
+	testutil.CheckTransferPeer(c, rc.Check(region), schedule.OpReplica, 3, 4)
+}
+
 func (s *testReplicaCheckerSuite) TestDistinctScore(c *C) {
 	opt := schedule.NewMockSchedulerOptions()
 	tc := schedule.NewMockCluster(opt)
@@ -745,6 +950,44 @@ func (s *testReplicaCheckerSuite) TestDistinctScore2(c *C) {
 	c.Assert(rc.Check(region), IsNil)
 }
 
+func (s *testReplicaCheckerSuite) TestRepairPriority(c *C) {
+	opt := schedule.NewMockSchedulerOptions()
+	tc := schedule.NewMockCluster(opt)
+
+	newTestReplication(opt, 5, "zone", "host")
+
+	rc := schedule.NewReplicaChecker(tc, namespace.DefaultClassifier)
+
+	tc.AddLabelsStore(1, 1, map[string]string{"zone": "z1", "host": "h1"})
+	tc.AddLabelsStore(2, 1, map[string]string{"zone": "z1", "host": "h2"})
+	tc.AddLabelsStore(3, 1, map[string]string{"zone": "z2", "host": "h1"})
+	tc.AddLabelsStore(4, 1, map[string]string{"zone": "z2", "host": "h2"})
+	tc.AddLabelsStore(5, 1, map[string]string{"zone": "z3", "host": "h1"})
+	tc.AddLeaderRegion(1, 1, 2, 3, 4, 5)
+	region := tc.GetRegion(1)
+	c.Assert(rc.GetRepairPriority(region), Equals, schedule.RepairNotNeeded)
+
+	// The region spans z1 and z2 with 2 healthy voters in each, it loses
+	// the quorum if either zone fails.
+	tc.SetStoreDown(5)
+	c.Assert(rc.GetRepairPriority(region), Equals, schedule.RepairAtRisk)
+
+	// Without location labels, each store is a failure domain, the region
+	// survives the failure of any one.
+	opt.LocationLabels = nil
+	c.Assert(rc.GetRepairPriority(region), Equals, schedule.RepairNormal)
+	opt.LocationLabels = []string{"zone", "host"}
+
+	// The leader reports the peer in store 4 is down.
+	downPeer := &pdpb.PeerStats{Peer: region.GetStorePeer(4), DownSeconds: 24 * 60 * 60}
+	region = region.Clone(core.WithDownPeers([]*pdpb.PeerStats{downPeer}))
+	c.Assert(rc.GetRepairPriority(region), Equals, schedule.RepairAtRisk)
+
+	// Only 2 of 5 voters are healthy.
+	tc.SetStoreDown(3)
+	c.Assert(rc.GetRepairPriority(region), Equals, schedule.RepairQuorumLost)
+}
+
 func (s *testReplicaCheckerSuite) TestStorageThreshold(c *C) {
 	opt := schedule.NewMockSchedulerOptions()
 	opt.LocationLabels = []string{"zone"}
@@ -938,6 +1181,21 @@ func (s *testMergeCheckerSuite) TestBasic(c *C) {
 	c.Assert(ops, IsNil)
 }
 
+func (s *testMergeCheckerSuite) TestMergeProtection(c *C) {
+	s.cluster.MockSchedulerOptions.MergeProtectionInterval = time.Hour
+
+	// The regions loaded from the storage are not protected.
+	c.Assert(s.mc.Check(s.regions[2]), NotNil)
+	// Skip the regions split recently.
+	region := s.regions[2].Clone(core.SetCreateTime(time.Now()))
+	c.Assert(s.mc.Check(region), IsNil)
+	region = s.regions[2].Clone(core.SetCreateTime(time.Now().Add(-2 * time.Hour)))
+	c.Assert(s.mc.Check(region), NotNil)
+	// Do not merge into the regions split recently.
+	s.cluster.PutRegion(s.regions[1].Clone(core.SetCreateTime(time.Now())))
+	c.Assert(s.mc.Check(s.regions[2]), IsNil)
+}
+
 func (s *testMergeCheckerSuite) checkSteps(c *C, op *schedule.Operator, steps []schedule.OperatorStep) {
 	c.Assert(op.Kind()&schedule.OpMerge, Not(Equals), 0)
 	c.Assert(steps, NotNil)
@@ -1241,6 +1499,10 @@ func (s *testScatterRangeLeaderSuite) TestBalance(c *C) {
 		tc.UpdateStoreStatus(uint64(i))
 	}
 	oc := schedule.NewOperatorController(nil, nil)
+	_, err := schedule.CreateScheduler("scatter-range", oc, "s_00", "s_50", "")
+	c.Assert(err, NotNil)
+	_, err = schedule.CreateScheduler("scatter-range", oc, "s_50", "s_00", "t")
+	c.Assert(err, NotNil)
 	hb, err := schedule.CreateScheduler("scatter-range", oc, "s_00", "s_50", "t")
 	c.Assert(err, IsNil)
 	limit := 0
@@ -69,4 +69,33 @@ func (s *baseScheduler) GetNextInterval(interval time.Duration) time.Duration {
 
 func (s *baseScheduler) Prepare(cluster schedule.Cluster) error { return nil }
 
+// debugScheduler is the base of the schedulers which move leaders and regions
+// randomly to stress the cluster. The schedule interval is fixed if it is
+// configured by users.
+type debugScheduler struct {
+	*baseScheduler
+	interval time.Duration
+}
+
+func newDebugScheduler(opController *schedule.OperatorController, interval time.Duration) *debugScheduler {
+	return &debugScheduler{
+		baseScheduler: newBaseScheduler(opController),
+		interval:      interval,
+	}
+}
+
+func (s *debugScheduler) GetMinInterval() time.Duration {
+	if s.interval > 0 {
+		return s.interval
+	}
+	return s.baseScheduler.GetMinInterval()
+}
+
+func (s *debugScheduler) GetNextInterval(interval time.Duration) time.Duration {
+	if s.interval > 0 {
+		return intervalGrow(interval, s.interval, zeroGrowth)
+	}
+	return s.baseScheduler.GetNextInterval(interval)
+}
+
 func (s *baseScheduler) Cleanup(cluster schedule.Cluster) {}
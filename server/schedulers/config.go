@@ -0,0 +1,147 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedulers
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+
+	"github.com/pingcap/pd/server/schedule"
+	"github.com/pkg/errors"
+)
+
+// decodeConfig decodes the JSON data to the config, the unknown fields are
+// rejected.
+func decodeConfig(data []byte, cfg interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return errors.WithStack(decoder.Decode(cfg))
+}
+
+// balanceSchedulerConfig is the config of the balance-leader and
+// balance-region schedulers.
+type balanceSchedulerConfig struct {
+	// TolerantSizeRatio overrides the tolerant-size-ratio of the schedule
+	// config if it is not 0.
+	TolerantSizeRatio float64 `json:"tolerant-size-ratio"`
+}
+
+// balanceConfig implements schedule.ConfigProvider for the balance
+// schedulers.
+type balanceConfig struct {
+	mu  sync.RWMutex
+	cfg balanceSchedulerConfig
+}
+
+func (c *balanceConfig) GetConfig() interface{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	cfg := c.cfg
+	return &cfg
+}
+
+func (c *balanceConfig) SetConfig(data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cfg := c.cfg
+	if err := decodeConfig(data, &cfg); err != nil {
+		return err
+	}
+	if cfg.TolerantSizeRatio < 0 {
+		return errors.Errorf("tolerant-size-ratio should be not less than 0, but it is %v", cfg.TolerantSizeRatio)
+	}
+	c.cfg = cfg
+	return nil
+}
+
+// wrapCluster overrides the tolerant size ratio of the cluster if it is set.
+func (c *balanceConfig) wrapCluster(cluster schedule.Cluster) schedule.Cluster {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.cfg.TolerantSizeRatio == 0 {
+		return cluster
+	}
+	return &tolerantCluster{Cluster: cluster, tolerantSizeRatio: c.cfg.TolerantSizeRatio}
+}
+
+// tolerantCluster is a cluster with a different tolerant size ratio.
+type tolerantCluster struct {
+	schedule.Cluster
+	tolerantSizeRatio float64
+}
+
+func (c *tolerantCluster) GetTolerantSizeRatio() float64 {
+	return c.tolerantSizeRatio
+}
+
+// hotRegionSchedulerConfig is the config of the hot region schedulers.
+type hotRegionSchedulerConfig struct {
+	// MinHotDegree overrides the hot degree threshold of the cluster if it is
+	// not 0. Only the regions whose hot degrees reach it are scheduled.
+	MinHotDegree int `json:"min-hot-degree"`
+	// LimitFactor is multiplied by the hot regions that the hottest store has
+	// more than the average, which is the max hot region operators.
+	LimitFactor float64 `json:"limit-factor"`
+	// ScheduleFactor is the ratio of the flow of the source store, less than
+	// which the flow of the target store should be after moving a region.
+	ScheduleFactor float64 `json:"schedule-factor"`
+}
+
+// hotRegionConfig implements schedule.ConfigProvider for the hot region
+// schedulers.
+type hotRegionConfig struct {
+	mu  sync.RWMutex
+	cfg hotRegionSchedulerConfig
+}
+
+func newHotRegionConfig() *hotRegionConfig {
+	return &hotRegionConfig{
+		cfg: hotRegionSchedulerConfig{
+			LimitFactor:    defaultHotRegionLimitFactor,
+			ScheduleFactor: defaultHotRegionScheduleFactor,
+		},
+	}
+}
+
+func (c *hotRegionConfig) GetConfig() interface{} {
+	return c.get()
+}
+
+func (c *hotRegionConfig) get() *hotRegionSchedulerConfig {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	cfg := c.cfg
+	return &cfg
+}
+
+func (c *hotRegionConfig) SetConfig(data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cfg := c.cfg
+	if err := decodeConfig(data, &cfg); err != nil {
+		return err
+	}
+	if cfg.MinHotDegree < 0 {
+		return errors.Errorf("min-hot-degree should be not less than 0, but it is %v", cfg.MinHotDegree)
+	}
+	if cfg.LimitFactor <= 0 || cfg.LimitFactor > 1 {
+		return errors.Errorf("limit-factor should be in (0, 1], but it is %v", cfg.LimitFactor)
+	}
+	if cfg.ScheduleFactor <= 0 || cfg.ScheduleFactor > 1 {
+		return errors.Errorf("schedule-factor should be in (0, 1], but it is %v", cfg.ScheduleFactor)
+	}
+	c.cfg = cfg
+	return nil
+}
@@ -23,7 +23,7 @@ import (
 )
 
 func init() {
-	schedule.RegisterScheduler("evict-leader", func(opController *schedule.OperatorController, args []string) (schedule.Scheduler, error) {
+	schedule.RegisterSchedulerWithArgs("evict-leader", func(opController *schedule.OperatorController, args []string) (schedule.Scheduler, error) {
 		if len(args) != 1 {
 			return nil, errors.New("evict-leader needs 1 argument")
 		}
@@ -32,6 +32,8 @@ func init() {
 			return nil, errors.WithStack(err)
 		}
 		return newEvictLeaderScheduler(opController, id), nil
+	}, []schedule.SchedulerArg{
+		{Name: "store-id", Description: "the store to evict all leaders from"},
 	})
 }
 
@@ -23,7 +23,7 @@ import (
 )
 
 func init() {
-	schedule.RegisterScheduler("grant-leader", func(opController *schedule.OperatorController, args []string) (schedule.Scheduler, error) {
+	schedule.RegisterSchedulerWithArgs("grant-leader", func(opController *schedule.OperatorController, args []string) (schedule.Scheduler, error) {
 		if len(args) != 1 {
 			return nil, errors.New("grant-leader needs 1 argument")
 		}
@@ -32,6 +32,8 @@ func init() {
 			return nil, errors.WithStack(err)
 		}
 		return newGrantLeaderScheduler(opController, id), nil
+	}, []schedule.SchedulerArg{
+		{Name: "store-id", Description: "the store to transfer all leaders to"},
 	})
 }
 
@@ -61,6 +63,9 @@ func (s *grantLeaderScheduler) GetType() string {
 	return "grant-leader"
 }
 func (s *grantLeaderScheduler) Prepare(cluster schedule.Cluster) error {
+	if store := cluster.GetStore(s.storeID); store != nil && schedule.NewRejectLeaderFilter().FilterTarget(cluster, store) {
+		return errors.Errorf("store %d rejects leaders by label property", s.storeID)
+	}
 	return cluster.BlockStore(s.storeID)
 }
 
@@ -74,6 +79,11 @@ func (s *grantLeaderScheduler) IsScheduleAllowed(cluster schedule.Cluster) bool
 
 func (s *grantLeaderScheduler) Schedule(cluster schedule.Cluster) []*schedule.Operator {
 	schedulerCounter.WithLabelValues(s.GetName(), "schedule").Inc()
+	// The label property may be set after the scheduler is added.
+	if store := cluster.GetStore(s.storeID); store != nil && schedule.NewRejectLeaderFilter().FilterTarget(cluster, store) {
+		schedulerCounter.WithLabelValues(s.GetName(), "reject_leader").Inc()
+		return nil
+	}
 	region := cluster.RandFollowerRegion(s.storeID, core.HealthRegion())
 	if region == nil {
 		schedulerCounter.WithLabelValues(s.GetName(), "no_follower").Inc()
@@ -39,9 +39,9 @@ func init() {
 }
 
 const (
-	hotRegionLimitFactor      = 0.75
-	storeHotRegionsDefaultLen = 100
-	hotRegionScheduleFactor   = 0.9
+	defaultHotRegionLimitFactor    = 0.75
+	storeHotRegionsDefaultLen      = 100
+	defaultHotRegionScheduleFactor = 0.9
 )
 
 // BalanceType : the perspective of balance
@@ -68,6 +68,7 @@ func newStoreStaticstics() *storeStatistics {
 
 type balanceHotRegionsScheduler struct {
 	*baseScheduler
+	*hotRegionConfig
 	sync.RWMutex
 	limit uint64
 	types []BalanceType
@@ -80,33 +81,36 @@ type balanceHotRegionsScheduler struct {
 func newBalanceHotRegionsScheduler(opController *schedule.OperatorController) *balanceHotRegionsScheduler {
 	base := newBaseScheduler(opController)
 	return &balanceHotRegionsScheduler{
-		baseScheduler: base,
-		limit:         1,
-		stats:         newStoreStaticstics(),
-		types:         []BalanceType{hotWriteRegionBalance, hotReadRegionBalance},
-		r:             rand.New(rand.NewSource(time.Now().UnixNano())),
+		baseScheduler:   base,
+		hotRegionConfig: newHotRegionConfig(),
+		limit:           1,
+		stats:           newStoreStaticstics(),
+		types:           []BalanceType{hotWriteRegionBalance, hotReadRegionBalance},
+		r:               rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 }
 
 func newBalanceHotReadRegionsScheduler(opController *schedule.OperatorController) *balanceHotRegionsScheduler {
 	base := newBaseScheduler(opController)
 	return &balanceHotRegionsScheduler{
-		baseScheduler: base,
-		limit:         1,
-		stats:         newStoreStaticstics(),
-		types:         []BalanceType{hotReadRegionBalance},
-		r:             rand.New(rand.NewSource(time.Now().UnixNano())),
+		baseScheduler:   base,
+		hotRegionConfig: newHotRegionConfig(),
+		limit:           1,
+		stats:           newStoreStaticstics(),
+		types:           []BalanceType{hotReadRegionBalance},
+		r:               rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 }
 
 func newBalanceHotWriteRegionsScheduler(opController *schedule.OperatorController) *balanceHotRegionsScheduler {
 	base := newBaseScheduler(opController)
 	return &balanceHotRegionsScheduler{
-		baseScheduler: base,
-		limit:         1,
-		stats:         newStoreStaticstics(),
-		types:         []BalanceType{hotWriteRegionBalance},
-		r:             rand.New(rand.NewSource(time.Now().UnixNano())),
+		baseScheduler:   base,
+		hotRegionConfig: newHotRegionConfig(),
+		limit:           1,
+		stats:           newStoreStaticstics(),
+		types:           []BalanceType{hotWriteRegionBalance},
+		r:               rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 }
 
@@ -201,8 +205,12 @@ func (h *balanceHotRegionsScheduler) balanceHotWriteRegions(cluster schedule.Clu
 
 func (h *balanceHotRegionsScheduler) calcScore(items []*core.RegionStat, cluster schedule.Cluster, kind core.ResourceKind) core.StoreHotRegionsStat {
 	stats := make(core.StoreHotRegionsStat)
+	minHotDegree := h.get().MinHotDegree
+	if minHotDegree == 0 {
+		minHotDegree = cluster.GetHotRegionLowThreshold()
+	}
 	for _, r := range items {
-		if r.HotDegree < cluster.GetHotRegionLowThreshold() {
+		if r.HotDegree < minHotDegree {
 			continue
 		}
 
@@ -272,6 +280,7 @@ func (h *balanceHotRegionsScheduler) balanceByPeer(cluster schedule.Cluster, sto
 		srcStore := cluster.GetStore(srcStoreID)
 		filters := []schedule.Filter{
 			schedule.StoreStateFilter{MoveRegion: true},
+			schedule.NewPendingCompactionFilter(),
 			schedule.NewExcludedFilter(srcRegion.GetStoreIds(), srcRegion.GetStoreIds()),
 			schedule.NewDistinctScoreFilter(cluster.GetLocationLabels(), cluster.GetRegionStores(srcRegion), srcStore),
 		}
@@ -375,6 +384,7 @@ func (h *balanceHotRegionsScheduler) selectDestStore(candidateStoreIDs []uint64,
 	sr := storesStat[srcStoreID]
 	srcFlowBytes := sr.TotalFlowBytes
 	srcHotRegionsCount := sr.RegionsStat.Len()
+	scheduleFactor := h.get().ScheduleFactor
 
 	var (
 		minFlowBytes    uint64 = math.MaxUint64
@@ -389,7 +399,7 @@ func (h *balanceHotRegionsScheduler) selectDestStore(candidateStoreIDs []uint64,
 				continue
 			}
 			if minRegionsCount == s.RegionsStat.Len() && minFlowBytes > s.TotalFlowBytes &&
-				uint64(float64(srcFlowBytes)*hotRegionScheduleFactor) > s.TotalFlowBytes+2*regionFlowBytes {
+				uint64(float64(srcFlowBytes)*scheduleFactor) > s.TotalFlowBytes+2*regionFlowBytes {
 				minFlowBytes = s.TotalFlowBytes
 				destStoreID = storeID
 			}
@@ -410,8 +420,8 @@ func (h *balanceHotRegionsScheduler) adjustBalanceLimit(storeID uint64, storesSt
 	}
 
 	avgRegionCount := hotRegionTotalCount / float64(len(storesStat))
-	// Multiplied by hotRegionLimitFactor to avoid transfer back and forth
-	limit := uint64((float64(srcStoreStatistics.RegionsStat.Len()) - avgRegionCount) * hotRegionLimitFactor)
+	// Multiplied by the limit factor to avoid transfer back and forth
+	limit := uint64((float64(srcStoreStatistics.RegionsStat.Len()) - avgRegionCount) * h.get().LimitFactor)
 	h.limit = maxUint64(1, limit)
 }
 
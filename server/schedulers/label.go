@@ -55,7 +55,8 @@ func (s *labelScheduler) Schedule(cluster schedule.Cluster) []*schedule.Operator
 	stores := cluster.GetStores()
 	rejectLeaderStores := make(map[uint64]struct{})
 	for _, s := range stores {
-		if cluster.CheckLabelProperty(schedule.RejectLeader, s.Labels) {
+		if cluster.CheckLabelProperty(schedule.RejectLeader, s.Labels) ||
+			cluster.CheckLabelProperty(schedule.WitnessReplica, s.Labels) {
 			rejectLeaderStores[s.GetId()] = struct{}{}
 		}
 	}
@@ -15,30 +15,34 @@ package schedulers
 
 import (
 	"math/rand"
+	"time"
 
 	"github.com/pingcap/pd/server/core"
 	"github.com/pingcap/pd/server/schedule"
 )
 
 func init() {
-	schedule.RegisterScheduler("random-merge", func(opController *schedule.OperatorController, args []string) (schedule.Scheduler, error) {
-		return newRandomMergeScheduler(opController), nil
-	})
+	schedule.RegisterSchedulerWithArgs("random-merge", func(opController *schedule.OperatorController, args []string) (schedule.Scheduler, error) {
+		interval, err := parseDebugSchedulerArgs("random-merge", args)
+		if err != nil {
+			return nil, err
+		}
+		return newRandomMergeScheduler(opController, interval), nil
+	}, debugSchedulerArgs)
 }
 
 type randomMergeScheduler struct {
-	*baseScheduler
+	*debugScheduler
 	selector *schedule.RandomSelector
 }
 
 // newRandomMergeScheduler creates an admin scheduler that shuffles regions
 // between stores.
-func newRandomMergeScheduler(opController *schedule.OperatorController) schedule.Scheduler {
+func newRandomMergeScheduler(opController *schedule.OperatorController, interval time.Duration) schedule.Scheduler {
 	filters := []schedule.Filter{schedule.StoreStateFilter{MoveRegion: true}}
-	base := newBaseScheduler(opController)
 	return &randomMergeScheduler{
-		baseScheduler: base,
-		selector:      schedule.NewRandomSelector(filters),
+		debugScheduler: newDebugScheduler(opController, interval),
+		selector:       schedule.NewRandomSelector(filters),
 	}
 }
 
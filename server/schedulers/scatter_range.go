@@ -14,6 +14,7 @@
 package schedulers
 
 import (
+	"bytes"
 	"fmt"
 	"net/url"
 
@@ -22,7 +23,7 @@ import (
 )
 
 func init() {
-	schedule.RegisterScheduler("scatter-range", func(opController *schedule.OperatorController, args []string) (schedule.Scheduler, error) {
+	schedule.RegisterSchedulerWithArgs("scatter-range", func(opController *schedule.OperatorController, args []string) (schedule.Scheduler, error) {
 		if len(args) != 3 {
 			return nil, errors.New("should specify the range and the name")
 		}
@@ -35,7 +36,17 @@ func init() {
 			return nil, err
 		}
 		name := args[2]
+		if name == "" {
+			return nil, errors.New("the range name should not be empty")
+		}
+		if len(endKey) > 0 && bytes.Compare([]byte(startKey), []byte(endKey)) >= 0 {
+			return nil, errors.Errorf("the start key %q should be less than the end key %q", startKey, endKey)
+		}
 		return newScatterRangeScheduler(opController, []string{startKey, endKey, name}), nil
+	}, []schedule.SchedulerArg{
+		{Name: "start-key", Description: "the url escaped start key of the range"},
+		{Name: "end-key", Description: "the url escaped end key of the range"},
+		{Name: "range-name", Description: "the name of the range"},
 	})
 }
 
@@ -48,8 +59,8 @@ type scatterRangeScheduler struct {
 	balanceRegion schedule.Scheduler
 }
 
-// newScatterRangeScheduler creates a scheduler that tends to keep leaders on
-// each store balanced.
+// newScatterRangeScheduler creates a scheduler that keeps the leaders and the
+// peers of the regions in the named range balanced on each store.
 func newScatterRangeScheduler(opController *schedule.OperatorController, args []string) schedule.Scheduler {
 	base := newBaseScheduler(opController)
 	return &scatterRangeScheduler{
@@ -14,6 +14,8 @@
 package schedulers
 
 import (
+	"time"
+
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/pd/pkg/testutil"
@@ -46,6 +48,8 @@ func (s *testShuffleLeaderSuite) TestShuffle(c *C) {
 	tc.AddLeaderRegion(3, 3, 4, 1, 2)
 	tc.AddLeaderRegion(4, 4, 1, 2, 3)
 
+	c.Assert(sl.GetMinInterval(), Equals, MinScheduleInterval)
+
 	for i := 0; i < 4; i++ {
 		op := sl.Schedule(tc)
 		c.Assert(op, NotNil)
@@ -53,6 +57,19 @@ func (s *testShuffleLeaderSuite) TestShuffle(c *C) {
 	}
 }
 
+func (s *testShuffleLeaderSuite) TestInterval(c *C) {
+	oc := schedule.NewOperatorController(nil, nil)
+	sl, err := schedule.CreateScheduler("shuffle-leader", oc, "10s")
+	c.Assert(err, IsNil)
+	c.Assert(sl.GetMinInterval(), Equals, 10*time.Second)
+	c.Assert(sl.GetNextInterval(10*time.Second), Equals, 10*time.Second)
+
+	_, err = schedule.CreateScheduler("shuffle-leader", oc, "-1s")
+	c.Assert(err, NotNil)
+	_, err = schedule.CreateScheduler("random-merge", oc, "10s", "20s")
+	c.Assert(err, NotNil)
+}
+
 var _ = Suite(&testBalanceAdjacentRegionSuite{})
 
 type testBalanceAdjacentRegionSuite struct{}
@@ -272,3 +289,97 @@ func (s *testRejectLeaderSuite) TestRejectLeader(c *C) {
 	op = sl.Schedule(tc)
 	testutil.CheckTransferLeader(c, op[0], schedule.OpLeader, 1, 2)
 }
+
+func (s *testRejectLeaderSuite) TestWitnessStore(c *C) {
+	opt := schedule.NewMockSchedulerOptions()
+	opt.LabelProperties = map[string][]*metapb.StoreLabel{
+		schedule.WitnessReplica: {{Key: "zone", Value: "z3"}},
+	}
+	tc := schedule.NewMockCluster(opt)
+	tc.AddLabelsStore(1, 1, map[string]string{"zone": "z3"})
+	tc.AddLeaderStore(2, 10)
+	tc.AddLeaderStore(3, 0)
+	tc.AddLeaderRegion(1, 1, 2, 3)
+
+	// The label scheduler transfers leader out of the witness store.
+	oc := schedule.NewOperatorController(nil, nil)
+	sl, err := schedule.CreateScheduler("label", oc)
+	c.Assert(err, IsNil)
+	op := sl.Schedule(tc)
+	testutil.CheckTransferLeader(c, op[0], schedule.OpLeader, 1, 3)
+	c.Assert(schedule.NewRejectLeaderFilter().FilterTarget(tc, tc.GetStore(1)), IsTrue)
+}
+
+func (s *testRejectLeaderSuite) TestRejectLeaderTargets(c *C) {
+	opt := schedule.NewMockSchedulerOptions()
+	opt.LabelProperties = map[string][]*metapb.StoreLabel{
+		schedule.RejectLeader: {{Key: "noleader", Value: "true"}},
+	}
+	tc := schedule.NewMockCluster(opt)
+
+	// Add stores 1~3, and stores 4~6 which reject leaders.
+	for i := uint64(1); i <= 3; i++ {
+		tc.AddRegionStore(i, 0)
+	}
+	for i := uint64(4); i <= 6; i++ {
+		tc.AddLabelsStore(i, 0, map[string]string{"noleader": "true"})
+	}
+	tc.AddLeaderRegion(1, 1, 2, 3)
+	tc.AddLeaderRegion(2, 1, 2, 3)
+
+	// The scatterer moves the peers of region 2 to stores 4~6 without
+	// transferring leader to them.
+	scatterer := schedule.NewRegionScatterer(tc, namespace.DefaultClassifier)
+	c.Assert(scatterer.Scatter(tc.GetRegion(1)), IsNil)
+	op := scatterer.Scatter(tc.GetRegion(2))
+	c.Assert(op, NotNil)
+	for i := 0; i < op.Len(); i++ {
+		if step, ok := op.Step(i).(schedule.TransferLeader); ok {
+			c.Assert(step.ToStore, Less, uint64(4))
+		}
+	}
+
+	// Can't grant leaders to store 4.
+	oc := schedule.NewOperatorController(nil, nil)
+	gl, err := schedule.CreateScheduler("grant-leader", oc, "4")
+	c.Assert(err, IsNil)
+	c.Assert(gl.Prepare(tc), NotNil)
+	c.Assert(gl.Schedule(tc), IsNil)
+	gl, err = schedule.CreateScheduler("grant-leader", oc, "2")
+	c.Assert(err, IsNil)
+	c.Assert(gl.Prepare(tc), IsNil)
+	testutil.CheckTransferLeader(c, gl.Schedule(tc)[0], schedule.OpLeader, 1, 2)
+}
+
+var _ = Suite(&testHotRegionConfigSuite{})
+
+type testHotRegionConfigSuite struct{}
+
+func (s *testHotRegionConfigSuite) TestConfig(c *C) {
+	hb, err := schedule.CreateScheduler("hot-region", schedule.NewOperatorController(nil, nil))
+	c.Assert(err, IsNil)
+	p := hb.(schedule.ConfigProvider)
+	c.Assert(p.GetConfig(), DeepEquals, &hotRegionSchedulerConfig{
+		LimitFactor:    defaultHotRegionLimitFactor,
+		ScheduleFactor: defaultHotRegionScheduleFactor,
+	})
+
+	for _, data := range []string{
+		`{"min-hot-degree": -1}`,
+		`{"limit-factor": 0}`,
+		`{"limit-factor": 1.5}`,
+		`{"schedule-factor": -0.5}`,
+		`{"unknown": 1}`,
+		`not json`,
+	} {
+		c.Assert(p.SetConfig([]byte(data)), NotNil, Commentf("%s", data))
+	}
+
+	// The absent fields keep their values.
+	c.Assert(p.SetConfig([]byte(`{"min-hot-degree": 5, "limit-factor": 0.5}`)), IsNil)
+	c.Assert(p.GetConfig(), DeepEquals, &hotRegionSchedulerConfig{
+		MinHotDegree:   5,
+		LimitFactor:    0.5,
+		ScheduleFactor: defaultHotRegionScheduleFactor,
+	})
+}
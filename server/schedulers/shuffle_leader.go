@@ -14,29 +14,34 @@
 package schedulers
 
 import (
+	"time"
+
 	"github.com/pingcap/pd/server/core"
 	"github.com/pingcap/pd/server/schedule"
 )
 
 func init() {
-	schedule.RegisterScheduler("shuffle-leader", func(opController *schedule.OperatorController, args []string) (schedule.Scheduler, error) {
-		return newShuffleLeaderScheduler(opController), nil
-	})
+	schedule.RegisterSchedulerWithArgs("shuffle-leader", func(opController *schedule.OperatorController, args []string) (schedule.Scheduler, error) {
+		interval, err := parseDebugSchedulerArgs("shuffle-leader", args)
+		if err != nil {
+			return nil, err
+		}
+		return newShuffleLeaderScheduler(opController, interval), nil
+	}, debugSchedulerArgs)
 }
 
 type shuffleLeaderScheduler struct {
-	*baseScheduler
+	*debugScheduler
 	selector *schedule.RandomSelector
 }
 
 // newShuffleLeaderScheduler creates an admin scheduler that shuffles leaders
 // between stores.
-func newShuffleLeaderScheduler(opController *schedule.OperatorController) schedule.Scheduler {
+func newShuffleLeaderScheduler(opController *schedule.OperatorController, interval time.Duration) schedule.Scheduler {
 	filters := []schedule.Filter{schedule.StoreStateFilter{TransferLeader: true}}
-	base := newBaseScheduler(opController)
 	return &shuffleLeaderScheduler{
-		baseScheduler: base,
-		selector:      schedule.NewRandomSelector(filters),
+		debugScheduler: newDebugScheduler(opController, interval),
+		selector:       schedule.NewRandomSelector(filters),
 	}
 }
 
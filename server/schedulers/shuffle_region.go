@@ -14,6 +14,8 @@
 package schedulers
 
 import (
+	"time"
+
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/pd/server/core"
 	"github.com/pingcap/pd/server/schedule"
@@ -21,24 +23,27 @@ import (
 )
 
 func init() {
-	schedule.RegisterScheduler("shuffle-region", func(opController *schedule.OperatorController, args []string) (schedule.Scheduler, error) {
-		return newShuffleRegionScheduler(opController), nil
-	})
+	schedule.RegisterSchedulerWithArgs("shuffle-region", func(opController *schedule.OperatorController, args []string) (schedule.Scheduler, error) {
+		interval, err := parseDebugSchedulerArgs("shuffle-region", args)
+		if err != nil {
+			return nil, err
+		}
+		return newShuffleRegionScheduler(opController, interval), nil
+	}, debugSchedulerArgs)
 }
 
 type shuffleRegionScheduler struct {
-	*baseScheduler
+	*debugScheduler
 	selector *schedule.RandomSelector
 }
 
 // newShuffleRegionScheduler creates an admin scheduler that shuffles regions
 // between stores.
-func newShuffleRegionScheduler(opController *schedule.OperatorController) schedule.Scheduler {
+func newShuffleRegionScheduler(opController *schedule.OperatorController, interval time.Duration) schedule.Scheduler {
 	filters := []schedule.Filter{schedule.StoreStateFilter{MoveRegion: true}}
-	base := newBaseScheduler(opController)
 	return &shuffleRegionScheduler{
-		baseScheduler: base,
-		selector:      schedule.NewRandomSelector(filters),
+		debugScheduler: newDebugScheduler(opController, interval),
+		selector:       schedule.NewRandomSelector(filters),
 	}
 }
 
@@ -20,8 +20,33 @@ import (
 	"github.com/pingcap/pd/server/cache"
 	"github.com/pingcap/pd/server/core"
 	"github.com/pingcap/pd/server/schedule"
+	"github.com/pkg/errors"
 )
 
+// debugSchedulerArgs is the schema of the arguments of the debug schedulers.
+var debugSchedulerArgs = []schedule.SchedulerArg{
+	{Name: "interval", Description: "the interval between two schedules, such as 10s", Optional: true},
+}
+
+// parseDebugSchedulerArgs returns the schedule interval of the debug
+// schedulers, zero if it is not specified.
+func parseDebugSchedulerArgs(name string, args []string) (time.Duration, error) {
+	if len(args) == 0 {
+		return 0, nil
+	}
+	if len(args) > 1 {
+		return 0, errors.Errorf("%s needs at most 1 argument", name)
+	}
+	interval, err := time.ParseDuration(args[0])
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	if interval <= 0 {
+		return 0, errors.Errorf("invalid interval %s for %s", args[0], name)
+	}
+	return interval, nil
+}
+
 func minUint64(a, b uint64) uint64 {
 	if a < b {
 		return a
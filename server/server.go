@@ -15,9 +15,11 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/rand"
 	"net/http"
+	"os"
 	"path"
 	"path/filepath"
 	"strconv"
@@ -31,10 +33,26 @@ import (
 	"github.com/coreos/etcd/pkg/types"
 	"github.com/coreos/go-semver/semver"
 	"github.com/golang/protobuf/proto"
+	"github.com/pingcap/errcode"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/pkg/bandwidthpb"
+	"github.com/pingcap/pd/pkg/confchangepb"
+	"github.com/pingcap/pd/pkg/configpb"
+	"github.com/pingcap/pd/pkg/encryptionpb"
 	"github.com/pingcap/pd/pkg/etcdutil"
+	"github.com/pingcap/pd/pkg/extstorage"
+	"github.com/pingcap/pd/pkg/gcpb"
+	"github.com/pingcap/pd/pkg/idpb"
+	"github.com/pingcap/pd/pkg/keyspacepb"
 	"github.com/pingcap/pd/pkg/logutil"
+	"github.com/pingcap/pd/pkg/metricutil"
+	"github.com/pingcap/pd/pkg/regionpb"
+	"github.com/pingcap/pd/pkg/replicationpb"
+	"github.com/pingcap/pd/pkg/splitpb"
+	"github.com/pingcap/pd/pkg/storepb"
+	"github.com/pingcap/pd/pkg/watchpb"
+	"github.com/pingcap/pd/pkg/witnesspb"
 	"github.com/pingcap/pd/server/core"
 	"github.com/pingcap/pd/server/namespace"
 	"github.com/pkg/errors"
@@ -60,6 +78,10 @@ type Server struct {
 	// Server state.
 	isServing int64
 	leader    atomic.Value
+	// leaderLease is the lease of the leader key if the server is leader.
+	leaderLease atomic.Value
+	// resigning is set when the server transfers the etcd leader to resign.
+	resigning int32
 
 	// Configs and initial fields.
 	cfg         *Config
@@ -88,6 +110,9 @@ type Server struct {
 	// store, region and peer, because we just need
 	// a unique ID.
 	idAlloc *idAllocator
+	// timeIDAlloc allocates the IDs ordered by time, which are used by the
+	// clients embedding the creation order in IDs.
+	timeIDAlloc *timeIDAllocator
 	// for kv operation.
 	kv *core.KV
 	// for namespace.
@@ -95,10 +120,40 @@ type Server struct {
 	// for raft cluster
 	cluster *RaftCluster
 	// For tso, set after pd becomes leader.
-	ts            atomic.Value
-	lastSavedTime time.Time
+	ts atomic.Value
+	// lastSavedTime is the timestamp saved in etcd, the physical time of TSO
+	// never exceeds it.
+	lastSavedTime atomic.Value
+	// tsoSaving is closed when the window being saved in the background is
+	// saved, nil if there is no one.
+	tsoSaving chan struct{}
 	// For async region heartbeat.
 	hbStreams *heartbeatStreams
+	// For metadata snapshots in external storage.
+	metaSnapshots metaSnapshots
+	// For compacting and defragmenting etcd.
+	etcdMaintenance etcdMaintenance
+	// For configs of other components.
+	configManager *configManager
+	// For keyspaces.
+	keyspaceManager *keyspaceManager
+	// For GC safe points of the cluster and services.
+	gcSafePointManager *gcSafePointManager
+	// For maintenance mode.
+	maintenance *maintenanceManager
+	// For forwarding requests to the leader.
+	forwarder *forwarder
+	// For caching the metadata read from etcd on the leader.
+	metaCache *metaCache
+	// For the log of cluster events.
+	events *eventLog
+	// For asynchronous admin jobs.
+	jobs *jobManager
+	// For the data keys to encrypt the data at rest.
+	keys *keyManager
+	// For serving APIs on the listeners separated from etcd, nil if the APIs
+	// are served by the embed etcd.
+	apiServer *apiServer
 }
 
 // CreateServer creates the UNINITIALIZED pd server with given configuration.
@@ -109,6 +164,8 @@ func CreateServer(cfg *Config, apiRegister func(*Server) http.Handler) (*Server,
 	s := &Server{
 		cfg:         cfg,
 		scheduleOpt: newScheduleOption(cfg),
+		forwarder:   newForwarder(),
+		metaCache:   newMetaCache(),
 	}
 	s.handler = newHandler(s)
 
@@ -117,12 +174,23 @@ func CreateServer(cfg *Config, apiRegister func(*Server) http.Handler) (*Server,
 	if err != nil {
 		return nil, err
 	}
-	if apiRegister != nil {
-		etcdCfg.UserHandlers = map[string]http.Handler{
-			pdAPIPrefix: apiRegister(s),
+	if s.cfg.APIUrls != "" {
+		var handler http.Handler
+		if apiRegister != nil {
+			handler = apiRegister(s)
 		}
+		if s.apiServer, err = newAPIServer(s.cfg.APIUrls, s.cfg.Security, handler, grpc.UnaryInterceptor(s.slowLogUnaryInterceptor)); err != nil {
+			return nil, err
+		}
+		s.registerServices(s.apiServer.grpcServer)
+	} else {
+		if apiRegister != nil {
+			etcdCfg.UserHandlers = map[string]http.Handler{
+				pdAPIPrefix: apiRegister(s),
+			}
+		}
+		etcdCfg.ServiceRegister = s.registerServices
 	}
-	etcdCfg.ServiceRegister = func(gs *grpc.Server) { pdpb.RegisterPDServer(gs, s) }
 	s.etcdCfg = etcdCfg
 	if EnableZap {
 		// The etcd master version has removed embed.Config.SetupLogging.
@@ -134,6 +202,23 @@ func CreateServer(cfg *Config, apiRegister func(*Server) http.Handler) (*Server,
 	return s, nil
 }
 
+func (s *Server) registerServices(gs *grpc.Server) {
+	pdpb.RegisterPDServer(gs, s)
+	watchpb.RegisterWatchServer(gs, s)
+	configpb.RegisterConfigServer(gs, &configService{s: s})
+	keyspacepb.RegisterKeyspaceServer(gs, &keyspaceService{s: s})
+	gcpb.RegisterGCServer(gs, &gcService{s: s})
+	idpb.RegisterIDServer(gs, &idService{s: s})
+	storepb.RegisterStoreServer(gs, &storeService{s: s})
+	splitpb.RegisterSplitServer(gs, &splitService{s: s})
+	encryptionpb.RegisterKeyManagerServer(gs, &keyManagerService{s: s})
+	replicationpb.RegisterReplicationServer(gs, &replicationService{s: s})
+	bandwidthpb.RegisterBandwidthServer(gs, &bandwidthService{s: s})
+	witnesspb.RegisterWitnessServer(gs, &witnessService{s: s})
+	confchangepb.RegisterConfChangeServer(gs, &confChangeService{s: s})
+	regionpb.RegisterRegionServer(gs, &regionService{s: s})
+}
+
 func (s *Server) startEtcd(ctx context.Context) error {
 	log.Info("start embed etcd")
 	ctx, cancel := context.WithTimeout(ctx, etcdStartTimeout)
@@ -164,13 +249,14 @@ func (s *Server) startEtcd(ctx context.Context) error {
 		return errors.Errorf("canceled when waiting embed etcd to be ready")
 	}
 
-	endpoints := []string{s.etcdCfg.ACUrls[0].String()}
+	endpoints := s.localEtcdEndpoints()
 	log.Infof("create etcd v3 client with endpoints %v", endpoints)
 
 	client, err := clientv3.New(clientv3.Config{
 		Endpoints:   endpoints,
 		DialTimeout: etcdTimeout,
 		TLS:         tlsConfig,
+		DialOptions: []grpc.DialOption{grpc.WithUnaryInterceptor(etcdClientInterceptor)},
 	})
 	if err != nil {
 		return errors.WithStack(err)
@@ -193,6 +279,10 @@ func (s *Server) startEtcd(ctx context.Context) error {
 		}
 	}
 
+	// Use the client urls of all members, so that the server can still access
+	// etcd when the local etcd listener has issues.
+	client.SetEndpoints(pickEtcdEndpoints(endpoints, memberClientURLs(etcdMembers), func(string) bool { return true })...)
+
 	s.etcd = etcd
 	s.client = client
 	s.id = etcdServerID
@@ -211,20 +301,38 @@ func (s *Server) startServer() error {
 
 	s.rootPath = path.Join(pdRootPath, strconv.FormatUint(s.clusterID, 10))
 	s.member, s.memberValue = s.memberInfo()
+	if err = s.saveMemberDeployInfo(); err != nil {
+		return err
+	}
 
 	s.idAlloc = &idAllocator{s: s}
+	s.timeIDAlloc = &timeIDAllocator{s: s}
 	kvBase := newEtcdKVBase(s)
 	path := filepath.Join(s.cfg.DataDir, "region-meta")
-	regionKV, err := core.NewRegionKV(path)
+	regionKV, err := core.NewRegionKVWithOptions(path, s.cfg.RegionStorage.options())
 	if err != nil {
 		return err
 	}
 	s.kv = core.NewKV(kvBase).SetRegionKV(regionKV)
+	s.configManager = newConfigManager(s.kv)
+	s.keyspaceManager = newKeyspaceManager(s.kv)
+	s.gcSafePointManager = newGCSafePointManager(s.kv)
+	s.maintenance = newMaintenanceManager(s.kv)
+	s.jobs = newJobManager(s)
+	s.events = newEventLog(s.kv, s.cfg.EventLog)
+	if s.keys, err = newKeyManager(s.kv, s.idAlloc, s.cfg.Encryption); err != nil {
+		return err
+	}
 	s.cluster = newRaftCluster(s, s.clusterID)
 	s.hbStreams = newHeartbeatStreams(s.clusterID)
 	if s.classifier, err = namespace.CreateClassifier(s.cfg.NamespaceClassifier, s.kv, s.idAlloc); err != nil {
 		return err
 	}
+	if s.cfg.MetaSnapshot.Storage != "" {
+		if s.metaSnapshots.storage, err = extstorage.Create(s.cfg.MetaSnapshot.Storage); err != nil {
+			return err
+		}
+	}
 
 	// Server has started.
 	atomic.StoreInt64(&s.isServing, 1)
@@ -233,14 +341,18 @@ func (s *Server) startServer() error {
 
 func (s *Server) initClusterID() error {
 	// Get any cluster key to parse the cluster ID.
-	resp, err := kvGet(s.client, pdClusterIDPath)
+	ctx, cancel := s.readContext(s.client.Ctx())
+	resp, err := kvGet(ctx, s.client, pdClusterIDPath)
+	cancel()
 	if err != nil {
 		return err
 	}
 
 	// If no key exist, generate a random cluster ID.
 	if len(resp.Kvs) == 0 {
-		s.clusterID, err = initOrGetClusterID(s.client, pdClusterIDPath)
+		ctx, cancel = context.WithTimeout(s.client.Ctx(), s.cfg.EtcdWriteTimeout.Duration)
+		defer cancel()
+		s.clusterID, err = initOrGetClusterID(ctx, s.client, pdClusterIDPath)
 		return err
 	}
 	s.clusterID, err = bytesToUint64(resp.Kvs[0].Value)
@@ -249,6 +361,8 @@ func (s *Server) initClusterID() error {
 
 // Close closes the server.
 func (s *Server) Close() {
+	// It must be checked before the server is marked closed.
+	isLeader := s.IsLeader()
 	if !atomic.CompareAndSwapInt64(&s.isServing, 1, 0) {
 		// server is already closed
 		return
@@ -256,8 +370,13 @@ func (s *Server) Close() {
 
 	log.Info("closing server")
 
+	s.drain(isLeader)
 	s.stopServerLoop()
 
+	if s.apiServer != nil {
+		s.apiServer.close()
+	}
+
 	if s.client != nil {
 		s.client.Close()
 	}
@@ -269,6 +388,7 @@ func (s *Server) Close() {
 	if s.hbStreams != nil {
 		s.hbStreams.Close()
 	}
+	s.forwarder.close("")
 	if err := s.kv.Close(); err != nil {
 		log.Errorf("close kv meet error: %s", err)
 	}
@@ -276,6 +396,74 @@ func (s *Server) Close() {
 	log.Info("close server")
 }
 
+// drain lets the clients move to the next leader smoothly before the server
+// is torn down. The new requests are already rejected with NotLeader since the
+// server is marked closed. The leader resigns and deletes its leader key,
+// flushes the region storage, and waits for the heartbeat responses in the
+// queues to be sent.
+func (s *Server) drain(isLeader bool) {
+	if !isLeader {
+		return
+	}
+	timeout := s.cfg.DrainTimeout.Duration
+	log.Infof("%s is draining before closed", s.Name())
+
+	ctx, cancel := context.WithTimeout(s.serverLoopCtx, timeout)
+	nextLeader, err := s.pickDrainNextLeader(ctx)
+	if err == nil {
+		err = s.resignLeader(ctx, nextLeader)
+	}
+	cancel()
+	if err != nil {
+		log.Warnf("failed to resign leader before closed: %v", err)
+	} else {
+		// The leader loop steps down once the etcd leader moves.
+		deadline := time.Now().Add(timeout)
+		for s.GetLeaderID() == s.ID() && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		// The next leader campaigns at once, rather than after the lease
+		// expires.
+		if err = s.deleteLeaderKey(); err != nil {
+			log.Warnf("failed to delete leader key before closed: %v", err)
+		}
+	}
+
+	if err = s.kv.Flush(); err != nil {
+		log.Errorf("failed to flush region storage: %v", err)
+	}
+
+	if s.hbStreams != nil {
+		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+		err = s.hbStreams.drain(ctx)
+		cancel()
+		if err != nil {
+			log.Warnf("failed to send the queued heartbeat responses before closed: %v", err)
+		}
+	}
+	log.Infof("%s is drained", s.Name())
+}
+
+// pickDrainNextLeader returns a healthy member to take over the leadership.
+// It returns an error if the other healthy members can not form a quorum, the
+// cluster is unavailable after this member is closed anyway.
+func (s *Server) pickDrainNextLeader(ctx context.Context) (string, error) {
+	members, err := s.getEtcdMemberStatus(ctx)
+	if err != nil {
+		return "", err
+	}
+	var healthy []string
+	for _, m := range members {
+		if m.MemberID != s.ID() && m.healthy() {
+			healthy = append(healthy, m.Name)
+		}
+	}
+	if len(healthy) < len(members)/2+1 {
+		return "", errors.Errorf("%d healthy members of %d can not form a quorum", len(healthy), len(members))
+	}
+	return healthy[0], nil
+}
+
 // isClosed checks whether server is closed or not.
 func (s *Server) isClosed() bool {
 	return atomic.LoadInt64(&s.isServing) == 0
@@ -300,6 +488,12 @@ func (s *Server) Run(ctx context.Context) error {
 		return err
 	}
 
+	if s.apiServer != nil {
+		if err := s.apiServer.start(); err != nil {
+			return err
+		}
+	}
+
 	s.startServerLoop()
 
 	return nil
@@ -312,10 +506,14 @@ func (s *Server) Context() context.Context {
 
 func (s *Server) startServerLoop() {
 	s.serverLoopCtx, s.serverLoopCancel = context.WithCancel(context.Background())
-	s.serverLoopWg.Add(3)
+	s.serverLoopWg.Add(7)
 	go s.leaderLoop()
 	go s.etcdLeaderLoop()
 	go s.serverMetricsLoop()
+	go s.metricsPushLoop()
+	go s.metaSnapshotLoop()
+	go s.etcdEndpointsLoop()
+	go s.etcdMaintenanceLoop()
 }
 
 func (s *Server) stopServerLoop() {
@@ -340,18 +538,115 @@ func (s *Server) serverMetricsLoop() {
 	}
 }
 
+// metricsPushLoop pushes the metrics periodically if the push mode is
+// configured, in addition to the metrics pulled from the API.
+func (s *Server) metricsPushLoop() {
+	defer logutil.LogPanic()
+	defer s.serverLoopWg.Done()
+
+	cfg := &s.cfg.Metric
+	if !cfg.IsPushEnabled() {
+		return
+	}
+	pusher, err := metricutil.NewPusher(cfg)
+	if err != nil {
+		log.Errorf("failed to create metrics pusher: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(s.serverLoopCtx)
+	defer cancel()
+	ticker := time.NewTicker(cfg.PushInterval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := pusher.Push(); err != nil {
+				log.Errorf("failed to push metrics to %s: %v", cfg.PushAddress, err)
+			}
+		case <-ctx.Done():
+			log.Info("server is closed, exit metrics push loop")
+			return
+		}
+	}
+}
+
 func (s *Server) collectEtcdStateMetrics() {
 	etcdStateGauge.WithLabelValues("term").Set(float64(s.etcd.Server.Term()))
 	etcdStateGauge.WithLabelValues("appliedIndex").Set(float64(s.etcd.Server.AppliedIndex()))
 	etcdStateGauge.WithLabelValues("committedIndex").Set(float64(s.etcd.Server.CommittedIndex()))
 }
 
-func (s *Server) bootstrapCluster(req *pdpb.BootstrapRequest) (*pdpb.BootstrapResponse, error) {
+// PrepareBootstrap checks whether the cluster can be bootstrapped with the
+// request, without bootstrapping it.
+func (s *Server) PrepareBootstrap(req *pdpb.BootstrapRequest) error {
+	if s.GetRaftCluster() != nil {
+		return errors.WithStack(core.AlreadyBootstrappedErr{ClusterID: s.clusterID})
+	}
+	if err := s.validateBootstrapRequest(req); err != nil {
+		return errcode.NewInvalidInputErr(err)
+	}
+	return nil
+}
+
+// validateBootstrapRequest checks the metadata in the request, the store
+// address, the store version and the replication config.
+func (s *Server) validateBootstrapRequest(req *pdpb.BootstrapRequest) error {
+	if err := checkBootstrapRequest(s.clusterID, req); err != nil {
+		return err
+	}
+
+	store := req.GetStore()
+	if store.GetAddress() == "" {
+		return errors.Errorf("missing store address for bootstrap %d", s.clusterID)
+	}
+	members, err := s.ListMembers()
+	if err != nil {
+		return err
+	}
+	for _, m := range members {
+		for _, u := range append(m.GetClientUrls(), m.GetPeerUrls()...) {
+			if strings.HasSuffix(u, "://"+store.GetAddress()) {
+				return errors.Errorf("store address %s is used by PD member %s", store.GetAddress(), m.GetName())
+			}
+		}
+	}
+
+	v, err := ParseVersion(store.GetVersion())
+	if err != nil {
+		return errors.Errorf("invalid store version %s for bootstrap %d", store.GetVersion(), s.clusterID)
+	}
+	if clusterVersion := s.scheduleOpt.loadClusterVersion(); !IsCompatible(clusterVersion, *v) {
+		return errors.Errorf("store version %s is not compatible with cluster version %s", v, clusterVersion)
+	}
+
+	rep := s.scheduleOpt.rep.load()
+	if rep.MaxReplicas == 0 {
+		return errors.New("invalid zero max replicas")
+	}
+	return rep.validate()
+}
+
+// isBootstrapRetry returns true if the cluster was bootstrapped by the same
+// store and region, which happens when TiKV retries after a timeout.
+func (s *Server) isBootstrapRetry(req *pdpb.BootstrapRequest) (bool, error) {
+	store := &metapb.Store{}
+	ok, err := s.kv.LoadStore(req.GetStore().GetId(), store)
+	if err != nil || !ok || store.GetAddress() != req.GetStore().GetAddress() {
+		return false, err
+	}
+	return s.kv.LoadRegion(req.GetRegion().GetId(), &metapb.Region{})
+}
+
+// bootstrapCluster bootstraps the cluster and returns its metadata. If the
+// cluster is already bootstrapped, the existing metadata is returned with an
+// AlreadyBootstrappedErr.
+func (s *Server) bootstrapCluster(req *pdpb.BootstrapRequest) (*metapb.Cluster, error) {
 	clusterID := s.clusterID
 
 	log.Infof("try to bootstrap raft cluster %d with %v", clusterID, req)
 
-	if err := checkBootstrapRequest(clusterID, req); err != nil {
+	if err := s.validateBootstrapRequest(req); err != nil {
 		return nil, err
 	}
 
@@ -403,10 +698,15 @@ func (s *Server) bootstrapCluster(req *pdpb.BootstrapRequest) (*pdpb.BootstrapRe
 	}
 	if !resp.Succeeded {
 		log.Warnf("cluster %d already bootstrapped", clusterID)
-		return nil, errors.Errorf("cluster %d already bootstrapped", clusterID)
+		meta := &metapb.Cluster{}
+		if _, err := s.kv.LoadMeta(meta); err != nil {
+			return nil, err
+		}
+		return meta, errors.WithStack(core.AlreadyBootstrappedErr{ClusterID: clusterID})
 	}
 
 	log.Infof("bootstrap cluster %d ok", clusterID)
+	s.metaCache.invalidate(metaCacheKey(metaCacheClusterStatus, clusterID))
 	err = s.kv.SaveRegion(req.GetRegion())
 	if err != nil {
 		log.Warnf("save the bootstrap region failed: %s", err)
@@ -419,7 +719,7 @@ func (s *Server) bootstrapCluster(req *pdpb.BootstrapRequest) (*pdpb.BootstrapRe
 		return nil, err
 	}
 
-	return &pdpb.BootstrapResponse{}, nil
+	return &clusterMeta, nil
 }
 
 func (s *Server) createRaftCluster() error {
@@ -427,6 +727,7 @@ func (s *Server) createRaftCluster() error {
 		return nil
 	}
 
+	s.loadSchedulerPlugins()
 	return s.cluster.start()
 }
 
@@ -436,6 +737,9 @@ func (s *Server) stopRaftCluster() {
 
 // GetAddr returns the server urls for clients.
 func (s *Server) GetAddr() string {
+	if s.cfg.AdvertiseAPIUrls != "" {
+		return s.cfg.AdvertiseAPIUrls
+	}
 	return s.cfg.AdvertiseClientUrls
 }
 
@@ -479,16 +783,33 @@ func (s *Server) ClusterID() uint64 {
 	return s.clusterID
 }
 
+// readContext derives a context with the etcd read timeout from ctx.
+func (s *Server) readContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, s.cfg.EtcdReadTimeout.Duration)
+}
+
 // txn returns an etcd client transaction wrapper.
 // The wrapper will set a request timeout to the context and log slow transactions.
 func (s *Server) txn() clientv3.Txn {
-	return newSlowLogTxn(s.client)
+	return s.txnWithContext(s.client.Ctx())
+}
+
+// txnWithContext is like txn, but the transaction is also canceled when ctx
+// is done.
+func (s *Server) txnWithContext(ctx context.Context) clientv3.Txn {
+	return newSlowLogTxn(ctx, s.client, s.cfg.EtcdWriteTimeout.Duration)
 }
 
 // leaderTxn returns txn() with a leader comparison to guarantee that
 // the transaction can be executed only if the server is leader.
 func (s *Server) leaderTxn(cs ...clientv3.Cmp) clientv3.Txn {
-	return s.txn().If(append(cs, s.leaderCmp())...)
+	return s.leaderTxnWithContext(s.client.Ctx(), cs...)
+}
+
+// leaderTxnWithContext is like leaderTxn, but the transaction is also
+// canceled when ctx is done.
+func (s *Server) leaderTxnWithContext(ctx context.Context, cs ...clientv3.Cmp) clientv3.Txn {
+	return s.txnWithContext(ctx).If(append(cs, s.leaderCmp())...)
 }
 
 // GetConfig gets the config information.
@@ -502,7 +823,9 @@ func (s *Server) GetConfig() *Config {
 	}
 	cfg.Namespace = namespaces
 	cfg.LabelProperty = s.scheduleOpt.loadLabelPropertyConfig().clone()
+	cfg.MergeProtection = s.scheduleOpt.loadMergeProtectionConfig().clone()
 	cfg.ClusterVersion = s.scheduleOpt.loadClusterVersion()
+	cfg.PDServerCfg = *s.scheduleOpt.loadPDServerConfig()
 	return cfg
 }
 
@@ -537,7 +860,7 @@ func (s *Server) GetReplicationConfig() *ReplicationConfig {
 // SetReplicationConfig sets the replication config.
 func (s *Server) SetReplicationConfig(cfg ReplicationConfig) error {
 	if err := cfg.validate(); err != nil {
-		return err
+		return errcode.NewInvalidInputErr(err)
 	}
 	old := s.scheduleOpt.rep.load()
 	s.scheduleOpt.rep.store(&cfg)
@@ -549,20 +872,37 @@ func (s *Server) SetReplicationConfig(cfg ReplicationConfig) error {
 	return nil
 }
 
-// GetNamespaceConfig get the namespace config.
-func (s *Server) GetNamespaceConfig(name string) *NamespaceConfig {
-	if _, ok := s.scheduleOpt.ns[name]; !ok {
-		return &NamespaceConfig{}
+// SetPDServerConfig sets the PD server config.
+func (s *Server) SetPDServerConfig(cfg PDServerConfig) error {
+	if err := cfg.validate(); err != nil {
+		return err
 	}
-
-	cfg := &NamespaceConfig{
-		LeaderScheduleLimit:  s.scheduleOpt.GetLeaderScheduleLimit(name),
-		RegionScheduleLimit:  s.scheduleOpt.GetRegionScheduleLimit(name),
-		ReplicaScheduleLimit: s.scheduleOpt.GetReplicaScheduleLimit(name),
-		MaxReplicas:          uint64(s.scheduleOpt.GetMaxReplicas(name)),
+	old := s.scheduleOpt.loadPDServerConfig()
+	s.scheduleOpt.pdServerConfig.Store(&cfg)
+	if err := s.scheduleOpt.persist(s.kv); err != nil {
+		return err
 	}
+	log.Infof("pd server config is updated: %+v, old: %+v", cfg, old)
+	return nil
+}
 
-	return cfg
+// GetNamespaceConfig get the namespace config.
+func (s *Server) GetNamespaceConfig(name string) *NamespaceConfig {
+	v, _ := s.metaCache.load(metaCacheNamespaceConfig, metaCacheKey(metaCacheNamespaceConfig, name), func() (interface{}, error) {
+		if _, ok := s.scheduleOpt.ns[name]; !ok {
+			return &NamespaceConfig{}, nil
+		}
+		return &NamespaceConfig{
+			LeaderScheduleLimit:  s.scheduleOpt.GetLeaderScheduleLimit(name),
+			RegionScheduleLimit:  s.scheduleOpt.GetRegionScheduleLimit(name),
+			ReplicaScheduleLimit: s.scheduleOpt.GetReplicaScheduleLimit(name),
+			MaxReplicas:          uint64(s.scheduleOpt.GetMaxReplicas(name)),
+			NamespaceQuota:       s.scheduleOpt.GetNamespaceQuota(name),
+		}, nil
+	})
+	// The config is adjusted by the callers, so a copy is returned.
+	cfg := *v.(*NamespaceConfig)
+	return &cfg
 }
 
 // GetNamespaceConfigWithAdjust get the namespace config that replace zero value with global config value.
@@ -584,6 +924,7 @@ func (s *Server) SetNamespaceConfig(name string, cfg NamespaceConfig) {
 		s.scheduleOpt.persist(s.kv)
 		log.Infof("namespace:%v config is added: %+v", name, cfg)
 	}
+	s.metaCache.invalidate(metaCacheKey(metaCacheNamespaceConfig, name))
 }
 
 // DeleteNamespaceConfig deletes the namespace config.
@@ -592,14 +933,19 @@ func (s *Server) DeleteNamespaceConfig(name string) {
 		cfg := n.load()
 		delete(s.scheduleOpt.ns, name)
 		s.scheduleOpt.persist(s.kv)
+		s.metaCache.invalidate(metaCacheKey(metaCacheNamespaceConfig, name))
 		log.Infof("namespace:%v config is deleted: %+v", name, *cfg)
 	}
 }
 
 // SetLabelProperty inserts a label property config.
 func (s *Server) SetLabelProperty(typ, labelKey, labelValue string) error {
+	if err := s.checkStoreLabel(labelKey, labelValue); err != nil {
+		return err
+	}
 	s.scheduleOpt.SetLabelProperty(typ, labelKey, labelValue)
 	err := s.scheduleOpt.persist(s.kv)
+	s.metaCache.invalidate(metaCacheKey(metaCacheLabelProperty, s.clusterID))
 	if err != nil {
 		return err
 	}
@@ -607,10 +953,32 @@ func (s *Server) SetLabelProperty(typ, labelKey, labelValue string) error {
 	return nil
 }
 
+// checkStoreLabel checks that the label is on any store, which is not
+// tombstone. It is skipped before the cluster is bootstrapped, when no store
+// is known yet, so that the label properties can be set in advance.
+func (s *Server) checkStoreLabel(key, value string) error {
+	cluster := s.GetRaftCluster()
+	if cluster == nil {
+		return nil
+	}
+	for _, store := range cluster.cachedCluster.GetStores() {
+		if store.IsTombstone() {
+			continue
+		}
+		for _, label := range store.GetLabels() {
+			if label.GetKey() == key && label.GetValue() == value {
+				return nil
+			}
+		}
+	}
+	return errors.Wrapf(ErrStoreLabelNotFound, "label %s=%s", key, value)
+}
+
 // DeleteLabelProperty deletes a label property config.
 func (s *Server) DeleteLabelProperty(typ, labelKey, labelValue string) error {
 	s.scheduleOpt.DeleteLabelProperty(typ, labelKey, labelValue)
 	err := s.scheduleOpt.persist(s.kv)
+	s.metaCache.invalidate(metaCacheKey(metaCacheLabelProperty, s.clusterID))
 	if err != nil {
 		return err
 	}
@@ -620,7 +988,44 @@ func (s *Server) DeleteLabelProperty(typ, labelKey, labelValue string) error {
 
 // GetLabelProperty returns the whole label property config.
 func (s *Server) GetLabelProperty() LabelPropertyConfig {
-	return s.scheduleOpt.loadLabelPropertyConfig().clone()
+	v, _ := s.metaCache.load(metaCacheLabelProperty, metaCacheKey(metaCacheLabelProperty, s.clusterID), func() (interface{}, error) {
+		return s.scheduleOpt.loadLabelPropertyConfig().clone(), nil
+	})
+	return v.(LabelPropertyConfig).clone()
+}
+
+// SetMergeProtectionRule inserts or updates a merge protection rule.
+func (s *Server) SetMergeProtectionRule(name string, rule *MergeProtectionRule) error {
+	if name == "" {
+		return errors.Wrap(ErrInvalidMergeProtectionRule, "empty name")
+	}
+	if err := rule.validate(); err != nil {
+		return errors.Wrap(ErrInvalidMergeProtectionRule, err.Error())
+	}
+	s.scheduleOpt.SetMergeProtectionRule(name, rule)
+	if err := s.scheduleOpt.persist(s.kv); err != nil {
+		return err
+	}
+	log.Infof("merge protection rule %s is updated: %+v", name, rule)
+	return nil
+}
+
+// DeleteMergeProtectionRule deletes a merge protection rule.
+func (s *Server) DeleteMergeProtectionRule(name string) error {
+	if _, ok := s.scheduleOpt.loadMergeProtectionConfig()[name]; !ok {
+		return errors.Wrapf(ErrMergeProtectionRuleNotFound, "rule %s", name)
+	}
+	s.scheduleOpt.DeleteMergeProtectionRule(name)
+	if err := s.scheduleOpt.persist(s.kv); err != nil {
+		return err
+	}
+	log.Infof("merge protection rule %s is deleted", name)
+	return nil
+}
+
+// GetMergeProtection returns the whole merge protection config.
+func (s *Server) GetMergeProtection() MergeProtectionConfig {
+	return s.scheduleOpt.loadMergeProtectionConfig().clone()
 }
 
 // SetClusterVersion sets the version of cluster.
@@ -643,6 +1048,16 @@ func (s *Server) GetClusterVersion() semver.Version {
 	return s.scheduleOpt.loadClusterVersion()
 }
 
+// IsFeatureSupported checks if the feature is supported by current cluster version.
+func (s *Server) IsFeatureSupported(f Feature) bool {
+	return IsFeatureSupportedBy(s.scheduleOpt.loadClusterVersion(), f)
+}
+
+// GetFeatureStatuses returns whether each feature is enabled by current cluster version.
+func (s *Server) GetFeatureStatuses() []*FeatureStatus {
+	return GetFeatureStatuses(s.scheduleOpt.loadClusterVersion())
+}
+
 // GetSecurityConfig get the security config.
 func (s *Server) GetSecurityConfig() *SecurityConfig {
 	return &s.cfg.Security
@@ -676,15 +1091,30 @@ func (s *Server) GetCluster() *metapb.Cluster {
 
 // GetClusterStatus gets cluster status.
 func (s *Server) GetClusterStatus() (*ClusterStatus, error) {
-	s.cluster.Lock()
-	defer s.cluster.Unlock()
-	return s.cluster.loadClusterStatus()
+	// The status is only read from the storage, so the cluster lock is not
+	// required.
+	v, err := s.metaCache.load(metaCacheClusterStatus, metaCacheKey(metaCacheClusterStatus, s.clusterID), func() (interface{}, error) {
+		return s.cluster.loadClusterStatus()
+	})
+	if err != nil {
+		return nil, err
+	}
+	status := *v.(*ClusterStatus)
+	status.Leader = s.GetLeader()
+	if cluster := s.GetRaftCluster(); cluster != nil {
+		cluster.fillClusterStatus(&status)
+	}
+	return &status, nil
 }
 
 func (s *Server) getAllocIDPath() string {
 	return path.Join(s.rootPath, "alloc_id")
 }
 
+func (s *Server) getTimeIDAllocPath() string {
+	return path.Join(s.getAllocIDPath(), "time")
+}
+
 func (s *Server) getMemberLeaderPriorityPath(id uint64) string {
 	return path.Join(s.rootPath, fmt.Sprintf("member/%d/leader_priority", id))
 }
@@ -697,8 +1127,9 @@ func (s *Server) SetMemberLeaderPriority(id uint64, priority int) error {
 		return errors.WithStack(err)
 	}
 	if !res.Succeeded {
-		return errors.New("save leader priority failed, maybe not leader")
+		return errors.Wrap(ErrNotLeader, "save leader priority failed")
 	}
+	s.metaCache.invalidate(metaCacheKey(metaCacheLeaderPriority, id))
 	return nil
 }
 
@@ -710,15 +1141,28 @@ func (s *Server) DeleteMemberLeaderPriority(id uint64) error {
 		return errors.WithStack(err)
 	}
 	if !res.Succeeded {
-		return errors.New("delete leader priority failed, maybe not leader")
+		return errors.Wrap(ErrNotLeader, "delete leader priority failed")
 	}
+	s.metaCache.invalidate(metaCacheKey(metaCacheLeaderPriority, id))
 	return nil
 }
 
 // GetMemberLeaderPriority loads a member's priority to be elected as the etcd leader.
 func (s *Server) GetMemberLeaderPriority(id uint64) (int, error) {
+	v, err := s.metaCache.load(metaCacheLeaderPriority, metaCacheKey(metaCacheLeaderPriority, id), func() (interface{}, error) {
+		return s.loadMemberLeaderPriority(id)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return v.(int), nil
+}
+
+func (s *Server) loadMemberLeaderPriority(id uint64) (int, error) {
 	key := s.getMemberLeaderPriorityPath(id)
-	res, err := kvGet(s.client, key)
+	ctx, cancel := s.readContext(s.client.Ctx())
+	defer cancel()
+	res, err := kvGet(ctx, s.client, key)
 	if err != nil {
 		return 0, err
 	}
@@ -732,6 +1176,125 @@ func (s *Server) GetMemberLeaderPriority(id uint64) (int, error) {
 	return int(priority), nil
 }
 
+func (s *Server) getMemberDeployInfoPath(id uint64, item string) string {
+	return path.Join(s.rootPath, fmt.Sprintf("member/%d/%s", id, item))
+}
+
+// saveMemberDeployInfo saves the binary version, git hash, deploy path and
+// labels of current PD to etcd, so that other members can show them.
+func (s *Server) saveMemberDeployInfo() error {
+	deployPath, err := os.Executable()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	ops := []clientv3.Op{
+		clientv3.OpPut(s.getMemberDeployInfoPath(s.id, "binary_version"), PDReleaseVersion),
+		clientv3.OpPut(s.getMemberDeployInfoPath(s.id, "git_hash"), PDGitHash),
+		clientv3.OpPut(s.getMemberDeployInfoPath(s.id, "deploy_path"), filepath.Dir(deployPath)),
+	}
+	if len(s.cfg.Labels) > 0 {
+		labels, err := json.Marshal(s.cfg.Labels)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		ops = append(ops, clientv3.OpPut(s.getMemberDeployInfoPath(s.id, "labels"), string(labels)))
+	} else {
+		ops = append(ops, clientv3.OpDelete(s.getMemberDeployInfoPath(s.id, "labels")))
+	}
+	if s.cfg.AdvertiseAPIUrls != "" {
+		ops = append(ops, clientv3.OpPut(s.getMemberDeployInfoPath(s.id, "api_urls"), s.cfg.AdvertiseAPIUrls))
+	} else {
+		ops = append(ops, clientv3.OpDelete(s.getMemberDeployInfoPath(s.id, "api_urls")))
+	}
+	_, err = s.txn().Then(ops...).Commit()
+	return errors.WithStack(err)
+}
+
+// ListMembers returns the members of the cluster. The client urls of the
+// members serving APIs on separated listeners are replaced by the api urls,
+// so that clients never talk to the etcd client urls directly.
+func (s *Server) ListMembers() ([]*pdpb.Member, error) {
+	members, err := GetMembers(s.GetClient())
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := s.readContext(s.client.Ctx())
+	defer cancel()
+	prefix := path.Join(s.rootPath, "member") + "/"
+	resp, err := kvGet(ctx, s.client, prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	apiUrls := make(map[string]string)
+	for _, kv := range resp.Kvs {
+		if key := string(kv.Key); strings.HasSuffix(key, "/api_urls") {
+			apiUrls[strings.TrimSuffix(strings.TrimPrefix(key, prefix), "/api_urls")] = string(kv.Value)
+		}
+	}
+	for _, m := range members {
+		if urls, ok := apiUrls[strconv.FormatUint(m.GetMemberId(), 10)]; ok {
+			m.ClientUrls = strings.Split(urls, ",")
+		}
+	}
+	return members, nil
+}
+
+// MemberDeployInfo is the deployment information reported by a member.
+type MemberDeployInfo struct {
+	BinaryVersion string `json:"binary_version"`
+	GitHash       string `json:"git_hash"`
+	DeployPath    string `json:"deploy_path"`
+	// Labels are the deployment labels of the member, such as the zone.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// GetMemberDeployInfo loads the deployment information of a member.
+func (s *Server) GetMemberDeployInfo(id uint64) (*MemberDeployInfo, error) {
+	info := &MemberDeployInfo{}
+	items := map[string]*string{
+		"binary_version": &info.BinaryVersion,
+		"git_hash":       &info.GitHash,
+		"deploy_path":    &info.DeployPath,
+	}
+	ctx, cancel := s.readContext(s.client.Ctx())
+	defer cancel()
+	for item, value := range items {
+		res, err := kvGet(ctx, s.client, s.getMemberDeployInfoPath(id, item))
+		if err != nil {
+			return nil, err
+		}
+		if len(res.Kvs) > 0 {
+			*value = string(res.Kvs[0].Value)
+		}
+	}
+	res, err := kvGet(ctx, s.client, s.getMemberDeployInfoPath(id, "labels"))
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Kvs) > 0 {
+		if err = json.Unmarshal(res.Kvs[0].Value, &info.Labels); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	return info, nil
+}
+
+// DeleteMemberDeployInfo removes a member's deployment information.
+func (s *Server) DeleteMemberDeployInfo(id uint64) error {
+	var ops []clientv3.Op
+	for _, item := range []string{"binary_version", "git_hash", "deploy_path", "api_urls", "labels"} {
+		ops = append(ops, clientv3.OpDelete(s.getMemberDeployInfoPath(id, item)))
+	}
+	res, err := s.leaderTxn().Then(ops...).Commit()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if !res.Succeeded {
+		return errors.Wrap(ErrNotLeader, "delete member deploy info failed")
+	}
+	return nil
+}
+
 // SetLogLevel sets log level.
 func (s *Server) SetLogLevel(level string) {
 	s.cfg.Log.Level = level
@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/pd/pkg/testutil"
 )
 
@@ -156,3 +157,24 @@ func (s *testServerSuite) TestCheckClusterID(c *C) {
 	err = svr.Run(context.TODO())
 	c.Assert(err, NotNil)
 }
+
+func (s *testServerSuite) TestCloseDrain(c *C) {
+	svrs, cleanup := newTestServersWithCfgs(c, NewTestMultiConfig(3))
+	defer cleanup()
+
+	leader := mustWaitLeader(c, svrs)
+	var others []*Server
+	for _, svr := range svrs {
+		if svr != leader {
+			others = append(others, svr)
+		}
+	}
+	leader.Close()
+
+	// The new requests are rejected with NotLeader.
+	c.Assert(leader.validateRequest(&pdpb.RequestHeader{ClusterId: leader.clusterID}), Equals, notLeaderError)
+	// The leader has resigned before etcd is closed.
+	c.Assert(others[0].GetEtcdLeader(), Not(Equals), leader.ID())
+	newLeader := mustWaitLeader(c, others)
+	c.Assert(newLeader.ID(), Not(Equals), leader.ID())
+}
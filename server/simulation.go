@@ -0,0 +1,417 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pingcap/errcode"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/pkg/typeutil"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/schedule"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultSimulationMaxSteps = 100000
+	// simulationIdleRounds is the count of consecutive rounds without any
+	// operator before the balance is regarded as converged, because the
+	// schedulers pick the regions randomly.
+	simulationIdleRounds = 10
+)
+
+// simulationSchedulers are the schedulers run by the simulation.
+var simulationSchedulers = []string{"balance-leader", "balance-region"}
+
+// CapacitySimulationInput is the change of stores to simulate.
+type CapacitySimulationInput struct {
+	// AddStores is the count of the new stores.
+	AddStores int `json:"add_stores"`
+	// Capacity is the capacity of each new store, it is the average capacity
+	// of the up stores if it is 0.
+	Capacity typeutil.ByteSize `json:"capacity"`
+	// Labels are the labels of the new stores.
+	Labels []*metapb.StoreLabel `json:"labels"`
+	// RemoveStores are the stores to be set offline.
+	RemoveStores []uint64 `json:"remove_stores"`
+	// MaxSteps is the limit of the operators to apply, the simulation stops
+	// without converging when it is reached.
+	MaxSteps int `json:"max_steps"`
+}
+
+// StoreDistribution is the leaders and regions on a store.
+type StoreDistribution struct {
+	LeaderCount int     `json:"leader_count"`
+	LeaderSize  int64   `json:"leader_size"`
+	RegionCount int     `json:"region_count"`
+	RegionSize  int64   `json:"region_size"`
+	RegionScore float64 `json:"region_score"`
+}
+
+// StoreSimulation is the predicted change of a store.
+type StoreSimulation struct {
+	StoreID uint64 `json:"store_id"`
+	// Added is true for the new stores.
+	Added bool `json:"added,omitempty"`
+	// Removed is true for the stores to be set offline.
+	Removed bool              `json:"removed,omitempty"`
+	Before  StoreDistribution `json:"before"`
+	After   StoreDistribution `json:"after"`
+}
+
+// CapacitySimulation is the predicted result of adding or removing stores.
+type CapacitySimulation struct {
+	// Converged is false if the simulation stops at the max steps.
+	Converged bool `json:"converged"`
+	Steps     int  `json:"steps"`
+	// MovedPeers is the count of the peers added to move the regions.
+	MovedPeers int `json:"moved_peers"`
+	// MovedSize is the estimated size of the data moved in MB.
+	MovedSize          int64             `json:"moved_size"`
+	TransferredLeaders int               `json:"transferred_leaders"`
+	Stores             []StoreSimulation `json:"stores"`
+}
+
+// simulationIDAllocator allocates the IDs above the IDs in the sandbox.
+type simulationIDAllocator struct {
+	base uint64
+}
+
+func (a *simulationIDAllocator) Alloc() (uint64, error) {
+	a.base++
+	return a.base, nil
+}
+
+func (a *simulationIDAllocator) observe(id uint64) {
+	if id > a.base {
+		a.base = id
+	}
+}
+
+// newSandbox clones the stores and the regions to a cluster in memory, which
+// is not persisted and is not subscribed by the watchers. The transient
+// states of the stores, such as busy and snapshots, are cleared.
+func (c *clusterInfo) newSandbox() *clusterInfo {
+	c.regionsMu.RLock()
+	defer c.regionsMu.RUnlock()
+	c.storesMu.RLock()
+	defer c.storesMu.RUnlock()
+
+	id := &simulationIDAllocator{}
+	sandbox := &clusterInfo{
+		core:        schedule.NewBasicCluster(),
+		id:          id,
+		opt:         c.opt,
+		meta:        c.meta,
+		regionIndex: core.NewRegionIndex(),
+	}
+	sandbox.core.Regions.Subscribe(sandbox.regionIndex.OnRegionEvent)
+	for _, store := range c.core.GetStores() {
+		id.observe(store.GetId())
+		store.Unblock()
+		store.Stats.IsBusy = false
+		store.Stats.SendingSnapCount = 0
+		store.Stats.ReceivingSnapCount = 0
+		store.Stats.ApplyingSnapCount = 0
+		sandbox.core.PutStore(store)
+	}
+	for _, region := range c.core.Regions.GetRegions() {
+		id.observe(region.GetID())
+		for _, p := range region.GetPeers() {
+			id.observe(p.GetId())
+		}
+		sandbox.core.PutRegion(region)
+	}
+	return sandbox
+}
+
+// capacitySimulator runs the replica checker and the balance schedulers on a
+// sandbox, and applies the operators at once until nothing is scheduled.
+type capacitySimulator struct {
+	cluster    *clusterInfo
+	checker    *schedule.ReplicaChecker
+	schedulers []schedule.Scheduler
+	maxSteps   int
+	result     *CapacitySimulation
+}
+
+// simulateCapacity predicts the distribution after adding or removing stores.
+// Namespaces are not taken into account, and the metrics of the schedulers
+// count the simulated schedules as well.
+func (c *clusterInfo) simulateCapacity(input *CapacitySimulationInput) (*CapacitySimulation, error) {
+	if input.AddStores < 0 || input.MaxSteps < 0 {
+		return nil, errcode.NewInvalidInputErr(errors.New("add_stores and max_steps should not be negative"))
+	}
+	sandbox := c.newSandbox()
+	removed := make(map[uint64]struct{}, len(input.RemoveStores))
+	for _, storeID := range input.RemoveStores {
+		store := sandbox.core.GetStore(storeID)
+		if store == nil {
+			return nil, errors.WithStack(core.NewStoreNotFoundErr(storeID))
+		}
+		if store.IsTombstone() {
+			return nil, errors.WithStack(core.StoreTombstonedErr{StoreID: storeID})
+		}
+		store.State = metapb.StoreState_Offline
+		sandbox.core.PutStore(store)
+		removed[storeID] = struct{}{}
+	}
+
+	before := sandbox.storeDistributions()
+	added := make(map[uint64]struct{}, input.AddStores)
+	capacity := uint64(input.Capacity)
+	if capacity == 0 {
+		capacity = sandbox.averageCapacity()
+	}
+	for i := 0; i < input.AddStores; i++ {
+		storeID, err := sandbox.allocID()
+		if err != nil {
+			return nil, err
+		}
+		store := core.NewStoreInfo(&metapb.Store{
+			Id:     storeID,
+			State:  metapb.StoreState_Up,
+			Labels: input.Labels,
+		})
+		store.Stats.StoreId = storeID
+		store.Stats.Capacity = capacity
+		store.Stats.Available = capacity
+		store.LastHeartbeatTS = time.Now()
+		sandbox.core.PutStore(store)
+		added[storeID] = struct{}{}
+	}
+
+	sim := &capacitySimulator{
+		cluster:  sandbox,
+		checker:  schedule.NewReplicaChecker(sandbox, nil),
+		maxSteps: input.MaxSteps,
+		result:   &CapacitySimulation{},
+	}
+	if sim.maxSteps == 0 {
+		sim.maxSteps = defaultSimulationMaxSteps
+	}
+	opController := schedule.NewOperatorController(sandbox, nil)
+	for _, name := range simulationSchedulers {
+		s, err := schedule.CreateScheduler(name, opController)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.Prepare(sandbox); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		defer s.Cleanup(sandbox)
+		sim.schedulers = append(sim.schedulers, s)
+	}
+	if err := sim.run(); err != nil {
+		return nil, err
+	}
+
+	after := sandbox.storeDistributions()
+	for storeID, d := range after {
+		_, isAdded := added[storeID]
+		_, isRemoved := removed[storeID]
+		sim.result.Stores = append(sim.result.Stores, StoreSimulation{
+			StoreID: storeID,
+			Added:   isAdded,
+			Removed: isRemoved,
+			Before:  before[storeID],
+			After:   d,
+		})
+	}
+	sort.Slice(sim.result.Stores, func(i, j int) bool { return sim.result.Stores[i].StoreID < sim.result.Stores[j].StoreID })
+	return sim.result, nil
+}
+
+// storeDistributions returns the distributions of the stores which are not
+// tombstone.
+func (c *clusterInfo) storeDistributions() map[uint64]StoreDistribution {
+	distributions := make(map[uint64]StoreDistribution)
+	for _, store := range c.GetStores() {
+		if store.IsTombstone() {
+			continue
+		}
+		distributions[store.GetId()] = StoreDistribution{
+			LeaderCount: store.LeaderCount,
+			LeaderSize:  store.LeaderSize,
+			RegionCount: store.RegionCount,
+			RegionSize:  store.RegionSize,
+			RegionScore: store.RegionScore(c.GetHighSpaceRatio(), c.GetLowSpaceRatio(), 0),
+		}
+	}
+	return distributions
+}
+
+func (c *clusterInfo) averageCapacity() uint64 {
+	var total, count uint64
+	for _, store := range c.GetStores() {
+		if store.IsUp() && store.Stats.GetCapacity() > 0 {
+			total += store.Stats.GetCapacity()
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return total / count
+}
+
+// run patrols the regions with the replica checker and balances the stores
+// in turn, until a whole round applies no operator.
+func (s *capacitySimulator) run() error {
+	for {
+		checked, err := s.patrolRegions()
+		if err != nil {
+			return err
+		}
+		balanced, err := s.balance()
+		if err != nil {
+			return err
+		}
+		if s.result.Steps >= s.maxSteps {
+			return nil
+		}
+		if checked+balanced == 0 {
+			s.result.Converged = true
+			return nil
+		}
+	}
+}
+
+func (s *capacitySimulator) patrolRegions() (int, error) {
+	if s.cluster.GetReplicaScheduleLimit() == 0 {
+		return 0, nil
+	}
+	var applied int
+	for _, region := range s.cluster.getRegions() {
+		if s.result.Steps >= s.maxSteps {
+			break
+		}
+		if op := s.checker.Check(region); op != nil {
+			if err := s.applyOperator(op); err != nil {
+				return applied, err
+			}
+			applied++
+		}
+	}
+	return applied, nil
+}
+
+// balance runs the schedulers in turn, the operators of a scheduler are
+// applied before running the next one, as they are created from the current
+// regions.
+func (s *capacitySimulator) balance() (int, error) {
+	var applied, idle int
+	for idle < simulationIdleRounds && s.result.Steps < s.maxSteps {
+		idle++
+		for _, scheduler := range s.schedulers {
+			if !scheduler.IsScheduleAllowed(s.cluster) {
+				continue
+			}
+			for _, op := range scheduler.Schedule(s.cluster) {
+				if s.result.Steps >= s.maxSteps {
+					break
+				}
+				if err := s.applyOperator(op); err != nil {
+					return applied, err
+				}
+				applied++
+				idle = 0
+			}
+		}
+	}
+	return applied, nil
+}
+
+// applyOperator finishes all steps of the operator on the sandbox at once.
+func (s *capacitySimulator) applyOperator(op *schedule.Operator) error {
+	c := s.cluster
+	origin := c.GetRegion(op.RegionID())
+	if origin == nil {
+		return errors.WithStack(ErrRegionNotFound(op.RegionID()))
+	}
+	region := origin
+	for step := op.Check(region); step != nil; step = op.Check(region) {
+		switch st := step.(type) {
+		case schedule.TransferLeader:
+			region = region.Clone(core.WithLeader(region.GetStorePeer(st.ToStore)))
+			s.result.TransferredLeaders++
+		case schedule.AddPeer:
+			region = region.Clone(core.WithAddPeer(&metapb.Peer{Id: st.PeerID, StoreId: st.ToStore}))
+			s.result.MovedPeers++
+			s.result.MovedSize += region.GetApproximateSize()
+		case schedule.AddLearner:
+			region = region.Clone(core.WithAddPeer(&metapb.Peer{Id: st.PeerID, StoreId: st.ToStore, IsLearner: true}))
+			s.result.MovedPeers++
+			s.result.MovedSize += region.GetApproximateSize()
+		case schedule.PromoteLearner:
+			region = region.Clone(core.WithRemoveStorePeer(st.ToStore), core.WithAddPeer(&metapb.Peer{Id: st.PeerID, StoreId: st.ToStore}))
+		case schedule.DemotePromote:
+			region = region.Clone(core.WithRemoveStorePeer(st.FromStore), core.WithRemoveStorePeer(st.ToStore),
+				core.WithAddPeer(&metapb.Peer{Id: st.FromPeerID, StoreId: st.FromStore, IsLearner: true}),
+				core.WithAddPeer(&metapb.Peer{Id: st.ToPeerID, StoreId: st.ToStore}))
+		case schedule.RemovePeer:
+			region = region.Clone(core.WithRemoveStorePeer(st.FromStore))
+		default:
+			return errors.Errorf("unsupported step %v of operator %v", step, op)
+		}
+	}
+	s.result.Steps++
+
+	c.regionsMu.Lock()
+	defer c.regionsMu.Unlock()
+	c.storesMu.Lock()
+	defer c.storesMu.Unlock()
+	c.core.PutRegion(region)
+	for storeID := range origin.GetStoreIds() {
+		if region.GetStorePeer(storeID) == nil {
+			c.moveStoreSpaceLocked(storeID, -region.GetApproximateSize())
+		}
+		c.updateStoreStatusLocked(storeID)
+	}
+	for storeID := range region.GetStoreIds() {
+		if origin.GetStorePeer(storeID) == nil {
+			c.moveStoreSpaceLocked(storeID, region.GetApproximateSize())
+		}
+		c.updateStoreStatusLocked(storeID)
+	}
+	return nil
+}
+
+// moveStoreSpaceLocked updates the used and the available space of the store
+// after moving the data of the size in MB to or from it, with the compression
+// ratio of the store.
+func (c *clusterInfo) moveStoreSpaceLocked(storeID uint64, size int64) {
+	store := c.core.GetStore(storeID)
+	if store == nil {
+		return
+	}
+	amplification := 1.0
+	if store.RegionSize > 0 && store.Stats.GetUsedSize() > 0 {
+		amplification = float64(store.RegionSize) / (float64(store.Stats.GetUsedSize()) / (1 << 20))
+	}
+	delta := int64(float64(size) / amplification * (1 << 20))
+	used := int64(store.Stats.GetUsedSize()) + delta
+	available := int64(store.Stats.GetAvailable()) - delta
+	if used < 0 {
+		used = 0
+	}
+	if available < 0 {
+		available = 0
+	}
+	store.Stats.UsedSize = uint64(used)
+	store.Stats.Available = uint64(available)
+	c.core.PutStore(store)
+}
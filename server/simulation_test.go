@@ -0,0 +1,124 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/errcode"
+)
+
+var _ = Suite(&testSimulationSuite{})
+
+type testSimulationSuite struct{}
+
+// newSimulationCluster creates a cluster with 3 replicas of the regions on
+// the stores 1, 2 and 3, and the leaders on the store 1.
+func newSimulationCluster(storeCount int, regionCount int) *testClusterInfo {
+	_, opt := newTestScheduleConfig()
+	tc := newTestClusterInfo(opt)
+	for i := 1; i <= storeCount; i++ {
+		tc.addRegionStore(uint64(i), 0)
+	}
+	for i := 1; i <= regionCount; i++ {
+		tc.addLeaderRegion(uint64(i), 1, 2, 3)
+	}
+	tc.regionsMu.Lock()
+	defer tc.regionsMu.Unlock()
+	tc.storesMu.Lock()
+	defer tc.storesMu.Unlock()
+	for i := 1; i <= storeCount; i++ {
+		tc.updateStoreStatusLocked(uint64(i))
+		store := tc.core.GetStore(uint64(i))
+		store.Stats.UsedSize = uint64(store.RegionSize) * (1 << 20)
+		store.Stats.Available = store.Stats.Capacity - store.Stats.UsedSize
+		tc.core.PutStore(store)
+	}
+	return tc
+}
+
+func (s *testSimulationSuite) TestAddStores(c *C) {
+	tc := newSimulationCluster(3, 30)
+	result, err := tc.simulateCapacity(&CapacitySimulationInput{AddStores: 3})
+	c.Assert(err, IsNil)
+	c.Assert(result.Converged, IsTrue)
+	c.Assert(result.Stores, HasLen, 6)
+	c.Assert(result.MovedPeers, Greater, 0)
+	c.Assert(result.MovedSize, Equals, int64(result.MovedPeers)*10)
+	c.Assert(result.TransferredLeaders, Greater, 0)
+
+	var regionCount, leaderCount int
+	for _, store := range result.Stores {
+		if store.StoreID <= 3 {
+			c.Assert(store.Added, IsFalse)
+			c.Assert(store.Before.RegionCount, Equals, 30)
+			c.Assert(store.After.RegionCount, Less, 30)
+		} else {
+			c.Assert(store.Added, IsTrue)
+			c.Assert(store.Before.RegionCount, Equals, 0)
+			c.Assert(store.After.RegionCount, Greater, 0)
+		}
+		regionCount += store.After.RegionCount
+		leaderCount += store.After.LeaderCount
+	}
+	c.Assert(regionCount, Equals, 90)
+	c.Assert(leaderCount, Equals, 30)
+
+	// The cluster is not changed.
+	c.Assert(tc.GetStores(), HasLen, 3)
+	c.Assert(tc.GetStore(1).RegionCount, Equals, 30)
+	c.Assert(tc.GetStore(1).LeaderCount, Equals, 30)
+	c.Assert(tc.GetRegion(1).GetStoreIds(), HasLen, 3)
+}
+
+func (s *testSimulationSuite) TestRemoveStores(c *C) {
+	tc := newSimulationCluster(4, 30)
+	result, err := tc.simulateCapacity(&CapacitySimulationInput{RemoveStores: []uint64{1}})
+	c.Assert(err, IsNil)
+	c.Assert(result.Converged, IsTrue)
+	c.Assert(result.Stores, HasLen, 4)
+	c.Assert(result.MovedPeers, Equals, 30)
+	c.Assert(result.MovedSize, Equals, int64(300))
+	for _, store := range result.Stores {
+		c.Assert(store.Removed, Equals, store.StoreID == 1)
+		c.Assert(store.Added, IsFalse)
+		if store.StoreID == 1 {
+			c.Assert(store.After.RegionCount, Equals, 0)
+		} else {
+			c.Assert(store.After.RegionCount, Equals, 30)
+		}
+	}
+	c.Assert(tc.GetStore(1).IsUp(), IsTrue)
+
+	// The regions can not be moved without enough stores.
+	result, err = tc.simulateCapacity(&CapacitySimulationInput{RemoveStores: []uint64{1, 2}})
+	c.Assert(err, IsNil)
+	c.Assert(result.Converged, IsTrue)
+	c.Assert(result.Stores[0].After.RegionCount+result.Stores[1].After.RegionCount, Equals, 30)
+}
+
+func (s *testSimulationSuite) TestMaxSteps(c *C) {
+	tc := newSimulationCluster(3, 30)
+	result, err := tc.simulateCapacity(&CapacitySimulationInput{AddStores: 3, MaxSteps: 5})
+	c.Assert(err, IsNil)
+	c.Assert(result.Converged, IsFalse)
+	c.Assert(result.Steps, Equals, 5)
+}
+
+func (s *testSimulationSuite) TestInvalidInput(c *C) {
+	tc := newSimulationCluster(3, 3)
+	_, err := tc.simulateCapacity(&CapacitySimulationInput{AddStores: -1})
+	c.Assert(errcode.CodeChain(err).Code(), Equals, errcode.InvalidInputCode)
+	_, err = tc.simulateCapacity(&CapacitySimulationInput{RemoveStores: []uint64{10}})
+	c.Assert(errcode.CodeChain(err).Code(), Equals, errcode.NotFoundCode)
+}
@@ -0,0 +1,149 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+)
+
+// RequestSlowLog records the details of a request, which are logged when the
+// request finishes above the slow log threshold.
+type RequestSlowLog struct {
+	protocol string
+	method   string
+	caller   string
+	start    time.Time
+	opt      *scheduleOption
+	// txnCount is the count of etcd transactions committed with the request
+	// context.
+	txnCount int32
+
+	mu      sync.Mutex
+	regions []uint64
+	stores  []uint64
+}
+
+type requestSlowLogKey struct{}
+
+// StartRequestSlowLog starts recording a request, the returned context
+// carries the record so that the etcd transactions with it are counted.
+func (s *Server) StartRequestSlowLog(ctx context.Context, protocol, method, caller string) (context.Context, *RequestSlowLog) {
+	l := &RequestSlowLog{
+		protocol: protocol,
+		method:   method,
+		caller:   caller,
+		start:    time.Now(),
+		opt:      s.scheduleOpt,
+	}
+	return context.WithValue(ctx, requestSlowLogKey{}, l), l
+}
+
+func requestSlowLogFromContext(ctx context.Context) *RequestSlowLog {
+	l, _ := ctx.Value(requestSlowLogKey{}).(*RequestSlowLog)
+	return l
+}
+
+func (l *RequestSlowLog) recordTxn() {
+	atomic.AddInt32(&l.txnCount, 1)
+}
+
+// TouchRegion records a region accessed by the request.
+func (l *RequestSlowLog) TouchRegion(regionID uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.regions = append(l.regions, regionID)
+}
+
+// TouchStore records a store accessed by the request.
+func (l *RequestSlowLog) TouchStore(storeID uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stores = append(l.stores, storeID)
+}
+
+// Finish logs the request if it runs longer than the threshold and it is
+// sampled.
+func (l *RequestSlowLog) Finish(err error) {
+	cost := time.Since(l.start)
+	cfg := l.opt.loadPDServerConfig()
+	threshold := cfg.SlowLogThreshold.Duration
+	if threshold == 0 || cost < threshold {
+		return
+	}
+	if cfg.SlowLogSampleRate < 1 && rand.Float64() >= cfg.SlowLogSampleRate {
+		return
+	}
+	fields := log.Fields{
+		"protocol":  l.protocol,
+		"method":    l.method,
+		"caller":    l.caller,
+		"cost":      cost,
+		"txn-count": atomic.LoadInt32(&l.txnCount),
+	}
+	l.mu.Lock()
+	if len(l.regions) > 0 {
+		fields["regions"] = l.regions
+	}
+	if len(l.stores) > 0 {
+		fields["stores"] = l.stores
+	}
+	l.mu.Unlock()
+	if err != nil {
+		fields["error"] = err
+	}
+	slowRequestCounter.WithLabelValues(l.protocol).Inc()
+	log.WithFields(fields).Warn("request runs too slow")
+}
+
+// touchRequest records the region and the store in the gRPC request.
+func (l *RequestSlowLog) touchRequest(req interface{}) {
+	if r, ok := req.(interface{ GetRegionId() uint64 }); ok && r.GetRegionId() != 0 {
+		l.TouchRegion(r.GetRegionId())
+	}
+	if r, ok := req.(interface{ GetRegion() *metapb.Region }); ok && r.GetRegion().GetId() != 0 {
+		l.TouchRegion(r.GetRegion().GetId())
+	}
+	if r, ok := req.(interface{ GetStoreId() uint64 }); ok && r.GetStoreId() != 0 {
+		l.TouchStore(r.GetStoreId())
+	}
+	if r, ok := req.(interface{ GetStore() *metapb.Store }); ok && r.GetStore().GetId() != 0 {
+		l.TouchStore(r.GetStore().GetId())
+	}
+}
+
+func grpcCaller(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
+
+// slowLogUnaryInterceptor records the unary gRPC requests for the slow log.
+// The streams are not recorded, they are long-lived like the heartbeats.
+func (s *Server) slowLogUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, l := s.StartRequestSlowLog(ctx, "grpc", info.FullMethod, grpcCaller(ctx))
+	l.touchRequest(req)
+	resp, err := handler(ctx, req)
+	l.Finish(err)
+	return resp, err
+}
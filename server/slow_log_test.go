@@ -0,0 +1,119 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/pkg/typeutil"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+var _ = Suite(&testSlowLogSuite{})
+
+type testSlowLogSuite struct{}
+
+// slowLogHook collects the slow request logs.
+type slowLogHook struct {
+	sync.Mutex
+	entries []*log.Entry
+}
+
+func (h *slowLogHook) Levels() []log.Level {
+	return []log.Level{log.WarnLevel}
+}
+
+func (h *slowLogHook) Fire(e *log.Entry) error {
+	if e.Message == "request runs too slow" {
+		h.Lock()
+		defer h.Unlock()
+		h.entries = append(h.entries, e)
+	}
+	return nil
+}
+
+func (h *slowLogHook) take() []*log.Entry {
+	h.Lock()
+	defer h.Unlock()
+	entries := h.entries
+	h.entries = nil
+	return entries
+}
+
+func (s *testSlowLogSuite) TestRequestSlowLog(c *C) {
+	cfg := NewTestSingleConfig()
+	svr := &Server{scheduleOpt: newScheduleOption(cfg)}
+	hook := &slowLogHook{}
+	log.AddHook(hook)
+
+	setSlowLog := func(threshold time.Duration, rate float64) {
+		pdServerCfg := *svr.scheduleOpt.loadPDServerConfig()
+		pdServerCfg.SlowLogThreshold = typeutil.NewDuration(threshold)
+		pdServerCfg.SlowLogSampleRate = rate
+		svr.scheduleOpt.pdServerConfig.Store(&pdServerCfg)
+	}
+	newRequest := func() *RequestSlowLog {
+		ctx, l := svr.StartRequestSlowLog(context.Background(), "http", "GET /pd/api/v1/store/1", "127.0.0.1:1234")
+		c.Assert(requestSlowLogFromContext(ctx), Equals, l)
+		l.TouchStore(1)
+		l.recordTxn()
+		time.Sleep(time.Millisecond)
+		return l
+	}
+
+	// The slow log is disabled by default.
+	newRequest().Finish(nil)
+	c.Assert(hook.take(), HasLen, 0)
+
+	setSlowLog(time.Nanosecond, 1)
+	newRequest().Finish(nil)
+	entries := hook.take()
+	c.Assert(entries, HasLen, 1)
+	c.Assert(entries[0].Data["method"], Equals, "GET /pd/api/v1/store/1")
+	c.Assert(entries[0].Data["caller"], Equals, "127.0.0.1:1234")
+	c.Assert(entries[0].Data["txn-count"], Equals, int32(1))
+	c.Assert(entries[0].Data["stores"], DeepEquals, []uint64{1})
+
+	// The requests below the threshold are not logged.
+	setSlowLog(time.Hour, 1)
+	newRequest().Finish(nil)
+	c.Assert(hook.take(), HasLen, 0)
+
+	// The requests are sampled.
+	setSlowLog(time.Nanosecond, 1e-9)
+	for i := 0; i < 10; i++ {
+		newRequest().Finish(nil)
+	}
+	c.Assert(hook.take(), HasLen, 0)
+
+	setSlowLog(time.Nanosecond, 1)
+	info := &grpc.UnaryServerInfo{FullMethod: "/pdpb.PD/GetStore"}
+	_, err := svr.slowLogUnaryInterceptor(context.Background(), &pdpb.GetStoreRequest{StoreId: 3}, info,
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			c.Assert(requestSlowLogFromContext(ctx), NotNil)
+			time.Sleep(time.Millisecond)
+			return nil, nil
+		})
+	c.Assert(err, IsNil)
+	entries = hook.take()
+	c.Assert(entries, HasLen, 1)
+	c.Assert(entries[0].Data["protocol"], Equals, "grpc")
+	c.Assert(entries[0].Data["method"], Equals, "/pdpb.PD/GetStore")
+	c.Assert(entries[0].Data["stores"], DeepEquals, []uint64{3})
+}
@@ -0,0 +1,148 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/pkg/bandwidthpb"
+)
+
+const (
+	// snapshotBandwidthBurst is the time the unused bandwidth is kept for.
+	snapshotBandwidthBurst = 10 * time.Second
+	// snapshotDemandExpire is the time after which a store which does not
+	// heartbeat is not counted when sharing the bandwidth.
+	snapshotDemandExpire = time.Minute
+	// defaultStoreHeartbeatInterval is used if a heartbeat does not report
+	// its interval.
+	defaultStoreHeartbeatInterval = 10 * time.Second
+)
+
+// snapshotBudget is a token bucket of the snapshot bandwidth of the cluster.
+// The bucket is filled at the configured bandwidth, and each store is granted
+// the tokens to send snapshots until its next heartbeat, which are shared by
+// the snapshots the stores are sending.
+type snapshotBudget struct {
+	sync.Mutex
+	opt      *scheduleOption
+	tokens   uint64
+	lastFill time.Time
+	// demands are the snapshots being sent by the stores.
+	demands map[uint64]snapshotDemand
+}
+
+type snapshotDemand struct {
+	sending  uint64
+	lastSeen time.Time
+}
+
+func newSnapshotBudget(opt *scheduleOption) *snapshotBudget {
+	return &snapshotBudget{
+		opt:     opt,
+		demands: make(map[uint64]snapshotDemand),
+	}
+}
+
+// grant returns the bandwidth of a store until its next heartbeat, nil if the
+// bandwidth is not limited.
+func (b *snapshotBudget) grant(stats *pdpb.StoreStats, now time.Time) *bandwidthpb.SnapshotBandwidth {
+	b.Lock()
+	defer b.Unlock()
+	rate := b.opt.GetSnapshotBandwidth()
+	if rate == 0 {
+		b.tokens, b.lastFill = 0, time.Time{}
+		return nil
+	}
+	b.fill(rate, now)
+
+	interval := time.Duration(stats.GetInterval().GetEndTimestamp()-stats.GetInterval().GetStartTimestamp()) * time.Second
+	if interval <= 0 {
+		interval = defaultStoreHeartbeatInterval
+	}
+	// Every store keeps a share even if it is not sending snapshots, so that
+	// it can start sending before the next heartbeat.
+	b.demands[stats.GetStoreId()] = snapshotDemand{
+		sending:  uint64(stats.GetSendingSnapCount()) + 1,
+		lastSeen: now,
+	}
+	var total uint64
+	for id, d := range b.demands {
+		if now.Sub(d.lastSeen) > snapshotDemandExpire {
+			delete(b.demands, id)
+			continue
+		}
+		total += d.sending
+	}
+
+	share := uint64(float64(rate) * interval.Seconds() * float64(b.demands[stats.GetStoreId()].sending) / float64(total))
+	if share > b.tokens {
+		share = b.tokens
+	}
+	b.tokens -= share
+	return &bandwidthpb.SnapshotBandwidth{
+		Tokens:    share,
+		RateLimit: uint64(float64(share) / interval.Seconds()),
+	}
+}
+
+// fill adds the tokens generated since the last fill to the bucket.
+func (b *snapshotBudget) fill(rate uint64, now time.Time) {
+	capacity := uint64(float64(rate) * snapshotBandwidthBurst.Seconds())
+	if b.lastFill.IsZero() {
+		b.tokens, b.lastFill = capacity, now
+		return
+	}
+	if now.Before(b.lastFill) {
+		return
+	}
+	b.tokens += uint64(float64(rate) * now.Sub(b.lastFill).Seconds())
+	if b.tokens > capacity {
+		b.tokens = capacity
+	}
+	b.lastFill = now
+}
+
+// bandwidthService implements gRPC BandwidthServer.
+type bandwidthService struct {
+	s *Server
+}
+
+// GetSnapshotBandwidth implements gRPC BandwidthServer.
+func (bs *bandwidthService) GetSnapshotBandwidth(ctx context.Context, request *bandwidthpb.GetSnapshotBandwidthRequest) (*bandwidthpb.GetSnapshotBandwidthResponse, error) {
+	if err := bs.s.validateRequest(request.GetHeader()); err != nil {
+		return nil, err
+	}
+	cluster := bs.s.GetRaftCluster()
+	if cluster == nil {
+		return &bandwidthpb.GetSnapshotBandwidthResponse{Header: bs.s.notBootstrappedHeader()}, nil
+	}
+	store, err := cluster.GetStore(request.GetStoreId())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	// The share is computed from the last heartbeat of the store, which
+	// reports the snapshots it is sending and its heartbeat interval.
+	stats := store.Stats
+	if stats == nil {
+		stats = &pdpb.StoreStats{StoreId: store.GetId()}
+	}
+	return &bandwidthpb.GetSnapshotBandwidthResponse{
+		Header:    bs.s.header(),
+		Bandwidth: cluster.snapshotBudget.grant(stats, time.Now()),
+	}, nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/pd/pkg/bandwidthpb"
+	"google.golang.org/grpc"
+)
+
+var _ = Suite(&testSnapshotBudgetSuite{})
+
+type testSnapshotBudgetSuite struct{}
+
+func newTestSnapshotStats(storeID uint64, sending uint32) *pdpb.StoreStats {
+	return &pdpb.StoreStats{
+		StoreId:          storeID,
+		SendingSnapCount: sending,
+		Interval:         &pdpb.TimeInterval{StartTimestamp: 100, EndTimestamp: 110},
+	}
+}
+
+func (s *testSnapshotBudgetSuite) TestGrant(c *C) {
+	cfg, opt := newTestScheduleConfig()
+	b := newSnapshotBudget(opt)
+	now := time.Now()
+	c.Assert(b.grant(newTestSnapshotStats(1, 0), now), IsNil)
+
+	cfg.SnapshotBandwidth = 100
+	opt.store(cfg)
+	// The bucket is full at first.
+	c.Assert(b.grant(newTestSnapshotStats(1, 0), now), DeepEquals, &bandwidthpb.SnapshotBandwidth{Tokens: 1000, RateLimit: 100})
+	c.Assert(b.grant(newTestSnapshotStats(2, 1), now), DeepEquals, &bandwidthpb.SnapshotBandwidth{})
+	// The bandwidth is shared by the snapshots being sent.
+	now = now.Add(5 * time.Second)
+	c.Assert(b.grant(newTestSnapshotStats(1, 0), now), DeepEquals, &bandwidthpb.SnapshotBandwidth{Tokens: 333, RateLimit: 33})
+	c.Assert(b.grant(newTestSnapshotStats(2, 1), now), DeepEquals, &bandwidthpb.SnapshotBandwidth{Tokens: 167, RateLimit: 16})
+	// The store which does not heartbeat is not counted.
+	now = now.Add(2 * time.Minute)
+	c.Assert(b.grant(newTestSnapshotStats(1, 0), now), DeepEquals, &bandwidthpb.SnapshotBandwidth{Tokens: 1000, RateLimit: 100})
+
+	cfg.SnapshotBandwidth = 0
+	opt.store(cfg)
+	c.Assert(b.grant(newTestSnapshotStats(1, 0), now), IsNil)
+}
+
+func (s *testSnapshotBudgetSuite) TestGetSnapshotBandwidth(c *C) {
+	_, svr, cleanup, err := NewTestServer()
+	c.Assert(err, IsNil)
+	defer cleanup()
+	mustWaitLeader(c, []*Server{svr})
+	req := (&baseCluster{svr: svr}).newBootstrapRequest(c, svr.clusterID, "127.0.0.1:0")
+	_, err = svr.bootstrapCluster(req)
+	c.Assert(err, IsNil)
+	storeID := req.GetStore().GetId()
+	c.Assert(svr.GetRaftCluster().cachedCluster.handleStoreHeartbeat(newTestSnapshotStats(storeID, 0)), IsNil)
+
+	conn, err := grpc.Dial(strings.TrimPrefix(svr.GetAddr(), "http://"), grpc.WithInsecure())
+	c.Assert(err, IsNil)
+	defer conn.Close()
+	client := bandwidthpb.NewBandwidthClient(conn)
+	request := &bandwidthpb.GetSnapshotBandwidthRequest{
+		Header:  newRequestHeader(svr.clusterID),
+		StoreId: storeID,
+	}
+	resp, err := client.GetSnapshotBandwidth(context.Background(), request)
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetBandwidth(), IsNil)
+
+	cfg := svr.scheduleOpt.load().clone()
+	cfg.SnapshotBandwidth = 100
+	svr.scheduleOpt.store(cfg)
+	resp, err = client.GetSnapshotBandwidth(context.Background(), request)
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetBandwidth(), DeepEquals, &bandwidthpb.SnapshotBandwidth{Tokens: 1000, RateLimit: 100})
+
+	request.StoreId = storeID + 1
+	_, err = client.GetSnapshotBandwidth(context.Background(), request)
+	c.Assert(err, NotNil)
+}
@@ -0,0 +1,144 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"time"
+
+	"github.com/pingcap/errcode"
+	"github.com/pingcap/pd/pkg/typeutil"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// StoreReplacementProgress is the progress of moving the peers of an offline
+// store to its replacement store.
+type StoreReplacementProgress struct {
+	StoreID            uint64 `json:"store_id"`
+	ReplacementStoreID uint64 `json:"replacement_store_id"`
+	StateName          string `json:"state_name"`
+	// RegionCount and RegionSize are those of the offline store when it was
+	// paired.
+	RegionCount     int       `json:"region_count"`
+	RegionSize      int64     `json:"region_size"`
+	LeftRegionCount int       `json:"left_region_count"`
+	LeftRegionSize  int64     `json:"left_region_size"`
+	Progress        float64   `json:"progress"`
+	StartTime       time.Time `json:"start_time"`
+	// LeftTime is estimated by the speed since the start, it is absent before
+	// any region is moved.
+	LeftTime *typeutil.Duration `json:"left_time,omitempty"`
+}
+
+// SetStoreReplacement pairs an offline store with a replacement store, the
+// peers of the offline store are moved to the replacement store first if the
+// placement allows.
+func (c *RaftCluster) SetStoreReplacement(storeID, replacementID uint64) error {
+	c.RLock()
+	defer c.RUnlock()
+
+	store := c.cachedCluster.GetStore(storeID)
+	if store == nil {
+		return core.NewStoreNotFoundErr(storeID)
+	}
+	if store.IsTombstone() {
+		return core.StoreTombstonedErr{StoreID: storeID}
+	}
+	if !store.IsOffline() {
+		return errcode.NewInvalidInputErr(errors.Errorf("store %d is not offline", storeID))
+	}
+	if replacementID == storeID {
+		return errcode.NewInvalidInputErr(errors.Errorf("store %d can not replace itself", storeID))
+	}
+	replacementStore := c.cachedCluster.GetStore(replacementID)
+	if replacementStore == nil {
+		return core.NewStoreNotFoundErr(replacementID)
+	}
+	if !replacementStore.IsUp() {
+		return errcode.NewInvalidInputErr(errors.Errorf("replacement store %d is not up", replacementID))
+	}
+
+	replacement := &core.StoreReplacement{
+		StoreID:     replacementID,
+		RegionCount: store.RegionCount,
+		RegionSize:  store.RegionSize,
+		StartTime:   time.Now(),
+	}
+	if err := c.s.kv.SaveStoreReplacement(storeID, replacement); err != nil {
+		return err
+	}
+
+	store.SetReplacement(replacement)
+	log.Infof("[store %d] paired with replacement store %d", storeID, replacementID)
+	return c.cachedCluster.putStore(store)
+}
+
+// DeleteStoreReplacement unpairs the store with its replacement store.
+func (c *RaftCluster) DeleteStoreReplacement(storeID uint64) error {
+	c.RLock()
+	defer c.RUnlock()
+
+	store := c.cachedCluster.GetStore(storeID)
+	if store == nil {
+		return core.NewStoreNotFoundErr(storeID)
+	}
+
+	if err := c.s.kv.DeleteStoreReplacement(storeID); err != nil {
+		return err
+	}
+
+	store.SetReplacement(nil)
+	log.Infof("[store %d] unpaired with the replacement store", storeID)
+	return c.cachedCluster.putStore(store)
+}
+
+// GetStoreReplacementProgress returns the progress of moving the peers of the
+// store to its replacement store.
+func (c *RaftCluster) GetStoreReplacementProgress(storeID uint64) (*StoreReplacementProgress, error) {
+	store := c.cachedCluster.GetStore(storeID)
+	if store == nil {
+		return nil, core.NewStoreNotFoundErr(storeID)
+	}
+	replacement := store.GetReplacement()
+	if replacement == nil {
+		return nil, errcode.NewNotFoundErr(errors.Errorf("store %d has no replacement store", storeID))
+	}
+
+	p := &StoreReplacementProgress{
+		StoreID:            storeID,
+		ReplacementStoreID: replacement.StoreID,
+		StateName:          store.GetState().String(),
+		RegionCount:        replacement.RegionCount,
+		RegionSize:         replacement.RegionSize,
+		Progress:           1,
+		StartTime:          replacement.StartTime,
+	}
+	if !store.IsTombstone() {
+		p.LeftRegionCount, p.LeftRegionSize = store.RegionCount, store.RegionSize
+	}
+	if p.LeftRegionCount == 0 || p.RegionCount == 0 {
+		return p, nil
+	}
+	moved := p.RegionCount - p.LeftRegionCount
+	if moved <= 0 {
+		p.Progress = 0
+		return p, nil
+	}
+	p.Progress = float64(moved) / float64(p.RegionCount)
+	elapsed := time.Since(replacement.StartTime)
+	left := typeutil.NewDuration(time.Duration(float64(elapsed) * float64(p.LeftRegionCount) / float64(moved)))
+	p.LeftTime = &left
+	return p, nil
+}
@@ -84,6 +84,12 @@ func (s *storeStatistics) Observe(store *core.StoreInfo) {
 	storeStatusGauge.WithLabelValues(s.namespace, id, "store_available").Set(float64(store.Stats.GetAvailable()))
 	storeStatusGauge.WithLabelValues(s.namespace, id, "store_used").Set(float64(store.Stats.GetUsedSize()))
 	storeStatusGauge.WithLabelValues(s.namespace, id, "store_capacity").Set(float64(store.Stats.GetCapacity()))
+	storeStatusGauge.WithLabelValues(s.namespace, id, "store_used_growth_rate").Set(store.UsedSizeGrowthRate())
+	timeToFull, _ := store.TimeToFull()
+	storeStatusGauge.WithLabelValues(s.namespace, id, "store_time_to_full").Set(timeToFull.Seconds())
+	storeStatusGauge.WithLabelValues(s.namespace, id, "store_disk_read_rate").Set(store.DiskReadRate())
+	storeStatusGauge.WithLabelValues(s.namespace, id, "store_disk_write_rate").Set(store.DiskWriteRate())
+	storeStatusGauge.WithLabelValues(s.namespace, id, "store_pending_compaction_bytes").Set(float64(store.DiskStats.PendingCompactionBytes))
 }
 
 func (s *storeStatistics) Collect() {
@@ -115,6 +121,11 @@ func (s *storeStatistics) resetStoreStatistics(id string) {
 	storeStatusGauge.WithLabelValues(s.namespace, id, "store_available").Set(0)
 	storeStatusGauge.WithLabelValues(s.namespace, id, "store_used").Set(0)
 	storeStatusGauge.WithLabelValues(s.namespace, id, "store_capacity").Set(0)
+	storeStatusGauge.WithLabelValues(s.namespace, id, "store_used_growth_rate").Set(0)
+	storeStatusGauge.WithLabelValues(s.namespace, id, "store_time_to_full").Set(0)
+	storeStatusGauge.WithLabelValues(s.namespace, id, "store_disk_read_rate").Set(0)
+	storeStatusGauge.WithLabelValues(s.namespace, id, "store_disk_write_rate").Set(0)
+	storeStatusGauge.WithLabelValues(s.namespace, id, "store_pending_compaction_bytes").Set(0)
 }
 
 type storeStatisticsMap struct {
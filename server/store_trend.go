@@ -0,0 +1,155 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pingcap/kvproto/pkg/pdpb"
+)
+
+const (
+	// storeTrendInterval is the time range of a sample of the store flow.
+	storeTrendInterval = time.Minute
+	// storeTrendRetention is the time range of the samples kept in memory.
+	storeTrendRetention = time.Hour
+)
+
+// StoreFlowSample is the average flow per second of a store in a sample
+// interval.
+type StoreFlowSample struct {
+	Time         time.Time `json:"time"`
+	BytesWritten float64   `json:"bytes_written"`
+	BytesRead    float64   `json:"bytes_read"`
+	KeysWritten  float64   `json:"keys_written"`
+	KeysRead     float64   `json:"keys_read"`
+}
+
+// storeFlowSum sums the flow reported by the heartbeats in a sample interval.
+type storeFlowSum struct {
+	start        time.Time
+	seconds      uint64
+	bytesWritten uint64
+	bytesRead    uint64
+	keysWritten  uint64
+	keysRead     uint64
+}
+
+// storeFlowRing is a ring buffer of the flow sums, the oldest one is
+// overwritten when it is full.
+type storeFlowRing struct {
+	sums []storeFlowSum
+	// next is the index to put the next sum.
+	next  int
+	count int
+}
+
+func newStoreFlowRing(size int) *storeFlowRing {
+	return &storeFlowRing{sums: make([]storeFlowSum, size)}
+}
+
+// last returns the latest sum, or nil if the ring is empty.
+func (r *storeFlowRing) last() *storeFlowSum {
+	if r.count == 0 {
+		return nil
+	}
+	return &r.sums[(r.next+len(r.sums)-1)%len(r.sums)]
+}
+
+func (r *storeFlowRing) push(sum storeFlowSum) {
+	r.sums[r.next] = sum
+	r.next = (r.next + 1) % len(r.sums)
+	if r.count < len(r.sums) {
+		r.count++
+	}
+}
+
+// samples returns the average flow of the sums from the oldest to the latest.
+func (r *storeFlowRing) samples() []StoreFlowSample {
+	samples := make([]StoreFlowSample, 0, r.count)
+	for i := 0; i < r.count; i++ {
+		sum := r.sums[(r.next+len(r.sums)-r.count+i)%len(r.sums)]
+		seconds := float64(sum.seconds)
+		samples = append(samples, StoreFlowSample{
+			Time:         sum.start,
+			BytesWritten: float64(sum.bytesWritten) / seconds,
+			BytesRead:    float64(sum.bytesRead) / seconds,
+			KeysWritten:  float64(sum.keysWritten) / seconds,
+			KeysRead:     float64(sum.keysRead) / seconds,
+		})
+	}
+	return samples
+}
+
+// storeTrends keeps the flow of stores in the retention, which is updated by
+// store heartbeats.
+type storeTrends struct {
+	sync.RWMutex
+	interval time.Duration
+	size     int
+	rings    map[uint64]*storeFlowRing
+}
+
+func newStoreTrends(interval, retention time.Duration) *storeTrends {
+	return &storeTrends{
+		interval: interval,
+		size:     int(retention / interval),
+		rings:    make(map[uint64]*storeFlowRing),
+	}
+}
+
+// observe adds the flow reported by a store heartbeat to the sample of the
+// interval.
+func (t *storeTrends) observe(stats *pdpb.StoreStats, now time.Time) {
+	interval := stats.GetInterval()
+	if interval.GetEndTimestamp() <= interval.GetStartTimestamp() {
+		return
+	}
+
+	t.Lock()
+	defer t.Unlock()
+	ring, ok := t.rings[stats.GetStoreId()]
+	if !ok {
+		ring = newStoreFlowRing(t.size)
+		t.rings[stats.GetStoreId()] = ring
+	}
+	start := now.Truncate(t.interval)
+	sum := ring.last()
+	if sum == nil || !sum.start.Equal(start) {
+		ring.push(storeFlowSum{start: start})
+		sum = ring.last()
+	}
+	sum.seconds += interval.GetEndTimestamp() - interval.GetStartTimestamp()
+	sum.bytesWritten += stats.GetBytesWritten()
+	sum.bytesRead += stats.GetBytesRead()
+	sum.keysWritten += stats.GetKeysWritten()
+	sum.keysRead += stats.GetKeysRead()
+}
+
+// get returns the flow samples of a store from the oldest to the latest.
+func (t *storeTrends) get(storeID uint64) []StoreFlowSample {
+	t.RLock()
+	defer t.RUnlock()
+	ring, ok := t.rings[storeID]
+	if !ok {
+		return nil
+	}
+	return ring.samples()
+}
+
+// GetStoreFlowTrend returns the flow samples of a store in the last hour.
+func (c *RaftCluster) GetStoreFlowTrend(storeID uint64) []StoreFlowSample {
+	return c.cachedCluster.storeTrends.get(storeID)
+}
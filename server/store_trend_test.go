@@ -0,0 +1,67 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+)
+
+var _ = Suite(&testStoreTrendSuite{})
+
+type testStoreTrendSuite struct{}
+
+func newTestStoreStats(storeID, bytesWritten, bytesRead uint64, seconds uint64) *pdpb.StoreStats {
+	return &pdpb.StoreStats{
+		StoreId:      storeID,
+		BytesWritten: bytesWritten,
+		BytesRead:    bytesRead,
+		KeysWritten:  bytesWritten / 10,
+		KeysRead:     bytesRead / 10,
+		Interval:     &pdpb.TimeInterval{StartTimestamp: 100, EndTimestamp: 100 + seconds},
+	}
+}
+
+func (s *testStoreTrendSuite) TestStoreTrends(c *C) {
+	trends := newStoreTrends(time.Minute, 3*time.Minute)
+	c.Assert(trends.get(1), IsNil)
+
+	start := time.Now().Truncate(time.Minute)
+	// Heartbeats in the same interval are summed up.
+	trends.observe(newTestStoreStats(1, 1000, 500, 10), start)
+	trends.observe(newTestStoreStats(1, 3000, 1500, 10), start.Add(10*time.Second))
+	// The heartbeat without interval is ignored.
+	trends.observe(&pdpb.StoreStats{StoreId: 1, BytesWritten: 1000}, start)
+	samples := trends.get(1)
+	c.Assert(samples, HasLen, 1)
+	c.Assert(samples[0].Time.Equal(start), IsTrue)
+	c.Assert(samples[0].BytesWritten, Equals, float64(200))
+	c.Assert(samples[0].BytesRead, Equals, float64(100))
+	c.Assert(samples[0].KeysWritten, Equals, float64(20))
+	c.Assert(samples[0].KeysRead, Equals, float64(10))
+	c.Assert(trends.get(2), IsNil)
+
+	// The oldest samples are overwritten.
+	for i := 1; i <= 3; i++ {
+		trends.observe(newTestStoreStats(1, uint64(i)*100, 0, 10), start.Add(time.Duration(i)*time.Minute))
+	}
+	samples = trends.get(1)
+	c.Assert(samples, HasLen, 3)
+	for i, sample := range samples {
+		c.Assert(sample.Time.Equal(start.Add(time.Duration(i+1)*time.Minute)), IsTrue)
+		c.Assert(sample.BytesWritten, Equals, float64((i+1)*10))
+	}
+}
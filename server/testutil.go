@@ -77,6 +77,7 @@ func NewTestSingleConfig() *Config {
 	cfg.TickInterval = typeutil.NewDuration(100 * time.Millisecond)
 	cfg.ElectionInterval = typeutil.NewDuration(3 * time.Second)
 	cfg.LeaderPriorityCheckInterval = typeutil.NewDuration(100 * time.Millisecond)
+	cfg.DrainTimeout = typeutil.NewDuration(time.Second)
 
 	cfg.Adjust(nil)
 
@@ -45,7 +45,9 @@ func (s *Server) getTimestampPath() string {
 }
 
 func (s *Server) loadTimestamp() (time.Time, error) {
-	data, err := getValue(s.client, s.getTimestampPath())
+	ctx, cancel := s.readContext(s.client.Ctx())
+	defer cancel()
+	data, err := getValue(ctx, s.client, s.getTimestampPath())
 	if err != nil {
 		return zeroTime, err
 	}
@@ -66,14 +68,52 @@ func (s *Server) saveTimestamp(ts time.Time) error {
 		return errors.WithStack(err)
 	}
 	if !resp.Succeeded {
-		return errors.New("save timestamp failed, maybe we lost leader")
+		return errors.Wrap(ErrNotLeader, "save timestamp failed")
 	}
 
-	s.lastSavedTime = ts
+	s.lastSavedTime.Store(ts)
 
 	return nil
 }
 
+func (s *Server) getLastSavedTime() time.Time {
+	if t, ok := s.lastSavedTime.Load().(time.Time); ok {
+		return t
+	}
+	return zeroTime
+}
+
+// saveTimestampAsync saves the next timestamp window in the background, if
+// there is no window being saved.
+func (s *Server) saveTimestampAsync(ts time.Time) {
+	if s.tsoSaving != nil {
+		select {
+		case <-s.tsoSaving:
+			s.tsoSaving = nil
+		default:
+			return
+		}
+	}
+	tsoCounter.WithLabelValues("save_async").Inc()
+	done := make(chan struct{})
+	s.tsoSaving = done
+	go func() {
+		defer close(done)
+		if err := s.saveTimestamp(ts); err != nil {
+			tsoCounter.WithLabelValues("save_async_failed").Inc()
+			log.Warnf("save timestamp %v in the background failed: %v", ts, err)
+		}
+	}()
+}
+
+// waitTimestampSaving waits for the window being saved in the background.
+func (s *Server) waitTimestampSaving() {
+	if s.tsoSaving != nil {
+		<-s.tsoSaving
+		s.tsoSaving = nil
+	}
+}
+
 func (s *Server) syncTimestamp() error {
 	tsoCounter.WithLabelValues("sync").Inc()
 
@@ -114,8 +154,9 @@ func (s *Server) syncTimestamp() error {
 // This function will do two things:
 // 1. When the logical time is going to be used up, the current physical time needs to increase.
 // 2. If the time window is not enough, which means the saved etcd time minus the next physical time
-//    is less than or equal to `updateTimestampGuard`, it will need to be updated and save the
-//    next physical time plus `TsoSaveInterval` into etcd.
+//    is less than or equal to `TsoSaveGuard`, the next physical time plus `TsoSaveInterval` is saved
+//    into etcd in the background. Only if the window is used up, which means the time left is less
+//    than or equal to `updateTimestampGuard`, it waits for the saving.
 //
 // Here is some constraints that this function must satisfy:
 // 1. The physical time is monotonically increasing.
@@ -158,13 +199,21 @@ func (s *Server) updateTimestamp() error {
 		return nil
 	}
 
-	// It is not safe to increase the physical time to `next`.
-	// The time window needs to be updated and saved to etcd.
-	if subTimeByWallClock(s.lastSavedTime, next) <= updateTimestampGuard {
-		save := next.Add(s.cfg.TsoSaveInterval.Duration)
-		if err := s.saveTimestamp(save); err != nil {
-			return err
+	save := next.Add(s.cfg.TsoSaveInterval.Duration)
+	if left := subTimeByWallClock(s.getLastSavedTime(), next); left <= updateTimestampGuard {
+		// It is not safe to increase the physical time to `next`.
+		// The time window needs to be updated and saved to etcd.
+		tsoCounter.WithLabelValues("window_exhausted").Inc()
+		start := time.Now()
+		s.waitTimestampSaving()
+		if subTimeByWallClock(s.getLastSavedTime(), next) <= updateTimestampGuard {
+			if err := s.saveTimestamp(save); err != nil {
+				return err
+			}
 		}
+		tsoWindowWaitDuration.Observe(time.Since(start).Seconds())
+	} else if left <= s.cfg.TsoSaveGuard.Duration {
+		s.saveTimestampAsync(save)
 	}
 
 	current := &atomicObject{
@@ -103,6 +103,21 @@ func (s *testTsoSuite) TestTsoCount0(c *C) {
 	c.Assert(err, NotNil)
 }
 
+func (s *testTsoSuite) TestTsoWindow(c *C) {
+	// The window is saved ahead before it is used up.
+	for i := 0; i < 20; i++ {
+		last := s.testGetTimestamp(c, 1)
+		saved := s.svr.getLastSavedTime()
+		c.Assert(last.GetPhysical(), Less, saved.UnixNano()/int64(time.Millisecond))
+		time.Sleep(50 * time.Millisecond)
+	}
+	data, err := getValue(context.TODO(), s.client, s.svr.getTimestampPath())
+	c.Assert(err, IsNil)
+	saved, err := parseTimestamp(data)
+	c.Assert(err, IsNil)
+	c.Assert(saved.After(time.Now()), IsTrue)
+}
+
 var _ = Suite(&testTimeFallBackSuite{})
 
 type testTimeFallBackSuite struct {
@@ -182,7 +197,7 @@ func (s *testTimeFallBackSuite) TestTimeFallBack(c *C) {
 
 func mustGetLeader(c *C, client *clientv3.Client, leaderPath string) *pdpb.Member {
 	for i := 0; i < 20; i++ {
-		leader, _, err := getLeader(client, leaderPath)
+		leader, _, err := getLeader(context.TODO(), client, leaderPath)
 		c.Assert(err, IsNil)
 		if leader != nil {
 			return leader
@@ -80,9 +80,18 @@ func CheckPDVersion(opt *scheduleOption) {
 	}
 }
 
+// withDefaultTimeout returns a context with the timeout if ctx has no
+// deadline, so that etcd requests never hang forever.
+func withDefaultTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
 // A helper function to get value with key from etcd.
-func getValue(c *clientv3.Client, key string, opts ...clientv3.OpOption) ([]byte, error) {
-	resp, err := get(c, key, opts...)
+func getValue(ctx context.Context, c *clientv3.Client, key string, opts ...clientv3.OpOption) ([]byte, error) {
+	resp, err := get(ctx, c, key, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -92,8 +101,8 @@ func getValue(c *clientv3.Client, key string, opts ...clientv3.OpOption) ([]byte
 	return resp.Kvs[0].Value, nil
 }
 
-func get(c *clientv3.Client, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
-	resp, err := kvGet(c, key, opts...)
+func get(ctx context.Context, c *clientv3.Client, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	resp, err := kvGet(ctx, c, key, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -107,8 +116,8 @@ func get(c *clientv3.Client, key string, opts ...clientv3.OpOption) (*clientv3.G
 }
 
 // Return boolean to indicate whether the key exists or not.
-func getProtoMsgWithModRev(c *clientv3.Client, key string, msg proto.Message, opts ...clientv3.OpOption) (bool, int64, error) {
-	resp, err := get(c, key, opts...)
+func getProtoMsgWithModRev(ctx context.Context, c *clientv3.Client, key string, msg proto.Message, opts ...clientv3.OpOption) (bool, int64, error) {
+	resp, err := get(ctx, c, key, opts...)
 	if err != nil {
 		return false, 0, err
 	}
@@ -122,8 +131,8 @@ func getProtoMsgWithModRev(c *clientv3.Client, key string, msg proto.Message, op
 	return true, resp.Kvs[0].ModRevision, nil
 }
 
-func initOrGetClusterID(c *clientv3.Client, key string) (uint64, error) {
-	ctx, cancel := context.WithTimeout(c.Ctx(), requestTimeout)
+func initOrGetClusterID(ctx context.Context, c *clientv3.Client, key string) (uint64, error) {
+	ctx, cancel := withDefaultTimeout(ctx, requestTimeout)
 	defer cancel()
 
 	// Generate a random cluster ID.
@@ -179,27 +188,43 @@ func uint64ToBytes(v uint64) []byte {
 type slowLogTxn struct {
 	clientv3.Txn
 	cancel context.CancelFunc
+	// slowLog is the record of the request which runs the transaction.
+	slowLog *RequestSlowLog
+	// keys are the keys of the operations, they are logged if the
+	// transaction is slow.
+	keys []string
 }
 
-func newSlowLogTxn(client *clientv3.Client) clientv3.Txn {
-	ctx, cancel := context.WithTimeout(client.Ctx(), requestTimeout)
+// newSlowLogTxn creates a transaction which is canceled when ctx is done or
+// the timeout is reached.
+func newSlowLogTxn(ctx context.Context, client *clientv3.Client, timeout time.Duration) clientv3.Txn {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	return &slowLogTxn{
-		Txn:    client.Txn(ctx),
-		cancel: cancel,
+		Txn:     client.Txn(ctx),
+		cancel:  cancel,
+		slowLog: requestSlowLogFromContext(ctx),
 	}
 }
 
 func (t *slowLogTxn) If(cs ...clientv3.Cmp) clientv3.Txn {
 	return &slowLogTxn{
-		Txn:    t.Txn.If(cs...),
-		cancel: t.cancel,
+		Txn:     t.Txn.If(cs...),
+		cancel:  t.cancel,
+		slowLog: t.slowLog,
+		keys:    t.keys,
 	}
 }
 
 func (t *slowLogTxn) Then(ops ...clientv3.Op) clientv3.Txn {
+	keys := t.keys
+	for _, op := range ops {
+		keys = append(keys, string(op.KeyBytes()))
+	}
 	return &slowLogTxn{
-		Txn:    t.Txn.Then(ops...),
-		cancel: t.cancel,
+		Txn:     t.Txn.Then(ops...),
+		cancel:  t.cancel,
+		slowLog: t.slowLog,
+		keys:    keys,
 	}
 }
 
@@ -208,10 +233,13 @@ func (t *slowLogTxn) Commit() (*clientv3.TxnResponse, error) {
 	start := time.Now()
 	resp, err := t.Txn.Commit()
 	t.cancel()
+	if t.slowLog != nil {
+		t.slowLog.recordTxn()
+	}
 
 	cost := time.Since(start)
 	if cost > slowRequestTime {
-		log.Warnf("txn runs too slow, resp: %v, err: %v, cost: %s", resp, err, cost)
+		log.Warnf("txn runs too slow, keys: %v, resp: %v, err: %v, cost: %s", t.keys, resp, err, cost)
 	}
 	label := "success"
 	if err != nil {
@@ -14,6 +14,7 @@
 package server
 
 import (
+	"context"
 	"math/rand"
 	"time"
 
@@ -48,6 +49,26 @@ func (s *testUtilSuite) TestSubTimeByWallClock(c *C) {
 	}
 }
 
+func (s *testUtilSuite) TestWithDefaultTimeout(c *C) {
+	ctx, cancel := withDefaultTimeout(context.Background(), time.Minute)
+	deadline, ok := ctx.Deadline()
+	c.Assert(ok, IsTrue)
+	c.Assert(time.Until(deadline), Greater, 59*time.Second)
+	cancel()
+
+	// The deadline of the caller is kept.
+	parent, cancelParent := context.WithTimeout(context.Background(), time.Second)
+	defer cancelParent()
+	ctx, cancel = withDefaultTimeout(parent, time.Minute)
+	defer cancel()
+	deadline, ok = ctx.Deadline()
+	c.Assert(ok, IsTrue)
+	c.Assert(time.Until(deadline) <= time.Second, IsTrue)
+
+	cancelParent()
+	<-ctx.Done()
+}
+
 func (s *testUtilSuite) TestVerifyLabels(c *C) {
 	tests := []struct {
 		label  string
@@ -14,6 +14,8 @@
 package server
 
 import (
+	"fmt"
+
 	"github.com/coreos/go-semver/semver"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -49,6 +51,54 @@ var featuresDict = map[Feature]string{
 	BatchSplit:  "2.1.0-rc.1",
 }
 
+var featureNames = map[Feature]string{
+	Base:        "base",
+	Version2_0:  "version2.0",
+	RegionMerge: "region-merge",
+	RaftLearner: "raft-learner",
+	BatchSplit:  "batch-split",
+}
+
+func (f Feature) String() string {
+	if name, ok := featureNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown-feature-%d", int(f))
+}
+
+// FeatureStatus shows whether a feature is enabled by the cluster version.
+type FeatureStatus struct {
+	Name       string `json:"name"`
+	MinVersion string `json:"min-version"`
+	Enabled    bool   `json:"enabled"`
+	Reason     string `json:"reason"`
+}
+
+// IsFeatureSupportedBy checks if the feature is supported by the cluster version.
+func IsFeatureSupportedBy(clusterVersion semver.Version, f Feature) bool {
+	return !clusterVersion.LessThan(MinSupportedVersion(f))
+}
+
+// GetFeatureStatuses returns the status of all features under the cluster version.
+func GetFeatureStatuses(clusterVersion semver.Version) []*FeatureStatus {
+	statuses := make([]*FeatureStatus, 0, len(featuresDict))
+	for f := Base; int(f) < len(featuresDict); f++ {
+		minVersion := MinSupportedVersion(f)
+		status := &FeatureStatus{
+			Name:       f.String(),
+			MinVersion: minVersion.String(),
+			Enabled:    IsFeatureSupportedBy(clusterVersion, f),
+		}
+		if status.Enabled {
+			status.Reason = fmt.Sprintf("cluster version %s >= %s", clusterVersion, minVersion)
+		} else {
+			status.Reason = fmt.Sprintf("cluster version %s < %s", clusterVersion, minVersion)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
 // MinSupportedVersion returns the minimum support version for the specified feature.
 func MinSupportedVersion(v Feature) semver.Version {
 	target, ok := featuresDict[v]
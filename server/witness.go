@@ -0,0 +1,55 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	"github.com/pingcap/pd/pkg/witnesspb"
+)
+
+// witnessService implements gRPC WitnessServer.
+type witnessService struct {
+	s *Server
+}
+
+// GetWitnessCommands implements gRPC WitnessServer.
+func (ws *witnessService) GetWitnessCommands(ctx context.Context, request *witnesspb.GetWitnessCommandsRequest) (*witnesspb.GetWitnessCommandsResponse, error) {
+	if err := ws.s.validateRequest(request.GetHeader()); err != nil {
+		return nil, err
+	}
+	cluster := ws.s.GetRaftCluster()
+	if cluster == nil {
+		return &witnesspb.GetWitnessCommandsResponse{Header: ws.s.notBootstrappedHeader()}, nil
+	}
+	return &witnesspb.GetWitnessCommandsResponse{
+		Header:   ws.s.header(),
+		Commands: cluster.coordinator.opController.GetWitnessCommands(request.GetStoreId()),
+	}, nil
+}
+
+// ReportWitness implements gRPC WitnessServer.
+func (ws *witnessService) ReportWitness(ctx context.Context, request *witnesspb.ReportWitnessRequest) (*witnesspb.ReportWitnessResponse, error) {
+	if err := ws.s.validateRequest(request.GetHeader()); err != nil {
+		return nil, err
+	}
+	cluster := ws.s.GetRaftCluster()
+	if cluster == nil {
+		return &witnesspb.ReportWitnessResponse{Header: ws.s.notBootstrappedHeader()}, nil
+	}
+	if err := cluster.cachedCluster.handleWitnessReport(request.GetRegionId(), request.GetPeerId(), request.GetWitness()); err != nil {
+		return nil, grpcError(err)
+	}
+	return &witnesspb.ReportWitnessResponse{Header: ws.s.header()}, nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/pkg/witnesspb"
+	"github.com/pingcap/pd/server/core"
+	"github.com/pingcap/pd/server/schedule"
+	"google.golang.org/grpc"
+)
+
+var _ = Suite(&testWitnessSuite{})
+
+type testWitnessSuite struct{}
+
+func (s *testWitnessSuite) TestSwitchToWitness(c *C) {
+	_, svr, cleanup, err := NewTestServer()
+	c.Assert(err, IsNil)
+	defer cleanup()
+	mustWaitLeader(c, []*Server{svr})
+	req := (&baseCluster{svr: svr}).newBootstrapRequest(c, svr.clusterID, "127.0.0.1:0")
+	_, err = svr.bootstrapCluster(req)
+	c.Assert(err, IsNil)
+
+	cluster := svr.GetRaftCluster()
+	ctx := context.Background()
+	meta := proto.Clone(req.GetRegion()).(*metapb.Region)
+	storeID := req.GetStore().GetId()
+	for i := uint64(1); i <= 2; i++ {
+		store := core.NewStoreInfo(&metapb.Store{Id: storeID + i, Address: fmt.Sprintf("127.0.0.1:%d", i)})
+		c.Assert(cluster.cachedCluster.putStore(store), IsNil)
+		meta.Peers = append(meta.Peers, &metapb.Peer{Id: meta.GetPeers()[0].GetId() + 100*i, StoreId: storeID + i})
+	}
+	meta.RegionEpoch.ConfVer += 2
+	c.Assert(cluster.HandleRegionHeartbeat(ctx, core.NewRegionInfo(proto.Clone(meta).(*metapb.Region), meta.GetPeers()[0])), IsNil)
+	witness := meta.GetPeers()[2]
+	step := schedule.SwitchToWitness{StoreID: witness.GetStoreId(), PeerID: witness.GetId()}
+	op := schedule.NewOperator("switchToWitness", meta.GetId(), meta.GetRegionEpoch(), schedule.OpReplica|schedule.OpRegion, step)
+	c.Assert(cluster.coordinator.opController.AddOperator(op), IsTrue)
+
+	conn, err := grpc.Dial(strings.TrimPrefix(svr.GetAddr(), "http://"), grpc.WithInsecure())
+	c.Assert(err, IsNil)
+	defer conn.Close()
+	client := witnesspb.NewWitnessClient(conn)
+	header := newRequestHeader(svr.clusterID)
+
+	// The switch is only sent to the store of the peer.
+	resp, err := client.GetWitnessCommands(ctx, &witnesspb.GetWitnessCommandsRequest{Header: header, StoreId: storeID})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetCommands(), HasLen, 0)
+	resp, err = client.GetWitnessCommands(ctx, &witnesspb.GetWitnessCommandsRequest{Header: header, StoreId: witness.GetStoreId()})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetCommands(), DeepEquals, []*witnesspb.WitnessCommand{{
+		RegionId:    meta.GetId(),
+		RegionEpoch: meta.GetRegionEpoch(),
+		PeerId:      witness.GetId(),
+		Witness:     true,
+	}})
+
+	// The store reports the witness after switching it.
+	_, err = client.ReportWitness(ctx, &witnesspb.ReportWitnessRequest{Header: header, RegionId: meta.GetId(), PeerId: witness.GetId() + 1, Witness: true})
+	c.Assert(err, NotNil)
+	_, err = client.ReportWitness(ctx, &witnesspb.ReportWitnessRequest{Header: header, RegionId: meta.GetId(), PeerId: witness.GetId(), Witness: true})
+	c.Assert(err, IsNil)
+	region := cluster.GetRegionInfoByID(meta.GetId())
+	c.Assert(region.GetWitnesses(), DeepEquals, []*metapb.Peer{witness})
+	c.Assert(step.IsFinish(region), IsTrue)
+	resp, err = client.GetWitnessCommands(ctx, &witnesspb.GetWitnessCommandsRequest{Header: header, StoreId: witness.GetStoreId()})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetCommands(), HasLen, 0)
+
+	// The witness is kept by the heartbeats, until it is removed.
+	c.Assert(cluster.HandleRegionHeartbeat(ctx, core.NewRegionInfo(proto.Clone(meta).(*metapb.Region), meta.GetPeers()[1])), IsNil)
+	c.Assert(cluster.GetRegionInfoByID(meta.GetId()).GetWitnesses(), DeepEquals, []*metapb.Peer{witness})
+	meta.Peers = meta.Peers[:2]
+	meta.RegionEpoch.ConfVer++
+	c.Assert(cluster.HandleRegionHeartbeat(ctx, core.NewRegionInfo(proto.Clone(meta).(*metapb.Region), meta.GetPeers()[1])), IsNil)
+	c.Assert(cluster.GetRegionInfoByID(meta.GetId()).GetWitnesses(), HasLen, 0)
+}
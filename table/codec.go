@@ -61,6 +61,103 @@ func (k Key) IsMeta() bool {
 	return bytes.HasPrefix(key, metaPrefix)
 }
 
+// KeyspaceID returns the keyspace ID of the key. A keyspace key starts with a
+// mode prefix, `x` for transactional keys or `r` for raw keys, followed by the
+// 3 bytes big-endian keyspace ID. ok is false if the key is not a keyspace key.
+func (k Key) KeyspaceID() (id uint32, ok bool) {
+	_, key, err := decodeBytes(k)
+	if err != nil {
+		return 0, false
+	}
+	if len(key) < 4 || (key[0] != 'x' && key[0] != 'r') {
+		return 0, false
+	}
+	return uint32(key[1])<<16 | uint32(key[2])<<8 | uint32(key[3]), true
+}
+
+var pads = make([]byte, encGroupSize)
+
+// EncodeBytes guarantees the encoded value is in ascending order for comparison.
+// The data is divided into 8 bytes groups padding with 0, and each group is
+// followed by a marker `0xFF - padding 0 count`. It is the same as the key
+// format of regions.
+func EncodeBytes(data []byte) Key {
+	// Allocate more space to avoid unnecessary slice growing.
+	// Assume that the byte slice size is about `(len(data) / encGroupSize + 1) * (encGroupSize + 1)` bytes,
+	// that is `(len(data) / 8 + 1) * 9` in our implement.
+	dLen := len(data)
+	result := make([]byte, 0, (dLen/encGroupSize+1)*(encGroupSize+1))
+	for idx := 0; idx <= dLen; idx += encGroupSize {
+		remain := dLen - idx
+		padCount := 0
+		if remain >= encGroupSize {
+			result = append(result, data[idx:idx+encGroupSize]...)
+		} else {
+			padCount = encGroupSize - remain
+			result = append(result, data[idx:]...)
+			result = append(result, pads[:padCount]...)
+		}
+
+		marker := encMarker - byte(padCount)
+		result = append(result, marker)
+	}
+	return result
+}
+
+// EncodeInt appends the encoded value to slice b and returns the appended slice.
+// EncodeInt guarantees that the encoded value is in ascending order for comparison.
+func EncodeInt(b []byte, v int64) []byte {
+	var data [8]byte
+	u := encodeIntToCmpUint(v)
+	binary.BigEndian.PutUint64(data[:], u)
+	return append(b, data[:]...)
+}
+
+func encodeIntToCmpUint(v int64) uint64 {
+	return uint64(v) ^ signMask
+}
+
+// GenerateSplitKeys returns the keys that split the rows under the prefix
+// into regionCount parts evenly. The row keys are assumed to be the prefix
+// followed by an encoded int64 handle in [0, rowCount). The returned keys are
+// encoded in the same format as the keys of regions.
+func GenerateSplitKeys(prefix []byte, rowCount int64, regionCount int) []Key {
+	if regionCount <= 1 || rowCount <= 1 {
+		return nil
+	}
+	if int64(regionCount) > rowCount {
+		regionCount = int(rowCount)
+	}
+	keys := make([]Key, 0, regionCount-1)
+	step := rowCount / int64(regionCount)
+	for i := 1; i < regionCount; i++ {
+		key := EncodeInt(append([]byte{}, prefix...), step*int64(i))
+		keys = append(keys, EncodeBytes(key))
+	}
+	return keys
+}
+
+// PrefixRange returns the key range [start, end) of all keys with the prefix.
+// The returned keys are encoded in the same format as the keys of regions.
+func PrefixRange(prefix []byte) (Key, Key) {
+	end := append([]byte{}, prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		end[i]++
+		if end[i] != 0 {
+			return EncodeBytes(prefix), EncodeBytes(end[:i+1])
+		}
+	}
+	// The prefix is all 0xFF, so no upper bound.
+	return EncodeBytes(prefix), nil
+}
+
+// TableRange returns the key range [start, end) of the table, including its
+// rows and indexes. The returned keys are encoded in the same format as the
+// keys of regions.
+func TableRange(tableID int64) (Key, Key) {
+	return PrefixRange(EncodeInt(append([]byte{}, tablePrefix...), tableID))
+}
+
 // DecodeInt decodes value encoded by EncodeInt before.
 // It returns the leftover un-decoded slice, decoded value if no error.
 func DecodeInt(b []byte) ([]byte, int64, error) {
@@ -14,6 +14,7 @@
 package table
 
 import (
+	"bytes"
 	"testing"
 
 	. "github.com/pingcap/check"
@@ -23,57 +24,89 @@ func TestTable(t *testing.T) {
 	TestingT(t)
 }
 
-var pads = make([]byte, encGroupSize)
-
 var _ = Suite(&testCodecSuite{})
 
 type testCodecSuite struct{}
 
-func encodeBytes(data []byte) Key {
-	// Allocate more space to avoid unnecessary slice growing.
-	// Assume that the byte slice size is about `(len(data) / encGroupSize + 1) * (encGroupSize + 1)` bytes,
-	// that is `(len(data) / 8 + 1) * 9` in our implement.
-	dLen := len(data)
-	result := make([]byte, 0, (dLen/encGroupSize+1)*(encGroupSize+1))
-	for idx := 0; idx <= dLen; idx += encGroupSize {
-		remain := dLen - idx
-		padCount := 0
-		if remain >= encGroupSize {
-			result = append(result, data[idx:idx+encGroupSize]...)
-		} else {
-			padCount = encGroupSize - remain
-			result = append(result, data[idx:]...)
-			result = append(result, pads[:padCount]...)
-		}
-
-		marker := encMarker - byte(padCount)
-		result = append(result, marker)
-	}
-	return result
-}
-
 func (s *testCodecSuite) TestDecodeBytes(c *C) {
 	key := "abcdefghijklmnopqrstuvwxyz"
 	for i := 0; i < len(key); i++ {
-		_, k, err := decodeBytes(encodeBytes([]byte(key[:i])))
+		_, k, err := decodeBytes(EncodeBytes([]byte(key[:i])))
 		c.Assert(err, IsNil)
 		c.Assert(string(k), Equals, key[:i])
 	}
 }
 
 func (s *testCodecSuite) TestTableID(c *C) {
-	key := encodeBytes([]byte("t\x80\x00\x00\x00\x00\x00\x00\xff"))
+	key := EncodeBytes([]byte("t\x80\x00\x00\x00\x00\x00\x00\xff"))
 	c.Assert(key.TableID(), Equals, int64(0xff))
 
-	key = encodeBytes([]byte("t\x80\x00\x00\x00\x00\x00\x00\xff_i\x01\x02"))
+	key = EncodeBytes([]byte("t\x80\x00\x00\x00\x00\x00\x00\xff_i\x01\x02"))
 	c.Assert(key.TableID(), Equals, int64(0xff))
 
 	key = []byte("t\x80\x00\x00\x00\x00\x00\x00\xff")
 	c.Assert(key.TableID(), Equals, int64(0))
 
-	key = encodeBytes([]byte("T\x00\x00\x00\x00\x00\x00\x00\xff"))
+	key = EncodeBytes([]byte("T\x00\x00\x00\x00\x00\x00\x00\xff"))
 	c.Assert(key.TableID(), Equals, int64(0))
 
-	key = encodeBytes([]byte("t\x80\x00\x00\x00\x00\x00\xff"))
+	key = EncodeBytes([]byte("t\x80\x00\x00\x00\x00\x00\xff"))
 	c.Assert(key.TableID(), Equals, int64(0))
 }
+
+func (s *testCodecSuite) TestKeyspaceID(c *C) {
+	id, ok := EncodeBytes([]byte("x\x00\x01\x02t\x80")).KeyspaceID()
+	c.Assert(ok, IsTrue)
+	c.Assert(id, Equals, uint32(0x102))
+
+	id, ok = EncodeBytes([]byte("r\x01\x00\x00")).KeyspaceID()
+	c.Assert(ok, IsTrue)
+	c.Assert(id, Equals, uint32(0x10000))
+
+	_, ok = EncodeBytes([]byte("x\x00\x01")).KeyspaceID()
+	c.Assert(ok, IsFalse)
+	_, ok = EncodeBytes([]byte("t\x80\x00\x00\x00\x00\x00\x00\xff")).KeyspaceID()
+	c.Assert(ok, IsFalse)
+	_, ok = Key("x\x00\x01\x02").KeyspaceID()
+	c.Assert(ok, IsFalse)
+}
+
+func (s *testCodecSuite) TestGenerateSplitKeys(c *C) {
+	prefix := []byte("t\x80\x00\x00\x00\x00\x00\x00\xff_r")
+	keys := GenerateSplitKeys(prefix, 100, 4)
+	c.Assert(keys, HasLen, 3)
+	start, end := PrefixRange(prefix)
+	for i, key := range keys {
+		c.Assert(key.TableID(), Equals, int64(0xff))
+		c.Assert(bytes.Compare(key, start), Greater, 0)
+		c.Assert(bytes.Compare(key, end), Less, 0)
+		if i > 0 {
+			c.Assert(bytes.Compare(key, keys[i-1]), Greater, 0)
+		}
+		_, k, err := decodeBytes(key)
+		c.Assert(err, IsNil)
+		_, handle, err := DecodeInt(k[len(prefix):])
+		c.Assert(err, IsNil)
+		c.Assert(handle, Equals, int64(25*(i+1)))
+	}
+
+	c.Assert(GenerateSplitKeys(prefix, 100, 1), HasLen, 0)
+	c.Assert(GenerateSplitKeys(prefix, 3, 10), HasLen, 2)
+
+	_, end = PrefixRange([]byte("t\xff"))
+	_, k, err := decodeBytes(end)
+	c.Assert(err, IsNil)
+	c.Assert(string(k), Equals, "u")
+	_, end = PrefixRange([]byte("\xff\xff"))
+	c.Assert(end, IsNil)
+}
+
+func (s *testCodecSuite) TestTableRange(c *C) {
+	start, end := TableRange(0xff)
+	c.Assert(start.TableID(), Equals, int64(0xff))
+	key := EncodeBytes([]byte("t\x80\x00\x00\x00\x00\x00\x00\xff_i\x01\x02"))
+	c.Assert(bytes.Compare(key, start), Greater, 0)
+	c.Assert(bytes.Compare(key, end), Less, 0)
+	key = EncodeBytes([]byte("t\x80\x00\x00\x00\x00\x00\x01\x00"))
+	c.Assert(bytes.Compare(key, end), GreaterEqual, 0)
+}
@@ -20,6 +20,7 @@ import (
 	"net/http"
 	"path"
 	"regexp"
+	"sort"
 	"sync"
 	"time"
 
@@ -33,16 +34,18 @@ func init() {
 	namespace.RegisterClassifier("table", NewTableNamespaceClassifier)
 }
 
-// Namespace defines two things:
+// Namespace defines three things:
 // 1. relation between a Name and several tables
-// 2. relation between a Name and several stores
-// It is used to bind tables with stores
+// 2. relation between a Name and several keyspaces
+// 3. relation between a Name and several stores
+// It is used to bind tables and keyspaces with stores
 type Namespace struct {
-	ID       uint64          `json:"ID"`
-	Name     string          `json:"Name"`
-	TableIDs map[int64]bool  `json:"table_ids,omitempty"`
-	StoreIDs map[uint64]bool `json:"store_ids,omitempty"`
-	Meta     bool            `json:"meta,omitempty"`
+	ID          uint64          `json:"ID"`
+	Name        string          `json:"Name"`
+	TableIDs    map[int64]bool  `json:"table_ids,omitempty"`
+	KeyspaceIDs map[uint32]bool `json:"keyspace_ids,omitempty"`
+	StoreIDs    map[uint64]bool `json:"store_ids,omitempty"`
+	Meta        bool            `json:"meta,omitempty"`
 }
 
 // NewNamespace creates a new namespace
@@ -79,6 +82,14 @@ func (ns *Namespace) AddTableID(tableID int64) {
 	ns.TableIDs[tableID] = true
 }
 
+// AddKeyspaceID adds a keyspaceID to this namespace
+func (ns *Namespace) AddKeyspaceID(keyspaceID uint32) {
+	if ns.KeyspaceIDs == nil {
+		ns.KeyspaceIDs = make(map[uint32]bool)
+	}
+	ns.KeyspaceIDs[keyspaceID] = true
+}
+
 // AddStoreID adds a storeID to this namespace
 func (ns *Namespace) AddStoreID(storeID uint64) {
 	if ns.StoreIDs == nil {
@@ -143,6 +154,15 @@ func (c *tableNamespaceClassifier) GetRegionNamespace(regionInfo *core.RegionInf
 	c.RLock()
 	defer c.RUnlock()
 
+	if keyspaceID, ok := Key(regionInfo.GetStartKey()).KeyspaceID(); ok {
+		for name, ns := range c.nsInfo.namespaces {
+			if ns.KeyspaceIDs[keyspaceID] {
+				return name
+			}
+		}
+		return namespace.DefaultNamespace
+	}
+
 	isMeta := Key(regionInfo.GetStartKey()).IsMeta()
 	tableID := Key(regionInfo.GetStartKey()).TableID()
 	if tableID == 0 && !isMeta {
@@ -159,6 +179,11 @@ func (c *tableNamespaceClassifier) GetRegionNamespace(regionInfo *core.RegionInf
 }
 
 func (c *tableNamespaceClassifier) AllowMerge(one *core.RegionInfo, other *core.RegionInfo) bool {
+	oneKeyspace, oneOK := Key(one.GetStartKey()).KeyspaceID()
+	otherKeyspace, otherOK := Key(other.GetStartKey()).KeyspaceID()
+	if oneOK || otherOK {
+		return oneOK && otherOK && oneKeyspace == otherKeyspace
+	}
 	return Key(one.GetStartKey()).TableID() == Key(other.GetStartKey()).TableID()
 }
 
@@ -241,6 +266,42 @@ func (c *tableNamespaceClassifier) RemoveNamespaceTableID(name string, tableID i
 	return c.putNamespaceLocked(n)
 }
 
+// AddNamespaceKeyspaceID adds keyspace ID to namespace.
+func (c *tableNamespaceClassifier) AddNamespaceKeyspaceID(name string, keyspaceID uint32) error {
+	c.Lock()
+	defer c.Unlock()
+
+	n := c.nsInfo.getNamespaceByName(name)
+	if n == nil {
+		return errors.Errorf("invalid namespace Name %s, not found", name)
+	}
+
+	if c.nsInfo.IsKeyspaceIDExist(keyspaceID) {
+		return errors.New("Keyspace ID already exists in this cluster")
+	}
+
+	n.AddKeyspaceID(keyspaceID)
+	return c.putNamespaceLocked(n)
+}
+
+// RemoveNamespaceKeyspaceID removes keyspace ID from namespace.
+func (c *tableNamespaceClassifier) RemoveNamespaceKeyspaceID(name string, keyspaceID uint32) error {
+	c.Lock()
+	defer c.Unlock()
+
+	n := c.nsInfo.getNamespaceByName(name)
+	if n == nil {
+		return errors.Errorf("invalid namespace Name %s, not found", name)
+	}
+
+	if _, ok := n.KeyspaceIDs[keyspaceID]; !ok {
+		return errors.Errorf("Keyspace ID %d is not belong to %s", keyspaceID, name)
+	}
+
+	delete(n.KeyspaceIDs, keyspaceID)
+	return c.putNamespaceLocked(n)
+}
+
 // AddMetaToNamespace adds meta to a namespace.
 func (c *tableNamespaceClassifier) AddMetaToNamespace(name string) error {
 	c.Lock()
@@ -292,6 +353,22 @@ func (c *tableNamespaceClassifier) AddNamespaceStoreID(name string, storeID uint
 	return c.putNamespaceLocked(n)
 }
 
+// GetNamespaceStoreIDs returns the IDs of the stores bound to each namespace.
+func (c *tableNamespaceClassifier) GetNamespaceStoreIDs() map[string][]uint64 {
+	c.RLock()
+	defer c.RUnlock()
+
+	res := make(map[string][]uint64)
+	for _, ns := range c.nsInfo.namespaces {
+		for storeID := range ns.StoreIDs {
+			res[ns.Name] = append(res[ns.Name], storeID)
+		}
+		storeIDs := res[ns.Name]
+		sort.Slice(storeIDs, func(i, j int) bool { return storeIDs[i] < storeIDs[j] })
+	}
+	return res
+}
+
 // RemoveNamespaceStoreID removes store ID from namespace.
 func (c *tableNamespaceClassifier) RemoveNamespaceStoreID(name string, storeID uint64) error {
 	c.Lock()
@@ -379,6 +456,17 @@ func (namespaceInfo *namespacesInfo) IsTableIDExist(tableID int64) bool {
 	return false
 }
 
+// IsKeyspaceIDExist returns true if keyspace ID exists in namespacesInfo
+func (namespaceInfo *namespacesInfo) IsKeyspaceIDExist(keyspaceID uint32) bool {
+	for _, ns := range namespaceInfo.namespaces {
+		_, ok := ns.KeyspaceIDs[keyspaceID]
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
 // IsStoreIDExist returns true if store ID exists in namespacesInfo
 func (namespaceInfo *namespacesInfo) IsStoreIDExist(storeID uint64) bool {
 	for _, ns := range namespaceInfo.namespaces {
@@ -409,7 +497,7 @@ func (namespaceInfo *namespacesInfo) saveNamespace(kv *core.KV, ns *Namespace) e
 	if err != nil {
 		return errors.WithStack(err)
 	}
-	err = kv.Save(namespaceInfo.namespacePath(ns.GetID()), string(value))
+	err = kv.Save(kv.Context(), namespaceInfo.namespacePath(ns.GetID()), string(value))
 	return err
 }
 
@@ -421,7 +509,7 @@ func (namespaceInfo *namespacesInfo) loadNamespaces(kv *core.KV, rangeLimit int)
 
 	for {
 		key := namespaceInfo.namespacePath(nextID)
-		res, err := kv.LoadRange(key, endKey, rangeLimit)
+		res, err := kv.LoadRange(kv.Context(), key, endKey, rangeLimit)
 		if err != nil {
 			return err
 		}
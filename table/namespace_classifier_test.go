@@ -112,14 +112,14 @@ func (s *testTableNamespaceSuite) TestTableNameSpaceGetRegionNamespace(c *C) {
 		{false, "t\x80\x00\x00\x00\x00\x00\x00\x03", "t\x80\x00\x00\x00\x00\x00\x00\x04", 3, false, "global"},
 		{false, "m\x80\x00\x00\x00\x00\x00\x00\x01", "", 0, true, "ns2"},
 		{false, "", "m\x80\x00\x00\x00\x00\x00\x00\x01", 0, false, "global"},
-		{true, string(encodeBytes([]byte("t\x80\x00\x00\x00\x00\x00\x00\x01"))), "", testTable1, false, "ns1"},
+		{true, string(EncodeBytes([]byte("t\x80\x00\x00\x00\x00\x00\x00\x01"))), "", testTable1, false, "ns1"},
 		{true, "t\x80\x00\x00\x00\x00\x00\x00\x01", "", 0, false, "global"}, // decode error
 	}
 	classifier := s.newClassifier(c)
 	for _, t := range testCases {
 		startKey, endKey := Key(t.startKey), Key(t.endKey)
 		if !t.endcoded {
-			startKey, endKey = encodeBytes(startKey), encodeBytes(endKey)
+			startKey, endKey = EncodeBytes(startKey), EncodeBytes(endKey)
 		}
 		c.Assert(startKey.TableID(), Equals, t.tableID)
 		c.Assert(startKey.IsMeta(), Equals, t.isMeta)
@@ -132,6 +132,33 @@ func (s *testTableNamespaceSuite) TestTableNameSpaceGetRegionNamespace(c *C) {
 	}
 }
 
+func (s *testTableNamespaceSuite) TestKeyspaceNamespace(c *C) {
+	classifier := s.newClassifier(c)
+	newRegion := func(startKey string) *core.RegionInfo {
+		return core.NewRegionInfo(&metapb.Region{StartKey: EncodeBytes([]byte(startKey))}, &metapb.Peer{})
+	}
+	region1 := newRegion("x\x00\x00\x01")
+	region2 := newRegion("x\x00\x00\x01t\x80\x00\x00\x00\x00\x00\x00\x02")
+	region3 := newRegion("x\x00\x00\x02")
+
+	c.Assert(classifier.GetRegionNamespace(region1), Equals, "global")
+	c.Assert(classifier.AddNamespaceKeyspaceID("ns1", 1), IsNil)
+	c.Assert(classifier.AddNamespaceKeyspaceID("ns2", 1), NotNil)
+	c.Assert(classifier.AddNamespaceKeyspaceID("ns3", 2), NotNil)
+	// The table ID in a keyspace is not used to classify regions.
+	c.Assert(classifier.GetRegionNamespace(region1), Equals, "ns1")
+	c.Assert(classifier.GetRegionNamespace(region2), Equals, "ns1")
+	c.Assert(classifier.GetRegionNamespace(region3), Equals, "global")
+
+	c.Assert(classifier.AllowMerge(region1, region2), IsTrue)
+	c.Assert(classifier.AllowMerge(region2, region3), IsFalse)
+	c.Assert(classifier.AllowMerge(region3, newRegion("y")), IsFalse)
+
+	c.Assert(classifier.RemoveNamespaceKeyspaceID("ns2", 1), NotNil)
+	c.Assert(classifier.RemoveNamespaceKeyspaceID("ns1", 1), IsNil)
+	c.Assert(classifier.GetRegionNamespace(region1), Equals, "global")
+}
+
 func (s *testTableNamespaceSuite) TestNamespaceOperation(c *C) {
 	kv := core.NewKV(core.NewMemoryKV())
 	classifier, err := NewTableNamespaceClassifier(kv, core.NewMockIDAllocator())
@@ -172,6 +199,7 @@ func (s *testTableNamespaceSuite) TestNamespaceOperation(c *C) {
 	namespaces = tableClassifier.GetNamespaces()
 	c.Assert(namespaces, HasLen, 2)
 	c.Assert(nsInfo.IsStoreIDExist(456), IsTrue)
+	c.Assert(tableClassifier.GetNamespaceStoreIDs(), DeepEquals, map[string][]uint64{"test1": {456}})
 
 	// Ensure that duplicate tableID cannot exist in one namespace
 	err = tableClassifier.AddNamespaceTableID("test1", 1)
@@ -33,6 +33,7 @@ func newTableClassifierHandler(classifier *tableNamespaceClassifier) http.Handle
 	router.HandleFunc("/table/namespaces", h.Get).Methods("GET")
 	router.HandleFunc("/table/namespaces", h.Post).Methods("POST")
 	router.HandleFunc("/table/namespaces/table", h.Update).Methods("POST")
+	router.HandleFunc("/table/namespaces/keyspace", h.UpdateKeyspace).Methods("POST")
 	router.HandleFunc("/table/namespaces/meta", h.SetMetaNamespace).Methods("POST")
 	router.HandleFunc("/table/store_ns/{id}", h.SetNamespace).Methods("POST")
 	return router
@@ -126,6 +127,36 @@ func (h *tableNamespaceHandler) Update(w http.ResponseWriter, r *http.Request) {
 	h.rd.JSON(w, http.StatusOK, nil)
 }
 
+func (h *tableNamespaceHandler) UpdateKeyspace(w http.ResponseWriter, r *http.Request) {
+	var input map[string]string
+	if err := readJSONRespondError(h.rd, w, r.Body, &input); err != nil {
+		return
+	}
+	keyspaceID, err := strconv.ParseUint(input["keyspace_id"], 10, 32)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	ns := input["namespace"]
+	switch input["action"] {
+	case "add":
+		if err := h.classifier.AddNamespaceKeyspaceID(ns, uint32(keyspaceID)); err != nil {
+			h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	case "remove":
+		if err := h.classifier.RemoveNamespaceKeyspaceID(ns, uint32(keyspaceID)); err != nil {
+			h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	default:
+		h.rd.JSON(w, http.StatusBadRequest, errors.New("unknown action"))
+		return
+	}
+
+	h.rd.JSON(w, http.StatusOK, nil)
+}
+
 func (h *tableNamespaceHandler) SetMetaNamespace(w http.ResponseWriter, r *http.Request) {
 	var input map[string]string
 	if err := readJSONRespondError(h.rd, w, r.Body, &input); err != nil {
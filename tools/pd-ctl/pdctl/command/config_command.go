@@ -24,12 +24,14 @@ import (
 )
 
 var (
-	configPrefix         = "pd/api/v1/config"
-	schedulePrefix       = "pd/api/v1/config/schedule"
-	replicationPrefix    = "pd/api/v1/config/replicate"
-	namespacePrefix      = "pd/api/v1/config/namespace"
-	labelPropertyPrefix  = "pd/api/v1/config/label-property"
-	clusterVersionPrefix = "pd/api/v1/config/cluster-version"
+	configPrefix          = "pd/api/v1/config"
+	schedulePrefix        = "pd/api/v1/config/schedule"
+	replicationPrefix     = "pd/api/v1/config/replicate"
+	namespacePrefix       = "pd/api/v1/config/namespace"
+	labelPropertyPrefix   = "pd/api/v1/config/label-property"
+	mergeProtectionPrefix = "pd/api/v1/config/merge-protection"
+	clusterVersionPrefix  = "pd/api/v1/config/cluster-version"
+	featuresPrefix        = "pd/api/v1/config/cluster-version/features"
 )
 
 // NewConfigCommand return a config subcommand of rootCmd
@@ -47,7 +49,7 @@ func NewConfigCommand() *cobra.Command {
 // NewShowConfigCommand return a show subcommand of configCmd
 func NewShowConfigCommand() *cobra.Command {
 	sc := &cobra.Command{
-		Use:   "show [namespace|replication|label-property|all]",
+		Use:   "show [namespace|replication|label-property|merge-protection|cluster-version|features|all]",
 		Short: "show schedule config of PD",
 		Run:   showConfigCommandFunc,
 	}
@@ -55,7 +57,9 @@ func NewShowConfigCommand() *cobra.Command {
 	sc.AddCommand(NewShowNamespaceConfigCommand())
 	sc.AddCommand(NewShowReplicationConfigCommand())
 	sc.AddCommand(NewShowLabelPropertyCommand())
+	sc.AddCommand(NewShowMergeProtectionCommand())
 	sc.AddCommand(NewShowClusterVersionCommand())
+	sc.AddCommand(NewShowFeaturesCommand())
 	return sc
 }
 
@@ -99,6 +103,16 @@ func NewShowLabelPropertyCommand() *cobra.Command {
 	return sc
 }
 
+// NewShowMergeProtectionCommand returns a show merge protection subcommand of show subcommand.
+func NewShowMergeProtectionCommand() *cobra.Command {
+	sc := &cobra.Command{
+		Use:   "merge-protection",
+		Short: "show merge protection rules",
+		Run:   showMergeProtectionConfigCommandFunc,
+	}
+	return sc
+}
+
 // NewShowClusterVersionCommand returns a cluster version subcommand of show subcommand.
 func NewShowClusterVersionCommand() *cobra.Command {
 	sc := &cobra.Command{
@@ -109,15 +123,26 @@ func NewShowClusterVersionCommand() *cobra.Command {
 	return sc
 }
 
+// NewShowFeaturesCommand returns a features subcommand of show subcommand.
+func NewShowFeaturesCommand() *cobra.Command {
+	sc := &cobra.Command{
+		Use:   "features",
+		Short: "show the features enabled by the cluster version",
+		Run:   showFeaturesCommandFunc,
+	}
+	return sc
+}
+
 // NewSetConfigCommand return a set subcommand of configCmd
 func NewSetConfigCommand() *cobra.Command {
 	sc := &cobra.Command{
-		Use:   "set <option> <value>, set namespace <name> <option> <value>, set label-property <type> <key> <value>, set cluster-version <version>",
+		Use:   "set <option> <value>, set namespace <name> <option> <value>, set label-property <type> <key> <value>, set merge-protection <name> <interval> table <table-id>|range <start-key> <end-key>, set cluster-version <version>",
 		Short: "set the option with value",
 		Run:   setConfigCommandFunc,
 	}
 	sc.AddCommand(NewSetNamespaceConfigCommand())
 	sc.AddCommand(NewSetLabelPropertyCommand())
+	sc.AddCommand(NewSetMergeProtectionCommand())
 	sc.AddCommand(NewSetClusterVersionCommand())
 	return sc
 }
@@ -142,6 +167,16 @@ func NewSetLabelPropertyCommand() *cobra.Command {
 	return sc
 }
 
+// NewSetMergeProtectionCommand creates a set subcommand of set subcommand
+func NewSetMergeProtectionCommand() *cobra.Command {
+	sc := &cobra.Command{
+		Use:   "merge-protection <name> <interval> table <table-id>|range <start-key> <end-key>",
+		Short: "protect the regions split in the table or the hex encoded key range from merging for the interval",
+		Run:   setMergeProtectionConfigCommandFunc,
+	}
+	return sc
+}
+
 // NewSetClusterVersionCommand creates a set subcommand of set subcommand
 func NewSetClusterVersionCommand() *cobra.Command {
 	sc := &cobra.Command{
@@ -155,11 +190,12 @@ func NewSetClusterVersionCommand() *cobra.Command {
 // NewDeleteConfigCommand a set subcommand of cfgCmd
 func NewDeleteConfigCommand() *cobra.Command {
 	sc := &cobra.Command{
-		Use:   "delete namespace|label-property",
+		Use:   "delete namespace|label-property|merge-protection",
 		Short: "delete the config option",
 	}
 	sc.AddCommand(NewDeleteNamespaceConfigCommand())
 	sc.AddCommand(NewDeleteLabelPropertyConfigCommand())
+	sc.AddCommand(NewDeleteMergeProtectionConfigCommand())
 	return sc
 }
 
@@ -183,6 +219,16 @@ func NewDeleteLabelPropertyConfigCommand() *cobra.Command {
 	return sc
 }
 
+// NewDeleteMergeProtectionConfigCommand a set subcommand of delete subcommand.
+func NewDeleteMergeProtectionConfigCommand() *cobra.Command {
+	sc := &cobra.Command{
+		Use:   "merge-protection <name>",
+		Short: "delete a merge protection rule",
+		Run:   deleteMergeProtectionConfigCommandFunc,
+	}
+	return sc
+}
+
 func showConfigCommandFunc(cmd *cobra.Command, args []string) {
 	r, err := doRequest(cmd, schedulePrefix, http.MethodGet)
 	if err != nil {
@@ -210,6 +256,15 @@ func showLabelPropertyConfigCommandFunc(cmd *cobra.Command, args []string) {
 	cmd.Println(r)
 }
 
+func showMergeProtectionConfigCommandFunc(cmd *cobra.Command, args []string) {
+	r, err := doRequest(cmd, mergeProtectionPrefix, http.MethodGet)
+	if err != nil {
+		cmd.Printf("Failed to get config: %s\n", err)
+		return
+	}
+	cmd.Println(r)
+}
+
 func showAllConfigCommandFunc(cmd *cobra.Command, args []string) {
 	r, err := doRequest(cmd, configPrefix, http.MethodGet)
 	if err != nil {
@@ -242,6 +297,15 @@ func showClusterVersionCommandFunc(cmd *cobra.Command, args []string) {
 	cmd.Println(r)
 }
 
+func showFeaturesCommandFunc(cmd *cobra.Command, args []string) {
+	r, err := doRequest(cmd, featuresPrefix, http.MethodGet)
+	if err != nil {
+		cmd.Printf("Failed to get features: %s\n", err)
+		return
+	}
+	cmd.Println(r)
+}
+
 func postConfigDataWithPath(cmd *cobra.Command, key, value, path string) error {
 	var val interface{}
 	data := make(map[string]interface{})
@@ -340,6 +404,45 @@ func postLabelProperty(cmd *cobra.Command, action string, args []string) {
 	postJSON(cmd, prefix, input)
 }
 
+func setMergeProtectionConfigCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) < 4 {
+		cmd.Println(cmd.UsageString())
+		return
+	}
+	input := map[string]interface{}{
+		"interval": args[1],
+	}
+	switch {
+	case args[2] == "table" && len(args) == 4:
+		tableID, err := strconv.ParseInt(args[3], 10, 64)
+		if err != nil {
+			cmd.Println("table-id should be a number")
+			return
+		}
+		input["table-id"] = tableID
+	case args[2] == "range" && len(args) == 5:
+		input["start-key"] = args[3]
+		input["end-key"] = args[4]
+	default:
+		cmd.Println(cmd.UsageString())
+		return
+	}
+	postJSON(cmd, path.Join(mergeProtectionPrefix, args[0]), input)
+}
+
+func deleteMergeProtectionConfigCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		cmd.Println(cmd.UsageString())
+		return
+	}
+	_, err := doRequest(cmd, path.Join(mergeProtectionPrefix, args[0]), http.MethodDelete)
+	if err != nil {
+		cmd.Printf("Failed to delete merge protection rule %s: %s\n", args[0], err)
+		return
+	}
+	cmd.Println("Success!")
+}
+
 func setClusterVersionCommandFunc(cmd *cobra.Command, args []string) {
 	if len(args) != 1 {
 		cmd.Println(cmd.UsageString())
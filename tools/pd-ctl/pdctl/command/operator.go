@@ -87,8 +87,8 @@ func NewAddOperatorCommand() *cobra.Command {
 // NewTransferLeaderCommand returns a command to transfer leader.
 func NewTransferLeaderCommand() *cobra.Command {
 	c := &cobra.Command{
-		Use:   "transfer-leader <region_id> <to_store_id>",
-		Short: "transfer a region's leader to the specified store",
+		Use:   "transfer-leader <region_id> <to_store_id|auto>",
+		Short: "transfer a region's leader to the specified store, or to the store picked by PD with auto",
 		Run:   transferLeaderCommandFunc,
 	}
 	return c
@@ -100,14 +100,25 @@ func transferLeaderCommandFunc(cmd *cobra.Command, args []string) {
 		return
 	}
 
+	input := make(map[string]interface{})
+	input["name"] = cmd.Name()
+	if args[1] == "auto" {
+		ids, err := parseUint64s(args[:1])
+		if err != nil {
+			cmd.Println(err)
+			return
+		}
+		input["region_id"] = ids[0]
+		input["auto"] = true
+		postJSON(cmd, operatorsPrefix, input)
+		return
+	}
+
 	ids, err := parseUint64s(args)
 	if err != nil {
 		cmd.Println(err)
 		return
 	}
-
-	input := make(map[string]interface{})
-	input["name"] = cmd.Name()
 	input["region_id"] = ids[0]
 	input["to_store_id"] = ids[1]
 	postJSON(cmd, operatorsPrefix, input)
@@ -122,9 +122,9 @@ func addSchedulerForStoreCommandFunc(cmd *cobra.Command, args []string) {
 // NewShuffleLeaderSchedulerCommand returns a command to add a shuffle-leader-scheduler.
 func NewShuffleLeaderSchedulerCommand() *cobra.Command {
 	c := &cobra.Command{
-		Use:   "shuffle-leader-scheduler",
+		Use:   "shuffle-leader-scheduler [<interval>]",
 		Short: "add a scheduler to shuffle leaders between stores",
-		Run:   addSchedulerCommandFunc,
+		Run:   addDebugSchedulerCommandFunc,
 	}
 	return c
 }
@@ -132,9 +132,9 @@ func NewShuffleLeaderSchedulerCommand() *cobra.Command {
 // NewShuffleRegionSchedulerCommand returns a command to add a shuffle-region-scheduler.
 func NewShuffleRegionSchedulerCommand() *cobra.Command {
 	c := &cobra.Command{
-		Use:   "shuffle-region-scheduler",
+		Use:   "shuffle-region-scheduler [<interval>]",
 		Short: "add a scheduler to shuffle regions between stores",
-		Run:   addSchedulerCommandFunc,
+		Run:   addDebugSchedulerCommandFunc,
 	}
 	return c
 }
@@ -172,9 +172,9 @@ func NewBalanceHotRegionSchedulerCommand() *cobra.Command {
 // NewRandomMergeSchedulerCommand returns a command to add a random-merge-scheduler.
 func NewRandomMergeSchedulerCommand() *cobra.Command {
 	c := &cobra.Command{
-		Use:   "random-merge-scheduler",
+		Use:   "random-merge-scheduler [<interval>]",
 		Short: "add a scheduler to merge regions randomly",
-		Run:   addSchedulerCommandFunc,
+		Run:   addDebugSchedulerCommandFunc,
 	}
 	return c
 }
@@ -200,6 +200,22 @@ func addSchedulerCommandFunc(cmd *cobra.Command, args []string) {
 	postJSON(cmd, schedulersPrefix, input)
 }
 
+// addDebugSchedulerCommandFunc adds a debug scheduler with an optional
+// schedule interval, such as 10s.
+func addDebugSchedulerCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) > 1 {
+		cmd.Println(cmd.UsageString())
+		return
+	}
+
+	input := make(map[string]interface{})
+	input["name"] = cmd.Name()
+	if len(args) == 1 {
+		input["interval"] = args[0]
+	}
+	postJSON(cmd, schedulersPrefix, input)
+}
+
 // NewScatterRangeSchedulerCommand returns a command to add a scatter-range-scheduler.
 func NewScatterRangeSchedulerCommand() *cobra.Command {
 	c := &cobra.Command{
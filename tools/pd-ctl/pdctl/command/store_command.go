@@ -18,6 +18,7 @@ import (
 	"net/http"
 	"path"
 	"strconv"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -30,13 +31,16 @@ var (
 // NewStoreCommand return a store subcommand of rootCmd
 func NewStoreCommand() *cobra.Command {
 	s := &cobra.Command{
-		Use:   `store [delete|label|weight] <store_id> [--jq="<query string>"]`,
+		Use:   `store [delete|label|weight|cordon|uncordon|replace] <store_id> [--jq="<query string>"]`,
 		Short: "show the store status",
 		Run:   showStoreCommandFunc,
 	}
 	s.AddCommand(NewDeleteStoreCommand())
 	s.AddCommand(NewLabelStoreCommand())
 	s.AddCommand(NewSetStoreWeightCommand())
+	s.AddCommand(NewCordonStoreCommand())
+	s.AddCommand(NewUncordonStoreCommand())
+	s.AddCommand(NewReplaceStoreCommand())
 	s.Flags().String("jq", "", "jq query")
 	return s
 }
@@ -70,6 +74,39 @@ func NewSetStoreWeightCommand() *cobra.Command {
 	}
 }
 
+// NewCordonStoreCommand returns a cordon subcommand of storeCmd.
+func NewCordonStoreCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "cordon <store_id> [<duration>]",
+		Short: "stop scheduling new peers and leaders to the store, optionally for a duration such as 30m",
+		Run:   cordonStoreCommandFunc,
+	}
+}
+
+// NewUncordonStoreCommand returns an uncordon subcommand of storeCmd.
+func NewUncordonStoreCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "uncordon <store_id>",
+		Short: "allow scheduling new peers and leaders to the store",
+		Run:   uncordonStoreCommandFunc,
+	}
+}
+
+// NewReplaceStoreCommand returns a replace subcommand of storeCmd.
+func NewReplaceStoreCommand() *cobra.Command {
+	r := &cobra.Command{
+		Use:   "replace <store_id> [<replacement_store_id>]",
+		Short: "show the progress of replacing the offline store, or pair it with a replacement store which its peers are moved to first",
+		Run:   replaceStoreCommandFunc,
+	}
+	r.AddCommand(&cobra.Command{
+		Use:   "delete <store_id>",
+		Short: "unpair the store with its replacement store",
+		Run:   deleteReplaceStoreCommandFunc,
+	})
+	return r
+}
+
 func showStoreCommandFunc(cmd *cobra.Command, args []string) {
 	prefix := storesPrefix
 	if len(args) == 1 {
@@ -143,3 +180,87 @@ func setStoreWeightCommandFunc(cmd *cobra.Command, args []string) {
 		"region": region,
 	})
 }
+
+func cordonStoreCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 1 && len(args) != 2 {
+		cmd.Println("Usage: store cordon <store_id> [<duration>]")
+		return
+	}
+	if _, err := strconv.Atoi(args[0]); err != nil {
+		cmd.Println("store_id should be a number")
+		return
+	}
+	prefix := fmt.Sprintf(path.Join(storePrefix, "cordon"), args[0])
+	if len(args) == 2 {
+		if _, err := time.ParseDuration(args[1]); err != nil {
+			cmd.Println("duration should be a duration such as 30m")
+			return
+		}
+		prefix += "?duration=" + args[1]
+	}
+	_, err := doRequest(cmd, prefix, http.MethodPost)
+	if err != nil {
+		cmd.Printf("Failed to cordon store %s: %s\n", args[0], err)
+		return
+	}
+	cmd.Println("Success!")
+}
+
+func uncordonStoreCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		cmd.Println("Usage: store uncordon <store_id>")
+		return
+	}
+	if _, err := strconv.Atoi(args[0]); err != nil {
+		cmd.Println("store_id should be a number")
+		return
+	}
+	prefix := fmt.Sprintf(path.Join(storePrefix, "cordon"), args[0])
+	_, err := doRequest(cmd, prefix, http.MethodDelete)
+	if err != nil {
+		cmd.Printf("Failed to uncordon store %s: %s\n", args[0], err)
+		return
+	}
+	cmd.Println("Success!")
+}
+
+func replaceStoreCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 1 && len(args) != 2 {
+		cmd.Println("Usage: store replace <store_id> [<replacement_store_id>]")
+		return
+	}
+	ids, err := parseUint64s(args)
+	if err != nil {
+		cmd.Println("store_id should be a number")
+		return
+	}
+	prefix := fmt.Sprintf(path.Join(storePrefix, "replacement"), args[0])
+	if len(args) == 2 {
+		postJSON(cmd, prefix, map[string]interface{}{"replacement_store_id": ids[1]})
+		return
+	}
+	r, err := doRequest(cmd, prefix, http.MethodGet)
+	if err != nil {
+		cmd.Printf("Failed to get the replacement of store %s: %s\n", args[0], err)
+		return
+	}
+	cmd.Println(r)
+}
+
+func deleteReplaceStoreCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		cmd.Println("Usage: store replace delete <store_id>")
+		return
+	}
+	if _, err := strconv.Atoi(args[0]); err != nil {
+		cmd.Println("store_id should be a number")
+		return
+	}
+	prefix := fmt.Sprintf(path.Join(storePrefix, "replacement"), args[0])
+	_, err := doRequest(cmd, prefix, http.MethodDelete)
+	if err != nil {
+		cmd.Printf("Failed to unpair store %s: %s\n", args[0], err)
+		return
+	}
+	cmd.Println("Success!")
+}
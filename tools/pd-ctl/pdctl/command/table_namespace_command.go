@@ -25,13 +25,14 @@ const (
 	namespacesPrefix     = "pd/api/v1/classifier/table/namespaces"
 	namespaceTablePrefix = "pd/api/v1/classifier/table/namespaces/table"
 	namespaceMetaPrefix  = "pd/api/v1/classifier/table/namespaces/meta"
+	namespaceKsPrefix    = "pd/api/v1/classifier/table/namespaces/keyspace"
 	storeNsPrefix        = "pd/api/v1/classifier/table/store_ns/%s"
 )
 
 // NewTableNamespaceCommand return a table namespace sub-command of rootCmd
 func NewTableNamespaceCommand() *cobra.Command {
 	s := &cobra.Command{
-		Use:   "table_ns [create|add|remove|set_store|rm_store|set_meta|rm_meta]",
+		Use:   "table_ns [create|add|remove|set_store|rm_store|set_meta|rm_meta|set_keyspace|rm_keyspace]",
 		Short: "show the table namespace information",
 		Run:   showNamespaceCommandFunc,
 	}
@@ -42,6 +43,8 @@ func NewTableNamespaceCommand() *cobra.Command {
 	s.AddCommand(NewRemoveNamespaceStoreCommand())
 	s.AddCommand(newSetMetaNamespaceCommand())
 	s.AddCommand(newRemoveMetaNamespaceCommand())
+	s.AddCommand(newSetKeyspaceNamespaceCommand())
+	s.AddCommand(newRemoveKeyspaceNamespaceCommand())
 	return s
 }
 
@@ -230,3 +233,53 @@ func removeMetaNamespaceCommandFunc(cmd *cobra.Command, args []string) {
 	}
 	postJSON(cmd, namespaceMetaPrefix, input)
 }
+
+func newSetKeyspaceNamespaceCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set_keyspace <namespace> <keyspace_id>",
+		Short: "set keyspace to namespace",
+		Run:   setKeyspaceNamespaceCommandFunc,
+	}
+}
+
+func newRemoveKeyspaceNamespaceCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rm_keyspace <namespace> <keyspace_id>",
+		Short: "remove keyspace from namespace",
+		Run:   removeKeyspaceNamespaceCommandFunc,
+	}
+}
+
+func setKeyspaceNamespaceCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 2 {
+		cmd.Println("Usage: set_keyspace <namespace> <keyspace_id>")
+		return
+	}
+	if _, err := strconv.ParseUint(args[1], 10, 32); err != nil {
+		cmd.Println("keyspace_id should be a number")
+		return
+	}
+	input := map[string]interface{}{
+		"namespace":   args[0],
+		"keyspace_id": args[1],
+		"action":      "add",
+	}
+	postJSON(cmd, namespaceKsPrefix, input)
+}
+
+func removeKeyspaceNamespaceCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 2 {
+		cmd.Println("Usage: rm_keyspace <namespace> <keyspace_id>")
+		return
+	}
+	if _, err := strconv.ParseUint(args[1], 10, 32); err != nil {
+		cmd.Println("keyspace_id should be a number")
+		return
+	}
+	input := map[string]interface{}{
+		"namespace":   args[0],
+		"keyspace_id": args[1],
+		"action":      "remove",
+	}
+	postJSON(cmd, namespaceKsPrefix, input)
+}